@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
 	"time"
@@ -8,8 +9,6 @@ import (
 	// "github.com/markbates/goth"
 	"github.com/quangdangfit/gocommon/logger"
 	"github.com/quangdangfit/gocommon/validation"
-	"golang.org/x/oauth2"
-	"golang.org/x/oauth2/google"
 
 	// "golang.org/x/oauth2/google"
 	// _ "github.com/GoAdminGroup/go-admin/adapter/gin" // Import the adapter, it must be imported. If it is not imported, you need to define it yourself.
@@ -21,13 +20,55 @@ import (
 
 	// orderModel "main/internal/order/model"
 	addressModel "main/internal/address/model"
+	addressSubscriber "main/internal/address/port/subscriber"
+	addressRepository "main/internal/address/repository"
+	auditModel "main/internal/audit/model"
+	auditRepository "main/internal/audit/repository"
+	auditService "main/internal/audit/service"
+	clinicModel "main/internal/clinic/model"
+	consultationModel "main/internal/consultation/model"
 	doctorModel "main/internal/doctor/model"
+	doctorSubscriber "main/internal/doctor/port/subscriber"
+	doctorRepository "main/internal/doctor/repository"
+	doctorService "main/internal/doctor/service"
+	fileModel "main/internal/file/model"
+	fileRepository "main/internal/file/repository"
+	fileService "main/internal/file/service"
+	healthModel "main/internal/health/model"
+	notificationModel "main/internal/notification/model"
+	notificationSubscriber "main/internal/notification/port/subscriber"
+	notificationRepository "main/internal/notification/repository"
+	notificationService "main/internal/notification/service"
+	realtimeSubscriber "main/internal/realtime/port/subscriber"
+	realtimeService "main/internal/realtime/service"
+	reviewModel "main/internal/review/model"
 	grpcServer "main/internal/server/grpc"
 	httpServer "main/internal/server/http"
+	specialtyModel "main/internal/specialty/model"
+	specialtySubscriber "main/internal/specialty/port/subscriber"
+	specialtyRepository "main/internal/specialty/repository"
+	specialtyService "main/internal/specialty/service"
 	userModel "main/internal/user/model"
+	userSubscriber "main/internal/user/port/subscriber"
+	userRepository "main/internal/user/repository"
+	userService "main/internal/user/service"
+	webhookModel "main/internal/webhook/model"
+	webhookSubscriber "main/internal/webhook/port/subscriber"
+	webhookRepository "main/internal/webhook/repository"
+	webhookService "main/internal/webhook/service"
 	conf "main/pkg/config"
 	"main/pkg/dbs"
+	"main/pkg/encryption"
+	"main/pkg/events"
+	"main/pkg/idempotency"
+	"main/pkg/imaging"
+	"main/pkg/notify"
+	"main/pkg/oauth"
+	"main/pkg/realtime"
 	"main/pkg/redis"
+	"main/pkg/session"
+	"main/pkg/storage"
+	"main/pkg/webhook"
 )
 
 //	@title			main Swagger API
@@ -75,54 +116,158 @@ func main() {
 		logger.Fatal("Cannot connect to database", err)
 		os.Exit(1)
 	}
-	//*********************************************
-	oauthConfig := &oauth2.Config{
-		ClientID:     cfg.GOOGLE_CLIENT_ID,
-		ClientSecret: cfg.GOOGLE_CLIENT_SECRET,
-		RedirectURL:  cfg.GOOGLE_REDIRECT_URL,
-		Scopes:       []string{"https://www.googleapis.com/auth/userinfo.email", "https://www.googleapis.com/auth/userinfo.profile"},
-		Endpoint:     google.Endpoint,
-	}
-
-	fbOauthConfig := &oauth2.Config{
-		ClientID:     "APP_ID",
-		ClientSecret: "APP_SECRET",
-		RedirectURL:  "http://localhost:8080/callback",
-		Scopes:       []string{"email"},
-		Endpoint: oauth2.Endpoint{
-			AuthURL:  "https://www.facebook.com/v3.2/dialog/oauth",
-			TokenURL: "https://graph.facebook.com/v3.2/oauth/access_token",
-		},
+	// sensitive columns are encrypted, run cmd/reencrypt after adding or
+	// rotating a key
+	keyring, err := encryption.FromConfig(cfg)
+	if err != nil {
+		logger.Fatal("Cannot load encryption keys", err)
 	}
-	//*********************************************
-	// // Initialize Facebook provider
-	// fbProvider := facebook.New(cfg.FACEBOOK_CLIENT_ID, cfg.FACEBOOK_CLIENT_SECRET, cfg.FACEBOOK_REDIRECT_URL, "email")
-	// provider := &goth.Provider{
-	// 	Facebook: fbProvider,
-	// 	Google:   oauthConfig,
-	// }
+	if err := encryption.Register(db.GetDB(), keyring); err != nil {
+		logger.Fatal("Cannot register encryption", err)
+	}
+	// Google, Facebook and a generic OpenID Connect provider, each enabled
+	// by its client id
+	oauthProviders := oauth.ProvidersFromConfig(cfg)
 
-	err = db.AutoMigrate(&userModel.User{}, &addressModel.Address{}, &doctorModel.Doctor{})
+	err = db.AutoMigrate(&userModel.User{}, &userModel.RecoveryCode{}, &userModel.UserIdentity{}, &userModel.Session{}, &userModel.APIKey{}, &addressModel.Address{}, &doctorModel.Doctor{}, &doctorModel.Verification{}, &fileModel.File{}, &reviewModel.Review{}, &specialtyModel.Specialty{}, &specialtyModel.DoctorSpecialty{}, &clinicModel.Clinic{}, &healthModel.Profile{}, &healthModel.Allergy{}, &healthModel.Condition{}, &healthModel.Medication{}, &healthModel.Vital{}, &healthModel.Grant{}, &healthModel.Change{}, &consultationModel.Consultation{}, &consultationModel.Medication{}, &consultationModel.Amendment{}, &consultationModel.Prescription{}, &auditModel.Event{}, &userModel.DataRequest{}, &idempotency.Record{}, &webhookModel.Subscription{}, &webhookModel.Delivery{}, &notificationModel.Notification{}, &notificationModel.Preference{})
 	if err != nil {
 		logger.Fatal("Database migration fail", err)
 	}
+	// users are found by the blind indexes of their email and phone number,
+	// the rows written before the indexes get theirs before serving
+	if filled, err := encryption.FillIndexes(context.Background(), db.GetDB(), &userModel.User{}, 500); err != nil {
+		logger.Error("Blind index backfill fail, run cmd/reencrypt", err)
+	} else if filled > 0 {
+		logger.Infof("Blind indexes filled for %d users", filled)
+	}
+
+	// the audit log can only be appended to
+	auditRepo := auditRepository.NewAuditRepository(db)
+	if err := auditRepo.Protect(context.Background()); err != nil {
+		logger.Fatal("Audit log protection fail", err)
+	}
+
+	// uploaded files, on disk or in an S3 compatible server
+	store, err := storage.FromConfig(cfg)
+	if err != nil {
+		logger.Fatal("Cannot open file storage", err)
+	}
+
+	// profile pictures are resized in the background
+	images := imaging.NewPipeline(cfg.ImageWorkers, cfg.ImageQueueSize)
+	go images.Run(context.Background())
 
 	validator := validation.New()
+	audits := auditService.NewAuditService(validator, auditRepo)
 
 	cache := redis.New(redis.Config{
-		Address:  cfg.RedisURI,
-		Password: cfg.RedisPassword,
-		Database: cfg.RedisDB,
+		Mode:              cfg.RedisMode,
+		Address:           cfg.RedisURI,
+		Username:          cfg.RedisUsername,
+		Password:          cfg.RedisPassword,
+		Database:          cfg.RedisDB,
+		MasterName:        cfg.RedisMasterName,
+		SentinelAddresses: cfg.RedisSentinelAddrs,
+		SentinelPassword:  cfg.RedisSentinelPassword,
+		ClusterAddresses:  cfg.RedisClusterAddrs,
+		TLS: redis.TLSConfig{
+			Enabled:            cfg.RedisTLS,
+			CAFile:             cfg.RedisTLSCAFile,
+			CertFile:           cfg.RedisTLSCertFile,
+			KeyFile:            cfg.RedisTLSKeyFile,
+			ServerName:         cfg.RedisTLSServerName,
+			InsecureSkipVerify: cfg.RedisTLSSkipVerify,
+		},
+		PoolSize:      cfg.RedisPoolSize,
+		Timeout:       cfg.RedisTimeout,
+		RetryInterval: cfg.RedisRetryInterval,
 	})
+	defer cache.Close()
+
+	// domain events of the services, the caches are invalidated by their
+	// subscribers
+	bus, err := events.FromConfig(cfg, cache)
+	if err != nil {
+		logger.Fatal("Cannot open event bus", err)
+	}
+	defer bus.Close()
+	for _, subscribe := range []func(context.Context, *events.Bus, redis.IRedis) error{
+		userSubscriber.Subscribe,
+		doctorSubscriber.Subscribe,
+		addressSubscriber.Subscribe,
+		specialtySubscriber.Subscribe,
+	} {
+		if err := subscribe(context.Background(), bus, cache); err != nil {
+			logger.Fatal("Event subscription fail", err)
+		}
+	}
+
+	// free-text specialities of doctors are mapped to the catalogue, the
+	// unmatched ones stay until admins complete it and migrate again
+	specialtySvc := specialtyService.NewSpecialtyService(validator, specialtyRepository.NewSpecialtyRepository(db), doctorRepository.NewDoctorRepository(db), bus)
+	if _, err := specialtySvc.MigrateSpecialists(context.Background()); err != nil {
+		logger.Error("Specialists migration fail", err)
+	}
+
+	// messages pushed to the connected users, fanned out to the instances
+	hub := realtime.NewHub(cache.Client(), cfg.RealtimeBuffer)
+	go hub.Run(context.Background())
+	userRepo := userRepository.NewUserRepository(db)
+	sessions := session.NewStore(cache, userService.NewSessionRevocations(userRepo))
+	realtimeSvc := realtimeService.NewRealtimeService(validator, hub, sessions, audits, cfg.RealtimeHeartbeat)
+	if err := realtimeSubscriber.Subscribe(context.Background(), bus, realtimeSvc); err != nil {
+		logger.Fatal("Event subscription fail", err)
+	}
+
+	// in-app inbox, the email and SMS go through it to honour the
+	// preferences of the users
+	notificationRepo := notificationRepository.NewNotificationRepository(db)
+	notificationSvc := notificationService.NewNotificationService(validator, notificationRepo, hub, notify.DefaultSenders(), cfg.NotificationTTL)
+	if err := notificationSubscriber.Subscribe(context.Background(), bus, notificationSvc); err != nil {
+		logger.Fatal("Event subscription fail", err)
+	}
+
+	// callbacks of the webhook subscriptions, retried with backoff
+	webhookRepo := webhookRepository.NewWebhookRepository(db)
+	webhookSvc := webhookService.NewWebhookService(validator, webhookRepo, webhook.NewSender(cfg.WebhookTimeout), audits, webhookService.OptionsFromConfig(cfg))
+	if err := webhookSubscriber.Subscribe(context.Background(), bus, webhookSvc); err != nil {
+		logger.Fatal("Event subscription fail", err)
+	}
+	go webhookSvc.RunDeliveries(context.Background(), cfg.WebhookInterval)
+
+	// doctors whose license expired are suspended until a new one is approved
+	doctorSvc := doctorService.NewDoctorService(validator, doctorRepository.NewDoctorRepository(db), nil, audits, bus)
+	go doctorSvc.RunLicenseExpiry(context.Background(), cfg.LicenseExpiryInterval)
+
+	// exports of personal data and erasures of accounts whose grace period
+	// is over
+	_, imageSvc := fileService.NewServices(fileRepository.NewFileRepository(db), store, images)
+	privacySvc := userService.NewPrivacyService(validator, userRepo, store, imageSvc, sessions, audits, bus, cfg.DataExportTTL, cfg.ErasureGracePeriod)
+	go privacySvc.RunDataRequests(context.Background(), cfg.DataRequestInterval)
+
+	// deleted rows can be restored until they are purged
+	go dbs.RunPurge(context.Background(), cfg.PurgeInterval, cfg.DeletedRetention,
+		addressRepository.NewAddressRepository(db),
+		doctorRepository.NewDoctorRepository(db),
+		privacySvc,
+	)
+	// expired idempotency keys, kept in Postgres when configured
+	if cfg.IdempotencyStore == "postgres" {
+		go dbs.RunPurge(context.Background(), cfg.PurgeInterval, 0, idempotency.NewPostgresStore(db.GetDB()))
+	}
+	// delivery logs of the webhooks
+	go dbs.RunPurge(context.Background(), cfg.PurgeInterval, cfg.WebhookLogRetention, webhookRepo)
+	// expired in-app notifications
+	go dbs.RunPurge(context.Background(), cfg.PurgeInterval, 0, notificationRepo)
 
 	go func() {
-		httpSvr := httpServer.NewServer(validator, db, cache, fbOauthConfig, oauthConfig)
+		httpSvr := httpServer.NewServer(validator, db, cache, oauthProviders, store, images, bus, hub)
 		if err = httpSvr.Run(); err != nil {
 			logger.Fatal(err)
 		}
 	}()
 
-	grpcSvr := grpcServer.NewServer(validator, db, cache, oauthConfig, fbOauthConfig)
+	grpcSvr := grpcServer.NewServer(validator, db, cache, oauthProviders, store, images, bus, hub)
 	if err = grpcSvr.Run(); err != nil {
 		logger.Fatal(err)
 	}
@@ -0,0 +1,70 @@
+// Command reencrypt encrypts with the active key the encrypted columns still
+// in plaintext or encrypted with an older key, and fills the blind indexes.
+// Run it after enabling the encryption and after every key rotation, the
+// older keys can be removed from the keyring once it completed.
+package main
+
+import (
+	"context"
+	"flag"
+
+	"github.com/quangdangfit/gocommon/logger"
+
+	consultationModel "main/internal/consultation/model"
+	healthModel "main/internal/health/model"
+	userModel "main/internal/user/model"
+	webhookModel "main/internal/webhook/model"
+	conf "main/pkg/config"
+	"main/pkg/dbs"
+	"main/pkg/encryption"
+)
+
+// models with encrypted columns
+var models = []interface{}{
+	&userModel.User{},
+	&healthModel.Profile{},
+	&healthModel.Allergy{},
+	&healthModel.Condition{},
+	&healthModel.Medication{},
+	&healthModel.Vital{},
+	&healthModel.Change{},
+	&consultationModel.Consultation{},
+	&consultationModel.Medication{},
+	&consultationModel.Amendment{},
+	&consultationModel.Prescription{},
+	&webhookModel.Subscription{},
+}
+
+func main() {
+	batchSize := flag.Int("batch", 500, "rows read at once")
+	flag.Parse()
+
+	cfg := conf.LoadConfig()
+	logger.Initialize(cfg.Environment)
+
+	db, err := dbs.NewDatabase(cfg.DatabaseURI)
+	if err != nil {
+		logger.Fatal("Cannot connect to database", err)
+	}
+	keyring, err := encryption.FromConfig(cfg)
+	if err != nil {
+		logger.Fatal("Cannot load encryption keys", err)
+	}
+	if err := encryption.Register(db.GetDB(), keyring); err != nil {
+		logger.Fatal("Cannot register encryption", err)
+	}
+
+	// the columns become text before their values are rewritten
+	if err := db.AutoMigrate(models...); err != nil {
+		logger.Fatal("Database migration fail", err)
+	}
+
+	ctx := context.Background()
+	for _, model := range models {
+		updated, err := encryption.Rotate(ctx, db.GetDB(), model, *batchSize)
+		if err != nil {
+			logger.Fatalf("Rotate %T fail after %d rows, error: %s", model, updated, err)
+		}
+		logger.Infof("Rotated %T, %d rows updated with key %s", model, updated, keyring.Active())
+	}
+}
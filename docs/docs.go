@@ -60,6 +60,12 @@ const docTemplate = `{
                         "name": "limit",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Also list the deleted addresses, for admins",
+                        "name": "include_deleted",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -85,6 +91,12 @@ const docTemplate = `{
                 ],
                 "summary": "create Address",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Key of the request and its retries, which get the first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
                     {
                         "description": "Body",
                         "name": "_",
@@ -95,6 +107,51 @@ const docTemplate = `{
                         }
                     }
                 ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Address"
+                        }
+                    },
+                    "409": {
+                        "description": "The first request with the key is running",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "The key was used with another body",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/address-admin/addresses/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Address"
+                ],
+                "summary": "Restore a deleted address",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Address ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -121,6 +178,12 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the address already read",
+                        "name": "If-None-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -129,6 +192,9 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/dto.Address"
                         }
+                    },
+                    "304": {
+                        "description": "Not changed since If-None-Match"
                     }
                 }
             },
@@ -146,6 +212,13 @@ const docTemplate = `{
                 ],
                 "summary": "Update Address",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ETag of the address, its version",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
                     {
                         "description": "Body",
                         "name": "_",
@@ -162,6 +235,12 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/dto.Address"
                         }
+                    },
+                    "412": {
+                        "description": "Changed since it was read",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
                     }
                 }
             },
@@ -179,6 +258,13 @@ const docTemplate = `{
                 ],
                 "summary": "Delete Address",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ETag of the address, its version",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
                     {
                         "description": "Body",
                         "name": "_",
@@ -195,32 +281,54 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/dto.Address"
                         }
+                    },
+                    "412": {
+                        "description": "Changed since it was read",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
                     }
                 }
-            }
-        },
-        "/auth-admin/create": {
-            "post": {
+            },
+            "patch": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
+                "description": "JSON Merge Patch (application/merge-patch+json, also for application/json) or JSON Patch (application/json-patch+json) of the fields of UpdateAddressReq, the other fields are kept",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users-admin"
+                    "Address"
                 ],
-                "summary": "Create new user",
+                "summary": "Change some fields of an Address",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Address ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the address, its version",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
                     {
                         "description": "Body",
                         "name": "_",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.RegisterReq"
+                            "$ref": "#/definitions/dto.PatchAddressReq"
                         }
                     }
                 ],
@@ -228,44 +336,38 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.RegisterRes"
+                            "$ref": "#/definitions/dto.Address"
                         }
-                    }
-                }
-            }
-        },
-        "/auth-admin/login": {
-            "post": {
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "users-admin"
-                ],
-                "summary": "LoginAdmin",
-                "parameters": [
-                    {
-                        "description": "Body",
-                        "name": "_",
-                        "in": "body",
-                        "required": true,
+                    },
+                    "409": {
+                        "description": "A test operation failed",
                         "schema": {
-                            "$ref": "#/definitions/dto.LoginReq"
+                            "$ref": "#/definitions/response.Response"
                         }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    },
+                    "412": {
+                        "description": "Changed since it was read",
                         "schema": {
-                            "$ref": "#/definitions/dto.LoginRes"
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "415": {
+                        "description": "Not a patch media type",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid or read-only fields",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
                         }
                     }
                 }
             }
         },
-        "/auth-admin/update": {
-            "put": {
+        "/audit-admin/events": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
@@ -275,31 +377,88 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users-admin"
+                    "Audit-admin"
                 ],
-                "summary": "Update user",
+                "summary": "List the audit log, the latest events first",
                 "parameters": [
                     {
-                        "description": "Body",
-                        "name": "_",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.UpdateUserReq"
-                        }
+                        "type": "string",
+                        "description": "Action, such as auth.login",
+                        "name": "action",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "success or failure",
+                        "name": "outcome",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Actor user ID",
+                        "name": "actor_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Target type, such as doctor",
+                        "name": "target_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Target ID",
+                        "name": "target_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Request ID",
+                        "name": "request_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Clinic ID",
+                        "name": "tenant_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "From, RFC 3339",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "To, RFC 3339, excluded",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.UpdateUserRes"
+                            "$ref": "#/definitions/dto.ListAuditEventsRes"
                         }
                     }
                 }
             }
         },
-        "/auth-admin/users": {
+        "/audit-admin/events/export": {
             "get": {
                 "security": [
                     {
@@ -307,54 +466,80 @@ const docTemplate = `{
                     }
                 ],
                 "produces": [
-                    "application/json"
+                    "text/csv"
                 ],
                 "tags": [
-                    "users-admin"
+                    "Audit-admin"
                 ],
-                "summary": "Get list Users",
+                "summary": "Export the audit log as csv, in the order of the chain",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "id_user",
-                        "name": "id_user",
-                        "in": "header",
-                        "required": true
+                        "description": "Action, such as auth.login",
+                        "name": "action",
+                        "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "name",
-                        "name": "name",
-                        "in": "path",
-                        "required": true
+                        "description": "success or failure",
+                        "name": "outcome",
+                        "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "page",
-                        "name": "page",
-                        "in": "path",
-                        "required": true
+                        "description": "Actor user ID",
+                        "name": "actor_id",
+                        "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "limit",
-                        "name": "limit",
-                        "in": "path",
-                        "required": true
+                        "description": "Target type, such as doctor",
+                        "name": "target_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Target ID",
+                        "name": "target_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Request ID",
+                        "name": "request_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Clinic ID",
+                        "name": "tenant_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "From, RFC 3339",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "To, RFC 3339, excluded",
+                        "name": "to",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.ListUsersRes"
+                            "type": "file"
                         }
                     }
                 }
             }
         },
-        "/auth-admin/{id}": {
-            "delete": {
+        "/audit-admin/verify": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
@@ -364,69 +549,55 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users-admin"
-                ],
-                "summary": "Delete User",
-                "parameters": [
-                    {
-                        "description": "Body",
-                        "name": "_",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.DeleteUserReq"
-                        }
-                    }
+                    "Audit-admin"
                 ],
+                "summary": "Check the hash chain of the audit log",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.User"
+                            "$ref": "#/definitions/dto.AuditChainRes"
                         }
                     }
                 }
             }
         },
-        "/auth-doctor/login": {
-            "post": {
+        "/auth-admin/api-keys": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users-doctor"
-                ],
-                "summary": "Login",
-                "parameters": [
-                    {
-                        "description": "Body",
-                        "name": "_",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.KLoginReq"
-                        }
-                    }
+                    "users-admin"
                 ],
+                "summary": "List the API keys",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.LoginRes"
+                            "$ref": "#/definitions/dto.ListAPIKeysRes"
                         }
                     }
                 }
-            }
-        },
-        "/auth-doctor/register": {
+            },
             "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users-doctor"
+                    "users-admin"
                 ],
-                "summary": "Register new user",
+                "summary": "Create an API key for a machine client",
                 "parameters": [
                     {
                         "description": "Body",
@@ -434,7 +605,7 @@ const docTemplate = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.KRegisterReq"
+                            "$ref": "#/definitions/dto.CreateAPIKeyReq"
                         }
                     }
                 ],
@@ -442,14 +613,14 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.RegisterRes"
+                            "$ref": "#/definitions/dto.CreateAPIKeyRes"
                         }
                     }
                 }
             }
         },
-        "/auth-doctor/update-user": {
-            "put": {
+        "/auth-admin/api-keys/{id}": {
+            "delete": {
                 "security": [
                     {
                         "ApiKeyAuth": []
@@ -459,47 +630,53 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users-doctor"
+                    "users-admin"
                 ],
-                "summary": "changes the password",
+                "summary": "Revoke an API key and the tokens obtained with it",
                 "parameters": [
                     {
-                        "description": "Body",
-                        "name": "_",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.KUpdateUserReq"
-                        }
+                        "type": "string",
+                        "description": "API key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/dto.UpdateUserRes"
-                        }
+                        "description": "OK"
                     }
                 }
             }
         },
-        "/auth-patient/login": {
+        "/auth-admin/create": {
             "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users-patient"
+                    "users-admin"
                 ],
-                "summary": "Login",
+                "summary": "Create new user",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Key of the request and its retries, which get the first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
                     {
                         "description": "Body",
                         "name": "_",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.KLoginReq"
+                            "$ref": "#/definitions/dto.RegisterReq"
                         }
                     }
                 ],
@@ -507,21 +684,33 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.LoginRes"
+                            "$ref": "#/definitions/dto.RegisterRes"
+                        }
+                    },
+                    "409": {
+                        "description": "The first request with the key is running",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "The key was used with another body",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
                         }
                     }
                 }
             }
         },
-        "/auth-patient/register": {
+        "/auth-admin/login": {
             "post": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users-patient"
+                    "users-admin"
                 ],
-                "summary": "Register new user",
+                "summary": "LoginAdmin",
                 "parameters": [
                     {
                         "description": "Body",
@@ -529,7 +718,7 @@ const docTemplate = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.KRegisterReq"
+                            "$ref": "#/definitions/dto.LoginReq"
                         }
                     }
                 ],
@@ -537,13 +726,13 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.RegisterRes"
+                            "$ref": "#/definitions/dto.LoginRes"
                         }
                     }
                 }
             }
         },
-        "/auth-patient/update-user": {
+        "/auth-admin/update": {
             "put": {
                 "security": [
                     {
@@ -554,17 +743,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users-patient"
+                    "users-admin"
                 ],
-                "summary": "changes the password",
+                "summary": "Update user",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ETag of my profile, its version",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
                     {
                         "description": "Body",
                         "name": "_",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.KUpdateUserReq"
+                            "$ref": "#/definitions/dto.UpdateUserReq"
                         }
                     }
                 ],
@@ -574,72 +770,108 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/dto.UpdateUserRes"
                         }
+                    },
+                    "412": {
+                        "description": "Changed since it was read",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
                     }
                 }
             }
         },
-        "/auth/facebook/callback": {
+        "/auth-admin/users": {
             "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "users-admin"
                 ],
-                "summary": "Handles Facebook OAuth2 callback",
+                "summary": "Get list Users",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Authorization Code from Facebook",
-                        "name": "code",
-                        "in": "query",
+                        "description": "id_user",
+                        "name": "id_user",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "page",
+                        "name": "page",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Also list the deleted users, for admins",
+                        "name": "include_deleted",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.LoginRes"
+                            "$ref": "#/definitions/dto.ListUsersRes"
                         }
                     }
                 }
             }
         },
-        "/auth/google/callback": {
-            "get": {
-                "description": "Logs user in with Google OAuth2",
-                "consumes": [
-                    "application/json"
+        "/auth-admin/users/{id}/mfa/reset": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "users-admin"
                 ],
-                "summary": "Login with Google",
+                "summary": "Reset the MFA of a user",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Authorization code received from Google",
-                        "name": "code",
-                        "in": "query",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/dto.LoginRes"
-                        }
+                        "description": "OK"
                     }
                 }
             }
         },
-        "/auth/me": {
-            "get": {
+        "/auth-admin/users/{id}/restore": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
@@ -649,9 +881,18 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "users-admin"
+                ],
+                "summary": "Restore a deleted user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "get my profile",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -662,7 +903,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/refresh-token": {
+        "/auth-admin/users/{id}/sessions": {
             "get": {
                 "security": [
                     {
@@ -673,32 +914,28 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "users-admin"
                 ],
-                "summary": "get my profile",
+                "summary": "List the sessions of a user",
                 "parameters": [
                     {
-                        "description": "Body",
-                        "name": "_",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.RefreshTokenReq"
-                        }
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.RefreshTokenRes"
+                            "$ref": "#/definitions/dto.ListSessionsRes"
                         }
                     }
                 }
-            }
-        },
-        "/auth/resend-verfiy-code-email": {
-            "put": {
+            },
+            "delete": {
                 "security": [
                     {
                         "ApiKeyAuth": []
@@ -708,32 +945,27 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "users-admin"
                 ],
-                "summary": "Verfiy Code for Email",
+                "summary": "Log out every session of a user",
                 "parameters": [
                     {
-                        "description": "Body",
-                        "name": "_",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.ResendVerifyEmailRequest"
-                        }
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/dto.VerifyResponse"
-                        }
+                        "description": "OK"
                     }
                 }
             }
         },
-        "/auth/resend-verfiy-code-phone-number": {
-            "put": {
+        "/auth-admin/users/{id}/sessions/{sessionId}": {
+            "delete": {
                 "security": [
                     {
                         "ApiKeyAuth": []
@@ -743,32 +975,34 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "users-admin"
                 ],
-                "summary": "Verfiy Code for PhoneNumber",
+                "summary": "Log out a session of a user",
                 "parameters": [
                     {
-                        "description": "Body",
-                        "name": "_",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.ResendVerifyPhoneNumberRequest"
-                        }
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/dto.VerifyResponse"
-                        }
+                        "description": "OK"
                     }
                 }
             }
         },
-        "/auth/verfiy-code-email": {
-            "put": {
+        "/auth-admin/{id}": {
+            "delete": {
                 "security": [
                     {
                         "ApiKeyAuth": []
@@ -778,17 +1012,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "users-admin"
                 ],
-                "summary": "Verfiy Code for Email",
+                "summary": "Delete User",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ETag of the user, its version",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
                     {
                         "description": "Body",
                         "name": "_",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.VerifyEmailRequest"
+                            "$ref": "#/definitions/dto.DeleteUserReq"
                         }
                     }
                 ],
@@ -796,26 +1037,27 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.VerifyResponse"
+                            "$ref": "#/definitions/dto.User"
+                        }
+                    },
+                    "412": {
+                        "description": "Changed since it was read",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
                         }
                     }
                 }
             }
         },
-        "/auth/verfiy-code-phone-number": {
-            "put": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
+        "/auth-doctor/login": {
+            "post": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "users-doctor"
                 ],
-                "summary": "Verfiy Code for PhoneNumber",
+                "summary": "Login",
                 "parameters": [
                     {
                         "description": "Body",
@@ -823,7 +1065,7 @@ const docTemplate = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.VerifyPhoneNumberRequest"
+                            "$ref": "#/definitions/dto.KLoginReq"
                         }
                     }
                 ],
@@ -831,34 +1073,35 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.VerifyResponse"
+                            "$ref": "#/definitions/dto.LoginRes"
                         }
                     }
                 }
             }
         },
-        "/doctor": {
+        "/auth-doctor/register": {
             "post": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Doctor"
+                    "users-doctor"
                 ],
-                "summary": "create Doctor",
+                "summary": "Register new user",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Key of the request and its retries, which get the first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
                     {
                         "description": "Body",
                         "name": "_",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.CreateDoctorReq"
+                            "$ref": "#/definitions/dto.KRegisterReq"
                         }
                     }
                 ],
@@ -866,14 +1109,26 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.Doctor"
+                            "$ref": "#/definitions/dto.RegisterRes"
+                        }
+                    },
+                    "409": {
+                        "description": "The first request with the key is running",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "The key was used with another body",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
                         }
                     }
                 }
             }
         },
-        "/doctor/list_doctors": {
-            "get": {
+        "/auth-doctor/update-user": {
+            "put": {
                 "security": [
                     {
                         "ApiKeyAuth": []
@@ -883,84 +1138,122 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Doctor"
+                    "users-doctor"
                 ],
-                "summary": "ListDoctors",
+                "summary": "changes the password",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Search",
-                        "name": "search",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "ID User",
-                        "name": "id_user",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Page number",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Limit per page",
-                        "name": "limit",
-                        "in": "query"
+                        "description": "ETag of my profile, its version",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Order by field",
-                        "name": "order_by",
-                        "in": "query"
+                        "description": "Body",
+                        "name": "_",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.KUpdateUserReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateUserRes"
+                        }
                     },
+                    "412": {
+                        "description": "Changed since it was read",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth-patient/login": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users-patient"
+                ],
+                "summary": "Login",
+                "parameters": [
                     {
-                        "type": "boolean",
-                        "description": "Order descending",
-                        "name": "order_desc",
-                        "in": "query"
+                        "description": "Body",
+                        "name": "_",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.KLoginReq"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.ListDoctorRes"
+                            "$ref": "#/definitions/dto.LoginRes"
                         }
                     }
                 }
             }
         },
-        "/doctor/{id}": {
-            "get": {
+        "/auth-patient/register": {
+            "post": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Doctor"
+                    "users-patient"
                 ],
-                "summary": "Get Doctor by id",
+                "summary": "Register new user",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Doctor ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Key of the request and its retries, which get the first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Body",
+                        "name": "_",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.KRegisterReq"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.Doctor"
+                            "$ref": "#/definitions/dto.RegisterRes"
+                        }
+                    },
+                    "409": {
+                        "description": "The first request with the key is running",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "The key was used with another body",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/auth-patient/update-user": {
             "put": {
                 "security": [
                     {
@@ -971,17 +1264,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Doctor"
+                    "users-patient"
                 ],
-                "summary": "Update Doctor",
+                "summary": "changes the password",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ETag of my profile, its version",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
                     {
                         "description": "Body",
                         "name": "_",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.UpdateDoctorReq"
+                            "$ref": "#/definitions/dto.KUpdateUserReq"
                         }
                     }
                 ],
@@ -989,12 +1289,20 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.Doctor"
+                            "$ref": "#/definitions/dto.UpdateUserRes"
+                        }
+                    },
+                    "412": {
+                        "description": "Changed since it was read",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/auth/account/data-requests": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
@@ -1004,9 +1312,33 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Doctor"
+                    "users-privacy"
                 ],
-                "summary": "Delete Doctor",
+                "summary": "List my export and erasure requests",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ListDataRequestsRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/account/erasure": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users-privacy"
+                ],
+                "summary": "Delete my account once the grace period is over",
                 "parameters": [
                     {
                         "description": "Body",
@@ -1014,347 +1346,6675 @@ const docTemplate = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.DeleteDoctorReq"
+                            "$ref": "#/definitions/dto.RequestErasureReq"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "202": {
+                        "description": "Accepted",
                         "schema": {
-                            "$ref": "#/definitions/dto.Doctor"
+                            "$ref": "#/definitions/dto.DataRequest"
                         }
                     }
                 }
-            }
-        }
-    },
-    "definitions": {
-        "dto.Address": {
-            "type": "object",
-            "properties": {
-                "city": {
-                    "description": "City of the address\nexample: \"San Francisco\"",
-                    "type": "string"
-                },
-                "id_address": {
-                    "description": "ID of the address\nexample: \"12345\"",
-                    "type": "string"
-                },
-                "id_user": {
-                    "description": "User ID associated with the address\nexample: \"67890\"",
-                    "type": "string"
-                },
-                "lat": {
-                    "description": "Latitude of the address\nexample: \"37.7749\"",
-                    "type": "string"
-                },
-                "long": {
-                    "description": "Longitude of the address\nexample: \"-122.4194\"",
-                    "type": "string"
-                },
-                "name": {
-                    "description": "Name of the address\nexample: \"Home\"",
-                    "type": "string"
-                },
-                "street": {
-                    "description": "Street of the address\nexample: \"Market Street\"",
-                    "type": "string"
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users-privacy"
+                ],
+                "summary": "Cancel the pending deletion of my account",
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    }
                 }
             }
         },
-        "dto.CreateAddressReq": {
-            "type": "object",
-            "properties": {
-                "city": {
-                    "description": "City of the address\nexample: \"San Francisco\"",
-                    "type": "string"
-                },
-                "id_user": {
-                    "description": "User ID associated with the address\nexample: \"67890\"",
-                    "type": "string"
+        "/auth/account/export": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users-privacy"
+                ],
+                "summary": "Request an archive of my personal data",
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/dto.DataRequest"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/account/export/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/zip"
+                ],
+                "tags": [
+                    "users-privacy"
+                ],
+                "summary": "Download the archive of a completed export",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Data request ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    }
+                }
+            }
+        },
+        "/auth/avatar": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Set an uploaded image as my picture",
+                "parameters": [
+                    {
+                        "description": "Body",
+                        "name": "_",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.SetAvatarReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted"
+                    }
+                }
+            }
+        },
+        "/auth/identities": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users-oauth"
+                ],
+                "summary": "List the provider accounts linked to the signed-in user",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ListIdentitiesRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/identities/{provider}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users-oauth"
+                ],
+                "summary": "Unlink a provider account from the signed-in user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Provider name",
+                        "name": "provider",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    }
+                }
+            }
+        },
+        "/auth/me": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "get my profile",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ETag of the profile already read",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.User"
+                        }
+                    },
+                    "304": {
+                        "description": "Not changed since If-None-Match"
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "JSON Merge Patch (application/merge-patch+json, also for application/json) or JSON Patch (application/json-patch+json) of name, email and phone_number. A changed email or phone number must be verified again.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Change some fields of my profile",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ETag of my profile, its version",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Body",
+                        "name": "_",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.PatchUserReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.User"
+                        }
+                    },
+                    "409": {
+                        "description": "Email used by another account or a test operation failed",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "412": {
+                        "description": "Changed since it was read",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "415": {
+                        "description": "Not a patch media type",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid or read-only fields",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/mfa/confirm": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users-mfa"
+                ],
+                "summary": "Confirm TOTP enrollment and get recovery codes",
+                "parameters": [
+                    {
+                        "description": "Body",
+                        "name": "_",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.MFACodeReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.RecoveryCodesRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/mfa/disable": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users-mfa"
+                ],
+                "summary": "Disable MFA",
+                "parameters": [
+                    {
+                        "description": "Body",
+                        "name": "_",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.DisableMFAReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    }
+                }
+            }
+        },
+        "/auth/mfa/enroll": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users-mfa"
+                ],
+                "summary": "Start TOTP enrollment",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.EnrollMFARes"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/mfa/recovery-codes": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users-mfa"
+                ],
+                "summary": "Replace the recovery codes",
+                "parameters": [
+                    {
+                        "description": "Body",
+                        "name": "_",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.MFACodeReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.RecoveryCodesRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/mfa/verify": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users-mfa"
+                ],
+                "summary": "Exchange the login MFA token and a code for tokens",
+                "parameters": [
+                    {
+                        "description": "Body",
+                        "name": "_",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.VerifyMFAReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.LoginRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/oauth/{provider}/callback": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users-oauth"
+                ],
+                "summary": "Complete a provider login or link",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "google, facebook or the configured oidc name",
+                        "name": "provider",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "State returned by the provider",
+                        "name": "state",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Authorization code returned by the provider",
+                        "name": "code",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.LoginRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/oauth/{provider}/link": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users-oauth"
+                ],
+                "summary": "Start linking a provider account to the signed-in user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "google, facebook or the configured oidc name",
+                        "name": "provider",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OAuthURLRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/oauth/{provider}/login": {
+            "get": {
+                "tags": [
+                    "users-oauth"
+                ],
+                "summary": "Redirect to the provider consent page",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "google, facebook or the configured oidc name",
+                        "name": "provider",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "302": {
+                        "description": "Found"
+                    }
+                }
+            }
+        },
+        "/auth/refresh-token": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "get my profile",
+                "parameters": [
+                    {
+                        "description": "Body",
+                        "name": "_",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.RefreshTokenReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.RefreshTokenRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/resend-verfiy-code-email": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Verfiy Code for Email",
+                "parameters": [
+                    {
+                        "description": "Body",
+                        "name": "_",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResendVerifyEmailRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.VerifyResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/resend-verfiy-code-phone-number": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Verfiy Code for PhoneNumber",
+                "parameters": [
+                    {
+                        "description": "Body",
+                        "name": "_",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResendVerifyPhoneNumberRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.VerifyResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/sessions": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users-sessions"
+                ],
+                "summary": "List where the signed-in user is logged in",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ListSessionsRes"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users-sessions"
+                ],
+                "summary": "Log out every other session of the signed-in user",
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    }
+                }
+            }
+        },
+        "/auth/sessions/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users-sessions"
+                ],
+                "summary": "Log out a session of the signed-in user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID, or current",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    }
+                }
+            }
+        },
+        "/auth/verfiy-code-email": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Verfiy Code for Email",
+                "parameters": [
+                    {
+                        "description": "Body",
+                        "name": "_",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.VerifyEmailRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.VerifyResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/verfiy-code-phone-number": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Verfiy Code for PhoneNumber",
+                "parameters": [
+                    {
+                        "description": "Body",
+                        "name": "_",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.VerifyPhoneNumberRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.VerifyResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/clinic": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Clinic"
+                ],
+                "summary": "Get a clinic, the clinic of the signed-in clinic admin on /clinic",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ETag of the clinic already read",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Clinic"
+                        }
+                    },
+                    "304": {
+                        "description": "Not changed since If-None-Match"
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Clinic"
+                ],
+                "summary": "Update a clinic, the clinic of the signed-in clinic admin on /clinic",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ETag of the clinic, its version",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Body",
+                        "name": "_",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateClinicReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Clinic"
+                        }
+                    },
+                    "412": {
+                        "description": "Changed since it was read",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/clinic-admin/clinics": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Clinic-admin"
+                ],
+                "summary": "List the clinics",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ListClinicsRes"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Clinic-admin"
+                ],
+                "summary": "Create a clinic",
+                "parameters": [
+                    {
+                        "description": "Body",
+                        "name": "_",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateClinicReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Clinic"
+                        }
+                    }
+                }
+            }
+        },
+        "/clinic-admin/clinics/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Clinic"
+                ],
+                "summary": "Get a clinic, the clinic of the signed-in clinic admin on /clinic",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Clinic ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the clinic already read",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Clinic"
+                        }
+                    },
+                    "304": {
+                        "description": "Not changed since If-None-Match"
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Clinic"
+                ],
+                "summary": "Update a clinic, the clinic of the signed-in clinic admin on /clinic",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Clinic ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the clinic, its version",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Body",
+                        "name": "_",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateClinicReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Clinic"
+                        }
+                    },
+                    "412": {
+                        "description": "Changed since it was read",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/clinic-admin/clinics/{id}/staff": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Clinic"
+                ],
+                "summary": "List the staff of a clinic",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Clinic ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ListStaffRes"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Clinic"
+                ],
+                "summary": "Add a registered user to a clinic as doctor or clinic admin",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Clinic ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Body",
+                        "name": "_",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.AddStaffReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Staff"
+                        }
+                    }
+                }
+            }
+        },
+        "/clinic-admin/clinics/{id}/staff/{userId}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Clinic"
+                ],
+                "summary": "Remove a user from a clinic",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Clinic ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    }
+                }
+            }
+        },
+        "/clinic/staff": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Clinic"
+                ],
+                "summary": "List the staff of a clinic",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ListStaffRes"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Clinic"
+                ],
+                "summary": "Add a registered user to a clinic as doctor or clinic admin",
+                "parameters": [
+                    {
+                        "description": "Body",
+                        "name": "_",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.AddStaffReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Staff"
+                        }
+                    }
+                }
+            }
+        },
+        "/clinic/staff/{userId}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Clinic"
+                ],
+                "summary": "Remove a user from a clinic",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    }
+                }
+            }
+        },
+        "/consultations": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Consultation"
+                ],
+                "summary": "List the signed consultations of the signed-in patient",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ListConsultationsRes"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Consultation"
+                ],
+                "summary": "Start a draft consultation as the signed-in doctor",
+                "parameters": [
+                    {
+                        "description": "Body",
+                        "name": "_",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateConsultationReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Consultation"
+                        }
+                    }
+                }
+            }
+        },
+        "/consultations/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Consultation"
+                ],
+                "summary": "Get a consultation, as its doctor or its patient once signed",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Consultation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Consultation"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Consultation"
+                ],
+                "summary": "Replace the content of a draft consultation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Consultation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the consultation, its version",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Body",
+                        "name": "_",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateConsultationReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Consultation"
+                        }
+                    },
+                    "412": {
+                        "description": "Changed since it was read",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/consultations/{id}/amendments": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Consultation"
+                ],
+                "summary": "Add an amendment to a signed consultation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Consultation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Body",
+                        "name": "_",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.AmendConsultationReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Amendment"
+                        }
+                    }
+                }
+            }
+        },
+        "/consultations/{id}/prescription": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/pdf"
+                ],
+                "tags": [
+                    "Consultation"
+                ],
+                "summary": "Download the signed prescription of a consultation as a pdf",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Consultation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    }
+                }
+            }
+        },
+        "/consultations/{id}/sign": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Consultation"
+                ],
+                "summary": "Sign a consultation, it cannot be changed afterwards and its prescription is issued",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Consultation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the consultation read before signing, its version",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Consultation"
+                        }
+                    },
+                    "412": {
+                        "description": "Changed since it was read",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/doctor": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Doctor"
+                ],
+                "summary": "create Doctor",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Key of the request and its retries, which get the first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Body",
+                        "name": "_",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateDoctorReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Doctor"
+                        }
+                    },
+                    "409": {
+                        "description": "The first request with the key is running",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "The key was used with another body",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/doctor-admin/doctors/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Doctor"
+                ],
+                "summary": "Restore a deleted doctor",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Doctor ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Doctor"
+                        }
+                    }
+                }
+            }
+        },
+        "/doctor-admin/verifications": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Doctor-verification"
+                ],
+                "summary": "List the verifications to review, oldest first",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "pending, approved or rejected, pending by default",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ListVerificationsRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/doctor-admin/verifications/{id}/approve": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Doctor-verification"
+                ],
+                "summary": "Approve a verification, the doctor is listed and can be booked",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Verification ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Verification"
+                        }
+                    }
+                }
+            }
+        },
+        "/doctor-admin/verifications/{id}/reject": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Doctor-verification"
+                ],
+                "summary": "Reject a verification with the reason",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Verification ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Body",
+                        "name": "_",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.RejectVerificationReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Verification"
+                        }
+                    }
+                }
+            }
+        },
+        "/doctor/consultations": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Consultation"
+                ],
+                "summary": "List the consultations written by the signed-in doctor",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Patient ID",
+                        "name": "patient_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ListConsultationsRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/doctor/image": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Doctor"
+                ],
+                "summary": "Set an uploaded image as the picture of the signed-in doctor",
+                "parameters": [
+                    {
+                        "description": "Body",
+                        "name": "_",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.SetImageReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted"
+                    }
+                }
+            }
+        },
+        "/doctor/list_doctors": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Doctor"
+                ],
+                "summary": "ListDoctors",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID User",
+                        "name": "id_user",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit per page",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "name, price, experience, created_at, rating_average or rating_count",
+                        "name": "order_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Order descending",
+                        "name": "order_desc",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Minimum average rating",
+                        "name": "min_rating",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Also list the deleted doctors, for admins",
+                        "name": "include_deleted",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ListDoctorRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/doctor/specialties": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Specialty"
+                ],
+                "summary": "Replace the specialties of the signed-in doctor",
+                "parameters": [
+                    {
+                        "description": "Body",
+                        "name": "_",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.SetDoctorSpecialtiesReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    }
+                }
+            }
+        },
+        "/doctor/verification": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Doctor-verification"
+                ],
+                "summary": "Get the last verification of the signed-in doctor",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Verification"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Doctor-verification"
+                ],
+                "summary": "Submit the license of the signed-in doctor for review",
+                "parameters": [
+                    {
+                        "description": "Body",
+                        "name": "_",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.SubmitVerificationReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Verification"
+                        }
+                    }
+                }
+            }
+        },
+        "/doctor/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Doctor"
+                ],
+                "summary": "Get Doctor by id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Doctor ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the doctor already read",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Doctor"
+                        }
+                    },
+                    "304": {
+                        "description": "Not changed since If-None-Match"
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Doctor"
+                ],
+                "summary": "Update Doctor",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ETag of the doctor, its version",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Body",
+                        "name": "_",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateDoctorReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Doctor"
+                        }
+                    },
+                    "412": {
+                        "description": "Changed since it was read",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Doctor"
+                ],
+                "summary": "Delete Doctor",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ETag of the doctor, its version",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Body",
+                        "name": "_",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.DeleteDoctorReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Doctor"
+                        }
+                    },
+                    "412": {
+                        "description": "Changed since it was read",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "JSON Merge Patch (application/merge-patch+json, also for application/json) or JSON Patch (application/json-patch+json) of the fields of UpdateDoctorReq, the other fields are kept",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Doctor"
+                ],
+                "summary": "Change some fields of a Doctor",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Doctor ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the doctor, its version",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Body",
+                        "name": "_",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.PatchDoctorReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Doctor"
+                        }
+                    },
+                    "409": {
+                        "description": "A test operation failed",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "412": {
+                        "description": "Changed since it was read",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "415": {
+                        "description": "Not a patch media type",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid or read-only fields",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/files": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Files"
+                ],
+                "summary": "Upload a file as multipart form data",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "JPEG, PNG, WebP or PDF",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.File"
+                        }
+                    }
+                }
+            }
+        },
+        "/files/stream": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/octet-stream"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Files"
+                ],
+                "summary": "Upload a file as the request body",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "File name",
+                        "name": "name",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.File"
+                        }
+                    }
+                }
+            }
+        },
+        "/files/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Files"
+                ],
+                "summary": "Get a file with a new download url",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "File ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.File"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Files"
+                ],
+                "summary": "Delete a file of the signed-in user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "File ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    }
+                }
+            }
+        },
+        "/files/{id}/download": {
+            "get": {
+                "produces": [
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "Files"
+                ],
+                "summary": "Download a file with a signed url",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "File ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Expiry of the url",
+                        "name": "expires",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Signature of the url",
+                        "name": "signature",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    }
+                }
+            }
+        },
+        "/health/grants": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "List the doctors the signed-in patient gave access to its health profile",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ListGrantsRes"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Give a doctor access to the health profile of the signed-in patient",
+                "parameters": [
+                    {
+                        "description": "Body",
+                        "name": "_",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.GrantAccessReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Grant"
+                        }
+                    }
+                }
+            }
+        },
+        "/health/grants/{doctorId}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Remove the access of a doctor to the health profile of the signed-in patient",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Doctor ID",
+                        "name": "doctorId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    }
+                }
+            }
+        },
+        "/health/patients": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "List the patients who gave the signed-in doctor access to their health profile",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ListGrantsRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/health/patients/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Get the health profile of a patient, me for the signed-in one",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Patient ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.HealthProfile"
+                        }
+                    }
+                }
+            }
+        },
+        "/health/patients/{id}/allergies": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Add an allergy to the health profile of a patient",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Patient ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Body",
+                        "name": "_",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.SaveAllergyReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Allergy"
+                        }
+                    }
+                }
+            }
+        },
+        "/health/patients/{id}/allergies/{itemId}": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Update an allergy of a patient",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Patient ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Allergy ID",
+                        "name": "itemId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the allergy, its version",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Body",
+                        "name": "_",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.SaveAllergyReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Allergy"
+                        }
+                    },
+                    "412": {
+                        "description": "Changed since it was read",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Delete an allergy of a patient",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Patient ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Allergy ID",
+                        "name": "itemId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the allergy, its version",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "412": {
+                        "description": "Changed since it was read",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/health/patients/{id}/blood-type": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Set the blood type of a patient",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Patient ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the blood type, the version of the health profile, 0 before it is set",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Body",
+                        "name": "_",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.SetBloodTypeReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "412": {
+                        "description": "Changed since it was read",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/health/patients/{id}/conditions": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Add a chronic condition to the health profile of a patient",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Patient ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Body",
+                        "name": "_",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.SaveConditionReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Condition"
+                        }
+                    }
+                }
+            }
+        },
+        "/health/patients/{id}/conditions/{itemId}": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Update a chronic condition of a patient",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Patient ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Condition ID",
+                        "name": "itemId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the condition, its version",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Body",
+                        "name": "_",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.SaveConditionReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Condition"
+                        }
+                    },
+                    "412": {
+                        "description": "Changed since it was read",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Delete a chronic condition of a patient",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Patient ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Condition ID",
+                        "name": "itemId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the condition, its version",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "412": {
+                        "description": "Changed since it was read",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/health/patients/{id}/history": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "List the changes of the health profile of a patient, newest first",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Patient ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "profile, allergy, condition, medication, vital or grant",
+                        "name": "entity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ListChangesRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/health/patients/{id}/medications": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Add a current medication to the health profile of a patient",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Patient ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Body",
+                        "name": "_",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.SaveMedicationReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Medication"
+                        }
+                    }
+                }
+            }
+        },
+        "/health/patients/{id}/medications/{itemId}": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Update a medication of a patient",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Patient ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Medication ID",
+                        "name": "itemId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the medication, its version",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Body",
+                        "name": "_",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.SaveMedicationReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Medication"
+                        }
+                    },
+                    "412": {
+                        "description": "Changed since it was read",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Delete a medication of a patient",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Patient ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Medication ID",
+                        "name": "itemId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the medication, its version",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "412": {
+                        "description": "Changed since it was read",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/health/patients/{id}/vitals": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "List the vitals history of a patient, newest first",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Patient ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ListVitalsRes"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Record a measurement of the vitals of a patient",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Patient ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Body",
+                        "name": "_",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.RecordVitalReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Vital"
+                        }
+                    }
+                }
+            }
+        },
+        "/images/{id}/{variant}": {
+            "get": {
+                "produces": [
+                    "image/jpeg",
+                    "image/webp"
+                ],
+                "tags": [
+                    "Files"
+                ],
+                "summary": "Get a variant of a profile picture",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "File ID of the picture",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "small, medium or large, .jpg or .webp",
+                        "name": "variant",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    }
+                }
+            }
+        },
+        "/notifications": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notifications"
+                ],
+                "summary": "List the notifications of the user not expired yet, the latest first",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Only the unread notifications",
+                        "name": "unread",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ListNotificationsRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications/preferences": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notifications"
+                ],
+                "summary": "List the channels the user receives every type of notifications on",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ListPreferencesRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications/preferences/{type}": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notifications"
+                ],
+                "summary": "Choose the channels the user receives a type of notifications on, the required types cannot be changed",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Notification type, such as verification_status",
+                        "name": "type",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Body",
+                        "name": "_",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdatePreferenceReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Preference"
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications/read-all": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notifications"
+                ],
+                "summary": "Mark all the notifications of the user read",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.MarkAllReadRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications/unread-count": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notifications"
+                ],
+                "summary": "Count the unread notifications of the user",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UnreadCountRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notifications"
+                ],
+                "summary": "Delete a notification",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Notification ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    }
+                }
+            }
+        },
+        "/notifications/{id}/read": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notifications"
+                ],
+                "summary": "Mark a notification read",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Notification ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    }
+                }
+            }
+        },
+        "/oauth/token": {
+            "post": {
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users-oauth"
+                ],
+                "summary": "Get a client token with the client credentials grant",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "client_credentials",
+                        "name": "grant_type",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "API key ID, or use HTTP Basic",
+                        "name": "client_id",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "API key, or use HTTP Basic",
+                        "name": "client_secret",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Space separated scopes",
+                        "name": "scope",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ClientTokenRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/prescriptions/verify/{code}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Prescription"
+                ],
+                "summary": "Verify a prescription with the signature of its QR code or its printed fingerprint",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Prescription code",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Signature or fingerprint",
+                        "name": "sig",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PrescriptionVerification"
+                        }
+                    }
+                }
+            }
+        },
+        "/realtime-admin/broadcast": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Realtime-admin"
+                ],
+                "summary": "Push a message to every connected user",
+                "parameters": [
+                    {
+                        "description": "Body",
+                        "name": "_",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.BroadcastReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/realtime/events": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "Realtime"
+                ],
+                "summary": "Receive the messages of the user and the broadcasts as Server-Sent Events, named by their type",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Access token, for the clients that cannot set the Authorization header",
+                        "name": "access_token",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/realtime/ws": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Realtime"
+                ],
+                "summary": "Receive the messages of the user and the broadcasts over a WebSocket, as json text frames",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Access token, for the clients that cannot set the Authorization header",
+                        "name": "access_token",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "Switching Protocols",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/review-admin/reviews": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Review-admin"
+                ],
+                "summary": "List the reviews with the hidden ones, newest first",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Doctor ID",
+                        "name": "doctor_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only reviews with this rating",
+                        "name": "rating",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only hidden or visible reviews",
+                        "name": "hidden",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ListModeratedReviewsRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/review-admin/reviews/{id}/hide": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Review-admin"
+                ],
+                "summary": "Hide an abusive review, it no longer counts in the rating",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Review ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the review, its version",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Body",
+                        "name": "_",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.HideReviewReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ModeratedReview"
+                        }
+                    },
+                    "412": {
+                        "description": "Changed since it was read",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/review-admin/reviews/{id}/unhide": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Review-admin"
+                ],
+                "summary": "Show a hidden review again",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Review ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the review, its version",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ModeratedReview"
+                        }
+                    },
+                    "412": {
+                        "description": "Changed since it was read",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/reviews": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Review"
+                ],
+                "summary": "List the reviews, newest first",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Doctor ID",
+                        "name": "doctor_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only reviews with this rating",
+                        "name": "rating",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ListReviewsRes"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Review"
+                ],
+                "summary": "Review a doctor, once per doctor",
+                "parameters": [
+                    {
+                        "description": "Body",
+                        "name": "_",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateReviewReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Review"
+                        }
+                    }
+                }
+            }
+        },
+        "/reviews/{id}/reply": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Review"
+                ],
+                "summary": "Reply to a review of the signed-in doctor, once",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Review ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the review, its version",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Body",
+                        "name": "_",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ReplyReviewReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Review"
+                        }
+                    },
+                    "412": {
+                        "description": "Changed since it was read",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/specialties": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Specialty"
+                ],
+                "summary": "List the specialties catalogue as a tree",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Locale of the names, Accept-Language by default",
+                        "name": "locale",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ListSpecialtiesRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/specialties/{slug}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Specialty"
+                ],
+                "summary": "Get a specialty with its children",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Slug",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Locale of the names, Accept-Language by default",
+                        "name": "locale",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Specialty"
+                        }
+                    }
+                }
+            }
+        },
+        "/specialty-admin/doctors/{id}/specialties": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Specialty-admin"
+                ],
+                "summary": "Replace the specialties of a doctor",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Doctor ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Body",
+                        "name": "_",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.SetDoctorSpecialtiesReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    }
+                }
+            }
+        },
+        "/specialty-admin/migrate": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Specialty-admin"
+                ],
+                "summary": "Map the free-text specialities of doctors to the catalogue",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.MigrationReport"
+                        }
+                    }
+                }
+            }
+        },
+        "/specialty-admin/specialties": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Specialty-admin"
+                ],
+                "summary": "Add a specialty to the catalogue",
+                "parameters": [
+                    {
+                        "description": "Body",
+                        "name": "_",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateSpecialtyReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Specialty"
+                        }
+                    }
+                }
+            }
+        },
+        "/specialty-admin/specialties/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Specialty-admin"
+                ],
+                "summary": "Replace a specialty",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Specialty ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the specialty, its version",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Body",
+                        "name": "_",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateSpecialtyReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Specialty"
+                        }
+                    },
+                    "412": {
+                        "description": "Changed since it was read",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Specialty-admin"
+                ],
+                "summary": "Delete a specialty without children nor doctors",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Specialty ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the specialty, its version",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "412": {
+                        "description": "Changed since it was read",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhook-admin/subscriptions": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhook-admin"
+                ],
+                "summary": "List the webhook subscriptions",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ListSubscriptionsRes"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhook-admin"
+                ],
+                "summary": "Subscribe a partner URL to events, the callbacks are signed with the returned secret",
+                "parameters": [
+                    {
+                        "description": "Body",
+                        "name": "_",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateSubscriptionReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateSubscriptionRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhook-admin/subscriptions/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhook-admin"
+                ],
+                "summary": "Get a webhook subscription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Subscription"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhook-admin"
+                ],
+                "summary": "Update a webhook subscription, activating it again resets its failures",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Body",
+                        "name": "_",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateSubscriptionReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Subscription"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhook-admin"
+                ],
+                "summary": "Delete a webhook subscription and its deliveries",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    }
+                }
+            }
+        },
+        "/webhook-admin/subscriptions/{id}/deliveries": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhook-admin"
+                ],
+                "summary": "List the deliveries of a subscription, the latest first",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "pending, succeeded or failed",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ListDeliveriesRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhook-admin/subscriptions/{id}/deliveries/{delivery_id}/redeliver": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhook-admin"
+                ],
+                "summary": "Attempt a delivery again, with as many attempts as a new one",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Delivery ID",
+                        "name": "delivery_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Delivery"
+                        }
+                    },
+                    "409": {
+                        "description": "Subscription disabled",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhook-admin/subscriptions/{id}/ping": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhook-admin"
+                ],
+                "summary": "Send a webhook.ping event to a subscription and return its delivery",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Delivery"
+                        }
+                    },
+                    "409": {
+                        "description": "Subscription disabled",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhook-admin/subscriptions/{id}/rotate-secret": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhook-admin"
+                ],
+                "summary": "Replace the secret signing the callbacks of a subscription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.RotateSecretRes"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "dto.APIKey": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "description": "Public part of the key, to tell keys apart\nexample: \"dk_a1B2c3D4\"",
+                    "type": "string"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "dto.AddStaffReq": {
+            "type": "object",
+            "required": [
+                "email",
+                "role"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "role": {
+                    "description": "example: \"doctor\"",
+                    "enum": [
+                        "doctor",
+                        "clinic_admin"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.UserRole"
+                        }
+                    ]
+                }
+            }
+        },
+        "dto.Address": {
+            "type": "object",
+            "properties": {
+                "city": {
+                    "description": "City of the address\nexample: \"San Francisco\"",
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "description": "Set on the deleted addresses listed by admins",
+                    "type": "string"
+                },
+                "id_address": {
+                    "description": "ID of the address\nexample: \"12345\"",
+                    "type": "string"
+                },
+                "id_user": {
+                    "description": "User ID associated with the address\nexample: \"67890\"",
+                    "type": "string"
+                },
+                "lat": {
+                    "description": "Latitude of the address\nexample: \"37.7749\"",
+                    "type": "string"
+                },
+                "long": {
+                    "description": "Longitude of the address\nexample: \"-122.4194\"",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Name of the address\nexample: \"Home\"",
+                    "type": "string"
+                },
+                "street": {
+                    "description": "Street of the address\nexample: \"Market Street\"",
+                    "type": "string"
+                },
+                "version": {
+                    "description": "Version of the address, also sent as its ETag",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.Allergy": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "reaction": {
+                    "type": "string"
+                },
+                "severity": {
+                    "description": "example: \"severe\"",
+                    "type": "string"
+                },
+                "substance": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "version": {
+                    "description": "Version of the allergy, also sent as its ETag",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.AmendConsultationReq": {
+            "type": "object",
+            "required": [
+                "reason",
+                "text"
+            ],
+            "properties": {
+                "reason": {
+                    "description": "example: \"Wrong dosage noted\"",
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "text": {
+                    "type": "string",
+                    "maxLength": 10000
+                }
+            }
+        },
+        "dto.Amendment": {
+            "type": "object",
+            "properties": {
+                "author_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.AuditChainRes": {
+            "type": "object",
+            "properties": {
+                "broken_at": {
+                    "type": "integer"
+                },
+                "checked": {
+                    "type": "integer"
+                },
+                "valid": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.AuditEvent": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "actor_id": {
+                    "type": "string"
+                },
+                "actor_role": {
+                    "type": "string"
+                },
+                "after": {
+                    "type": "string"
+                },
+                "before": {
+                    "type": "string"
+                },
+                "client_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "diff": {
+                    "type": "string"
+                },
+                "hash": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "ip": {
+                    "type": "string"
+                },
+                "outcome": {
+                    "type": "string"
+                },
+                "prev_hash": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "request_id": {
+                    "type": "string"
+                },
+                "seq": {
+                    "type": "integer"
+                },
+                "target_id": {
+                    "type": "string"
+                },
+                "target_type": {
+                    "type": "string"
+                },
+                "tenant_id": {
+                    "type": "string"
+                },
+                "user_agent": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.BroadcastReq": {
+            "type": "object",
+            "required": [
+                "title"
+            ],
+            "properties": {
+                "body": {
+                    "type": "string",
+                    "maxLength": 2000
+                },
+                "title": {
+                    "description": "example: \"Scheduled maintenance\"",
+                    "type": "string",
+                    "maxLength": 200
+                }
+            }
+        },
+        "dto.Change": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "actor_id": {
+                    "type": "string"
+                },
+                "after": {
+                    "type": "string"
+                },
+                "before": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "entity": {
+                    "type": "string"
+                },
+                "entity_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ClientTokenRes": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "type": "string"
+                },
+                "expires_in": {
+                    "description": "Seconds until the token expires",
+                    "type": "integer"
+                },
+                "scope": {
+                    "type": "string"
+                },
+                "token_type": {
+                    "description": "example: \"Bearer\"",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.Clinic": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "version": {
+                    "description": "Version of the clinic, also sent as its ETag",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.Condition": {
+            "type": "object",
+            "properties": {
+                "diagnosed_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "example: \"active\"",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "version": {
+                    "description": "Version of the condition, also sent as its ETag",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.Consultation": {
+            "type": "object",
+            "properties": {
+                "amendments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.Amendment"
+                    }
+                },
+                "assessment": {
+                    "type": "string"
+                },
+                "content_hash": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "diagnoses": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.Diagnosis"
+                    }
+                },
+                "doctor_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "medications": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.PrescribedMedication"
+                    }
+                },
+                "objective": {
+                    "type": "string"
+                },
+                "patient_id": {
+                    "type": "string"
+                },
+                "plan": {
+                    "type": "string"
+                },
+                "signed_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "example: \"signed\"",
+                    "type": "string"
+                },
+                "subjective": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "version": {
+                    "description": "Version of the consultation, also sent as its ETag",
+                    "type": "integer"
+                },
+                "visited_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CreateAPIKeyReq": {
+            "type": "object",
+            "required": [
+                "name",
+                "scopes"
+            ],
+            "properties": {
+                "expires_at": {
+                    "description": "The key never expires when empty",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Which service uses the key\nexample: \"billing\"",
+                    "type": "string"
+                },
+                "scopes": {
+                    "description": "Permissions granted to the key\nexample: [\"users:read\",\"doctors:read\"]",
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "dto.CreateAPIKeyRes": {
+            "type": "object",
+            "properties": {
+                "api_key": {
+                    "$ref": "#/definitions/dto.APIKey"
+                },
+                "key": {
+                    "description": "The key, shown only once. Send it in the X-API-Key header, or use the\nkey id and the key as client_id and client_secret of the client\ncredentials grant.",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CreateAddressReq": {
+            "type": "object",
+            "properties": {
+                "city": {
+                    "description": "City of the address\nexample: \"San Francisco\"",
+                    "type": "string"
+                },
+                "id_user": {
+                    "description": "User ID associated with the address\nexample: \"67890\"",
+                    "type": "string"
+                },
+                "lat": {
+                    "description": "Latitude of the address\nexample: \"37.7749\"",
+                    "type": "string"
+                },
+                "long": {
+                    "description": "Longitude of the address\nexample: \"-122.4194\"",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Name of the address\nexample: \"Home\"",
+                    "type": "string"
+                },
+                "street": {
+                    "description": "Street of the address\nexample: \"Market Street\"",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CreateClinicReq": {
+            "type": "object",
+            "required": [
+                "name",
+                "slug"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "name": {
+                    "description": "example: \"Saint Mary Clinic\"",
+                    "type": "string",
+                    "maxLength": 200
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "slug": {
+                    "description": "Unique, lower case words separated by dashes\nexample: \"saint-mary\"",
+                    "type": "string",
+                    "maxLength": 100
+                }
+            }
+        },
+        "dto.CreateConsultationReq": {
+            "type": "object",
+            "required": [
+                "diagnoses",
+                "medications",
+                "patient_id"
+            ],
+            "properties": {
+                "assessment": {
+                    "type": "string",
+                    "maxLength": 10000
+                },
+                "diagnoses": {
+                    "type": "array",
+                    "maxItems": 20,
+                    "items": {
+                        "$ref": "#/definitions/dto.Diagnosis"
+                    }
+                },
+                "medications": {
+                    "type": "array",
+                    "maxItems": 30,
+                    "items": {
+                        "$ref": "#/definitions/dto.PrescribedMedication"
+                    }
+                },
+                "objective": {
+                    "type": "string",
+                    "maxLength": 10000
+                },
+                "patient_id": {
+                    "type": "string"
+                },
+                "plan": {
+                    "type": "string",
+                    "maxLength": 10000
+                },
+                "subjective": {
+                    "type": "string",
+                    "maxLength": 10000
+                },
+                "visited_at": {
+                    "description": "The creation time by default",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CreateDoctorReq": {
+            "type": "object",
+            "properties": {
+                "experience": {
+                    "type": "integer"
+                },
+                "id_user": {
+                    "type": "string"
+                },
+                "image": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "specalist": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CreateReviewReq": {
+            "type": "object",
+            "required": [
+                "doctor_id",
+                "rating"
+            ],
+            "properties": {
+                "doctor_id": {
+                    "type": "string"
+                },
+                "rating": {
+                    "description": "From 1 to 5\nexample: 5",
+                    "type": "integer",
+                    "maximum": 5,
+                    "minimum": 1
+                },
+                "text": {
+                    "description": "example: \"Listened carefully and explained everything\"",
+                    "type": "string",
+                    "maxLength": 2000
+                }
+            }
+        },
+        "dto.CreateSpecialtyReq": {
+            "type": "object",
+            "required": [
+                "aliases",
+                "names",
+                "slug"
+            ],
+            "properties": {
+                "aliases": {
+                    "description": "Other spellings of the specialty found in free-text values\nexample: [\"pediatric cardiologist\"]",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "icon": {
+                    "description": "Url of the icon",
+                    "type": "string"
+                },
+                "names": {
+                    "description": "Names by locale, en is required\nexample: {\"en\":\"Pediatric cardiology\",\"fr\":\"Cardiologie pédiatrique\"}",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "parent_id": {
+                    "type": "string"
+                },
+                "slug": {
+                    "description": "Lower case words separated by dashes\nexample: \"pediatric-cardiology\"",
+                    "type": "string",
+                    "maxLength": 64
+                }
+            }
+        },
+        "dto.CreateSubscriptionReq": {
+            "type": "object",
+            "required": [
+                "events",
+                "url"
+            ],
+            "properties": {
+                "clinic_id": {
+                    "description": "ClinicID restricts the subscription to the events of a clinic",
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "events": {
+                    "description": "Names of the events, or \"*\" for all of them\nexample: [\"doctor.created\",\"doctor.updated\"]",
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "url": {
+                    "description": "example: \"https://partner.example.com/webhooks\"",
+                    "type": "string",
+                    "maxLength": 2000
+                }
+            }
+        },
+        "dto.CreateSubscriptionRes": {
+            "type": "object",
+            "properties": {
+                "secret": {
+                    "type": "string"
+                },
+                "subscription": {
+                    "$ref": "#/definitions/dto.Subscription"
+                }
+            }
+        },
+        "dto.DataRequest": {
+            "type": "object",
+            "properties": {
+                "completed_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "due_at": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "description": "ExpiresAt is when the export archive is deleted",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "kind": {
+                    "description": "export or erasure\nexample: \"export\"",
+                    "type": "string"
+                },
+                "size": {
+                    "description": "Size of the export archive in bytes",
+                    "type": "integer"
+                },
+                "status": {
+                    "description": "pending, processing, completed, failed, cancelled or expired\nexample: \"pending\"",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.DeleteAddressReq": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "description": "ID of the address\nexample: \"12345\"",
+                    "type": "string"
+                },
+                "id_user": {
+                    "description": "User ID associated with the address\nexample: \"67890\"",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.DeleteDoctorReq": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "description": "ID of the Doctor\nexample: \"12345\"",
+                    "type": "string"
+                },
+                "id_user": {
+                    "description": "User ID associated with the Doctor\nexample: \"67890\"",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.DeleteUserReq": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "description": "ID of the address\nexample: \"12345\"",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.Delivery": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "delivered_at": {
+                    "type": "string"
+                },
+                "duration_ms": {
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "event_id": {
+                    "type": "string"
+                },
+                "event_name": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "next_attempt_at": {
+                    "type": "string"
+                },
+                "payload": {
+                    "type": "string"
+                },
+                "response_body": {
+                    "type": "string"
+                },
+                "response_status": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "subscription_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.Diagnosis": {
+            "type": "object",
+            "required": [
+                "description"
+            ],
+            "properties": {
+                "code": {
+                    "description": "example: \"J06.9\"",
+                    "type": "string",
+                    "maxLength": 20
+                },
+                "description": {
+                    "description": "example: \"Acute upper respiratory infection\"",
+                    "type": "string",
+                    "maxLength": 500
+                }
+            }
+        },
+        "dto.DisableMFAReq": {
+            "type": "object",
+            "required": [
+                "code",
+                "password"
+            ],
+            "properties": {
+                "code": {
+                    "description": "Code from the authenticator app or a recovery code\nexample: \"123456\"",
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.Doctor": {
+            "type": "object",
+            "properties": {
+                "deleted_at": {
+                    "description": "Set on the deleted doctors listed by admins",
+                    "type": "string"
+                },
+                "experience": {
+                    "type": "integer"
+                },
+                "id_Doctor": {
+                    "type": "string"
+                },
+                "id_user": {
+                    "type": "string"
+                },
+                "image": {
+                    "type": "string"
+                },
+                "image_variants": {
+                    "description": "Thumbnails of the picture by size and format\nexample: {\"small.webp\":\"http://localhost:8888/api/v1/images/12345/small.webp\"}",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "license_expires_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "rating_average": {
+                    "description": "Average rating of the reviews, from 1 to 5\nexample: 4.5",
+                    "type": "number"
+                },
+                "rating_count": {
+                    "type": "integer"
+                },
+                "specalist": {
+                    "type": "string"
+                },
+                "specialties": {
+                    "description": "Specialties of the catalogue, one is primary",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_doctor_dto.DoctorSpecialty"
+                    }
+                },
+                "status": {
+                    "description": "License verification status\nexample: \"verified\"",
+                    "type": "string"
+                },
+                "version": {
+                    "description": "Version of the doctor, also sent as its ETag",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.EnrollMFARes": {
+            "type": "object",
+            "properties": {
+                "otpauth_uri": {
+                    "description": "otpauth:// provisioning URI",
+                    "type": "string"
+                },
+                "qr_code": {
+                    "description": "Provisioning URI rendered as a PNG data URI",
+                    "type": "string"
+                },
+                "secret": {
+                    "description": "Base32 secret for manual entry\nexample: \"JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP\"",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.File": {
+            "type": "object",
+            "properties": {
+                "checksum": {
+                    "description": "Hex sha256 of the content",
+                    "type": "string"
+                },
+                "content_type": {
+                    "description": "Sniffed from the content\nexample: \"image/png\"",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "url": {
+                    "description": "Signed download url, anyone having it can download the file until it\nexpires",
+                    "type": "string"
+                },
+                "url_expires_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.Grant": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "doctor_id": {
+                    "type": "string"
+                },
+                "patient_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.GrantAccessReq": {
+            "type": "object",
+            "required": [
+                "doctor_id"
+            ],
+            "properties": {
+                "doctor_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.HealthProfile": {
+            "type": "object",
+            "properties": {
+                "allergies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.Allergy"
+                    }
+                },
+                "blood_type": {
+                    "description": "example: \"O+\"",
+                    "type": "string"
+                },
+                "conditions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.Condition"
+                    }
+                },
+                "latest_vital": {
+                    "$ref": "#/definitions/dto.Vital"
+                },
+                "medications": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.Medication"
+                    }
+                },
+                "patient_id": {
+                    "type": "string"
+                },
+                "version": {
+                    "description": "Version of the blood type, 0 until it is set, also the ETag to set it",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.HideReviewReq": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "reason": {
+                    "description": "example: \"Abusive language\"",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.Identity": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "provider": {
+                    "description": "example: \"google\"",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.KLoginReq": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.KRegisterReq": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                },
+                "phone_number": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.KUpdateUserReq": {
+            "type": "object",
+            "required": [
+                "email",
+                "new_password",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "new_password": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                },
+                "phone_number": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.KUser": {
+            "type": "object",
+            "properties": {
+                "approve_email": {
+                    "type": "boolean"
+                },
+                "approve_phone_number": {
+                    "type": "boolean"
+                },
+                "avatar": {
+                    "type": "string"
+                },
+                "avatar_variants": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                },
+                "phone_number": {
+                    "type": "string"
+                },
+                "role": {
+                    "$ref": "#/definitions/model.UserRole"
+                },
+                "tenant_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "verify_code_email": {
+                    "type": "integer"
+                },
+                "verify_code_phone_number": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.ListAPIKeysRes": {
+            "type": "object",
+            "properties": {
+                "api_keys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.APIKey"
+                    }
+                }
+            }
+        },
+        "dto.ListAddressRes": {
+            "type": "object",
+            "properties": {
+                "addresses": {
+                    "description": "List of addresses\nexample: [{\"id_address\":\"12345\",\"id_user\":\"67890\",\"name\":\"Home\",\"city\":\"San Francisco\",\"street\":\"Market Street\",\"lat\":\"37.7749\",\"long\":\"-122.4194\"}]",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.Address"
+                    }
+                },
+                "pagination": {
+                    "description": "Pagination info",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/paging.Pagination"
+                        }
+                    ]
+                }
+            }
+        },
+        "dto.ListAuditEventsRes": {
+            "type": "object",
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.AuditEvent"
+                    }
+                },
+                "pagination": {
+                    "$ref": "#/definitions/paging.Pagination"
+                }
+            }
+        },
+        "dto.ListChangesRes": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.Change"
+                    }
+                },
+                "pagination": {
+                    "$ref": "#/definitions/paging.Pagination"
+                }
+            }
+        },
+        "dto.ListClinicsRes": {
+            "type": "object",
+            "properties": {
+                "clinics": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.Clinic"
+                    }
+                }
+            }
+        },
+        "dto.ListConsultationsRes": {
+            "type": "object",
+            "properties": {
+                "consultations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.Consultation"
+                    }
+                },
+                "pagination": {
+                    "$ref": "#/definitions/paging.Pagination"
+                }
+            }
+        },
+        "dto.ListDataRequestsRes": {
+            "type": "object",
+            "properties": {
+                "data_requests": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.DataRequest"
+                    }
+                }
+            }
+        },
+        "dto.ListDeliveriesRes": {
+            "type": "object",
+            "properties": {
+                "deliveries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.Delivery"
+                    }
+                },
+                "pagination": {
+                    "$ref": "#/definitions/paging.Pagination"
+                }
+            }
+        },
+        "dto.ListDoctorRes": {
+            "type": "object",
+            "properties": {
+                "Doctors": {
+                    "description": "List of Doctors\nexample: [{\"id_Doctor\":\"12345\",\"id_user\":\"67890\",\"name\":\"Home\",\"city\":\"San Francisco\",\"street\":\"Market Street\",\"lat\":\"37.7749\",\"long\":\"-122.4194\"}]",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.Doctor"
+                    }
+                },
+                "pagination": {
+                    "description": "Pagination info",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/paging.Pagination"
+                        }
+                    ]
+                }
+            }
+        },
+        "dto.ListGrantsRes": {
+            "type": "object",
+            "properties": {
+                "grants": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.Grant"
+                    }
+                }
+            }
+        },
+        "dto.ListIdentitiesRes": {
+            "type": "object",
+            "properties": {
+                "identities": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.Identity"
+                    }
+                }
+            }
+        },
+        "dto.ListModeratedReviewsRes": {
+            "type": "object",
+            "properties": {
+                "pagination": {
+                    "$ref": "#/definitions/paging.Pagination"
+                },
+                "reviews": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.ModeratedReview"
+                    }
+                }
+            }
+        },
+        "dto.ListNotificationsRes": {
+            "type": "object",
+            "properties": {
+                "notifications": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.Notification"
+                    }
+                },
+                "pagination": {
+                    "$ref": "#/definitions/paging.Pagination"
+                }
+            }
+        },
+        "dto.ListPreferencesRes": {
+            "type": "object",
+            "properties": {
+                "preferences": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.Preference"
+                    }
+                }
+            }
+        },
+        "dto.ListReviewsRes": {
+            "type": "object",
+            "properties": {
+                "pagination": {
+                    "$ref": "#/definitions/paging.Pagination"
+                },
+                "reviews": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.Review"
+                    }
+                }
+            }
+        },
+        "dto.ListSessionsRes": {
+            "type": "object",
+            "properties": {
+                "sessions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.Session"
+                    }
+                }
+            }
+        },
+        "dto.ListSpecialtiesRes": {
+            "type": "object",
+            "properties": {
+                "specialties": {
+                    "description": "Root specialties with their children",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.Specialty"
+                    }
+                }
+            }
+        },
+        "dto.ListStaffRes": {
+            "type": "object",
+            "properties": {
+                "staff": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.Staff"
+                    }
+                }
+            }
+        },
+        "dto.ListSubscriptionsRes": {
+            "type": "object",
+            "properties": {
+                "subscriptions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.Subscription"
+                    }
+                }
+            }
+        },
+        "dto.ListUsersRes": {
+            "type": "object",
+            "properties": {
+                "Users": {
+                    "description": "List of Users\nexample: [{\"id_Users\":\"12345\",\"id_user\":\"67890\",\"name\":\"Home\",\"city\":\"San Francisco\",\"street\":\"Market Street\",\"lat\":\"37.7749\",\"long\":\"-122.4194\"}]",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.KUser"
+                    }
+                },
+                "pagination": {
+                    "description": "Pagination info",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/paging.Pagination"
+                        }
+                    ]
+                }
+            }
+        },
+        "dto.ListVerificationsRes": {
+            "type": "object",
+            "properties": {
+                "pagination": {
+                    "$ref": "#/definitions/paging.Pagination"
+                },
+                "verifications": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.Verification"
+                    }
+                }
+            }
+        },
+        "dto.ListVitalsRes": {
+            "type": "object",
+            "properties": {
+                "pagination": {
+                    "$ref": "#/definitions/paging.Pagination"
+                },
+                "vitals": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.Vital"
+                    }
+                }
+            }
+        },
+        "dto.LoginReq": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                },
+                "role": {
+                    "$ref": "#/definitions/model.UserRole"
+                }
+            }
+        },
+        "dto.LoginRes": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "type": "string"
+                },
+                "mfa_enrollment_required": {
+                    "description": "MFAEnrollmentRequired is set when the role enforces MFA and the user\nmust enroll with MFAToken first",
+                    "type": "boolean"
+                },
+                "mfa_required": {
+                    "description": "MFARequired is set instead of the tokens when a second factor is\nneeded, MFAToken is then exchanged at /auth/mfa/verify",
+                    "type": "boolean"
+                },
+                "mfa_token": {
+                    "type": "string"
+                },
+                "refresh_token": {
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/dto.User"
+                }
+            }
+        },
+        "dto.MFACodeReq": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "description": "Code from the authenticator app\nexample: \"123456\"",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.MarkAllReadRes": {
+            "type": "object",
+            "properties": {
+                "read": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.Medication": {
+            "type": "object",
+            "properties": {
+                "dosage": {
+                    "type": "string"
+                },
+                "ended_at": {
+                    "type": "string"
+                },
+                "frequency": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "version": {
+                    "description": "Version of the medication, also sent as its ETag",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.Message": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "object"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "sent_at": {
+                    "type": "string"
+                },
+                "type": {
+                    "description": "verification.status, profile.updated, notification.created, broadcast or\nheartbeat",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.MigrationReport": {
+            "type": "object",
+            "properties": {
+                "mapped": {
+                    "type": "integer"
+                },
+                "unmatched": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "dto.ModeratedReview": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "doctor_id": {
+                    "type": "string"
+                },
+                "hidden": {
+                    "type": "boolean"
+                },
+                "hidden_at": {
+                    "type": "string"
+                },
+                "hidden_by": {
+                    "type": "string"
+                },
+                "hidden_reason": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "patient_id": {
+                    "type": "string"
+                },
+                "rating": {
+                    "description": "example: 5",
+                    "type": "integer"
+                },
+                "replied_at": {
+                    "type": "string"
+                },
+                "reply": {
+                    "type": "string"
+                },
+                "text": {
+                    "type": "string"
+                },
+                "version": {
+                    "description": "Version of the review, also sent as its ETag",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.Notification": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "payload": {
+                    "description": "Payload is json, for the client to act on the notification",
+                    "type": "object"
+                },
+                "read_at": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.OAuthURLRes": {
+            "type": "object",
+            "properties": {
+                "authorization_url": {
+                    "description": "URL of the provider consent page",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.PatchAddressReq": {
+            "type": "object",
+            "properties": {
+                "city": {
+                    "type": "string"
                 },
                 "lat": {
-                    "description": "Latitude of the address\nexample: \"37.7749\"",
                     "type": "string"
                 },
                 "long": {
-                    "description": "Longitude of the address\nexample: \"-122.4194\"",
                     "type": "string"
-                },
-                "name": {
-                    "description": "Name of the address\nexample: \"Home\"",
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 200,
+                    "minLength": 1
+                },
+                "street": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.PatchDoctorReq": {
+            "type": "object",
+            "properties": {
+                "experience": {
+                    "type": "integer",
+                    "maximum": 80,
+                    "minimum": 0
+                },
+                "image": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 200,
+                    "minLength": 1
+                },
+                "price": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "specalist": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.PatchUserReq": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 200
+                },
+                "phone_number": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.Preference": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "boolean"
+                },
+                "in_app": {
+                    "type": "boolean"
+                },
+                "required": {
+                    "description": "Required types are sent whatever the preference",
+                    "type": "boolean"
+                },
+                "sms": {
+                    "type": "boolean"
+                },
+                "type": {
+                    "description": "example: \"verification_status\"",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.PrescribedMedication": {
+            "type": "object",
+            "required": [
+                "dosage",
+                "frequency",
+                "name"
+            ],
+            "properties": {
+                "dosage": {
+                    "description": "example: \"1 capsule\"",
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "duration_days": {
+                    "description": "example: 7",
+                    "type": "integer",
+                    "maximum": 365,
+                    "minimum": 1
+                },
+                "frequency": {
+                    "description": "example: \"3 times a day\"",
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "instructions": {
+                    "description": "example: \"With food\"",
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "name": {
+                    "description": "example: \"Amoxicillin 500 mg\"",
+                    "type": "string",
+                    "maxLength": 200
+                }
+            }
+        },
+        "dto.PrescriptionVerification": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "doctor_name": {
+                    "type": "string"
+                },
+                "issued_at": {
+                    "type": "string"
+                },
+                "license_number": {
+                    "type": "string"
+                },
+                "medications": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.PrescribedMedication"
+                    }
+                },
+                "patient_name": {
+                    "type": "string"
+                },
+                "valid": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.RecordVitalReq": {
+            "type": "object",
+            "properties": {
+                "diastolic": {
+                    "description": "example: 80",
+                    "type": "integer"
+                },
+                "heart_rate": {
+                    "description": "example: 70",
+                    "type": "integer"
+                },
+                "height_cm": {
+                    "description": "example: 175",
+                    "type": "number"
+                },
+                "notes": {
+                    "type": "string",
+                    "maxLength": 2000
+                },
+                "oxygen_saturation": {
+                    "description": "example: 98",
+                    "type": "integer",
+                    "maximum": 100
+                },
+                "recorded_at": {
+                    "description": "Now by default",
+                    "type": "string"
+                },
+                "systolic": {
+                    "description": "example: 120",
+                    "type": "integer"
+                },
+                "temperature_c": {
+                    "description": "example: 36.8",
+                    "type": "number"
+                },
+                "weight_kg": {
+                    "description": "example: 72.5",
+                    "type": "number"
+                }
+            }
+        },
+        "dto.RecoveryCodesRes": {
+            "type": "object",
+            "properties": {
+                "recovery_codes": {
+                    "description": "Single use codes, shown only once\nexample: [\"abcde-fghij\"]",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "dto.RefreshTokenReq": {
+            "type": "object",
+            "required": [
+                "refresh_token"
+            ],
+            "properties": {
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.RefreshTokenRes": {
+            "type": "object",
+            "properties": {
+                "access_token": {
                     "type": "string"
                 },
-                "street": {
-                    "description": "Street of the address\nexample: \"Market Street\"",
+                "refresh_token": {
+                    "description": "RefreshToken replaces the one used, which is no longer accepted",
                     "type": "string"
                 }
             }
         },
-        "dto.CreateDoctorReq": {
+        "dto.RegisterReq": {
             "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
             "properties": {
-                "experience": {
-                    "type": "integer"
-                },
-                "id_user": {
-                    "type": "string"
-                },
-                "image": {
+                "email": {
                     "type": "string"
                 },
                 "name": {
                     "type": "string"
                 },
-                "price": {
-                    "type": "number"
+                "password": {
+                    "type": "string"
                 },
-                "specalist": {
+                "phone_number": {
                     "type": "string"
+                },
+                "role": {
+                    "$ref": "#/definitions/model.UserRole"
                 }
             }
         },
-        "dto.DeleteAddressReq": {
+        "dto.RegisterRes": {
             "type": "object",
             "properties": {
-                "id": {
-                    "description": "ID of the address\nexample: \"12345\"",
-                    "type": "string"
-                },
-                "id_user": {
-                    "description": "User ID associated with the address\nexample: \"67890\"",
+                "user": {
+                    "$ref": "#/definitions/dto.User"
+                }
+            }
+        },
+        "dto.RejectVerificationReq": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "reason": {
+                    "description": "example: \"The license number does not match the document\"",
                     "type": "string"
                 }
             }
         },
-        "dto.DeleteDoctorReq": {
+        "dto.ReplyReviewReq": {
             "type": "object",
+            "required": [
+                "reply"
+            ],
             "properties": {
-                "id": {
-                    "description": "ID of the Doctor\nexample: \"12345\"",
+                "reply": {
+                    "type": "string",
+                    "maxLength": 2000
+                }
+            }
+        },
+        "dto.RequestErasureReq": {
+            "type": "object",
+            "properties": {
+                "password": {
+                    "description": "Required when the account has a password",
                     "type": "string"
-                },
-                "id_user": {
-                    "description": "User ID associated with the Doctor\nexample: \"67890\"",
+                }
+            }
+        },
+        "dto.ResendVerifyEmailRequest": {
+            "type": "object",
+            "properties": {
+                "email": {
                     "type": "string"
                 }
             }
         },
-        "dto.DeleteUserReq": {
+        "dto.ResendVerifyPhoneNumberRequest": {
             "type": "object",
             "properties": {
-                "id": {
-                    "description": "ID of the address\nexample: \"12345\"",
+                "phone_number": {
                     "type": "string"
                 }
             }
         },
-        "dto.Doctor": {
+        "dto.Review": {
             "type": "object",
             "properties": {
-                "experience": {
-                    "type": "integer"
+                "created_at": {
+                    "type": "string"
                 },
-                "id_Doctor": {
+                "doctor_id": {
                     "type": "string"
                 },
-                "id_user": {
+                "id": {
                     "type": "string"
                 },
-                "image": {
+                "rating": {
+                    "description": "example: 5",
+                    "type": "integer"
+                },
+                "replied_at": {
                     "type": "string"
                 },
-                "name": {
+                "reply": {
                     "type": "string"
                 },
-                "price": {
-                    "type": "number"
+                "text": {
+                    "type": "string"
                 },
-                "specalist": {
+                "version": {
+                    "description": "Version of the review, also sent as its ETag",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.RotateSecretRes": {
+            "type": "object",
+            "properties": {
+                "secret": {
                     "type": "string"
                 }
             }
         },
-        "dto.KLoginReq": {
+        "dto.SaveAllergyReq": {
             "type": "object",
             "required": [
-                "email",
-                "password"
+                "substance"
             ],
             "properties": {
-                "email": {
-                    "type": "string"
+                "reaction": {
+                    "description": "example: \"Hives\"",
+                    "type": "string",
+                    "maxLength": 500
                 },
-                "password": {
-                    "type": "string"
+                "severity": {
+                    "description": "example: \"severe\"",
+                    "type": "string",
+                    "enum": [
+                        "mild",
+                        "moderate",
+                        "severe"
+                    ]
+                },
+                "substance": {
+                    "description": "example: \"Penicillin\"",
+                    "type": "string",
+                    "maxLength": 200
                 }
             }
         },
-        "dto.KRegisterReq": {
+        "dto.SaveConditionReq": {
             "type": "object",
             "required": [
-                "email",
-                "password"
+                "name"
             ],
             "properties": {
-                "email": {
+                "diagnosed_at": {
                     "type": "string"
                 },
                 "name": {
-                    "type": "string"
+                    "description": "example: \"Type 2 diabetes\"",
+                    "type": "string",
+                    "maxLength": 200
                 },
-                "password": {
-                    "type": "string"
+                "notes": {
+                    "type": "string",
+                    "maxLength": 2000
                 },
-                "phone_number": {
-                    "type": "string"
+                "status": {
+                    "description": "Active by default\nexample: \"active\"",
+                    "type": "string",
+                    "enum": [
+                        "active",
+                        "resolved"
+                    ]
                 }
             }
         },
-        "dto.KUpdateUserReq": {
+        "dto.SaveMedicationReq": {
             "type": "object",
             "required": [
-                "email",
-                "new_password",
-                "password"
+                "name"
             ],
             "properties": {
-                "email": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
+                "dosage": {
+                    "description": "example: \"500 mg\"",
+                    "type": "string",
+                    "maxLength": 100
                 },
-                "name": {
+                "ended_at": {
                     "type": "string"
                 },
-                "new_password": {
-                    "type": "string"
+                "frequency": {
+                    "description": "example: \"twice a day\"",
+                    "type": "string",
+                    "maxLength": 100
                 },
-                "password": {
-                    "type": "string"
+                "name": {
+                    "description": "example: \"Metformin\"",
+                    "type": "string",
+                    "maxLength": 200
                 },
-                "phone_number": {
+                "started_at": {
                     "type": "string"
                 }
             }
         },
-        "dto.KUser": {
+        "dto.Session": {
             "type": "object",
             "properties": {
-                "approve_email": {
-                    "type": "boolean"
-                },
-                "approve_phone_number": {
-                    "type": "boolean"
-                },
                 "created_at": {
                     "type": "string"
                 },
-                "deleted_at": {
+                "current": {
+                    "description": "Current is set on the session of the request",
+                    "type": "boolean"
+                },
+                "device": {
+                    "description": "example: \"Chrome on Windows\"",
                     "type": "string"
                 },
-                "email": {
+                "expires_at": {
                     "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
-                "name": {
+                "ip": {
                     "type": "string"
                 },
-                "password": {
+                "last_seen_at": {
                     "type": "string"
                 },
-                "phone_number": {
+                "user_agent": {
                     "type": "string"
-                },
-                "role": {
-                    "$ref": "#/definitions/model.UserRole"
-                },
-                "updated_at": {
+                }
+            }
+        },
+        "dto.SetAvatarReq": {
+            "type": "object",
+            "required": [
+                "file_id"
+            ],
+            "properties": {
+                "file_id": {
+                    "description": "ID of the uploaded image",
                     "type": "string"
-                },
-                "verify_code_email": {
-                    "type": "integer"
-                },
-                "verify_code_phone_number": {
-                    "type": "integer"
                 }
             }
         },
-        "dto.ListAddressRes": {
+        "dto.SetBloodTypeReq": {
             "type": "object",
             "properties": {
-                "addresses": {
-                    "description": "List of addresses\nexample: [{\"id_address\":\"12345\",\"id_user\":\"67890\",\"name\":\"Home\",\"city\":\"San Francisco\",\"street\":\"Market Street\",\"lat\":\"37.7749\",\"long\":\"-122.4194\"}]",
+                "blood_type": {
+                    "description": "Empty when unknown\nexample: \"O+\"",
+                    "type": "string",
+                    "enum": [
+                        "A+",
+                        "A-",
+                        "B+",
+                        "B-",
+                        "AB+",
+                        "AB-",
+                        "O+",
+                        "O-"
+                    ]
+                }
+            }
+        },
+        "dto.SetDoctorSpecialtiesReq": {
+            "type": "object",
+            "required": [
+                "specialties"
+            ],
+            "properties": {
+                "specialties": {
                     "type": "array",
+                    "maxItems": 10,
+                    "minItems": 1,
                     "items": {
-                        "$ref": "#/definitions/dto.Address"
+                        "$ref": "#/definitions/internal_specialty_dto.DoctorSpecialty"
                     }
-                },
-                "pagination": {
-                    "description": "Pagination info",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/paging.Pagination"
-                        }
-                    ]
                 }
             }
         },
-        "dto.ListDoctorRes": {
+        "dto.SetImageReq": {
             "type": "object",
+            "required": [
+                "file_id"
+            ],
             "properties": {
-                "Doctors": {
-                    "description": "List of Doctors\nexample: [{\"id_Doctor\":\"12345\",\"id_user\":\"67890\",\"name\":\"Home\",\"city\":\"San Francisco\",\"street\":\"Market Street\",\"lat\":\"37.7749\",\"long\":\"-122.4194\"}]",
+                "file_id": {
+                    "description": "ID of the uploaded image",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.Specialty": {
+            "type": "object",
+            "properties": {
+                "aliases": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/dto.Doctor"
+                        "type": "string"
                     }
                 },
-                "pagination": {
-                    "description": "Pagination info",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/paging.Pagination"
-                        }
-                    ]
+                "children": {
+                    "description": "Specialties refining this one",
+                    "type": "array",
+                    "items": {
+                        "type": "object"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "icon": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Name in the requested locale\nexample: \"Cardiology\"",
+                    "type": "string"
+                },
+                "names": {
+                    "description": "example: {\"en\":\"Cardiology\",\"fr\":\"Cardiologie\"}",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "parent_id": {
+                    "type": "string"
+                },
+                "slug": {
+                    "description": "example: \"cardiology\"",
+                    "type": "string"
+                },
+                "version": {
+                    "description": "Version of the specialty, also sent as its ETag",
+                    "type": "integer"
                 }
             }
         },
-        "dto.ListUsersRes": {
+        "dto.SpecialtySummary": {
             "type": "object",
             "properties": {
-                "Users": {
-                    "description": "List of Users\nexample: [{\"id_Users\":\"12345\",\"id_user\":\"67890\",\"name\":\"Home\",\"city\":\"San Francisco\",\"street\":\"Market Street\",\"lat\":\"37.7749\",\"long\":\"-122.4194\"}]",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/dto.KUser"
+                "icon": {
+                    "type": "string"
+                },
+                "names": {
+                    "description": "Names by locale\nexample: {\"en\":\"Cardiology\"}",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
                     }
                 },
-                "pagination": {
-                    "description": "Pagination info",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/paging.Pagination"
-                        }
-                    ]
+                "slug": {
+                    "description": "example: \"cardiology\"",
+                    "type": "string"
                 }
             }
         },
-        "dto.LoginReq": {
+        "dto.Staff": {
             "type": "object",
-            "required": [
-                "email",
-                "password"
-            ],
             "properties": {
                 "email": {
                     "type": "string"
                 },
-                "password": {
+                "id": {
+                    "type": "string"
+                },
+                "name": {
                     "type": "string"
                 },
                 "role": {
@@ -1362,84 +8022,83 @@ const docTemplate = `{
                 }
             }
         },
-        "dto.LoginRes": {
+        "dto.SubmitVerificationReq": {
             "type": "object",
+            "required": [
+                "documents",
+                "issuing_authority",
+                "license_expires_at",
+                "license_number"
+            ],
             "properties": {
-                "access_token": {
+                "documents": {
+                    "description": "Urls of the uploaded license documents\nexample: [\"https://files.example.com/license.pdf\"]",
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "issuing_authority": {
+                    "description": "example: \"Medical Council\"",
                     "type": "string"
                 },
-                "refresh_token": {
+                "license_expires_at": {
                     "type": "string"
                 },
-                "user": {
-                    "$ref": "#/definitions/dto.User"
-                }
-            }
-        },
-        "dto.RefreshTokenReq": {
-            "type": "object",
-            "required": [
-                "refresh_token"
-            ],
-            "properties": {
-                "refresh_token": {
+                "license_number": {
+                    "description": "example: \"MD-123456\"",
                     "type": "string"
                 }
             }
         },
-        "dto.RefreshTokenRes": {
+        "dto.Subscription": {
             "type": "object",
             "properties": {
-                "access_token": {
+                "active": {
+                    "type": "boolean"
+                },
+                "clinic_id": {
                     "type": "string"
-                }
-            }
-        },
-        "dto.RegisterReq": {
-            "type": "object",
-            "required": [
-                "email",
-                "password"
-            ],
-            "properties": {
-                "email": {
+                },
+                "created_at": {
                     "type": "string"
                 },
-                "name": {
+                "created_by": {
                     "type": "string"
                 },
-                "password": {
+                "description": {
                     "type": "string"
                 },
-                "phone_number": {
+                "disabled_at": {
+                    "description": "DisabledAt is set when the subscription was disabled, by an admin or\nafter too many failed deliveries",
                     "type": "string"
                 },
-                "role": {
-                    "$ref": "#/definitions/model.UserRole"
-                }
-            }
-        },
-        "dto.RegisterRes": {
-            "type": "object",
-            "properties": {
-                "user": {
-                    "$ref": "#/definitions/dto.User"
-                }
-            }
-        },
-        "dto.ResendVerifyEmailRequest": {
-            "type": "object",
-            "properties": {
-                "email": {
+                "disabled_reason": {
+                    "type": "string"
+                },
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "failures": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "url": {
                     "type": "string"
                 }
             }
         },
-        "dto.ResendVerifyPhoneNumberRequest": {
+        "dto.UnreadCountRes": {
             "type": "object",
             "properties": {
-                "phone_number": {
-                    "type": "string"
+                "unread": {
+                    "type": "integer"
                 }
             }
         },
@@ -1476,6 +8135,67 @@ const docTemplate = `{
                 }
             }
         },
+        "dto.UpdateClinicReq": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 200
+                },
+                "phone": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.UpdateConsultationReq": {
+            "type": "object",
+            "required": [
+                "diagnoses",
+                "medications"
+            ],
+            "properties": {
+                "assessment": {
+                    "type": "string",
+                    "maxLength": 10000
+                },
+                "diagnoses": {
+                    "type": "array",
+                    "maxItems": 20,
+                    "items": {
+                        "$ref": "#/definitions/dto.Diagnosis"
+                    }
+                },
+                "medications": {
+                    "type": "array",
+                    "maxItems": 30,
+                    "items": {
+                        "$ref": "#/definitions/dto.PrescribedMedication"
+                    }
+                },
+                "objective": {
+                    "type": "string",
+                    "maxLength": 10000
+                },
+                "plan": {
+                    "type": "string",
+                    "maxLength": 10000
+                },
+                "subjective": {
+                    "type": "string",
+                    "maxLength": 10000
+                },
+                "visited_at": {
+                    "description": "The creation time by default",
+                    "type": "string"
+                }
+            }
+        },
         "dto.UpdateDoctorReq": {
             "type": "object",
             "properties": {
@@ -1502,6 +8222,83 @@ const docTemplate = `{
                 }
             }
         },
+        "dto.UpdatePreferenceReq": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "boolean"
+                },
+                "in_app": {
+                    "type": "boolean"
+                },
+                "sms": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.UpdateSpecialtyReq": {
+            "type": "object",
+            "required": [
+                "aliases",
+                "names",
+                "slug"
+            ],
+            "properties": {
+                "aliases": {
+                    "description": "Other spellings of the specialty found in free-text values\nexample: [\"pediatric cardiologist\"]",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "icon": {
+                    "description": "Url of the icon",
+                    "type": "string"
+                },
+                "names": {
+                    "description": "Names by locale, en is required\nexample: {\"en\":\"Pediatric cardiology\",\"fr\":\"Cardiologie pédiatrique\"}",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "parent_id": {
+                    "type": "string"
+                },
+                "slug": {
+                    "description": "Lower case words separated by dashes\nexample: \"pediatric-cardiology\"",
+                    "type": "string",
+                    "maxLength": 64
+                }
+            }
+        },
+        "dto.UpdateSubscriptionReq": {
+            "type": "object",
+            "required": [
+                "events",
+                "url"
+            ],
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "events": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "url": {
+                    "type": "string",
+                    "maxLength": 2000
+                }
+            }
+        },
         "dto.UpdateUserReq": {
             "type": "object",
             "required": [
@@ -1544,6 +8341,17 @@ const docTemplate = `{
         "dto.User": {
             "type": "object",
             "properties": {
+                "avatar": {
+                    "description": "Large jpeg variant of the picture",
+                    "type": "string"
+                },
+                "avatar_variants": {
+                    "description": "Thumbnails of the picture by size and format\nexample: {\"small.webp\":\"http://localhost:8888/api/v1/images/12345/small.webp\"}",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
                 "created_at": {
                     "type": "string"
                 },
@@ -1553,8 +8361,57 @@ const docTemplate = `{
                 "id": {
                     "type": "string"
                 },
+                "mfa_enabled": {
+                    "type": "boolean"
+                },
                 "updated_at": {
                     "type": "string"
+                },
+                "version": {
+                    "description": "Version of the user, also sent as its ETag",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.Verification": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "doctor_id": {
+                    "type": "string"
+                },
+                "documents": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "issuing_authority": {
+                    "type": "string"
+                },
+                "license_expires_at": {
+                    "type": "string"
+                },
+                "license_number": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "reviewed_at": {
+                    "type": "string"
+                },
+                "reviewed_by": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "example: \"pending\"",
+                    "type": "string"
                 }
             }
         },
@@ -1569,6 +8426,23 @@ const docTemplate = `{
                 }
             }
         },
+        "dto.VerifyMFAReq": {
+            "type": "object",
+            "required": [
+                "code",
+                "mfa_token"
+            ],
+            "properties": {
+                "code": {
+                    "description": "Code from the authenticator app or a recovery code\nexample: \"123456\"",
+                    "type": "string"
+                },
+                "mfa_token": {
+                    "description": "Challenge token returned by login",
+                    "type": "string"
+                }
+            }
+        },
         "dto.VerifyPhoneNumberRequest": {
             "type": "object",
             "properties": {
@@ -1588,12 +8462,79 @@ const docTemplate = `{
                 }
             }
         },
+        "dto.Vital": {
+            "type": "object",
+            "properties": {
+                "diastolic": {
+                    "type": "integer"
+                },
+                "heart_rate": {
+                    "type": "integer"
+                },
+                "height_cm": {
+                    "type": "number"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "oxygen_saturation": {
+                    "type": "integer"
+                },
+                "recorded_at": {
+                    "type": "string"
+                },
+                "recorded_by": {
+                    "type": "string"
+                },
+                "systolic": {
+                    "type": "integer"
+                },
+                "temperature_c": {
+                    "type": "number"
+                },
+                "weight_kg": {
+                    "type": "number"
+                }
+            }
+        },
+        "internal_doctor_dto.DoctorSpecialty": {
+            "type": "object",
+            "properties": {
+                "primary": {
+                    "type": "boolean"
+                },
+                "specialty": {
+                    "$ref": "#/definitions/dto.SpecialtySummary"
+                },
+                "specialty_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_specialty_dto.DoctorSpecialty": {
+            "type": "object",
+            "required": [
+                "specialty_id"
+            ],
+            "properties": {
+                "primary": {
+                    "type": "boolean"
+                },
+                "specialty_id": {
+                    "type": "string"
+                }
+            }
+        },
         "model.UserRole": {
             "type": "string",
             "enum": [
                 "admin",
                 "doctor",
-                "client"
+                "client",
+                "clinic_admin"
             ],
             "x-enum-comments": {
                 "UserRoleAdmin": "Administrator role",
@@ -1603,7 +8544,8 @@ const docTemplate = `{
             "x-enum-varnames": [
                 "UserRoleAdmin",
                 "UserRoleDoctor",
-                "UserRoleClient"
+                "UserRoleClient",
+                "UserRoleClinicAdmin"
             ]
         },
         "paging.Pagination": {
@@ -1625,6 +8567,13 @@ const docTemplate = `{
                     "type": "integer"
                 }
             }
+        },
+        "response.Response": {
+            "type": "object",
+            "properties": {
+                "error": {},
+                "result": {}
+            }
         }
     },
     "securityDefinitions": {
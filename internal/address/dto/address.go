@@ -1,6 +1,8 @@
 package dto
 
 import (
+	"time"
+
 	"main/pkg/paging"
 )
 
@@ -30,6 +32,10 @@ type Address struct {
 	// Longitude of the address
 	// example: "-122.4194"
 	Long string `json:"long"`
+	// Set on the deleted addresses listed by admins
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	// Version of the address, also sent as its ETag
+	Version int64 `json:"version"`
 }
 
 // ***************************************************************************\\
@@ -83,6 +89,21 @@ type UpdateAddressReq struct {
 	// Longitude of the address
 	// example: "-122.4194"
 	Long string `json:"long"`
+	// Version the change applies to, from If-Match over HTTP
+	Version int64 `json:"-"`
+}
+
+// ***************************************************************************\\
+// ***************************************************************************\\
+// PatchAddressReq holds the fields changed by a patch of an address, the
+// others are nil and kept.
+// swagger:model PatchAddressReq
+type PatchAddressReq struct {
+	Name   *string `json:"name,omitempty" validate:"omitnil,min=1,max=200"`
+	City   *string `json:"city,omitempty"`
+	Street *string `json:"street,omitempty"`
+	Lat    *string `json:"lat,omitempty" validate:"omitnil,latitude"`
+	Long   *string `json:"long,omitempty" validate:"omitnil,longitude"`
 }
 
 // ***************************************************************************\\
@@ -102,6 +123,8 @@ type ListAddressReq struct {
 	// Limit number of items per page
 	// example: 10
 	Limit int64 `json:"-" form:"limit"`
+	// Also lists the deleted addresses, for admins
+	IncludeDeleted bool `json:"include_deleted,omitempty" form:"include_deleted"`
 }
 
 // ListAddressRes represents the response body for listing addresses.
@@ -125,6 +148,8 @@ type DeleteAddressReq struct {
 	// User ID associated with the address
 	// example: "67890"
 	IDUser string `json:"id_user"`
+	// Version the change applies to, from If-Match over HTTP
+	Version int64 `json:"-"`
 }
 
 //***************************************************************************\\
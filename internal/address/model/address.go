@@ -18,6 +18,12 @@ type Address struct {
 	Long      string    `json:"long"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+	// DeletedAt is set on soft-deleted addresses, purged after the retention
+	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"index"`
+	// Version is bumped by every change, it is the ETag of the address
+	Version int64 `json:"version" gorm:"not null;default:1"`
+	// TenantID is the clinic of the user owning the address
+	TenantID string `json:"tenant_id" gorm:"not null;default:'';index"`
 }
 
 func (m *Address) BeforeCreate(tx *gorm.DB) error {
@@ -2,14 +2,21 @@ package grpc
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/quangdangfit/gocommon/logger"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"main/internal/address/dto"
+	"main/internal/address/model"
 	"main/internal/address/service"
-	"main/pkg/config"
+	"main/pkg/dbs"
+	"main/pkg/patch"
 	"main/pkg/redis"
+	"main/pkg/tenant"
 	"main/pkg/utils"
 	pb "main/proto/gen/go/address"
 )
@@ -37,32 +44,15 @@ func NewAddressHandler(
 //	}
 
 func (h *AddressHandler) GetAddressByID(ctx context.Context, req *pb.GetAddressByIDRequest) (*pb.AddressResponse, error) {
-	var res dto.Address
-	cacheKey := "address_" + req.Id
-	err := h.cache.Get(cacheKey, &res)
-	if err == nil {
-		return &pb.AddressResponse{Address: &pb.Address{
-			IdAddress: res.ID,
-			IdUser:    res.IDUser,
-			Name:      res.Name,
-			City:      res.City,
-			Street:    res.Street,
-			Lat:       res.Lat,
-			Long:      res.Long,
-
-			// CreatedAt: formatTimeToString(res.CreatedAt),
-			// UpdatedAt: formatTimeToString(res.UpdatedAt),
-		}}, nil
-	}
-
+	// never cached, its version is the one UpdateAddress is checked against
 	address, err := h.service.GetAddressByID(ctx, req.Id)
 	if err != nil {
 		logger.Error("Failed to get address detail: ", err)
 		return nil, err
 	}
 
+	var res dto.Address
 	utils.Copy(&res, &address)
-	_ = h.cache.SetWithExpiration(cacheKey, res, config.AddressCachingTime.Abs())
 	return &pb.AddressResponse{Address: &pb.Address{
 		IdAddress: res.ID,
 		IdUser:    res.IDUser,
@@ -71,13 +61,14 @@ func (h *AddressHandler) GetAddressByID(ctx context.Context, req *pb.GetAddressB
 		Street:    res.Street,
 		Lat:       res.Lat,
 		Long:      res.Long,
+		Version:   res.Version,
 	}}, nil
 }
 
 func (h *AddressHandler) ListAddresses(ctx context.Context, req *pb.ListAddressesRequest) (*pb.ListAddressesResponse, error) {
 	var res dto.ListAddressRes
-	cacheKey := "addresses_list"
-	err := h.cache.Get(cacheKey, &res)
+	cacheKey := tenant.CacheKey(ctx, "addresses_list")
+	err := h.cache.Get(ctx, cacheKey, &res)
 	if err == nil {
 		var pbAddresses []*pb.Address
 		for _, addr := range res.Addresses {
@@ -89,6 +80,7 @@ func (h *AddressHandler) ListAddresses(ctx context.Context, req *pb.ListAddresse
 				Street:    addr.Street,
 				Lat:       addr.Lat,
 				Long:      addr.Long,
+				Version:   addr.Version,
 			})
 		}
 		return &pb.ListAddressesResponse{Addresses: pbAddresses}, nil
@@ -102,7 +94,7 @@ func (h *AddressHandler) ListAddresses(ctx context.Context, req *pb.ListAddresse
 
 	utils.Copy(&res.Addresses, &addresses)
 	res.Pagination = pagination
-	_ = h.cache.SetWithExpiration(cacheKey, res, time.Hour) // Adjust caching time as needed
+	_ = h.cache.SetWithExpiration(ctx, cacheKey, res, time.Hour) // Adjust caching time as needed
 
 	var pbAddresses []*pb.Address
 	for _, addr := range addresses {
@@ -114,6 +106,7 @@ func (h *AddressHandler) ListAddresses(ctx context.Context, req *pb.ListAddresse
 			Street:    addr.Street,
 			Lat:       addr.Lat,
 			Long:      addr.Long,
+			Version:   addr.Version,
 		})
 	}
 	return &pb.ListAddressesResponse{Addresses: pbAddresses}, nil
@@ -136,7 +129,6 @@ func (h *AddressHandler) CreateAddress(ctx context.Context, req *pb.CreateAddres
 
 	var res dto.Address
 	utils.Copy(&res, &address)
-	_ = h.cache.RemovePattern("*address*")
 	return &pb.AddressResponse{Address: &pb.Address{
 		IdAddress: res.ID,
 		IdUser:    res.IDUser,
@@ -145,26 +137,37 @@ func (h *AddressHandler) CreateAddress(ctx context.Context, req *pb.CreateAddres
 		Street:    res.Street,
 		Lat:       res.Lat,
 		Long:      res.Long,
+		Version:   res.Version,
 	}}, nil
 }
 
 func (h *AddressHandler) UpdateAddress(ctx context.Context, req *pb.UpdateAddressRequest) (*pb.AddressResponse, error) {
-	var addressDTO dto.UpdateAddressReq
-	addressDTO.Name = req.Request.Name
-	addressDTO.City = req.Request.City
-	addressDTO.Street = req.Request.Street
-	addressDTO.Lat = req.Request.Lat
-	addressDTO.Long = req.Request.Long
-
-	address, err := h.service.Update(ctx, req.Id, &addressDTO)
+	var address *model.Address
+	var err error
+	if paths := req.GetUpdateMask().GetPaths(); len(paths) > 0 {
+		var changes patch.Patch
+		if changes, err = addressPatch(req.GetRequest(), paths); err == nil {
+			address, err = h.service.Patch(ctx, req.Id, req.GetRequest().GetVersion(), changes)
+		}
+	} else {
+		var addressDTO dto.UpdateAddressReq
+		addressDTO.ID = req.Id
+		addressDTO.IDUser = req.Request.IdUser
+		addressDTO.Name = req.Request.Name
+		addressDTO.City = req.Request.City
+		addressDTO.Street = req.Request.Street
+		addressDTO.Lat = req.Request.Lat
+		addressDTO.Long = req.Request.Long
+		addressDTO.Version = req.Request.Version
+		address, err = h.service.Update(ctx, req.Id, &addressDTO)
+	}
 	if err != nil {
 		logger.Error("Failed to update address: ", err)
-		return nil, err
+		return nil, versionError(err)
 	}
 
 	var res dto.Address
 	utils.Copy(&res, &address)
-	_ = h.cache.RemovePattern("*address*")
 	return &pb.AddressResponse{Address: &pb.Address{
 		IdAddress: res.ID,
 		IdUser:    res.IDUser,
@@ -173,21 +176,55 @@ func (h *AddressHandler) UpdateAddress(ctx context.Context, req *pb.UpdateAddres
 		Street:    res.Street,
 		Lat:       res.Lat,
 		Long:      res.Long,
+		Version:   res.Version,
 	}}, nil
 }
 
 func (h *AddressHandler) DeleteAddress(ctx context.Context, req *pb.DeleteAddressRequest) (*pb.AddressResponse, error) {
-	address, err := h.service.Delete(ctx, req.Id, &dto.DeleteAddressReq{})
+	address, err := h.service.Delete(ctx, req.Id, &dto.DeleteAddressReq{Version: req.Request.GetVersion()})
 	if err != nil {
 		logger.Error("Failed to delete address: ", err)
-		return nil, err
+		return nil, versionError(err)
 	}
 
 	var res dto.Address
 	utils.Copy(&res, &address)
-	_ = h.cache.RemovePattern("*address*")
 	return &pb.AddressResponse{Address: &pb.Address{
 		IdAddress: req.Request.Id,
 		IdUser:    req.Request.IdUser,
 	}}, nil
 }
+
+// addressPatch is the merge patch of the fields of req in the update mask
+func addressPatch(req *pb.UpdateAddressReq, paths []string) (patch.Patch, error) {
+	fields := make(map[string]interface{}, len(paths))
+	for _, path := range paths {
+		switch path {
+		case "name":
+			fields["name"] = req.GetName()
+		case "city":
+			fields["city"] = req.GetCity()
+		case "street":
+			fields["street"] = req.GetStreet()
+		case "lat":
+			fields["lat"] = req.GetLat()
+		case "long":
+			fields["long"] = req.GetLong()
+		default:
+			return nil, fmt.Errorf("%w: %q cannot be in update_mask", patch.ErrInvalidPatch, path)
+		}
+	}
+	return patch.Merge(fields), nil
+}
+
+// versionError tells the clients to read the address again when it changed
+// since their version, and to fix invalid update masks
+func versionError(err error) error {
+	if errors.Is(err, dbs.ErrStaleVersion) {
+		return status.New(codes.Aborted, err.Error()).Err()
+	}
+	if patch.StatusCode(err) != 0 {
+		return status.New(codes.InvalidArgument, err.Error()).Err()
+	}
+	return err
+}
@@ -6,14 +6,16 @@ import (
 
 	"main/internal/address/repository"
 	"main/internal/address/service"
+	"main/pkg/audit"
 	"main/pkg/dbs"
+	"main/pkg/events"
 	"main/pkg/redis"
 	pb "main/proto/gen/go/address"
 )
 
-func RegisterHandlers(svr *grpc.Server, db dbs.IDatabase, validator validation.Validation, cache redis.IRedis) {
+func RegisterHandlers(svr *grpc.Server, db dbs.IDatabase, validator validation.Validation, cache redis.IRedis, recorder audit.Recorder, publisher events.Publisher) {
 	AddressRepo := repository.NewAddressRepository(db)
-	AddressSvc := service.NewAddressService(validator, AddressRepo)
+	AddressSvc := service.NewAddressService(validator, AddressRepo, recorder, publisher)
 	AddressHandler := NewAddressHandler(cache, AddressSvc)
 
 	pb.RegisterAddressServiceServer(svr, AddressHandler)
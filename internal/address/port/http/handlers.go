@@ -1,6 +1,7 @@
 package http
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -10,8 +11,12 @@ import (
 	"main/internal/address/dto"
 	"main/internal/address/service"
 	"main/pkg/config"
+	"main/pkg/dbs"
+	"main/pkg/patch"
+	"main/pkg/rbac"
 	"main/pkg/redis"
 	"main/pkg/response"
+	"main/pkg/tenant"
 	"main/pkg/utils"
 )
 
@@ -37,11 +42,15 @@ func NewAddressHandler(
 //	@Summary	Get Address by id
 //	@Tags		Address
 //	@Produce	json
-//	@Param		id	path	string	true	"Address ID"
+//	@Param		id				path	string	true	"Address ID"
+//	@Param		If-None-Match	header	string	false	"ETag of the address already read"
 //	@Success	200	{object}	dto.Address
+//	@Success	304	"Not changed since If-None-Match"
 //	@Router		/address/{id} [get]
 func (p *AddressHandler) GetAddressByID(c *gin.Context) {
 
+	// never cached, the cache is cleared after the changes and its ETag
+	// could be older than the version the next If-Match is checked against
 	AddressId := c.Param("id")
 	Address, err := p.service.GetAddressByID(c, AddressId)
 	if err != nil {
@@ -51,15 +60,8 @@ func (p *AddressHandler) GetAddressByID(c *gin.Context) {
 	}
 
 	var res dto.Address
-	cacheKey := c.Request.URL.RequestURI()
-	err2 := p.cache.Get(cacheKey, &res)
-	if err2 == nil {
-		response.JSON(c, http.StatusOK, res)
-		return
-	}
 	utils.Copy(&res, &Address)
-	response.JSON(c, http.StatusOK, res)
-	_ = p.cache.SetWithExpiration(cacheKey, res, config.AddressCachingTime.Abs())
+	response.Versioned(c, http.StatusOK, res.Version, res)
 }
 
 // ListAddress godoc
@@ -71,6 +73,7 @@ func (p *AddressHandler) GetAddressByID(c *gin.Context) {
 //	@Param		name	path	string					true	"name"
 //	@Param		page	path	string					true	"page"
 //	@Param		limit	path	string					true	"limit"
+//	@Param		include_deleted	query	bool	false	"Also list the deleted addresses, for admins"
 //
 // @Success	200	{object}	dto.ListAddressRes
 // @Router		/address  [get]
@@ -105,9 +108,14 @@ func (p *AddressHandler) ListAddresses(c *gin.Context) {
 		return
 	}
 
+	if req.IncludeDeleted && !rbac.Has(c.GetStringSlice("permissions"), rbac.DeletedManage) {
+		response.Error(c, http.StatusForbidden, errors.New("forbidden"), "Forbidden")
+		return
+	}
+
 	var res dto.ListAddressRes
-	cacheKey := c.Request.URL.RequestURI()
-	err := p.cache.Get(cacheKey, &res)
+	cacheKey := tenant.CacheKey(c, c.Request.URL.RequestURI())
+	err := p.cache.Get(c, cacheKey, &res)
 	if err == nil {
 		response.JSON(c, http.StatusOK, res)
 		return
@@ -123,7 +131,7 @@ func (p *AddressHandler) ListAddresses(c *gin.Context) {
 	utils.Copy(&res.Addresses, &Addresses)
 	res.Pagination = pagination
 	response.JSON(c, http.StatusOK, res)
-	_ = p.cache.SetWithExpiration(cacheKey, res, config.AddressCachingTime)
+	_ = p.cache.SetWithExpiration(c, cacheKey, res, config.AddressCachingTime)
 }
 
 // CreateAddress godoc
@@ -132,8 +140,11 @@ func (p *AddressHandler) ListAddresses(c *gin.Context) {
 //	@Tags		Address
 //	@Produce	json
 //	@Security	ApiKeyAuth
+//	@Param		Idempotency-Key	header	string	false	"Key of the request and its retries, which get the first response"
 //	@Param		_	body	dto.CreateAddressReq	true	"Body"
 //	@Success	200	{object}	dto.Address
+//	@Failure	409	{object}	response.Response	"The first request with the key is running"
+//	@Failure	422	{object}	response.Response	"The key was used with another body"
 //	@Router		/address [post]
 func (p *AddressHandler) CreateAddress(c *gin.Context) {
 	var req dto.CreateAddressReq
@@ -153,7 +164,6 @@ func (p *AddressHandler) CreateAddress(c *gin.Context) {
 	var res dto.Address
 	utils.Copy(&res, &Address)
 	response.JSON(c, http.StatusOK, res)
-	_ = p.cache.RemovePattern("*Address*")
 }
 
 // UpdateAddress godoc
@@ -162,12 +172,19 @@ func (p *AddressHandler) CreateAddress(c *gin.Context) {
 //	@Tags		Address
 //	@Produce	json
 //	@Security	ApiKeyAuth
+//	@Param		If-Match	header	string	true	"ETag of the address, its version"
 //	@Param		_	body	dto.UpdateAddressReq	true	"Body"
 //	@Success	200	{object}	dto.Address
+//	@Failure	412	{object}	response.Response	"Changed since it was read"
 //	@Router		/address/{id} [put]
 func (p *AddressHandler) UpdateAddress(c *gin.Context) {
 	//	@Param		id	path	string					true	"Address ID"
 	// AddressId := c.Param("id")
+	version, ok := response.IfMatch(c)
+	if !ok {
+		return
+	}
+
 	var req dto.UpdateAddressReq
 	if err := c.ShouldBindJSON(&req); c.Request.Body == nil || err != nil {
 		logger.Error("Failed to get body", err)
@@ -175,7 +192,12 @@ func (p *AddressHandler) UpdateAddress(c *gin.Context) {
 		return
 	}
 
+	req.Version = version
 	Address, err := p.service.Update(c, req.ID, &req)
+	if errors.Is(err, dbs.ErrStaleVersion) {
+		response.StaleVersion(c, err)
+		return
+	}
 	if err != nil {
 		logger.Error("Failed to Update Address", err.Error())
 		response.Error(c, http.StatusInternalServerError, err, "Something went wrong")
@@ -184,8 +206,56 @@ func (p *AddressHandler) UpdateAddress(c *gin.Context) {
 
 	var res dto.Address
 	utils.Copy(&res, &Address)
-	response.JSON(c, http.StatusOK, res)
-	_ = p.cache.RemovePattern("*Address*")
+	response.Versioned(c, http.StatusOK, res.Version, res)
+}
+
+// PatchAddress godoc
+//
+//	@Summary	Change some fields of an Address
+//	@Description	JSON Merge Patch (application/merge-patch+json, also for application/json) or JSON Patch (application/json-patch+json) of the fields of UpdateAddressReq, the other fields are kept
+//	@Tags		Address
+//	@Accept		json
+//	@Produce	json
+//	@Security	ApiKeyAuth
+//	@Param		id			path	string	true	"Address ID"
+//	@Param		If-Match	header	string	true	"ETag of the address, its version"
+//	@Param		_	body	dto.PatchAddressReq	true	"Body"
+//	@Success	200	{object}	dto.Address
+//	@Failure	409	{object}	response.Response	"A test operation failed"
+//	@Failure	412	{object}	response.Response	"Changed since it was read"
+//	@Failure	415	{object}	response.Response	"Not a patch media type"
+//	@Failure	422	{object}	response.Response	"Invalid or read-only fields"
+//	@Router		/address/{id} [patch]
+func (p *AddressHandler) PatchAddress(c *gin.Context) {
+	version, ok := response.IfMatch(c)
+	if !ok {
+		return
+	}
+
+	changes, err := patch.FromRequest(c.Request)
+	if err != nil {
+		response.Error(c, patch.StatusCode(err), err, err.Error())
+		return
+	}
+
+	Address, err := p.service.Patch(c, c.Param("id"), version, changes)
+	if errors.Is(err, dbs.ErrStaleVersion) {
+		response.StaleVersion(c, err)
+		return
+	}
+	if status := patch.StatusCode(err); status != 0 {
+		response.Error(c, status, err, err.Error())
+		return
+	}
+	if err != nil {
+		logger.Error("Failed to Patch Address", err.Error())
+		response.Error(c, http.StatusInternalServerError, err, "Something went wrong")
+		return
+	}
+
+	var res dto.Address
+	utils.Copy(&res, &Address)
+	response.Versioned(c, http.StatusOK, res.Version, res)
 }
 
 // DeleteAddress godoc
@@ -194,12 +264,19 @@ func (p *AddressHandler) UpdateAddress(c *gin.Context) {
 //	@Tags		Address
 //	@Produce	json
 //	@Security	ApiKeyAuth
+//	@Param		If-Match	header	string	true	"ETag of the address, its version"
 //	@Param		_	body	dto.DeleteAddressReq	true	"Body"
 //	@Success	200	{object}	dto.Address
+//	@Failure	412	{object}	response.Response	"Changed since it was read"
 //	@Router		/address/{id} [Delete]
 func (p *AddressHandler) DeleteAddress(c *gin.Context) {
 	//	@Param		id	path	string					true	"Address ID"
 	// AddressId := c.Param("id")
+	version, ok := response.IfMatch(c)
+	if !ok {
+		return
+	}
+
 	var req dto.DeleteAddressReq
 	if err := c.ShouldBindJSON(&req); c.Request.Body == nil || err != nil {
 		logger.Error("Failed to get body", err)
@@ -207,7 +284,12 @@ func (p *AddressHandler) DeleteAddress(c *gin.Context) {
 		return
 	}
 
+	req.Version = version
 	Address, err := p.service.Delete(c, req.ID, &req)
+	if errors.Is(err, dbs.ErrStaleVersion) {
+		response.StaleVersion(c, err)
+		return
+	}
 	if err != nil {
 		logger.Error("Failed to Delete Address", err.Error())
 		response.Error(c, http.StatusInternalServerError, err, "Something went wrong")
@@ -217,5 +299,30 @@ func (p *AddressHandler) DeleteAddress(c *gin.Context) {
 	var res dto.Address
 	utils.Copy(&res, &Address)
 	response.JSON(c, http.StatusOK, res)
-	_ = p.cache.RemovePattern("*Address*")
+}
+
+// RestoreAddress godoc
+//
+//	@Summary	Restore a deleted address
+//	@Tags		Address
+//	@Produce	json
+//	@Security	ApiKeyAuth
+//	@Param		id	path		string	true	"Address ID"
+//	@Success	200	{object}	dto.Address
+//	@Router		/address-admin/addresses/{id}/restore [post]
+func (p *AddressHandler) RestoreAddress(c *gin.Context) {
+	address, err := p.service.Restore(c, c.Param("id"))
+	if errors.Is(err, service.ErrAddressNotDeleted) {
+		response.Error(c, http.StatusNotFound, err, err.Error())
+		return
+	}
+	if err != nil {
+		logger.Error("Failed to restore address ", err)
+		response.Error(c, http.StatusInternalServerError, err, "Something went wrong")
+		return
+	}
+
+	var res dto.Address
+	utils.Copy(&res, &address)
+	response.JSON(c, http.StatusOK, res)
 }
@@ -6,23 +6,38 @@ import (
 
 	"main/internal/address/repository"
 	"main/internal/address/service"
+	"main/pkg/audit"
 	"main/pkg/dbs"
+	"main/pkg/events"
+	"main/pkg/idempotency"
 	"main/pkg/middleware"
+	"main/pkg/rbac"
 	"main/pkg/redis"
 )
 
-func Routes(r *gin.RouterGroup, sqlDB dbs.IDatabase, validator validation.Validation, cache redis.IRedis) {
+func Routes(r *gin.RouterGroup, sqlDB dbs.IDatabase, validator validation.Validation, cache redis.IRedis, auth *middleware.Authenticator, keys *idempotency.Keys, recorder audit.Recorder, publisher events.Publisher) {
 	addressRepo := repository.NewAddressRepository(sqlDB)
-	addressSvc := service.NewAddressService(validator, addressRepo)
+	addressSvc := service.NewAddressService(validator, addressRepo, recorder, publisher)
 	addressHandler := NewAddressHandler(cache, addressSvc)
 
-	authMiddleware := middleware.JWTAuth()
+	authMiddleware := middleware.JWTPermission(auth, rbac.AddressesWrite)
+	deletedManage := middleware.JWTPermission(auth, rbac.DeletedManage)
+	// retried creations return the first address
+	idempotent := middleware.Idempotency(keys)
 	AddressRoute := r.Group("/address")
 	{
 		AddressRoute.GET("", addressHandler.ListAddresses)
 		AddressRoute.GET("/:id", addressHandler.GetAddressByID)
-		AddressRoute.POST("", authMiddleware, addressHandler.CreateAddress)
+		AddressRoute.POST("", authMiddleware, idempotent, addressHandler.CreateAddress)
 		AddressRoute.PUT("/:id", authMiddleware, addressHandler.UpdateAddress)
+		AddressRoute.PATCH("/:id", authMiddleware, addressHandler.PatchAddress)
 		AddressRoute.DELETE("/:id", authMiddleware, addressHandler.DeleteAddress)
 	}
+
+	// deleted addresses, listed with include_deleted
+	addressRouteAdmin := r.Group("/address-admin")
+	{
+		addressRouteAdmin.GET("/addresses", deletedManage, addressHandler.ListAddresses)
+		addressRouteAdmin.POST("/addresses/:id/restore", deletedManage, addressHandler.RestoreAddress)
+	}
 }
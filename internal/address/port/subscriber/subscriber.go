@@ -0,0 +1,17 @@
+package subscriber
+
+import (
+	"context"
+
+	"main/pkg/events"
+	"main/pkg/redis"
+	"main/pkg/tenant"
+)
+
+// Subscribe removes the cached addresses and lists of addresses when an
+// address changes
+func Subscribe(ctx context.Context, bus *events.Bus, cache redis.IRedis) error {
+	return bus.Subscribe(ctx, "address-cache", func(ctx context.Context, _ *events.Event) error {
+		return cache.RemovePattern(ctx, tenant.CachePrefix+"*address*")
+	}, events.AddressChanged)
+}
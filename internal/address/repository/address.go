@@ -2,12 +2,14 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"main/internal/address/dto"
 	"main/internal/address/model"
 	"main/pkg/config"
 	"main/pkg/dbs"
 	"main/pkg/paging"
+	"main/pkg/tenant"
 )
 
 //go:generate mockery --name=IAddressRepository
@@ -17,6 +19,8 @@ type IAddressRepository interface {
 	Update(ctx context.Context, Address *model.Address) error
 	ListAddresses(ctx context.Context, req *dto.ListAddressReq) ([]*model.Address, *paging.Pagination, error)
 	GetAddressByID(ctx context.Context, id string) (*model.Address, error)
+	Restore(ctx context.Context, id string) (bool, error)
+	Purge(ctx context.Context, before time.Time) (int64, error)
 }
 
 type AddressRepo struct {
@@ -52,8 +56,13 @@ func (r *AddressRepo) ListAddresses(ctx context.Context, req *dto.ListAddressReq
 	// 	}
 	// }
 
+	opts := []dbs.FindOption{dbs.WithQuery(query...)}
+	if req.IncludeDeleted {
+		opts = append(opts, dbs.WithDeleted())
+	}
+
 	var total int64
-	if err := r.db.Count(ctx, &model.Address{}, &total, dbs.WithQuery(query...)); err != nil {
+	if err := r.db.Count(ctx, &model.Address{}, &total, opts...); err != nil {
 		return nil, nil, err
 	}
 
@@ -63,10 +72,11 @@ func (r *AddressRepo) ListAddresses(ctx context.Context, req *dto.ListAddressReq
 	if err := r.db.Find(
 		ctx,
 		&Addresss,
-		dbs.WithQuery(query...),
-		dbs.WithLimit(int(pagination.Limit)),
-		dbs.WithOffset(int(pagination.Skip)),
-		// dbs.WithOrder(order),
+		append(opts,
+			dbs.WithLimit(int(pagination.Limit)),
+			dbs.WithOffset(int(pagination.Skip)),
+			// dbs.WithOrder(order),
+		)...,
 	); err != nil {
 		return nil, nil, err
 	}
@@ -86,9 +96,22 @@ func (r *AddressRepo) Create(ctx context.Context, Address *model.Address) error
 	return r.db.Create(ctx, Address)
 }
 
+// Update saves the address if it is still at its version
 func (r *AddressRepo) Update(ctx context.Context, Address *model.Address) error {
 	return r.db.Update(ctx, Address)
 }
+
+// Delete deletes the address if it is still at its version
 func (r *AddressRepo) Delete(ctx context.Context, Address *model.Address) error {
-	return r.db.Delete(ctx, Address)
+	return dbs.DeleteVersion(r.db.GetDB().WithContext(ctx), Address, Address.Version)
+}
+
+// Restore restores the deleted address id, false when it is not deleted
+func (r *AddressRepo) Restore(ctx context.Context, id string) (bool, error) {
+	return dbs.Restore(r.db.GetDB().WithContext(ctx), &model.Address{}, id)
+}
+
+// Purge hard deletes the addresses deleted before before
+func (r *AddressRepo) Purge(ctx context.Context, before time.Time) (int64, error) {
+	return dbs.Purge(r.db.GetDB().WithContext(tenant.Global(ctx)), &model.Address{}, before)
 }
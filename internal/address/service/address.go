@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"errors"
 
 	"github.com/quangdangfit/gocommon/logger"
 	"github.com/quangdangfit/gocommon/validation"
@@ -9,10 +10,15 @@ import (
 	"main/internal/address/dto"
 	"main/internal/address/model"
 	"main/internal/address/repository"
+	"main/pkg/audit"
+	"main/pkg/events"
 	"main/pkg/paging"
+	"main/pkg/patch"
 	"main/pkg/utils"
 )
 
+var ErrAddressNotDeleted = errors.New("address not found or not deleted")
+
 //go:generate mockery --name=IAddressService
 type IAddressService interface {
 	ListAddresses(c context.Context, req *dto.ListAddressReq) ([]*model.Address, *paging.Pagination, error)
@@ -20,20 +26,28 @@ type IAddressService interface {
 	Create(ctx context.Context, req *dto.CreateAddressReq) (*model.Address, error)
 	Delete(ctx context.Context, id string, req *dto.DeleteAddressReq) (*model.Address, error)
 	Update(ctx context.Context, id string, req *dto.UpdateAddressReq) (*model.Address, error)
+	Patch(ctx context.Context, id string, version int64, changes patch.Patch) (*model.Address, error)
+	Restore(ctx context.Context, id string) (*model.Address, error)
 }
 
 type AddressService struct {
 	validator validation.Validation
 	repo      repository.IAddressRepository
+	recorder  audit.Recorder
+	publisher events.Publisher
 }
 
 func NewAddressService(
 	validator validation.Validation,
 	repo repository.IAddressRepository,
+	recorder audit.Recorder,
+	publisher events.Publisher,
 ) *AddressService {
 	return &AddressService{
 		validator: validator,
 		repo:      repo,
+		recorder:  recorder,
+		publisher: publisher,
 	}
 }
 
@@ -68,6 +82,8 @@ func (p *AddressService) Create(ctx context.Context, req *dto.CreateAddressReq)
 		logger.Errorf("Create fail, error: %s", err)
 		return nil, err
 	}
+	p.recorder.Record(ctx, &audit.Event{Action: audit.AddressCreate, TargetType: audit.TargetAddress, TargetID: Address.ID, After: &Address})
+	p.publish(ctx, &Address, events.ActionCreated)
 
 	return &Address, nil
 }
@@ -82,13 +98,55 @@ func (p *AddressService) Update(ctx context.Context, id string, req *dto.UpdateA
 		logger.Errorf("Update.GetAddressByID fail, id: %s, error: %s", id, err)
 		return nil, err
 	}
+	before := *Address
 
+	// the request never moves the address to another row, nor drops its user
 	utils.Copy(Address, req)
+	Address.ID = id
+	if req.IDUser == "" {
+		Address.IDUser = before.IDUser
+	}
+	Address.Version = req.Version
 	err = p.repo.Update(ctx, Address)
 	if err != nil {
 		logger.Errorf("Update fail, id: %s, error: %s", id, err)
 		return nil, err
 	}
+	p.recorder.Record(ctx, &audit.Event{Action: audit.AddressUpdate, TargetType: audit.TargetAddress, TargetID: id, Before: &before, After: Address})
+	p.publish(ctx, Address, events.ActionUpdated)
+
+	return Address, nil
+}
+
+// Patch applies changes to the editable fields of an address at version, the
+// fields it does not change are kept and only the changed ones are validated
+func (p *AddressService) Patch(ctx context.Context, id string, version int64, changes patch.Patch) (*model.Address, error) {
+	Address, err := p.repo.GetAddressByID(ctx, id)
+	if err != nil {
+		logger.Errorf("Patch.GetAddressByID fail, id: %s, error: %s", id, err)
+		return nil, err
+	}
+	before := *Address
+
+	var current dto.UpdateAddressReq
+	utils.Copy(&current, Address)
+	var req dto.PatchAddressReq
+	if err := patch.Apply(changes, &current, &req); err != nil {
+		return nil, err
+	}
+	if err := p.validator.ValidateStruct(&req); err != nil {
+		return nil, patch.Invalid(err)
+	}
+
+	utils.Copy(Address, &req)
+	Address.Version = version
+	err = p.repo.Update(ctx, Address)
+	if err != nil {
+		logger.Errorf("Patch fail, id: %s, error: %s", id, err)
+		return nil, err
+	}
+	p.recorder.Record(ctx, &audit.Event{Action: audit.AddressUpdate, TargetType: audit.TargetAddress, TargetID: id, Before: &before, After: Address})
+	p.publish(ctx, Address, events.ActionUpdated)
 
 	return Address, nil
 }
@@ -104,12 +162,41 @@ func (p *AddressService) Delete(ctx context.Context, id string, req *dto.DeleteA
 		return nil, err
 	}
 
-	utils.Copy(Address, req)
+	Address.Version = req.Version
 	err = p.repo.Delete(ctx, Address)
 	if err != nil {
 		logger.Errorf("Delete fail, id: %s, error: %s", id, err)
 		return nil, err
 	}
+	p.recorder.Record(ctx, &audit.Event{Action: audit.AddressDelete, TargetType: audit.TargetAddress, TargetID: id, Before: Address})
+	p.publish(ctx, Address, events.ActionDeleted)
 
 	return Address, nil
 }
+
+// Restore restores a deleted address
+func (p *AddressService) Restore(ctx context.Context, id string) (*model.Address, error) {
+	restored, err := p.repo.Restore(ctx, id)
+	if err != nil {
+		logger.Errorf("Restore fail, id: %s, error: %s", id, err)
+		return nil, err
+	}
+	if !restored {
+		return nil, ErrAddressNotDeleted
+	}
+
+	address, err := p.repo.GetAddressByID(ctx, id)
+	if err != nil {
+		logger.Errorf("Restore.GetAddressByID fail, id: %s, error: %s", id, err)
+		return nil, err
+	}
+	p.recorder.Record(ctx, &audit.Event{Action: audit.AddressRestore, TargetType: audit.TargetAddress, TargetID: id, After: address})
+	p.publish(ctx, address, events.ActionRestored)
+
+	return address, nil
+}
+
+// publish tells the subscribers that address changed
+func (p *AddressService) publish(ctx context.Context, address *model.Address, action string) {
+	p.publisher.Publish(ctx, events.New(ctx, events.AddressChanged, address.ID, &events.Change{Action: action, UserID: address.IDUser}))
+}
@@ -0,0 +1,64 @@
+package dto
+
+import (
+	"time"
+
+	"main/pkg/paging"
+)
+
+// swagger:model AuditEvent
+type AuditEvent struct {
+	ID         string    `json:"id"`
+	Seq        int64     `json:"seq"`
+	CreatedAt  time.Time `json:"created_at"`
+	Action     string    `json:"action"`
+	Outcome    string    `json:"outcome"`
+	Reason     string    `json:"reason"`
+	ActorID    string    `json:"actor_id"`
+	ActorRole  string    `json:"actor_role"`
+	ClientID   string    `json:"client_id"`
+	TenantID   string    `json:"tenant_id"`
+	TargetType string    `json:"target_type"`
+	TargetID   string    `json:"target_id"`
+	Before     string    `json:"before"`
+	After      string    `json:"after"`
+	Diff       string    `json:"diff"`
+	IP         string    `json:"ip"`
+	UserAgent  string    `json:"user_agent"`
+	RequestID  string    `json:"request_id"`
+	PrevHash   string    `json:"prev_hash"`
+	Hash       string    `json:"hash"`
+}
+
+// ListAuditEventsReq filters the audit log, the export ignores the paging
+type ListAuditEventsReq struct {
+	// example: "auth.login"
+	Action string `json:"action,omitempty" form:"action"`
+	// example: "failure"
+	Outcome    string `json:"outcome,omitempty" form:"outcome" validate:"omitempty,oneof=success failure"`
+	ActorID    string `json:"actor_id,omitempty" form:"actor_id"`
+	TargetType string `json:"target_type,omitempty" form:"target_type"`
+	TargetID   string `json:"target_id,omitempty" form:"target_id"`
+	RequestID  string `json:"request_id,omitempty" form:"request_id"`
+	TenantID   string `json:"tenant_id,omitempty" form:"tenant_id"`
+	// From and To are RFC 3339 times, To excluded
+	From  *time.Time `json:"from,omitempty" form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To    *time.Time `json:"to,omitempty" form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	Page  int64      `json:"page,omitempty" form:"page"`
+	Limit int64      `json:"limit,omitempty" form:"limit"`
+}
+
+// swagger:model ListAuditEventsRes
+type ListAuditEventsRes struct {
+	Events     []*AuditEvent      `json:"events"`
+	Pagination *paging.Pagination `json:"pagination"`
+}
+
+// AuditChainRes is the result of checking the hash chain, BrokenAt is the
+// first entry whose hash or link does not match
+// swagger:model AuditChainRes
+type AuditChainRes struct {
+	Valid    bool  `json:"valid"`
+	Checked  int64 `json:"checked"`
+	BrokenAt int64 `json:"broken_at,omitempty"`
+}
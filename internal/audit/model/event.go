@@ -0,0 +1,94 @@
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Event is an entry of the audit log. Entries are only appended, each one is
+// chained to the previous one by hashing its content with PrevHash, so
+// changing or removing an entry breaks the chain from there.
+type Event struct {
+	ID        string    `json:"id" gorm:"unique;not null;index;primary_key"`
+	Seq       int64     `json:"seq" gorm:"not null;uniqueIndex"`
+	CreatedAt time.Time `json:"created_at" gorm:"not null;index"`
+	Action    string    `json:"action" gorm:"not null;index"`
+	Outcome   string    `json:"outcome" gorm:"not null"`
+	Reason    string    `json:"reason"`
+	ActorID   string    `json:"actor_id" gorm:"index"`
+	ActorRole string    `json:"actor_role"`
+	ClientID  string    `json:"client_id"`
+	// TenantID is the clinic of the actor, empty outside of clinics
+	TenantID   string `json:"tenant_id" gorm:"not null;default:'';index"`
+	TargetType string `json:"target_type" gorm:"index:idx_audit_target"`
+	TargetID   string `json:"target_id" gorm:"index:idx_audit_target"`
+	// Before, After and Diff are json, without the secrets
+	Before    string `json:"before" gorm:"type:text"`
+	After     string `json:"after" gorm:"type:text"`
+	Diff      string `json:"diff" gorm:"type:text"`
+	IP        string `json:"ip"`
+	UserAgent string `json:"user_agent"`
+	RequestID string `json:"request_id" gorm:"index"`
+	PrevHash  string `json:"prev_hash" gorm:"not null"`
+	Hash      string `json:"hash" gorm:"not null"`
+}
+
+func (Event) TableName() string {
+	return "audit_events"
+}
+
+// BeforeCreate sets the id and the time, to the precision stored by postgres
+// so the hash can be computed again from the stored entry
+func (m *Event) BeforeCreate() error {
+	m.ID = uuid.New().String()
+	m.CreatedAt = time.Now().UTC().Truncate(time.Microsecond)
+	return nil
+}
+
+// Chain sets the position of the event after prev, nil for the first event,
+// and its hash
+func (m *Event) Chain(prev *Event) {
+	m.Seq = 1
+	m.PrevHash = ""
+	if prev != nil {
+		m.Seq = prev.Seq + 1
+		m.PrevHash = prev.Hash
+	}
+	m.Hash = m.ComputeHash()
+}
+
+// ComputeHash hashes the content of the event with PrevHash, each field
+// prefixed by its length so fields cannot be shifted into one another
+func (m *Event) ComputeHash() string {
+	h := sha256.New()
+	for _, field := range []string{
+		strconv.FormatInt(m.Seq, 10),
+		m.ID,
+		m.CreatedAt.UTC().Format(time.RFC3339Nano),
+		m.Action,
+		m.Outcome,
+		m.Reason,
+		m.ActorID,
+		m.ActorRole,
+		m.ClientID,
+		m.TenantID,
+		m.TargetType,
+		m.TargetID,
+		m.Before,
+		m.After,
+		m.Diff,
+		m.IP,
+		m.UserAgent,
+		m.RequestID,
+		m.PrevHash,
+	} {
+		h.Write([]byte(strconv.Itoa(len(field))))
+		h.Write([]byte{':'})
+		h.Write([]byte(field))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,119 @@
+package http
+
+import (
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/quangdangfit/gocommon/logger"
+
+	"main/internal/audit/dto"
+	"main/internal/audit/service"
+	"main/pkg/response"
+	"main/pkg/utils"
+)
+
+type AuditHandler struct {
+	service service.IAuditService
+}
+
+func NewAuditHandler(service service.IAuditService) *AuditHandler {
+	return &AuditHandler{service: service}
+}
+
+// ListEvents godoc
+//
+//	@Summary	List the audit log, the latest events first
+//	@Tags		Audit-admin
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		action		query		string	false	"Action, such as auth.login"
+//	@Param		outcome		query		string	false	"success or failure"
+//	@Param		actor_id	query		string	false	"Actor user ID"
+//	@Param		target_type	query		string	false	"Target type, such as doctor"
+//	@Param		target_id	query		string	false	"Target ID"
+//	@Param		request_id	query		string	false	"Request ID"
+//	@Param		tenant_id	query		string	false	"Clinic ID"
+//	@Param		from		query		string	false	"From, RFC 3339"
+//	@Param		to			query		string	false	"To, RFC 3339, excluded"
+//	@Param		page		query		int		false	"Page"
+//	@Param		limit		query		int		false	"Limit"
+//	@Success	200			{object}	dto.ListAuditEventsRes
+//	@Router		/audit-admin/events [get]
+func (h *AuditHandler) ListEvents(c *gin.Context) {
+	var req dto.ListAuditEventsReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.Error("Failed to get query params", err)
+		response.Error(c, http.StatusBadRequest, err, "Invalid parameters")
+		return
+	}
+
+	events, pagination, err := h.service.ListEvents(c, &req)
+	if err != nil {
+		logger.Error("Failed to list audit events ", err)
+		response.Error(c, http.StatusBadRequest, err, err.Error())
+		return
+	}
+
+	var res dto.ListAuditEventsRes
+	utils.Copy(&res.Events, &events)
+	res.Pagination = pagination
+	response.JSON(c, http.StatusOK, res)
+}
+
+// ExportEvents godoc
+//
+//	@Summary	Export the audit log as csv, in the order of the chain
+//	@Tags		Audit-admin
+//	@Security	ApiKeyAuth
+//	@Produce	text/csv
+//	@Param		action		query	string	false	"Action, such as auth.login"
+//	@Param		outcome		query	string	false	"success or failure"
+//	@Param		actor_id	query	string	false	"Actor user ID"
+//	@Param		target_type	query	string	false	"Target type, such as doctor"
+//	@Param		target_id	query	string	false	"Target ID"
+//	@Param		request_id	query	string	false	"Request ID"
+//	@Param		tenant_id	query	string	false	"Clinic ID"
+//	@Param		from		query	string	false	"From, RFC 3339"
+//	@Param		to			query	string	false	"To, RFC 3339, excluded"
+//	@Success	200			{file}	file
+//	@Router		/audit-admin/events/export [get]
+func (h *AuditHandler) ExportEvents(c *gin.Context) {
+	var req dto.ListAuditEventsReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.Error("Failed to get query params", err)
+		response.Error(c, http.StatusBadRequest, err, "Invalid parameters")
+		return
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{
+		"filename": "audit-" + time.Now().UTC().Format("20060102T150405Z") + ".csv",
+	}))
+	c.Header("Cache-Control", "no-store")
+	c.Status(http.StatusOK)
+	// the rows are streamed, a failure can only cut the export short
+	if err := h.service.Export(c, &req, c.Writer); err != nil {
+		logger.Error("Failed to export audit events ", err)
+	}
+}
+
+// VerifyChain godoc
+//
+//	@Summary	Check the hash chain of the audit log
+//	@Tags		Audit-admin
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Success	200	{object}	dto.AuditChainRes
+//	@Router		/audit-admin/verify [get]
+func (h *AuditHandler) VerifyChain(c *gin.Context) {
+	res, err := h.service.VerifyChain(c)
+	if err != nil {
+		logger.Error("Failed to verify audit chain ", err)
+		response.Error(c, http.StatusInternalServerError, err, "Something went wrong")
+		return
+	}
+
+	response.JSON(c, http.StatusOK, res)
+}
@@ -0,0 +1,21 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"main/internal/audit/service"
+	"main/pkg/middleware"
+	"main/pkg/rbac"
+)
+
+func Routes(r *gin.RouterGroup, auditSvc service.IAuditService, auth *middleware.Authenticator) {
+	auditHandler := NewAuditHandler(auditSvc)
+
+	auditRead := middleware.JWTPermission(auth, rbac.AuditRead)
+	auditRouteAdmin := r.Group("/audit-admin")
+	{
+		auditRouteAdmin.GET("/events", auditRead, auditHandler.ListEvents)
+		auditRouteAdmin.GET("/events/export", auditRead, auditHandler.ExportEvents)
+		auditRouteAdmin.GET("/verify", auditRead, auditHandler.VerifyChain)
+	}
+}
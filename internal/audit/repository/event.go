@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"main/internal/audit/dto"
+	"main/internal/audit/model"
+	"main/pkg/config"
+	"main/pkg/dbs"
+	"main/pkg/paging"
+	"main/pkg/tenant"
+)
+
+// appendLock is the advisory lock serializing the appends, so every event is
+// chained to the last one
+const appendLock = 7261_0041
+
+//go:generate mockery --name=IAuditRepository
+type IAuditRepository interface {
+	Append(ctx context.Context, event *model.Event) error
+	ListEvents(ctx context.Context, req *dto.ListAuditEventsReq) ([]*model.Event, *paging.Pagination, error)
+	EachEvent(ctx context.Context, req *dto.ListAuditEventsReq, batchSize int, fn func(events []*model.Event) error) error
+	Protect(ctx context.Context) error
+}
+
+type AuditRepo struct {
+	db dbs.IDatabase
+}
+
+func NewAuditRepository(db dbs.IDatabase) *AuditRepo {
+	return &AuditRepo{db: db}
+}
+
+// Append chains event to the last event and inserts it. The log spans the
+// clinics, it is never scoped to the clinic of ctx.
+func (r *AuditRepo) Append(ctx context.Context, event *model.Event) error {
+	ctx, cancel := context.WithTimeout(tenant.Global(ctx), config.DatabaseTimeout)
+	defer cancel()
+
+	return r.db.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", appendLock).Error; err != nil {
+			return err
+		}
+
+		var last model.Event
+		err := tx.Order("seq DESC").First(&last).Error
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			event.Chain(nil)
+		case err != nil:
+			return err
+		default:
+			event.Chain(&last)
+		}
+		return tx.Create(event).Error
+	})
+}
+
+func (r *AuditRepo) ListEvents(ctx context.Context, req *dto.ListAuditEventsReq) ([]*model.Event, *paging.Pagination, error) {
+	ctx, cancel := context.WithTimeout(tenant.Global(ctx), config.DatabaseTimeout)
+	defer cancel()
+
+	query := filter(req)
+
+	var total int64
+	if err := r.db.Count(ctx, &model.Event{}, &total, dbs.WithQuery(query...)); err != nil {
+		return nil, nil, err
+	}
+
+	pagination := paging.New(req.Page, req.Limit, total)
+
+	var events []*model.Event
+	if err := r.db.Find(
+		ctx,
+		&events,
+		dbs.WithQuery(query...),
+		dbs.WithLimit(int(pagination.Limit)),
+		dbs.WithOffset(int(pagination.Skip)),
+		dbs.WithOrder("seq DESC"),
+	); err != nil {
+		return nil, nil, err
+	}
+
+	return events, pagination, nil
+}
+
+// EachEvent calls fn with the events matching req in the order of the chain,
+// batchSize at a time, until fn fails
+func (r *AuditRepo) EachEvent(ctx context.Context, req *dto.ListAuditEventsReq, batchSize int, fn func(events []*model.Event) error) error {
+	ctx = tenant.Global(ctx)
+	query := filter(req)
+
+	var after int64
+	for {
+		var events []*model.Event
+		err := func() error {
+			ctx, cancel := context.WithTimeout(ctx, config.DatabaseTimeout)
+			defer cancel()
+
+			tx := r.db.GetDB().WithContext(ctx).Where("seq > ?", after)
+			for _, q := range query {
+				tx = tx.Where(q.Query, q.Args...)
+			}
+			return tx.Order("seq").Limit(batchSize).Find(&events).Error
+		}()
+		if err != nil {
+			return err
+		}
+		if len(events) == 0 {
+			return nil
+		}
+		if err := fn(events); err != nil {
+			return err
+		}
+		if len(events) < batchSize {
+			return nil
+		}
+		after = events[len(events)-1].Seq
+	}
+}
+
+// Protect installs the trigger rejecting the updates and deletes of the
+// audit log, the table can only be appended to
+func (r *AuditRepo) Protect(ctx context.Context) error {
+	statements := []string{
+		`CREATE OR REPLACE FUNCTION audit_events_append_only() RETURNS trigger AS $$
+BEGIN
+	RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql`,
+		`DROP TRIGGER IF EXISTS audit_events_append_only ON audit_events`,
+		`CREATE TRIGGER audit_events_append_only BEFORE UPDATE OR DELETE OR TRUNCATE ON audit_events
+	FOR EACH STATEMENT EXECUTE FUNCTION audit_events_append_only()`,
+	}
+	for _, statement := range statements {
+		if err := r.db.Exec(ctx, statement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func filter(req *dto.ListAuditEventsReq) []dbs.Query {
+	var query []dbs.Query
+	if req == nil {
+		return query
+	}
+	if req.Action != "" {
+		query = append(query, dbs.NewQuery("action = ?", req.Action))
+	}
+	if req.Outcome != "" {
+		query = append(query, dbs.NewQuery("outcome = ?", req.Outcome))
+	}
+	if req.ActorID != "" {
+		query = append(query, dbs.NewQuery("actor_id = ?", req.ActorID))
+	}
+	if req.TargetType != "" {
+		query = append(query, dbs.NewQuery("target_type = ?", req.TargetType))
+	}
+	if req.TargetID != "" {
+		query = append(query, dbs.NewQuery("target_id = ?", req.TargetID))
+	}
+	if req.RequestID != "" {
+		query = append(query, dbs.NewQuery("request_id = ?", req.RequestID))
+	}
+	if req.TenantID != "" {
+		query = append(query, dbs.NewQuery("tenant_id = ?", req.TenantID))
+	}
+	if req.From != nil {
+		query = append(query, dbs.NewQuery("created_at >= ?", *req.From))
+	}
+	if req.To != nil {
+		query = append(query, dbs.NewQuery("created_at < ?", *req.To))
+	}
+	return query
+}
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/quangdangfit/gocommon/logger"
+	"github.com/quangdangfit/gocommon/validation"
+
+	"main/internal/audit/dto"
+	"main/internal/audit/model"
+	"main/internal/audit/repository"
+	"main/pkg/audit"
+	"main/pkg/paging"
+)
+
+// batchSize is the number of events read at a time by the export and the
+// chain verification
+const batchSize = 500
+
+// errBroken stops the verification at the first broken entry
+var errBroken = errors.New("audit chain broken")
+
+// CSVHeader is the first row of the export
+var CSVHeader = []string{
+	"seq", "id", "created_at", "action", "outcome", "reason", "actor_id", "actor_role", "client_id", "tenant_id",
+	"target_type", "target_id", "before", "after", "diff", "ip", "user_agent", "request_id", "prev_hash", "hash",
+}
+
+//go:generate mockery --name=IAuditService
+type IAuditService interface {
+	audit.Recorder
+	ListEvents(ctx context.Context, req *dto.ListAuditEventsReq) ([]*model.Event, *paging.Pagination, error)
+	Export(ctx context.Context, req *dto.ListAuditEventsReq, w io.Writer) error
+	VerifyChain(ctx context.Context) (*dto.AuditChainRes, error)
+}
+
+type AuditService struct {
+	validator validation.Validation
+	repo      repository.IAuditRepository
+}
+
+func NewAuditService(
+	validator validation.Validation,
+	repo repository.IAuditRepository,
+) *AuditService {
+	return &AuditService{
+		validator: validator,
+		repo:      repo,
+	}
+}
+
+// Record appends event with the actor of ctx. It is recorded even when the
+// request is cancelled, a failure is only logged.
+func (s *AuditService) Record(ctx context.Context, event *audit.Event) {
+	actor := audit.ActorFromContext(ctx)
+	entry := model.Event{
+		Action:     event.Action,
+		Outcome:    event.Outcome,
+		Reason:     event.Reason,
+		ActorID:    actor.UserID,
+		ActorRole:  actor.Role,
+		ClientID:   actor.ClientID,
+		TenantID:   actor.TenantID,
+		TargetType: event.TargetType,
+		TargetID:   event.TargetID,
+		IP:         actor.IP,
+		UserAgent:  actor.UserAgent,
+		RequestID:  actor.RequestID,
+	}
+	if entry.Outcome == "" {
+		entry.Outcome = audit.Success
+	}
+
+	before, after := audit.Snapshot(event.Before), audit.Snapshot(event.After)
+	entry.Before = marshal(before)
+	entry.After = marshal(after)
+	if before != nil && after != nil {
+		entry.Diff = marshal(audit.Diff(before, after))
+	}
+
+	_ = entry.BeforeCreate()
+	if err := s.repo.Append(context.WithoutCancel(ctx), &entry); err != nil {
+		logger.Errorf("Record.Append fail, action: %s, target: %s, error: %s", entry.Action, entry.TargetID, err)
+	}
+}
+
+func (s *AuditService) ListEvents(ctx context.Context, req *dto.ListAuditEventsReq) ([]*model.Event, *paging.Pagination, error) {
+	if err := s.validator.ValidateStruct(req); err != nil {
+		return nil, nil, err
+	}
+
+	events, pagination, err := s.repo.ListEvents(ctx, req)
+	if err != nil {
+		logger.Errorf("ListEvents fail, error: %s", err)
+		return nil, nil, err
+	}
+	return events, pagination, nil
+}
+
+// Export writes the events matching req to w as csv, in the order of the
+// chain
+func (s *AuditService) Export(ctx context.Context, req *dto.ListAuditEventsReq, w io.Writer) error {
+	if err := s.validator.ValidateStruct(req); err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(CSVHeader); err != nil {
+		return err
+	}
+	err := s.repo.EachEvent(ctx, req, batchSize, func(events []*model.Event) error {
+		for _, e := range events {
+			if err := writer.Write([]string{
+				strconv.FormatInt(e.Seq, 10), e.ID, e.CreatedAt.UTC().Format(time.RFC3339Nano), e.Action, e.Outcome,
+				e.Reason, e.ActorID, e.ActorRole, e.ClientID, e.TenantID, e.TargetType, e.TargetID,
+				e.Before, e.After, e.Diff, e.IP, e.UserAgent, e.RequestID, e.PrevHash, e.Hash,
+			}); err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+	})
+	if err != nil {
+		logger.Errorf("Export fail, error: %s", err)
+		return err
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// VerifyChain hashes every event again and checks it links to the previous
+// one, stopping at the first broken entry
+func (s *AuditService) VerifyChain(ctx context.Context) (*dto.AuditChainRes, error) {
+	res := dto.AuditChainRes{Valid: true}
+	var prev *model.Event
+	err := s.repo.EachEvent(ctx, nil, batchSize, func(events []*model.Event) error {
+		for _, e := range events {
+			res.Checked++
+			if !linked(prev, e) || e.ComputeHash() != e.Hash {
+				res.Valid = false
+				res.BrokenAt = e.Seq
+				return errBroken
+			}
+			prev = e
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errBroken) {
+		logger.Errorf("VerifyChain fail, error: %s", err)
+		return nil, err
+	}
+	return &res, nil
+}
+
+func linked(prev, e *model.Event) bool {
+	if prev == nil {
+		return e.Seq == 1 && e.PrevHash == ""
+	}
+	return e.Seq == prev.Seq+1 && e.PrevHash == prev.Hash
+}
+
+func marshal(v any) string {
+	if v == nil {
+		return ""
+	}
+	switch m := v.(type) {
+	case map[string]any:
+		if m == nil {
+			return ""
+		}
+	case map[string]audit.Change:
+		if m == nil {
+			return ""
+		}
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
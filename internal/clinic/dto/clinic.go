@@ -0,0 +1,67 @@
+package dto
+
+import (
+	"time"
+
+	"main/internal/user/model"
+)
+
+// swagger:model Clinic
+type Clinic struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Slug      string    `json:"slug"`
+	Email     string    `json:"email"`
+	Phone     string    `json:"phone"`
+	CreatedAt time.Time `json:"created_at"`
+	// Version of the clinic, also sent as its ETag
+	Version int64 `json:"version"`
+}
+
+// swagger:model CreateClinicReq
+type CreateClinicReq struct {
+	// example: "Saint Mary Clinic"
+	Name string `json:"name" validate:"required,max=200"`
+	// Unique, lower case words separated by dashes
+	// example: "saint-mary"
+	Slug  string `json:"slug" validate:"required,max=100"`
+	Email string `json:"email" validate:"omitempty,email"`
+	Phone string `json:"phone"`
+}
+
+// UpdateClinicReq changes the name and contacts, the slug stays
+// swagger:model UpdateClinicReq
+type UpdateClinicReq struct {
+	Name  string `json:"name" validate:"required,max=200"`
+	Email string `json:"email" validate:"omitempty,email"`
+	Phone string `json:"phone"`
+	// Version the change applies to, from If-Match over HTTP
+	Version int64 `json:"-"`
+}
+
+// swagger:model ListClinicsRes
+type ListClinicsRes struct {
+	Clinics []*Clinic `json:"clinics"`
+}
+
+// Staff is a user of a clinic
+// swagger:model Staff
+type Staff struct {
+	ID    string         `json:"id"`
+	Email string         `json:"email"`
+	Name  string         `json:"name"`
+	Role  model.UserRole `json:"role"`
+}
+
+// AddStaffReq adds a registered user without clinic to one
+// swagger:model AddStaffReq
+type AddStaffReq struct {
+	Email string `json:"email" validate:"required,email"`
+	// example: "doctor"
+	Role model.UserRole `json:"role" validate:"required,oneof=doctor clinic_admin"`
+}
+
+// swagger:model ListStaffRes
+type ListStaffRes struct {
+	Staff []*Staff `json:"staff"`
+}
@@ -0,0 +1,31 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Clinic is an organisation owning doctors, staff users and their addresses.
+// Its ID is the tenant id of the rows it owns.
+type Clinic struct {
+	ID        string    `json:"id" gorm:"unique;not null;index;primary_key"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Name      string    `json:"name" gorm:"not null"`
+	Slug      string    `json:"slug" gorm:"not null;uniqueIndex"`
+	Email     string    `json:"email"`
+	Phone     string    `json:"phone"`
+	// Version is bumped by every change, it is the ETag of the clinic
+	Version int64 `json:"version" gorm:"not null;default:1"`
+}
+
+func (Clinic) TableName() string {
+	return "clinics"
+}
+
+func (m *Clinic) BeforeCreate() error {
+	m.ID = uuid.New().String()
+	m.CreatedAt = time.Now()
+	return nil
+}
@@ -0,0 +1,244 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/quangdangfit/gocommon/logger"
+
+	"main/internal/clinic/dto"
+	"main/internal/clinic/service"
+	"main/pkg/dbs"
+	"main/pkg/response"
+	"main/pkg/tenant"
+	"main/pkg/utils"
+)
+
+type ClinicHandler struct {
+	service service.IClinicService
+}
+
+func NewClinicHandler(
+	service service.IClinicService,
+) *ClinicHandler {
+	return &ClinicHandler{
+		service: service,
+	}
+}
+
+// CreateClinic godoc
+//
+//	@Summary	Create a clinic
+//	@Tags		Clinic-admin
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		_	body		dto.CreateClinicReq	true	"Body"
+//	@Success	200	{object}	dto.Clinic
+//	@Router		/clinic-admin/clinics [post]
+func (h *ClinicHandler) CreateClinic(c *gin.Context) {
+	var req dto.CreateClinicReq
+	if err := c.ShouldBindJSON(&req); c.Request.Body == nil || err != nil {
+		logger.Error("Failed to get body", err)
+		response.Error(c, http.StatusBadRequest, err, "Invalid parameters")
+		return
+	}
+
+	clinic, err := h.service.Create(c, &req)
+	if err != nil {
+		logger.Error("Failed to create clinic ", err)
+		clinicError(c, err)
+		return
+	}
+
+	var res dto.Clinic
+	utils.Copy(&res, clinic)
+	response.JSON(c, http.StatusOK, res)
+}
+
+// ListClinics godoc
+//
+//	@Summary	List the clinics
+//	@Tags		Clinic-admin
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Success	200	{object}	dto.ListClinicsRes
+//	@Router		/clinic-admin/clinics [get]
+func (h *ClinicHandler) ListClinics(c *gin.Context) {
+	clinics, err := h.service.ListClinics(c)
+	if err != nil {
+		logger.Error("Failed to list clinics ", err)
+		clinicError(c, err)
+		return
+	}
+
+	var res dto.ListClinicsRes
+	utils.Copy(&res.Clinics, &clinics)
+	response.JSON(c, http.StatusOK, res)
+}
+
+// GetClinic godoc
+//
+//	@Summary	Get a clinic, the clinic of the signed-in clinic admin on /clinic
+//	@Tags		Clinic
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		id				path		string	true	"Clinic ID"
+//	@Param		If-None-Match	header		string	false	"ETag of the clinic already read"
+//	@Success	200				{object}	dto.Clinic
+//	@Success	304				"Not changed since If-None-Match"
+//	@Router		/clinic-admin/clinics/{id} [get]
+//	@Router		/clinic [get]
+func (h *ClinicHandler) GetClinic(c *gin.Context) {
+	clinic, err := h.service.GetClinicByID(c, clinicID(c))
+	if err != nil {
+		logger.Error("Failed to get clinic ", err)
+		clinicError(c, err)
+		return
+	}
+
+	var res dto.Clinic
+	utils.Copy(&res, clinic)
+	response.Versioned(c, http.StatusOK, res.Version, res)
+}
+
+// UpdateClinic godoc
+//
+//	@Summary	Update a clinic, the clinic of the signed-in clinic admin on /clinic
+//	@Tags		Clinic
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		id			path		string				true	"Clinic ID"
+//	@Param		If-Match	header		string				true	"ETag of the clinic, its version"
+//	@Param		_			body		dto.UpdateClinicReq	true	"Body"
+//	@Success	200			{object}	dto.Clinic
+//	@Failure	412			{object}	response.Response	"Changed since it was read"
+//	@Router		/clinic-admin/clinics/{id} [put]
+//	@Router		/clinic [put]
+func (h *ClinicHandler) UpdateClinic(c *gin.Context) {
+	version, ok := response.IfMatch(c)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateClinicReq
+	if err := c.ShouldBindJSON(&req); c.Request.Body == nil || err != nil {
+		logger.Error("Failed to get body", err)
+		response.Error(c, http.StatusBadRequest, err, "Invalid parameters")
+		return
+	}
+
+	req.Version = version
+	clinic, err := h.service.Update(c, clinicID(c), &req)
+	if err != nil {
+		logger.Error("Failed to update clinic ", err)
+		clinicError(c, err)
+		return
+	}
+
+	var res dto.Clinic
+	utils.Copy(&res, clinic)
+	response.Versioned(c, http.StatusOK, res.Version, res)
+}
+
+// ListStaff godoc
+//
+//	@Summary	List the staff of a clinic
+//	@Tags		Clinic
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		id	path		string	true	"Clinic ID"
+//	@Success	200	{object}	dto.ListStaffRes
+//	@Router		/clinic-admin/clinics/{id}/staff [get]
+//	@Router		/clinic/staff [get]
+func (h *ClinicHandler) ListStaff(c *gin.Context) {
+	staff, err := h.service.ListStaff(c, clinicID(c))
+	if err != nil {
+		logger.Error("Failed to list staff ", err)
+		clinicError(c, err)
+		return
+	}
+
+	var res dto.ListStaffRes
+	utils.Copy(&res.Staff, &staff)
+	response.JSON(c, http.StatusOK, res)
+}
+
+// AddStaff godoc
+//
+//	@Summary	Add a registered user to a clinic as doctor or clinic admin
+//	@Tags		Clinic
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		id	path		string			true	"Clinic ID"
+//	@Param		_	body		dto.AddStaffReq	true	"Body"
+//	@Success	200	{object}	dto.Staff
+//	@Router		/clinic-admin/clinics/{id}/staff [post]
+//	@Router		/clinic/staff [post]
+func (h *ClinicHandler) AddStaff(c *gin.Context) {
+	var req dto.AddStaffReq
+	if err := c.ShouldBindJSON(&req); c.Request.Body == nil || err != nil {
+		logger.Error("Failed to get body", err)
+		response.Error(c, http.StatusBadRequest, err, "Invalid parameters")
+		return
+	}
+
+	user, err := h.service.AddStaff(c, clinicID(c), &req)
+	if err != nil {
+		logger.Error("Failed to add staff ", err)
+		clinicError(c, err)
+		return
+	}
+
+	var res dto.Staff
+	utils.Copy(&res, user)
+	response.JSON(c, http.StatusOK, res)
+}
+
+// RemoveStaff godoc
+//
+//	@Summary	Remove a user from a clinic
+//	@Tags		Clinic
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		id		path	string	true	"Clinic ID"
+//	@Param		userId	path	string	true	"User ID"
+//	@Success	200
+//	@Router		/clinic-admin/clinics/{id}/staff/{userId} [delete]
+//	@Router		/clinic/staff/{userId} [delete]
+func (h *ClinicHandler) RemoveStaff(c *gin.Context) {
+	err := h.service.RemoveStaff(c, clinicID(c), c.GetString("userId"), c.Param("userId"))
+	if err != nil {
+		logger.Error("Failed to remove staff ", err)
+		clinicError(c, err)
+		return
+	}
+
+	response.JSON(c, http.StatusOK, nil)
+}
+
+// clinicID is the clinic of the path for admins, else the clinic of the
+// signed-in clinic admin
+func clinicID(c *gin.Context) string {
+	if id := c.Param("id"); id != "" {
+		return id
+	}
+	return c.GetString(tenant.Key)
+}
+
+func clinicError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrClinicNotFound):
+		response.Error(c, http.StatusNotFound, err, "Clinic not found")
+	case errors.Is(err, service.ErrUserNotFound), errors.Is(err, service.ErrNotStaff):
+		response.Error(c, http.StatusNotFound, err, err.Error())
+	case errors.Is(err, service.ErrSlugTaken), errors.Is(err, service.ErrAlreadyStaff):
+		response.Error(c, http.StatusConflict, err, err.Error())
+	case errors.Is(err, dbs.ErrStaleVersion):
+		response.StaleVersion(c, err)
+	case errors.Is(err, service.ErrInvalidSlug), errors.Is(err, service.ErrAdminStaff), errors.Is(err, service.ErrRemoveSelf):
+		response.Error(c, http.StatusBadRequest, err, err.Error())
+	default:
+		response.Error(c, http.StatusInternalServerError, err, "Something went wrong")
+	}
+}
@@ -0,0 +1,44 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/quangdangfit/gocommon/validation"
+
+	"main/internal/clinic/repository"
+	"main/internal/clinic/service"
+	"main/pkg/audit"
+	"main/pkg/dbs"
+	"main/pkg/events"
+	"main/pkg/middleware"
+	"main/pkg/rbac"
+)
+
+func Routes(r *gin.RouterGroup, sqlDB dbs.IDatabase, validator validation.Validation, auth *middleware.Authenticator, recorder audit.Recorder, publisher events.Publisher) {
+	clinicRepo := repository.NewClinicRepository(sqlDB)
+	clinicSvc := service.NewClinicService(validator, clinicRepo, auth.Sessions(), recorder, publisher)
+	clinicHandler := NewClinicHandler(clinicSvc)
+
+	clinicsAdmin := middleware.JWTPermission(auth, rbac.ClinicsAdmin)
+	clinicManage := middleware.JWTPermission(auth, rbac.ClinicManage)
+
+	clinicRouteAdmin := r.Group("/clinic-admin")
+	{
+		clinicRouteAdmin.POST("/clinics", clinicsAdmin, clinicHandler.CreateClinic)
+		clinicRouteAdmin.GET("/clinics", clinicsAdmin, clinicHandler.ListClinics)
+		clinicRouteAdmin.GET("/clinics/:id", clinicsAdmin, clinicHandler.GetClinic)
+		clinicRouteAdmin.PUT("/clinics/:id", clinicsAdmin, clinicHandler.UpdateClinic)
+		clinicRouteAdmin.GET("/clinics/:id/staff", clinicsAdmin, clinicHandler.ListStaff)
+		clinicRouteAdmin.POST("/clinics/:id/staff", clinicsAdmin, clinicHandler.AddStaff)
+		clinicRouteAdmin.DELETE("/clinics/:id/staff/:userId", clinicsAdmin, clinicHandler.RemoveStaff)
+	}
+
+	// the clinic of the signed-in clinic admin
+	clinicRoute := r.Group("/clinic")
+	{
+		clinicRoute.GET("", clinicManage, clinicHandler.GetClinic)
+		clinicRoute.PUT("", clinicManage, clinicHandler.UpdateClinic)
+		clinicRoute.GET("/staff", clinicManage, clinicHandler.ListStaff)
+		clinicRoute.POST("/staff", clinicManage, clinicHandler.AddStaff)
+		clinicRoute.DELETE("/staff/:userId", clinicManage, clinicHandler.RemoveStaff)
+	}
+}
@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	addressModel "main/internal/address/model"
+	"main/internal/clinic/model"
+	doctorModel "main/internal/doctor/model"
+	userModel "main/internal/user/model"
+	"main/pkg/dbs"
+	"main/pkg/encryption"
+	"main/pkg/tenant"
+)
+
+//go:generate mockery --name=IClinicRepository
+type IClinicRepository interface {
+	Create(ctx context.Context, clinic *model.Clinic) error
+	Update(ctx context.Context, clinic *model.Clinic) error
+	GetClinicByID(ctx context.Context, id string) (*model.Clinic, error)
+	GetClinicBySlug(ctx context.Context, slug string) (*model.Clinic, error)
+	ListClinics(ctx context.Context) ([]*model.Clinic, error)
+	GetUserByEmail(ctx context.Context, email string) (*userModel.User, error)
+	ListStaff(ctx context.Context, clinicID string) ([]*userModel.User, error)
+	GetStaff(ctx context.Context, clinicID, userID string) (*userModel.User, error)
+	MoveStaff(ctx context.Context, userID, fromClinicID, toClinicID string, role userModel.UserRole) (*Moved, error)
+}
+
+// Moved is what MoveStaff moved along with the user
+type Moved struct {
+	// Sessions are the revoked sessions
+	Sessions []string
+	// DoctorIDs and AddressIDs are the doctor profile and addresses of the
+	// user
+	DoctorIDs  []string
+	AddressIDs []string
+}
+
+type ClinicRepo struct {
+	db dbs.IDatabase
+}
+
+func NewClinicRepository(db dbs.IDatabase) *ClinicRepo {
+	return &ClinicRepo{db: db}
+}
+
+func (r *ClinicRepo) Create(ctx context.Context, clinic *model.Clinic) error {
+	return r.db.GetDB().WithContext(ctx).Create(clinic).Error
+}
+
+// Update saves the clinic if it is still at its version
+func (r *ClinicRepo) Update(ctx context.Context, clinic *model.Clinic) error {
+	return dbs.SaveVersion(r.db.GetDB().WithContext(ctx), clinic)
+}
+
+func (r *ClinicRepo) GetClinicByID(ctx context.Context, id string) (*model.Clinic, error) {
+	var clinic model.Clinic
+	if err := r.db.GetDB().WithContext(ctx).Where("id = ?", id).First(&clinic).Error; err != nil {
+		return nil, err
+	}
+	return &clinic, nil
+}
+
+func (r *ClinicRepo) GetClinicBySlug(ctx context.Context, slug string) (*model.Clinic, error) {
+	var clinic model.Clinic
+	if err := r.db.GetDB().WithContext(ctx).Where("slug = ?", slug).First(&clinic).Error; err != nil {
+		return nil, err
+	}
+	return &clinic, nil
+}
+
+func (r *ClinicRepo) ListClinics(ctx context.Context) ([]*model.Clinic, error) {
+	var clinics []*model.Clinic
+	if err := r.db.GetDB().WithContext(ctx).Order("name").Find(&clinics).Error; err != nil {
+		return nil, err
+	}
+	return clinics, nil
+}
+
+// GetUserByEmail finds the user of email in any clinic
+func (r *ClinicRepo) GetUserByEmail(ctx context.Context, email string) (*userModel.User, error) {
+	var user userModel.User
+	if err := r.db.GetDB().WithContext(tenant.Global(ctx)).Where("email_index = ?", encryption.BlindIndex("email", email)).First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *ClinicRepo) ListStaff(ctx context.Context, clinicID string) ([]*userModel.User, error) {
+	var users []*userModel.User
+	err := r.db.GetDB().WithContext(ctx).
+		Where("tenant_id = ?", clinicID).
+		Order("name").
+		Find(&users).Error
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+func (r *ClinicRepo) GetStaff(ctx context.Context, clinicID, userID string) (*userModel.User, error) {
+	var user userModel.User
+	err := r.db.GetDB().WithContext(ctx).
+		Where("id = ? AND tenant_id = ?", userID, clinicID).
+		First(&user).Error
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// MoveStaff moves the user of fromClinicID, with its doctor profile and
+// addresses, to toClinicID with role and revokes its sessions so its tokens
+// carry the new clinic. It returns nil when the user is not in fromClinicID.
+func (r *ClinicRepo) MoveStaff(ctx context.Context, userID, fromClinicID, toClinicID string, role userModel.UserRole) (*Moved, error) {
+	var moved *Moved
+	var sessions, doctors, addresses []string
+	// the rows change clinic, the scope of ctx would hide them
+	err := r.db.GetDB().WithContext(tenant.Global(ctx)).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&userModel.User{}).
+			Where("id = ? AND tenant_id = ?", userID, fromClinicID).
+			Updates(map[string]interface{}{
+				"tenant_id": toClinicID,
+				"role":      role,
+			})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		err := tx.Model(&doctorModel.Doctor{}).
+			Where("id_user = ?", userID).
+			Pluck("id", &doctors).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Model(&doctorModel.Doctor{}).
+			Where("id_user = ?", userID).
+			Update("tenant_id", toClinicID).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Model(&addressModel.Address{}).
+			Where("id_user = ?", userID).
+			Pluck("id", &addresses).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Model(&addressModel.Address{}).
+			Where("id_user = ?", userID).
+			Update("tenant_id", toClinicID).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Model(&userModel.Session{}).
+			Where("user_id = ? AND revoked_at IS NULL", userID).
+			Pluck("id", &sessions).Error
+		if err != nil {
+			return err
+		}
+		if len(sessions) > 0 {
+			err = tx.Model(&userModel.Session{}).
+				Where("id IN ?", sessions).
+				Updates(map[string]interface{}{
+					"tenant_id":  toClinicID,
+					"revoked_at": time.Now(),
+				}).Error
+			if err != nil {
+				return err
+			}
+		}
+		moved = &Moved{Sessions: sessions, DoctorIDs: doctors, AddressIDs: addresses}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return moved, nil
+}
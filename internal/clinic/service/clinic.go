@@ -0,0 +1,252 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"time"
+
+	"github.com/quangdangfit/gocommon/logger"
+	"github.com/quangdangfit/gocommon/validation"
+	"gorm.io/gorm"
+
+	"main/internal/clinic/dto"
+	"main/internal/clinic/model"
+	"main/internal/clinic/repository"
+	userModel "main/internal/user/model"
+	"main/pkg/audit"
+	"main/pkg/events"
+	"main/pkg/session"
+)
+
+var (
+	ErrClinicNotFound = errors.New("clinic not found")
+	ErrUserNotFound   = errors.New("user not found")
+	ErrSlugTaken      = errors.New("slug already used by another clinic")
+	ErrInvalidSlug    = errors.New("slug must be lower case words separated by dashes")
+	ErrAlreadyStaff   = errors.New("user already belongs to a clinic")
+	ErrAdminStaff     = errors.New("admins cannot join a clinic")
+	ErrNotStaff       = errors.New("user is not staff of the clinic")
+	ErrRemoveSelf     = errors.New("clinic admins cannot remove themselves")
+)
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+//go:generate mockery --name=IClinicService
+type IClinicService interface {
+	Create(ctx context.Context, req *dto.CreateClinicReq) (*model.Clinic, error)
+	Update(ctx context.Context, id string, req *dto.UpdateClinicReq) (*model.Clinic, error)
+	GetClinicByID(ctx context.Context, id string) (*model.Clinic, error)
+	ListClinics(ctx context.Context) ([]*model.Clinic, error)
+	ListStaff(ctx context.Context, clinicID string) ([]*userModel.User, error)
+	AddStaff(ctx context.Context, clinicID string, req *dto.AddStaffReq) (*userModel.User, error)
+	RemoveStaff(ctx context.Context, clinicID, actorID, userID string) error
+}
+
+type ClinicService struct {
+	validator validation.Validation
+	repo      repository.IClinicRepository
+	sessions  *session.Store
+	recorder  audit.Recorder
+	publisher events.Publisher
+}
+
+func NewClinicService(
+	validator validation.Validation,
+	repo repository.IClinicRepository,
+	sessions *session.Store,
+	recorder audit.Recorder,
+	publisher events.Publisher,
+) *ClinicService {
+	return &ClinicService{
+		validator: validator,
+		repo:      repo,
+		sessions:  sessions,
+		recorder:  recorder,
+		publisher: publisher,
+	}
+}
+
+// staffRole is what a role change records of the user
+type staffRole struct {
+	Role     userModel.UserRole `json:"role"`
+	TenantID string             `json:"tenant_id"`
+}
+
+func (s *ClinicService) Create(ctx context.Context, req *dto.CreateClinicReq) (*model.Clinic, error) {
+	if err := s.validator.ValidateStruct(req); err != nil {
+		return nil, err
+	}
+	if !slugPattern.MatchString(req.Slug) {
+		return nil, ErrInvalidSlug
+	}
+	if _, err := s.repo.GetClinicBySlug(ctx, req.Slug); err == nil {
+		return nil, ErrSlugTaken
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	clinic := model.Clinic{
+		Name:  req.Name,
+		Slug:  req.Slug,
+		Email: req.Email,
+		Phone: req.Phone,
+	}
+	clinic.BeforeCreate()
+	if err := s.repo.Create(ctx, &clinic); err != nil {
+		logger.Errorf("Create fail, slug: %s, error: %s", req.Slug, err)
+		return nil, err
+	}
+
+	return &clinic, nil
+}
+
+func (s *ClinicService) Update(ctx context.Context, id string, req *dto.UpdateClinicReq) (*model.Clinic, error) {
+	if err := s.validator.ValidateStruct(req); err != nil {
+		return nil, err
+	}
+
+	clinic, err := s.GetClinicByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	clinic.Name = req.Name
+	clinic.Email = req.Email
+	clinic.Phone = req.Phone
+	clinic.Version = req.Version
+	clinic.UpdatedAt = time.Now()
+	if err := s.repo.Update(ctx, clinic); err != nil {
+		logger.Errorf("Update fail, id: %s, error: %s", id, err)
+		return nil, err
+	}
+
+	return clinic, nil
+}
+
+func (s *ClinicService) GetClinicByID(ctx context.Context, id string) (*model.Clinic, error) {
+	clinic, err := s.repo.GetClinicByID(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrClinicNotFound
+	}
+	if err != nil {
+		logger.Errorf("GetClinicByID fail, id: %s, error: %s", id, err)
+		return nil, err
+	}
+	return clinic, nil
+}
+
+func (s *ClinicService) ListClinics(ctx context.Context) ([]*model.Clinic, error) {
+	return s.repo.ListClinics(ctx)
+}
+
+func (s *ClinicService) ListStaff(ctx context.Context, clinicID string) ([]*userModel.User, error) {
+	if _, err := s.GetClinicByID(ctx, clinicID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListStaff(ctx, clinicID)
+}
+
+// AddStaff adds a registered user of no clinic to clinicID, its doctor
+// profile and addresses follow it. The user signs in again to act for the
+// clinic.
+func (s *ClinicService) AddStaff(ctx context.Context, clinicID string, req *dto.AddStaffReq) (*userModel.User, error) {
+	if err := s.validator.ValidateStruct(req); err != nil {
+		return nil, err
+	}
+	if _, err := s.GetClinicByID(ctx, clinicID); err != nil {
+		return nil, err
+	}
+
+	user, err := s.repo.GetUserByEmail(ctx, req.Email)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		logger.Errorf("AddStaff.GetUserByEmail fail, email: %s, error: %s", req.Email, err)
+		return nil, err
+	}
+	if user.Role == userModel.UserRoleAdmin {
+		return nil, ErrAdminStaff
+	}
+	if user.TenantID != "" {
+		return nil, ErrAlreadyStaff
+	}
+
+	moved, err := s.repo.MoveStaff(ctx, user.ID, "", clinicID, req.Role)
+	if err != nil {
+		logger.Errorf("AddStaff.MoveStaff fail, id: %s, error: %s", user.ID, err)
+		return nil, err
+	}
+	if moved == nil {
+		return nil, ErrAlreadyStaff
+	}
+	s.recorder.Record(ctx, &audit.Event{
+		Action:     audit.RoleChange,
+		TargetType: audit.TargetUser,
+		TargetID:   user.ID,
+		Before:     staffRole{Role: user.Role},
+		After:      staffRole{Role: req.Role, TenantID: clinicID},
+	})
+
+	s.publish(ctx, user.ID, moved)
+
+	user.TenantID = clinicID
+	user.Role = req.Role
+	s.sessions.Revoke(ctx, moved.Sessions...)
+	return user, nil
+}
+
+// RemoveStaff removes userID from clinicID, a clinic admin becomes a client
+// and a doctor an independent one
+func (s *ClinicService) RemoveStaff(ctx context.Context, clinicID, actorID, userID string) error {
+	if actorID == userID {
+		return ErrRemoveSelf
+	}
+
+	user, err := s.repo.GetStaff(ctx, clinicID, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrNotStaff
+	}
+	if err != nil {
+		logger.Errorf("RemoveStaff.GetStaff fail, id: %s, error: %s", userID, err)
+		return err
+	}
+	role := user.Role
+	if role == userModel.UserRoleClinicAdmin {
+		role = userModel.UserRoleClient
+	}
+
+	moved, err := s.repo.MoveStaff(ctx, userID, clinicID, "", role)
+	if err != nil {
+		logger.Errorf("RemoveStaff.MoveStaff fail, id: %s, error: %s", userID, err)
+		return err
+	}
+	if moved == nil {
+		return ErrNotStaff
+	}
+	s.recorder.Record(ctx, &audit.Event{
+		Action:     audit.RoleChange,
+		TargetType: audit.TargetUser,
+		TargetID:   userID,
+		Before:     staffRole{Role: user.Role, TenantID: clinicID},
+		After:      staffRole{Role: role},
+	})
+	s.publish(ctx, userID, moved)
+
+	s.sessions.Revoke(ctx, moved.Sessions...)
+	return nil
+}
+
+// publish tells the subscribers that the user, its doctor profile and its
+// addresses changed clinic
+func (s *ClinicService) publish(ctx context.Context, userID string, moved *repository.Moved) {
+	change := &events.Change{Action: events.ActionUpdated, UserID: userID}
+	s.publisher.Publish(ctx, events.New(ctx, events.UserUpdated, userID, change))
+	for _, id := range moved.DoctorIDs {
+		s.publisher.Publish(ctx, events.New(ctx, events.DoctorUpdated, id, change))
+	}
+	for _, id := range moved.AddressIDs {
+		s.publisher.Publish(ctx, events.New(ctx, events.AddressChanged, id, change))
+	}
+}
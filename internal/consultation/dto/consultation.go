@@ -0,0 +1,115 @@
+package dto
+
+import (
+	"time"
+
+	"main/pkg/paging"
+)
+
+// swagger:model Diagnosis
+type Diagnosis struct {
+	// example: "J06.9"
+	Code string `json:"code" validate:"max=20"`
+	// example: "Acute upper respiratory infection"
+	Description string `json:"description" validate:"required,max=500"`
+}
+
+// swagger:model PrescribedMedication
+type PrescribedMedication struct {
+	// example: "Amoxicillin 500 mg"
+	Name string `json:"name" validate:"required,max=200"`
+	// example: "1 capsule"
+	Dosage string `json:"dosage" validate:"required,max=100"`
+	// example: "3 times a day"
+	Frequency string `json:"frequency" validate:"required,max=100"`
+	// example: 7
+	DurationDays int `json:"duration_days" validate:"gte=1,lte=365"`
+	// example: "With food"
+	Instructions string `json:"instructions" validate:"max=500"`
+}
+
+// swagger:model Amendment
+type Amendment struct {
+	ID        string    `json:"id"`
+	AuthorID  string    `json:"author_id"`
+	Reason    string    `json:"reason"`
+	Text      string    `json:"text"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// swagger:model Consultation
+type Consultation struct {
+	ID          string                  `json:"id"`
+	DoctorID    string                  `json:"doctor_id"`
+	PatientID   string                  `json:"patient_id"`
+	VisitedAt   time.Time               `json:"visited_at"`
+	Subjective  string                  `json:"subjective"`
+	Objective   string                  `json:"objective"`
+	Assessment  string                  `json:"assessment"`
+	Plan        string                  `json:"plan"`
+	Diagnoses   []*Diagnosis            `json:"diagnoses"`
+	Medications []*PrescribedMedication `json:"medications"`
+	// example: "signed"
+	Status      string       `json:"status"`
+	SignedAt    *time.Time   `json:"signed_at"`
+	ContentHash string       `json:"content_hash"`
+	Amendments  []*Amendment `json:"amendments"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+	// Version of the consultation, also sent as its ETag
+	Version int64 `json:"version"`
+}
+
+// swagger:model CreateConsultationReq
+type CreateConsultationReq struct {
+	PatientID string `json:"patient_id" validate:"required"`
+	UpdateConsultationReq
+}
+
+// UpdateConsultationReq replaces the content of a draft consultation
+// swagger:model UpdateConsultationReq
+type UpdateConsultationReq struct {
+	// The creation time by default
+	VisitedAt   *time.Time              `json:"visited_at"`
+	Subjective  string                  `json:"subjective" validate:"max=10000"`
+	Objective   string                  `json:"objective" validate:"max=10000"`
+	Assessment  string                  `json:"assessment" validate:"max=10000"`
+	Plan        string                  `json:"plan" validate:"max=10000"`
+	Diagnoses   []*Diagnosis            `json:"diagnoses" validate:"max=20,dive,required"`
+	Medications []*PrescribedMedication `json:"medications" validate:"max=30,dive,required"`
+	// Version the change applies to, from If-Match over HTTP
+	Version int64 `json:"-"`
+}
+
+// swagger:model AmendConsultationReq
+type AmendConsultationReq struct {
+	// example: "Wrong dosage noted"
+	Reason string `json:"reason" validate:"required,max=500"`
+	Text   string `json:"text" validate:"required,max=10000"`
+}
+
+type ListConsultationsReq struct {
+	// PatientID filters the consultations of a doctor
+	PatientID string `json:"patient_id,omitempty" form:"patient_id"`
+	Page      int64  `json:"page,omitempty" form:"page"`
+	Limit     int64  `json:"limit,omitempty" form:"limit"`
+}
+
+// swagger:model ListConsultationsRes
+type ListConsultationsRes struct {
+	Consultations []*Consultation    `json:"consultations"`
+	Pagination    *paging.Pagination `json:"pagination"`
+}
+
+// PrescriptionVerification is what a pharmacy sees of a prescription, the
+// medications only when the signature is valid
+// swagger:model PrescriptionVerification
+type PrescriptionVerification struct {
+	Valid         bool                    `json:"valid"`
+	Code          string                  `json:"code"`
+	IssuedAt      time.Time               `json:"issued_at"`
+	DoctorName    string                  `json:"doctor_name"`
+	LicenseNumber string                  `json:"license_number"`
+	PatientName   string                  `json:"patient_name"`
+	Medications   []*PrescribedMedication `json:"medications,omitempty"`
+}
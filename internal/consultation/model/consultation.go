@@ -0,0 +1,154 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	ConsultationDraft = "draft"
+	// ConsultationSigned consultations are immutable, they are corrected with
+	// amendments
+	ConsultationSigned = "signed"
+)
+
+// Consultation is the record of a visit written by the doctor as SOAP notes
+type Consultation struct {
+	ID        string    `json:"id" gorm:"unique;not null;index;primary_key"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	// TenantID is the clinic of the doctor
+	TenantID  string    `json:"tenant_id" gorm:"not null;default:'';index"`
+	DoctorID  string    `json:"doctor_id" gorm:"not null;index"`
+	PatientID string    `json:"patient_id" gorm:"not null;index"`
+	VisitedAt time.Time `json:"visited_at"`
+	// SOAP notes, encrypted like the diagnoses
+	Subjective string     `json:"subjective" gorm:"type:text;serializer:encrypted"`
+	Objective  string     `json:"objective" gorm:"type:text;serializer:encrypted"`
+	Assessment string     `json:"assessment" gorm:"type:text;serializer:encrypted"`
+	Plan       string     `json:"plan" gorm:"type:text;serializer:encrypted"`
+	Diagnoses  Diagnoses  `json:"diagnoses" gorm:"type:text;serializer:encrypted"`
+	Status     string     `json:"status" gorm:"not null;default:draft;index"`
+	SignedAt   *time.Time `json:"signed_at"`
+	// ContentHash is the sha256 of the content when signed
+	ContentHash string        `json:"content_hash"`
+	Medications []*Medication `json:"medications" gorm:"foreignKey:ConsultationID;constraint:OnDelete:CASCADE"`
+	Amendments  []*Amendment  `json:"amendments" gorm:"foreignKey:ConsultationID;constraint:OnDelete:CASCADE"`
+	// Version is bumped by every change, it is the ETag of the consultation
+	Version int64 `json:"version" gorm:"not null;default:1"`
+}
+
+func (Consultation) TableName() string {
+	return "consultations"
+}
+
+func (m *Consultation) BeforeCreate() error {
+	m.ID = uuid.New().String()
+	m.CreatedAt = time.Now()
+	m.Status = ConsultationDraft
+	return nil
+}
+
+// Medication is prescribed in a consultation
+type Medication struct {
+	ID             string `json:"id" gorm:"unique;not null;index;primary_key"`
+	ConsultationID string `json:"consultation_id" gorm:"not null;index"`
+	// Position keeps the order of the prescription
+	Position     int    `json:"position"`
+	Name         string `json:"name" gorm:"not null;type:text;serializer:encrypted"`
+	Dosage       string `json:"dosage" gorm:"type:text;serializer:encrypted"`
+	Frequency    string `json:"frequency" gorm:"type:text;serializer:encrypted"`
+	DurationDays int    `json:"duration_days"`
+	Instructions string `json:"instructions" gorm:"type:text;serializer:encrypted"`
+}
+
+func (Medication) TableName() string {
+	return "consultation_medications"
+}
+
+func (m *Medication) BeforeCreate() error {
+	m.ID = uuid.New().String()
+	return nil
+}
+
+// Amendment corrects a signed consultation, amendments are append only
+type Amendment struct {
+	ID             string    `json:"id" gorm:"unique;not null;index;primary_key"`
+	ConsultationID string    `json:"consultation_id" gorm:"not null;index"`
+	CreatedAt      time.Time `json:"created_at"`
+	// AuthorID is the user who wrote the amendment
+	AuthorID string `json:"author_id" gorm:"not null"`
+	Reason   string `json:"reason" gorm:"type:text;serializer:encrypted"`
+	Text     string `json:"text" gorm:"type:text;serializer:encrypted"`
+}
+
+func (Amendment) TableName() string {
+	return "consultation_amendments"
+}
+
+func (m *Amendment) BeforeCreate() error {
+	m.ID = uuid.New().String()
+	m.CreatedAt = time.Now()
+	return nil
+}
+
+// Prescription is issued when a consultation with medications is signed, the
+// names are copied so the prescription does not change with the profiles
+type Prescription struct {
+	ID             string    `json:"id" gorm:"unique;not null;index;primary_key"`
+	ConsultationID string    `json:"consultation_id" gorm:"not null;uniqueIndex"`
+	TenantID       string    `json:"tenant_id" gorm:"not null;default:'';index"`
+	IssuedAt       time.Time `json:"issued_at"`
+	// Code identifies the prescription for the pharmacies
+	Code          string `json:"code" gorm:"not null;uniqueIndex"`
+	DoctorName    string `json:"doctor_name"`
+	LicenseNumber string `json:"license_number"`
+	PatientName   string `json:"patient_name" gorm:"type:text;serializer:encrypted"`
+	// Signature is the hmac of the content of the prescription
+	Signature string `json:"signature"`
+}
+
+func (Prescription) TableName() string {
+	return "prescriptions"
+}
+
+func (m *Prescription) BeforeCreate() error {
+	m.ID = uuid.New().String()
+	m.IssuedAt = time.Now()
+	return nil
+}
+
+// Diagnosis is coded, with ICD-10 for instance
+type Diagnosis struct {
+	Code        string `json:"code"`
+	Description string `json:"description"`
+}
+
+// Diagnoses are stored as json
+type Diagnoses []Diagnosis
+
+func (d Diagnoses) Value() (driver.Value, error) {
+	if d == nil {
+		return "[]", nil
+	}
+	b, err := json.Marshal([]Diagnosis(d))
+	return string(b), err
+}
+
+func (d *Diagnoses) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case string:
+		return json.Unmarshal([]byte(v), d)
+	case []byte:
+		return json.Unmarshal(v, d)
+	case nil:
+		*d = nil
+		return nil
+	default:
+		return fmt.Errorf("cannot scan %T into Diagnoses", value)
+	}
+}
@@ -0,0 +1,295 @@
+package http
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/quangdangfit/gocommon/logger"
+
+	"main/internal/consultation/dto"
+	"main/internal/consultation/service"
+	"main/pkg/dbs"
+	"main/pkg/response"
+	"main/pkg/utils"
+)
+
+// consultations are medical records, they are never cached
+type ConsultationHandler struct {
+	service service.IConsultationService
+}
+
+func NewConsultationHandler(service service.IConsultationService) *ConsultationHandler {
+	return &ConsultationHandler{service: service}
+}
+
+// CreateConsultation godoc
+//
+//	@Summary	Start a draft consultation as the signed-in doctor
+//	@Tags		Consultation
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		_	body		dto.CreateConsultationReq	true	"Body"
+//	@Success	200	{object}	dto.Consultation
+//	@Router		/consultations [post]
+func (h *ConsultationHandler) CreateConsultation(c *gin.Context) {
+	var req dto.CreateConsultationReq
+	if err := c.ShouldBindJSON(&req); c.Request.Body == nil || err != nil {
+		logger.Error("Failed to get body", err)
+		response.Error(c, http.StatusBadRequest, err, "Invalid parameters")
+		return
+	}
+
+	consultation, err := h.service.Create(c, c.GetString("userId"), &req)
+	if err != nil {
+		logger.Error("Failed to create consultation ", err)
+		consultationError(c, err)
+		return
+	}
+
+	var res dto.Consultation
+	utils.Copy(&res, consultation)
+	response.Versioned(c, http.StatusOK, res.Version, res)
+}
+
+// UpdateConsultation godoc
+//
+//	@Summary	Replace the content of a draft consultation
+//	@Tags		Consultation
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		id	path		string						true	"Consultation ID"
+//	@Param		If-Match	header	string	true	"ETag of the consultation, its version"
+//	@Param		_	body		dto.UpdateConsultationReq	true	"Body"
+//	@Success	200	{object}	dto.Consultation
+//	@Failure	412	{object}	response.Response	"Changed since it was read"
+//	@Router		/consultations/{id} [put]
+func (h *ConsultationHandler) UpdateConsultation(c *gin.Context) {
+	version, ok := response.IfMatch(c)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateConsultationReq
+	if err := c.ShouldBindJSON(&req); c.Request.Body == nil || err != nil {
+		logger.Error("Failed to get body", err)
+		response.Error(c, http.StatusBadRequest, err, "Invalid parameters")
+		return
+	}
+
+	req.Version = version
+	consultation, err := h.service.Update(c, c.GetString("userId"), c.Param("id"), &req)
+	if err != nil {
+		logger.Error("Failed to update consultation ", err)
+		consultationError(c, err)
+		return
+	}
+
+	var res dto.Consultation
+	utils.Copy(&res, consultation)
+	response.Versioned(c, http.StatusOK, res.Version, res)
+}
+
+// SignConsultation godoc
+//
+//	@Summary	Sign a consultation, it cannot be changed afterwards and its prescription is issued
+//	@Tags		Consultation
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		id	path		string	true	"Consultation ID"
+//	@Param		If-Match	header	string	true	"ETag of the consultation read before signing, its version"
+//	@Success	200	{object}	dto.Consultation
+//	@Failure	412	{object}	response.Response	"Changed since it was read"
+//	@Router		/consultations/{id}/sign [post]
+func (h *ConsultationHandler) SignConsultation(c *gin.Context) {
+	version, ok := response.IfMatch(c)
+	if !ok {
+		return
+	}
+
+	consultation, err := h.service.Sign(c, c.GetString("userId"), c.Param("id"), version)
+	if err != nil {
+		logger.Error("Failed to sign consultation ", err)
+		consultationError(c, err)
+		return
+	}
+
+	var res dto.Consultation
+	utils.Copy(&res, consultation)
+	response.Versioned(c, http.StatusOK, res.Version, res)
+}
+
+// AmendConsultation godoc
+//
+//	@Summary	Add an amendment to a signed consultation
+//	@Tags		Consultation
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		id	path		string						true	"Consultation ID"
+//	@Param		_	body		dto.AmendConsultationReq	true	"Body"
+//	@Success	200	{object}	dto.Amendment
+//	@Router		/consultations/{id}/amendments [post]
+func (h *ConsultationHandler) AmendConsultation(c *gin.Context) {
+	var req dto.AmendConsultationReq
+	if err := c.ShouldBindJSON(&req); c.Request.Body == nil || err != nil {
+		logger.Error("Failed to get body", err)
+		response.Error(c, http.StatusBadRequest, err, "Invalid parameters")
+		return
+	}
+
+	amendment, err := h.service.Amend(c, c.GetString("userId"), c.Param("id"), &req)
+	if err != nil {
+		logger.Error("Failed to amend consultation ", err)
+		consultationError(c, err)
+		return
+	}
+
+	var res dto.Amendment
+	utils.Copy(&res, amendment)
+	response.JSON(c, http.StatusOK, res)
+}
+
+// GetConsultation godoc
+//
+//	@Summary	Get a consultation, as its doctor or its patient once signed
+//	@Tags		Consultation
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		id	path		string	true	"Consultation ID"
+//	@Success	200	{object}	dto.Consultation
+//	@Router		/consultations/{id} [get]
+func (h *ConsultationHandler) GetConsultation(c *gin.Context) {
+	consultation, err := h.service.GetConsultation(c, c.GetString("userId"), c.Param("id"))
+	if err != nil {
+		logger.Error("Failed to get consultation ", err)
+		consultationError(c, err)
+		return
+	}
+
+	var res dto.Consultation
+	utils.Copy(&res, consultation)
+	response.Versioned(c, http.StatusOK, res.Version, res)
+}
+
+// ListOwnConsultations godoc
+//
+//	@Summary	List the signed consultations of the signed-in patient
+//	@Tags		Consultation
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		page	query		int	false	"Page"
+//	@Param		limit	query		int	false	"Limit"
+//	@Success	200		{object}	dto.ListConsultationsRes
+//	@Router		/consultations [get]
+func (h *ConsultationHandler) ListOwnConsultations(c *gin.Context) {
+	var req dto.ListConsultationsReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.Error("Failed to get query params", err)
+		response.Error(c, http.StatusBadRequest, err, "Invalid parameters")
+		return
+	}
+
+	consultations, pagination, err := h.service.ListOwnConsultations(c, c.GetString("userId"), &req)
+	if err != nil {
+		logger.Error("Failed to list consultations ", err)
+		consultationError(c, err)
+		return
+	}
+
+	var res dto.ListConsultationsRes
+	utils.Copy(&res.Consultations, &consultations)
+	res.Pagination = pagination
+	response.JSON(c, http.StatusOK, res)
+}
+
+// ListDoctorConsultations godoc
+//
+//	@Summary	List the consultations written by the signed-in doctor
+//	@Tags		Consultation
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		patient_id	query		string	false	"Patient ID"
+//	@Param		page		query		int		false	"Page"
+//	@Param		limit		query		int		false	"Limit"
+//	@Success	200			{object}	dto.ListConsultationsRes
+//	@Router		/doctor/consultations [get]
+func (h *ConsultationHandler) ListDoctorConsultations(c *gin.Context) {
+	var req dto.ListConsultationsReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.Error("Failed to get query params", err)
+		response.Error(c, http.StatusBadRequest, err, "Invalid parameters")
+		return
+	}
+
+	consultations, pagination, err := h.service.ListDoctorConsultations(c, c.GetString("userId"), &req)
+	if err != nil {
+		logger.Error("Failed to list consultations ", err)
+		consultationError(c, err)
+		return
+	}
+
+	var res dto.ListConsultationsRes
+	utils.Copy(&res.Consultations, &consultations)
+	res.Pagination = pagination
+	response.JSON(c, http.StatusOK, res)
+}
+
+// DownloadPrescription godoc
+//
+//	@Summary	Download the signed prescription of a consultation as a pdf
+//	@Tags		Consultation
+//	@Security	ApiKeyAuth
+//	@Produce	application/pdf
+//	@Param		id	path	string	true	"Consultation ID"
+//	@Success	200	{file}	file
+//	@Router		/consultations/{id}/prescription [get]
+func (h *ConsultationHandler) DownloadPrescription(c *gin.Context) {
+	prescription, content, err := h.service.GetPrescriptionPDF(c, c.GetString("userId"), c.Param("id"))
+	if err != nil {
+		logger.Error("Failed to get prescription ", err)
+		consultationError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", mime.FormatMediaType("inline", map[string]string{
+		"filename": "prescription-" + prescription.Code + ".pdf",
+	}))
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "application/pdf", content)
+}
+
+// VerifyPrescription godoc
+//
+//	@Summary	Verify a prescription with the signature of its QR code or its printed fingerprint
+//	@Tags		Prescription
+//	@Produce	json
+//	@Param		code	path		string	true	"Prescription code"
+//	@Param		sig		query		string	true	"Signature or fingerprint"
+//	@Success	200		{object}	dto.PrescriptionVerification
+//	@Router		/prescriptions/verify/{code} [get]
+func (h *ConsultationHandler) VerifyPrescription(c *gin.Context) {
+	res, err := h.service.VerifyPrescription(c, c.Param("code"), c.Query("sig"))
+	if err != nil {
+		logger.Error("Failed to verify prescription ", err)
+		consultationError(c, err)
+		return
+	}
+
+	response.JSON(c, http.StatusOK, res)
+}
+
+func consultationError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, dbs.ErrStaleVersion):
+		response.StaleVersion(c, err)
+	case errors.Is(err, service.ErrNotDoctor), errors.Is(err, service.ErrNotAuthor), errors.Is(err, service.ErrDoctorNotVerified):
+		response.Error(c, http.StatusForbidden, err, err.Error())
+	case errors.Is(err, service.ErrConsultationNotFound), errors.Is(err, service.ErrPrescriptionNotFound), errors.Is(err, service.ErrPatientNotFound):
+		response.Error(c, http.StatusNotFound, err, err.Error())
+	case errors.Is(err, service.ErrSigned), errors.Is(err, service.ErrNotSigned):
+		response.Error(c, http.StatusConflict, err, err.Error())
+	default:
+		response.Error(c, http.StatusBadRequest, err, err.Error())
+	}
+}
@@ -0,0 +1,50 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/quangdangfit/gocommon/validation"
+
+	"main/internal/consultation/repository"
+	"main/internal/consultation/service"
+	doctorRepository "main/internal/doctor/repository"
+	userRepository "main/internal/user/repository"
+	"main/pkg/config"
+	"main/pkg/dbs"
+	"main/pkg/middleware"
+)
+
+func Routes(r *gin.RouterGroup, sqlDB dbs.IDatabase, validator validation.Validation, auth *middleware.Authenticator) {
+	cfg := config.GetConfig()
+	signingKey := cfg.PrescriptionSigningKey
+	if signingKey == "" {
+		signingKey = cfg.AuthSecret
+	}
+
+	consultationRepo := repository.NewConsultationRepository(sqlDB)
+	consultationSvc := service.NewConsultationService(
+		validator,
+		consultationRepo,
+		doctorRepository.NewDoctorRepository(sqlDB),
+		userRepository.NewUserRepository(sqlDB),
+		service.NewPrescriptionSigner(signingKey, cfg.PrescriptionVerifyURL),
+	)
+	consultationHandler := NewConsultationHandler(consultationSvc)
+
+	// the doctor who wrote the consultation, or its patient once signed
+	userAuthMiddleware := middleware.JWTAuth(auth)
+	consultationRoute := r.Group("/consultations")
+	{
+		consultationRoute.GET("", userAuthMiddleware, consultationHandler.ListOwnConsultations)
+		consultationRoute.POST("", userAuthMiddleware, consultationHandler.CreateConsultation)
+		consultationRoute.GET("/:id", userAuthMiddleware, consultationHandler.GetConsultation)
+		consultationRoute.PUT("/:id", userAuthMiddleware, consultationHandler.UpdateConsultation)
+		consultationRoute.POST("/:id/sign", userAuthMiddleware, consultationHandler.SignConsultation)
+		consultationRoute.POST("/:id/amendments", userAuthMiddleware, consultationHandler.AmendConsultation)
+		consultationRoute.GET("/:id/prescription", userAuthMiddleware, consultationHandler.DownloadPrescription)
+	}
+
+	r.Group("/doctor").GET("/consultations", userAuthMiddleware, consultationHandler.ListDoctorConsultations)
+
+	// pharmacies verify the prescriptions without an account
+	r.Group("/prescriptions").GET("/verify/:code", consultationHandler.VerifyPrescription)
+}
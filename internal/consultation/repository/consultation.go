@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"main/internal/consultation/dto"
+	"main/internal/consultation/model"
+	"main/pkg/config"
+	"main/pkg/dbs"
+	"main/pkg/paging"
+)
+
+//go:generate mockery --name=IConsultationRepository
+type IConsultationRepository interface {
+	Create(ctx context.Context, consultation *model.Consultation) error
+	Update(ctx context.Context, consultation *model.Consultation) (bool, error)
+	Sign(ctx context.Context, consultation *model.Consultation, prescription *model.Prescription) (bool, error)
+	AddAmendment(ctx context.Context, amendment *model.Amendment) error
+	GetConsultationByID(ctx context.Context, id string) (*model.Consultation, error)
+	ListConsultations(ctx context.Context, doctorID string, signedOnly bool, req *dto.ListConsultationsReq) ([]*model.Consultation, *paging.Pagination, error)
+	GetPrescription(ctx context.Context, consultationID string) (*model.Prescription, error)
+	GetPrescriptionByCode(ctx context.Context, code string) (*model.Prescription, error)
+}
+
+type ConsultationRepo struct {
+	db dbs.IDatabase
+}
+
+func NewConsultationRepository(db dbs.IDatabase) *ConsultationRepo {
+	return &ConsultationRepo{db: db}
+}
+
+func (r *ConsultationRepo) Create(ctx context.Context, consultation *model.Consultation) error {
+	return r.db.GetDB().WithContext(ctx).Omit("Amendments").Create(consultation).Error
+}
+
+// Update replaces the notes and medications of a draft consultation still at
+// its version and bumps it, false when it was changed or signed meanwhile
+func (r *ConsultationRepo) Update(ctx context.Context, consultation *model.Consultation) (bool, error) {
+	updated := false
+	version := consultation.Version
+	consultation.Version++
+	err := r.db.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.Consultation{}).
+			Where("id = ? AND status = ? AND version = ?", consultation.ID, model.ConsultationDraft, version).
+			Select("UpdatedAt", "VisitedAt", "Subjective", "Objective", "Assessment", "Plan", "Diagnoses", "Version").
+			Updates(consultation)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		if err := tx.Where("consultation_id = ?", consultation.ID).Delete(&model.Medication{}).Error; err != nil {
+			return err
+		}
+		if len(consultation.Medications) > 0 {
+			if err := tx.Create(consultation.Medications).Error; err != nil {
+				return err
+			}
+		}
+		updated = true
+		return nil
+	})
+	if !updated {
+		consultation.Version = version
+	}
+	return updated, err
+}
+
+// Sign signs a draft consultation still at its version and issues its
+// prescription if any, false when it was changed or signed meanwhile
+func (r *ConsultationRepo) Sign(ctx context.Context, consultation *model.Consultation, prescription *model.Prescription) (bool, error) {
+	signed := false
+	err := r.db.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// the version is bumped by the versioning callback
+		result := tx.Model(&model.Consultation{}).
+			Where("id = ? AND status = ? AND version = ?", consultation.ID, model.ConsultationDraft, consultation.Version).
+			Updates(map[string]interface{}{
+				"status":       model.ConsultationSigned,
+				"signed_at":    consultation.SignedAt,
+				"content_hash": consultation.ContentHash,
+				"updated_at":   consultation.UpdatedAt,
+			})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		if prescription != nil {
+			if err := tx.Create(prescription).Error; err != nil {
+				return err
+			}
+		}
+		signed = true
+		return nil
+	})
+	if signed {
+		consultation.Version++
+	}
+	return signed, err
+}
+
+func (r *ConsultationRepo) AddAmendment(ctx context.Context, amendment *model.Amendment) error {
+	return r.db.GetDB().WithContext(ctx).Create(amendment).Error
+}
+
+func (r *ConsultationRepo) GetConsultationByID(ctx context.Context, id string) (*model.Consultation, error) {
+	var consultation model.Consultation
+	err := r.db.GetDB().WithContext(ctx).
+		Preload("Medications", func(db *gorm.DB) *gorm.DB { return db.Order("position") }).
+		Preload("Amendments", func(db *gorm.DB) *gorm.DB { return db.Order("created_at") }).
+		Where("id = ?", id).
+		First(&consultation).Error
+	if err != nil {
+		return nil, err
+	}
+	return &consultation, nil
+}
+
+// ListConsultations lists the consultations of the doctor, or of the patient
+// of req when doctorID is empty, latest visits first
+func (r *ConsultationRepo) ListConsultations(ctx context.Context, doctorID string, signedOnly bool, req *dto.ListConsultationsReq) ([]*model.Consultation, *paging.Pagination, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.DatabaseTimeout)
+	defer cancel()
+
+	var query []dbs.Query
+	if doctorID != "" {
+		query = append(query, dbs.NewQuery("doctor_id = ?", doctorID))
+	}
+	if req.PatientID != "" {
+		query = append(query, dbs.NewQuery("patient_id = ?", req.PatientID))
+	}
+	if signedOnly {
+		query = append(query, dbs.NewQuery("status = ?", model.ConsultationSigned))
+	}
+
+	var total int64
+	if err := r.db.Count(ctx, &model.Consultation{}, &total, dbs.WithQuery(query...)); err != nil {
+		return nil, nil, err
+	}
+
+	pagination := paging.New(req.Page, req.Limit, total)
+
+	var consultations []*model.Consultation
+	if err := r.db.Find(
+		ctx,
+		&consultations,
+		dbs.WithQuery(query...),
+		dbs.WithLimit(int(pagination.Limit)),
+		dbs.WithOffset(int(pagination.Skip)),
+		dbs.WithOrder("visited_at DESC"),
+		dbs.WithPreload([]string{"Medications"}),
+	); err != nil {
+		return nil, nil, err
+	}
+
+	return consultations, pagination, nil
+}
+
+func (r *ConsultationRepo) GetPrescription(ctx context.Context, consultationID string) (*model.Prescription, error) {
+	var prescription model.Prescription
+	if err := r.db.GetDB().WithContext(ctx).Where("consultation_id = ?", consultationID).First(&prescription).Error; err != nil {
+		return nil, err
+	}
+	return &prescription, nil
+}
+
+func (r *ConsultationRepo) GetPrescriptionByCode(ctx context.Context, code string) (*model.Prescription, error) {
+	var prescription model.Prescription
+	if err := r.db.GetDB().WithContext(ctx).Where("code = ?", code).First(&prescription).Error; err != nil {
+		return nil, err
+	}
+	return &prescription, nil
+}
@@ -0,0 +1,463 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/quangdangfit/gocommon/logger"
+	"github.com/quangdangfit/gocommon/validation"
+	"gorm.io/gorm"
+
+	"main/internal/consultation/dto"
+	"main/internal/consultation/model"
+	"main/internal/consultation/repository"
+	doctorModel "main/internal/doctor/model"
+	userModel "main/internal/user/model"
+	"main/pkg/dbs"
+	"main/pkg/paging"
+	"main/pkg/tenant"
+	"main/pkg/utils"
+)
+
+var (
+	ErrConsultationNotFound = errors.New("consultation not found")
+	ErrPrescriptionNotFound = errors.New("prescription not found")
+	ErrPatientNotFound      = errors.New("patient not found")
+	ErrNotDoctor            = errors.New("only doctors write consultations")
+	ErrNotAuthor            = errors.New("only the author can change the consultation")
+	ErrDoctorNotVerified    = errors.New("only verified doctors can sign")
+	ErrSigned               = errors.New("consultation is signed, add an amendment instead")
+	ErrNotSigned            = errors.New("consultation is not signed yet")
+)
+
+//go:generate mockery --name=IConsultationService
+type IConsultationService interface {
+	Create(ctx context.Context, userID string, req *dto.CreateConsultationReq) (*model.Consultation, error)
+	Update(ctx context.Context, userID, id string, req *dto.UpdateConsultationReq) (*model.Consultation, error)
+	Sign(ctx context.Context, userID, id string, version int64) (*model.Consultation, error)
+	Amend(ctx context.Context, userID, id string, req *dto.AmendConsultationReq) (*model.Amendment, error)
+	GetConsultation(ctx context.Context, userID, id string) (*model.Consultation, error)
+	ListOwnConsultations(ctx context.Context, userID string, req *dto.ListConsultationsReq) ([]*model.Consultation, *paging.Pagination, error)
+	ListDoctorConsultations(ctx context.Context, userID string, req *dto.ListConsultationsReq) ([]*model.Consultation, *paging.Pagination, error)
+	GetPrescriptionPDF(ctx context.Context, userID, id string) (*model.Prescription, []byte, error)
+	VerifyPrescription(ctx context.Context, code, signature string) (*dto.PrescriptionVerification, error)
+}
+
+// Doctors finds the doctors writing consultations
+type Doctors interface {
+	GetDoctorByUserID(ctx context.Context, userID string) (*doctorModel.Doctor, error)
+}
+
+// Users finds the patients
+type Users interface {
+	GetUserByID(ctx context.Context, id string) (*userModel.User, error)
+}
+
+type ConsultationService struct {
+	validator validation.Validation
+	repo      repository.IConsultationRepository
+	doctors   Doctors
+	users     Users
+	signer    *PrescriptionSigner
+}
+
+func NewConsultationService(
+	validator validation.Validation,
+	repo repository.IConsultationRepository,
+	doctors Doctors,
+	users Users,
+	signer *PrescriptionSigner,
+) *ConsultationService {
+	return &ConsultationService{
+		validator: validator,
+		repo:      repo,
+		doctors:   doctors,
+		users:     users,
+		signer:    signer,
+	}
+}
+
+// Create starts a draft consultation of the doctor of userID
+func (s *ConsultationService) Create(ctx context.Context, userID string, req *dto.CreateConsultationReq) (*model.Consultation, error) {
+	if err := s.validator.ValidateStruct(req); err != nil {
+		return nil, err
+	}
+
+	doctor, err := s.doctor(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.patient(ctx, req.PatientID); err != nil {
+		return nil, err
+	}
+
+	consultation := model.Consultation{DoctorID: doctor.ID, PatientID: req.PatientID}
+	consultation.BeforeCreate()
+	apply(&consultation, &req.UpdateConsultationReq)
+	if err := s.repo.Create(ctx, &consultation); err != nil {
+		logger.Errorf("Create fail, doctor: %s, error: %s", doctor.ID, err)
+		return nil, err
+	}
+
+	return &consultation, nil
+}
+
+// Update replaces the content of a draft consultation
+func (s *ConsultationService) Update(ctx context.Context, userID, id string, req *dto.UpdateConsultationReq) (*model.Consultation, error) {
+	if err := s.validator.ValidateStruct(req); err != nil {
+		return nil, err
+	}
+
+	consultation, _, err := s.authored(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if consultation.Status != model.ConsultationDraft {
+		return nil, ErrSigned
+	}
+
+	if req.Version != dbs.AnyVersion {
+		consultation.Version = req.Version
+	}
+	apply(consultation, req)
+	updated, err := s.repo.Update(ctx, consultation)
+	if err != nil {
+		logger.Errorf("Update fail, id: %s, error: %s", id, err)
+		return nil, err
+	}
+	if !updated {
+		return nil, dbs.ErrStaleVersion
+	}
+
+	return consultation, nil
+}
+
+// Sign makes the consultation at version immutable and issues its
+// prescription when medications are prescribed
+func (s *ConsultationService) Sign(ctx context.Context, userID, id string, version int64) (*model.Consultation, error) {
+	consultation, doctor, err := s.authored(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if doctor.Status != doctorModel.DoctorVerified {
+		return nil, ErrDoctorNotVerified
+	}
+	if consultation.Status != model.ConsultationDraft {
+		return nil, ErrSigned
+	}
+	// the doctor signs the content it read
+	if version != dbs.AnyVersion && version != consultation.Version {
+		return nil, dbs.ErrStaleVersion
+	}
+
+	now := time.Now()
+	consultation.Status = model.ConsultationSigned
+	consultation.SignedAt = &now
+	consultation.UpdatedAt = now
+	consultation.ContentHash = contentHash(consultation)
+
+	var prescription *model.Prescription
+	if len(consultation.Medications) > 0 {
+		patient, err := s.patient(ctx, consultation.PatientID)
+		if err != nil {
+			return nil, err
+		}
+		code, err := newCode()
+		if err != nil {
+			return nil, err
+		}
+
+		prescription = &model.Prescription{
+			ConsultationID: consultation.ID,
+			TenantID:       consultation.TenantID,
+			Code:           code,
+			DoctorName:     doctor.Name,
+			LicenseNumber:  doctor.LicenseNumber,
+			PatientName:    patient.Name,
+		}
+		prescription.BeforeCreate()
+		prescription.IssuedAt = now.Truncate(time.Second)
+		prescription.Signature = s.signer.Sign(prescription, consultation.Medications)
+	}
+
+	signed, err := s.repo.Sign(ctx, consultation, prescription)
+	if err != nil {
+		logger.Errorf("Sign fail, id: %s, error: %s", id, err)
+		return nil, err
+	}
+	if !signed {
+		return nil, dbs.ErrStaleVersion
+	}
+
+	return consultation, nil
+}
+
+// Amend adds an amendment to a signed consultation
+func (s *ConsultationService) Amend(ctx context.Context, userID, id string, req *dto.AmendConsultationReq) (*model.Amendment, error) {
+	if err := s.validator.ValidateStruct(req); err != nil {
+		return nil, err
+	}
+
+	consultation, _, err := s.authored(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if consultation.Status != model.ConsultationSigned {
+		return nil, ErrNotSigned
+	}
+
+	amendment := model.Amendment{
+		ConsultationID: consultation.ID,
+		AuthorID:       userID,
+		Reason:         req.Reason,
+		Text:           req.Text,
+	}
+	amendment.BeforeCreate()
+	if err := s.repo.AddAmendment(ctx, &amendment); err != nil {
+		logger.Errorf("Amend.AddAmendment fail, id: %s, error: %s", id, err)
+		return nil, err
+	}
+
+	return &amendment, nil
+}
+
+// GetConsultation returns the consultation to its author, or to its patient
+// once signed
+func (s *ConsultationService) GetConsultation(ctx context.Context, userID, id string) (*model.Consultation, error) {
+	consultation, err := s.consultation(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if consultation.PatientID == userID && consultation.Status == model.ConsultationSigned {
+		return consultation, nil
+	}
+
+	doctor, err := s.doctors.GetDoctorByUserID(ctx, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrConsultationNotFound
+	}
+	if err != nil {
+		logger.Errorf("GetConsultation.GetDoctorByUserID fail, user: %s, error: %s", userID, err)
+		return nil, err
+	}
+	if doctor.ID != consultation.DoctorID {
+		return nil, ErrConsultationNotFound
+	}
+	return consultation, nil
+}
+
+// ListOwnConsultations lists the signed consultations of the patient
+func (s *ConsultationService) ListOwnConsultations(ctx context.Context, userID string, req *dto.ListConsultationsReq) ([]*model.Consultation, *paging.Pagination, error) {
+	req.PatientID = userID
+	consultations, pagination, err := s.repo.ListConsultations(ctx, "", true, req)
+	if err != nil {
+		logger.Errorf("ListOwnConsultations fail, user: %s, error: %s", userID, err)
+		return nil, nil, err
+	}
+	return consultations, pagination, nil
+}
+
+// ListDoctorConsultations lists the consultations written by the doctor of
+// userID, of a patient when set
+func (s *ConsultationService) ListDoctorConsultations(ctx context.Context, userID string, req *dto.ListConsultationsReq) ([]*model.Consultation, *paging.Pagination, error) {
+	doctor, err := s.doctor(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	consultations, pagination, err := s.repo.ListConsultations(ctx, doctor.ID, false, req)
+	if err != nil {
+		logger.Errorf("ListDoctorConsultations fail, doctor: %s, error: %s", doctor.ID, err)
+		return nil, nil, err
+	}
+	return consultations, pagination, nil
+}
+
+// GetPrescriptionPDF renders the prescription of the consultation for its
+// author or its patient
+func (s *ConsultationService) GetPrescriptionPDF(ctx context.Context, userID, id string) (*model.Prescription, []byte, error) {
+	consultation, err := s.GetConsultation(ctx, userID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	prescription, err := s.repo.GetPrescription(ctx, consultation.ID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil, ErrPrescriptionNotFound
+	}
+	if err != nil {
+		logger.Errorf("GetPrescriptionPDF.GetPrescription fail, id: %s, error: %s", id, err)
+		return nil, nil, err
+	}
+
+	content, err := renderPrescription(prescription, consultation, s.signer.URL(prescription))
+	if err != nil {
+		logger.Errorf("GetPrescriptionPDF.render fail, id: %s, error: %s", id, err)
+		return nil, nil, err
+	}
+	return prescription, content, nil
+}
+
+// VerifyPrescription checks the prescription of code against signature, the
+// full one from its QR code or its printed fingerprint. Unknown codes and
+// wrong signatures are not told apart.
+func (s *ConsultationService) VerifyPrescription(ctx context.Context, code, signature string) (*dto.PrescriptionVerification, error) {
+	ctx = tenant.Global(ctx)
+	code = strings.ToUpper(strings.TrimSpace(code))
+	prescription, err := s.repo.GetPrescriptionByCode(ctx, code)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrPrescriptionNotFound
+	}
+	if err != nil {
+		logger.Errorf("VerifyPrescription fail, code: %s, error: %s", code, err)
+		return nil, err
+	}
+
+	if !matchSignature(prescription.Signature, signature) {
+		return nil, ErrPrescriptionNotFound
+	}
+
+	consultation, err := s.consultation(ctx, prescription.ConsultationID)
+	if err != nil {
+		return nil, err
+	}
+
+	// the prescription is known but invalid when its data was altered since
+	res := dto.PrescriptionVerification{
+		Valid:         s.signer.Verify(prescription, consultation.Medications),
+		Code:          prescription.Code,
+		IssuedAt:      prescription.IssuedAt,
+		DoctorName:    prescription.DoctorName,
+		LicenseNumber: prescription.LicenseNumber,
+	}
+	if res.Valid {
+		res.PatientName = prescription.PatientName
+		utils.Copy(&res.Medications, &consultation.Medications)
+	}
+	return &res, nil
+}
+
+// authored returns the consultation with its doctor when it is userID
+func (s *ConsultationService) authored(ctx context.Context, userID, id string) (*model.Consultation, *doctorModel.Doctor, error) {
+	doctor, err := s.doctor(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	consultation, err := s.consultation(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if consultation.DoctorID != doctor.ID {
+		return nil, nil, ErrNotAuthor
+	}
+	return consultation, doctor, nil
+}
+
+func (s *ConsultationService) consultation(ctx context.Context, id string) (*model.Consultation, error) {
+	consultation, err := s.repo.GetConsultationByID(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrConsultationNotFound
+	}
+	if err != nil {
+		logger.Errorf("GetConsultationByID fail, id: %s, error: %s", id, err)
+		return nil, err
+	}
+	return consultation, nil
+}
+
+func (s *ConsultationService) doctor(ctx context.Context, userID string) (*doctorModel.Doctor, error) {
+	doctor, err := s.doctors.GetDoctorByUserID(ctx, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotDoctor
+	}
+	if err != nil {
+		logger.Errorf("GetDoctorByUserID fail, user: %s, error: %s", userID, err)
+		return nil, err
+	}
+	return doctor, nil
+}
+
+// patient finds the patient across clinics, patients belong to none
+func (s *ConsultationService) patient(ctx context.Context, id string) (*userModel.User, error) {
+	patient, err := s.users.GetUserByID(tenant.Global(ctx), id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrPatientNotFound
+	}
+	if err != nil {
+		logger.Errorf("GetUserByID fail, id: %s, error: %s", id, err)
+		return nil, err
+	}
+	return patient, nil
+}
+
+// apply sets the content of req on the consultation
+func apply(consultation *model.Consultation, req *dto.UpdateConsultationReq) {
+	consultation.VisitedAt = consultation.CreatedAt
+	if req.VisitedAt != nil {
+		consultation.VisitedAt = *req.VisitedAt
+	}
+	consultation.Subjective = req.Subjective
+	consultation.Objective = req.Objective
+	consultation.Assessment = req.Assessment
+	consultation.Plan = req.Plan
+	consultation.UpdatedAt = time.Now()
+
+	consultation.Diagnoses = model.Diagnoses{}
+	for _, diagnosis := range req.Diagnoses {
+		consultation.Diagnoses = append(consultation.Diagnoses, model.Diagnosis{
+			Code:        diagnosis.Code,
+			Description: diagnosis.Description,
+		})
+	}
+
+	consultation.Medications = nil
+	for i, item := range req.Medications {
+		medication := model.Medication{
+			ConsultationID: consultation.ID,
+			Position:       i,
+			Name:           item.Name,
+			Dosage:         item.Dosage,
+			Frequency:      item.Frequency,
+			DurationDays:   item.DurationDays,
+			Instructions:   item.Instructions,
+		}
+		medication.BeforeCreate()
+		consultation.Medications = append(consultation.Medications, &medication)
+	}
+}
+
+// contentHash is the sha256 of the signed content of the consultation
+func contentHash(consultation *model.Consultation) string {
+	content, _ := json.Marshal(struct {
+		ID          string              `json:"id"`
+		DoctorID    string              `json:"doctor_id"`
+		PatientID   string              `json:"patient_id"`
+		VisitedAt   time.Time           `json:"visited_at"`
+		Subjective  string              `json:"subjective"`
+		Objective   string              `json:"objective"`
+		Assessment  string              `json:"assessment"`
+		Plan        string              `json:"plan"`
+		Diagnoses   model.Diagnoses     `json:"diagnoses"`
+		Medications []*model.Medication `json:"medications"`
+		SignedAt    *time.Time          `json:"signed_at"`
+	}{
+		consultation.ID,
+		consultation.DoctorID,
+		consultation.PatientID,
+		consultation.VisitedAt.UTC(),
+		consultation.Subjective,
+		consultation.Objective,
+		consultation.Assessment,
+		consultation.Plan,
+		consultation.Diagnoses,
+		consultation.Medications,
+		consultation.SignedAt,
+	})
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	qrcode "github.com/skip2/go-qrcode"
+
+	"main/internal/consultation/model"
+	"main/pkg/pdf"
+)
+
+// FingerprintSize is the length of the signature printed on the
+// prescriptions, enough for a pharmacy to verify a prescription by hand
+const FingerprintSize = 16
+
+// PrescriptionSigner signs the prescriptions and builds the urls pharmacies
+// verify them with
+type PrescriptionSigner struct {
+	key []byte
+	// verifyURL is joined with the code of the prescription
+	verifyURL string
+}
+
+func NewPrescriptionSigner(key, verifyURL string) *PrescriptionSigner {
+	return &PrescriptionSigner{key: []byte(key), verifyURL: strings.TrimSuffix(verifyURL, "/")}
+}
+
+// Sign returns the signature of the prescription with its medications
+func (s *PrescriptionSigner) Sign(prescription *model.Prescription, medications []*model.Medication) string {
+	mac := hmac.New(sha256.New, s.key)
+	fields := []string{
+		prescription.Code,
+		prescription.ConsultationID,
+		strconv.FormatInt(prescription.IssuedAt.Unix(), 10),
+		prescription.DoctorName,
+		prescription.LicenseNumber,
+		prescription.PatientName,
+	}
+	for _, medication := range medications {
+		fields = append(fields,
+			medication.Name,
+			medication.Dosage,
+			medication.Frequency,
+			strconv.Itoa(medication.DurationDays),
+			medication.Instructions,
+		)
+	}
+	// lengths prefix the fields so they cannot be shifted from one to another
+	for _, field := range fields {
+		fmt.Fprintf(mac, "%d:%s\n", len(field), field)
+	}
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify tells whether the signature of the prescription still matches its
+// content
+func (s *PrescriptionSigner) Verify(prescription *model.Prescription, medications []*model.Medication) bool {
+	return hmac.Equal([]byte(prescription.Signature), []byte(s.Sign(prescription, medications)))
+}
+
+// matchSignature tells whether given is the signature, or its fingerprint
+func matchSignature(signature, given string) bool {
+	given = strings.ToLower(given)
+	if len(given) == FingerprintSize && len(signature) >= FingerprintSize {
+		signature = signature[:FingerprintSize]
+	}
+	return hmac.Equal([]byte(signature), []byte(given))
+}
+
+// URL returns the verification url of the prescription, encoded in its QR
+// code
+func (s *PrescriptionSigner) URL(prescription *model.Prescription) string {
+	return s.verifyURL + "/" + url.PathEscape(prescription.Code) + "?sig=" + prescription.Signature
+}
+
+// newCode returns a random code, readable over the phone
+func newCode() (string, error) {
+	b := make([]byte, 10)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	code := base32.StdEncoding.EncodeToString(b)
+	return code[:4] + "-" + code[4:8] + "-" + code[8:12], nil
+}
+
+// renderPrescription writes the prescription of the consultation as a pdf
+// with the QR code of its verification url
+func renderPrescription(prescription *model.Prescription, consultation *model.Consultation, verifyURL string) ([]byte, error) {
+	qr, err := qrcode.New(verifyURL, qrcode.Medium)
+	if err != nil {
+		return nil, err
+	}
+
+	const (
+		left     = 50.0
+		right    = pdf.A4Width - 50
+		width    = right - left
+		bottom   = pdf.A4Height - 60
+		lineSize = 14.0
+	)
+	doc := pdf.New()
+	page := doc.AddPage()
+	y := 70.0
+	newLine := func(height float64) {
+		y += height
+		if y > bottom {
+			page = doc.AddPage()
+			y = 70
+		}
+	}
+
+	page.Text(left, y, pdf.HelveticaBold, 20, "Prescription")
+	page.Text(right-150, y, pdf.Helvetica, 10, "No. "+prescription.Code)
+	newLine(12)
+	page.Line(left, y, right, y, 1)
+	newLine(24)
+
+	for _, row := range [][2]string{
+		{"Doctor", prescription.DoctorName},
+		{"License", prescription.LicenseNumber},
+		{"Patient", prescription.PatientName},
+		{"Date", consultation.VisitedAt.Format("2006-01-02")},
+		{"Issued", prescription.IssuedAt.UTC().Format("2006-01-02 15:04 UTC")},
+	} {
+		page.Text(left, y, pdf.HelveticaBold, 11, row[0])
+		page.Text(left+80, y, pdf.Helvetica, 11, row[1])
+		newLine(lineSize + 2)
+	}
+	newLine(12)
+
+	page.Text(left, y, pdf.HelveticaBold, 13, "Medications")
+	newLine(20)
+	for i, medication := range consultation.Medications {
+		page.Text(left, y, pdf.HelveticaBold, 11, fmt.Sprintf("%d. %s", i+1, medication.Name))
+		newLine(lineSize)
+		details := fmt.Sprintf("%s, %s, for %d days", medication.Dosage, medication.Frequency, medication.DurationDays)
+		if medication.Instructions != "" {
+			details += ". " + medication.Instructions
+		}
+		for _, line := range pdf.Wrap(details, 10, width-15) {
+			page.Text(left+15, y, pdf.Helvetica, 10, line)
+			newLine(lineSize)
+		}
+		newLine(6)
+	}
+
+	// the verification block stays on a single page
+	const qrSize = 110.0
+	if y+qrSize+40 > bottom {
+		page = doc.AddPage()
+		y = 70
+	}
+	newLine(10)
+	page.Line(left, y, right, y, 0.5)
+	newLine(15)
+	page.Bitmap(left, y, qrSize, qr.Bitmap())
+	page.Text(left+qrSize+15, y+20, pdf.HelveticaBold, 11, "Verify this prescription")
+	page.Text(left+qrSize+15, y+38, pdf.Helvetica, 9, "Scan the code, or enter the number and fingerprint at")
+	page.Text(left+qrSize+15, y+52, pdf.Helvetica, 9, strings.SplitN(verifyURL, "?", 2)[0])
+	page.Text(left+qrSize+15, y+72, pdf.Helvetica, 10, "Fingerprint: "+prescription.Signature[:FingerprintSize])
+	page.Text(left+qrSize+15, y+86, pdf.Helvetica, 9, "Electronically signed by "+prescription.DoctorName)
+
+	var buf bytes.Buffer
+	if _, err := doc.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
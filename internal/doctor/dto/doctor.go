@@ -1,6 +1,8 @@
 package dto
 
 import (
+	"time"
+
 	"main/pkg/paging"
 )
 
@@ -16,6 +18,40 @@ type Doctor struct {
 	Price      float32 `json:"price"`
 	Specalist  string  `json:"specalist"`
 	Experience int     `json:"experience"`
+	// Thumbnails of the picture by size and format
+	// example: {"small.webp":"http://localhost:8888/api/v1/images/12345/small.webp"}
+	ImageVariants map[string]string `json:"image_variants"`
+	// License verification status
+	// example: "verified"
+	Status           string     `json:"status"`
+	LicenseExpiresAt *time.Time `json:"license_expires_at"`
+	// Average rating of the reviews, from 1 to 5
+	// example: 4.5
+	RatingAverage float64 `json:"rating_average"`
+	RatingCount   int64   `json:"rating_count"`
+	// Specialties of the catalogue, one is primary
+	Specialties []*DoctorSpecialty `json:"specialties"`
+	// Set on the deleted doctors listed by admins
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	// Version of the doctor, also sent as its ETag
+	Version int64 `json:"version"`
+}
+
+// swagger:model DoctorSpecialty
+type DoctorSpecialty struct {
+	SpecialtyID string            `json:"specialty_id"`
+	Primary     bool              `json:"primary"`
+	Specialty   *SpecialtySummary `json:"specialty"`
+}
+
+// swagger:model SpecialtySummary
+type SpecialtySummary struct {
+	// example: "cardiology"
+	Slug string `json:"slug"`
+	// Names by locale
+	// example: {"en":"Cardiology"}
+	Names map[string]string `json:"names"`
+	Icon  string            `json:"icon"`
 }
 
 // ***************************************************************************\\
@@ -31,6 +67,15 @@ type CreateDoctorReq struct {
 	Experience int     `json:"experience"`
 }
 
+// ***************************************************************************\\
+// ***************************************************************************\\
+// SetImageReq sets an uploaded image as the picture of the signed-in doctor.
+// swagger:model SetImageReq
+type SetImageReq struct {
+	// ID of the uploaded image
+	FileID string `json:"file_id" validate:"required"`
+}
+
 // ***************************************************************************\\
 // ***************************************************************************\\
 // UpdateDoctorReq represents the request body for updating an existing Doctor.
@@ -43,6 +88,21 @@ type UpdateDoctorReq struct {
 	Price      float32 `json:"price"`
 	Specalist  string  `json:"specalist"`
 	Experience int     `json:"experience"`
+	// Version the change applies to, from If-Match over HTTP
+	Version int64 `json:"-"`
+}
+
+// ***************************************************************************\\
+// ***************************************************************************\\
+// PatchDoctorReq holds the fields changed by a patch of a Doctor, the others
+// are nil and kept.
+// swagger:model PatchDoctorReq
+type PatchDoctorReq struct {
+	Name       *string  `json:"name,omitempty" validate:"omitnil,min=1,max=200"`
+	Image      *string  `json:"image,omitempty"`
+	Price      *float32 `json:"price,omitempty" validate:"omitnil,min=0"`
+	Specalist  *string  `json:"specalist,omitempty"`
+	Experience *int     `json:"experience,omitempty" validate:"omitnil,min=0,max=80"`
 }
 
 // ***************************************************************************\\
@@ -66,10 +126,22 @@ type ListDoctorReq struct {
 	IDUser    string    `json:"id_user,omitempty" form:"id_user"`
 	Page      int64     `json:"page,omitempty" form:"page"`
 	Limit     int64     `json:"limit,omitempty" form:"limit"`
-	OrderList []OrderBy `json:"order_list,omitempty" form:"order_list"`
+	OrderList []OrderBy `json:"order_list,omitempty" form:"order_list" validate:"dive"`
+	// Only doctors of the specialty of this slug or of its children
+	// example: "cardiology"
+	Specialty string `json:"specialty,omitempty" form:"specialty"`
+	// Only doctors rated at least MinRating
+	// example: 4
+	MinRating float64 `json:"min_rating,omitempty" form:"min_rating" validate:"min=0,max=5"`
+	// Sorts by name, price, experience, created_at, rating_average or
+	// rating_count, before OrderList
+	OrderBy   string `json:"order_by,omitempty" form:"order_by" validate:"omitempty,oneof=name price experience created_at rating_average rating_count"`
+	OrderDesc bool   `json:"order_desc,omitempty" form:"order_desc"`
+	// Also lists the deleted doctors, for admins
+	IncludeDeleted bool `json:"include_deleted,omitempty" form:"include_deleted"`
 }
 type OrderBy struct {
-	OrderBy   string `json:"order_by,omitempty" form:"order_by"`
+	OrderBy   string `json:"order_by,omitempty" form:"order_by" validate:"omitempty,oneof=name price experience created_at rating_average rating_count"`
 	OrderDesc bool   `json:"order_desc,omitempty" form:"order_desc"`
 }
 
@@ -94,6 +166,8 @@ type DeleteDoctorReq struct {
 	// User ID associated with the Doctor
 	// example: "67890"
 	IDUser string `json:"id_user"`
+	// Version the change applies to, from If-Match over HTTP
+	Version int64 `json:"-"`
 }
 
 //***************************************************************************\\
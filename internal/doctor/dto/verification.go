@@ -0,0 +1,57 @@
+package dto
+
+import (
+	"time"
+
+	"main/pkg/paging"
+)
+
+// SubmitVerificationReq is the license a doctor submits for review
+// swagger:model SubmitVerificationReq
+type SubmitVerificationReq struct {
+	// example: "MD-123456"
+	LicenseNumber string `json:"license_number" validate:"required"`
+	// example: "Medical Council"
+	IssuingAuthority string    `json:"issuing_authority" validate:"required"`
+	LicenseExpiresAt time.Time `json:"license_expires_at" validate:"required"`
+	// Urls of the uploaded license documents
+	// example: ["https://files.example.com/license.pdf"]
+	Documents []string `json:"documents" validate:"required,min=1,dive,url"`
+}
+
+// RejectVerificationReq gives the doctor the reason of the rejection
+// swagger:model RejectVerificationReq
+type RejectVerificationReq struct {
+	// example: "The license number does not match the document"
+	Reason string `json:"reason" validate:"required"`
+}
+
+// swagger:model Verification
+type Verification struct {
+	ID               string    `json:"id"`
+	DoctorID         string    `json:"doctor_id"`
+	LicenseNumber    string    `json:"license_number"`
+	IssuingAuthority string    `json:"issuing_authority"`
+	LicenseExpiresAt time.Time `json:"license_expires_at"`
+	Documents        []string  `json:"documents"`
+	// example: "pending"
+	Status     string     `json:"status"`
+	Reason     string     `json:"reason"`
+	ReviewedBy string     `json:"reviewed_by"`
+	ReviewedAt *time.Time `json:"reviewed_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// ListVerificationsReq lists the review queue, oldest first
+type ListVerificationsReq struct {
+	// pending when empty
+	Status string `json:"status,omitempty" form:"status"`
+	Page   int64  `json:"page,omitempty" form:"page"`
+	Limit  int64  `json:"limit,omitempty" form:"limit"`
+}
+
+// swagger:model ListVerificationsRes
+type ListVerificationsRes struct {
+	Verifications []*Verification    `json:"verifications"`
+	Pagination    *paging.Pagination `json:"pagination"`
+}
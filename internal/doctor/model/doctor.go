@@ -4,28 +4,70 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	specialtyModel "main/internal/specialty/model"
+	"main/pkg/imaging"
 )
 
 // Doctor represents the domain model for an Doctor.
 type Doctor struct {
-	ID         string     `json:"id_Doctor"`
-	IDUser     string     `json:"id_user"`
-	Name       string     `json:"name"`
-	Image      string     `json:"image"`
-	Price      float32    `json:"price"`
-	Specalist  string     `json:"specalist"`
-	Experience int        `json:"experience"`
-	CreatedAt  time.Time  `json:"created_at"`
-	UpdatedAt  time.Time  `json:"updated_at"`
-	DeletedAt  *time.Time `json:"deleted_at" gorm:"index"`
+	ID     string  `json:"id_Doctor"`
+	IDUser string  `json:"id_user"`
+	Name   string  `json:"name"`
+	Image  string  `json:"image"`
+	Price  float32 `json:"price"`
+	// Specalist is the free-text speciality, the name of the primary
+	// specialty once specialties are set
+	Specalist  string         `json:"specalist"`
+	Experience int            `json:"experience"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+	DeletedAt  gorm.DeletedAt `json:"deleted_at" gorm:"index"`
+	// Version is bumped by every change, it is the ETag of the doctor
+	Version int64 `json:"version" gorm:"not null;default:1"`
+	// ImageVariants are the thumbnails of the uploaded picture, Image is the
+	// large jpeg one
+	ImageVariants imaging.Variants `json:"image_variants" gorm:"type:text"`
+	// Status of the license verification, only verified doctors are listed
+	// and can be booked. The license is the last approved one.
+	Status           string     `json:"status" gorm:"not null;default:unverified;index"`
+	LicenseNumber    string     `json:"license_number"`
+	IssuingAuthority string     `json:"issuing_authority"`
+	LicenseExpiresAt *time.Time `json:"license_expires_at"`
+	// Rating of the visible reviews, kept up to date by the reviews
+	RatingAverage float64 `json:"rating_average" gorm:"not null;default:0;index"`
+	RatingCount   int64   `json:"rating_count" gorm:"not null;default:0"`
+	// Specialties of the catalogue, one is primary
+	Specialties []*specialtyModel.DoctorSpecialty `json:"specialties" gorm:"foreignKey:DoctorID;constraint:OnDelete:CASCADE"`
+	// TenantID is the clinic of the doctor, empty for independent doctors
+	TenantID string `json:"tenant_id" gorm:"not null;default:'';index"`
 }
 
+// ImageVariant is the variant set as Image
+const ImageVariant = "large.jpg"
+
+const (
+	DoctorUnverified = "unverified"
+	DoctorPending    = "pending"
+	DoctorVerified   = "verified"
+	DoctorRejected   = "rejected"
+	// DoctorSuspended doctors were verified but their license expired
+	DoctorSuspended = "suspended"
+)
+
 func (m *Doctor) BeforeCreate() error {
 	m.ID = uuid.New().String()
 	m.CreatedAt = time.Now()
+	m.Status = DoctorUnverified
 	return nil
 }
 
+// Bookable reports whether patients can book the doctor
+func (m *Doctor) Bookable() bool {
+	return m.Status == DoctorVerified && m.LicenseExpiresAt != nil && time.Now().Before(*m.LicenseExpiresAt)
+}
+
 // func (m *Doctor) BeforeUpdate(tx *gorm.DB) error {
 // 	m.UpdatedAt = time.Now()
 // 	return nil
@@ -0,0 +1,68 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	VerificationPending  = "pending"
+	VerificationApproved = "approved"
+	VerificationRejected = "rejected"
+)
+
+// Verification is a license submitted by a doctor, reviewed by an admin
+type Verification struct {
+	ID               string     `json:"id" gorm:"unique;not null;index;primary_key"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+	DoctorID         string     `json:"doctor_id" gorm:"not null;index"`
+	LicenseNumber    string     `json:"license_number" gorm:"not null"`
+	IssuingAuthority string     `json:"issuing_authority" gorm:"not null"`
+	LicenseExpiresAt time.Time  `json:"license_expires_at" gorm:"not null"`
+	Documents        Documents  `json:"documents" gorm:"type:text"`
+	Status           string     `json:"status" gorm:"not null;index"`
+	Reason           string     `json:"reason"`
+	ReviewedBy       string     `json:"reviewed_by"`
+	ReviewedAt       *time.Time `json:"reviewed_at"`
+}
+
+func (Verification) TableName() string {
+	return "doctor_verifications"
+}
+
+func (m *Verification) BeforeCreate() error {
+	m.ID = uuid.New().String()
+	m.CreatedAt = time.Now()
+	m.Status = VerificationPending
+	return nil
+}
+
+// Documents are the urls of the uploaded license documents, stored as json
+type Documents []string
+
+func (d Documents) Value() (driver.Value, error) {
+	if d == nil {
+		return "[]", nil
+	}
+	b, err := json.Marshal([]string(d))
+	return string(b), err
+}
+
+func (d *Documents) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case string:
+		return json.Unmarshal([]byte(v), d)
+	case []byte:
+		return json.Unmarshal(v, d)
+	case nil:
+		*d = nil
+		return nil
+	default:
+		return fmt.Errorf("cannot scan %T into Documents", value)
+	}
+}
@@ -2,14 +2,22 @@ package grpc
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/quangdangfit/gocommon/logger"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"main/internal/doctor/dto"
+	"main/internal/doctor/model"
 	"main/internal/doctor/service"
-	"main/pkg/config"
+	specialtyModel "main/internal/specialty/model"
+	"main/pkg/dbs"
+	"main/pkg/patch"
 	"main/pkg/redis"
+	"main/pkg/tenant"
 	"main/pkg/utils"
 	pb "main/proto/gen/go/doctor"
 )
@@ -37,55 +45,51 @@ func NewDoctorHandler(
 //	}
 
 func (h *DoctorHandler) GetDoctorByID(ctx context.Context, req *pb.GetDoctorByIDRequest) (*pb.DoctorResponse, error) {
-	var res dto.Doctor
-	cacheKey := "Doctor_" + req.Id
-	err := h.cache.Get(cacheKey, &res)
-	if err == nil {
-		return &pb.DoctorResponse{Doctor: &pb.Doctor{
-			Id:         res.ID,
-			IdUser:     res.IDUser,
-			Name:       res.Name,
-			Image:      res.Image,
-			Price:      res.Price,
-			Specialist: res.Specalist,
-			Experience: int32(res.Experience),
-		}}, nil
-	}
-
+	// never cached, its version is the one UpdateDoctor is checked against
 	Doctor, err := h.service.GetDoctorByID(ctx, req.Id)
 	if err != nil {
 		logger.Error("Failed to get Doctor detail: ", err)
 		return nil, err
 	}
 
+	var res dto.Doctor
 	utils.Copy(&res, &Doctor)
-	_ = h.cache.SetWithExpiration(cacheKey, res, config.DoctorCachingTime.Abs())
 	return &pb.DoctorResponse{Doctor: &pb.Doctor{
-		Id:         res.ID,
-		IdUser:     res.IDUser,
-		Name:       res.Name,
-		Image:      res.Image,
-		Price:      res.Price,
-		Specialist: res.Specalist,
-		Experience: int32(res.Experience),
+		Id:            res.ID,
+		IdUser:        res.IDUser,
+		Name:          res.Name,
+		Image:         res.Image,
+		Price:         res.Price,
+		Specialist:    res.Specalist,
+		Experience:    int32(res.Experience),
+		ImageVariants: res.ImageVariants,
+		RatingAverage: res.RatingAverage,
+		RatingCount:   res.RatingCount,
+		Specialties:   specialtiesToPb(res.Specialties),
+		Version:       res.Version,
 	}}, nil
 }
 
 func (h *DoctorHandler) ListDoctors(ctx context.Context, req *pb.ListDoctorReq) (*pb.ListDoctorRes, error) {
 	var res dto.ListDoctorRes
-	cacheKey := "Doctors_list"
-	err := h.cache.Get(cacheKey, &res)
+	cacheKey := tenant.CacheKey(ctx, "Doctors_list")
+	err := h.cache.Get(ctx, cacheKey, &res)
 	if err == nil {
 		var pbDoctors []*pb.Doctor
 		for _, addr := range res.Doctors {
 			pbDoctors = append(pbDoctors, &pb.Doctor{
-				Id:         addr.ID,
-				IdUser:     addr.IDUser,
-				Name:       addr.Name,
-				Image:      addr.Image,
-				Price:      addr.Price,
-				Specialist: addr.Specalist,
-				Experience: int32(addr.Experience),
+				Id:            addr.ID,
+				IdUser:        addr.IDUser,
+				Name:          addr.Name,
+				Image:         addr.Image,
+				Price:         addr.Price,
+				Specialist:    addr.Specalist,
+				Experience:    int32(addr.Experience),
+				ImageVariants: addr.ImageVariants,
+				RatingAverage: addr.RatingAverage,
+				RatingCount:   addr.RatingCount,
+				Specialties:   specialtiesToPb(addr.Specialties),
+				Version:       addr.Version,
 			})
 		}
 		return &pb.ListDoctorRes{Doctors: pbDoctors}, nil
@@ -99,18 +103,23 @@ func (h *DoctorHandler) ListDoctors(ctx context.Context, req *pb.ListDoctorReq)
 
 	utils.Copy(&res.Doctors, &Doctors)
 	res.Pagination = pagination
-	_ = h.cache.SetWithExpiration(cacheKey, res, time.Hour) // Adjust caching time as needed
+	_ = h.cache.SetWithExpiration(ctx, cacheKey, res, time.Hour) // Adjust caching time as needed
 
 	var pbDoctors []*pb.Doctor
-	for _, addr := range Doctors {
+	for _, addr := range res.Doctors {
 		pbDoctors = append(pbDoctors, &pb.Doctor{
-			Id:         addr.ID,
-			IdUser:     addr.IDUser,
-			Name:       addr.Name,
-			Image:      addr.Image,
-			Price:      addr.Price,
-			Specialist: addr.Specalist,
-			Experience: int32(addr.Experience),
+			Id:            addr.ID,
+			IdUser:        addr.IDUser,
+			Name:          addr.Name,
+			Image:         addr.Image,
+			Price:         addr.Price,
+			Specialist:    addr.Specalist,
+			Experience:    int32(addr.Experience),
+			ImageVariants: addr.ImageVariants,
+			RatingAverage: addr.RatingAverage,
+			RatingCount:   addr.RatingCount,
+			Specialties:   specialtiesToPb(addr.Specialties),
+			Version:       addr.Version,
 		})
 	}
 	return &pb.ListDoctorRes{Doctors: pbDoctors}, nil
@@ -133,64 +142,133 @@ func (h *DoctorHandler) CreateDoctor(ctx context.Context, req *pb.CreateDoctorRe
 
 	var res dto.Doctor
 	utils.Copy(&res, &Doctor)
-	_ = h.cache.RemovePattern("*Doctor*")
 	return &pb.DoctorResponse{Doctor: &pb.Doctor{
-		Id:         res.ID,
-		IdUser:     res.IDUser,
-		Name:       res.Name,
-		Image:      res.Image,
-		Price:      res.Price,
-		Specialist: res.Specalist,
-		Experience: int32(res.Experience),
+		Id:            res.ID,
+		IdUser:        res.IDUser,
+		Name:          res.Name,
+		Image:         res.Image,
+		Price:         res.Price,
+		Specialist:    res.Specalist,
+		Experience:    int32(res.Experience),
+		ImageVariants: res.ImageVariants,
+		RatingAverage: res.RatingAverage,
+		RatingCount:   res.RatingCount,
+		Specialties:   specialtiesToPb(res.Specialties),
+		Version:       res.Version,
 	}}, nil
 }
 
 func (h *DoctorHandler) UpdateDoctor(ctx context.Context, req *pb.UpdateDoctorReq) (*pb.DoctorResponse, error) {
-	var DoctorDTO dto.UpdateDoctorReq
-	DoctorDTO.IDUser = req.IdUser
-	DoctorDTO.Name = req.Name
-	DoctorDTO.Image = req.Image
-	DoctorDTO.Price = req.Price
-	DoctorDTO.Specalist = req.Specialist
-	DoctorDTO.Experience = int(req.Experience)
-
-	Doctor, err := h.service.Update(ctx, req.Id, &DoctorDTO)
+	var Doctor *model.Doctor
+	var err error
+	if paths := req.GetUpdateMask().GetPaths(); len(paths) > 0 {
+		var changes patch.Patch
+		if changes, err = doctorPatch(req, paths); err == nil {
+			Doctor, err = h.service.Patch(ctx, req.Id, req.Version, changes)
+		}
+	} else {
+		var DoctorDTO dto.UpdateDoctorReq
+		DoctorDTO.ID = req.Id
+		DoctorDTO.IDUser = req.IdUser
+		DoctorDTO.Name = req.Name
+		DoctorDTO.Image = req.Image
+		DoctorDTO.Price = req.Price
+		DoctorDTO.Specalist = req.Specialist
+		DoctorDTO.Experience = int(req.Experience)
+		DoctorDTO.Version = req.Version
+		Doctor, err = h.service.Update(ctx, req.Id, &DoctorDTO)
+	}
 	if err != nil {
 		logger.Error("Failed to update Doctor: ", err)
-		return nil, err
+		return nil, versionError(err)
 	}
 
 	var res dto.Doctor
 	utils.Copy(&res, &Doctor)
-	_ = h.cache.RemovePattern("*Doctor*")
 	return &pb.DoctorResponse{Doctor: &pb.Doctor{
-		Id:         res.ID,
-		IdUser:     res.IDUser,
-		Name:       res.Name,
-		Image:      res.Image,
-		Price:      res.Price,
-		Specialist: res.Specalist,
-		Experience: int32(res.Experience),
+		Id:            res.ID,
+		IdUser:        res.IDUser,
+		Name:          res.Name,
+		Image:         res.Image,
+		Price:         res.Price,
+		Specialist:    res.Specalist,
+		Experience:    int32(res.Experience),
+		ImageVariants: res.ImageVariants,
+		RatingAverage: res.RatingAverage,
+		RatingCount:   res.RatingCount,
+		Specialties:   specialtiesToPb(res.Specialties),
+		Version:       res.Version,
 	}}, nil
 }
 
 func (h *DoctorHandler) DeleteDoctor(ctx context.Context, req *pb.DeleteDoctorReq) (*pb.DoctorResponse, error) {
-	Doctor, err := h.service.Delete(ctx, req.Id, &dto.DeleteDoctorReq{})
+	Doctor, err := h.service.Delete(ctx, req.Id, &dto.DeleteDoctorReq{Version: req.Version})
 	if err != nil {
 		logger.Error("Failed to delete Doctor: ", err)
-		return nil, err
+		return nil, versionError(err)
 	}
 
 	var res dto.Doctor
 	utils.Copy(&res, &Doctor)
-	_ = h.cache.RemovePattern("*Doctor*")
 	return &pb.DoctorResponse{Doctor: &pb.Doctor{
-		Id:         res.ID,
-		IdUser:     res.IDUser,
-		Name:       res.Name,
-		Image:      res.Image,
-		Price:      res.Price,
-		Specialist: res.Specalist,
-		Experience: int32(res.Experience),
+		Id:            res.ID,
+		IdUser:        res.IDUser,
+		Name:          res.Name,
+		Image:         res.Image,
+		Price:         res.Price,
+		Specialist:    res.Specalist,
+		Experience:    int32(res.Experience),
+		ImageVariants: res.ImageVariants,
+		RatingAverage: res.RatingAverage,
+		RatingCount:   res.RatingCount,
+		Specialties:   specialtiesToPb(res.Specialties),
+		Version:       res.Version,
 	}}, nil
 }
+
+func specialtiesToPb(specialties []*dto.DoctorSpecialty) []*pb.DoctorSpecialty {
+	var res []*pb.DoctorSpecialty
+	for _, specialty := range specialties {
+		link := &pb.DoctorSpecialty{SpecialtyId: specialty.SpecialtyID, Primary: specialty.Primary}
+		if specialty.Specialty != nil {
+			link.Slug = specialty.Specialty.Slug
+			link.Name = specialty.Specialty.Names[specialtyModel.DefaultLocale]
+		}
+		res = append(res, link)
+	}
+	return res
+}
+
+// doctorPatch is the merge patch of the fields of req in the update mask
+func doctorPatch(req *pb.UpdateDoctorReq, paths []string) (patch.Patch, error) {
+	fields := make(map[string]interface{}, len(paths))
+	for _, path := range paths {
+		switch path {
+		case "name":
+			fields["name"] = req.Name
+		case "image":
+			fields["image"] = req.Image
+		case "price":
+			fields["price"] = req.Price
+		case "specialist":
+			fields["specalist"] = req.Specialist
+		case "experience":
+			fields["experience"] = req.Experience
+		default:
+			return nil, fmt.Errorf("%w: %q cannot be in update_mask", patch.ErrInvalidPatch, path)
+		}
+	}
+	return patch.Merge(fields), nil
+}
+
+// versionError tells the clients to read the Doctor again when it changed
+// since their version, and to fix invalid update masks
+func versionError(err error) error {
+	if errors.Is(err, dbs.ErrStaleVersion) {
+		return status.New(codes.Aborted, err.Error()).Err()
+	}
+	if patch.StatusCode(err) != 0 {
+		return status.New(codes.InvalidArgument, err.Error()).Err()
+	}
+	return err
+}
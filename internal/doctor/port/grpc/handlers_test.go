@@ -0,0 +1,154 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/quangdangfit/gocommon/logger"
+	"github.com/quangdangfit/gocommon/validation"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"gorm.io/gorm"
+
+	"main/internal/doctor/model"
+	"main/internal/doctor/repository"
+	"main/internal/doctor/service"
+	specialtyModel "main/internal/specialty/model"
+	"main/pkg/audit"
+	"main/pkg/config"
+	"main/pkg/dbs"
+	"main/pkg/events"
+	"main/pkg/redis"
+	pb "main/proto/gen/go/doctor"
+)
+
+func TestMain(m *testing.M) {
+	logger.Initialize(config.ProductionEnv)
+	os.Exit(m.Run())
+}
+
+// doctorRepo keeps the doctors in memory, saving them at their version like
+// dbs.SaveVersion
+type doctorRepo struct {
+	repository.IDoctorRepository
+	doctors map[string]model.Doctor
+}
+
+func (r *doctorRepo) Create(ctx context.Context, doctor *model.Doctor) error {
+	doctor.Version = 1
+	r.doctors[doctor.ID] = *doctor
+	return nil
+}
+
+func (r *doctorRepo) GetDoctorByID(ctx context.Context, id string) (*model.Doctor, error) {
+	doctor, ok := r.doctors[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &doctor, nil
+}
+
+func (r *doctorRepo) Update(ctx context.Context, doctor *model.Doctor) error {
+	if doctor.ID == "" {
+		return dbs.ErrMissingPrimaryKey
+	}
+	if current, ok := r.doctors[doctor.ID]; !ok || current.Version != doctor.Version {
+		return dbs.ErrStaleVersion
+	}
+	doctor.Version++
+	r.doctors[doctor.ID] = *doctor
+	return nil
+}
+
+func newDoctorClient(t *testing.T, repo *doctorRepo) pb.DoctorServiceClient {
+	t.Helper()
+	cache := redis.New(redis.Config{Address: miniredis.RunT(t).Addr(), Timeout: time.Second})
+	svc := service.NewDoctorService(validation.New(), repo, nil, audit.Nop(), events.Nop())
+
+	lis := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	pb.RegisterDoctorServiceServer(server, NewDoctorHandler(cache, svc))
+	go func() { _ = server.Serve(lis) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return pb.NewDoctorServiceClient(conn)
+}
+
+func TestDoctorHandler(t *testing.T) {
+	repo := &doctorRepo{doctors: map[string]model.Doctor{}}
+	client := newDoctorClient(t, repo)
+	ctx := context.Background()
+
+	created, err := client.CreateDoctor(ctx, &pb.CreateDoctorReq{IdUser: "user", Name: "Ada", Price: 10, Specialist: "cardiology", Experience: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := created.Doctor.Id
+	if id == "" || created.Doctor.Version != 1 {
+		t.Fatalf("created = %v", created.Doctor)
+	}
+
+	// the specialties of the catalogue are returned
+	doctor := repo.doctors[id]
+	doctor.Specialties = []*specialtyModel.DoctorSpecialty{{
+		DoctorID: id, SpecialtyID: "cardio", Primary: true,
+		Specialty: &specialtyModel.Specialty{ID: "cardio", Slug: "cardiology", Names: map[string]string{specialtyModel.DefaultLocale: "Cardiology"}},
+	}}
+	repo.doctors[id] = doctor
+	got, err := client.GetDoctorByID(ctx, &pb.GetDoctorByIDRequest{Id: id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if specialties := got.Doctor.Specialties; len(specialties) != 1 || specialties[0].Slug != "cardiology" || !specialties[0].Primary {
+		t.Errorf("specialties = %v", specialties)
+	}
+
+	// without a mask the whole doctor is replaced, its id and user are kept
+	updated, err := client.UpdateDoctor(ctx, &pb.UpdateDoctorReq{Id: id, Name: "Ada L.", Price: 20, Version: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := updated.Doctor; d.Id != id || d.IdUser != "user" || d.Name != "Ada L." || d.Price != 20 || d.Specialist != "" || d.Version != 2 {
+		t.Errorf("updated = %v", d)
+	}
+	if len(repo.doctors) != 1 {
+		t.Errorf("update wrote %d doctors", len(repo.doctors))
+	}
+
+	// the mask only changes its fields
+	patched, err := client.UpdateDoctor(ctx, &pb.UpdateDoctorReq{
+		Id: id, Name: "ignored", Experience: 4, Version: 2,
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"experience"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := patched.Doctor; d.Name != "Ada L." || d.Experience != 4 || d.Version != 3 {
+		t.Errorf("patched = %v", d)
+	}
+
+	_, err = client.UpdateDoctor(ctx, &pb.UpdateDoctorReq{Id: id, Name: "stale", Version: 2})
+	if status.Code(err) != codes.Aborted {
+		t.Errorf("stale update error = %v", err)
+	}
+	_, err = client.UpdateDoctor(ctx, &pb.UpdateDoctorReq{Id: id, Version: 3, UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"status"}}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("update of status error = %v", err)
+	}
+}
@@ -6,14 +6,16 @@ import (
 
 	"main/internal/doctor/repository"
 	"main/internal/doctor/service"
+	"main/pkg/audit"
 	"main/pkg/dbs"
+	"main/pkg/events"
 	"main/pkg/redis"
 	pb "main/proto/gen/go/doctor"
 )
 
-func RegisterHandlers(svr *grpc.Server, db dbs.IDatabase, validator validation.Validation, cache redis.IRedis) {
+func RegisterHandlers(svr *grpc.Server, db dbs.IDatabase, validator validation.Validation, cache redis.IRedis, recorder audit.Recorder, publisher events.Publisher) {
 	DoctorRepo := repository.NewDoctorRepository(db)
-	DoctorSvc := service.NewDoctorService(validator, DoctorRepo)
+	DoctorSvc := service.NewDoctorService(validator, DoctorRepo, nil, recorder, publisher)
 	DoctorHandler := NewDoctorHandler(cache, DoctorSvc)
 
 	pb.RegisterDoctorServiceServer(svr, DoctorHandler)
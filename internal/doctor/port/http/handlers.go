@@ -1,6 +1,7 @@
 package http
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -9,8 +10,13 @@ import (
 	"main/internal/doctor/dto"
 	"main/internal/doctor/service"
 	"main/pkg/config"
+	"main/pkg/dbs"
+	"main/pkg/imaging"
+	"main/pkg/patch"
+	"main/pkg/rbac"
 	"main/pkg/redis"
 	"main/pkg/response"
+	"main/pkg/tenant"
 	"main/pkg/utils"
 )
 
@@ -36,11 +42,15 @@ func NewDoctorHandler(
 //	@Summary	Get Doctor by id
 //	@Tags		Doctor
 //	@Produce	json
-//	@Param		id	path	string	true	"Doctor ID"
+//	@Param		id				path	string	true	"Doctor ID"
+//	@Param		If-None-Match	header	string	false	"ETag of the doctor already read"
 //	@Success	200	{object}	dto.Doctor
+//	@Success	304	"Not changed since If-None-Match"
 //	@Router		/doctor/{id} [get]
 func (p *DoctorHandler) GetDoctorByID(c *gin.Context) {
 
+	// never cached, the cache is cleared after the changes and its ETag
+	// could be older than the version the next If-Match is checked against
 	DoctorId := c.Param("id")
 	Doctor, err := p.service.GetDoctorByID(c, DoctorId)
 	if err != nil {
@@ -50,15 +60,8 @@ func (p *DoctorHandler) GetDoctorByID(c *gin.Context) {
 	}
 
 	var res dto.Doctor
-	cacheKey := c.Request.URL.RequestURI()
-	err2 := p.cache.Get(cacheKey, &res)
-	if err2 == nil {
-		response.JSON(c, http.StatusOK, res)
-		return
-	}
 	utils.Copy(&res, &Doctor)
-	response.JSON(c, http.StatusOK, res)
-	_ = p.cache.SetWithExpiration(cacheKey, res, config.DoctorCachingTime.Abs())
+	response.Versioned(c, http.StatusOK, res.Version, res)
 }
 
 // ListDoctor godoc
@@ -70,8 +73,10 @@ func (p *DoctorHandler) GetDoctorByID(c *gin.Context) {
 // @Param		id_user	query	string	false	"ID User"
 // @Param		page	query	int64	false	"Page number"
 // @Param		limit	query	int64	false	"Limit per page"
-// @Param		order_by	query	string	false	"Order by field"
+// @Param		order_by	query	string	false	"name, price, experience, created_at, rating_average or rating_count"
 // @Param		order_desc	query	bool	false	"Order descending"
+// @Param		min_rating	query	number	false	"Minimum average rating"
+// @Param		include_deleted	query	bool	false	"Also list the deleted doctors, for admins"
 // @Success	200	{object}	dto.ListDoctorRes
 // @Router		/doctor/list_doctors [get]
 func (p *DoctorHandler) ListDoctors(c *gin.Context) {
@@ -82,15 +87,24 @@ func (p *DoctorHandler) ListDoctors(c *gin.Context) {
 		return
 	}
 
+	if req.IncludeDeleted && !rbac.Has(c.GetStringSlice("permissions"), rbac.DeletedManage) {
+		response.Error(c, http.StatusForbidden, errors.New("forbidden"), "Forbidden")
+		return
+	}
+
 	var res dto.ListDoctorRes
-	cacheKey := c.Request.URL.RequestURI()
-	err := p.cache.Get(cacheKey, &res)
+	cacheKey := tenant.CacheKey(c, c.Request.URL.RequestURI())
+	err := p.cache.Get(c, cacheKey, &res)
 	if err == nil {
 		response.JSON(c, http.StatusOK, res)
 		return
 	}
 
 	Doctors, pagination, err := p.service.ListDoctors(c, &req)
+	if errors.Is(err, service.ErrInvalidFilter) {
+		response.Error(c, http.StatusBadRequest, err, "Invalid parameters")
+		return
+	}
 	if err != nil {
 		logger.Error("Failed to get list of Doctors: ", err)
 		response.Error(c, http.StatusInternalServerError, err, "Something went wrong")
@@ -100,7 +114,7 @@ func (p *DoctorHandler) ListDoctors(c *gin.Context) {
 	utils.Copy(&res.Doctors, &Doctors)
 	res.Pagination = pagination
 	response.JSON(c, http.StatusOK, res)
-	_ = p.cache.SetWithExpiration(cacheKey, res, config.DoctorCachingTime)
+	_ = p.cache.SetWithExpiration(c, cacheKey, res, config.DoctorCachingTime)
 }
 
 // CreateDoctor godoc
@@ -109,8 +123,11 @@ func (p *DoctorHandler) ListDoctors(c *gin.Context) {
 //	@Tags		Doctor
 //	@Produce	json
 //	@Security	ApiKeyAuth
+//	@Param		Idempotency-Key	header	string	false	"Key of the request and its retries, which get the first response"
 //	@Param		_	body	dto.CreateDoctorReq	true	"Body"
 //	@Success	200	{object}	dto.Doctor
+//	@Failure	409	{object}	response.Response	"The first request with the key is running"
+//	@Failure	422	{object}	response.Response	"The key was used with another body"
 //	@Router		/doctor [post]
 func (p *DoctorHandler) CreateDoctor(c *gin.Context) {
 	var req dto.CreateDoctorReq
@@ -130,7 +147,6 @@ func (p *DoctorHandler) CreateDoctor(c *gin.Context) {
 	var res dto.Doctor
 	utils.Copy(&res, &Doctor)
 	response.JSON(c, http.StatusOK, res)
-	_ = p.cache.RemovePattern("*Doctor*")
 }
 
 // UpdateDoctor godoc
@@ -139,12 +155,19 @@ func (p *DoctorHandler) CreateDoctor(c *gin.Context) {
 //	@Tags		Doctor
 //	@Produce	json
 //	@Security	ApiKeyAuth
+//	@Param		If-Match	header	string	true	"ETag of the doctor, its version"
 //	@Param		_	body	dto.UpdateDoctorReq	true	"Body"
 //	@Success	200	{object}	dto.Doctor
+//	@Failure	412	{object}	response.Response	"Changed since it was read"
 //	@Router		/doctor/{id} [put]
 func (p *DoctorHandler) UpdateDoctor(c *gin.Context) {
 	//	@Param		id	path	string					true	"Doctor ID"
 	// DoctorId := c.Param("id")
+	version, ok := response.IfMatch(c)
+	if !ok {
+		return
+	}
+
 	var req dto.UpdateDoctorReq
 	if err := c.ShouldBindJSON(&req); c.Request.Body == nil || err != nil {
 		logger.Error("Failed to get body", err)
@@ -152,7 +175,12 @@ func (p *DoctorHandler) UpdateDoctor(c *gin.Context) {
 		return
 	}
 
+	req.Version = version
 	Doctor, err := p.service.Update(c, req.ID, &req)
+	if errors.Is(err, dbs.ErrStaleVersion) {
+		response.StaleVersion(c, err)
+		return
+	}
 	if err != nil {
 		logger.Error("Failed to Update Doctor", err.Error())
 		response.Error(c, http.StatusInternalServerError, err, "Something went wrong")
@@ -161,8 +189,56 @@ func (p *DoctorHandler) UpdateDoctor(c *gin.Context) {
 
 	var res dto.Doctor
 	utils.Copy(&res, &Doctor)
-	response.JSON(c, http.StatusOK, res)
-	_ = p.cache.RemovePattern("*Doctor*")
+	response.Versioned(c, http.StatusOK, res.Version, res)
+}
+
+// PatchDoctor godoc
+//
+//	@Summary	Change some fields of a Doctor
+//	@Description	JSON Merge Patch (application/merge-patch+json, also for application/json) or JSON Patch (application/json-patch+json) of the fields of UpdateDoctorReq, the other fields are kept
+//	@Tags		Doctor
+//	@Accept		json
+//	@Produce	json
+//	@Security	ApiKeyAuth
+//	@Param		id			path	string	true	"Doctor ID"
+//	@Param		If-Match	header	string	true	"ETag of the doctor, its version"
+//	@Param		_	body	dto.PatchDoctorReq	true	"Body"
+//	@Success	200	{object}	dto.Doctor
+//	@Failure	409	{object}	response.Response	"A test operation failed"
+//	@Failure	412	{object}	response.Response	"Changed since it was read"
+//	@Failure	415	{object}	response.Response	"Not a patch media type"
+//	@Failure	422	{object}	response.Response	"Invalid or read-only fields"
+//	@Router		/doctor/{id} [patch]
+func (p *DoctorHandler) PatchDoctor(c *gin.Context) {
+	version, ok := response.IfMatch(c)
+	if !ok {
+		return
+	}
+
+	changes, err := patch.FromRequest(c.Request)
+	if err != nil {
+		response.Error(c, patch.StatusCode(err), err, err.Error())
+		return
+	}
+
+	Doctor, err := p.service.Patch(c, c.Param("id"), version, changes)
+	if errors.Is(err, dbs.ErrStaleVersion) {
+		response.StaleVersion(c, err)
+		return
+	}
+	if status := patch.StatusCode(err); status != 0 {
+		response.Error(c, status, err, err.Error())
+		return
+	}
+	if err != nil {
+		logger.Error("Failed to Patch Doctor", err.Error())
+		response.Error(c, http.StatusInternalServerError, err, "Something went wrong")
+		return
+	}
+
+	var res dto.Doctor
+	utils.Copy(&res, &Doctor)
+	response.Versioned(c, http.StatusOK, res.Version, res)
 }
 
 // DeleteDoctor godoc
@@ -171,12 +247,19 @@ func (p *DoctorHandler) UpdateDoctor(c *gin.Context) {
 //	@Tags		Doctor
 //	@Produce	json
 //	@Security	ApiKeyAuth
+//	@Param		If-Match	header	string	true	"ETag of the doctor, its version"
 //	@Param		_	body	dto.DeleteDoctorReq	true	"Body"
 //	@Success	200	{object}	dto.Doctor
+//	@Failure	412	{object}	response.Response	"Changed since it was read"
 //	@Router		/doctor/{id} [Delete]
 func (p *DoctorHandler) DeleteDoctor(c *gin.Context) {
 	//	@Param		id	path	string					true	"Doctor ID"
 	// DoctorId := c.Param("id")
+	version, ok := response.IfMatch(c)
+	if !ok {
+		return
+	}
+
 	var req dto.DeleteDoctorReq
 	if err := c.ShouldBindJSON(&req); c.Request.Body == nil || err != nil {
 		logger.Error("Failed to get body", err)
@@ -184,7 +267,12 @@ func (p *DoctorHandler) DeleteDoctor(c *gin.Context) {
 		return
 	}
 
+	req.Version = version
 	Doctor, err := p.service.Delete(c, req.ID, &req)
+	if errors.Is(err, dbs.ErrStaleVersion) {
+		response.StaleVersion(c, err)
+		return
+	}
 	if err != nil {
 		logger.Error("Failed to Delete Doctor", err.Error())
 		response.Error(c, http.StatusInternalServerError, err, "Something went wrong")
@@ -194,5 +282,64 @@ func (p *DoctorHandler) DeleteDoctor(c *gin.Context) {
 	var res dto.Doctor
 	utils.Copy(&res, &Doctor)
 	response.JSON(c, http.StatusOK, res)
-	_ = p.cache.RemovePattern("*Doctor*")
+}
+
+// RestoreDoctor godoc
+//
+//	@Summary	Restore a deleted doctor
+//	@Tags		Doctor
+//	@Produce	json
+//	@Security	ApiKeyAuth
+//	@Param		id	path		string	true	"Doctor ID"
+//	@Success	200	{object}	dto.Doctor
+//	@Router		/doctor-admin/doctors/{id}/restore [post]
+func (p *DoctorHandler) RestoreDoctor(c *gin.Context) {
+	doctor, err := p.service.Restore(c, c.Param("id"))
+	if errors.Is(err, service.ErrDoctorNotDeleted) {
+		response.Error(c, http.StatusNotFound, err, err.Error())
+		return
+	}
+	if err != nil {
+		logger.Error("Failed to restore doctor ", err)
+		response.Error(c, http.StatusInternalServerError, err, "Something went wrong")
+		return
+	}
+
+	var res dto.Doctor
+	utils.Copy(&res, &doctor)
+	response.JSON(c, http.StatusOK, res)
+}
+
+// SetImage godoc
+//
+//	@Summary	Set an uploaded image as the picture of the signed-in doctor
+//	@Tags		Doctor
+//	@Produce	json
+//	@Security	ApiKeyAuth
+//	@Param		_	body	dto.SetImageReq	true	"Body"
+//	@Success	202
+//	@Router		/doctor/image [put]
+func (p *DoctorHandler) SetImage(c *gin.Context) {
+	var req dto.SetImageReq
+	if err := c.ShouldBindJSON(&req); c.Request.Body == nil || err != nil {
+		logger.Error("Failed to get body", err)
+		response.Error(c, http.StatusBadRequest, err, "Invalid parameters")
+		return
+	}
+
+	if err := p.service.SetImage(c, c.GetString("userId"), &req); err != nil {
+		logger.Error("Failed to set Doctor image", err.Error())
+		switch {
+		case errors.Is(err, service.ErrDoctorNotFound):
+			response.Error(c, http.StatusNotFound, err, "Create your doctor profile first")
+		case errors.Is(err, imaging.ErrQueueFull):
+			response.Error(c, http.StatusServiceUnavailable, err, err.Error())
+		default:
+			response.Error(c, http.StatusBadRequest, err, err.Error())
+		}
+		return
+	}
+
+	// the picture is set once resized
+	response.JSON(c, http.StatusAccepted, nil)
 }
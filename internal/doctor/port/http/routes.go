@@ -6,23 +6,48 @@ import (
 
 	"main/internal/doctor/repository"
 	"main/internal/doctor/service"
+	"main/pkg/audit"
 	"main/pkg/dbs"
+	"main/pkg/events"
+	"main/pkg/idempotency"
 	"main/pkg/middleware"
+	"main/pkg/rbac"
 	"main/pkg/redis"
 )
 
-func Routes(r *gin.RouterGroup, sqlDB dbs.IDatabase, validator validation.Validation, cache redis.IRedis) {
+func Routes(r *gin.RouterGroup, sqlDB dbs.IDatabase, validator validation.Validation, cache redis.IRedis, auth *middleware.Authenticator, keys *idempotency.Keys, images service.ImageProcessor, recorder audit.Recorder, publisher events.Publisher) {
 	doctorRepo := repository.NewDoctorRepository(sqlDB)
-	doctorSvc := service.NewDoctorService(validator, doctorRepo)
+	doctorSvc := service.NewDoctorService(validator, doctorRepo, images, recorder, publisher)
 	doctorHandler := NewDoctorHandler(cache, doctorSvc)
 
-	authMiddleware := middleware.JWTAuth()
+	authMiddleware := middleware.JWTPermission(auth, rbac.DoctorsWrite)
+	userAuthMiddleware := middleware.JWTAuth(auth)
+	doctorsVerify := middleware.JWTPermission(auth, rbac.DoctorsVerify)
+	deletedManage := middleware.JWTPermission(auth, rbac.DeletedManage)
+	// retried creations return the first doctor
+	idempotent := middleware.Idempotency(keys)
 	doctorRoute := r.Group("/doctor")
 	{
 		doctorRoute.GET("/list_doctors", doctorHandler.ListDoctors)
+		// license of the signed-in doctor
+		doctorRoute.POST("/verification", userAuthMiddleware, doctorHandler.SubmitVerification)
+		doctorRoute.GET("/verification", userAuthMiddleware, doctorHandler.GetVerification)
+		doctorRoute.PUT("/image", userAuthMiddleware, doctorHandler.SetImage)
 		doctorRoute.GET("/:id", doctorHandler.GetDoctorByID)
-		doctorRoute.POST("", authMiddleware, doctorHandler.CreateDoctor)
+		doctorRoute.POST("", authMiddleware, idempotent, doctorHandler.CreateDoctor)
 		doctorRoute.PUT("/:id", authMiddleware, doctorHandler.UpdateDoctor)
+		doctorRoute.PATCH("/:id", authMiddleware, doctorHandler.PatchDoctor)
 		doctorRoute.DELETE("/:id", authMiddleware, doctorHandler.DeleteDoctor)
 	}
+
+	// review queue of the submitted licenses
+	doctorRouteAdmin := r.Group("/doctor-admin")
+	{
+		doctorRouteAdmin.GET("/verifications", doctorsVerify, doctorHandler.ListVerifications)
+		doctorRouteAdmin.POST("/verifications/:id/approve", doctorsVerify, doctorHandler.ApproveVerification)
+		doctorRouteAdmin.POST("/verifications/:id/reject", doctorsVerify, doctorHandler.RejectVerification)
+		// deleted doctors, listed with include_deleted
+		doctorRouteAdmin.GET("/doctors", deletedManage, doctorHandler.ListDoctors)
+		doctorRouteAdmin.POST("/doctors/:id/restore", deletedManage, doctorHandler.RestoreDoctor)
+	}
 }
@@ -0,0 +1,163 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/quangdangfit/gocommon/logger"
+
+	"main/internal/doctor/dto"
+	"main/internal/doctor/service"
+	"main/pkg/response"
+	"main/pkg/utils"
+)
+
+// SubmitVerification GetVerification ListVerifications ApproveVerification RejectVerification
+
+// SubmitVerification godoc
+//
+//	@Summary	Submit the license of the signed-in doctor for review
+//	@Tags		Doctor-verification
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		_	body		dto.SubmitVerificationReq	true	"Body"
+//	@Success	200	{object}	dto.Verification
+//	@Router		/doctor/verification [post]
+func (p *DoctorHandler) SubmitVerification(c *gin.Context) {
+	var req dto.SubmitVerificationReq
+	if err := c.ShouldBindJSON(&req); c.Request.Body == nil || err != nil {
+		logger.Error("Failed to get body", err)
+		response.Error(c, http.StatusBadRequest, err, "Invalid parameters")
+		return
+	}
+
+	verification, err := p.service.SubmitVerification(c, c.GetString("userId"), &req)
+	if err != nil {
+		logger.Error("Failed to submit verification ", err)
+		verificationError(c, err)
+		return
+	}
+
+	var res dto.Verification
+	utils.Copy(&res, &verification)
+	response.JSON(c, http.StatusOK, res)
+}
+
+// GetVerification godoc
+//
+//	@Summary	Get the last verification of the signed-in doctor
+//	@Tags		Doctor-verification
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Success	200	{object}	dto.Verification
+//	@Router		/doctor/verification [get]
+func (p *DoctorHandler) GetVerification(c *gin.Context) {
+	verification, err := p.service.GetVerification(c, c.GetString("userId"))
+	if err != nil {
+		logger.Error("Failed to get verification ", err)
+		verificationError(c, err)
+		return
+	}
+
+	var res dto.Verification
+	utils.Copy(&res, &verification)
+	response.JSON(c, http.StatusOK, res)
+}
+
+// ListVerifications godoc
+//
+//	@Summary	List the verifications to review, oldest first
+//	@Tags		Doctor-verification
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		status	query		string	false	"pending, approved or rejected, pending by default"
+//	@Param		page	query		int64	false	"Page number"
+//	@Param		limit	query		int64	false	"Limit per page"
+//	@Success	200		{object}	dto.ListVerificationsRes
+//	@Router		/doctor-admin/verifications [get]
+func (p *DoctorHandler) ListVerifications(c *gin.Context) {
+	var req dto.ListVerificationsReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.Error("Failed to get query params", err)
+		response.Error(c, http.StatusBadRequest, err, "Invalid parameters")
+		return
+	}
+
+	verifications, pagination, err := p.service.ListVerifications(c, &req)
+	if err != nil {
+		logger.Error("Failed to list verifications ", err)
+		response.Error(c, http.StatusInternalServerError, err, "Something went wrong")
+		return
+	}
+
+	var res dto.ListVerificationsRes
+	utils.Copy(&res.Verifications, &verifications)
+	res.Pagination = pagination
+	response.JSON(c, http.StatusOK, res)
+}
+
+// ApproveVerification godoc
+//
+//	@Summary	Approve a verification, the doctor is listed and can be booked
+//	@Tags		Doctor-verification
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		id	path		string	true	"Verification ID"
+//	@Success	200	{object}	dto.Verification
+//	@Router		/doctor-admin/verifications/{id}/approve [post]
+func (p *DoctorHandler) ApproveVerification(c *gin.Context) {
+	verification, err := p.service.ApproveVerification(c, c.GetString("userId"), c.Param("id"))
+	if err != nil {
+		logger.Error("Failed to approve verification ", err)
+		verificationError(c, err)
+		return
+	}
+
+	var res dto.Verification
+	utils.Copy(&res, &verification)
+	response.JSON(c, http.StatusOK, res)
+}
+
+// RejectVerification godoc
+//
+//	@Summary	Reject a verification with the reason
+//	@Tags		Doctor-verification
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		id	path		string						true	"Verification ID"
+//	@Param		_	body		dto.RejectVerificationReq	true	"Body"
+//	@Success	200	{object}	dto.Verification
+//	@Router		/doctor-admin/verifications/{id}/reject [post]
+func (p *DoctorHandler) RejectVerification(c *gin.Context) {
+	var req dto.RejectVerificationReq
+	if err := c.ShouldBindJSON(&req); c.Request.Body == nil || err != nil {
+		logger.Error("Failed to get body", err)
+		response.Error(c, http.StatusBadRequest, err, "Invalid parameters")
+		return
+	}
+
+	verification, err := p.service.RejectVerification(c, c.GetString("userId"), c.Param("id"), &req)
+	if err != nil {
+		logger.Error("Failed to reject verification ", err)
+		verificationError(c, err)
+		return
+	}
+
+	var res dto.Verification
+	utils.Copy(&res, &verification)
+	response.JSON(c, http.StatusOK, res)
+}
+
+func verificationError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrDoctorNotFound):
+		response.Error(c, http.StatusNotFound, err, "Create your doctor profile first")
+	case errors.Is(err, service.ErrVerificationNotFound):
+		response.Error(c, http.StatusNotFound, err, "Verification not found")
+	case errors.Is(err, service.ErrVerificationPending), errors.Is(err, service.ErrVerificationReviewed):
+		response.Error(c, http.StatusConflict, err, err.Error())
+	default:
+		response.Error(c, http.StatusBadRequest, err, err.Error())
+	}
+}
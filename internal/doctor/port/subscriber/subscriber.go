@@ -0,0 +1,21 @@
+package subscriber
+
+import (
+	"context"
+
+	"main/pkg/events"
+	"main/pkg/redis"
+	"main/pkg/tenant"
+)
+
+// Subscribe removes the cached doctors and lists of doctors when a doctor
+// changes, or a specialty whose name they show
+func Subscribe(ctx context.Context, bus *events.Bus, cache redis.IRedis) error {
+	return bus.Subscribe(ctx, "doctor-cache", func(ctx context.Context, _ *events.Event) error {
+		if err := cache.RemovePattern(ctx, tenant.CachePrefix+"*doctor*"); err != nil {
+			return err
+		}
+		// keys of the grpc handlers
+		return cache.RemovePattern(ctx, tenant.CachePrefix+"*Doctor*")
+	}, events.DoctorCreated, events.DoctorUpdated, events.DoctorDeleted, events.SpecialtyChanged)
+}
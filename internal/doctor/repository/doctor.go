@@ -2,13 +2,21 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"main/internal/doctor/dto"
 	"main/internal/doctor/model"
 	"main/pkg/config"
 	"main/pkg/dbs"
+	"main/pkg/imaging"
 	"main/pkg/paging"
+	"main/pkg/tenant"
 )
 
 //go:generate mockery --name=IDoctorRepository
@@ -18,6 +26,16 @@ type IDoctorRepository interface {
 	Update(ctx context.Context, Doctor *model.Doctor) error
 	ListDoctors(ctx context.Context, req *dto.ListDoctorReq) ([]*model.Doctor, *paging.Pagination, error)
 	GetDoctorByID(ctx context.Context, id string) (*model.Doctor, error)
+	GetDoctorByUserID(ctx context.Context, userID string) (*model.Doctor, error)
+	SubmitVerification(ctx context.Context, verification *model.Verification) (bool, error)
+	GetVerification(ctx context.Context, id string) (*model.Verification, error)
+	GetLatestVerification(ctx context.Context, doctorID string) (*model.Verification, error)
+	ListVerifications(ctx context.Context, req *dto.ListVerificationsReq) ([]*model.Verification, *paging.Pagination, error)
+	ReviewVerification(ctx context.Context, verification *model.Verification) (bool, error)
+	SuspendExpiredLicenses(ctx context.Context, now time.Time) (int64, error)
+	UpdateImage(ctx context.Context, id string, variants imaging.Variants) error
+	Restore(ctx context.Context, id string) (bool, error)
+	Purge(ctx context.Context, before time.Time) (int64, error)
 }
 
 type DoctorRepo struct {
@@ -29,6 +47,16 @@ type DoctorRepo struct {
 // 	panic("unimplemented")
 // }
 
+// orderColumns are the columns doctors can be listed by
+var orderColumns = map[string]bool{
+	"name":           true,
+	"price":          true,
+	"experience":     true,
+	"created_at":     true,
+	"rating_average": true,
+	"rating_count":   true,
+}
+
 func NewDoctorRepository(db dbs.IDatabase) *DoctorRepo {
 	return &DoctorRepo{db: db}
 }
@@ -37,33 +65,55 @@ func (r *DoctorRepo) ListDoctors(ctx context.Context, req *dto.ListDoctorReq) ([
 	ctx, cancel := context.WithTimeout(ctx, config.DatabaseTimeout)
 	defer cancel()
 
-	query := make([]dbs.Query, 0)
-	if req.Search != "" || req.Search != " " {
+	// unverified doctors are hidden
+	query := []dbs.Query{dbs.NewQuery("status = ?", model.DoctorVerified)}
+	if strings.TrimSpace(req.Search) != "" {
 		query = append(query, dbs.NewQuery("name LIKE ?", "%"+req.Search+"%"))
-	} else {
-		query = append(query, dbs.NewQuery(""))
 	}
 	// if req.Code != "" {
 	// 	query = append(query, dbs.NewQuery("code = ?", req.Code))
 	// }
 
+	if req.Specialty != "" {
+		query = append(query, dbs.NewQuery(`id IN (
+			SELECT ds.doctor_id FROM doctor_specialties ds WHERE ds.specialty_id IN (
+				WITH RECURSIVE tree AS (
+					SELECT id FROM specialties WHERE slug = ?
+					UNION SELECT s.id FROM specialties s JOIN tree ON s.parent_id = tree.id
+				) SELECT id FROM tree
+			)
+		)`, req.Specialty))
+	}
+	if req.MinRating > 0 {
+		query = append(query, dbs.NewQuery("rating_average >= ?", req.MinRating))
+	}
+
 	// Construct order criteria
 	orderList := req.OrderList
-	order := ""
-	OrderDes := ""
+	if req.OrderBy != "" {
+		orderList = append([]dto.OrderBy{{OrderBy: req.OrderBy, OrderDesc: req.OrderDesc}}, orderList...)
+	}
+	var orders []string
 	for _, i := range orderList {
+		// columns are whitelisted, the order is not a query parameter
+		if !orderColumns[i.OrderBy] {
+			return nil, nil, fmt.Errorf("cannot order by %q", i.OrderBy)
+		}
 		if i.OrderDesc {
-			OrderDes = " DESC"
+			orders = append(orders, i.OrderBy+" DESC")
 		} else {
-			OrderDes = ""
-
+			orders = append(orders, i.OrderBy)
 		}
-		order += (i.OrderBy + OrderDes)
+	}
+	order := strings.Join(orders, ", ")
 
+	opts := []dbs.FindOption{dbs.WithQuery(query...)}
+	if req.IncludeDeleted {
+		opts = append(opts, dbs.WithDeleted())
 	}
 
 	var total int64
-	if err := r.db.Count(ctx, &model.Doctor{}, &total, dbs.WithQuery(query...)); err != nil {
+	if err := r.db.Count(ctx, &model.Doctor{}, &total, opts...); err != nil {
 		return nil, nil, err
 	}
 
@@ -73,10 +123,12 @@ func (r *DoctorRepo) ListDoctors(ctx context.Context, req *dto.ListDoctorReq) ([
 	if err := r.db.Find(
 		ctx,
 		&Doctors,
-		dbs.WithQuery(query...),
-		dbs.WithLimit(int(pagination.Limit)),
-		dbs.WithOffset(int(pagination.Skip)),
-		dbs.WithOrder(order),
+		append(opts,
+			dbs.WithLimit(int(pagination.Limit)),
+			dbs.WithOffset(int(pagination.Skip)),
+			dbs.WithOrder(order),
+			dbs.WithPreload([]string{"Specialties.Specialty"}),
+		)...,
 	); err != nil {
 		return nil, nil, err
 	}
@@ -87,7 +139,11 @@ func (r *DoctorRepo) ListDoctors(ctx context.Context, req *dto.ListDoctorReq) ([
 
 func (r *DoctorRepo) GetDoctorByID(ctx context.Context, id string) (*model.Doctor, error) {
 	var Doctor model.Doctor
-	if err := r.db.FindById(ctx, id, &Doctor); err != nil {
+	err := r.db.GetDB().WithContext(ctx).
+		Preload("Specialties.Specialty").
+		Where("id = ?", id).
+		First(&Doctor).Error
+	if err != nil {
 		return nil, err
 	}
 	return &Doctor, nil
@@ -105,9 +161,211 @@ func (r *DoctorRepo) Create(ctx context.Context, doctor *model.Doctor) error {
 	}
 }
 
+// Update saves the doctor without its specialties, which are set apart, if
+// it is still at its version
 func (r *DoctorRepo) Update(ctx context.Context, Doctor *model.Doctor) error {
-	return r.db.Update(ctx, Doctor)
+	return dbs.SaveVersion(r.db.GetDB().WithContext(ctx).Omit(clause.Associations), Doctor)
 }
+
+// Delete deletes the doctor if it is still at its version
 func (r *DoctorRepo) Delete(ctx context.Context, Doctor *model.Doctor) error {
-	return r.db.Delete(ctx, Doctor)
+	return dbs.DeleteVersion(r.db.GetDB().WithContext(ctx), Doctor, Doctor.Version)
+}
+
+func (r *DoctorRepo) GetDoctorByUserID(ctx context.Context, userID string) (*model.Doctor, error) {
+	var doctor model.Doctor
+	if err := r.db.GetDB().WithContext(ctx).Where("id_user = ?", userID).First(&doctor).Error; err != nil {
+		return nil, err
+	}
+	return &doctor, nil
+}
+
+// SubmitVerification queues the verification, false when one of the doctor
+// is already waiting for review. A verified doctor renewing its license stays
+// verified meanwhile.
+func (r *DoctorRepo) SubmitVerification(ctx context.Context, verification *model.Verification) (bool, error) {
+	created := false
+	err := r.db.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var pending int64
+		err := tx.Model(&model.Verification{}).
+			Where("doctor_id = ? AND status = ?", verification.DoctorID, model.VerificationPending).
+			Count(&pending).Error
+		if err != nil || pending > 0 {
+			return err
+		}
+		if err := tx.Create(verification).Error; err != nil {
+			return err
+		}
+		err = tx.Model(&model.Doctor{}).
+			Where("id = ? AND status <> ?", verification.DoctorID, model.DoctorVerified).
+			Update("status", model.DoctorPending).Error
+		if err != nil {
+			return err
+		}
+		created = true
+		return nil
+	})
+	return created, err
+}
+
+func (r *DoctorRepo) GetVerification(ctx context.Context, id string) (*model.Verification, error) {
+	var verification model.Verification
+	if err := r.db.GetDB().WithContext(ctx).Where("id = ?", id).First(&verification).Error; err != nil {
+		return nil, err
+	}
+	return &verification, nil
+}
+
+func (r *DoctorRepo) GetLatestVerification(ctx context.Context, doctorID string) (*model.Verification, error) {
+	var verification model.Verification
+	err := r.db.GetDB().WithContext(ctx).
+		Where("doctor_id = ?", doctorID).
+		Order("created_at DESC").
+		First(&verification).Error
+	if err != nil {
+		return nil, err
+	}
+	return &verification, nil
+}
+
+func (r *DoctorRepo) ListVerifications(ctx context.Context, req *dto.ListVerificationsReq) ([]*model.Verification, *paging.Pagination, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.DatabaseTimeout)
+	defer cancel()
+
+	status := req.Status
+	if status == "" {
+		status = model.VerificationPending
+	}
+	query := dbs.NewQuery("status = ?", status)
+
+	var total int64
+	if err := r.db.Count(ctx, &model.Verification{}, &total, dbs.WithQuery(query)); err != nil {
+		return nil, nil, err
+	}
+
+	pagination := paging.New(req.Page, req.Limit, total)
+
+	var verifications []*model.Verification
+	if err := r.db.Find(
+		ctx,
+		&verifications,
+		dbs.WithQuery(query),
+		dbs.WithLimit(int(pagination.Limit)),
+		dbs.WithOffset(int(pagination.Skip)),
+		dbs.WithOrder("created_at"),
+	); err != nil {
+		return nil, nil, err
+	}
+
+	return verifications, pagination, nil
+}
+
+// ReviewVerification records the review of a pending verification and updates
+// the doctor, false when the verification was already reviewed. An approval
+// replaces the license of the doctor, a rejection leaves a verified doctor
+// with its current license.
+func (r *DoctorRepo) ReviewVerification(ctx context.Context, verification *model.Verification) (bool, error) {
+	reviewed := false
+	err := r.db.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.Verification{}).
+			Where("id = ? AND status = ?", verification.ID, model.VerificationPending).
+			Updates(map[string]interface{}{
+				"status":      verification.Status,
+				"reason":      verification.Reason,
+				"reviewed_by": verification.ReviewedBy,
+				"reviewed_at": verification.ReviewedAt,
+			})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		doctor := tx.Model(&model.Doctor{}).Where("id = ?", verification.DoctorID)
+		var err error
+		if verification.Status == model.VerificationApproved {
+			err = doctor.Updates(map[string]interface{}{
+				"status":             model.DoctorVerified,
+				"license_number":     verification.LicenseNumber,
+				"issuing_authority":  verification.IssuingAuthority,
+				"license_expires_at": verification.LicenseExpiresAt,
+			}).Error
+		} else {
+			err = doctor.Where("status <> ?", model.DoctorVerified).
+				Update("status", model.DoctorRejected).Error
+		}
+		if err != nil {
+			return err
+		}
+		reviewed = true
+		return nil
+	})
+	return reviewed, err
+}
+
+// SuspendExpiredLicenses suspends the verified doctors whose license expired
+// by now and returns how many were
+func (r *DoctorRepo) SuspendExpiredLicenses(ctx context.Context, now time.Time) (int64, error) {
+	result := r.db.GetDB().WithContext(ctx).Model(&model.Doctor{}).
+		Where("status = ? AND license_expires_at <= ?", model.DoctorVerified, now).
+		Update("status", model.DoctorSuspended)
+	return result.RowsAffected, result.Error
+}
+
+// UpdateImage sets the picture variants, Image being the large jpeg one
+func (r *DoctorRepo) UpdateImage(ctx context.Context, id string, variants imaging.Variants) error {
+	return r.db.GetDB().WithContext(ctx).Model(&model.Doctor{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"image":          variants[model.ImageVariant],
+			"image_variants": variants,
+		}).Error
+}
+
+// Restore restores the deleted doctor id, false when it is not deleted or
+// its user has another doctor profile since
+func (r *DoctorRepo) Restore(ctx context.Context, id string) (bool, error) {
+	var doctor model.Doctor
+	err := r.db.GetDB().WithContext(ctx).Unscoped().
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		First(&doctor).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	var active int64
+	if err := r.db.GetDB().WithContext(ctx).Model(&model.Doctor{}).Where("id_user = ?", doctor.IDUser).Count(&active).Error; err != nil {
+		return false, err
+	}
+	if active > 0 {
+		return false, nil
+	}
+	return dbs.Restore(r.db.GetDB().WithContext(ctx), &model.Doctor{}, id)
+}
+
+// Purge hard deletes the doctors deleted before before, with their
+// verifications and reviews. The doctors of consultations are kept, the
+// records refer to them.
+func (r *DoctorRepo) Purge(ctx context.Context, before time.Time) (int64, error) {
+	var purged int64
+	err := r.db.GetDB().WithContext(tenant.Global(ctx)).Transaction(func(tx *gorm.DB) error {
+		var ids []string
+		err := tx.Unscoped().Model(&model.Doctor{}).
+			Where("deleted_at < ? AND id NOT IN (SELECT doctor_id FROM consultations)", before).
+			Pluck("id", &ids).Error
+		if err != nil || len(ids) == 0 {
+			return err
+		}
+
+		for _, table := range []string{"doctor_verifications", "reviews", "doctor_specialties"} {
+			if err := tx.Exec("DELETE FROM "+table+" WHERE doctor_id IN ?", ids).Error; err != nil {
+				return err
+			}
+		}
+		result := tx.Unscoped().Where("id IN ?", ids).Delete(&model.Doctor{})
+		purged = result.RowsAffected
+		return result.Error
+	})
+	return purged, err
 }
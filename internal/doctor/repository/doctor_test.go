@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"main/internal/doctor/dto"
+	"main/internal/doctor/model"
+	"main/pkg/dbs"
+)
+
+// dryRun is a database building the queries without running them, each
+// query run is appended to queries with its vars
+func dryRun(t *testing.T, queries *[]*gorm.Statement) dbs.IDatabase {
+	t.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{
+		DryRun:                 true,
+		SkipDefaultTransaction: true,
+		DisableAutomaticPing:   true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = db.Callback().Query().After("gorm:query").Register("test:record", func(tx *gorm.DB) {
+		*queries = append(*queries, tx.Statement)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dbs.Wrap(db)
+}
+
+func TestListDoctorsVerifiedOnly(t *testing.T) {
+	var queries []*gorm.Statement
+	repo := NewDoctorRepository(dryRun(t, &queries))
+
+	for _, req := range []*dto.ListDoctorReq{{}, {Search: "Ada", MinRating: 4}, {IncludeDeleted: true}} {
+		queries = nil
+		if _, _, err := repo.ListDoctors(context.Background(), req); err != nil {
+			t.Fatal(err)
+		}
+		if len(queries) != 2 {
+			t.Fatalf("ListDoctors(%+v) ran %d queries, want the count and the page", req, len(queries))
+		}
+		for _, query := range queries {
+			sql := query.SQL.String()
+			if !strings.Contains(sql, "status = ") || !hasVar(query.Vars, model.DoctorVerified) {
+				t.Errorf("ListDoctors(%+v) lists unverified doctors: %s %v", req, sql, query.Vars)
+			}
+		}
+	}
+}
+
+// hasVar reports whether value is one of vars, or of a slice in vars
+func hasVar(vars []interface{}, value string) bool {
+	for _, v := range vars {
+		switch v := v.(type) {
+		case string:
+			if v == value {
+				return true
+			}
+		case []interface{}:
+			if hasVar(v, value) {
+				return true
+			}
+		}
+	}
+	return false
+}
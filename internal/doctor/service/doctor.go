@@ -2,6 +2,8 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	"github.com/quangdangfit/gocommon/logger"
 	"github.com/quangdangfit/gocommon/validation"
@@ -9,10 +11,18 @@ import (
 	"main/internal/doctor/dto"
 	"main/internal/doctor/model"
 	"main/internal/doctor/repository"
+	"main/pkg/audit"
+	"main/pkg/events"
+	"main/pkg/imaging"
 	"main/pkg/paging"
+	"main/pkg/patch"
 	"main/pkg/utils"
 )
 
+// ErrDoctorNotDeleted is also returned when the user of the doctor has
+// another doctor profile since
+var ErrDoctorNotDeleted = errors.New("doctor not found or not deleted")
+
 //go:generate mockery --name=IDoctorService
 type IDoctorService interface {
 	ListDoctors(c context.Context, req *dto.ListDoctorReq) ([]*model.Doctor, *paging.Pagination, error)
@@ -20,20 +30,45 @@ type IDoctorService interface {
 	Create(ctx context.Context, req *dto.CreateDoctorReq) (*model.Doctor, error)
 	Delete(ctx context.Context, id string, req *dto.DeleteDoctorReq) (*model.Doctor, error)
 	Update(ctx context.Context, id string, req *dto.UpdateDoctorReq) (*model.Doctor, error)
+	Patch(ctx context.Context, id string, version int64, changes patch.Patch) (*model.Doctor, error)
+	SubmitVerification(ctx context.Context, userID string, req *dto.SubmitVerificationReq) (*model.Verification, error)
+	GetVerification(ctx context.Context, userID string) (*model.Verification, error)
+	ListVerifications(ctx context.Context, req *dto.ListVerificationsReq) ([]*model.Verification, *paging.Pagination, error)
+	ApproveVerification(ctx context.Context, reviewerID, id string) (*model.Verification, error)
+	RejectVerification(ctx context.Context, reviewerID, id string, req *dto.RejectVerificationReq) (*model.Verification, error)
+	CheckBookable(ctx context.Context, id string) error
+	SetImage(ctx context.Context, userID string, req *dto.SetImageReq) error
+	SuspendExpiredLicenses(ctx context.Context) (int64, error)
+	Restore(ctx context.Context, id string) (*model.Doctor, error)
+}
+
+// ImageProcessor generates the variants of an uploaded image in the
+// background, calling done with their urls
+type ImageProcessor interface {
+	ProcessImage(ctx context.Context, ownerID, fileID string, done func(ctx context.Context, variants imaging.Variants) error) error
 }
 
 type DoctorService struct {
 	validator validation.Validation
 	repo      repository.IDoctorRepository
+	images    ImageProcessor
+	recorder  audit.Recorder
+	publisher events.Publisher
 }
 
 func NewDoctorService(
 	validator validation.Validation,
 	repo repository.IDoctorRepository,
+	images ImageProcessor,
+	recorder audit.Recorder,
+	publisher events.Publisher,
 ) *DoctorService {
 	return &DoctorService{
 		validator: validator,
 		repo:      repo,
+		images:    images,
+		recorder:  recorder,
+		publisher: publisher,
 	}
 }
 
@@ -47,6 +82,10 @@ func (p *DoctorService) GetDoctorByID(ctx context.Context, id string) (*model.Do
 }
 
 func (p *DoctorService) ListDoctors(ctx context.Context, req *dto.ListDoctorReq) ([]*model.Doctor, *paging.Pagination, error) {
+	if err := p.validator.ValidateStruct(req); err != nil {
+		return nil, nil, fmt.Errorf("%w: %s", ErrInvalidFilter, err)
+	}
+
 	Doctors, pagination, err := p.repo.ListDoctors(ctx, req)
 	if err != nil {
 		return nil, nil, err
@@ -68,6 +107,8 @@ func (p *DoctorService) Create(ctx context.Context, req *dto.CreateDoctorReq) (*
 		logger.Errorf("Create fail, error: %s", err)
 		return nil, err
 	}
+	p.recorder.Record(ctx, &audit.Event{Action: audit.DoctorCreate, TargetType: audit.TargetDoctor, TargetID: doctor.ID, After: &doctor})
+	p.publish(ctx, events.DoctorCreated, &doctor, events.ActionCreated)
 
 	return &doctor, nil
 }
@@ -82,13 +123,55 @@ func (p *DoctorService) Update(ctx context.Context, id string, req *dto.UpdateDo
 		logger.Errorf("Update.GetDoctorByID fail, id: %s, error: %s", id, err)
 		return nil, err
 	}
+	before := *Doctor
 
+	// the request never moves the doctor to another row, nor drops its user
 	utils.Copy(Doctor, req)
+	Doctor.ID = id
+	if req.IDUser == "" {
+		Doctor.IDUser = before.IDUser
+	}
+	Doctor.Version = req.Version
 	err = p.repo.Update(ctx, Doctor)
 	if err != nil {
 		logger.Errorf("Update fail, id: %s, error: %s", id, err)
 		return nil, err
 	}
+	p.recorder.Record(ctx, &audit.Event{Action: audit.DoctorUpdate, TargetType: audit.TargetDoctor, TargetID: id, Before: &before, After: Doctor})
+	p.publish(ctx, events.DoctorUpdated, Doctor, events.ActionUpdated)
+
+	return Doctor, nil
+}
+
+// Patch applies changes to the editable fields of a Doctor at version, the
+// fields it does not change are kept and only the changed ones are validated
+func (p *DoctorService) Patch(ctx context.Context, id string, version int64, changes patch.Patch) (*model.Doctor, error) {
+	Doctor, err := p.repo.GetDoctorByID(ctx, id)
+	if err != nil {
+		logger.Errorf("Patch.GetDoctorByID fail, id: %s, error: %s", id, err)
+		return nil, err
+	}
+	before := *Doctor
+
+	var current dto.UpdateDoctorReq
+	utils.Copy(&current, Doctor)
+	var req dto.PatchDoctorReq
+	if err := patch.Apply(changes, &current, &req); err != nil {
+		return nil, err
+	}
+	if err := p.validator.ValidateStruct(&req); err != nil {
+		return nil, patch.Invalid(err)
+	}
+
+	utils.Copy(Doctor, &req)
+	Doctor.Version = version
+	err = p.repo.Update(ctx, Doctor)
+	if err != nil {
+		logger.Errorf("Patch fail, id: %s, error: %s", id, err)
+		return nil, err
+	}
+	p.recorder.Record(ctx, &audit.Event{Action: audit.DoctorUpdate, TargetType: audit.TargetDoctor, TargetID: id, Before: &before, After: Doctor})
+	p.publish(ctx, events.DoctorUpdated, Doctor, events.ActionUpdated)
 
 	return Doctor, nil
 }
@@ -104,12 +187,62 @@ func (p *DoctorService) Delete(ctx context.Context, id string, req *dto.DeleteDo
 		return nil, err
 	}
 
-	utils.Copy(Doctor, req)
+	Doctor.Version = req.Version
 	err = p.repo.Delete(ctx, Doctor)
 	if err != nil {
 		logger.Errorf("Delete fail, id: %s, error: %s", id, err)
 		return nil, err
 	}
+	p.recorder.Record(ctx, &audit.Event{Action: audit.DoctorDelete, TargetType: audit.TargetDoctor, TargetID: id, Before: Doctor})
+	p.publish(ctx, events.DoctorDeleted, Doctor, events.ActionDeleted)
 
 	return Doctor, nil
 }
+
+// Restore restores a deleted doctor, with its specialties
+func (p *DoctorService) Restore(ctx context.Context, id string) (*model.Doctor, error) {
+	restored, err := p.repo.Restore(ctx, id)
+	if err != nil {
+		logger.Errorf("Restore fail, id: %s, error: %s", id, err)
+		return nil, err
+	}
+	if !restored {
+		return nil, ErrDoctorNotDeleted
+	}
+
+	doctor, err := p.repo.GetDoctorByID(ctx, id)
+	if err != nil {
+		logger.Errorf("Restore.GetDoctorByID fail, id: %s, error: %s", id, err)
+		return nil, err
+	}
+	p.recorder.Record(ctx, &audit.Event{Action: audit.DoctorRestore, TargetType: audit.TargetDoctor, TargetID: id, After: doctor})
+	p.publish(ctx, events.DoctorUpdated, doctor, events.ActionRestored)
+
+	return doctor, nil
+}
+
+// SetImage sets an image uploaded by userID as the picture of its doctor
+// profile once its variants are generated
+func (p *DoctorService) SetImage(ctx context.Context, userID string, req *dto.SetImageReq) error {
+	if err := p.validator.ValidateStruct(req); err != nil {
+		return err
+	}
+
+	doctor, err := p.doctorByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	return p.images.ProcessImage(ctx, userID, req.FileID, func(ctx context.Context, variants imaging.Variants) error {
+		if err := p.repo.UpdateImage(ctx, doctor.ID, variants); err != nil {
+			return err
+		}
+		p.publish(ctx, events.DoctorUpdated, doctor, events.ActionUpdated)
+		return nil
+	})
+}
+
+// publish tells the subscribers that doctor changed
+func (p *DoctorService) publish(ctx context.Context, name string, doctor *model.Doctor, action string) {
+	p.publisher.Publish(ctx, events.New(ctx, name, doctor.ID, &events.Change{Action: action, UserID: doctor.IDUser, Status: doctor.Status}))
+}
@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/quangdangfit/gocommon/logger"
+	"gorm.io/gorm"
+
+	"main/internal/doctor/dto"
+	"main/internal/doctor/model"
+	"main/pkg/audit"
+	"main/pkg/events"
+	"main/pkg/paging"
+)
+
+var (
+	ErrDoctorNotFound       = errors.New("doctor not found")
+	ErrDoctorNotBookable    = errors.New("doctor is not verified")
+	ErrVerificationNotFound = errors.New("verification not found")
+	ErrVerificationPending  = errors.New("a verification is already waiting for review")
+	ErrVerificationReviewed = errors.New("verification already reviewed")
+	ErrLicenseExpired       = errors.New("license expired")
+	ErrInvalidFilter        = errors.New("invalid filter")
+)
+
+// SubmitVerification queues the license of the doctor profile of userID for
+// review
+func (p *DoctorService) SubmitVerification(ctx context.Context, userID string, req *dto.SubmitVerificationReq) (*model.Verification, error) {
+	if err := p.validator.ValidateStruct(req); err != nil {
+		return nil, err
+	}
+	if !req.LicenseExpiresAt.After(time.Now()) {
+		return nil, ErrLicenseExpired
+	}
+
+	doctor, err := p.doctorByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	verification := model.Verification{
+		DoctorID:         doctor.ID,
+		LicenseNumber:    req.LicenseNumber,
+		IssuingAuthority: req.IssuingAuthority,
+		LicenseExpiresAt: req.LicenseExpiresAt,
+		Documents:        req.Documents,
+	}
+	verification.BeforeCreate()
+	created, err := p.repo.SubmitVerification(ctx, &verification)
+	if err != nil {
+		logger.Errorf("SubmitVerification fail, doctor: %s, error: %s", doctor.ID, err)
+		return nil, err
+	}
+	if !created {
+		return nil, ErrVerificationPending
+	}
+	if doctor.Status != model.DoctorVerified {
+		doctor.Status = model.DoctorPending
+	}
+	p.publish(ctx, events.DoctorUpdated, doctor, events.ActionUpdated)
+
+	return &verification, nil
+}
+
+// GetVerification returns the last verification submitted by the doctor
+// profile of userID
+func (p *DoctorService) GetVerification(ctx context.Context, userID string) (*model.Verification, error) {
+	doctor, err := p.doctorByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	verification, err := p.repo.GetLatestVerification(ctx, doctor.ID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrVerificationNotFound
+	}
+	if err != nil {
+		logger.Errorf("GetVerification.GetLatestVerification fail, doctor: %s, error: %s", doctor.ID, err)
+		return nil, err
+	}
+
+	return verification, nil
+}
+
+func (p *DoctorService) ListVerifications(ctx context.Context, req *dto.ListVerificationsReq) ([]*model.Verification, *paging.Pagination, error) {
+	verifications, pagination, err := p.repo.ListVerifications(ctx, req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return verifications, pagination, nil
+}
+
+// ApproveVerification verifies the doctor with the submitted license, an
+// expired license has to be rejected
+func (p *DoctorService) ApproveVerification(ctx context.Context, reviewerID, id string) (*model.Verification, error) {
+	verification, err := p.pendingVerification(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !verification.LicenseExpiresAt.After(time.Now()) {
+		return nil, ErrLicenseExpired
+	}
+
+	verification.Status = model.VerificationApproved
+	return p.review(ctx, reviewerID, verification)
+}
+
+func (p *DoctorService) RejectVerification(ctx context.Context, reviewerID, id string, req *dto.RejectVerificationReq) (*model.Verification, error) {
+	if err := p.validator.ValidateStruct(req); err != nil {
+		return nil, err
+	}
+
+	verification, err := p.pendingVerification(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	verification.Status = model.VerificationRejected
+	verification.Reason = req.Reason
+	return p.review(ctx, reviewerID, verification)
+}
+
+// CheckBookable fails with ErrDoctorNotBookable unless patients can book the
+// doctor
+func (p *DoctorService) CheckBookable(ctx context.Context, id string) error {
+	doctor, err := p.repo.GetDoctorByID(ctx, id)
+	if err != nil {
+		logger.Errorf("CheckBookable.GetDoctorByID fail, id: %s, error: %s", id, err)
+		return err
+	}
+	if !doctor.Bookable() {
+		return ErrDoctorNotBookable
+	}
+	return nil
+}
+
+func (p *DoctorService) SuspendExpiredLicenses(ctx context.Context) (int64, error) {
+	suspended, err := p.repo.SuspendExpiredLicenses(ctx, time.Now())
+	if err != nil {
+		logger.Errorf("SuspendExpiredLicenses fail, error: %s", err)
+		return 0, err
+	}
+	if suspended > 0 {
+		logger.Infof("Suspended %d doctors with an expired license", suspended)
+		p.recorder.Record(ctx, &audit.Event{
+			Action:     audit.DoctorSuspend,
+			TargetType: audit.TargetDoctor,
+			Reason:     "license_expired",
+			After:      map[string]int64{"suspended": suspended},
+		})
+		p.publisher.Publish(ctx, events.New(ctx, events.DoctorUpdated, "", &events.Change{Action: events.ActionSuspended, Status: model.DoctorSuspended}))
+	}
+	return suspended, nil
+}
+
+// RunLicenseExpiry suspends the doctors whose license expired every interval
+// until ctx is done
+func (p *DoctorService) RunLicenseExpiry(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		_, _ = p.SuspendExpiredLicenses(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p *DoctorService) doctorByUserID(ctx context.Context, userID string) (*model.Doctor, error) {
+	doctor, err := p.repo.GetDoctorByUserID(ctx, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrDoctorNotFound
+	}
+	if err != nil {
+		logger.Errorf("GetDoctorByUserID fail, user: %s, error: %s", userID, err)
+		return nil, err
+	}
+	return doctor, nil
+}
+
+func (p *DoctorService) pendingVerification(ctx context.Context, id string) (*model.Verification, error) {
+	verification, err := p.repo.GetVerification(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrVerificationNotFound
+	}
+	if err != nil {
+		logger.Errorf("GetVerification fail, id: %s, error: %s", id, err)
+		return nil, err
+	}
+	if verification.Status != model.VerificationPending {
+		return nil, ErrVerificationReviewed
+	}
+	return verification, nil
+}
+
+func (p *DoctorService) review(ctx context.Context, reviewerID string, verification *model.Verification) (*model.Verification, error) {
+	now := time.Now()
+	verification.ReviewedBy = reviewerID
+	verification.ReviewedAt = &now
+
+	reviewed, err := p.repo.ReviewVerification(ctx, verification)
+	if err != nil {
+		logger.Errorf("ReviewVerification fail, id: %s, error: %s", verification.ID, err)
+		return nil, err
+	}
+	if !reviewed {
+		return nil, ErrVerificationReviewed
+	}
+	p.recorder.Record(ctx, &audit.Event{
+		Action:     audit.DoctorReview,
+		TargetType: audit.TargetDoctor,
+		TargetID:   verification.DoctorID,
+		Reason:     verification.Reason,
+		After:      verification,
+	})
+	if doctor, err := p.repo.GetDoctorByID(ctx, verification.DoctorID); err == nil {
+		p.publish(ctx, events.DoctorUpdated, doctor, events.ActionReviewed)
+	}
+	return verification, nil
+}
@@ -0,0 +1,277 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/quangdangfit/gocommon/logger"
+	"github.com/quangdangfit/gocommon/validation"
+	"gorm.io/gorm"
+
+	"main/internal/doctor/dto"
+	"main/internal/doctor/model"
+	"main/internal/doctor/repository"
+	"main/pkg/audit"
+	"main/pkg/config"
+	"main/pkg/events"
+)
+
+func TestMain(m *testing.M) {
+	logger.Initialize(config.ProductionEnv)
+	os.Exit(m.Run())
+}
+
+// verificationRepo keeps the doctors and verifications in memory, updating
+// the doctors like the transactions of repository.DoctorRepo
+type verificationRepo struct {
+	repository.IDoctorRepository
+	doctors       map[string]*model.Doctor
+	verifications map[string]*model.Verification
+}
+
+func newVerificationRepo(doctors ...*model.Doctor) *verificationRepo {
+	r := &verificationRepo{doctors: map[string]*model.Doctor{}, verifications: map[string]*model.Verification{}}
+	for _, doctor := range doctors {
+		r.doctors[doctor.ID] = doctor
+	}
+	return r
+}
+
+func (r *verificationRepo) GetDoctorByID(ctx context.Context, id string) (*model.Doctor, error) {
+	doctor, ok := r.doctors[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *doctor
+	return &copied, nil
+}
+
+func (r *verificationRepo) GetDoctorByUserID(ctx context.Context, userID string) (*model.Doctor, error) {
+	for _, doctor := range r.doctors {
+		if doctor.IDUser == userID {
+			return r.GetDoctorByID(ctx, doctor.ID)
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *verificationRepo) SubmitVerification(ctx context.Context, verification *model.Verification) (bool, error) {
+	for _, v := range r.verifications {
+		if v.DoctorID == verification.DoctorID && v.Status == model.VerificationPending {
+			return false, nil
+		}
+	}
+	copied := *verification
+	r.verifications[verification.ID] = &copied
+	if doctor := r.doctors[verification.DoctorID]; doctor.Status != model.DoctorVerified {
+		doctor.Status = model.DoctorPending
+	}
+	return true, nil
+}
+
+func (r *verificationRepo) GetVerification(ctx context.Context, id string) (*model.Verification, error) {
+	verification, ok := r.verifications[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *verification
+	return &copied, nil
+}
+
+func (r *verificationRepo) ReviewVerification(ctx context.Context, verification *model.Verification) (bool, error) {
+	stored := r.verifications[verification.ID]
+	if stored == nil || stored.Status != model.VerificationPending {
+		return false, nil
+	}
+	*stored = *verification
+
+	doctor := r.doctors[verification.DoctorID]
+	if verification.Status == model.VerificationApproved {
+		expiresAt := verification.LicenseExpiresAt
+		doctor.Status = model.DoctorVerified
+		doctor.LicenseNumber = verification.LicenseNumber
+		doctor.IssuingAuthority = verification.IssuingAuthority
+		doctor.LicenseExpiresAt = &expiresAt
+	} else if doctor.Status != model.DoctorVerified {
+		doctor.Status = model.DoctorRejected
+	}
+	return true, nil
+}
+
+// published records the events of the service
+type published []*events.Event
+
+func (p *published) Publish(ctx context.Context, event *events.Event) {
+	*p = append(*p, event)
+}
+
+// lastStatus is the doctor status of the last event
+func (p published) lastStatus(t *testing.T) string {
+	t.Helper()
+	if len(p) == 0 {
+		t.Fatal("no event published")
+	}
+	var change events.Change
+	if err := p[len(p)-1].Decode(&change); err != nil {
+		t.Fatal(err)
+	}
+	return change.Status
+}
+
+func newDoctor(status string) *model.Doctor {
+	return &model.Doctor{ID: "doctor-" + status, IDUser: "user-" + status, Name: "Ada", Status: status}
+}
+
+func license(expiresAt time.Time) *dto.SubmitVerificationReq {
+	return &dto.SubmitVerificationReq{
+		LicenseNumber:    "MD-123456",
+		IssuingAuthority: "Medical Council",
+		LicenseExpiresAt: expiresAt,
+		Documents:        []string{"https://files.example.com/license.pdf"},
+	}
+}
+
+func TestVerificationApprove(t *testing.T) {
+	doctor := newDoctor(model.DoctorUnverified)
+	repo := newVerificationRepo(doctor)
+	var sent published
+	svc := NewDoctorService(validation.New(), repo, nil, audit.Nop(), &sent)
+	ctx := context.Background()
+	expiresAt := time.Now().AddDate(1, 0, 0)
+
+	if err := svc.CheckBookable(ctx, doctor.ID); !errors.Is(err, ErrDoctorNotBookable) {
+		t.Errorf("unverified CheckBookable = %v, want ErrDoctorNotBookable", err)
+	}
+
+	verification, err := svc.SubmitVerification(ctx, doctor.IDUser, license(expiresAt))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if verification.Status != model.VerificationPending || doctor.Status != model.DoctorPending {
+		t.Errorf("submitted verification %s, doctor %s", verification.Status, doctor.Status)
+	}
+	if status := sent.lastStatus(t); status != model.DoctorPending {
+		t.Errorf("submit published status %s", status)
+	}
+	if _, err := svc.SubmitVerification(ctx, doctor.IDUser, license(expiresAt)); !errors.Is(err, ErrVerificationPending) {
+		t.Errorf("second submit = %v, want ErrVerificationPending", err)
+	}
+	if err := svc.CheckBookable(ctx, doctor.ID); !errors.Is(err, ErrDoctorNotBookable) {
+		t.Errorf("pending CheckBookable = %v, want ErrDoctorNotBookable", err)
+	}
+
+	approved, err := svc.ApproveVerification(ctx, "admin", verification.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if approved.Status != model.VerificationApproved || approved.ReviewedBy != "admin" || approved.ReviewedAt == nil {
+		t.Errorf("approved = %+v", approved)
+	}
+	if doctor.Status != model.DoctorVerified || doctor.LicenseNumber != "MD-123456" || !doctor.LicenseExpiresAt.Equal(expiresAt) {
+		t.Errorf("approved doctor = %+v", doctor)
+	}
+	if status := sent.lastStatus(t); status != model.DoctorVerified {
+		t.Errorf("approve published status %s", status)
+	}
+	if err := svc.CheckBookable(ctx, doctor.ID); err != nil {
+		t.Errorf("verified CheckBookable = %v", err)
+	}
+
+	if _, err := svc.ApproveVerification(ctx, "admin", verification.ID); !errors.Is(err, ErrVerificationReviewed) {
+		t.Errorf("second approve = %v, want ErrVerificationReviewed", err)
+	}
+	if _, err := svc.RejectVerification(ctx, "admin", verification.ID, &dto.RejectVerificationReq{Reason: "late"}); !errors.Is(err, ErrVerificationReviewed) {
+		t.Errorf("reject of approved = %v, want ErrVerificationReviewed", err)
+	}
+}
+
+func TestVerificationReject(t *testing.T) {
+	doctor := newDoctor(model.DoctorUnverified)
+	repo := newVerificationRepo(doctor)
+	svc := NewDoctorService(validation.New(), repo, nil, audit.Nop(), events.Nop())
+	ctx := context.Background()
+
+	verification, err := svc.SubmitVerification(ctx, doctor.IDUser, license(time.Now().AddDate(1, 0, 0)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.RejectVerification(ctx, "admin", verification.ID, &dto.RejectVerificationReq{}); err == nil {
+		t.Error("reject without a reason succeeded")
+	}
+
+	rejected, err := svc.RejectVerification(ctx, "admin", verification.ID, &dto.RejectVerificationReq{Reason: "unreadable"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rejected.Status != model.VerificationRejected || rejected.Reason != "unreadable" || doctor.Status != model.DoctorRejected {
+		t.Errorf("rejected verification %s, doctor %s", rejected.Status, doctor.Status)
+	}
+	if err := svc.CheckBookable(ctx, doctor.ID); !errors.Is(err, ErrDoctorNotBookable) {
+		t.Errorf("rejected CheckBookable = %v, want ErrDoctorNotBookable", err)
+	}
+
+	// a rejected doctor submits again
+	if _, err := svc.SubmitVerification(ctx, doctor.IDUser, license(time.Now().AddDate(1, 0, 0))); err != nil {
+		t.Fatal(err)
+	}
+	if doctor.Status != model.DoctorPending {
+		t.Errorf("resubmitted doctor %s", doctor.Status)
+	}
+}
+
+func TestVerificationRenewal(t *testing.T) {
+	// a verified doctor renewing its license stays verified, even rejected
+	doctor := newDoctor(model.DoctorVerified)
+	expiresAt := time.Now().AddDate(0, 1, 0)
+	doctor.LicenseNumber, doctor.LicenseExpiresAt = "MD-1", &expiresAt
+	repo := newVerificationRepo(doctor)
+	svc := NewDoctorService(validation.New(), repo, nil, audit.Nop(), events.Nop())
+	ctx := context.Background()
+
+	verification, err := svc.SubmitVerification(ctx, doctor.IDUser, license(time.Now().AddDate(2, 0, 0)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doctor.Status != model.DoctorVerified {
+		t.Errorf("renewing doctor %s", doctor.Status)
+	}
+	if _, err := svc.RejectVerification(ctx, "admin", verification.ID, &dto.RejectVerificationReq{Reason: "unreadable"}); err != nil {
+		t.Fatal(err)
+	}
+	if doctor.Status != model.DoctorVerified || doctor.LicenseNumber != "MD-1" {
+		t.Errorf("rejected renewal left doctor %s with license %s", doctor.Status, doctor.LicenseNumber)
+	}
+}
+
+func TestVerificationExpiredLicense(t *testing.T) {
+	doctor := newDoctor(model.DoctorUnverified)
+	repo := newVerificationRepo(doctor)
+	svc := NewDoctorService(validation.New(), repo, nil, audit.Nop(), events.Nop())
+	ctx := context.Background()
+
+	if _, err := svc.SubmitVerification(ctx, doctor.IDUser, license(time.Now().Add(-time.Hour))); !errors.Is(err, ErrLicenseExpired) {
+		t.Errorf("submit of expired license = %v, want ErrLicenseExpired", err)
+	}
+	if _, err := svc.SubmitVerification(ctx, "nobody", license(time.Now().AddDate(1, 0, 0))); !errors.Is(err, ErrDoctorNotFound) {
+		t.Errorf("submit without doctor = %v, want ErrDoctorNotFound", err)
+	}
+
+	// the license expired while waiting for review
+	verification, err := svc.SubmitVerification(ctx, doctor.IDUser, license(time.Now().AddDate(1, 0, 0)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo.verifications[verification.ID].LicenseExpiresAt = time.Now().Add(-time.Hour)
+	if _, err := svc.ApproveVerification(ctx, "admin", verification.ID); !errors.Is(err, ErrLicenseExpired) {
+		t.Errorf("approve of expired license = %v, want ErrLicenseExpired", err)
+	}
+	if doctor.Status != model.DoctorPending {
+		t.Errorf("doctor %s after refused approval", doctor.Status)
+	}
+	if _, err := svc.ApproveVerification(ctx, "admin", "unknown"); !errors.Is(err, ErrVerificationNotFound) {
+		t.Errorf("approve of unknown = %v, want ErrVerificationNotFound", err)
+	}
+}
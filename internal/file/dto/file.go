@@ -0,0 +1,20 @@
+package dto
+
+import "time"
+
+// swagger:model File
+type File struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Sniffed from the content
+	// example: "image/png"
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+	// Hex sha256 of the content
+	Checksum  string    `json:"checksum"`
+	CreatedAt time.Time `json:"created_at"`
+	// Signed download url, anyone having it can download the file until it
+	// expires
+	URL          string    `json:"url"`
+	URLExpiresAt time.Time `json:"url_expires_at"`
+}
@@ -0,0 +1,32 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// File is an uploaded file, its content is in the storage under Key
+type File struct {
+	ID          string    `json:"id" gorm:"unique;not null;index;primary_key"`
+	CreatedAt   time.Time `json:"created_at"`
+	OwnerID     string    `json:"owner_id" gorm:"not null;index"`
+	Name        string    `json:"name"`
+	Key         string    `json:"-" gorm:"not null;uniqueIndex"`
+	ContentType string    `json:"content_type" gorm:"not null"`
+	Size        int64     `json:"size"`
+	// hex sha256 of the content
+	Checksum string `json:"checksum" gorm:"not null"`
+}
+
+func (File) TableName() string {
+	return "files"
+}
+
+func (m *File) BeforeCreate() error {
+	m.ID = uuid.New().String()
+	m.CreatedAt = time.Now()
+	// objects of a user are grouped, ids never reach the storage unchecked
+	m.Key = m.OwnerID + "/" + m.ID
+	return nil
+}
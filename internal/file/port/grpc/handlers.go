@@ -0,0 +1,125 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/quangdangfit/gocommon/logger"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"main/internal/file/model"
+	"main/internal/file/service"
+	"main/pkg/rbac"
+	"main/pkg/storage"
+	pb "main/proto/gen/go/file"
+)
+
+type FileHandler struct {
+	service service.IFileService
+	pb.UnimplementedFileServiceServer
+}
+
+func NewFileHandler(service service.IFileService) *FileHandler {
+	return &FileHandler{service: service}
+}
+
+func (h *FileHandler) Upload(stream pb.FileService_UploadServer) error {
+	ctx := stream.Context()
+	userID, _ := ctx.Value("userId").(string)
+	if userID == "" {
+		return errors.New("unauthorized")
+	}
+
+	first, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	info := first.GetInfo()
+	if info == nil || info.Name == "" {
+		return status.New(codes.InvalidArgument, "the first message must be the info").Err()
+	}
+
+	file, err := h.service.Upload(ctx, userID, info.Name, &chunkReader{stream: stream})
+	if err != nil {
+		logger.Error("Failed to upload file ", err)
+		return fileError(err)
+	}
+
+	return stream.SendAndClose(&pb.FileRes{File: h.toPb(file)})
+}
+
+func (h *FileHandler) GetFile(ctx context.Context, req *pb.GetFileReq) (*pb.FileRes, error) {
+	userID, _ := ctx.Value("userId").(string)
+	permissions, _ := ctx.Value("permissions").([]string)
+
+	file, err := h.service.GetFile(ctx, userID, req.Id, rbac.Has(permissions, rbac.FilesRead))
+	if err != nil {
+		logger.Error("Failed to get file ", err)
+		return nil, fileError(err)
+	}
+
+	return &pb.FileRes{File: h.toPb(file)}, nil
+}
+
+func (h *FileHandler) DeleteFile(ctx context.Context, req *pb.DeleteFileReq) (*pb.DeleteFileRes, error) {
+	userID, _ := ctx.Value("userId").(string)
+	if userID == "" {
+		return nil, errors.New("unauthorized")
+	}
+
+	if err := h.service.Delete(ctx, userID, req.Id); err != nil {
+		logger.Error("Failed to delete file ", err)
+		return nil, fileError(err)
+	}
+
+	return &pb.DeleteFileRes{}, nil
+}
+
+func (h *FileHandler) toPb(file *model.File) *pb.File {
+	url, expiresAt := h.service.DownloadURL(file)
+	return &pb.File{
+		Id:           file.ID,
+		Name:         file.Name,
+		ContentType:  file.ContentType,
+		Size:         file.Size,
+		Checksum:     file.Checksum,
+		CreatedAt:    file.CreatedAt.Format(time.RFC3339),
+		Url:          url,
+		UrlExpiresAt: expiresAt.Format(time.RFC3339),
+	}
+}
+
+// chunkReader reads the chunks of an upload stream as one content
+type chunkReader struct {
+	stream pb.FileService_UploadServer
+	chunk  []byte
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for len(r.chunk) == 0 {
+		req, err := r.stream.Recv()
+		if err != nil {
+			return 0, err
+		}
+		r.chunk = req.GetChunk()
+	}
+
+	n := copy(p, r.chunk)
+	r.chunk = r.chunk[n:]
+	return n, nil
+}
+
+func fileError(err error) error {
+	switch {
+	case errors.Is(err, service.ErrFileNotFound):
+		return status.New(codes.NotFound, err.Error()).Err()
+	case errors.Is(err, storage.ErrTooLarge):
+		return status.New(codes.ResourceExhausted, err.Error()).Err()
+	case errors.Is(err, storage.ErrContentType), errors.Is(err, storage.ErrEmpty):
+		return status.New(codes.InvalidArgument, err.Error()).Err()
+	default:
+		return err
+	}
+}
@@ -0,0 +1,14 @@
+package grpc
+
+import (
+	"google.golang.org/grpc"
+
+	"main/internal/file/service"
+	pb "main/proto/gen/go/file"
+)
+
+func RegisterHandlers(svr *grpc.Server, fileSvc *service.FileService) {
+	fileHandler := NewFileHandler(fileSvc)
+
+	pb.RegisterFileServiceServer(svr, fileHandler)
+}
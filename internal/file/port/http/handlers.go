@@ -0,0 +1,218 @@
+package http
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/quangdangfit/gocommon/logger"
+
+	"main/internal/file/dto"
+	"main/internal/file/model"
+	"main/internal/file/service"
+	"main/pkg/imaging"
+	"main/pkg/rbac"
+	"main/pkg/response"
+	"main/pkg/storage"
+	"main/pkg/utils"
+)
+
+type FileHandler struct {
+	service service.IFileService
+	images  service.IImageService
+}
+
+func NewFileHandler(service service.IFileService, images service.IImageService) *FileHandler {
+	return &FileHandler{service: service, images: images}
+}
+
+// UploadFile UploadFileStream GetFile DeleteFile DownloadFile GetImage
+
+// UploadFile godoc
+//
+//	@Summary	Upload a file as multipart form data
+//	@Tags		Files
+//	@Security	ApiKeyAuth
+//	@Accept		multipart/form-data
+//	@Produce	json
+//	@Param		file	formData	file	true	"JPEG, PNG, WebP or PDF"
+//	@Success	200		{object}	dto.File
+//	@Router		/files [post]
+func (h *FileHandler) UploadFile(c *gin.Context) {
+	// parts are streamed, the form is never parsed in memory
+	mr, err := c.Request.MultipartReader()
+	if err != nil {
+		logger.Error("Failed to get multipart body ", err)
+		response.Error(c, http.StatusBadRequest, err, "Invalid parameters")
+		return
+	}
+
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			logger.Error("Failed to read multipart body ", err)
+			response.Error(c, http.StatusBadRequest, err, "Invalid parameters")
+			return
+		}
+		if part.FormName() == "file" {
+			h.upload(c, part.FileName(), part)
+			return
+		}
+	}
+
+	response.Error(c, http.StatusBadRequest, errors.New("missing file"), "Invalid parameters")
+}
+
+// UploadFileStream godoc
+//
+//	@Summary	Upload a file as the request body
+//	@Tags		Files
+//	@Security	ApiKeyAuth
+//	@Accept		octet-stream
+//	@Produce	json
+//	@Param		name	query		string	true	"File name"
+//	@Success	200		{object}	dto.File
+//	@Router		/files/stream [post]
+func (h *FileHandler) UploadFileStream(c *gin.Context) {
+	name := c.Query("name")
+	if name == "" {
+		response.Error(c, http.StatusBadRequest, errors.New("missing name"), "Invalid parameters")
+		return
+	}
+
+	h.upload(c, name, c.Request.Body)
+}
+
+// GetFile godoc
+//
+//	@Summary	Get a file with a new download url
+//	@Tags		Files
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		id	path		string	true	"File ID"
+//	@Success	200	{object}	dto.File
+//	@Router		/files/{id} [get]
+func (h *FileHandler) GetFile(c *gin.Context) {
+	readAny := rbac.Has(c.GetStringSlice("permissions"), rbac.FilesRead)
+	file, err := h.service.GetFile(c, c.GetString("userId"), c.Param("id"), readAny)
+	if err != nil {
+		logger.Error("Failed to get file ", err)
+		fileError(c, err)
+		return
+	}
+
+	response.JSON(c, http.StatusOK, h.fileRes(file))
+}
+
+// DeleteFile godoc
+//
+//	@Summary	Delete a file of the signed-in user
+//	@Tags		Files
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		id	path	string	true	"File ID"
+//	@Success	200
+//	@Router		/files/{id} [delete]
+func (h *FileHandler) DeleteFile(c *gin.Context) {
+	if err := h.service.Delete(c, c.GetString("userId"), c.Param("id")); err != nil {
+		logger.Error("Failed to delete file ", err)
+		fileError(c, err)
+		return
+	}
+
+	response.JSON(c, http.StatusOK, nil)
+}
+
+// DownloadFile godoc
+//
+//	@Summary	Download a file with a signed url
+//	@Tags		Files
+//	@Produce	octet-stream
+//	@Param		id			path	string	true	"File ID"
+//	@Param		expires		query	string	true	"Expiry of the url"
+//	@Param		signature	query	string	true	"Signature of the url"
+//	@Success	200
+//	@Router		/files/{id}/download [get]
+func (h *FileHandler) DownloadFile(c *gin.Context) {
+	file, content, err := h.service.Download(c, c.Param("id"), c.Query("expires"), c.Query("signature"))
+	if err != nil {
+		logger.Error("Failed to download file ", err)
+		fileError(c, err)
+		return
+	}
+	defer content.Close()
+
+	c.DataFromReader(http.StatusOK, file.Size, file.ContentType, content, map[string]string{
+		"Content-Disposition":    mime.FormatMediaType("inline", map[string]string{"filename": file.Name}),
+		"ETag":                   `"` + file.Checksum + `"`,
+		"Cache-Control":          "private",
+		"X-Content-Type-Options": "nosniff",
+	})
+}
+
+// GetImage godoc
+//
+//	@Summary	Get a variant of a profile picture
+//	@Tags		Files
+//	@Produce	image/jpeg,image/webp
+//	@Param		id		path	string	true	"File ID of the picture"
+//	@Param		variant	path	string	true	"small, medium or large, .jpg or .webp"
+//	@Success	200
+//	@Router		/images/{id}/{variant} [get]
+func (h *FileHandler) GetImage(c *gin.Context) {
+	contentType, content, err := h.images.GetVariant(c, c.Param("id"), c.Param("variant"))
+	if err != nil {
+		logger.Error("Failed to get image ", err)
+		fileError(c, err)
+		return
+	}
+	defer content.Close()
+
+	// variants of a file never change
+	c.DataFromReader(http.StatusOK, -1, contentType, content, map[string]string{
+		"Cache-Control":          "public, max-age=31536000, immutable",
+		"X-Content-Type-Options": "nosniff",
+	})
+}
+
+func (h *FileHandler) upload(c *gin.Context, name string, r io.Reader) {
+	file, err := h.service.Upload(c, c.GetString("userId"), name, r)
+	if err != nil {
+		logger.Error("Failed to upload file ", err)
+		fileError(c, err)
+		return
+	}
+
+	response.JSON(c, http.StatusOK, h.fileRes(file))
+}
+
+func (h *FileHandler) fileRes(file *model.File) dto.File {
+	var res dto.File
+	utils.Copy(&res, file)
+	res.URL, res.URLExpiresAt = h.service.DownloadURL(file)
+	return res
+}
+
+func fileError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrFileNotFound):
+		response.Error(c, http.StatusNotFound, err, "File not found")
+	case errors.Is(err, storage.ErrTooLarge):
+		response.Error(c, http.StatusRequestEntityTooLarge, err, err.Error())
+	case errors.Is(err, storage.ErrContentType):
+		response.Error(c, http.StatusUnsupportedMediaType, err, err.Error())
+	case errors.Is(err, storage.ErrEmpty), errors.Is(err, service.ErrNotImage):
+		response.Error(c, http.StatusBadRequest, err, err.Error())
+	case errors.Is(err, storage.ErrInvalidSignature), errors.Is(err, storage.ErrURLExpired):
+		response.Error(c, http.StatusForbidden, err, err.Error())
+	case errors.Is(err, imaging.ErrQueueFull):
+		response.Error(c, http.StatusServiceUnavailable, err, err.Error())
+	default:
+		response.Error(c, http.StatusInternalServerError, err, "Something went wrong")
+	}
+}
@@ -0,0 +1,26 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"main/internal/file/service"
+	"main/pkg/middleware"
+)
+
+func Routes(r *gin.RouterGroup, fileSvc *service.FileService, imageSvc *service.ImageService, auth *middleware.Authenticator) {
+	fileHandler := NewFileHandler(fileSvc, imageSvc)
+
+	authMiddleware := middleware.JWTAuth(auth)
+	fileRoute := r.Group("/files")
+	{
+		fileRoute.POST("", authMiddleware, fileHandler.UploadFile)
+		fileRoute.POST("/stream", authMiddleware, fileHandler.UploadFileStream)
+		fileRoute.GET("/:id", authMiddleware, fileHandler.GetFile)
+		fileRoute.DELETE("/:id", authMiddleware, fileHandler.DeleteFile)
+		// the signature authorizes the download
+		fileRoute.GET("/:id/download", fileHandler.DownloadFile)
+	}
+
+	// variants of profile pictures are public
+	r.GET("/images/:id/:variant", fileHandler.GetImage)
+}
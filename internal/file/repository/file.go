@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+
+	"main/internal/file/model"
+	"main/pkg/dbs"
+)
+
+//go:generate mockery --name=IFileRepository
+type IFileRepository interface {
+	Create(ctx context.Context, file *model.File) error
+	GetFileByID(ctx context.Context, id string) (*model.File, error)
+	Delete(ctx context.Context, file *model.File) error
+}
+
+type FileRepo struct {
+	db dbs.IDatabase
+}
+
+func NewFileRepository(db dbs.IDatabase) *FileRepo {
+	return &FileRepo{db: db}
+}
+
+func (r *FileRepo) Create(ctx context.Context, file *model.File) error {
+	return r.db.Create(ctx, file)
+}
+
+func (r *FileRepo) GetFileByID(ctx context.Context, id string) (*model.File, error) {
+	var file model.File
+	if err := r.db.GetDB().WithContext(ctx).Where("id = ?", id).First(&file).Error; err != nil {
+		return nil, err
+	}
+	return &file, nil
+}
+
+func (r *FileRepo) Delete(ctx context.Context, file *model.File) error {
+	return r.db.Delete(ctx, file)
+}
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"path/filepath"
+	"time"
+
+	"github.com/quangdangfit/gocommon/logger"
+	"gorm.io/gorm"
+
+	"main/internal/file/model"
+	"main/internal/file/repository"
+	"main/pkg/config"
+	"main/pkg/imaging"
+	"main/pkg/storage"
+)
+
+// ContentTypes can be uploaded, images and documents
+var ContentTypes = []string{
+	"image/jpeg",
+	"image/png",
+	"image/webp",
+	"application/pdf",
+}
+
+var ErrFileNotFound = errors.New("file not found")
+
+//go:generate mockery --name=IFileService
+type IFileService interface {
+	Upload(ctx context.Context, ownerID, name string, r io.Reader) (*model.File, error)
+	GetFile(ctx context.Context, userID, id string, readAny bool) (*model.File, error)
+	Download(ctx context.Context, id, expires, signature string) (*model.File, io.ReadCloser, error)
+	Delete(ctx context.Context, userID, id string) error
+	DownloadURL(file *model.File) (string, time.Time)
+}
+
+type FileService struct {
+	repo    repository.IFileRepository
+	storage storage.Storage
+	signer  *storage.Signer
+	limits  storage.Limits
+	urlTTL  time.Duration
+}
+
+func NewFileService(
+	repo repository.IFileRepository,
+	store storage.Storage,
+	signer *storage.Signer,
+	limits storage.Limits,
+	urlTTL time.Duration) *FileService {
+
+	return &FileService{
+		repo:    repo,
+		storage: store,
+		signer:  signer,
+		limits:  limits,
+		urlTTL:  urlTTL,
+	}
+}
+
+// NewServices returns the file and image services, configured from the
+// config
+func NewServices(repo repository.IFileRepository, store storage.Storage, pipeline *imaging.Pipeline) (*FileService, *ImageService) {
+	cfg := config.GetConfig()
+	files := NewFileService(
+		repo,
+		store,
+		storage.NewSigner(cfg.AuthSecret, cfg.StorageURLBase),
+		storage.Limits{MaxSize: cfg.StorageMaxSize, ContentTypes: ContentTypes},
+		cfg.StorageURLTTL,
+	)
+	return files, NewImageService(files, pipeline, cfg.StorageImageURLBase)
+}
+
+// Upload stores the content of r for ownerID, r is streamed and never held
+// in memory
+func (s *FileService) Upload(ctx context.Context, ownerID, name string, r io.Reader) (*model.File, error) {
+	file := model.File{OwnerID: ownerID, Name: filepath.Base(name)}
+	file.BeforeCreate()
+
+	object, err := storage.Upload(ctx, s.storage, file.Key, r, s.limits)
+	if err != nil {
+		logger.Errorf("Upload.Put fail, owner: %s, error: %s", ownerID, err)
+		return nil, err
+	}
+	file.ContentType = object.ContentType
+	file.Size = object.Size
+	file.Checksum = object.Checksum
+
+	if err := s.repo.Create(ctx, &file); err != nil {
+		logger.Errorf("Upload.Create fail, owner: %s, error: %s", ownerID, err)
+		_ = s.storage.Delete(ctx, file.Key)
+		return nil, err
+	}
+
+	return &file, nil
+}
+
+// GetFile returns a file of userID, or any file when readAny
+func (s *FileService) GetFile(ctx context.Context, userID, id string, readAny bool) (*model.File, error) {
+	file, err := s.getFile(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	// files of others do not exist for the user
+	if !readAny && file.OwnerID != userID {
+		return nil, ErrFileNotFound
+	}
+	return file, nil
+}
+
+// Download opens the file of a signed download url
+func (s *FileService) Download(ctx context.Context, id, expires, signature string) (*model.File, io.ReadCloser, error) {
+	if err := s.signer.Verify(id, expires, signature); err != nil {
+		return nil, nil, err
+	}
+
+	file, err := s.getFile(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	content, err := s.storage.Get(ctx, file.Key)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, nil, ErrFileNotFound
+	}
+	if err != nil {
+		logger.Errorf("Download.Get fail, id: %s, error: %s", id, err)
+		return nil, nil, err
+	}
+
+	return file, content, nil
+}
+
+func (s *FileService) Delete(ctx context.Context, userID, id string) error {
+	file, err := s.GetFile(ctx, userID, id, false)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(ctx, file); err != nil {
+		logger.Errorf("Delete fail, id: %s, error: %s", id, err)
+		return err
+	}
+	if err := s.storage.Delete(ctx, file.Key); err != nil {
+		logger.Errorf("Delete.Storage fail, id: %s, error: %s", id, err)
+	}
+	return nil
+}
+
+// DownloadURL returns a signed download url of file and when it expires
+func (s *FileService) DownloadURL(file *model.File) (string, time.Time) {
+	return s.signer.URL(file.ID, s.urlTTL)
+}
+
+func (s *FileService) getFile(ctx context.Context, id string) (*model.File, error) {
+	file, err := s.repo.GetFileByID(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrFileNotFound
+	}
+	if err != nil {
+		logger.Errorf("GetFileByID fail, id: %s, error: %s", id, err)
+		return nil, err
+	}
+	return file, nil
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/quangdangfit/gocommon/logger"
+
+	"main/internal/file/model"
+	"main/pkg/imaging"
+	"main/pkg/storage"
+)
+
+// imagesPrefix groups the variants in the storage, they are public
+const imagesPrefix = "images/"
+
+var ErrNotImage = errors.New("file is not an image")
+
+//go:generate mockery --name=IImageService
+type IImageService interface {
+	ProcessImage(ctx context.Context, ownerID, fileID string, done func(ctx context.Context, variants imaging.Variants) error) error
+	GetVariant(ctx context.Context, fileID, name string) (string, io.ReadCloser, error)
+}
+
+type ImageService struct {
+	files    *FileService
+	pipeline *imaging.Pipeline
+	// urlBase is joined with the file id and the variant name
+	urlBase string
+}
+
+func NewImageService(files *FileService, pipeline *imaging.Pipeline, urlBase string) *ImageService {
+	return &ImageService{
+		files:    files,
+		pipeline: pipeline,
+		urlBase:  urlBase,
+	}
+}
+
+// ProcessImage checks fileID is an image of ownerID and generates its
+// variants in the background, done is called with their urls once stored
+func (s *ImageService) ProcessImage(ctx context.Context, ownerID, fileID string, done func(ctx context.Context, variants imaging.Variants) error) error {
+	file, err := s.files.GetFile(ctx, ownerID, fileID, false)
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(file.ContentType, "image/") {
+		return ErrNotImage
+	}
+
+	return s.pipeline.Submit(func(ctx context.Context) {
+		variants, err := s.process(ctx, file.ID, file.Key)
+		if err != nil {
+			logger.Errorf("ProcessImage fail, file: %s, error: %s", file.ID, err)
+			return
+		}
+		if err := done(ctx, variants); err != nil {
+			logger.Errorf("ProcessImage.Done fail, file: %s, error: %s", file.ID, err)
+		}
+	})
+}
+
+// GetVariant returns the content type and the content of a variant
+func (s *ImageService) GetVariant(ctx context.Context, fileID, name string) (string, io.ReadCloser, error) {
+	ext := strings.TrimPrefix(path.Ext(name), ".")
+	if _, err := uuid.Parse(fileID); err != nil || !isVariant(name) {
+		return "", nil, ErrFileNotFound
+	}
+
+	content, err := s.files.storage.Get(ctx, variantKey(fileID, name))
+	if errors.Is(err, storage.ErrNotFound) {
+		return "", nil, ErrFileNotFound
+	}
+	if err != nil {
+		logger.Errorf("GetVariant fail, file: %s, error: %s", fileID, err)
+		return "", nil, err
+	}
+	return imaging.Formats[ext], content, nil
+}
+
+// RemoveStored deletes the content and the image variants of files from the
+// storage, once their rows are deleted
+func (s *ImageService) RemoveStored(ctx context.Context, files []*model.File) {
+	for _, file := range files {
+		keys := []string{file.Key}
+		if strings.HasPrefix(file.ContentType, "image/") {
+			for _, name := range imaging.VariantNames() {
+				keys = append(keys, variantKey(file.ID, name))
+			}
+		}
+		for _, key := range keys {
+			if err := s.files.storage.Delete(ctx, key); err != nil {
+				logger.Errorf("RemoveStored fail, file: %s, key: %s, error: %s", file.ID, key, err)
+			}
+		}
+	}
+}
+
+func (s *ImageService) process(ctx context.Context, fileID, key string) (imaging.Variants, error) {
+	content, err := s.files.storage.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer content.Close()
+
+	images, err := imaging.Process(content)
+	if err != nil {
+		return nil, err
+	}
+
+	variants := imaging.Variants{}
+	for _, image := range images {
+		err := s.files.storage.Put(ctx, variantKey(fileID, image.Name), bytes.NewReader(image.Data), int64(len(image.Data)), image.ContentType)
+		if err != nil {
+			return nil, err
+		}
+		variants[image.Name] = s.urlBase + "/" + fileID + "/" + image.Name
+	}
+	return variants, nil
+}
+
+func variantKey(fileID, name string) string {
+	return imagesPrefix + fileID + "/" + name
+}
+
+func isVariant(name string) bool {
+	for _, n := range imaging.VariantNames() {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,202 @@
+package dto
+
+import (
+	"time"
+
+	"main/pkg/paging"
+)
+
+// swagger:model Allergy
+type Allergy struct {
+	ID        string `json:"id"`
+	Substance string `json:"substance"`
+	Reaction  string `json:"reaction"`
+	// example: "severe"
+	Severity  string    `json:"severity"`
+	UpdatedAt time.Time `json:"updated_at"`
+	// Version of the allergy, also sent as its ETag
+	Version int64 `json:"version"`
+}
+
+// swagger:model SaveAllergyReq
+type SaveAllergyReq struct {
+	// example: "Penicillin"
+	Substance string `json:"substance" validate:"required,max=200"`
+	// example: "Hives"
+	Reaction string `json:"reaction" validate:"max=500"`
+	// example: "severe"
+	Severity string `json:"severity" validate:"omitempty,oneof=mild moderate severe"`
+	// Version the change applies to, from If-Match over HTTP, unused by creations
+	Version int64 `json:"-"`
+}
+
+// swagger:model Condition
+type Condition struct {
+	ID          string     `json:"id"`
+	Name        string     `json:"name"`
+	DiagnosedAt *time.Time `json:"diagnosed_at"`
+	// example: "active"
+	Status    string    `json:"status"`
+	Notes     string    `json:"notes"`
+	UpdatedAt time.Time `json:"updated_at"`
+	// Version of the condition, also sent as its ETag
+	Version int64 `json:"version"`
+}
+
+// swagger:model SaveConditionReq
+type SaveConditionReq struct {
+	// example: "Type 2 diabetes"
+	Name        string     `json:"name" validate:"required,max=200"`
+	DiagnosedAt *time.Time `json:"diagnosed_at"`
+	// Active by default
+	// example: "active"
+	Status string `json:"status" validate:"omitempty,oneof=active resolved"`
+	Notes  string `json:"notes" validate:"max=2000"`
+	// Version the change applies to, from If-Match over HTTP, unused by creations
+	Version int64 `json:"-"`
+}
+
+// swagger:model Medication
+type Medication struct {
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`
+	Dosage    string     `json:"dosage"`
+	Frequency string     `json:"frequency"`
+	StartedAt *time.Time `json:"started_at"`
+	EndedAt   *time.Time `json:"ended_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	// Version of the medication, also sent as its ETag
+	Version int64 `json:"version"`
+}
+
+// swagger:model SaveMedicationReq
+type SaveMedicationReq struct {
+	// example: "Metformin"
+	Name string `json:"name" validate:"required,max=200"`
+	// example: "500 mg"
+	Dosage string `json:"dosage" validate:"max=100"`
+	// example: "twice a day"
+	Frequency string     `json:"frequency" validate:"max=100"`
+	StartedAt *time.Time `json:"started_at"`
+	EndedAt   *time.Time `json:"ended_at"`
+	// Version the change applies to, from If-Match over HTTP, unused by creations
+	Version int64 `json:"-"`
+}
+
+// swagger:model Vital
+type Vital struct {
+	ID               string    `json:"id"`
+	RecordedAt       time.Time `json:"recorded_at"`
+	RecordedBy       string    `json:"recorded_by"`
+	HeightCm         *float64  `json:"height_cm"`
+	WeightKg         *float64  `json:"weight_kg"`
+	Systolic         *int      `json:"systolic"`
+	Diastolic        *int      `json:"diastolic"`
+	HeartRate        *int      `json:"heart_rate"`
+	TemperatureC     *float64  `json:"temperature_c"`
+	OxygenSaturation *int      `json:"oxygen_saturation"`
+	Notes            string    `json:"notes"`
+}
+
+// RecordVitalReq records a measurement, at least one value is required
+// swagger:model RecordVitalReq
+type RecordVitalReq struct {
+	// Now by default
+	RecordedAt *time.Time `json:"recorded_at"`
+	// example: 175
+	HeightCm *float64 `json:"height_cm" validate:"omitempty,gt=0,lt=300"`
+	// example: 72.5
+	WeightKg *float64 `json:"weight_kg" validate:"omitempty,gt=0,lt=700"`
+	// example: 120
+	Systolic *int `json:"systolic" validate:"omitempty,gt=0,lt=300"`
+	// example: 80
+	Diastolic *int `json:"diastolic" validate:"omitempty,gt=0,lt=300"`
+	// example: 70
+	HeartRate *int `json:"heart_rate" validate:"omitempty,gt=0,lt=300"`
+	// example: 36.8
+	TemperatureC *float64 `json:"temperature_c" validate:"omitempty,gt=25,lt=45"`
+	// example: 98
+	OxygenSaturation *int   `json:"oxygen_saturation" validate:"omitempty,gt=0,lte=100"`
+	Notes            string `json:"notes" validate:"max=2000"`
+}
+
+// HealthProfile is the current health data of a patient with the latest
+// vitals
+// swagger:model HealthProfile
+type HealthProfile struct {
+	PatientID string `json:"patient_id"`
+	// example: "O+"
+	BloodType string `json:"blood_type"`
+	// Version of the blood type, 0 until it is set, also the ETag to set it
+	Version     int64         `json:"version"`
+	Allergies   []*Allergy    `json:"allergies"`
+	Conditions  []*Condition  `json:"conditions"`
+	Medications []*Medication `json:"medications"`
+	LatestVital *Vital        `json:"latest_vital"`
+}
+
+// swagger:model SetBloodTypeReq
+type SetBloodTypeReq struct {
+	// Empty when unknown
+	// example: "O+"
+	BloodType string `json:"blood_type" validate:"omitempty,oneof=A+ A- B+ B- AB+ AB- O+ O-"`
+	// Version the change applies to, from If-Match over HTTP, 0 when the
+	// blood type was never set
+	Version int64 `json:"-"`
+}
+
+type ListVitalsReq struct {
+	Page  int64 `json:"page,omitempty" form:"page"`
+	Limit int64 `json:"limit,omitempty" form:"limit"`
+}
+
+// swagger:model ListVitalsRes
+type ListVitalsRes struct {
+	Vitals     []*Vital           `json:"vitals"`
+	Pagination *paging.Pagination `json:"pagination"`
+}
+
+// Change is a change of the health profile, Before and After are the json
+// of the entity
+// swagger:model HealthChange
+type Change struct {
+	ID        string    `json:"id"`
+	ActorID   string    `json:"actor_id"`
+	Entity    string    `json:"entity"`
+	EntityID  string    `json:"entity_id"`
+	Action    string    `json:"action"`
+	Before    string    `json:"before"`
+	After     string    `json:"after"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ListChangesReq struct {
+	// Only the changes of this entity, allergy for instance
+	Entity string `json:"entity,omitempty" form:"entity"`
+	Page   int64  `json:"page,omitempty" form:"page"`
+	Limit  int64  `json:"limit,omitempty" form:"limit"`
+}
+
+// swagger:model ListChangesRes
+type ListChangesRes struct {
+	Changes    []*Change          `json:"changes"`
+	Pagination *paging.Pagination `json:"pagination"`
+}
+
+// Grant is a doctor the patient gave access to its health profile
+// swagger:model Grant
+type Grant struct {
+	PatientID string    `json:"patient_id"`
+	DoctorID  string    `json:"doctor_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// swagger:model GrantAccessReq
+type GrantAccessReq struct {
+	DoctorID string `json:"doctor_id" validate:"required"`
+}
+
+// swagger:model ListGrantsRes
+type ListGrantsRes struct {
+	Grants []*Grant `json:"grants"`
+}
@@ -0,0 +1,176 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Entities of the health profile, as recorded in the changes
+const (
+	EntityProfile    = "profile"
+	EntityAllergy    = "allergy"
+	EntityCondition  = "condition"
+	EntityMedication = "medication"
+	EntityVital      = "vital"
+	EntityGrant      = "grant"
+)
+
+// Actions of the changes
+const (
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+)
+
+// Profile holds the health data of a patient that has a single value
+type Profile struct {
+	PatientID string    `json:"patient_id" gorm:"primary_key"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	BloodType string    `json:"blood_type" gorm:"type:text;serializer:encrypted"`
+	// Version is bumped by every change, it is the ETag of the profile
+	Version int64 `json:"version" gorm:"not null;default:1"`
+}
+
+func (Profile) TableName() string {
+	return "health_profiles"
+}
+
+type Allergy struct {
+	ID        string    `json:"id" gorm:"unique;not null;index;primary_key"`
+	PatientID string    `json:"patient_id" gorm:"not null;index"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Substance string    `json:"substance" gorm:"not null;type:text;serializer:encrypted"`
+	Reaction  string    `json:"reaction" gorm:"type:text;serializer:encrypted"`
+	// Severity is mild, moderate or severe
+	Severity string `json:"severity"`
+	// Version is bumped by every change, it is the ETag of the allergy
+	Version int64 `json:"version" gorm:"not null;default:1"`
+}
+
+func (Allergy) TableName() string {
+	return "health_allergies"
+}
+
+func (m *Allergy) BeforeCreate() error {
+	m.ID = uuid.New().String()
+	m.CreatedAt = time.Now()
+	return nil
+}
+
+// Condition is a chronic condition of a patient
+type Condition struct {
+	ID          string     `json:"id" gorm:"unique;not null;index;primary_key"`
+	PatientID   string     `json:"patient_id" gorm:"not null;index"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	Name        string     `json:"name" gorm:"not null;type:text;serializer:encrypted"`
+	DiagnosedAt *time.Time `json:"diagnosed_at"`
+	// Status is active or resolved
+	Status string `json:"status"`
+	Notes  string `json:"notes" gorm:"type:text;serializer:encrypted"`
+	// Version is bumped by every change, it is the ETag of the condition
+	Version int64 `json:"version" gorm:"not null;default:1"`
+}
+
+func (Condition) TableName() string {
+	return "health_conditions"
+}
+
+func (m *Condition) BeforeCreate() error {
+	m.ID = uuid.New().String()
+	m.CreatedAt = time.Now()
+	return nil
+}
+
+// Medication is a medication a patient takes, until EndedAt when set
+type Medication struct {
+	ID        string     `json:"id" gorm:"unique;not null;index;primary_key"`
+	PatientID string     `json:"patient_id" gorm:"not null;index"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	Name      string     `json:"name" gorm:"not null;type:text;serializer:encrypted"`
+	Dosage    string     `json:"dosage" gorm:"type:text;serializer:encrypted"`
+	Frequency string     `json:"frequency" gorm:"type:text;serializer:encrypted"`
+	StartedAt *time.Time `json:"started_at"`
+	EndedAt   *time.Time `json:"ended_at"`
+	// Version is bumped by every change, it is the ETag of the medication
+	Version int64 `json:"version" gorm:"not null;default:1"`
+}
+
+func (Medication) TableName() string {
+	return "health_medications"
+}
+
+func (m *Medication) BeforeCreate() error {
+	m.ID = uuid.New().String()
+	m.CreatedAt = time.Now()
+	return nil
+}
+
+// Vital is a measurement of the vitals of a patient, the measurements are
+// only added so they form the history of the vitals
+type Vital struct {
+	ID         string    `json:"id" gorm:"unique;not null;index;primary_key"`
+	PatientID  string    `json:"patient_id" gorm:"not null;index:idx_vital_patient_recorded"`
+	RecordedAt time.Time `json:"recorded_at" gorm:"not null;index:idx_vital_patient_recorded"`
+	// RecordedBy is the user who entered the measurement
+	RecordedBy       string   `json:"recorded_by"`
+	HeightCm         *float64 `json:"height_cm"`
+	WeightKg         *float64 `json:"weight_kg"`
+	Systolic         *int     `json:"systolic"`
+	Diastolic        *int     `json:"diastolic"`
+	HeartRate        *int     `json:"heart_rate"`
+	TemperatureC     *float64 `json:"temperature_c"`
+	OxygenSaturation *int     `json:"oxygen_saturation"`
+	Notes            string   `json:"notes" gorm:"type:text;serializer:encrypted"`
+}
+
+func (Vital) TableName() string {
+	return "health_vitals"
+}
+
+func (m *Vital) BeforeCreate() error {
+	m.ID = uuid.New().String()
+	if m.RecordedAt.IsZero() {
+		m.RecordedAt = time.Now()
+	}
+	return nil
+}
+
+// Grant lets a doctor read and edit the health profile of a patient
+type Grant struct {
+	PatientID string    `json:"patient_id" gorm:"primary_key"`
+	DoctorID  string    `json:"doctor_id" gorm:"primary_key;index"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (Grant) TableName() string {
+	return "health_grants"
+}
+
+// Change records who changed the health profile of a patient, with the
+// entity before and after as json
+type Change struct {
+	ID        string    `json:"id" gorm:"unique;not null;index;primary_key"`
+	PatientID string    `json:"patient_id" gorm:"not null;index:idx_change_patient_created"`
+	CreatedAt time.Time `json:"created_at" gorm:"index:idx_change_patient_created"`
+	ActorID   string    `json:"actor_id" gorm:"not null"`
+	Entity    string    `json:"entity" gorm:"not null"`
+	EntityID  string    `json:"entity_id"`
+	Action    string    `json:"action" gorm:"not null"`
+	Before    string    `json:"before" gorm:"type:text;serializer:encrypted"`
+	After     string    `json:"after" gorm:"type:text;serializer:encrypted"`
+}
+
+func (Change) TableName() string {
+	return "health_changes"
+}
+
+func (m *Change) BeforeCreate() error {
+	m.ID = uuid.New().String()
+	m.CreatedAt = time.Now()
+	return nil
+}
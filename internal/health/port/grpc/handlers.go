@@ -0,0 +1,409 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/quangdangfit/gocommon/logger"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"main/internal/health/dto"
+	"main/internal/health/model"
+	"main/internal/health/service"
+	"main/pkg/dbs"
+	"main/pkg/utils"
+	pb "main/proto/gen/go/health"
+)
+
+type HealthHandler struct {
+	service service.IHealthService
+	pb.UnimplementedHealthServiceServer
+}
+
+func NewHealthHandler(service service.IHealthService) *HealthHandler {
+	return &HealthHandler{service: service}
+}
+
+func (h *HealthHandler) GetProfile(ctx context.Context, req *pb.GetProfileReq) (*pb.HealthProfile, error) {
+	userID, _ := ctx.Value("userId").(string)
+	profile, err := h.service.GetProfile(ctx, userID, patientID(userID, req.PatientId))
+	if err != nil {
+		logger.Error("Failed to get health profile ", err)
+		return nil, healthError(err)
+	}
+
+	res := &pb.HealthProfile{PatientId: profile.PatientID, BloodType: profile.BloodType, Version: profile.Version}
+	for _, allergy := range profile.Allergies {
+		res.Allergies = append(res.Allergies, &pb.Allergy{
+			Id:        allergy.ID,
+			Substance: allergy.Substance,
+			Reaction:  allergy.Reaction,
+			Severity:  allergy.Severity,
+			UpdatedAt: allergy.UpdatedAt.Format(time.RFC3339),
+			Version:   allergy.Version,
+		})
+	}
+	for _, condition := range profile.Conditions {
+		res.Conditions = append(res.Conditions, conditionToPb(condition))
+	}
+	for _, medication := range profile.Medications {
+		res.Medications = append(res.Medications, &pb.Medication{
+			Id:        medication.ID,
+			Name:      medication.Name,
+			Dosage:    medication.Dosage,
+			Frequency: medication.Frequency,
+			StartedAt: formatTime(medication.StartedAt),
+			EndedAt:   formatTime(medication.EndedAt),
+			UpdatedAt: medication.UpdatedAt.Format(time.RFC3339),
+			Version:   medication.Version,
+		})
+	}
+	if profile.LatestVital != nil {
+		res.LatestVital = vitalToPb(profile.LatestVital)
+	}
+	return res, nil
+}
+
+func (h *HealthHandler) SetBloodType(ctx context.Context, req *pb.SetBloodTypeReq) (*pb.SetBloodTypeRes, error) {
+	userID, _ := ctx.Value("userId").(string)
+	profile, err := h.service.SetBloodType(ctx, userID, patientID(userID, req.PatientId), &dto.SetBloodTypeReq{
+		BloodType: req.BloodType,
+		Version:   req.Version,
+	})
+	if err != nil {
+		logger.Error("Failed to set blood type ", err)
+		return nil, healthError(err)
+	}
+
+	return &pb.SetBloodTypeRes{Version: profile.Version}, nil
+}
+
+func (h *HealthHandler) SaveAllergy(ctx context.Context, req *pb.SaveAllergyReq) (*pb.Allergy, error) {
+	userID, _ := ctx.Value("userId").(string)
+	allergy, err := h.service.SaveAllergy(ctx, userID, patientID(userID, req.PatientId), req.Id, &dto.SaveAllergyReq{
+		Substance: req.Substance,
+		Reaction:  req.Reaction,
+		Severity:  req.Severity,
+		Version:   req.Version,
+	})
+	if err != nil {
+		logger.Error("Failed to save allergy ", err)
+		return nil, healthError(err)
+	}
+
+	return &pb.Allergy{
+		Id:        allergy.ID,
+		Substance: allergy.Substance,
+		Reaction:  allergy.Reaction,
+		Severity:  allergy.Severity,
+		UpdatedAt: allergy.UpdatedAt.Format(time.RFC3339),
+		Version:   allergy.Version,
+	}, nil
+}
+
+func (h *HealthHandler) SaveCondition(ctx context.Context, req *pb.SaveConditionReq) (*pb.Condition, error) {
+	diagnosedAt, err := parseTime(req.DiagnosedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	userID, _ := ctx.Value("userId").(string)
+	condition, err := h.service.SaveCondition(ctx, userID, patientID(userID, req.PatientId), req.Id, &dto.SaveConditionReq{
+		Name:        req.Name,
+		DiagnosedAt: diagnosedAt,
+		Status:      req.Status,
+		Notes:       req.Notes,
+		Version:     req.Version,
+	})
+	if err != nil {
+		logger.Error("Failed to save condition ", err)
+		return nil, healthError(err)
+	}
+
+	var res dto.Condition
+	utils.Copy(&res, condition)
+	return conditionToPb(&res), nil
+}
+
+func (h *HealthHandler) SaveMedication(ctx context.Context, req *pb.SaveMedicationReq) (*pb.Medication, error) {
+	startedAt, err := parseTime(req.StartedAt)
+	if err != nil {
+		return nil, err
+	}
+	endedAt, err := parseTime(req.EndedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	userID, _ := ctx.Value("userId").(string)
+	medication, err := h.service.SaveMedication(ctx, userID, patientID(userID, req.PatientId), req.Id, &dto.SaveMedicationReq{
+		Name:      req.Name,
+		Dosage:    req.Dosage,
+		Frequency: req.Frequency,
+		StartedAt: startedAt,
+		EndedAt:   endedAt,
+		Version:   req.Version,
+	})
+	if err != nil {
+		logger.Error("Failed to save medication ", err)
+		return nil, healthError(err)
+	}
+
+	return &pb.Medication{
+		Id:        medication.ID,
+		Name:      medication.Name,
+		Dosage:    medication.Dosage,
+		Frequency: medication.Frequency,
+		StartedAt: formatTime(medication.StartedAt),
+		EndedAt:   formatTime(medication.EndedAt),
+		UpdatedAt: medication.UpdatedAt.Format(time.RFC3339),
+		Version:   medication.Version,
+	}, nil
+}
+
+func (h *HealthHandler) DeleteItem(ctx context.Context, req *pb.DeleteItemReq) (*pb.DeleteItemRes, error) {
+	userID, _ := ctx.Value("userId").(string)
+	if err := h.service.DeleteItem(ctx, userID, patientID(userID, req.PatientId), req.Entity, req.Id, req.Version); err != nil {
+		logger.Error("Failed to delete health item ", err)
+		return nil, healthError(err)
+	}
+
+	return &pb.DeleteItemRes{}, nil
+}
+
+func (h *HealthHandler) RecordVital(ctx context.Context, req *pb.RecordVitalReq) (*pb.Vital, error) {
+	recordedAt, err := parseTime(req.RecordedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	userID, _ := ctx.Value("userId").(string)
+	vital, err := h.service.RecordVital(ctx, userID, patientID(userID, req.PatientId), &dto.RecordVitalReq{
+		RecordedAt:       recordedAt,
+		HeightCm:         req.HeightCm,
+		WeightKg:         req.WeightKg,
+		Systolic:         toInt(req.Systolic),
+		Diastolic:        toInt(req.Diastolic),
+		HeartRate:        toInt(req.HeartRate),
+		TemperatureC:     req.TemperatureC,
+		OxygenSaturation: toInt(req.OxygenSaturation),
+		Notes:            req.Notes,
+	})
+	if err != nil {
+		logger.Error("Failed to record vital ", err)
+		return nil, healthError(err)
+	}
+
+	var res dto.Vital
+	utils.Copy(&res, vital)
+	return vitalToPb(&res), nil
+}
+
+func (h *HealthHandler) ListVitals(ctx context.Context, req *pb.ListVitalsReq) (*pb.ListVitalsRes, error) {
+	userID, _ := ctx.Value("userId").(string)
+	vitals, pagination, err := h.service.ListVitals(ctx, userID, patientID(userID, req.PatientId), &dto.ListVitalsReq{
+		Page:  req.Page,
+		Limit: req.Limit,
+	})
+	if err != nil {
+		logger.Error("Failed to list vitals ", err)
+		return nil, healthError(err)
+	}
+
+	var list []*dto.Vital
+	utils.Copy(&list, &vitals)
+	res := &pb.ListVitalsRes{Total: pagination.Total}
+	for _, vital := range list {
+		res.Vitals = append(res.Vitals, vitalToPb(vital))
+	}
+	return res, nil
+}
+
+func (h *HealthHandler) ListChanges(ctx context.Context, req *pb.ListChangesReq) (*pb.ListChangesRes, error) {
+	userID, _ := ctx.Value("userId").(string)
+	changes, pagination, err := h.service.ListChanges(ctx, userID, patientID(userID, req.PatientId), &dto.ListChangesReq{
+		Entity: req.Entity,
+		Page:   req.Page,
+		Limit:  req.Limit,
+	})
+	if err != nil {
+		logger.Error("Failed to list health changes ", err)
+		return nil, healthError(err)
+	}
+
+	res := &pb.ListChangesRes{Total: pagination.Total}
+	for _, change := range changes {
+		res.Changes = append(res.Changes, &pb.Change{
+			Id:        change.ID,
+			ActorId:   change.ActorID,
+			Entity:    change.Entity,
+			EntityId:  change.EntityID,
+			Action:    change.Action,
+			Before:    change.Before,
+			After:     change.After,
+			CreatedAt: change.CreatedAt.Format(time.RFC3339),
+		})
+	}
+	return res, nil
+}
+
+func (h *HealthHandler) ListGrants(ctx context.Context, req *pb.ListGrantsReq) (*pb.ListGrantsRes, error) {
+	userID, _ := ctx.Value("userId").(string)
+	if userID == "" {
+		return nil, status.New(codes.Unauthenticated, "unauthorized").Err()
+	}
+
+	grants, err := h.service.ListGrants(ctx, userID)
+	if err != nil {
+		logger.Error("Failed to list grants ", err)
+		return nil, healthError(err)
+	}
+
+	return grantsToPb(grants), nil
+}
+
+func (h *HealthHandler) GrantAccess(ctx context.Context, req *pb.GrantAccessReq) (*pb.Grant, error) {
+	userID, _ := ctx.Value("userId").(string)
+	if userID == "" {
+		return nil, status.New(codes.Unauthenticated, "unauthorized").Err()
+	}
+
+	grant, err := h.service.GrantAccess(ctx, userID, &dto.GrantAccessReq{DoctorID: req.DoctorId})
+	if err != nil {
+		logger.Error("Failed to grant access ", err)
+		return nil, healthError(err)
+	}
+
+	return grantToPb(grant), nil
+}
+
+func (h *HealthHandler) RevokeAccess(ctx context.Context, req *pb.RevokeAccessReq) (*pb.RevokeAccessRes, error) {
+	userID, _ := ctx.Value("userId").(string)
+	if userID == "" {
+		return nil, status.New(codes.Unauthenticated, "unauthorized").Err()
+	}
+
+	if err := h.service.RevokeAccess(ctx, userID, req.DoctorId); err != nil {
+		logger.Error("Failed to revoke access ", err)
+		return nil, healthError(err)
+	}
+
+	return &pb.RevokeAccessRes{}, nil
+}
+
+func (h *HealthHandler) ListPatients(ctx context.Context, req *pb.ListPatientsReq) (*pb.ListGrantsRes, error) {
+	userID, _ := ctx.Value("userId").(string)
+	grants, err := h.service.ListPatients(ctx, userID)
+	if err != nil {
+		logger.Error("Failed to list patients ", err)
+		return nil, healthError(err)
+	}
+
+	return grantsToPb(grants), nil
+}
+
+func conditionToPb(condition *dto.Condition) *pb.Condition {
+	return &pb.Condition{
+		Id:          condition.ID,
+		Name:        condition.Name,
+		DiagnosedAt: formatTime(condition.DiagnosedAt),
+		Status:      condition.Status,
+		Notes:       condition.Notes,
+		UpdatedAt:   condition.UpdatedAt.Format(time.RFC3339),
+		Version:     condition.Version,
+	}
+}
+
+func vitalToPb(vital *dto.Vital) *pb.Vital {
+	return &pb.Vital{
+		Id:               vital.ID,
+		RecordedAt:       vital.RecordedAt.Format(time.RFC3339),
+		RecordedBy:       vital.RecordedBy,
+		HeightCm:         vital.HeightCm,
+		WeightKg:         vital.WeightKg,
+		Systolic:         toInt32(vital.Systolic),
+		Diastolic:        toInt32(vital.Diastolic),
+		HeartRate:        toInt32(vital.HeartRate),
+		TemperatureC:     vital.TemperatureC,
+		OxygenSaturation: toInt32(vital.OxygenSaturation),
+		Notes:            vital.Notes,
+	}
+}
+
+func grantToPb(grant *model.Grant) *pb.Grant {
+	return &pb.Grant{
+		PatientId: grant.PatientID,
+		DoctorId:  grant.DoctorID,
+		CreatedAt: grant.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func grantsToPb(grants []*model.Grant) *pb.ListGrantsRes {
+	res := &pb.ListGrantsRes{}
+	for _, grant := range grants {
+		res.Grants = append(res.Grants, grantToPb(grant))
+	}
+	return res
+}
+
+// patientID is the patient of the request, me is the signed-in user
+func patientID(userID, id string) string {
+	if id == "me" {
+		return userID
+	}
+	return id
+}
+
+// parseTime parses an RFC 3339 date, nil when empty
+func parseTime(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, status.New(codes.InvalidArgument, "dates must be RFC 3339").Err()
+	}
+	return &t, nil
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+func toInt(value *int32) *int {
+	if value == nil {
+		return nil
+	}
+	v := int(*value)
+	return &v
+}
+
+func toInt32(value *int) *int32 {
+	if value == nil {
+		return nil
+	}
+	v := int32(*value)
+	return &v
+}
+
+func healthError(err error) error {
+	switch {
+	case errors.Is(err, dbs.ErrStaleVersion):
+		return status.New(codes.Aborted, err.Error()).Err()
+	case errors.Is(err, service.ErrAccessDenied):
+		return status.New(codes.PermissionDenied, err.Error()).Err()
+	case errors.Is(err, service.ErrDoctorNotFound), errors.Is(err, service.ErrItemNotFound), errors.Is(err, service.ErrGrantNotFound):
+		return status.New(codes.NotFound, err.Error()).Err()
+	case errors.Is(err, service.ErrAlreadyGranted):
+		return status.New(codes.AlreadyExists, err.Error()).Err()
+	case errors.Is(err, service.ErrEmptyVital), errors.Is(err, service.ErrInvalidPeriod), errors.Is(err, service.ErrUnknownEntity):
+		return status.New(codes.InvalidArgument, err.Error()).Err()
+	default:
+		return err
+	}
+}
@@ -0,0 +1,20 @@
+package grpc
+
+import (
+	"github.com/quangdangfit/gocommon/validation"
+	"google.golang.org/grpc"
+
+	doctorRepository "main/internal/doctor/repository"
+	"main/internal/health/repository"
+	"main/internal/health/service"
+	"main/pkg/dbs"
+	pb "main/proto/gen/go/health"
+)
+
+func RegisterHandlers(svr *grpc.Server, db dbs.IDatabase, validator validation.Validation) {
+	healthRepo := repository.NewHealthRepository(db)
+	healthSvc := service.NewHealthService(validator, healthRepo, doctorRepository.NewDoctorRepository(db))
+	healthHandler := NewHealthHandler(healthSvc)
+
+	pb.RegisterHealthServiceServer(svr, healthHandler)
+}
@@ -0,0 +1,535 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/quangdangfit/gocommon/logger"
+
+	"main/internal/health/dto"
+	"main/internal/health/model"
+	"main/internal/health/service"
+	"main/pkg/dbs"
+	"main/pkg/response"
+	"main/pkg/utils"
+)
+
+// health data is never cached
+type HealthHandler struct {
+	service service.IHealthService
+}
+
+func NewHealthHandler(service service.IHealthService) *HealthHandler {
+	return &HealthHandler{service: service}
+}
+
+// GetProfile godoc
+//
+//	@Summary	Get the health profile of a patient, me for the signed-in one
+//	@Tags		Health
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		id	path		string	true	"Patient ID"
+//	@Success	200	{object}	dto.HealthProfile
+//	@Router		/health/patients/{id} [get]
+func (h *HealthHandler) GetProfile(c *gin.Context) {
+	profile, err := h.service.GetProfile(c, c.GetString("userId"), patientID(c))
+	if err != nil {
+		logger.Error("Failed to get health profile ", err)
+		healthError(c, err)
+		return
+	}
+
+	response.JSON(c, http.StatusOK, profile)
+}
+
+// SetBloodType godoc
+//
+//	@Summary	Set the blood type of a patient
+//	@Tags		Health
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		id	path	string				true	"Patient ID"
+//	@Param		If-Match	header	string	true	"ETag of the blood type, the version of the health profile, 0 before it is set"
+//	@Param		_	body	dto.SetBloodTypeReq	true	"Body"
+//	@Success	200
+//	@Failure	412	{object}	response.Response	"Changed since it was read"
+//	@Router		/health/patients/{id}/blood-type [put]
+func (h *HealthHandler) SetBloodType(c *gin.Context) {
+	version, ok := response.IfMatch(c)
+	if !ok {
+		return
+	}
+
+	var req dto.SetBloodTypeReq
+	if err := c.ShouldBindJSON(&req); c.Request.Body == nil || err != nil {
+		logger.Error("Failed to get body", err)
+		response.Error(c, http.StatusBadRequest, err, "Invalid parameters")
+		return
+	}
+
+	req.Version = version
+	profile, err := h.service.SetBloodType(c, c.GetString("userId"), patientID(c), &req)
+	if err != nil {
+		logger.Error("Failed to set blood type ", err)
+		healthError(c, err)
+		return
+	}
+
+	response.Versioned(c, http.StatusOK, profile.Version, nil)
+}
+
+// AddAllergy godoc
+//
+//	@Summary	Add an allergy to the health profile of a patient
+//	@Tags		Health
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		id	path		string				true	"Patient ID"
+//	@Param		_	body		dto.SaveAllergyReq	true	"Body"
+//	@Success	200	{object}	dto.Allergy
+//	@Router		/health/patients/{id}/allergies [post]
+func (h *HealthHandler) AddAllergy(c *gin.Context) {
+	h.saveAllergy(c, "")
+}
+
+// UpdateAllergy godoc
+//
+//	@Summary	Update an allergy of a patient
+//	@Tags		Health
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		id		path		string				true	"Patient ID"
+//	@Param		itemId	path		string				true	"Allergy ID"
+//	@Param		If-Match	header	string	true	"ETag of the allergy, its version"
+//	@Param		_		body		dto.SaveAllergyReq	true	"Body"
+//	@Success	200		{object}	dto.Allergy
+//	@Failure	412	{object}	response.Response	"Changed since it was read"
+//	@Router		/health/patients/{id}/allergies/{itemId} [put]
+func (h *HealthHandler) UpdateAllergy(c *gin.Context) {
+	h.saveAllergy(c, c.Param("itemId"))
+}
+
+func (h *HealthHandler) saveAllergy(c *gin.Context, id string) {
+	version, ok := ifMatch(c, id)
+	if !ok {
+		return
+	}
+
+	var req dto.SaveAllergyReq
+	if err := c.ShouldBindJSON(&req); c.Request.Body == nil || err != nil {
+		logger.Error("Failed to get body", err)
+		response.Error(c, http.StatusBadRequest, err, "Invalid parameters")
+		return
+	}
+
+	req.Version = version
+	allergy, err := h.service.SaveAllergy(c, c.GetString("userId"), patientID(c), id, &req)
+	if err != nil {
+		logger.Error("Failed to save allergy ", err)
+		healthError(c, err)
+		return
+	}
+
+	var res dto.Allergy
+	utils.Copy(&res, allergy)
+	response.Versioned(c, http.StatusOK, res.Version, res)
+}
+
+// DeleteAllergy godoc
+//
+//	@Summary	Delete an allergy of a patient
+//	@Tags		Health
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		id		path	string	true	"Patient ID"
+//	@Param		itemId	path	string	true	"Allergy ID"
+//	@Param		If-Match	header	string	true	"ETag of the allergy, its version"
+//	@Success	200
+//	@Failure	412	{object}	response.Response	"Changed since it was read"
+//	@Router		/health/patients/{id}/allergies/{itemId} [delete]
+func (h *HealthHandler) DeleteAllergy(c *gin.Context) {
+	h.deleteItem(c, model.EntityAllergy)
+}
+
+// AddCondition godoc
+//
+//	@Summary	Add a chronic condition to the health profile of a patient
+//	@Tags		Health
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		id	path		string					true	"Patient ID"
+//	@Param		_	body		dto.SaveConditionReq	true	"Body"
+//	@Success	200	{object}	dto.Condition
+//	@Router		/health/patients/{id}/conditions [post]
+func (h *HealthHandler) AddCondition(c *gin.Context) {
+	h.saveCondition(c, "")
+}
+
+// UpdateCondition godoc
+//
+//	@Summary	Update a chronic condition of a patient
+//	@Tags		Health
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		id		path		string					true	"Patient ID"
+//	@Param		itemId	path		string					true	"Condition ID"
+//	@Param		If-Match	header	string	true	"ETag of the condition, its version"
+//	@Param		_		body		dto.SaveConditionReq	true	"Body"
+//	@Success	200		{object}	dto.Condition
+//	@Failure	412	{object}	response.Response	"Changed since it was read"
+//	@Router		/health/patients/{id}/conditions/{itemId} [put]
+func (h *HealthHandler) UpdateCondition(c *gin.Context) {
+	h.saveCondition(c, c.Param("itemId"))
+}
+
+func (h *HealthHandler) saveCondition(c *gin.Context, id string) {
+	version, ok := ifMatch(c, id)
+	if !ok {
+		return
+	}
+
+	var req dto.SaveConditionReq
+	if err := c.ShouldBindJSON(&req); c.Request.Body == nil || err != nil {
+		logger.Error("Failed to get body", err)
+		response.Error(c, http.StatusBadRequest, err, "Invalid parameters")
+		return
+	}
+
+	req.Version = version
+	condition, err := h.service.SaveCondition(c, c.GetString("userId"), patientID(c), id, &req)
+	if err != nil {
+		logger.Error("Failed to save condition ", err)
+		healthError(c, err)
+		return
+	}
+
+	var res dto.Condition
+	utils.Copy(&res, condition)
+	response.Versioned(c, http.StatusOK, res.Version, res)
+}
+
+// DeleteCondition godoc
+//
+//	@Summary	Delete a chronic condition of a patient
+//	@Tags		Health
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		id		path	string	true	"Patient ID"
+//	@Param		itemId	path	string	true	"Condition ID"
+//	@Param		If-Match	header	string	true	"ETag of the condition, its version"
+//	@Success	200
+//	@Failure	412	{object}	response.Response	"Changed since it was read"
+//	@Router		/health/patients/{id}/conditions/{itemId} [delete]
+func (h *HealthHandler) DeleteCondition(c *gin.Context) {
+	h.deleteItem(c, model.EntityCondition)
+}
+
+// AddMedication godoc
+//
+//	@Summary	Add a current medication to the health profile of a patient
+//	@Tags		Health
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		id	path		string					true	"Patient ID"
+//	@Param		_	body		dto.SaveMedicationReq	true	"Body"
+//	@Success	200	{object}	dto.Medication
+//	@Router		/health/patients/{id}/medications [post]
+func (h *HealthHandler) AddMedication(c *gin.Context) {
+	h.saveMedication(c, "")
+}
+
+// UpdateMedication godoc
+//
+//	@Summary	Update a medication of a patient
+//	@Tags		Health
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		id		path		string					true	"Patient ID"
+//	@Param		itemId	path		string					true	"Medication ID"
+//	@Param		If-Match	header	string	true	"ETag of the medication, its version"
+//	@Param		_		body		dto.SaveMedicationReq	true	"Body"
+//	@Success	200		{object}	dto.Medication
+//	@Failure	412	{object}	response.Response	"Changed since it was read"
+//	@Router		/health/patients/{id}/medications/{itemId} [put]
+func (h *HealthHandler) UpdateMedication(c *gin.Context) {
+	h.saveMedication(c, c.Param("itemId"))
+}
+
+func (h *HealthHandler) saveMedication(c *gin.Context, id string) {
+	version, ok := ifMatch(c, id)
+	if !ok {
+		return
+	}
+
+	var req dto.SaveMedicationReq
+	if err := c.ShouldBindJSON(&req); c.Request.Body == nil || err != nil {
+		logger.Error("Failed to get body", err)
+		response.Error(c, http.StatusBadRequest, err, "Invalid parameters")
+		return
+	}
+
+	req.Version = version
+	medication, err := h.service.SaveMedication(c, c.GetString("userId"), patientID(c), id, &req)
+	if err != nil {
+		logger.Error("Failed to save medication ", err)
+		healthError(c, err)
+		return
+	}
+
+	var res dto.Medication
+	utils.Copy(&res, medication)
+	response.Versioned(c, http.StatusOK, res.Version, res)
+}
+
+// DeleteMedication godoc
+//
+//	@Summary	Delete a medication of a patient
+//	@Tags		Health
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		id		path	string	true	"Patient ID"
+//	@Param		itemId	path	string	true	"Medication ID"
+//	@Param		If-Match	header	string	true	"ETag of the medication, its version"
+//	@Success	200
+//	@Failure	412	{object}	response.Response	"Changed since it was read"
+//	@Router		/health/patients/{id}/medications/{itemId} [delete]
+func (h *HealthHandler) DeleteMedication(c *gin.Context) {
+	h.deleteItem(c, model.EntityMedication)
+}
+
+func (h *HealthHandler) deleteItem(c *gin.Context, entity string) {
+	version, ok := response.IfMatch(c)
+	if !ok {
+		return
+	}
+
+	err := h.service.DeleteItem(c, c.GetString("userId"), patientID(c), entity, c.Param("itemId"), version)
+	if err != nil {
+		logger.Error("Failed to delete "+entity+" ", err)
+		healthError(c, err)
+		return
+	}
+
+	response.JSON(c, http.StatusOK, nil)
+}
+
+// RecordVital godoc
+//
+//	@Summary	Record a measurement of the vitals of a patient
+//	@Tags		Health
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		id	path		string				true	"Patient ID"
+//	@Param		_	body		dto.RecordVitalReq	true	"Body"
+//	@Success	200	{object}	dto.Vital
+//	@Router		/health/patients/{id}/vitals [post]
+func (h *HealthHandler) RecordVital(c *gin.Context) {
+	var req dto.RecordVitalReq
+	if err := c.ShouldBindJSON(&req); c.Request.Body == nil || err != nil {
+		logger.Error("Failed to get body", err)
+		response.Error(c, http.StatusBadRequest, err, "Invalid parameters")
+		return
+	}
+
+	vital, err := h.service.RecordVital(c, c.GetString("userId"), patientID(c), &req)
+	if err != nil {
+		logger.Error("Failed to record vital ", err)
+		healthError(c, err)
+		return
+	}
+
+	var res dto.Vital
+	utils.Copy(&res, vital)
+	response.JSON(c, http.StatusOK, res)
+}
+
+// ListVitals godoc
+//
+//	@Summary	List the vitals history of a patient, newest first
+//	@Tags		Health
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		id		path		string	true	"Patient ID"
+//	@Param		page	query		int		false	"Page"
+//	@Param		limit	query		int		false	"Limit"
+//	@Success	200		{object}	dto.ListVitalsRes
+//	@Router		/health/patients/{id}/vitals [get]
+func (h *HealthHandler) ListVitals(c *gin.Context) {
+	var req dto.ListVitalsReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.Error("Failed to get query params", err)
+		response.Error(c, http.StatusBadRequest, err, "Invalid parameters")
+		return
+	}
+
+	vitals, pagination, err := h.service.ListVitals(c, c.GetString("userId"), patientID(c), &req)
+	if err != nil {
+		logger.Error("Failed to list vitals ", err)
+		healthError(c, err)
+		return
+	}
+
+	var res dto.ListVitalsRes
+	utils.Copy(&res.Vitals, &vitals)
+	res.Pagination = pagination
+	response.JSON(c, http.StatusOK, res)
+}
+
+// ListChanges godoc
+//
+//	@Summary	List the changes of the health profile of a patient, newest first
+//	@Tags		Health
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		id		path		string	true	"Patient ID"
+//	@Param		entity	query		string	false	"profile, allergy, condition, medication, vital or grant"
+//	@Param		page	query		int		false	"Page"
+//	@Param		limit	query		int		false	"Limit"
+//	@Success	200		{object}	dto.ListChangesRes
+//	@Router		/health/patients/{id}/history [get]
+func (h *HealthHandler) ListChanges(c *gin.Context) {
+	var req dto.ListChangesReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.Error("Failed to get query params", err)
+		response.Error(c, http.StatusBadRequest, err, "Invalid parameters")
+		return
+	}
+
+	changes, pagination, err := h.service.ListChanges(c, c.GetString("userId"), patientID(c), &req)
+	if err != nil {
+		logger.Error("Failed to list health changes ", err)
+		healthError(c, err)
+		return
+	}
+
+	var res dto.ListChangesRes
+	utils.Copy(&res.Changes, &changes)
+	res.Pagination = pagination
+	response.JSON(c, http.StatusOK, res)
+}
+
+// ListGrants godoc
+//
+//	@Summary	List the doctors the signed-in patient gave access to its health profile
+//	@Tags		Health
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Success	200	{object}	dto.ListGrantsRes
+//	@Router		/health/grants [get]
+func (h *HealthHandler) ListGrants(c *gin.Context) {
+	grants, err := h.service.ListGrants(c, c.GetString("userId"))
+	if err != nil {
+		logger.Error("Failed to list grants ", err)
+		healthError(c, err)
+		return
+	}
+
+	var res dto.ListGrantsRes
+	utils.Copy(&res.Grants, &grants)
+	response.JSON(c, http.StatusOK, res)
+}
+
+// GrantAccess godoc
+//
+//	@Summary	Give a doctor access to the health profile of the signed-in patient
+//	@Tags		Health
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		_	body		dto.GrantAccessReq	true	"Body"
+//	@Success	200	{object}	dto.Grant
+//	@Router		/health/grants [post]
+func (h *HealthHandler) GrantAccess(c *gin.Context) {
+	var req dto.GrantAccessReq
+	if err := c.ShouldBindJSON(&req); c.Request.Body == nil || err != nil {
+		logger.Error("Failed to get body", err)
+		response.Error(c, http.StatusBadRequest, err, "Invalid parameters")
+		return
+	}
+
+	grant, err := h.service.GrantAccess(c, c.GetString("userId"), &req)
+	if err != nil {
+		logger.Error("Failed to grant access ", err)
+		healthError(c, err)
+		return
+	}
+
+	var res dto.Grant
+	utils.Copy(&res, grant)
+	response.JSON(c, http.StatusOK, res)
+}
+
+// RevokeAccess godoc
+//
+//	@Summary	Remove the access of a doctor to the health profile of the signed-in patient
+//	@Tags		Health
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		doctorId	path	string	true	"Doctor ID"
+//	@Success	200
+//	@Router		/health/grants/{doctorId} [delete]
+func (h *HealthHandler) RevokeAccess(c *gin.Context) {
+	if err := h.service.RevokeAccess(c, c.GetString("userId"), c.Param("doctorId")); err != nil {
+		logger.Error("Failed to revoke access ", err)
+		healthError(c, err)
+		return
+	}
+
+	response.JSON(c, http.StatusOK, nil)
+}
+
+// ListPatients godoc
+//
+//	@Summary	List the patients who gave the signed-in doctor access to their health profile
+//	@Tags		Health
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Success	200	{object}	dto.ListGrantsRes
+//	@Router		/health/patients [get]
+func (h *HealthHandler) ListPatients(c *gin.Context) {
+	grants, err := h.service.ListPatients(c, c.GetString("userId"))
+	if err != nil {
+		logger.Error("Failed to list patients ", err)
+		healthError(c, err)
+		return
+	}
+
+	var res dto.ListGrantsRes
+	utils.Copy(&res.Grants, &grants)
+	response.JSON(c, http.StatusOK, res)
+}
+
+// ifMatch returns the If-Match version of the item id to update, the new
+// items have none
+func ifMatch(c *gin.Context, id string) (int64, bool) {
+	if id == "" {
+		return 0, true
+	}
+	return response.IfMatch(c)
+}
+
+// patientID is the patient of the path, me is the signed-in user
+func patientID(c *gin.Context) string {
+	if id := c.Param("id"); id != "me" {
+		return id
+	}
+	return c.GetString("userId")
+}
+
+func healthError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, dbs.ErrStaleVersion):
+		response.StaleVersion(c, err)
+	case errors.Is(err, service.ErrAccessDenied):
+		response.Error(c, http.StatusForbidden, err, err.Error())
+	case errors.Is(err, service.ErrDoctorNotFound), errors.Is(err, service.ErrItemNotFound), errors.Is(err, service.ErrGrantNotFound):
+		response.Error(c, http.StatusNotFound, err, err.Error())
+	case errors.Is(err, service.ErrAlreadyGranted):
+		response.Error(c, http.StatusConflict, err, err.Error())
+	case errors.Is(err, service.ErrEmptyVital), errors.Is(err, service.ErrInvalidPeriod), errors.Is(err, service.ErrUnknownEntity):
+		response.Error(c, http.StatusBadRequest, err, err.Error())
+	default:
+		response.Error(c, http.StatusInternalServerError, err, "Something went wrong")
+	}
+}
@@ -0,0 +1,43 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/quangdangfit/gocommon/validation"
+
+	doctorRepository "main/internal/doctor/repository"
+	"main/internal/health/repository"
+	"main/internal/health/service"
+	"main/pkg/dbs"
+	"main/pkg/middleware"
+)
+
+func Routes(r *gin.RouterGroup, sqlDB dbs.IDatabase, validator validation.Validation, auth *middleware.Authenticator) {
+	healthRepo := repository.NewHealthRepository(sqlDB)
+	healthSvc := service.NewHealthService(validator, healthRepo, doctorRepository.NewDoctorRepository(sqlDB))
+	healthHandler := NewHealthHandler(healthSvc)
+
+	// the patient itself, or a verified doctor it gave access
+	userAuthMiddleware := middleware.JWTAuth(auth)
+	healthRoute := r.Group("/health")
+	{
+		healthRoute.GET("/patients", userAuthMiddleware, healthHandler.ListPatients)
+		healthRoute.GET("/patients/:id", userAuthMiddleware, healthHandler.GetProfile)
+		healthRoute.PUT("/patients/:id/blood-type", userAuthMiddleware, healthHandler.SetBloodType)
+		healthRoute.POST("/patients/:id/allergies", userAuthMiddleware, healthHandler.AddAllergy)
+		healthRoute.PUT("/patients/:id/allergies/:itemId", userAuthMiddleware, healthHandler.UpdateAllergy)
+		healthRoute.DELETE("/patients/:id/allergies/:itemId", userAuthMiddleware, healthHandler.DeleteAllergy)
+		healthRoute.POST("/patients/:id/conditions", userAuthMiddleware, healthHandler.AddCondition)
+		healthRoute.PUT("/patients/:id/conditions/:itemId", userAuthMiddleware, healthHandler.UpdateCondition)
+		healthRoute.DELETE("/patients/:id/conditions/:itemId", userAuthMiddleware, healthHandler.DeleteCondition)
+		healthRoute.POST("/patients/:id/medications", userAuthMiddleware, healthHandler.AddMedication)
+		healthRoute.PUT("/patients/:id/medications/:itemId", userAuthMiddleware, healthHandler.UpdateMedication)
+		healthRoute.DELETE("/patients/:id/medications/:itemId", userAuthMiddleware, healthHandler.DeleteMedication)
+		healthRoute.GET("/patients/:id/vitals", userAuthMiddleware, healthHandler.ListVitals)
+		healthRoute.POST("/patients/:id/vitals", userAuthMiddleware, healthHandler.RecordVital)
+		healthRoute.GET("/patients/:id/history", userAuthMiddleware, healthHandler.ListChanges)
+		// doctors the signed-in patient gave access
+		healthRoute.GET("/grants", userAuthMiddleware, healthHandler.ListGrants)
+		healthRoute.POST("/grants", userAuthMiddleware, healthHandler.GrantAccess)
+		healthRoute.DELETE("/grants/:doctorId", userAuthMiddleware, healthHandler.RevokeAccess)
+	}
+}
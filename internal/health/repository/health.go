@@ -0,0 +1,231 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"main/internal/health/dto"
+	"main/internal/health/model"
+	"main/pkg/config"
+	"main/pkg/dbs"
+	"main/pkg/paging"
+)
+
+//go:generate mockery --name=IHealthRepository
+type IHealthRepository interface {
+	GetProfile(ctx context.Context, patientID string) (*model.Profile, error)
+	ListItems(ctx context.Context, patientID string, items interface{}) error
+	GetItem(ctx context.Context, patientID, id string, item interface{}) error
+	Save(ctx context.Context, item interface{}, change *model.Change) error
+	Delete(ctx context.Context, item interface{}, version int64, change *model.Change) error
+	LatestVital(ctx context.Context, patientID string) (*model.Vital, error)
+	ListVitals(ctx context.Context, patientID string, req *dto.ListVitalsReq) ([]*model.Vital, *paging.Pagination, error)
+	ListChanges(ctx context.Context, patientID string, req *dto.ListChangesReq) ([]*model.Change, *paging.Pagination, error)
+	HasGrant(ctx context.Context, patientID, doctorID string) (bool, error)
+	ListGrants(ctx context.Context, patientID string) ([]*model.Grant, error)
+	ListGrantsOfDoctor(ctx context.Context, doctorID string) ([]*model.Grant, error)
+	CreateGrant(ctx context.Context, grant *model.Grant, change *model.Change) (bool, error)
+	DeleteGrant(ctx context.Context, grant *model.Grant, change *model.Change) (bool, error)
+}
+
+type HealthRepo struct {
+	db dbs.IDatabase
+}
+
+func NewHealthRepository(db dbs.IDatabase) *HealthRepo {
+	return &HealthRepo{db: db}
+}
+
+func (r *HealthRepo) GetProfile(ctx context.Context, patientID string) (*model.Profile, error) {
+	var profile model.Profile
+	if err := r.db.GetDB().WithContext(ctx).Where("patient_id = ?", patientID).First(&profile).Error; err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// ListItems lists the allergies, conditions or medications of the patient in
+// the order they were added
+func (r *HealthRepo) ListItems(ctx context.Context, patientID string, items interface{}) error {
+	return r.db.GetDB().WithContext(ctx).
+		Where("patient_id = ?", patientID).
+		Order("created_at").
+		Find(items).Error
+}
+
+// GetItem finds the allergy, condition or medication id of the patient
+func (r *HealthRepo) GetItem(ctx context.Context, patientID, id string, item interface{}) error {
+	return r.db.GetDB().WithContext(ctx).
+		Where("id = ? AND patient_id = ?", id, patientID).
+		First(item).Error
+}
+
+// Save creates item, or updates it if it is still at its version, and
+// records change with it
+func (r *HealthRepo) Save(ctx context.Context, item interface{}, change *model.Change) error {
+	return r.db.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		if change.Action == model.ActionCreate {
+			err = tx.Create(item).Error
+		} else {
+			err = dbs.SaveVersion(tx, item)
+		}
+		if err != nil {
+			return err
+		}
+		return tx.Create(change).Error
+	})
+}
+
+// Delete deletes item if it is still at version and records change with it
+func (r *HealthRepo) Delete(ctx context.Context, item interface{}, version int64, change *model.Change) error {
+	return r.db.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := dbs.DeleteVersion(tx, item, version); err != nil {
+			return err
+		}
+		return tx.Create(change).Error
+	})
+}
+
+func (r *HealthRepo) LatestVital(ctx context.Context, patientID string) (*model.Vital, error) {
+	var vital model.Vital
+	err := r.db.GetDB().WithContext(ctx).
+		Where("patient_id = ?", patientID).
+		Order("recorded_at DESC").
+		First(&vital).Error
+	if err != nil {
+		return nil, err
+	}
+	return &vital, nil
+}
+
+// ListVitals lists the measurements newest first
+func (r *HealthRepo) ListVitals(ctx context.Context, patientID string, req *dto.ListVitalsReq) ([]*model.Vital, *paging.Pagination, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.DatabaseTimeout)
+	defer cancel()
+
+	query := []dbs.Query{dbs.NewQuery("patient_id = ?", patientID)}
+
+	var total int64
+	if err := r.db.Count(ctx, &model.Vital{}, &total, dbs.WithQuery(query...)); err != nil {
+		return nil, nil, err
+	}
+
+	pagination := paging.New(req.Page, req.Limit, total)
+
+	var vitals []*model.Vital
+	if err := r.db.Find(
+		ctx,
+		&vitals,
+		dbs.WithQuery(query...),
+		dbs.WithLimit(int(pagination.Limit)),
+		dbs.WithOffset(int(pagination.Skip)),
+		dbs.WithOrder("recorded_at DESC"),
+	); err != nil {
+		return nil, nil, err
+	}
+
+	return vitals, pagination, nil
+}
+
+// ListChanges lists the changes of the patient newest first
+func (r *HealthRepo) ListChanges(ctx context.Context, patientID string, req *dto.ListChangesReq) ([]*model.Change, *paging.Pagination, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.DatabaseTimeout)
+	defer cancel()
+
+	query := []dbs.Query{dbs.NewQuery("patient_id = ?", patientID)}
+	if req.Entity != "" {
+		query = append(query, dbs.NewQuery("entity = ?", req.Entity))
+	}
+
+	var total int64
+	if err := r.db.Count(ctx, &model.Change{}, &total, dbs.WithQuery(query...)); err != nil {
+		return nil, nil, err
+	}
+
+	pagination := paging.New(req.Page, req.Limit, total)
+
+	var changes []*model.Change
+	if err := r.db.Find(
+		ctx,
+		&changes,
+		dbs.WithQuery(query...),
+		dbs.WithLimit(int(pagination.Limit)),
+		dbs.WithOffset(int(pagination.Skip)),
+		dbs.WithOrder("created_at DESC"),
+	); err != nil {
+		return nil, nil, err
+	}
+
+	return changes, pagination, nil
+}
+
+func (r *HealthRepo) HasGrant(ctx context.Context, patientID, doctorID string) (bool, error) {
+	var count int64
+	err := r.db.GetDB().WithContext(ctx).Model(&model.Grant{}).
+		Where("patient_id = ? AND doctor_id = ?", patientID, doctorID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *HealthRepo) ListGrants(ctx context.Context, patientID string) ([]*model.Grant, error) {
+	var grants []*model.Grant
+	err := r.db.GetDB().WithContext(ctx).
+		Where("patient_id = ?", patientID).
+		Order("created_at").
+		Find(&grants).Error
+	if err != nil {
+		return nil, err
+	}
+	return grants, nil
+}
+
+func (r *HealthRepo) ListGrantsOfDoctor(ctx context.Context, doctorID string) ([]*model.Grant, error) {
+	var grants []*model.Grant
+	err := r.db.GetDB().WithContext(ctx).
+		Where("doctor_id = ?", doctorID).
+		Order("created_at").
+		Find(&grants).Error
+	if err != nil {
+		return nil, err
+	}
+	return grants, nil
+}
+
+// CreateGrant adds the grant and records change with it, false when the
+// doctor already has access
+func (r *HealthRepo) CreateGrant(ctx context.Context, grant *model.Grant, change *model.Change) (bool, error) {
+	created := false
+	err := r.db.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(grant)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		if err := tx.Create(change).Error; err != nil {
+			return err
+		}
+		created = true
+		return nil
+	})
+	return created, err
+}
+
+// DeleteGrant revokes the grant and records change with it, false when the
+// doctor had no access
+func (r *HealthRepo) DeleteGrant(ctx context.Context, grant *model.Grant, change *model.Change) (bool, error) {
+	deleted := false
+	err := r.db.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("patient_id = ? AND doctor_id = ?", grant.PatientID, grant.DoctorID).Delete(&model.Grant{})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		if err := tx.Create(change).Error; err != nil {
+			return err
+		}
+		deleted = true
+		return nil
+	})
+	return deleted, err
+}
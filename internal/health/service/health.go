@@ -0,0 +1,467 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/quangdangfit/gocommon/logger"
+	"github.com/quangdangfit/gocommon/validation"
+	"gorm.io/gorm"
+
+	doctorModel "main/internal/doctor/model"
+	"main/internal/health/dto"
+	"main/internal/health/model"
+	"main/internal/health/repository"
+	"main/pkg/dbs"
+	"main/pkg/paging"
+	"main/pkg/utils"
+)
+
+var (
+	ErrAccessDenied   = errors.New("no access to the health profile of this patient")
+	ErrDoctorNotFound = errors.New("doctor not found")
+	ErrItemNotFound   = errors.New("item not found")
+	ErrGrantNotFound  = errors.New("doctor has no access")
+	ErrAlreadyGranted = errors.New("doctor already has access")
+	ErrEmptyVital     = errors.New("at least one measurement is required")
+	ErrInvalidPeriod  = errors.New("ended_at must be after started_at")
+	ErrUnknownEntity  = errors.New("unknown entity")
+)
+
+//go:generate mockery --name=IHealthService
+type IHealthService interface {
+	GetProfile(ctx context.Context, actorID, patientID string) (*dto.HealthProfile, error)
+	SetBloodType(ctx context.Context, actorID, patientID string, req *dto.SetBloodTypeReq) (*model.Profile, error)
+	SaveAllergy(ctx context.Context, actorID, patientID, id string, req *dto.SaveAllergyReq) (*model.Allergy, error)
+	SaveCondition(ctx context.Context, actorID, patientID, id string, req *dto.SaveConditionReq) (*model.Condition, error)
+	SaveMedication(ctx context.Context, actorID, patientID, id string, req *dto.SaveMedicationReq) (*model.Medication, error)
+	DeleteItem(ctx context.Context, actorID, patientID, entity, id string, version int64) error
+	RecordVital(ctx context.Context, actorID, patientID string, req *dto.RecordVitalReq) (*model.Vital, error)
+	ListVitals(ctx context.Context, actorID, patientID string, req *dto.ListVitalsReq) ([]*model.Vital, *paging.Pagination, error)
+	ListChanges(ctx context.Context, actorID, patientID string, req *dto.ListChangesReq) ([]*model.Change, *paging.Pagination, error)
+	ListGrants(ctx context.Context, patientID string) ([]*model.Grant, error)
+	GrantAccess(ctx context.Context, patientID string, req *dto.GrantAccessReq) (*model.Grant, error)
+	RevokeAccess(ctx context.Context, patientID, doctorID string) error
+	ListPatients(ctx context.Context, userID string) ([]*model.Grant, error)
+}
+
+// Doctors finds the doctors patients give access to
+type Doctors interface {
+	GetDoctorByID(ctx context.Context, id string) (*doctorModel.Doctor, error)
+	GetDoctorByUserID(ctx context.Context, userID string) (*doctorModel.Doctor, error)
+}
+
+type HealthService struct {
+	validator validation.Validation
+	repo      repository.IHealthRepository
+	doctors   Doctors
+}
+
+func NewHealthService(
+	validator validation.Validation,
+	repo repository.IHealthRepository,
+	doctors Doctors,
+) *HealthService {
+	return &HealthService{
+		validator: validator,
+		repo:      repo,
+		doctors:   doctors,
+	}
+}
+
+// GetProfile returns the current health data of the patient with its latest
+// vitals
+func (s *HealthService) GetProfile(ctx context.Context, actorID, patientID string) (*dto.HealthProfile, error) {
+	if err := s.authorize(ctx, actorID, patientID); err != nil {
+		return nil, err
+	}
+
+	res := dto.HealthProfile{PatientID: patientID}
+	profile, err := s.repo.GetProfile(ctx, patientID)
+	if err == nil {
+		res.BloodType = profile.BloodType
+		res.Version = profile.Version
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		logger.Errorf("GetProfile fail, id: %s, error: %s", patientID, err)
+		return nil, err
+	}
+
+	var allergies []*model.Allergy
+	var conditions []*model.Condition
+	var medications []*model.Medication
+	for _, items := range []interface{}{&allergies, &conditions, &medications} {
+		if err := s.repo.ListItems(ctx, patientID, items); err != nil {
+			logger.Errorf("GetProfile.ListItems fail, id: %s, error: %s", patientID, err)
+			return nil, err
+		}
+	}
+	utils.Copy(&res.Allergies, &allergies)
+	utils.Copy(&res.Conditions, &conditions)
+	utils.Copy(&res.Medications, &medications)
+
+	vital, err := s.repo.LatestVital(ctx, patientID)
+	if err == nil {
+		res.LatestVital = &dto.Vital{}
+		utils.Copy(res.LatestVital, vital)
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		logger.Errorf("GetProfile.LatestVital fail, id: %s, error: %s", patientID, err)
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+func (s *HealthService) SetBloodType(ctx context.Context, actorID, patientID string, req *dto.SetBloodTypeReq) (*model.Profile, error) {
+	if err := s.validator.ValidateStruct(req); err != nil {
+		return nil, err
+	}
+	if err := s.authorize(ctx, actorID, patientID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	action := model.ActionUpdate
+	before := ""
+	profile, err := s.repo.GetProfile(ctx, patientID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// the blood type was never set, version 0 of the profile
+		if req.Version != 0 {
+			return nil, dbs.ErrStaleVersion
+		}
+		action = model.ActionCreate
+		profile = &model.Profile{PatientID: patientID, CreatedAt: now}
+	} else if err != nil {
+		logger.Errorf("SetBloodType.GetProfile fail, id: %s, error: %s", patientID, err)
+		return nil, err
+	} else {
+		before = toJSON(profile)
+		profile.Version = req.Version
+	}
+
+	profile.BloodType = req.BloodType
+	profile.UpdatedAt = now
+	change := newChange(actorID, patientID, model.EntityProfile, patientID, action, before, toJSON(profile))
+	if err := s.repo.Save(ctx, profile, change); err != nil {
+		logger.Errorf("SetBloodType.Save fail, id: %s, error: %s", patientID, err)
+		return nil, err
+	}
+
+	return profile, nil
+}
+
+// SaveAllergy adds an allergy when id is empty, else updates it
+func (s *HealthService) SaveAllergy(ctx context.Context, actorID, patientID, id string, req *dto.SaveAllergyReq) (*model.Allergy, error) {
+	if err := s.validator.ValidateStruct(req); err != nil {
+		return nil, err
+	}
+
+	allergy := &model.Allergy{}
+	err := s.saveItem(ctx, actorID, patientID, model.EntityAllergy, id, allergy, func() string {
+		if allergy.ID == "" {
+			allergy.BeforeCreate()
+			allergy.PatientID = patientID
+		} else {
+			allergy.Version = req.Version
+		}
+		allergy.Substance = req.Substance
+		allergy.Reaction = req.Reaction
+		allergy.Severity = req.Severity
+		allergy.UpdatedAt = time.Now()
+		return allergy.ID
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return allergy, nil
+}
+
+// SaveCondition adds a condition when id is empty, else updates it
+func (s *HealthService) SaveCondition(ctx context.Context, actorID, patientID, id string, req *dto.SaveConditionReq) (*model.Condition, error) {
+	if err := s.validator.ValidateStruct(req); err != nil {
+		return nil, err
+	}
+
+	condition := &model.Condition{}
+	err := s.saveItem(ctx, actorID, patientID, model.EntityCondition, id, condition, func() string {
+		if condition.ID == "" {
+			condition.BeforeCreate()
+			condition.PatientID = patientID
+		} else {
+			condition.Version = req.Version
+		}
+		condition.Name = req.Name
+		condition.DiagnosedAt = req.DiagnosedAt
+		condition.Status = req.Status
+		if condition.Status == "" {
+			condition.Status = "active"
+		}
+		condition.Notes = req.Notes
+		condition.UpdatedAt = time.Now()
+		return condition.ID
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return condition, nil
+}
+
+// SaveMedication adds a medication when id is empty, else updates it
+func (s *HealthService) SaveMedication(ctx context.Context, actorID, patientID, id string, req *dto.SaveMedicationReq) (*model.Medication, error) {
+	if err := s.validator.ValidateStruct(req); err != nil {
+		return nil, err
+	}
+	if req.StartedAt != nil && req.EndedAt != nil && req.EndedAt.Before(*req.StartedAt) {
+		return nil, ErrInvalidPeriod
+	}
+
+	medication := &model.Medication{}
+	err := s.saveItem(ctx, actorID, patientID, model.EntityMedication, id, medication, func() string {
+		if medication.ID == "" {
+			medication.BeforeCreate()
+			medication.PatientID = patientID
+		} else {
+			medication.Version = req.Version
+		}
+		medication.Name = req.Name
+		medication.Dosage = req.Dosage
+		medication.Frequency = req.Frequency
+		medication.StartedAt = req.StartedAt
+		medication.EndedAt = req.EndedAt
+		medication.UpdatedAt = time.Now()
+		return medication.ID
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return medication, nil
+}
+
+// DeleteItem deletes an allergy, condition or medication still at version
+func (s *HealthService) DeleteItem(ctx context.Context, actorID, patientID, entity, id string, version int64) error {
+	var item interface{}
+	switch entity {
+	case model.EntityAllergy:
+		item = &model.Allergy{}
+	case model.EntityCondition:
+		item = &model.Condition{}
+	case model.EntityMedication:
+		item = &model.Medication{}
+	default:
+		return ErrUnknownEntity
+	}
+
+	if err := s.authorize(ctx, actorID, patientID); err != nil {
+		return err
+	}
+	if err := s.repo.GetItem(ctx, patientID, id, item); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrItemNotFound
+		}
+		logger.Errorf("DeleteItem.GetItem fail, id: %s, error: %s", id, err)
+		return err
+	}
+
+	change := newChange(actorID, patientID, entity, id, model.ActionDelete, toJSON(item), "")
+	if err := s.repo.Delete(ctx, item, version, change); err != nil {
+		logger.Errorf("DeleteItem fail, id: %s, error: %s", id, err)
+		return err
+	}
+	return nil
+}
+
+// RecordVital adds a measurement to the vitals history
+func (s *HealthService) RecordVital(ctx context.Context, actorID, patientID string, req *dto.RecordVitalReq) (*model.Vital, error) {
+	if err := s.validator.ValidateStruct(req); err != nil {
+		return nil, err
+	}
+	if req.HeightCm == nil && req.WeightKg == nil && req.Systolic == nil && req.Diastolic == nil &&
+		req.HeartRate == nil && req.TemperatureC == nil && req.OxygenSaturation == nil {
+		return nil, ErrEmptyVital
+	}
+	if err := s.authorize(ctx, actorID, patientID); err != nil {
+		return nil, err
+	}
+
+	var vital model.Vital
+	utils.Copy(&vital, req)
+	if req.RecordedAt != nil {
+		vital.RecordedAt = *req.RecordedAt
+	}
+	vital.BeforeCreate()
+	vital.PatientID = patientID
+	vital.RecordedBy = actorID
+
+	change := newChange(actorID, patientID, model.EntityVital, vital.ID, model.ActionCreate, "", toJSON(&vital))
+	if err := s.repo.Save(ctx, &vital, change); err != nil {
+		logger.Errorf("RecordVital fail, id: %s, error: %s", patientID, err)
+		return nil, err
+	}
+
+	return &vital, nil
+}
+
+func (s *HealthService) ListVitals(ctx context.Context, actorID, patientID string, req *dto.ListVitalsReq) ([]*model.Vital, *paging.Pagination, error) {
+	if err := s.authorize(ctx, actorID, patientID); err != nil {
+		return nil, nil, err
+	}
+	return s.repo.ListVitals(ctx, patientID, req)
+}
+
+// ListChanges returns the history of the health profile, newest first
+func (s *HealthService) ListChanges(ctx context.Context, actorID, patientID string, req *dto.ListChangesReq) ([]*model.Change, *paging.Pagination, error) {
+	if err := s.authorize(ctx, actorID, patientID); err != nil {
+		return nil, nil, err
+	}
+	return s.repo.ListChanges(ctx, patientID, req)
+}
+
+func (s *HealthService) ListGrants(ctx context.Context, patientID string) ([]*model.Grant, error) {
+	return s.repo.ListGrants(ctx, patientID)
+}
+
+// GrantAccess lets a doctor read and edit the health profile of the patient
+func (s *HealthService) GrantAccess(ctx context.Context, patientID string, req *dto.GrantAccessReq) (*model.Grant, error) {
+	if err := s.validator.ValidateStruct(req); err != nil {
+		return nil, err
+	}
+
+	doctor, err := s.doctors.GetDoctorByID(ctx, req.DoctorID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrDoctorNotFound
+	}
+	if err != nil {
+		logger.Errorf("GrantAccess.GetDoctorByID fail, id: %s, error: %s", req.DoctorID, err)
+		return nil, err
+	}
+
+	grant := &model.Grant{PatientID: patientID, DoctorID: doctor.ID, CreatedAt: time.Now()}
+	change := newChange(patientID, patientID, model.EntityGrant, doctor.ID, model.ActionCreate, "", toJSON(grant))
+	created, err := s.repo.CreateGrant(ctx, grant, change)
+	if err != nil {
+		logger.Errorf("GrantAccess fail, id: %s, error: %s", req.DoctorID, err)
+		return nil, err
+	}
+	if !created {
+		return nil, ErrAlreadyGranted
+	}
+
+	return grant, nil
+}
+
+// RevokeAccess removes the access of a doctor, its past changes stay in the
+// history
+func (s *HealthService) RevokeAccess(ctx context.Context, patientID, doctorID string) error {
+	grant := &model.Grant{PatientID: patientID, DoctorID: doctorID}
+	change := newChange(patientID, patientID, model.EntityGrant, doctorID, model.ActionDelete, toJSON(grant), "")
+	deleted, err := s.repo.DeleteGrant(ctx, grant, change)
+	if err != nil {
+		logger.Errorf("RevokeAccess fail, id: %s, error: %s", doctorID, err)
+		return err
+	}
+	if !deleted {
+		return ErrGrantNotFound
+	}
+	return nil
+}
+
+// ListPatients returns the grants of the patients who gave the doctor profile
+// of userID access
+func (s *HealthService) ListPatients(ctx context.Context, userID string) ([]*model.Grant, error) {
+	doctor, err := s.doctors.GetDoctorByUserID(ctx, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrDoctorNotFound
+	}
+	if err != nil {
+		logger.Errorf("ListPatients.GetDoctorByUserID fail, id: %s, error: %s", userID, err)
+		return nil, err
+	}
+
+	return s.repo.ListGrantsOfDoctor(ctx, doctor.ID)
+}
+
+// authorize lets the patient itself and the doctors it gave access, as long
+// as they are verified with a current license
+func (s *HealthService) authorize(ctx context.Context, actorID, patientID string) error {
+	if actorID == "" || patientID == "" {
+		return ErrAccessDenied
+	}
+	if actorID == patientID {
+		return nil
+	}
+
+	doctor, err := s.doctors.GetDoctorByUserID(ctx, actorID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrAccessDenied
+	}
+	if err != nil {
+		logger.Errorf("authorize.GetDoctorByUserID fail, id: %s, error: %s", actorID, err)
+		return err
+	}
+	if !doctor.Bookable() {
+		return ErrAccessDenied
+	}
+
+	granted, err := s.repo.HasGrant(ctx, patientID, doctor.ID)
+	if err != nil {
+		logger.Errorf("authorize.HasGrant fail, id: %s, error: %s", patientID, err)
+		return err
+	}
+	if !granted {
+		return ErrAccessDenied
+	}
+	return nil
+}
+
+// saveItem loads item id of the patient, or creates it when id is empty,
+// applies the request with apply and saves it with the change
+func (s *HealthService) saveItem(ctx context.Context, actorID, patientID, entity, id string, item interface{}, apply func() string) error {
+	if err := s.authorize(ctx, actorID, patientID); err != nil {
+		return err
+	}
+
+	action := model.ActionCreate
+	before := ""
+	if id != "" {
+		if err := s.repo.GetItem(ctx, patientID, id, item); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrItemNotFound
+			}
+			logger.Errorf("saveItem.GetItem fail, id: %s, error: %s", id, err)
+			return err
+		}
+		action = model.ActionUpdate
+		before = toJSON(item)
+	}
+
+	id = apply()
+	change := newChange(actorID, patientID, entity, id, action, before, toJSON(item))
+	if err := s.repo.Save(ctx, item, change); err != nil {
+		logger.Errorf("saveItem.Save fail, id: %s, error: %s", id, err)
+		return err
+	}
+	return nil
+}
+
+func newChange(actorID, patientID, entity, entityID, action, before, after string) *model.Change {
+	change := &model.Change{
+		PatientID: patientID,
+		ActorID:   actorID,
+		Entity:    entity,
+		EntityID:  entityID,
+		Action:    action,
+		Before:    before,
+		After:     after,
+	}
+	change.BeforeCreate()
+	return change
+}
+
+func toJSON(value interface{}) string {
+	data, _ := json.Marshal(value)
+	return string(data)
+}
@@ -0,0 +1,282 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/quangdangfit/gocommon/logger"
+	"github.com/quangdangfit/gocommon/validation"
+	"gorm.io/gorm"
+
+	doctorModel "main/internal/doctor/model"
+	"main/internal/health/dto"
+	"main/internal/health/model"
+	"main/internal/health/repository"
+	"main/pkg/config"
+	"main/pkg/dbs"
+	"main/pkg/paging"
+)
+
+func TestMain(m *testing.M) {
+	logger.Initialize(config.ProductionEnv)
+	os.Exit(m.Run())
+}
+
+// healthRepo keeps the allergies, grants and history in memory, recording
+// the changes with the writes like the transactions of repository.HealthRepo
+type healthRepo struct {
+	repository.IHealthRepository
+	allergies map[string]model.Allergy
+	grants    map[[2]string]bool
+	changes   []*model.Change
+}
+
+func (r *healthRepo) GetItem(ctx context.Context, patientID, id string, item interface{}) error {
+	allergy, ok := r.allergies[id]
+	if !ok || allergy.PatientID != patientID {
+		return gorm.ErrRecordNotFound
+	}
+	*item.(*model.Allergy) = allergy
+	return nil
+}
+
+func (r *healthRepo) Save(ctx context.Context, item interface{}, change *model.Change) error {
+	allergy := item.(*model.Allergy)
+	if current, ok := r.allergies[allergy.ID]; ok {
+		if allergy.Version != dbs.AnyVersion && current.Version != allergy.Version {
+			return dbs.ErrStaleVersion
+		}
+		allergy.Version = current.Version + 1
+	} else {
+		allergy.Version = 1
+	}
+	r.allergies[allergy.ID] = *allergy
+	r.changes = append(r.changes, change)
+	return nil
+}
+
+func (r *healthRepo) Delete(ctx context.Context, item interface{}, version int64, change *model.Change) error {
+	allergy := item.(*model.Allergy)
+	if version != dbs.AnyVersion && allergy.Version != version {
+		return dbs.ErrStaleVersion
+	}
+	delete(r.allergies, allergy.ID)
+	r.changes = append(r.changes, change)
+	return nil
+}
+
+func (r *healthRepo) ListChanges(ctx context.Context, patientID string, req *dto.ListChangesReq) ([]*model.Change, *paging.Pagination, error) {
+	var changes []*model.Change
+	for i := len(r.changes) - 1; i >= 0; i-- {
+		if r.changes[i].PatientID == patientID {
+			changes = append(changes, r.changes[i])
+		}
+	}
+	return changes, paging.New(1, int64(len(changes)), int64(len(changes))), nil
+}
+
+func (r *healthRepo) HasGrant(ctx context.Context, patientID, doctorID string) (bool, error) {
+	return r.grants[[2]string{patientID, doctorID}], nil
+}
+
+func (r *healthRepo) CreateGrant(ctx context.Context, grant *model.Grant, change *model.Change) (bool, error) {
+	key := [2]string{grant.PatientID, grant.DoctorID}
+	if r.grants[key] {
+		return false, nil
+	}
+	r.grants[key] = true
+	r.changes = append(r.changes, change)
+	return true, nil
+}
+
+func (r *healthRepo) DeleteGrant(ctx context.Context, grant *model.Grant, change *model.Change) (bool, error) {
+	key := [2]string{grant.PatientID, grant.DoctorID}
+	if !r.grants[key] {
+		return false, nil
+	}
+	delete(r.grants, key)
+	r.changes = append(r.changes, change)
+	return true, nil
+}
+
+// doctors are found by id and by the id of their user
+type doctors map[string]*doctorModel.Doctor
+
+func (d doctors) GetDoctorByID(ctx context.Context, id string) (*doctorModel.Doctor, error) {
+	for _, doctor := range d {
+		if doctor.ID == id {
+			return doctor, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (d doctors) GetDoctorByUserID(ctx context.Context, userID string) (*doctorModel.Doctor, error) {
+	if doctor, ok := d[userID]; ok {
+		return doctor, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func newHealthService(t *testing.T) (*HealthService, *healthRepo) {
+	t.Helper()
+	valid := time.Now().AddDate(1, 0, 0)
+	expired := time.Now().Add(-time.Hour)
+	repo := &healthRepo{allergies: map[string]model.Allergy{}, grants: map[[2]string]bool{}}
+	return NewHealthService(validation.New(), repo, doctors{
+		"verified-user":  {ID: "verified", IDUser: "verified-user", Status: doctorModel.DoctorVerified, LicenseExpiresAt: &valid},
+		"expired-user":   {ID: "expired", IDUser: "expired-user", Status: doctorModel.DoctorVerified, LicenseExpiresAt: &expired},
+		"suspended-user": {ID: "suspended", IDUser: "suspended-user", Status: doctorModel.DoctorSuspended, LicenseExpiresAt: &expired},
+		"rejected-user":  {ID: "rejected", IDUser: "rejected-user", Status: doctorModel.DoctorRejected},
+		"pending-user":   {ID: "pending", IDUser: "pending-user", Status: doctorModel.DoctorPending},
+	}), repo
+}
+
+func TestAuthorize(t *testing.T) {
+	svc, repo := newHealthService(t)
+	ctx := context.Background()
+	for _, doctorID := range []string{"verified", "expired", "suspended", "rejected", "pending"} {
+		repo.grants[[2]string{"patient", doctorID}] = true
+	}
+
+	tests := []struct {
+		name      string
+		actorID   string
+		patientID string
+		want      error
+	}{
+		{"patient itself", "patient", "patient", nil},
+		{"verified doctor with a grant", "verified-user", "patient", nil},
+		{"verified doctor without grant", "verified-user", "other", ErrAccessDenied},
+		{"license expired", "expired-user", "patient", ErrAccessDenied},
+		{"suspended doctor", "suspended-user", "patient", ErrAccessDenied},
+		{"rejected doctor", "rejected-user", "patient", ErrAccessDenied},
+		{"pending doctor", "pending-user", "patient", ErrAccessDenied},
+		{"other patient", "other", "patient", ErrAccessDenied},
+		{"no actor", "", "patient", ErrAccessDenied},
+		{"no patient", "patient", "", ErrAccessDenied},
+	}
+	for _, tt := range tests {
+		if err := svc.authorize(ctx, tt.actorID, tt.patientID); !errors.Is(err, tt.want) {
+			t.Errorf("%s: authorize = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestGrantAccess(t *testing.T) {
+	svc, repo := newHealthService(t)
+	ctx := context.Background()
+
+	if _, err := svc.GrantAccess(ctx, "patient", &dto.GrantAccessReq{DoctorID: "unknown"}); !errors.Is(err, ErrDoctorNotFound) {
+		t.Errorf("grant to unknown = %v, want ErrDoctorNotFound", err)
+	}
+	if _, err := svc.SaveAllergy(ctx, "verified-user", "patient", "", &dto.SaveAllergyReq{Substance: "Penicillin"}); !errors.Is(err, ErrAccessDenied) {
+		t.Errorf("save before grant = %v, want ErrAccessDenied", err)
+	}
+
+	grant, err := svc.GrantAccess(ctx, "patient", &dto.GrantAccessReq{DoctorID: "verified"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if grant.PatientID != "patient" || grant.DoctorID != "verified" {
+		t.Errorf("grant = %+v", grant)
+	}
+	if _, err := svc.GrantAccess(ctx, "patient", &dto.GrantAccessReq{DoctorID: "verified"}); !errors.Is(err, ErrAlreadyGranted) {
+		t.Errorf("second grant = %v, want ErrAlreadyGranted", err)
+	}
+	if _, err := svc.SaveAllergy(ctx, "verified-user", "patient", "", &dto.SaveAllergyReq{Substance: "Penicillin"}); err != nil {
+		t.Errorf("save after grant = %v", err)
+	}
+
+	if err := svc.RevokeAccess(ctx, "patient", "verified"); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.RevokeAccess(ctx, "patient", "verified"); !errors.Is(err, ErrGrantNotFound) {
+		t.Errorf("second revoke = %v, want ErrGrantNotFound", err)
+	}
+	if _, _, err := svc.ListChanges(ctx, "verified-user", "patient", &dto.ListChangesReq{}); !errors.Is(err, ErrAccessDenied) {
+		t.Errorf("history after revoke = %v, want ErrAccessDenied", err)
+	}
+
+	// the grant and its revocation are in the history, by the patient
+	var grants []*model.Change
+	for _, change := range repo.changes {
+		if change.Entity == model.EntityGrant {
+			grants = append(grants, change)
+		}
+	}
+	if len(grants) != 2 || grants[0].Action != model.ActionCreate || grants[1].Action != model.ActionDelete ||
+		grants[0].ActorID != "patient" || grants[1].EntityID != "verified" {
+		t.Errorf("grant changes = %+v", grants)
+	}
+}
+
+func TestChangeHistory(t *testing.T) {
+	svc, repo := newHealthService(t)
+	ctx := context.Background()
+	repo.grants[[2]string{"patient", "verified"}] = true
+
+	allergy, err := svc.SaveAllergy(ctx, "verified-user", "patient", "", &dto.SaveAllergyReq{Substance: "Penicillin", Severity: "mild"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.SaveAllergy(ctx, "patient", "patient", allergy.ID, &dto.SaveAllergyReq{Substance: "Penicillin", Severity: "severe", Version: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.SaveAllergy(ctx, "patient", "patient", allergy.ID, &dto.SaveAllergyReq{Substance: "Penicillin", Version: 1}); !errors.Is(err, dbs.ErrStaleVersion) {
+		t.Errorf("stale save = %v, want ErrStaleVersion", err)
+	}
+	if _, err := svc.SaveAllergy(ctx, "other", "other", allergy.ID, &dto.SaveAllergyReq{Substance: "Penicillin"}); !errors.Is(err, ErrItemNotFound) {
+		t.Errorf("save of another patient's allergy = %v, want ErrItemNotFound", err)
+	}
+	if err := svc.DeleteItem(ctx, "verified-user", "patient", model.EntityAllergy, allergy.ID, 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.DeleteItem(ctx, "verified-user", "patient", "diagnosis", allergy.ID, 2); !errors.Is(err, ErrUnknownEntity) {
+		t.Errorf("delete of unknown entity = %v, want ErrUnknownEntity", err)
+	}
+
+	changes, _, err := svc.ListChanges(ctx, "patient", "patient", &dto.ListChangesReq{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		action, actor, before, after string
+	}{
+		{model.ActionDelete, "verified-user", "severe", ""},
+		{model.ActionUpdate, "patient", "mild", "severe"},
+		{model.ActionCreate, "verified-user", "", "mild"},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("%d changes, want %d", len(changes), len(want))
+	}
+	for i, w := range want {
+		change := changes[i]
+		if change.Action != w.action || change.ActorID != w.actor || change.Entity != model.EntityAllergy || change.EntityID != allergy.ID {
+			t.Errorf("change %d = %+v", i, change)
+		}
+		if got := severity(t, change.Before); got != w.before {
+			t.Errorf("change %d before severity %q, want %q", i, got, w.before)
+		}
+		if got := severity(t, change.After); got != w.after {
+			t.Errorf("change %d after severity %q, want %q", i, got, w.after)
+		}
+	}
+}
+
+// severity is the severity of the allergy json of a change
+func severity(t *testing.T, data string) string {
+	t.Helper()
+	if data == "" {
+		return ""
+	}
+	var allergy model.Allergy
+	if err := json.Unmarshal([]byte(data), &allergy); err != nil {
+		t.Fatal(err)
+	}
+	return allergy.Severity
+}
@@ -0,0 +1,66 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"main/pkg/paging"
+)
+
+// swagger:model Notification
+type Notification struct {
+	ID    string `json:"id"`
+	Type  string `json:"type"`
+	Title string `json:"title"`
+	Body  string `json:"body"`
+	// Payload is json, for the client to act on the notification
+	Payload   json.RawMessage `json:"payload" swaggertype:"object"`
+	ReadAt    *time.Time      `json:"read_at"`
+	ExpiresAt time.Time       `json:"expires_at"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+type ListNotificationsReq struct {
+	Unread bool  `json:"unread,omitempty" form:"unread"`
+	Page   int64 `json:"page,omitempty" form:"page"`
+	Limit  int64 `json:"limit,omitempty" form:"limit"`
+}
+
+// swagger:model ListNotificationsRes
+type ListNotificationsRes struct {
+	Notifications []*Notification    `json:"notifications"`
+	Pagination    *paging.Pagination `json:"pagination"`
+}
+
+// swagger:model UnreadCountRes
+type UnreadCountRes struct {
+	Unread int64 `json:"unread"`
+}
+
+// swagger:model MarkAllReadRes
+type MarkAllReadRes struct {
+	Read int64 `json:"read"`
+}
+
+// swagger:model NotificationPreference
+type Preference struct {
+	// example: "verification_status"
+	Type  string `json:"type"`
+	Email bool   `json:"email"`
+	SMS   bool   `json:"sms"`
+	InApp bool   `json:"in_app"`
+	// Required types are sent whatever the preference
+	Required bool `json:"required"`
+}
+
+// swagger:model ListPreferencesRes
+type ListPreferencesRes struct {
+	Preferences []*Preference `json:"preferences"`
+}
+
+// swagger:model UpdatePreferenceReq
+type UpdatePreferenceReq struct {
+	Email bool `json:"email"`
+	SMS   bool `json:"sms"`
+	InApp bool `json:"in_app"`
+}
@@ -0,0 +1,48 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Notification is an in-app notification of a user, it is deleted once
+// expired
+type Notification struct {
+	ID        string    `json:"id" gorm:"unique;not null;index;primary_key"`
+	CreatedAt time.Time `json:"created_at" gorm:"index:idx_notification_user,priority:2"`
+	UserID    string    `json:"user_id" gorm:"not null;index:idx_notification_user,priority:1"`
+	Type      string    `json:"type" gorm:"not null"`
+	Title     string    `json:"title" gorm:"not null"`
+	Body      string    `json:"body" gorm:"type:text"`
+	// Payload is json, for the client to act on the notification
+	Payload   json.RawMessage `json:"payload" gorm:"type:jsonb"`
+	ReadAt    *time.Time      `json:"read_at"`
+	ExpiresAt time.Time       `json:"expires_at" gorm:"not null;index"`
+}
+
+func (Notification) TableName() string {
+	return "notifications"
+}
+
+func (m *Notification) BeforeCreate() error {
+	m.ID = uuid.New().String()
+	m.CreatedAt = time.Now()
+	return nil
+}
+
+// Preference is the channels a user opted in for a type of notifications,
+// the default of the type applies without one
+type Preference struct {
+	UserID    string    `json:"user_id" gorm:"primary_key"`
+	Type      string    `json:"type" gorm:"primary_key"`
+	Email     bool      `json:"email" gorm:"not null"`
+	SMS       bool      `json:"sms" gorm:"not null"`
+	InApp     bool      `json:"in_app" gorm:"not null"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (Preference) TableName() string {
+	return "notification_preferences"
+}
@@ -0,0 +1,186 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/quangdangfit/gocommon/logger"
+
+	"main/internal/notification/dto"
+	"main/internal/notification/service"
+	"main/pkg/response"
+	"main/pkg/utils"
+)
+
+type NotificationHandler struct {
+	service service.INotificationService
+}
+
+func NewNotificationHandler(service service.INotificationService) *NotificationHandler {
+	return &NotificationHandler{service: service}
+}
+
+// ListNotifications godoc
+//
+//	@Summary	List the notifications of the user not expired yet, the latest first
+//	@Tags		Notifications
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		unread	query		bool	false	"Only the unread notifications"
+//	@Param		page	query		int		false	"Page"
+//	@Param		limit	query		int		false	"Limit"
+//	@Success	200		{object}	dto.ListNotificationsRes
+//	@Router		/notifications [get]
+func (h *NotificationHandler) ListNotifications(c *gin.Context) {
+	var req dto.ListNotificationsReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.Error("Failed to get query params", err)
+		response.Error(c, http.StatusBadRequest, err, "Invalid parameters")
+		return
+	}
+
+	notifications, pagination, err := h.service.List(c, c.GetString("userId"), &req)
+	if err != nil {
+		logger.Error("Failed to list notifications ", err)
+		response.Error(c, http.StatusInternalServerError, err, "Something went wrong")
+		return
+	}
+
+	var res dto.ListNotificationsRes
+	utils.Copy(&res.Notifications, &notifications)
+	res.Pagination = pagination
+	response.JSON(c, http.StatusOK, res)
+}
+
+// UnreadCount godoc
+//
+//	@Summary	Count the unread notifications of the user
+//	@Tags		Notifications
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Success	200	{object}	dto.UnreadCountRes
+//	@Router		/notifications/unread-count [get]
+func (h *NotificationHandler) UnreadCount(c *gin.Context) {
+	unread, err := h.service.UnreadCount(c, c.GetString("userId"))
+	if err != nil {
+		logger.Error("Failed to count unread notifications ", err)
+		response.Error(c, http.StatusInternalServerError, err, "Something went wrong")
+		return
+	}
+
+	response.JSON(c, http.StatusOK, dto.UnreadCountRes{Unread: unread})
+}
+
+// MarkRead godoc
+//
+//	@Summary	Mark a notification read
+//	@Tags		Notifications
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		id	path		string	true	"Notification ID"
+//	@Success	200	{object}	nil
+//	@Router		/notifications/{id}/read [post]
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	if err := h.service.MarkRead(c, c.GetString("userId"), c.Param("id")); err != nil {
+		notificationError(c, err, "Failed to mark notification read ")
+		return
+	}
+
+	response.JSON(c, http.StatusOK, nil)
+}
+
+// MarkAllRead godoc
+//
+//	@Summary	Mark all the notifications of the user read
+//	@Tags		Notifications
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Success	200	{object}	dto.MarkAllReadRes
+//	@Router		/notifications/read-all [post]
+func (h *NotificationHandler) MarkAllRead(c *gin.Context) {
+	read, err := h.service.MarkAllRead(c, c.GetString("userId"))
+	if err != nil {
+		logger.Error("Failed to mark notifications read ", err)
+		response.Error(c, http.StatusInternalServerError, err, "Something went wrong")
+		return
+	}
+
+	response.JSON(c, http.StatusOK, dto.MarkAllReadRes{Read: read})
+}
+
+// DeleteNotification godoc
+//
+//	@Summary	Delete a notification
+//	@Tags		Notifications
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		id	path		string	true	"Notification ID"
+//	@Success	200	{object}	nil
+//	@Router		/notifications/{id} [delete]
+func (h *NotificationHandler) DeleteNotification(c *gin.Context) {
+	if err := h.service.Delete(c, c.GetString("userId"), c.Param("id")); err != nil {
+		notificationError(c, err, "Failed to delete notification ")
+		return
+	}
+
+	response.JSON(c, http.StatusOK, nil)
+}
+
+// ListPreferences godoc
+//
+//	@Summary	List the channels the user receives every type of notifications on
+//	@Tags		Notifications
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Success	200	{object}	dto.ListPreferencesRes
+//	@Router		/notifications/preferences [get]
+func (h *NotificationHandler) ListPreferences(c *gin.Context) {
+	preferences, err := h.service.ListPreferences(c, c.GetString("userId"))
+	if err != nil {
+		logger.Error("Failed to list notification preferences ", err)
+		response.Error(c, http.StatusInternalServerError, err, "Something went wrong")
+		return
+	}
+
+	response.JSON(c, http.StatusOK, dto.ListPreferencesRes{Preferences: preferences})
+}
+
+// UpdatePreference godoc
+//
+//	@Summary	Choose the channels the user receives a type of notifications on, the required types cannot be changed
+//	@Tags		Notifications
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		type	path		string					true	"Notification type, such as verification_status"
+//	@Param		_		body		dto.UpdatePreferenceReq	true	"Body"
+//	@Success	200		{object}	dto.Preference
+//	@Router		/notifications/preferences/{type} [put]
+func (h *NotificationHandler) UpdatePreference(c *gin.Context) {
+	var req dto.UpdatePreferenceReq
+	if err := c.ShouldBindJSON(&req); c.Request.Body == nil || err != nil {
+		logger.Error("Failed to get body", err)
+		response.Error(c, http.StatusBadRequest, err, "Invalid parameters")
+		return
+	}
+
+	preference, err := h.service.UpdatePreference(c, c.GetString("userId"), c.Param("type"), &req)
+	if err != nil {
+		notificationError(c, err, "Failed to update notification preference ")
+		return
+	}
+
+	response.JSON(c, http.StatusOK, preference)
+}
+
+func notificationError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrNotificationNotFound):
+		response.Error(c, http.StatusNotFound, err, err.Error())
+	case errors.Is(err, service.ErrUnknownType), errors.Is(err, service.ErrRequiredType):
+		response.Error(c, http.StatusBadRequest, err, err.Error())
+	default:
+		logger.Error(message, err)
+		response.Error(c, http.StatusBadRequest, err, err.Error())
+	}
+}
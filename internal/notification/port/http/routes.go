@@ -0,0 +1,25 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"main/internal/notification/service"
+	"main/pkg/middleware"
+)
+
+func Routes(r *gin.RouterGroup, notificationSvc service.INotificationService, auth *middleware.Authenticator) {
+	notificationHandler := NewNotificationHandler(notificationSvc)
+
+	authMiddleware := middleware.JWTAuth(auth)
+	notificationRoute := r.Group("/notifications", authMiddleware)
+	{
+		notificationRoute.GET("", notificationHandler.ListNotifications)
+		notificationRoute.GET("/unread-count", notificationHandler.UnreadCount)
+		notificationRoute.POST("/read-all", notificationHandler.MarkAllRead)
+		notificationRoute.POST("/:id/read", notificationHandler.MarkRead)
+		notificationRoute.DELETE("/:id", notificationHandler.DeleteNotification)
+		// the channels of every type, email, sms and in_app
+		notificationRoute.GET("/preferences", notificationHandler.ListPreferences)
+		notificationRoute.PUT("/preferences/:type", notificationHandler.UpdatePreference)
+	}
+}
@@ -0,0 +1,13 @@
+package subscriber
+
+import (
+	"context"
+
+	"main/internal/notification/service"
+	"main/pkg/events"
+)
+
+// Subscribe notifies the doctors of the review of their license
+func Subscribe(ctx context.Context, bus *events.Bus, notificationSvc *service.NotificationService) error {
+	return bus.Subscribe(ctx, "notifications", notificationSvc.NotifyEvent, events.DoctorUpdated)
+}
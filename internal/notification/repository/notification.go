@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"main/internal/notification/dto"
+	"main/internal/notification/model"
+	userModel "main/internal/user/model"
+	"main/pkg/config"
+	"main/pkg/dbs"
+	"main/pkg/paging"
+	"main/pkg/tenant"
+)
+
+//go:generate mockery --name=INotificationRepository
+type INotificationRepository interface {
+	Create(ctx context.Context, notification *model.Notification) error
+	List(ctx context.Context, userID string, req *dto.ListNotificationsReq, now time.Time) ([]*model.Notification, *paging.Pagination, error)
+	CountUnread(ctx context.Context, userID string, now time.Time) (int64, error)
+	MarkRead(ctx context.Context, userID, id string, at time.Time) (bool, error)
+	MarkAllRead(ctx context.Context, userID string, at time.Time) (int64, error)
+	Delete(ctx context.Context, userID, id string) (bool, error)
+	ListPreferences(ctx context.Context, userID string) ([]*model.Preference, error)
+	GetPreference(ctx context.Context, userID, typ string) (*model.Preference, error)
+	SavePreference(ctx context.Context, preference *model.Preference) error
+	GetContact(ctx context.Context, userID string) (*userModel.User, error)
+	Purge(ctx context.Context, before time.Time) (int64, error)
+}
+
+type NotificationRepo struct {
+	db dbs.IDatabase
+}
+
+func NewNotificationRepository(db dbs.IDatabase) *NotificationRepo {
+	return &NotificationRepo{db: db}
+}
+
+func (r *NotificationRepo) Create(ctx context.Context, notification *model.Notification) error {
+	return r.db.Create(ctx, notification)
+}
+
+// List returns the notifications of userID not expired by now, the latest
+// first
+func (r *NotificationRepo) List(ctx context.Context, userID string, req *dto.ListNotificationsReq, now time.Time) ([]*model.Notification, *paging.Pagination, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.DatabaseTimeout)
+	defer cancel()
+
+	query := []dbs.Query{
+		dbs.NewQuery("user_id = ?", userID),
+		dbs.NewQuery("expires_at > ?", now),
+	}
+	if req.Unread {
+		query = append(query, dbs.NewQuery("read_at IS NULL"))
+	}
+
+	var total int64
+	if err := r.db.Count(ctx, &model.Notification{}, &total, dbs.WithQuery(query...)); err != nil {
+		return nil, nil, err
+	}
+
+	pagination := paging.New(req.Page, req.Limit, total)
+
+	var notifications []*model.Notification
+	if err := r.db.Find(
+		ctx,
+		&notifications,
+		dbs.WithQuery(query...),
+		dbs.WithLimit(int(pagination.Limit)),
+		dbs.WithOffset(int(pagination.Skip)),
+		dbs.WithOrder("created_at DESC"),
+	); err != nil {
+		return nil, nil, err
+	}
+
+	return notifications, pagination, nil
+}
+
+func (r *NotificationRepo) CountUnread(ctx context.Context, userID string, now time.Time) (int64, error) {
+	var unread int64
+	err := r.db.Count(ctx, &model.Notification{}, &unread, dbs.WithQuery(
+		dbs.NewQuery("user_id = ?", userID),
+		dbs.NewQuery("expires_at > ?", now),
+		dbs.NewQuery("read_at IS NULL"),
+	))
+	return unread, err
+}
+
+// MarkRead marks the notification read at, it keeps the time it was first
+// read. It returns false when userID has no such notification.
+func (r *NotificationRepo) MarkRead(ctx context.Context, userID, id string, at time.Time) (bool, error) {
+	result := r.db.GetDB().WithContext(ctx).Model(&model.Notification{}).
+		Where("id = ? AND user_id = ?", id, userID).
+		Update("read_at", gorm.Expr("COALESCE(read_at, ?)", at))
+	return result.RowsAffected > 0, result.Error
+}
+
+// MarkAllRead marks the unread notifications of userID read at and returns
+// how many were
+func (r *NotificationRepo) MarkAllRead(ctx context.Context, userID string, at time.Time) (int64, error) {
+	result := r.db.GetDB().WithContext(ctx).Model(&model.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Update("read_at", at)
+	return result.RowsAffected, result.Error
+}
+
+func (r *NotificationRepo) Delete(ctx context.Context, userID, id string) (bool, error) {
+	result := r.db.GetDB().WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).Delete(&model.Notification{})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *NotificationRepo) ListPreferences(ctx context.Context, userID string) ([]*model.Preference, error) {
+	var preferences []*model.Preference
+	if err := r.db.GetDB().WithContext(ctx).Where("user_id = ?", userID).Find(&preferences).Error; err != nil {
+		return nil, err
+	}
+	return preferences, nil
+}
+
+// GetPreference returns nil without an error when userID has no preference
+// for typ
+func (r *NotificationRepo) GetPreference(ctx context.Context, userID, typ string) (*model.Preference, error) {
+	var preference model.Preference
+	err := r.db.GetDB().WithContext(ctx).Where("user_id = ? AND type = ?", userID, typ).First(&preference).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &preference, nil
+}
+
+func (r *NotificationRepo) SavePreference(ctx context.Context, preference *model.Preference) error {
+	return r.db.GetDB().WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "type"}},
+		DoUpdates: clause.AssignmentColumns([]string{"email", "sms", "in_app", "updated_at"}),
+	}).Create(preference).Error
+}
+
+// GetContact returns the user with its name, email and phone number,
+// whatever its clinic
+func (r *NotificationRepo) GetContact(ctx context.Context, userID string) (*userModel.User, error) {
+	var user userModel.User
+	err := r.db.GetDB().WithContext(tenant.Global(ctx)).
+		Select("id", "name", "email", "phone_number").
+		Where("id = ?", userID).First(&user).Error
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// Purge deletes the notifications expired before before
+func (r *NotificationRepo) Purge(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.GetDB().WithContext(ctx).Where("expires_at < ?", before).Delete(&model.Notification{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,336 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/quangdangfit/gocommon/logger"
+	"github.com/quangdangfit/gocommon/validation"
+
+	doctorModel "main/internal/doctor/model"
+	"main/internal/notification/dto"
+	"main/internal/notification/model"
+	"main/internal/notification/repository"
+	"main/pkg/events"
+	"main/pkg/notify"
+	"main/pkg/paging"
+	"main/pkg/realtime"
+	"main/pkg/utils"
+)
+
+var (
+	ErrNotificationNotFound = errors.New("notification not found")
+	ErrUnknownType          = errors.New("unknown notification type")
+	// ErrRequiredType is returned when changing the preference of a type
+	// that is always sent
+	ErrRequiredType = errors.New("notification type cannot be turned off")
+)
+
+// channels are tried in order, the in-app notification is kept even when the
+// email or SMS fails
+var channels = []string{notify.ChannelInApp, notify.ChannelEmail, notify.ChannelSMS}
+
+//go:generate mockery --name=INotificationService
+type INotificationService interface {
+	List(ctx context.Context, userID string, req *dto.ListNotificationsReq) ([]*model.Notification, *paging.Pagination, error)
+	UnreadCount(ctx context.Context, userID string) (int64, error)
+	MarkRead(ctx context.Context, userID, id string) error
+	MarkAllRead(ctx context.Context, userID string) (int64, error)
+	Delete(ctx context.Context, userID, id string) error
+	ListPreferences(ctx context.Context, userID string) ([]*dto.Preference, error)
+	UpdatePreference(ctx context.Context, userID, typ string, req *dto.UpdatePreferenceReq) (*dto.Preference, error)
+}
+
+type NotificationService struct {
+	validator validation.Validation
+	repo      repository.INotificationRepository
+	hub       *realtime.Hub
+	senders   notify.Senders
+	// ttl is how long the in-app notifications are kept by default
+	ttl time.Duration
+}
+
+func NewNotificationService(
+	validator validation.Validation,
+	repo repository.INotificationRepository,
+	hub *realtime.Hub,
+	senders notify.Senders,
+	ttl time.Duration,
+) *NotificationService {
+	return &NotificationService{
+		validator: validator,
+		repo:      repo,
+		hub:       hub,
+		senders:   senders,
+		ttl:       ttl,
+	}
+}
+
+// Notify sends msg on the channels the preference of its user has for its
+// type, among the channels of msg. Every channel is tried, the errors are
+// joined.
+func (s *NotificationService) Notify(ctx context.Context, msg *notify.Message) error {
+	preference, err := s.preference(ctx, msg.UserID, msg.Type)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, channel := range channels {
+		if !preference.Has(channel) || (len(msg.Channels) > 0 && !contains(msg.Channels, channel)) {
+			continue
+		}
+		if err := s.send(ctx, channel, msg); err != nil {
+			logger.Errorf("Notify fail, user: %s, type: %s, channel: %s, error: %s", msg.UserID, msg.Type, channel, err)
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// preference is the channels userID gets typ on, the default of the types
+// without a preference and the required types
+func (s *NotificationService) preference(ctx context.Context, userID, typ string) (notify.Preference, error) {
+	kind, ok := notify.Types[typ]
+	if !ok {
+		return notify.Preference{}, ErrUnknownType
+	}
+	if kind.Required {
+		return kind.Default, nil
+	}
+
+	preference, err := s.repo.GetPreference(ctx, userID, typ)
+	if err != nil {
+		logger.Errorf("Notify.GetPreference fail, user: %s, type: %s, error: %s", userID, typ, err)
+		return notify.Preference{}, err
+	}
+	if preference == nil {
+		return kind.Default, nil
+	}
+	return notify.Preference{Email: preference.Email, SMS: preference.SMS, InApp: preference.InApp}, nil
+}
+
+func (s *NotificationService) send(ctx context.Context, channel string, msg *notify.Message) error {
+	if channel == notify.ChannelInApp {
+		return s.store(ctx, msg)
+	}
+
+	if (channel == notify.ChannelEmail && msg.Email == "") || (channel == notify.ChannelSMS && msg.Phone == "") {
+		user, err := s.repo.GetContact(ctx, msg.UserID)
+		if err != nil {
+			return err
+		}
+		contact := *msg
+		contact.Name, contact.Email, contact.Phone = user.Name, user.Email, user.PhoneNumber
+		msg = &contact
+	}
+
+	switch channel {
+	case notify.ChannelEmail:
+		if msg.Email == "" {
+			return nil
+		}
+		html := msg.HTML
+		if html == "" {
+			html = msg.Body
+		}
+		return s.senders.Email(msg.Name, msg.Email, msg.Title, msg.Body, html)
+	case notify.ChannelSMS:
+		if msg.Phone == "" {
+			return nil
+		}
+		return s.senders.SMS(msg.Phone, msg.Body)
+	}
+	return fmt.Errorf("unknown notification channel %q", channel)
+}
+
+// store keeps the in-app notification and pushes it to the connected clients
+// of the user
+func (s *NotificationService) store(ctx context.Context, msg *notify.Message) error {
+	ttl := msg.TTL
+	if ttl == 0 {
+		ttl = s.ttl
+	}
+	notification := &model.Notification{
+		UserID: msg.UserID,
+		Type:   msg.Type,
+		Title:  msg.Title,
+		Body:   msg.Body,
+	}
+	if msg.Payload != nil {
+		payload, err := json.Marshal(msg.Payload)
+		if err != nil {
+			return err
+		}
+		notification.Payload = payload
+	}
+	notification.BeforeCreate()
+	notification.ExpiresAt = notification.CreatedAt.Add(ttl)
+	if err := s.repo.Create(ctx, notification); err != nil {
+		return err
+	}
+
+	var pushed dto.Notification
+	utils.Copy(&pushed, notification)
+	if err := s.hub.Send(ctx, msg.UserID, realtime.NewMessage(realtime.TypeNotification, &pushed)); err != nil {
+		logger.Errorf("Notify.Send fail, user: %s, notification: %s, error: %s", msg.UserID, notification.ID, err)
+	}
+	return nil
+}
+
+func (s *NotificationService) List(ctx context.Context, userID string, req *dto.ListNotificationsReq) ([]*model.Notification, *paging.Pagination, error) {
+	notifications, pagination, err := s.repo.List(ctx, userID, req, time.Now())
+	if err != nil {
+		logger.Errorf("List fail, user: %s, error: %s", userID, err)
+		return nil, nil, err
+	}
+	return notifications, pagination, nil
+}
+
+func (s *NotificationService) UnreadCount(ctx context.Context, userID string) (int64, error) {
+	return s.repo.CountUnread(ctx, userID, time.Now())
+}
+
+func (s *NotificationService) MarkRead(ctx context.Context, userID, id string) error {
+	found, err := s.repo.MarkRead(ctx, userID, id, time.Now())
+	if err != nil {
+		logger.Errorf("MarkRead fail, id: %s, error: %s", id, err)
+		return err
+	}
+	if !found {
+		return ErrNotificationNotFound
+	}
+	return nil
+}
+
+// MarkAllRead returns how many notifications were unread
+func (s *NotificationService) MarkAllRead(ctx context.Context, userID string) (int64, error) {
+	read, err := s.repo.MarkAllRead(ctx, userID, time.Now())
+	if err != nil {
+		logger.Errorf("MarkAllRead fail, user: %s, error: %s", userID, err)
+		return 0, err
+	}
+	return read, nil
+}
+
+func (s *NotificationService) Delete(ctx context.Context, userID, id string) error {
+	found, err := s.repo.Delete(ctx, userID, id)
+	if err != nil {
+		logger.Errorf("Delete fail, id: %s, error: %s", id, err)
+		return err
+	}
+	if !found {
+		return ErrNotificationNotFound
+	}
+	return nil
+}
+
+// ListPreferences returns the preference of userID for every type, the
+// default of the types it did not change
+func (s *NotificationService) ListPreferences(ctx context.Context, userID string) ([]*dto.Preference, error) {
+	saved, err := s.repo.ListPreferences(ctx, userID)
+	if err != nil {
+		logger.Errorf("ListPreferences fail, user: %s, error: %s", userID, err)
+		return nil, err
+	}
+	byType := make(map[string]*model.Preference, len(saved))
+	for _, preference := range saved {
+		byType[preference.Type] = preference
+	}
+
+	preferences := make([]*dto.Preference, 0, len(notify.Types))
+	for _, typ := range types() {
+		kind := notify.Types[typ]
+		preference := &dto.Preference{
+			Type:     typ,
+			Email:    kind.Default.Email,
+			SMS:      kind.Default.SMS,
+			InApp:    kind.Default.InApp,
+			Required: kind.Required,
+		}
+		if p, ok := byType[typ]; ok && !kind.Required {
+			preference.Email, preference.SMS, preference.InApp = p.Email, p.SMS, p.InApp
+		}
+		preferences = append(preferences, preference)
+	}
+	return preferences, nil
+}
+
+func (s *NotificationService) UpdatePreference(ctx context.Context, userID, typ string, req *dto.UpdatePreferenceReq) (*dto.Preference, error) {
+	if err := s.validator.ValidateStruct(req); err != nil {
+		return nil, err
+	}
+	kind, ok := notify.Types[typ]
+	if !ok {
+		return nil, ErrUnknownType
+	}
+	if kind.Required {
+		return nil, ErrRequiredType
+	}
+
+	preference := &model.Preference{
+		UserID:    userID,
+		Type:      typ,
+		Email:     req.Email,
+		SMS:       req.SMS,
+		InApp:     req.InApp,
+		UpdatedAt: time.Now(),
+	}
+	if err := s.repo.SavePreference(ctx, preference); err != nil {
+		logger.Errorf("UpdatePreference fail, user: %s, type: %s, error: %s", userID, typ, err)
+		return nil, err
+	}
+
+	return &dto.Preference{Type: typ, Email: req.Email, SMS: req.SMS, InApp: req.InApp}, nil
+}
+
+// NotifyEvent notifies the doctors whose license was reviewed, it is the
+// handler of the domain events. Failed channels are logged and not retried,
+// a redelivery would send the others again.
+func (s *NotificationService) NotifyEvent(ctx context.Context, event *events.Event) error {
+	var change events.Change
+	if err := event.Decode(&change); err != nil {
+		return err
+	}
+	if event.Name != events.DoctorUpdated || change.Action != events.ActionReviewed || change.UserID == "" {
+		return nil
+	}
+
+	title := "Your license was verified"
+	body := "Patients can now find and book you."
+	if change.Status != doctorModel.DoctorVerified {
+		title = "Your license verification was not approved"
+		body = "Please check the reason and submit your license again."
+	}
+	_ = s.Notify(ctx, &notify.Message{
+		UserID:  change.UserID,
+		Type:    notify.TypeVerificationStatus,
+		Title:   title,
+		Body:    body,
+		Payload: map[string]string{"doctor_id": event.SubjectID, "status": change.Status},
+	})
+	return nil
+}
+
+// types are the notification types in a stable order
+func types() []string {
+	names := make([]string, 0, len(notify.Types))
+	for typ := range notify.Types {
+		names = append(names, typ)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,268 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/quangdangfit/gocommon/logger"
+	"github.com/quangdangfit/gocommon/validation"
+
+	"main/internal/notification/dto"
+	"main/internal/notification/model"
+	"main/internal/notification/repository"
+	userModel "main/internal/user/model"
+	"main/pkg/config"
+	"main/pkg/notify"
+	"main/pkg/paging"
+	"main/pkg/realtime"
+)
+
+func TestMain(m *testing.M) {
+	logger.Initialize(config.ProductionEnv)
+	os.Exit(m.Run())
+}
+
+// notificationRepo keeps the notifications and preferences in memory, the
+// expired notifications are filtered out like the queries of
+// repository.NotificationRepo
+type notificationRepo struct {
+	repository.INotificationRepository
+	notifications []*model.Notification
+	preferences   map[[2]string]*model.Preference
+}
+
+func (r *notificationRepo) Create(ctx context.Context, notification *model.Notification) error {
+	copied := *notification
+	r.notifications = append(r.notifications, &copied)
+	return nil
+}
+
+func (r *notificationRepo) List(ctx context.Context, userID string, req *dto.ListNotificationsReq, now time.Time) ([]*model.Notification, *paging.Pagination, error) {
+	var notifications []*model.Notification
+	for i := len(r.notifications) - 1; i >= 0; i-- {
+		n := r.notifications[i]
+		if n.UserID == userID && n.ExpiresAt.After(now) && (!req.Unread || n.ReadAt == nil) {
+			notifications = append(notifications, n)
+		}
+	}
+	return notifications, paging.New(1, int64(len(notifications)), int64(len(notifications))), nil
+}
+
+func (r *notificationRepo) CountUnread(ctx context.Context, userID string, now time.Time) (int64, error) {
+	notifications, _, err := r.List(ctx, userID, &dto.ListNotificationsReq{Unread: true}, now)
+	return int64(len(notifications)), err
+}
+
+func (r *notificationRepo) ListPreferences(ctx context.Context, userID string) ([]*model.Preference, error) {
+	var preferences []*model.Preference
+	for key, preference := range r.preferences {
+		if key[0] == userID {
+			preferences = append(preferences, preference)
+		}
+	}
+	return preferences, nil
+}
+
+func (r *notificationRepo) GetPreference(ctx context.Context, userID, typ string) (*model.Preference, error) {
+	return r.preferences[[2]string{userID, typ}], nil
+}
+
+func (r *notificationRepo) SavePreference(ctx context.Context, preference *model.Preference) error {
+	r.preferences[[2]string{preference.UserID, preference.Type}] = preference
+	return nil
+}
+
+func (r *notificationRepo) GetContact(ctx context.Context, userID string) (*userModel.User, error) {
+	return &userModel.User{ID: userID, Name: "Ada", Email: "ada@example.com", PhoneNumber: "+33612345678"}, nil
+}
+
+// sent records the emails and SMS by recipient
+type sent struct {
+	emails []string
+	sms    []string
+	err    error
+}
+
+func (s *sent) senders() notify.Senders {
+	return notify.Senders{
+		Email: func(toName, toEmail, subject, plainTextContent, htmlContent string) error {
+			s.emails = append(s.emails, toEmail)
+			return s.err
+		},
+		SMS: func(toPhoneNumber, message string) error {
+			s.sms = append(s.sms, toPhoneNumber)
+			return s.err
+		},
+	}
+}
+
+func newNotificationService(t *testing.T) (*NotificationService, *notificationRepo, *realtime.Hub, *sent) {
+	t.Helper()
+	repo := &notificationRepo{preferences: map[[2]string]*model.Preference{}}
+	hub := realtime.NewHub(nil, 4)
+	out := &sent{}
+	return NewNotificationService(validation.New(), repo, hub, out.senders(), 24*time.Hour), repo, hub, out
+}
+
+func TestPreferences(t *testing.T) {
+	svc, repo, _, out := newNotificationService(t)
+	ctx := context.Background()
+
+	if _, err := svc.UpdatePreference(ctx, "user", notify.TypeVerificationCode, &dto.UpdatePreferenceReq{}); !errors.Is(err, ErrRequiredType) {
+		t.Errorf("update of a required type = %v, want ErrRequiredType", err)
+	}
+	if _, err := svc.UpdatePreference(ctx, "user", "newsletter", &dto.UpdatePreferenceReq{}); !errors.Is(err, ErrUnknownType) {
+		t.Errorf("update of an unknown type = %v, want ErrUnknownType", err)
+	}
+	if _, err := svc.UpdatePreference(ctx, "user", notify.TypeVerificationStatus, &dto.UpdatePreferenceReq{SMS: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	preferences, err := svc.ListPreferences(ctx, "user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]dto.Preference{
+		notify.TypeVerificationCode:   {Type: notify.TypeVerificationCode, Email: true, SMS: true, Required: true},
+		notify.TypeVerificationStatus: {Type: notify.TypeVerificationStatus, SMS: true},
+	}
+	if len(preferences) != len(want) {
+		t.Fatalf("%d preferences, want %d", len(preferences), len(want))
+	}
+	for _, preference := range preferences {
+		if *preference != want[preference.Type] {
+			t.Errorf("preference %+v, want %+v", *preference, want[preference.Type])
+		}
+	}
+	// the other users keep the defaults
+	preferences, err = svc.ListPreferences(ctx, "other")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, preference := range preferences {
+		if preference.Type == notify.TypeVerificationStatus && (!preference.Email || !preference.InApp || preference.SMS) {
+			t.Errorf("default preference %+v", *preference)
+		}
+	}
+
+	// the message is only sent on the channels of the preference, to the
+	// contact of the user
+	if err := svc.Notify(ctx, &notify.Message{UserID: "user", Type: notify.TypeVerificationStatus, Title: "Verified"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(out.sms) != 1 || out.sms[0] != "+33612345678" || len(out.emails) != 0 || len(repo.notifications) != 0 {
+		t.Errorf("sent sms %v, emails %v, %d in-app", out.sms, out.emails, len(repo.notifications))
+	}
+
+	// required types ignore the preferences, the channels of the message
+	// are kept
+	repo.preferences[[2]string{"user", notify.TypeVerificationCode}] = &model.Preference{UserID: "user", Type: notify.TypeVerificationCode}
+	out.sms, out.emails = nil, nil
+	msg := &notify.Message{UserID: "user", Type: notify.TypeVerificationCode, Channels: []string{notify.ChannelEmail}, Email: "new@example.com"}
+	if err := svc.Notify(ctx, msg); err != nil {
+		t.Fatal(err)
+	}
+	if len(out.emails) != 1 || out.emails[0] != "new@example.com" || len(out.sms) != 0 {
+		t.Errorf("sent emails %v, sms %v", out.emails, out.sms)
+	}
+
+	if err := svc.Notify(ctx, &notify.Message{UserID: "user", Type: "newsletter"}); !errors.Is(err, ErrUnknownType) {
+		t.Errorf("notify of an unknown type = %v, want ErrUnknownType", err)
+	}
+}
+
+func TestNotificationTTL(t *testing.T) {
+	svc, repo, _, _ := newNotificationService(t)
+	ctx := context.Background()
+
+	if err := svc.Notify(ctx, &notify.Message{UserID: "user", Type: notify.TypeVerificationStatus, Title: "default", Channels: []string{notify.ChannelInApp}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.Notify(ctx, &notify.Message{UserID: "user", Type: notify.TypeVerificationStatus, Title: "short", Channels: []string{notify.ChannelInApp}, TTL: time.Hour}); err != nil {
+		t.Fatal(err)
+	}
+	if len(repo.notifications) != 2 {
+		t.Fatalf("%d notifications stored, want 2", len(repo.notifications))
+	}
+	if n := repo.notifications[0]; n.ExpiresAt.Sub(n.CreatedAt) != 24*time.Hour {
+		t.Errorf("default notification kept %s", n.ExpiresAt.Sub(n.CreatedAt))
+	}
+	short := repo.notifications[1]
+	if short.ExpiresAt.Sub(short.CreatedAt) != time.Hour {
+		t.Errorf("notification with a TTL kept %s", short.ExpiresAt.Sub(short.CreatedAt))
+	}
+
+	if count, err := svc.UnreadCount(ctx, "user"); err != nil || count != 2 {
+		t.Errorf("UnreadCount = %d, %v", count, err)
+	}
+	// an hour later, the short one expired
+	short.ExpiresAt = time.Now().Add(-time.Second)
+	notifications, _, err := svc.List(ctx, "user", &dto.ListNotificationsReq{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(notifications) != 1 || notifications[0].Title != "default" {
+		t.Errorf("listed %+v", notifications)
+	}
+	if count, err := svc.UnreadCount(ctx, "user"); err != nil || count != 1 {
+		t.Errorf("UnreadCount after expiry = %d, %v", count, err)
+	}
+}
+
+func TestHubDelivery(t *testing.T) {
+	svc, repo, hub, out := newNotificationService(t)
+	ctx := context.Background()
+	user, other := hub.Subscribe("user"), hub.Subscribe("other")
+	defer user.Close()
+	defer other.Close()
+
+	// the in-app notification is kept and pushed even when the email fails
+	out.err = errors.New("smtp down")
+	msg := &notify.Message{UserID: "user", Type: notify.TypeVerificationStatus, Title: "Verified", Payload: map[string]string{"status": "verified"}}
+	if err := svc.Notify(ctx, msg); !errors.Is(err, out.err) {
+		t.Errorf("Notify = %v, want the email error", err)
+	}
+	if len(repo.notifications) != 1 || len(out.emails) != 1 {
+		t.Fatalf("%d notifications stored, %d emails", len(repo.notifications), len(out.emails))
+	}
+
+	select {
+	case pushed := <-user.C:
+		var notification dto.Notification
+		if err := json.Unmarshal(pushed.Data, &notification); err != nil {
+			t.Fatal(err)
+		}
+		if pushed.Type != realtime.TypeNotification || notification.ID != repo.notifications[0].ID ||
+			notification.Title != "Verified" || string(notification.Payload) != `{"status":"verified"}` {
+			t.Errorf("pushed %s %+v", pushed.Type, notification)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no notification pushed")
+	}
+	select {
+	case pushed := <-other.C:
+		t.Errorf("another user got %+v", pushed)
+	default:
+	}
+
+	// without the in-app channel, nothing is pushed
+	if _, err := svc.UpdatePreference(ctx, "user", notify.TypeVerificationStatus, &dto.UpdatePreferenceReq{Email: true}); err != nil {
+		t.Fatal(err)
+	}
+	out.err = nil
+	if err := svc.Notify(ctx, msg); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case pushed := <-user.C:
+		t.Errorf("pushed %+v without the in-app channel", pushed)
+	default:
+	}
+	if len(repo.notifications) != 1 {
+		t.Errorf("%d notifications stored", len(repo.notifications))
+	}
+}
@@ -0,0 +1,46 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// swagger:model RealtimeMessage
+type Message struct {
+	ID string `json:"id"`
+	// verification.status, profile.updated, notification.created, broadcast or
+	// heartbeat
+	Type   string          `json:"type"`
+	Data   json.RawMessage `json:"data,omitempty" swaggertype:"object"`
+	SentAt time.Time       `json:"sent_at"`
+}
+
+// swagger:model BroadcastReq
+type BroadcastReq struct {
+	// example: "Scheduled maintenance"
+	Title string `json:"title" validate:"required,max=200"`
+	Body  string `json:"body" validate:"max=2000"`
+}
+
+// BroadcastMessage is the data of the broadcast messages
+type BroadcastMessage struct {
+	Title string `json:"title"`
+	Body  string `json:"body,omitempty"`
+}
+
+// VerificationStatus is the data of the verification.status messages
+type VerificationStatus struct {
+	// email, phone or doctor
+	Subject   string `json:"subject"`
+	SubjectID string `json:"subject_id"`
+	// Status of the doctor, verified for the email and phone
+	Status string `json:"status"`
+}
+
+// ProfileChange is the data of the profile.updated messages
+type ProfileChange struct {
+	// user or doctor
+	Subject   string `json:"subject"`
+	SubjectID string `json:"subject_id"`
+	Action    string `json:"action"`
+}
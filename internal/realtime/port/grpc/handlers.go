@@ -0,0 +1,65 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/quangdangfit/gocommon/logger"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"main/internal/realtime/dto"
+	"main/internal/realtime/service"
+	"main/pkg/realtime"
+	"main/pkg/session"
+	pb "main/proto/gen/go/realtime"
+)
+
+type RealtimeHandler struct {
+	service service.IRealtimeService
+	pb.UnimplementedRealtimeServiceServer
+}
+
+func NewRealtimeHandler(service service.IRealtimeService) *RealtimeHandler {
+	return &RealtimeHandler{service: service}
+}
+
+func (h *RealtimeHandler) Subscribe(_ *pb.SubscribeReq, stream pb.RealtimeService_SubscribeServer) error {
+	ctx := stream.Context()
+	userID, _ := ctx.Value("userId").(string)
+	sessionID, _ := ctx.Value("sessionId").(string)
+	if userID == "" {
+		return errors.New("unauthorized")
+	}
+
+	err := h.service.Stream(ctx, userID, sessionID, func(msg *realtime.Message) error {
+		return stream.Send(toPb(msg))
+	})
+	switch {
+	case errors.Is(err, service.ErrStreamBehind):
+		return status.New(codes.ResourceExhausted, err.Error()).Err()
+	case errors.Is(err, session.ErrRevoked):
+		return status.New(codes.Unauthenticated, err.Error()).Err()
+	}
+	return err
+}
+
+func (h *RealtimeHandler) Broadcast(ctx context.Context, req *pb.BroadcastReq) (*pb.Message, error) {
+	msg, err := h.service.Broadcast(ctx, &dto.BroadcastReq{Title: req.Title, Body: req.Body})
+	if err != nil {
+		logger.Error("Failed to broadcast ", err)
+		return nil, status.New(codes.InvalidArgument, err.Error()).Err()
+	}
+
+	return toPb(msg), nil
+}
+
+func toPb(msg *realtime.Message) *pb.Message {
+	return &pb.Message{
+		Id:     msg.ID,
+		Type:   msg.Type,
+		Data:   string(msg.Data),
+		SentAt: msg.SentAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,20 @@
+package grpc
+
+import (
+	"github.com/quangdangfit/gocommon/validation"
+	"google.golang.org/grpc"
+
+	"main/internal/realtime/service"
+	"main/pkg/audit"
+	"main/pkg/config"
+	"main/pkg/middleware"
+	"main/pkg/realtime"
+	pb "main/proto/gen/go/realtime"
+)
+
+func RegisterHandlers(svr *grpc.Server, validator validation.Validation, auth *middleware.Authenticator, hub *realtime.Hub, recorder audit.Recorder) {
+	realtimeSvc := service.NewRealtimeService(validator, hub, auth.Sessions(), recorder, config.GetConfig().RealtimeHeartbeat)
+	realtimeHandler := NewRealtimeHandler(realtimeSvc)
+
+	pb.RegisterRealtimeServiceServer(svr, realtimeHandler)
+}
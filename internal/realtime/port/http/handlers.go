@@ -0,0 +1,126 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/quangdangfit/gocommon/logger"
+	"golang.org/x/net/websocket"
+
+	"main/internal/realtime/dto"
+	"main/internal/realtime/service"
+	"main/pkg/realtime"
+	"main/pkg/response"
+	"main/pkg/utils"
+)
+
+type RealtimeHandler struct {
+	service service.IRealtimeService
+}
+
+func NewRealtimeHandler(service service.IRealtimeService) *RealtimeHandler {
+	return &RealtimeHandler{service: service}
+}
+
+// WebSocket godoc
+//
+//	@Summary	Receive the messages of the user and the broadcasts over a WebSocket, as json text frames
+//	@Tags		Realtime
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		access_token	query		string	false	"Access token, for the clients that cannot set the Authorization header"
+//	@Success	101				{object}	dto.Message
+//	@Router		/realtime/ws [get]
+func (h *RealtimeHandler) WebSocket(c *gin.Context) {
+	userID, sessionID := c.GetString("userId"), c.GetString("sessionId")
+	server := websocket.Server{
+		// the connection is authenticated by its token and not by a
+		// cookie, so it can be opened from any origin
+		Handshake: func(*websocket.Config, *http.Request) error { return nil },
+		Handler: func(conn *websocket.Conn) {
+			defer conn.Close()
+			ctx, cancel := context.WithCancel(c.Request.Context())
+			defer cancel()
+			// clients send nothing, the read only fails once they are gone
+			go func() {
+				_, _ = io.Copy(io.Discard, conn)
+				cancel()
+			}()
+
+			err := h.service.Stream(ctx, userID, sessionID, func(msg *realtime.Message) error {
+				return websocket.JSON.Send(conn, msg)
+			})
+			if err != nil {
+				logger.Info("Realtime websocket closed ", err)
+			}
+		},
+	}
+	server.ServeHTTP(c.Writer, c.Request)
+}
+
+// Events godoc
+//
+//	@Summary	Receive the messages of the user and the broadcasts as Server-Sent Events, named by their type
+//	@Tags		Realtime
+//	@Security	ApiKeyAuth
+//	@Produce	text/event-stream
+//	@Param		access_token	query		string	false	"Access token, for the clients that cannot set the Authorization header"
+//	@Success	200				{object}	dto.Message
+//	@Router		/realtime/events [get]
+func (h *RealtimeHandler) Events(c *gin.Context) {
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	// proxies must not buffer the stream
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	err := h.service.Stream(c.Request.Context(), c.GetString("userId"), c.GetString("sessionId"), func(msg *realtime.Message) error {
+		data, err := json.Marshal(msg)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(c.Writer, "id: %s\nevent: %s\ndata: %s\n\n", msg.ID, msg.Type, data); err != nil {
+			return err
+		}
+		c.Writer.Flush()
+		return nil
+	})
+	if err != nil {
+		logger.Info("Realtime event stream closed ", err)
+	}
+}
+
+// Broadcast godoc
+//
+//	@Summary	Push a message to every connected user
+//	@Tags		Realtime-admin
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		_	body		dto.BroadcastReq	true	"Body"
+//	@Success	200	{object}	dto.Message
+//	@Router		/realtime-admin/broadcast [post]
+func (h *RealtimeHandler) Broadcast(c *gin.Context) {
+	var req dto.BroadcastReq
+	if err := c.ShouldBindJSON(&req); c.Request.Body == nil || err != nil {
+		logger.Error("Failed to get body", err)
+		response.Error(c, http.StatusBadRequest, err, "Invalid parameters")
+		return
+	}
+
+	msg, err := h.service.Broadcast(c, &req)
+	if err != nil {
+		logger.Error("Failed to broadcast ", err)
+		response.Error(c, http.StatusBadRequest, err, err.Error())
+		return
+	}
+
+	var res dto.Message
+	utils.Copy(&res, msg)
+	response.JSON(c, http.StatusOK, res)
+}
@@ -0,0 +1,29 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/quangdangfit/gocommon/validation"
+
+	"main/internal/realtime/service"
+	"main/pkg/audit"
+	"main/pkg/config"
+	"main/pkg/middleware"
+	"main/pkg/rbac"
+	"main/pkg/realtime"
+)
+
+func Routes(r *gin.RouterGroup, validator validation.Validation, auth *middleware.Authenticator, hub *realtime.Hub, recorder audit.Recorder) {
+	realtimeSvc := service.NewRealtimeService(validator, hub, auth.Sessions(), recorder, config.GetConfig().RealtimeHeartbeat)
+	realtimeHandler := NewRealtimeHandler(realtimeSvc)
+
+	realtimeRoute := r.Group("/realtime", middleware.JWTStream(auth))
+	{
+		realtimeRoute.GET("/ws", realtimeHandler.WebSocket)
+		realtimeRoute.GET("/events", realtimeHandler.Events)
+	}
+
+	realtimeRouteAdmin := r.Group("/realtime-admin", middleware.JWTPermission(auth, rbac.BroadcastsSend))
+	{
+		realtimeRouteAdmin.POST("/broadcast", realtimeHandler.Broadcast)
+	}
+}
@@ -0,0 +1,14 @@
+package subscriber
+
+import (
+	"context"
+
+	"main/internal/realtime/service"
+	"main/pkg/events"
+)
+
+// Subscribe pushes the verifications and profile changes to the connected
+// users
+func Subscribe(ctx context.Context, bus *events.Bus, realtimeSvc *service.RealtimeService) error {
+	return bus.Subscribe(ctx, "realtime", realtimeSvc.Notify, events.EmailVerified, events.PhoneVerified, events.UserUpdated, events.DoctorUpdated)
+}
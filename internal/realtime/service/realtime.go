@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/quangdangfit/gocommon/logger"
+	"github.com/quangdangfit/gocommon/validation"
+
+	"main/internal/realtime/dto"
+	"main/pkg/audit"
+	"main/pkg/events"
+	"main/pkg/realtime"
+	"main/pkg/session"
+)
+
+// ErrStreamBehind closes the streams of the clients too slow to keep up, they
+// reconnect and refetch what they show
+var ErrStreamBehind = errors.New("realtime stream fell behind")
+
+//go:generate mockery --name=IRealtimeService
+type IRealtimeService interface {
+	Stream(ctx context.Context, userID, sessionID string, send func(*realtime.Message) error) error
+	Broadcast(ctx context.Context, req *dto.BroadcastReq) (*realtime.Message, error)
+}
+
+type RealtimeService struct {
+	validator validation.Validation
+	hub       *realtime.Hub
+	sessions  *session.Store
+	recorder  audit.Recorder
+	heartbeat time.Duration
+}
+
+func NewRealtimeService(
+	validator validation.Validation,
+	hub *realtime.Hub,
+	sessions *session.Store,
+	recorder audit.Recorder,
+	heartbeat time.Duration,
+) *RealtimeService {
+	return &RealtimeService{
+		validator: validator,
+		hub:       hub,
+		sessions:  sessions,
+		recorder:  recorder,
+		heartbeat: heartbeat,
+	}
+}
+
+// Stream sends the messages of userID and the broadcasts to send until ctx is
+// done, the stream falls behind or the session is revoked. A heartbeat is sent
+// every heartbeat, when the session is checked again.
+func (s *RealtimeService) Stream(ctx context.Context, userID, sessionID string, send func(*realtime.Message) error) error {
+	subscription := s.hub.Subscribe(userID)
+	defer subscription.Close()
+
+	ticker := time.NewTicker(s.heartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case msg, ok := <-subscription.C:
+			if !ok {
+				return ErrStreamBehind
+			}
+			if err := send(msg); err != nil {
+				return err
+			}
+		case <-ticker.C:
+			if err := s.sessions.Check(ctx, sessionID); err != nil {
+				return err
+			}
+			if err := send(realtime.NewMessage(realtime.TypeHeartbeat, nil)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Broadcast pushes a message to every connected user
+func (s *RealtimeService) Broadcast(ctx context.Context, req *dto.BroadcastReq) (*realtime.Message, error) {
+	if err := s.validator.ValidateStruct(req); err != nil {
+		return nil, err
+	}
+
+	msg := realtime.NewMessage(realtime.TypeBroadcast, &dto.BroadcastMessage{Title: req.Title, Body: req.Body})
+	if err := s.hub.Broadcast(ctx, msg); err != nil {
+		logger.Errorf("Broadcast fail, title: %s, error: %s", req.Title, err)
+		return nil, err
+	}
+	s.recorder.Record(ctx, &audit.Event{Action: audit.Broadcast, TargetType: audit.TargetBroadcast, TargetID: msg.ID, After: req})
+
+	return msg, nil
+}
+
+// Notify pushes the verifications and profile changes to the channel of their
+// user, it is the handler of the domain events
+func (s *RealtimeService) Notify(ctx context.Context, event *events.Event) error {
+	var change events.Change
+	if err := event.Decode(&change); err != nil {
+		return err
+	}
+
+	var userID string
+	var msg *realtime.Message
+	switch event.Name {
+	case events.EmailVerified, events.PhoneVerified:
+		subject := "email"
+		if event.Name == events.PhoneVerified {
+			subject = "phone"
+		}
+		userID = event.SubjectID
+		msg = realtime.NewMessage(realtime.TypeVerification, &dto.VerificationStatus{Subject: subject, SubjectID: event.SubjectID, Status: "verified"})
+	case events.UserUpdated:
+		userID = event.SubjectID
+		msg = realtime.NewMessage(realtime.TypeProfile, &dto.ProfileChange{Subject: "user", SubjectID: event.SubjectID, Action: change.Action})
+	case events.DoctorUpdated:
+		userID = change.UserID
+		if change.Action == events.ActionReviewed || change.Action == events.ActionSuspended {
+			msg = realtime.NewMessage(realtime.TypeVerification, &dto.VerificationStatus{Subject: "doctor", SubjectID: event.SubjectID, Status: change.Status})
+		} else {
+			msg = realtime.NewMessage(realtime.TypeProfile, &dto.ProfileChange{Subject: "doctor", SubjectID: event.SubjectID, Action: change.Action})
+		}
+	}
+	// jobs changing many subjects have no user to notify
+	if msg == nil || userID == "" {
+		return nil
+	}
+
+	return s.hub.Send(ctx, userID, msg)
+}
@@ -0,0 +1,79 @@
+package dto
+
+import (
+	"time"
+
+	"main/pkg/paging"
+)
+
+// swagger:model Review
+type Review struct {
+	ID       string `json:"id"`
+	DoctorID string `json:"doctor_id"`
+	// example: 5
+	Rating    int        `json:"rating"`
+	Text      string     `json:"text"`
+	Reply     string     `json:"reply"`
+	RepliedAt *time.Time `json:"replied_at"`
+	CreatedAt time.Time  `json:"created_at"`
+	// Version of the review, also sent as its ETag
+	Version int64 `json:"version"`
+}
+
+// ModeratedReview shows moderators who hid a review and why
+// swagger:model ModeratedReview
+type ModeratedReview struct {
+	Review
+	PatientID    string     `json:"patient_id"`
+	Hidden       bool       `json:"hidden"`
+	HiddenReason string     `json:"hidden_reason"`
+	HiddenBy     string     `json:"hidden_by"`
+	HiddenAt     *time.Time `json:"hidden_at"`
+}
+
+// swagger:model CreateReviewReq
+type CreateReviewReq struct {
+	DoctorID string `json:"doctor_id" validate:"required"`
+	// From 1 to 5
+	// example: 5
+	Rating int `json:"rating" validate:"required,min=1,max=5"`
+	// example: "Listened carefully and explained everything"
+	Text string `json:"text" validate:"max=2000"`
+}
+
+// swagger:model ReplyReviewReq
+type ReplyReviewReq struct {
+	Reply string `json:"reply" validate:"required,max=2000"`
+	// Version the reply applies to, from If-Match over HTTP
+	Version int64 `json:"-"`
+}
+
+// swagger:model HideReviewReq
+type HideReviewReq struct {
+	// example: "Abusive language"
+	Reason string `json:"reason" validate:"required"`
+	// Version the change applies to, from If-Match over HTTP
+	Version int64 `json:"-"`
+}
+
+type ListReviewsReq struct {
+	DoctorID string `json:"doctor_id,omitempty" form:"doctor_id"`
+	// Only reviews with this rating
+	Rating int   `json:"rating,omitempty" form:"rating"`
+	Page   int64 `json:"page,omitempty" form:"page"`
+	Limit  int64 `json:"limit,omitempty" form:"limit"`
+	// Moderators only, hidden reviews are never listed otherwise
+	Hidden *bool `json:"hidden,omitempty" form:"hidden"`
+}
+
+// swagger:model ListReviewsRes
+type ListReviewsRes struct {
+	Reviews    []*Review          `json:"reviews"`
+	Pagination *paging.Pagination `json:"pagination"`
+}
+
+// swagger:model ListModeratedReviewsRes
+type ListModeratedReviewsRes struct {
+	Reviews    []*ModeratedReview `json:"reviews"`
+	Pagination *paging.Pagination `json:"pagination"`
+}
@@ -0,0 +1,38 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Review is the rating a patient gives a doctor, one per patient and doctor.
+// Hidden reviews do not count in the rating of the doctor.
+type Review struct {
+	ID        string     `json:"id" gorm:"unique;not null;index;primary_key"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	DoctorID  string     `json:"doctor_id" gorm:"not null;uniqueIndex:idx_review_doctor_patient"`
+	PatientID string     `json:"patient_id" gorm:"not null;uniqueIndex:idx_review_doctor_patient"`
+	Rating    int        `json:"rating" gorm:"not null"`
+	Text      string     `json:"text"`
+	Reply     string     `json:"reply"`
+	RepliedAt *time.Time `json:"replied_at"`
+	Hidden    bool       `json:"hidden" gorm:"not null;default:false;index"`
+	// HiddenReason is only shown to moderators
+	HiddenReason string     `json:"hidden_reason"`
+	HiddenBy     string     `json:"hidden_by"`
+	HiddenAt     *time.Time `json:"hidden_at"`
+	// Version is bumped by every change, it is the ETag of the review
+	Version int64 `json:"version" gorm:"not null;default:1"`
+}
+
+func (Review) TableName() string {
+	return "reviews"
+}
+
+func (m *Review) BeforeCreate() error {
+	m.ID = uuid.New().String()
+	m.CreatedAt = time.Now()
+	return nil
+}
@@ -0,0 +1,244 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/quangdangfit/gocommon/logger"
+
+	"main/internal/review/dto"
+	"main/internal/review/service"
+	"main/pkg/dbs"
+	"main/pkg/response"
+	"main/pkg/utils"
+)
+
+type ReviewHandler struct {
+	service service.IReviewService
+}
+
+func NewReviewHandler(
+	service service.IReviewService,
+) *ReviewHandler {
+	return &ReviewHandler{
+		service: service,
+	}
+}
+
+// CreateReview godoc
+//
+//	@Summary	Review a doctor, once per doctor
+//	@Tags		Review
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		_	body		dto.CreateReviewReq	true	"Body"
+//	@Success	200	{object}	dto.Review
+//	@Router		/reviews [post]
+func (h *ReviewHandler) CreateReview(c *gin.Context) {
+	var req dto.CreateReviewReq
+	if err := c.ShouldBindJSON(&req); c.Request.Body == nil || err != nil {
+		logger.Error("Failed to get body", err)
+		response.Error(c, http.StatusBadRequest, err, "Invalid parameters")
+		return
+	}
+
+	review, err := h.service.Create(c, c.GetString("userId"), &req)
+	if err != nil {
+		logger.Error("Failed to create review ", err)
+		reviewError(c, err)
+		return
+	}
+
+	var res dto.Review
+	utils.Copy(&res, &review)
+	response.Versioned(c, http.StatusOK, res.Version, res)
+}
+
+// ListReviews godoc
+//
+//	@Summary	List the reviews, newest first
+//	@Tags		Review
+//	@Produce	json
+//	@Param		doctor_id	query		string	false	"Doctor ID"
+//	@Param		rating		query		int		false	"Only reviews with this rating"
+//	@Param		page		query		int64	false	"Page number"
+//	@Param		limit		query		int64	false	"Limit per page"
+//	@Success	200			{object}	dto.ListReviewsRes
+//	@Router		/reviews [get]
+func (h *ReviewHandler) ListReviews(c *gin.Context) {
+	var req dto.ListReviewsReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.Error("Failed to get query params", err)
+		response.Error(c, http.StatusBadRequest, err, "Invalid parameters")
+		return
+	}
+
+	reviews, pagination, err := h.service.ListReviews(c, &req)
+	if err != nil {
+		logger.Error("Failed to list reviews ", err)
+		response.Error(c, http.StatusInternalServerError, err, "Something went wrong")
+		return
+	}
+
+	var res dto.ListReviewsRes
+	utils.Copy(&res.Reviews, &reviews)
+	res.Pagination = pagination
+	response.JSON(c, http.StatusOK, res)
+}
+
+// ReplyReview godoc
+//
+//	@Summary	Reply to a review of the signed-in doctor, once
+//	@Tags		Review
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		id	path		string				true	"Review ID"
+//	@Param		If-Match	header	string	true	"ETag of the review, its version"
+//	@Param		_	body		dto.ReplyReviewReq	true	"Body"
+//	@Success	200	{object}	dto.Review
+//	@Failure	412	{object}	response.Response	"Changed since it was read"
+//	@Router		/reviews/{id}/reply [put]
+func (h *ReviewHandler) ReplyReview(c *gin.Context) {
+	version, ok := response.IfMatch(c)
+	if !ok {
+		return
+	}
+
+	var req dto.ReplyReviewReq
+	if err := c.ShouldBindJSON(&req); c.Request.Body == nil || err != nil {
+		logger.Error("Failed to get body", err)
+		response.Error(c, http.StatusBadRequest, err, "Invalid parameters")
+		return
+	}
+
+	req.Version = version
+	review, err := h.service.Reply(c, c.GetString("userId"), c.Param("id"), &req)
+	if err != nil {
+		logger.Error("Failed to reply to review ", err)
+		reviewError(c, err)
+		return
+	}
+
+	var res dto.Review
+	utils.Copy(&res, &review)
+	response.Versioned(c, http.StatusOK, res.Version, res)
+}
+
+// ListModeratedReviews godoc
+//
+//	@Summary	List the reviews with the hidden ones, newest first
+//	@Tags		Review-admin
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		doctor_id	query		string	false	"Doctor ID"
+//	@Param		rating		query		int		false	"Only reviews with this rating"
+//	@Param		hidden		query		bool	false	"Only hidden or visible reviews"
+//	@Param		page		query		int64	false	"Page number"
+//	@Param		limit		query		int64	false	"Limit per page"
+//	@Success	200			{object}	dto.ListModeratedReviewsRes
+//	@Router		/review-admin/reviews [get]
+func (h *ReviewHandler) ListModeratedReviews(c *gin.Context) {
+	var req dto.ListReviewsReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.Error("Failed to get query params", err)
+		response.Error(c, http.StatusBadRequest, err, "Invalid parameters")
+		return
+	}
+
+	reviews, pagination, err := h.service.ListModeratedReviews(c, &req)
+	if err != nil {
+		logger.Error("Failed to list reviews ", err)
+		response.Error(c, http.StatusInternalServerError, err, "Something went wrong")
+		return
+	}
+
+	var res dto.ListModeratedReviewsRes
+	utils.Copy(&res.Reviews, &reviews)
+	res.Pagination = pagination
+	response.JSON(c, http.StatusOK, res)
+}
+
+// HideReview godoc
+//
+//	@Summary	Hide an abusive review, it no longer counts in the rating
+//	@Tags		Review-admin
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		id	path		string				true	"Review ID"
+//	@Param		If-Match	header	string	true	"ETag of the review, its version"
+//	@Param		_	body		dto.HideReviewReq	true	"Body"
+//	@Success	200	{object}	dto.ModeratedReview
+//	@Failure	412	{object}	response.Response	"Changed since it was read"
+//	@Router		/review-admin/reviews/{id}/hide [post]
+func (h *ReviewHandler) HideReview(c *gin.Context) {
+	version, ok := response.IfMatch(c)
+	if !ok {
+		return
+	}
+
+	var req dto.HideReviewReq
+	if err := c.ShouldBindJSON(&req); c.Request.Body == nil || err != nil {
+		logger.Error("Failed to get body", err)
+		response.Error(c, http.StatusBadRequest, err, "Invalid parameters")
+		return
+	}
+
+	req.Version = version
+	review, err := h.service.Hide(c, c.GetString("userId"), c.Param("id"), &req)
+	if err != nil {
+		logger.Error("Failed to hide review ", err)
+		reviewError(c, err)
+		return
+	}
+
+	var res dto.ModeratedReview
+	utils.Copy(&res, &review)
+	response.Versioned(c, http.StatusOK, res.Version, res)
+}
+
+// UnhideReview godoc
+//
+//	@Summary	Show a hidden review again
+//	@Tags		Review-admin
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		id	path		string	true	"Review ID"
+//	@Param		If-Match	header	string	true	"ETag of the review, its version"
+//	@Success	200	{object}	dto.ModeratedReview
+//	@Failure	412	{object}	response.Response	"Changed since it was read"
+//	@Router		/review-admin/reviews/{id}/unhide [post]
+func (h *ReviewHandler) UnhideReview(c *gin.Context) {
+	version, ok := response.IfMatch(c)
+	if !ok {
+		return
+	}
+
+	review, err := h.service.Unhide(c, c.Param("id"), version)
+	if err != nil {
+		logger.Error("Failed to unhide review ", err)
+		reviewError(c, err)
+		return
+	}
+
+	var res dto.ModeratedReview
+	utils.Copy(&res, &review)
+	response.Versioned(c, http.StatusOK, res.Version, res)
+}
+
+func reviewError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, dbs.ErrStaleVersion):
+		response.StaleVersion(c, err)
+	case errors.Is(err, service.ErrDoctorNotFound):
+		response.Error(c, http.StatusNotFound, err, "Doctor not found")
+	case errors.Is(err, service.ErrReviewNotFound):
+		response.Error(c, http.StatusNotFound, err, "Review not found")
+	case errors.Is(err, service.ErrNotReviewed), errors.Is(err, service.ErrOwnReview):
+		response.Error(c, http.StatusForbidden, err, err.Error())
+	case errors.Is(err, service.ErrAlreadyReviewed), errors.Is(err, service.ErrAlreadyReplied), errors.Is(err, service.ErrHiddenUnchanged):
+		response.Error(c, http.StatusConflict, err, err.Error())
+	default:
+		response.Error(c, http.StatusBadRequest, err, err.Error())
+	}
+}
@@ -0,0 +1,38 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/quangdangfit/gocommon/validation"
+
+	doctorRepository "main/internal/doctor/repository"
+	"main/internal/review/repository"
+	"main/internal/review/service"
+	"main/pkg/dbs"
+	"main/pkg/events"
+	"main/pkg/middleware"
+	"main/pkg/rbac"
+)
+
+func Routes(r *gin.RouterGroup, sqlDB dbs.IDatabase, validator validation.Validation, auth *middleware.Authenticator, publisher events.Publisher) {
+	reviewRepo := repository.NewReviewRepository(sqlDB)
+	reviewSvc := service.NewReviewService(validator, reviewRepo, doctorRepository.NewDoctorRepository(sqlDB), publisher)
+	reviewHandler := NewReviewHandler(reviewSvc)
+
+	reviewsWrite := middleware.JWTPermission(auth, rbac.ReviewsWrite)
+	userAuthMiddleware := middleware.JWTAuth(auth)
+	reviewsModerate := middleware.JWTPermission(auth, rbac.ReviewsModerate)
+	reviewRoute := r.Group("/reviews")
+	{
+		reviewRoute.GET("", reviewHandler.ListReviews)
+		reviewRoute.POST("", reviewsWrite, reviewHandler.CreateReview)
+		// the doctor profile of the signed-in user must be the reviewed one
+		reviewRoute.PUT("/:id/reply", userAuthMiddleware, reviewHandler.ReplyReview)
+	}
+
+	reviewRouteAdmin := r.Group("/review-admin")
+	{
+		reviewRouteAdmin.GET("/reviews", reviewsModerate, reviewHandler.ListModeratedReviews)
+		reviewRouteAdmin.POST("/reviews/:id/hide", reviewsModerate, reviewHandler.HideReview)
+		reviewRouteAdmin.POST("/reviews/:id/unhide", reviewsModerate, reviewHandler.UnhideReview)
+	}
+}
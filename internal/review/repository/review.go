@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	doctorModel "main/internal/doctor/model"
+	"main/internal/review/dto"
+	"main/internal/review/model"
+	"main/pkg/config"
+	"main/pkg/dbs"
+	"main/pkg/paging"
+)
+
+//go:generate mockery --name=IReviewRepository
+type IReviewRepository interface {
+	Create(ctx context.Context, review *model.Review) (bool, error)
+	GetReviewByID(ctx context.Context, id string) (*model.Review, error)
+	ListReviews(ctx context.Context, req *dto.ListReviewsReq, moderated bool) ([]*model.Review, *paging.Pagination, error)
+	Reply(ctx context.Context, id, reply string, version int64, now time.Time) (bool, error)
+	SetHidden(ctx context.Context, review *model.Review) (bool, error)
+}
+
+type ReviewRepo struct {
+	db dbs.IDatabase
+}
+
+func NewReviewRepository(db dbs.IDatabase) *ReviewRepo {
+	return &ReviewRepo{db: db}
+}
+
+// Create adds the review and updates the rating of the doctor, false when the
+// patient already reviewed the doctor
+func (r *ReviewRepo) Create(ctx context.Context, review *model.Review) (bool, error) {
+	created := false
+	err := r.db.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var existing int64
+		err := tx.Model(&model.Review{}).
+			Where("doctor_id = ? AND patient_id = ?", review.DoctorID, review.PatientID).
+			Count(&existing).Error
+		if err != nil || existing > 0 {
+			return err
+		}
+		if err := tx.Create(review).Error; err != nil {
+			return err
+		}
+		if err := updateRating(tx, review.DoctorID); err != nil {
+			return err
+		}
+		created = true
+		return nil
+	})
+	return created, err
+}
+
+func (r *ReviewRepo) GetReviewByID(ctx context.Context, id string) (*model.Review, error) {
+	var review model.Review
+	if err := r.db.GetDB().WithContext(ctx).Where("id = ?", id).First(&review).Error; err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+// ListReviews lists the reviews newest first, hidden ones only when moderated
+func (r *ReviewRepo) ListReviews(ctx context.Context, req *dto.ListReviewsReq, moderated bool) ([]*model.Review, *paging.Pagination, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.DatabaseTimeout)
+	defer cancel()
+
+	var query []dbs.Query
+	if req.DoctorID != "" {
+		query = append(query, dbs.NewQuery("doctor_id = ?", req.DoctorID))
+	}
+	if req.Rating != 0 {
+		query = append(query, dbs.NewQuery("rating = ?", req.Rating))
+	}
+	if !moderated {
+		query = append(query, dbs.NewQuery("hidden = ?", false))
+	} else if req.Hidden != nil {
+		query = append(query, dbs.NewQuery("hidden = ?", *req.Hidden))
+	}
+
+	var total int64
+	if err := r.db.Count(ctx, &model.Review{}, &total, dbs.WithQuery(query...)); err != nil {
+		return nil, nil, err
+	}
+
+	pagination := paging.New(req.Page, req.Limit, total)
+
+	var reviews []*model.Review
+	if err := r.db.Find(
+		ctx,
+		&reviews,
+		dbs.WithQuery(query...),
+		dbs.WithLimit(int(pagination.Limit)),
+		dbs.WithOffset(int(pagination.Skip)),
+		dbs.WithOrder("created_at DESC"),
+	); err != nil {
+		return nil, nil, err
+	}
+
+	return reviews, pagination, nil
+}
+
+// Reply sets the reply of the doctor to the review still at version, false
+// when the review was changed or already has one
+func (r *ReviewRepo) Reply(ctx context.Context, id, reply string, version int64, now time.Time) (bool, error) {
+	result := r.db.GetDB().WithContext(ctx).Model(&model.Review{}).
+		Where("id = ? AND reply = ? AND version = ?", id, "", version).
+		Updates(map[string]interface{}{
+			"reply":      reply,
+			"replied_at": now,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// SetHidden hides or shows the review still at its version and updates the
+// rating of the doctor, false when the review was changed or already was
+func (r *ReviewRepo) SetHidden(ctx context.Context, review *model.Review) (bool, error) {
+	changed := false
+	err := r.db.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.Review{}).
+			Where("id = ? AND hidden = ? AND version = ?", review.ID, !review.Hidden, review.Version).
+			Updates(map[string]interface{}{
+				"hidden":        review.Hidden,
+				"hidden_reason": review.HiddenReason,
+				"hidden_by":     review.HiddenBy,
+				"hidden_at":     review.HiddenAt,
+			})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		if err := updateRating(tx, review.DoctorID); err != nil {
+			return err
+		}
+		changed = true
+		return nil
+	})
+	if changed {
+		review.Version++
+	}
+	return changed, err
+}
+
+// updateRating recomputes the rating of the doctor from its visible reviews,
+// the doctor row is locked so concurrent reviews are counted in turn
+func updateRating(tx *gorm.DB, doctorID string) error {
+	var doctor doctorModel.Doctor
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id").
+		Where("id = ?", doctorID).
+		First(&doctor).Error
+	if err != nil {
+		return err
+	}
+
+	var rating struct {
+		Average float64
+		Count   int64
+	}
+	err = tx.Model(&model.Review{}).
+		Select("COALESCE(AVG(rating), 0) AS average, COUNT(*) AS count").
+		Where("doctor_id = ? AND hidden = ?", doctorID, false).
+		Scan(&rating).Error
+	if err != nil {
+		return err
+	}
+
+	return tx.Model(&doctorModel.Doctor{}).
+		Where("id = ?", doctorID).
+		Updates(map[string]interface{}{
+			"rating_average": rating.Average,
+			"rating_count":   rating.Count,
+		}).Error
+}
@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"main/internal/review/model"
+	"main/pkg/dbs"
+)
+
+// newMock is a repository on a mocked database expecting the statements in
+// order
+func newMock(t *testing.T) (*ReviewRepo, sqlmock.Sqlmock) {
+	t.Helper()
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+		_ = sqlDB.Close()
+	})
+
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewReviewRepository(dbs.Wrap(db)), mock
+}
+
+// expectRating expects the rating of doctorID to be recomputed from its
+// visible reviews, with the doctor locked
+func expectRating(mock sqlmock.Sqlmock, doctorID string, average float64, count int64) {
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id" FROM "doctors" WHERE id = $1`)+`.* FOR UPDATE`).
+		WithArgs(doctorID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(doctorID))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT COALESCE(AVG(rating), 0) AS average, COUNT(*) AS count FROM "reviews" WHERE doctor_id = $1 AND hidden = $2`)).
+		WithArgs(doctorID, false).
+		WillReturnRows(sqlmock.NewRows([]string{"average", "count"}).AddRow(average, count))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "doctors" SET "rating_average"=$1,"rating_count"=$2`)).
+		WithArgs(average, count, sqlmock.AnyArg(), doctorID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+}
+
+func TestCreateUpdatesRating(t *testing.T) {
+	repo, mock := newMock(t)
+	review := &model.Review{DoctorID: "doctor", PatientID: "patient", Rating: 4}
+	review.BeforeCreate()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "reviews" WHERE doctor_id = $1 AND patient_id = $2`)).
+		WithArgs("doctor", "patient").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "reviews"`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectRating(mock, "doctor", 4.5, 2)
+	mock.ExpectCommit()
+
+	created, err := repo.Create(context.Background(), review)
+	if err != nil || !created {
+		t.Fatalf("Create = %v, %v", created, err)
+	}
+}
+
+func TestCreateOncePerPatient(t *testing.T) {
+	repo, mock := newMock(t)
+	review := &model.Review{DoctorID: "doctor", PatientID: "patient", Rating: 1}
+	review.BeforeCreate()
+
+	// neither the review nor the rating are written
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "reviews" WHERE doctor_id = $1 AND patient_id = $2`)).
+		WithArgs("doctor", "patient").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectCommit()
+
+	created, err := repo.Create(context.Background(), review)
+	if err != nil || created {
+		t.Fatalf("second Create = %v, %v", created, err)
+	}
+}
+
+func TestSetHiddenUpdatesRating(t *testing.T) {
+	repo, mock := newMock(t)
+	now := time.Now()
+	review := &model.Review{ID: "review", DoctorID: "doctor", Hidden: true, HiddenReason: "abuse", HiddenBy: "moderator", HiddenAt: &now, Version: 3}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "reviews" SET`)+`.*`+regexp.QuoteMeta(`WHERE id = $6 AND hidden = $7 AND version = $8`)).
+		WithArgs(true, sqlmock.AnyArg(), "moderator", "abuse", sqlmock.AnyArg(), "review", false, int64(3)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectRating(mock, "doctor", 0, 0)
+	mock.ExpectCommit()
+
+	changed, err := repo.SetHidden(context.Background(), review)
+	if err != nil || !changed {
+		t.Fatalf("SetHidden = %v, %v", changed, err)
+	}
+	if review.Version != 4 {
+		t.Errorf("version = %d, want 4", review.Version)
+	}
+}
+
+func TestSetHiddenStale(t *testing.T) {
+	repo, mock := newMock(t)
+	review := &model.Review{ID: "review", DoctorID: "doctor", Version: 2}
+
+	// changed meanwhile, the rating is left as is
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "reviews" SET`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	changed, err := repo.SetHidden(context.Background(), review)
+	if err != nil || changed {
+		t.Fatalf("stale SetHidden = %v, %v", changed, err)
+	}
+	if review.Version != 2 {
+		t.Errorf("version = %d, want 2", review.Version)
+	}
+}
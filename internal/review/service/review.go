@@ -0,0 +1,264 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/quangdangfit/gocommon/logger"
+	"github.com/quangdangfit/gocommon/validation"
+	"gorm.io/gorm"
+
+	doctorModel "main/internal/doctor/model"
+	"main/internal/review/dto"
+	"main/internal/review/model"
+	"main/internal/review/repository"
+	"main/pkg/dbs"
+	"main/pkg/events"
+	"main/pkg/paging"
+)
+
+var (
+	ErrDoctorNotFound  = errors.New("doctor not found")
+	ErrReviewNotFound  = errors.New("review not found")
+	ErrAlreadyReviewed = errors.New("you already reviewed this doctor")
+	ErrAlreadyReplied  = errors.New("review already has a reply")
+	ErrNotReviewed     = errors.New("only the reviewed doctor can reply")
+	ErrOwnReview       = errors.New("doctors cannot review themselves")
+	ErrHiddenUnchanged = errors.New("review is already in this state")
+)
+
+//go:generate mockery --name=IReviewService
+type IReviewService interface {
+	Create(ctx context.Context, patientID string, req *dto.CreateReviewReq) (*model.Review, error)
+	ListReviews(ctx context.Context, req *dto.ListReviewsReq) ([]*model.Review, *paging.Pagination, error)
+	ListModeratedReviews(ctx context.Context, req *dto.ListReviewsReq) ([]*model.Review, *paging.Pagination, error)
+	Reply(ctx context.Context, userID, id string, req *dto.ReplyReviewReq) (*model.Review, error)
+	Hide(ctx context.Context, moderatorID, id string, req *dto.HideReviewReq) (*model.Review, error)
+	Unhide(ctx context.Context, id string, version int64) (*model.Review, error)
+}
+
+// Doctors finds the reviewed doctors
+type Doctors interface {
+	GetDoctorByID(ctx context.Context, id string) (*doctorModel.Doctor, error)
+	GetDoctorByUserID(ctx context.Context, userID string) (*doctorModel.Doctor, error)
+}
+
+type ReviewService struct {
+	validator validation.Validation
+	repo      repository.IReviewRepository
+	doctors   Doctors
+	publisher events.Publisher
+}
+
+func NewReviewService(
+	validator validation.Validation,
+	repo repository.IReviewRepository,
+	doctors Doctors,
+	publisher events.Publisher,
+) *ReviewService {
+	return &ReviewService{
+		validator: validator,
+		repo:      repo,
+		doctors:   doctors,
+		publisher: publisher,
+	}
+}
+
+// Create reviews a verified doctor, a patient reviews each doctor once
+func (s *ReviewService) Create(ctx context.Context, patientID string, req *dto.CreateReviewReq) (*model.Review, error) {
+	if err := s.validator.ValidateStruct(req); err != nil {
+		return nil, err
+	}
+
+	doctor, err := s.doctors.GetDoctorByID(ctx, req.DoctorID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrDoctorNotFound
+	}
+	if err != nil {
+		logger.Errorf("Create.GetDoctorByID fail, id: %s, error: %s", req.DoctorID, err)
+		return nil, err
+	}
+	// unverified doctors are not listed either
+	if doctor.Status != doctorModel.DoctorVerified {
+		return nil, ErrDoctorNotFound
+	}
+	if doctor.IDUser == patientID {
+		return nil, ErrOwnReview
+	}
+
+	review := model.Review{
+		DoctorID:  doctor.ID,
+		PatientID: patientID,
+		Rating:    req.Rating,
+		Text:      req.Text,
+	}
+	review.BeforeCreate()
+	created, err := s.repo.Create(ctx, &review)
+	if err != nil {
+		logger.Errorf("Create fail, doctor: %s, error: %s", doctor.ID, err)
+		return nil, err
+	}
+	if !created {
+		return nil, ErrAlreadyReviewed
+	}
+	s.publish(ctx, doctor)
+
+	return &review, nil
+}
+
+// ListReviews lists the visible reviews
+func (s *ReviewService) ListReviews(ctx context.Context, req *dto.ListReviewsReq) ([]*model.Review, *paging.Pagination, error) {
+	reviews, pagination, err := s.repo.ListReviews(ctx, req, false)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return reviews, pagination, nil
+}
+
+// ListModeratedReviews lists the reviews with the hidden ones
+func (s *ReviewService) ListModeratedReviews(ctx context.Context, req *dto.ListReviewsReq) ([]*model.Review, *paging.Pagination, error) {
+	reviews, pagination, err := s.repo.ListReviews(ctx, req, true)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return reviews, pagination, nil
+}
+
+// Reply answers a review of the doctor profile of userID, once
+func (s *ReviewService) Reply(ctx context.Context, userID, id string, req *dto.ReplyReviewReq) (*model.Review, error) {
+	if err := s.validator.ValidateStruct(req); err != nil {
+		return nil, err
+	}
+
+	review, err := s.review(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	doctor, err := s.doctors.GetDoctorByUserID(ctx, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotReviewed
+	}
+	if err != nil {
+		logger.Errorf("Reply.GetDoctorByUserID fail, user: %s, error: %s", userID, err)
+		return nil, err
+	}
+	if doctor.ID != review.DoctorID {
+		return nil, ErrNotReviewed
+	}
+	if review.Reply != "" {
+		return nil, ErrAlreadyReplied
+	}
+	if err := checkVersion(review, req.Version); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	replied, err := s.repo.Reply(ctx, id, req.Reply, review.Version, now)
+	if err != nil {
+		logger.Errorf("Reply fail, id: %s, error: %s", id, err)
+		return nil, err
+	}
+	if !replied {
+		return nil, dbs.ErrStaleVersion
+	}
+
+	review.Reply = req.Reply
+	review.RepliedAt = &now
+	review.Version++
+	return review, nil
+}
+
+// Hide removes an abusive review from the listings and the rating of the
+// doctor
+func (s *ReviewService) Hide(ctx context.Context, moderatorID, id string, req *dto.HideReviewReq) (*model.Review, error) {
+	if err := s.validator.ValidateStruct(req); err != nil {
+		return nil, err
+	}
+
+	review, err := s.review(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if review.Hidden {
+		return nil, ErrHiddenUnchanged
+	}
+	if err := checkVersion(review, req.Version); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	review.Hidden = true
+	review.HiddenReason = req.Reason
+	review.HiddenBy = moderatorID
+	review.HiddenAt = &now
+	return s.setHidden(ctx, review)
+}
+
+// Unhide restores a review hidden by mistake
+func (s *ReviewService) Unhide(ctx context.Context, id string, version int64) (*model.Review, error) {
+	review, err := s.review(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !review.Hidden {
+		return nil, ErrHiddenUnchanged
+	}
+	if err := checkVersion(review, version); err != nil {
+		return nil, err
+	}
+
+	review.Hidden = false
+	review.HiddenReason = ""
+	review.HiddenBy = ""
+	review.HiddenAt = nil
+	return s.setHidden(ctx, review)
+}
+
+func (s *ReviewService) review(ctx context.Context, id string) (*model.Review, error) {
+	review, err := s.repo.GetReviewByID(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrReviewNotFound
+	}
+	if err != nil {
+		logger.Errorf("GetReviewByID fail, id: %s, error: %s", id, err)
+		return nil, err
+	}
+	return review, nil
+}
+
+func (s *ReviewService) setHidden(ctx context.Context, review *model.Review) (*model.Review, error) {
+	changed, err := s.repo.SetHidden(ctx, review)
+	if err != nil {
+		logger.Errorf("SetHidden fail, id: %s, error: %s", review.ID, err)
+		return nil, err
+	}
+	if !changed {
+		return nil, dbs.ErrStaleVersion
+	}
+
+	doctor, err := s.doctors.GetDoctorByID(ctx, review.DoctorID)
+	if err != nil {
+		logger.Errorf("SetHidden.GetDoctorByID fail, id: %s, error: %s", review.DoctorID, err)
+		return review, nil
+	}
+	s.publish(ctx, doctor)
+	return review, nil
+}
+
+// publish tells the subscribers that the rating of doctor changed
+func (s *ReviewService) publish(ctx context.Context, doctor *doctorModel.Doctor) {
+	s.publisher.Publish(ctx, events.New(ctx, events.DoctorUpdated, doctor.ID, &events.Change{Action: events.ActionUpdated, UserID: doctor.IDUser, Status: doctor.Status}))
+}
+
+// checkVersion fails when the review is no longer at the version the change
+// applies to
+func checkVersion(review *model.Review, version int64) error {
+	if version != dbs.AnyVersion && version != review.Version {
+		return dbs.ErrStaleVersion
+	}
+	return nil
+}
@@ -0,0 +1,250 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/quangdangfit/gocommon/logger"
+	"github.com/quangdangfit/gocommon/validation"
+	"gorm.io/gorm"
+
+	doctorModel "main/internal/doctor/model"
+	"main/internal/review/dto"
+	"main/internal/review/model"
+	"main/internal/review/repository"
+	"main/pkg/config"
+	"main/pkg/dbs"
+	"main/pkg/events"
+)
+
+func TestMain(m *testing.M) {
+	logger.Initialize(config.ProductionEnv)
+	os.Exit(m.Run())
+}
+
+// reviewRepo keeps the reviews in memory and the rating of the doctors up to
+// date like the transactions of repository.ReviewRepo
+type reviewRepo struct {
+	repository.IReviewRepository
+	reviews map[string]*model.Review
+	doctors map[string]*doctorModel.Doctor
+}
+
+func (r *reviewRepo) Create(ctx context.Context, review *model.Review) (bool, error) {
+	for _, existing := range r.reviews {
+		if existing.DoctorID == review.DoctorID && existing.PatientID == review.PatientID {
+			return false, nil
+		}
+	}
+	review.Version = 1
+	copied := *review
+	r.reviews[review.ID] = &copied
+	r.updateRating(review.DoctorID)
+	return true, nil
+}
+
+func (r *reviewRepo) GetReviewByID(ctx context.Context, id string) (*model.Review, error) {
+	review, ok := r.reviews[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *review
+	return &copied, nil
+}
+
+func (r *reviewRepo) SetHidden(ctx context.Context, review *model.Review) (bool, error) {
+	stored := r.reviews[review.ID]
+	if stored.Hidden == review.Hidden || stored.Version != review.Version {
+		return false, nil
+	}
+	review.Version++
+	*stored = *review
+	r.updateRating(review.DoctorID)
+	return true, nil
+}
+
+func (r *reviewRepo) Reply(ctx context.Context, id, reply string, version int64, now time.Time) (bool, error) {
+	review := r.reviews[id]
+	if review.Reply != "" || review.Version != version {
+		return false, nil
+	}
+	review.Reply, review.RepliedAt = reply, &now
+	review.Version++
+	return true, nil
+}
+
+func (r *reviewRepo) updateRating(doctorID string) {
+	var sum, count int64
+	for _, review := range r.reviews {
+		if review.DoctorID == doctorID && !review.Hidden {
+			sum += int64(review.Rating)
+			count++
+		}
+	}
+	doctor := r.doctors[doctorID]
+	doctor.RatingCount, doctor.RatingAverage = count, 0
+	if count > 0 {
+		doctor.RatingAverage = float64(sum) / float64(count)
+	}
+}
+
+func (r *reviewRepo) GetDoctorByID(ctx context.Context, id string) (*doctorModel.Doctor, error) {
+	doctor, ok := r.doctors[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *doctor
+	return &copied, nil
+}
+
+func (r *reviewRepo) GetDoctorByUserID(ctx context.Context, userID string) (*doctorModel.Doctor, error) {
+	for _, doctor := range r.doctors {
+		if doctor.IDUser == userID {
+			return r.GetDoctorByID(ctx, doctor.ID)
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// published counts the events of the service by subject
+type published map[string]int
+
+func (p published) Publish(ctx context.Context, event *events.Event) {
+	if event.Name == events.DoctorUpdated {
+		p[event.SubjectID]++
+	}
+}
+
+func newReviewService(t *testing.T) (*ReviewService, *reviewRepo, published) {
+	t.Helper()
+	repo := &reviewRepo{
+		reviews: map[string]*model.Review{},
+		doctors: map[string]*doctorModel.Doctor{
+			"doctor":     {ID: "doctor", IDUser: "doctor-user", Status: doctorModel.DoctorVerified},
+			"unverified": {ID: "unverified", IDUser: "unverified-user", Status: doctorModel.DoctorPending},
+		},
+	}
+	sent := published{}
+	return NewReviewService(validation.New(), repo, repo, sent), repo, sent
+}
+
+func TestReviewRating(t *testing.T) {
+	svc, repo, sent := newReviewService(t)
+	ctx := context.Background()
+	doctor := repo.doctors["doctor"]
+
+	first, err := svc.Create(ctx, "patient-1", &dto.CreateReviewReq{DoctorID: "doctor", Rating: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.Create(ctx, "patient-2", &dto.CreateReviewReq{DoctorID: "doctor", Rating: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if doctor.RatingAverage != 3.5 || doctor.RatingCount != 2 || sent["doctor"] != 2 {
+		t.Errorf("rating %v of %d, %d events", doctor.RatingAverage, doctor.RatingCount, sent["doctor"])
+	}
+
+	// hidden, the review no longer counts
+	hidden, err := svc.Hide(ctx, "moderator", first.ID, &dto.HideReviewReq{Reason: "abuse", Version: first.Version})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !hidden.Hidden || hidden.HiddenBy != "moderator" || hidden.Version != 2 {
+		t.Errorf("hidden = %+v", hidden)
+	}
+	if doctor.RatingAverage != 2 || doctor.RatingCount != 1 || sent["doctor"] != 3 {
+		t.Errorf("rating after hide %v of %d, %d events", doctor.RatingAverage, doctor.RatingCount, sent["doctor"])
+	}
+	if _, err := svc.Hide(ctx, "moderator", first.ID, &dto.HideReviewReq{Reason: "abuse", Version: dbs.AnyVersion}); !errors.Is(err, ErrHiddenUnchanged) {
+		t.Errorf("second Hide = %v, want ErrHiddenUnchanged", err)
+	}
+
+	if _, err := svc.Unhide(ctx, first.ID, 1); !errors.Is(err, dbs.ErrStaleVersion) {
+		t.Errorf("stale Unhide = %v, want ErrStaleVersion", err)
+	}
+	shown, err := svc.Unhide(ctx, first.ID, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if shown.Hidden || shown.HiddenReason != "" || shown.HiddenAt != nil {
+		t.Errorf("shown = %+v", shown)
+	}
+	if doctor.RatingAverage != 3.5 || doctor.RatingCount != 2 || sent["doctor"] != 4 {
+		t.Errorf("rating after unhide %v of %d, %d events", doctor.RatingAverage, doctor.RatingCount, sent["doctor"])
+	}
+}
+
+func TestReviewOncePerPatient(t *testing.T) {
+	svc, repo, sent := newReviewService(t)
+	ctx := context.Background()
+
+	if _, err := svc.Create(ctx, "patient", &dto.CreateReviewReq{DoctorID: "doctor", Rating: 4}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.Create(ctx, "patient", &dto.CreateReviewReq{DoctorID: "doctor", Rating: 1}); !errors.Is(err, ErrAlreadyReviewed) {
+		t.Errorf("second Create = %v, want ErrAlreadyReviewed", err)
+	}
+	if doctor := repo.doctors["doctor"]; doctor.RatingAverage != 4 || doctor.RatingCount != 1 || sent["doctor"] != 1 {
+		t.Errorf("rating %v of %d, %d events", doctor.RatingAverage, doctor.RatingCount, sent["doctor"])
+	}
+}
+
+func TestReviewCreateRefused(t *testing.T) {
+	svc, repo, sent := newReviewService(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		patientID string
+		req       dto.CreateReviewReq
+		want      error
+	}{
+		{"unknown doctor", "patient", dto.CreateReviewReq{DoctorID: "unknown", Rating: 5}, ErrDoctorNotFound},
+		{"unverified doctor", "patient", dto.CreateReviewReq{DoctorID: "unverified", Rating: 5}, ErrDoctorNotFound},
+		{"own profile", "doctor-user", dto.CreateReviewReq{DoctorID: "doctor", Rating: 5}, ErrOwnReview},
+	}
+	for _, tt := range tests {
+		if _, err := svc.Create(ctx, tt.patientID, &tt.req); !errors.Is(err, tt.want) {
+			t.Errorf("%s: Create = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+	if _, err := svc.Create(ctx, "patient", &dto.CreateReviewReq{DoctorID: "doctor", Rating: 6}); err == nil {
+		t.Error("rating of 6 accepted")
+	}
+	if len(repo.reviews) != 0 || len(sent) != 0 {
+		t.Errorf("%d reviews created, %d events", len(repo.reviews), len(sent))
+	}
+}
+
+func TestReviewReply(t *testing.T) {
+	svc, _, _ := newReviewService(t)
+	ctx := context.Background()
+
+	review, err := svc.Create(ctx, "patient", &dto.CreateReviewReq{DoctorID: "doctor", Rating: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.Reply(ctx, "unverified-user", review.ID, &dto.ReplyReviewReq{Reply: "thanks"}); !errors.Is(err, ErrNotReviewed) {
+		t.Errorf("Reply of another doctor = %v, want ErrNotReviewed", err)
+	}
+	if _, err := svc.Reply(ctx, "doctor-user", review.ID, &dto.ReplyReviewReq{Reply: "thanks", Version: 2}); !errors.Is(err, dbs.ErrStaleVersion) {
+		t.Errorf("stale Reply = %v, want ErrStaleVersion", err)
+	}
+	if _, err := svc.Reply(ctx, "doctor-user", "unknown", &dto.ReplyReviewReq{Reply: "thanks"}); !errors.Is(err, ErrReviewNotFound) {
+		t.Errorf("Reply to unknown = %v, want ErrReviewNotFound", err)
+	}
+
+	replied, err := svc.Reply(ctx, "doctor-user", review.ID, &dto.ReplyReviewReq{Reply: "thanks", Version: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replied.Reply != "thanks" || replied.RepliedAt == nil || replied.Version != 2 {
+		t.Errorf("replied = %+v", replied)
+	}
+	if _, err := svc.Reply(ctx, "doctor-user", review.ID, &dto.ReplyReviewReq{Reply: "again"}); !errors.Is(err, ErrAlreadyReplied) {
+		t.Errorf("second Reply = %v, want ErrAlreadyReplied", err)
+	}
+}
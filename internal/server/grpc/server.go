@@ -6,53 +6,145 @@ import (
 
 	"github.com/quangdangfit/gocommon/logger"
 	"github.com/quangdangfit/gocommon/validation"
-	"golang.org/x/oauth2"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
 
 	// cartGRPC "main/internal/cart/port/grpc"
 	addressGRPC "main/internal/address/port/grpc"
+	auditRepository "main/internal/audit/repository"
+	auditService "main/internal/audit/service"
+	doctorGRPC "main/internal/doctor/port/grpc"
+	fileGRPC "main/internal/file/port/grpc"
+	fileRepository "main/internal/file/repository"
+	fileService "main/internal/file/service"
+	healthGRPC "main/internal/health/port/grpc"
+	notificationRepository "main/internal/notification/repository"
+	notificationService "main/internal/notification/service"
+	realtimeGRPC "main/internal/realtime/port/grpc"
+	specialtyGRPC "main/internal/specialty/port/grpc"
 	userGRPC "main/internal/user/port/grpc"
+	userRepository "main/internal/user/repository"
+	userService "main/internal/user/service"
 	"main/pkg/config"
 	"main/pkg/dbs"
+	"main/pkg/events"
+	"main/pkg/idempotency"
+	"main/pkg/imaging"
 	"main/pkg/middleware"
+	"main/pkg/notify"
+	"main/pkg/oauth"
+	"main/pkg/ratelimit"
+	"main/pkg/realtime"
 	"main/pkg/redis"
+	"main/pkg/session"
+	"main/pkg/storage"
 )
 
+// idempotentMethods replay their response to the retries sent with the same
+// idempotency-key
+var idempotentMethods = []string{
+	"/address.AddressService/CreateAddress",
+	"/doctor.DoctorService/CreateDoctor",
+	"/user.UserService/Register",
+}
+
 type Server struct {
-	engine        *grpc.Server
-	cfg           *config.Schema
-	validator     validation.Validation
-	db            dbs.IDatabase
-	cache         redis.IRedis
-	oauthConfig   *oauth2.Config
-	fbOauthConfig *oauth2.Config
+	engine         *grpc.Server
+	cfg            *config.Schema
+	validator      validation.Validation
+	db             dbs.IDatabase
+	cache          redis.IRedis
+	oauthProviders *oauth.Registry
+	storage        storage.Storage
+	images         *imaging.Pipeline
+	publisher      events.Publisher
+	hub            *realtime.Hub
+	auth           *middleware.Authenticator
+	audits         *auditService.AuditService
 }
 
-func NewServer(validator validation.Validation, db dbs.IDatabase, cache redis.IRedis, oauthConfig *oauth2.Config, fbOauthConfig *oauth2.Config) *Server {
-	interceptor := middleware.NewAuthInterceptor(config.AuthIgnoreMethods)
+func NewServer(validator validation.Validation, db dbs.IDatabase, cache redis.IRedis, oauthProviders *oauth.Registry, store storage.Storage, images *imaging.Pipeline, publisher events.Publisher, hub *realtime.Hub) *Server {
+	audits := auditService.NewAuditService(validator, auditRepository.NewAuditRepository(db))
+	userRepo := userRepository.NewUserRepository(db)
+	sessions := session.NewStore(cache, userService.NewSessionRevocations(userRepo))
+	apiKeys := userService.NewAPIKeyService(validator, userRepo, cache, sessions, audits)
+	auth := middleware.NewAuthenticator(sessions, apiKeys)
+	interceptor := middleware.NewAuthInterceptor(
+		config.AuthIgnoreMethods,
+		config.AuthMFAMethods,
+		config.AuthRefreshMethods,
+		config.AuthMethodPermissions,
+		auth,
+	)
+
+	rules := ratelimit.RulesFromConfig(config.GetConfig())
+	loginPolicy := middleware.RateLimitPolicy{
+		Rule: rules.Login,
+		Keys: []middleware.InterceptorRateLimitKey{middleware.RateLimitByPeer(), middleware.RateLimitByRequestEmail()},
+	}
+	verifyPolicy := middleware.RateLimitPolicy{
+		Rule: rules.Verify,
+		Keys: []middleware.InterceptorRateLimitKey{middleware.RateLimitByPeer(), middleware.RateLimitByCaller()},
+	}
+	rateLimitInterceptor := middleware.NewRateLimitInterceptor(
+		ratelimit.New(cache),
+		middleware.RateLimitPolicy{Rule: rules.Default},
+		map[string]middleware.RateLimitPolicy{
+			"/user.UserService/Login":                       loginPolicy,
+			"/user.UserService/Register":                    {Rule: rules.Register},
+			"/user.UserService/VerfiyCodeEmail":             verifyPolicy,
+			"/user.UserService/VerfiyCodePhoneNumber":       verifyPolicy,
+			"/user.UserService/VerfiyCodeEmailResend":       verifyPolicy,
+			"/user.UserService/VerfiyCodePhoneNumberResend": verifyPolicy,
+			"/user.UserService/VerifyMFA":                   {Rule: rules.Verify},
+			"/user.UserService/ConfirmMFA":                  verifyPolicy,
+			"/user.UserService/RegenerateRecoveryCodes":     verifyPolicy,
+			"/user.UserService/DisableMFA":                  verifyPolicy,
+			"/user.UserService/ClientToken":                 {Rule: rules.Login, Keys: []middleware.InterceptorRateLimitKey{middleware.RateLimitByPeer()}},
+		},
+	)
+
+	// responses replayed to the retries sent with an idempotency-key
+	keys, err := idempotency.FromConfig(config.GetConfig(), db.GetDB(), cache)
+	if err != nil {
+		logger.Fatal("Cannot open idempotency keys ", err)
+	}
+	idempotencyInterceptor := middleware.NewIdempotencyInterceptor(keys, idempotentMethods...)
 
 	grpcServer := grpc.NewServer(
 		grpc.ChainUnaryInterceptor(
+			middleware.RequestIDUnary(),
+			middleware.SessionClientUnary(),
 			interceptor.Unary(),
+			rateLimitInterceptor.Unary(),
+			idempotencyInterceptor.Unary(),
+		),
+		grpc.ChainStreamInterceptor(
+			middleware.RequestIDStream(),
+			middleware.SessionClientStream(),
+			interceptor.Stream(),
+			rateLimitInterceptor.Stream(),
 		),
 	)
 
 	return &Server{
-		engine:        grpcServer,
-		cfg:           config.GetConfig(),
-		validator:     validator,
-		db:            db,
-		cache:         cache,
-		oauthConfig:   oauthConfig,
-		fbOauthConfig: fbOauthConfig,
+		engine:         grpcServer,
+		cfg:            config.GetConfig(),
+		validator:      validator,
+		db:             db,
+		cache:          cache,
+		oauthProviders: oauthProviders,
+		storage:        store,
+		images:         images,
+		publisher:      publisher,
+		hub:            hub,
+		auth:           auth,
+		audits:         audits,
 	}
 }
 
 func (s Server) Run() error {
-	userGRPC.RegisterHandlers(s.engine, s.db, s.validator, s.oauthConfig, s.fbOauthConfig)
-	addressGRPC.RegisterHandlers(s.engine, s.db, s.validator, s.cache)
-	// cartGRPC.RegisterHandlers(s.engine, s.db, s.validator)
+	s.registerHandlers()
 
 	reflection.Register(s.engine)
 
@@ -72,3 +164,19 @@ func (s Server) Run() error {
 
 	return nil
 }
+
+// registerHandlers registers the services of the modules on the engine
+func (s Server) registerHandlers() {
+	files, images := fileService.NewServices(fileRepository.NewFileRepository(s.db), s.storage, s.images)
+
+	notifications := notificationService.NewNotificationService(s.validator, notificationRepository.NewNotificationRepository(s.db), s.hub, notify.DefaultSenders(), s.cfg.NotificationTTL)
+
+	userGRPC.RegisterHandlers(s.engine, s.db, s.validator, s.cache, s.oauthProviders, s.auth, images, s.audits, s.publisher, notifications)
+	addressGRPC.RegisterHandlers(s.engine, s.db, s.validator, s.cache, s.audits, s.publisher)
+	doctorGRPC.RegisterHandlers(s.engine, s.db, s.validator, s.cache, s.audits, s.publisher)
+	fileGRPC.RegisterHandlers(s.engine, files)
+	specialtyGRPC.RegisterHandlers(s.engine, s.db, s.validator, s.publisher)
+	healthGRPC.RegisterHandlers(s.engine, s.db, s.validator)
+	realtimeGRPC.RegisterHandlers(s.engine, s.validator, s.auth, s.hub, s.audits)
+	// cartGRPC.RegisterHandlers(s.engine, s.db, s.validator)
+}
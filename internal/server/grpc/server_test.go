@@ -0,0 +1,73 @@
+package grpc
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/quangdangfit/gocommon/validation"
+	"google.golang.org/grpc"
+
+	auditRepository "main/internal/audit/repository"
+	auditService "main/internal/audit/service"
+	userRepository "main/internal/user/repository"
+	userService "main/internal/user/service"
+	"main/pkg/config"
+	"main/pkg/dbs"
+	"main/pkg/events"
+	"main/pkg/middleware"
+	"main/pkg/realtime"
+	"main/pkg/redis"
+	"main/pkg/session"
+)
+
+// TestRegisterHandlers checks that the methods configured for the
+// interceptors are served
+func TestRegisterHandlers(t *testing.T) {
+	cache := redis.New(redis.Config{Address: miniredis.RunT(t).Addr(), Timeout: time.Second})
+	// the handlers do not query the database until they are called
+	db := &dbs.Database{}
+	validator := validation.New()
+	s := Server{
+		engine:    grpc.NewServer(),
+		cfg:       config.GetConfig(),
+		validator: validator,
+		db:        db,
+		cache:     cache,
+		publisher: events.Nop(),
+		hub:       realtime.NewHub(cache.Client(), 1),
+		auth:      middleware.NewAuthenticator(session.NewStore(cache, userService.NewSessionRevocations(userRepository.NewUserRepository(db))), nil),
+		audits:    auditService.NewAuditService(validator, auditRepository.NewAuditRepository(db)),
+	}
+	s.registerHandlers()
+
+	var methods []string
+	methods = append(methods, config.AuthIgnoreMethods...)
+	methods = append(methods, config.AuthMFAMethods...)
+	methods = append(methods, config.AuthRefreshMethods...)
+	methods = append(methods, idempotentMethods...)
+	for method := range config.AuthMethodPermissions {
+		methods = append(methods, method)
+	}
+
+	services := s.engine.GetServiceInfo()
+	for _, method := range methods {
+		name, call, _ := strings.Cut(strings.TrimPrefix(method, "/"), "/")
+		info, ok := services[name]
+		if !ok {
+			t.Errorf("%s: service %s is not registered", method, name)
+			continue
+		}
+		served := false
+		for _, m := range info.Methods {
+			served = served || m.Name == call
+		}
+		if !served {
+			t.Errorf("%s is not served", method)
+		}
+	}
+	if _, ok := services["doctor.DoctorService"]; !ok {
+		t.Error("doctor.DoctorService is not registered")
+	}
+}
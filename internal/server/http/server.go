@@ -10,40 +10,72 @@ import (
 	"github.com/quangdangfit/gocommon/validation"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
-	"golang.org/x/oauth2"
 
 	_ "main/docs"
 	// orderHttp "main/internal/order/port/http"
 	addressHttp "main/internal/address/port/http"
+	auditHttp "main/internal/audit/port/http"
+	auditRepository "main/internal/audit/repository"
+	auditService "main/internal/audit/service"
+	clinicHttp "main/internal/clinic/port/http"
+	consultationHttp "main/internal/consultation/port/http"
 	doctorHttp "main/internal/doctor/port/http"
+	fileHttp "main/internal/file/port/http"
+	fileRepository "main/internal/file/repository"
+	fileService "main/internal/file/service"
+	healthHttp "main/internal/health/port/http"
+	notificationHttp "main/internal/notification/port/http"
+	notificationRepository "main/internal/notification/repository"
+	notificationService "main/internal/notification/service"
+	realtimeHttp "main/internal/realtime/port/http"
+	reviewHttp "main/internal/review/port/http"
+	specialtyHttp "main/internal/specialty/port/http"
 	userHttp "main/internal/user/port/http"
+	userRepository "main/internal/user/repository"
+	userService "main/internal/user/service"
+	webhookHttp "main/internal/webhook/port/http"
 	// Admin "main/pkg/admin"
 	"main/pkg/config"
 	"main/pkg/dbs"
+	"main/pkg/events"
+	"main/pkg/idempotency"
+	"main/pkg/imaging"
+	"main/pkg/middleware"
+	"main/pkg/notify"
+	"main/pkg/oauth"
+	"main/pkg/ratelimit"
+	"main/pkg/realtime"
 	"main/pkg/redis"
 	"main/pkg/response"
+	"main/pkg/session"
+	"main/pkg/storage"
 )
 
 type Server struct {
-	engine        *gin.Engine
-	cfg           *config.Schema
-	validator     validation.Validation
-	db            dbs.IDatabase
-	cache         redis.IRedis
-	oauthConfig   *oauth2.Config
-	fbOauthConfig *oauth2.Config
+	engine         *gin.Engine
+	cfg            *config.Schema
+	validator      validation.Validation
+	db             dbs.IDatabase
+	cache          redis.IRedis
+	oauthProviders *oauth.Registry
+	storage        storage.Storage
+	images         *imaging.Pipeline
+	publisher      events.Publisher
+	hub            *realtime.Hub
 }
 
-func NewServer(validator validation.Validation, db dbs.IDatabase, cache redis.IRedis, fbOauthConfig *oauth2.Config,
-	oauthConfig *oauth2.Config) *Server {
+func NewServer(validator validation.Validation, db dbs.IDatabase, cache redis.IRedis, oauthProviders *oauth.Registry, store storage.Storage, images *imaging.Pipeline, publisher events.Publisher, hub *realtime.Hub) *Server {
 	return &Server{
-		engine:        gin.Default(),
-		cfg:           config.GetConfig(),
-		validator:     validator,
-		db:            db,
-		cache:         cache,
-		oauthConfig:   oauthConfig,
-		fbOauthConfig: fbOauthConfig,
+		engine:         gin.Default(),
+		cfg:            config.GetConfig(),
+		validator:      validator,
+		db:             db,
+		cache:          cache,
+		oauthProviders: oauthProviders,
+		storage:        store,
+		images:         images,
+		publisher:      publisher,
+		hub:            hub,
 	}
 }
 
@@ -83,9 +115,41 @@ func (s Server) GetEngine() *gin.Engine {
 
 func (s Server) MapRoutes() error {
 	v1 := s.engine.Group("/api/v1")
-	userHttp.Routes(v1, s.db, s.validator, s.fbOauthConfig, s.oauthConfig)
-	addressHttp.Routes(v1, s.db, s.validator, s.cache)
-	doctorHttp.Routes(v1, s.db, s.validator, s.cache)
+	v1.Use(middleware.RequestID())
+	v1.Use(middleware.SessionClient())
+	v1.Use(middleware.RateLimit(ratelimit.New(s.cache), ratelimit.RulesFromConfig(s.cfg).Default, middleware.RateLimitByIP()))
+
+	// security and data-access events of every module
+	audits := auditService.NewAuditService(s.validator, auditRepository.NewAuditRepository(s.db))
+
+	userRepo := userRepository.NewUserRepository(s.db)
+	sessions := session.NewStore(s.cache, userService.NewSessionRevocations(userRepo))
+	apiKeys := userService.NewAPIKeyService(s.validator, userRepo, s.cache, sessions, audits)
+	auth := middleware.NewAuthenticator(sessions, apiKeys)
+	// responses replayed to the retries sent with an Idempotency-Key
+	keys, err := idempotency.FromConfig(s.cfg, s.db.GetDB(), s.cache)
+	if err != nil {
+		return err
+	}
+
+	files, images := fileService.NewServices(fileRepository.NewFileRepository(s.db), s.storage, s.images)
+
+	// in-app notifications, the verification codes are also sent through it
+	notifications := notificationService.NewNotificationService(s.validator, notificationRepository.NewNotificationRepository(s.db), s.hub, notify.DefaultSenders(), s.cfg.NotificationTTL)
+
+	userHttp.Routes(v1, s.db, s.validator, s.cache, s.oauthProviders, auth, keys, images, s.storage, images, audits, s.publisher, notifications)
+	addressHttp.Routes(v1, s.db, s.validator, s.cache, auth, keys, audits, s.publisher)
+	doctorHttp.Routes(v1, s.db, s.validator, s.cache, auth, keys, images, audits, s.publisher)
+	reviewHttp.Routes(v1, s.db, s.validator, auth, s.publisher)
+	specialtyHttp.Routes(v1, s.db, s.validator, s.cache, auth, s.publisher)
+	clinicHttp.Routes(v1, s.db, s.validator, auth, audits, s.publisher)
+	healthHttp.Routes(v1, s.db, s.validator, auth)
+	consultationHttp.Routes(v1, s.db, s.validator, auth)
+	fileHttp.Routes(v1, files, images, auth)
+	auditHttp.Routes(v1, audits, auth)
+	webhookHttp.Routes(v1, s.db, s.validator, auth, audits)
+	realtimeHttp.Routes(v1, s.validator, auth, s.hub, audits)
+	notificationHttp.Routes(v1, notifications, auth)
 	// orderHttp.Routes(v1, s.db, s.validator)
 
 	// Create a pointer to AdminPanel and call Run method
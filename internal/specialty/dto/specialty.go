@@ -0,0 +1,126 @@
+package dto
+
+import (
+	"time"
+
+	"main/internal/specialty/model"
+)
+
+// swagger:model Specialty
+type Specialty struct {
+	ID       string  `json:"id"`
+	ParentID *string `json:"parent_id"`
+	// example: "cardiology"
+	Slug string `json:"slug"`
+	// Name in the requested locale
+	// example: "Cardiology"
+	Name string `json:"name"`
+	// example: {"en":"Cardiology","fr":"Cardiologie"}
+	Names     map[string]string `json:"names"`
+	Icon      string            `json:"icon"`
+	Aliases   []string          `json:"aliases"`
+	CreatedAt time.Time         `json:"created_at"`
+	// Specialties refining this one
+	Children []*Specialty `json:"children,omitempty" swaggertype:"array,object"`
+	// Version of the specialty, also sent as its ETag
+	Version int64 `json:"version"`
+}
+
+// swagger:model CreateSpecialtyReq
+type CreateSpecialtyReq struct {
+	ParentID *string `json:"parent_id"`
+	// Lower case words separated by dashes
+	// example: "pediatric-cardiology"
+	Slug string `json:"slug" validate:"required,max=64"`
+	// Names by locale, en is required
+	// example: {"en":"Pediatric cardiology","fr":"Cardiologie pédiatrique"}
+	Names map[string]string `json:"names" validate:"required,min=1,dive,keys,min=2,max=16,endkeys,required"`
+	// Url of the icon
+	Icon string `json:"icon" validate:"omitempty,url"`
+	// Other spellings of the specialty found in free-text values
+	// example: ["pediatric cardiologist"]
+	Aliases []string `json:"aliases" validate:"dive,required"`
+}
+
+// UpdateSpecialtyReq replaces the specialty
+// swagger:model UpdateSpecialtyReq
+type UpdateSpecialtyReq struct {
+	CreateSpecialtyReq
+	// Version the change applies to, from If-Match over HTTP
+	Version int64 `json:"-"`
+}
+
+type ListSpecialtiesReq struct {
+	// Locale of the names
+	// example: "fr"
+	Locale string `json:"locale,omitempty" form:"locale"`
+}
+
+// swagger:model ListSpecialtiesRes
+type ListSpecialtiesRes struct {
+	// Root specialties with their children
+	Specialties []*Specialty `json:"specialties"`
+}
+
+// swagger:model DoctorSpecialty
+type DoctorSpecialty struct {
+	SpecialtyID string `json:"specialty_id" validate:"required"`
+	Primary     bool   `json:"primary"`
+}
+
+// SetDoctorSpecialtiesReq replaces the specialties of a doctor, exactly one of
+// them is primary
+// swagger:model SetDoctorSpecialtiesReq
+type SetDoctorSpecialtiesReq struct {
+	Specialties []DoctorSpecialty `json:"specialties" validate:"required,min=1,max=10,dive"`
+}
+
+// MigrationReport lists the free-text specialities of doctors that could not
+// be mapped to the catalogue, by number of doctors
+// swagger:model MigrationReport
+type MigrationReport struct {
+	Mapped    int            `json:"mapped"`
+	Unmatched map[string]int `json:"unmatched"`
+}
+
+// NewSpecialty converts specialty with its name in locale
+func NewSpecialty(specialty *model.Specialty, locale string) *Specialty {
+	return &Specialty{
+		ID:        specialty.ID,
+		ParentID:  specialty.ParentID,
+		Slug:      specialty.Slug,
+		Name:      specialty.Name(locale),
+		Names:     specialty.Names,
+		Icon:      specialty.Icon,
+		Aliases:   specialty.Aliases,
+		CreatedAt: specialty.CreatedAt,
+		Version:   specialty.Version,
+	}
+}
+
+// Tree nests the specialties under their parent and returns the roots, or the
+// specialty of rootID
+func Tree(specialties []*model.Specialty, locale, rootID string) []*Specialty {
+	nodes := make(map[string]*Specialty, len(specialties))
+	for _, specialty := range specialties {
+		nodes[specialty.ID] = NewSpecialty(specialty, locale)
+	}
+
+	var roots []*Specialty
+	for _, specialty := range specialties {
+		node := nodes[specialty.ID]
+		if specialty.ID == rootID {
+			roots = append(roots, node)
+		}
+		var parent *Specialty
+		if specialty.ParentID != nil {
+			parent = nodes[*specialty.ParentID]
+		}
+		if parent != nil {
+			parent.Children = append(parent.Children, node)
+		} else if rootID == "" {
+			roots = append(roots, node)
+		}
+	}
+	return roots
+}
@@ -0,0 +1,117 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultLocale is the locale every specialty has a name in
+const DefaultLocale = "en"
+
+// Specialty is an entry of the catalogue, specialties under a parent refine
+// it
+type Specialty struct {
+	ID        string    `json:"id" gorm:"unique;not null;index;primary_key"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	ParentID  *string   `json:"parent_id" gorm:"index"`
+	Slug      string    `json:"slug" gorm:"not null;uniqueIndex"`
+	// Names by locale, with at least the DefaultLocale one
+	Names Names `json:"names" gorm:"type:text"`
+	// Icon is the url of the icon
+	Icon string `json:"icon"`
+	// Aliases are the other spellings mapped to the specialty when migrating
+	// free-text values
+	Aliases Aliases `json:"aliases" gorm:"type:text"`
+	// Version is bumped by every change, it is the ETag of the specialty
+	Version int64 `json:"version" gorm:"not null;default:1"`
+}
+
+func (Specialty) TableName() string {
+	return "specialties"
+}
+
+func (m *Specialty) BeforeCreate() error {
+	m.ID = uuid.New().String()
+	m.CreatedAt = time.Now()
+	return nil
+}
+
+// Name returns the name in locale, the default one when missing
+func (m *Specialty) Name(locale string) string {
+	if name, ok := m.Names[locale]; ok && name != "" {
+		return name
+	}
+	if name, ok := m.Names[DefaultLocale]; ok && name != "" {
+		return name
+	}
+	return m.Slug
+}
+
+// DoctorSpecialty links a doctor to a specialty, one of the specialties of a
+// doctor is the primary one
+type DoctorSpecialty struct {
+	DoctorID    string     `json:"doctor_id" gorm:"primaryKey"`
+	SpecialtyID string     `json:"specialty_id" gorm:"primaryKey;index"`
+	Primary     bool       `json:"primary" gorm:"column:is_primary;not null;default:false"`
+	CreatedAt   time.Time  `json:"created_at"`
+	Specialty   *Specialty `json:"specialty,omitempty" gorm:"foreignKey:SpecialtyID;constraint:OnDelete:RESTRICT"`
+}
+
+func (DoctorSpecialty) TableName() string {
+	return "doctor_specialties"
+}
+
+// Names are the names of a specialty by locale, stored as json
+type Names map[string]string
+
+func (n Names) Value() (driver.Value, error) {
+	if n == nil {
+		return "{}", nil
+	}
+	b, err := json.Marshal(map[string]string(n))
+	return string(b), err
+}
+
+func (n *Names) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case string:
+		return json.Unmarshal([]byte(v), n)
+	case []byte:
+		return json.Unmarshal(v, n)
+	case nil:
+		*n = nil
+		return nil
+	default:
+		return fmt.Errorf("cannot scan %T into Names", value)
+	}
+}
+
+// Aliases are stored as json
+type Aliases []string
+
+func (a Aliases) Value() (driver.Value, error) {
+	if a == nil {
+		return "[]", nil
+	}
+	b, err := json.Marshal([]string(a))
+	return string(b), err
+}
+
+func (a *Aliases) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case string:
+		return json.Unmarshal([]byte(v), a)
+	case []byte:
+		return json.Unmarshal(v, a)
+	case nil:
+		*a = nil
+		return nil
+	default:
+		return fmt.Errorf("cannot scan %T into Aliases", value)
+	}
+}
@@ -0,0 +1,176 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/quangdangfit/gocommon/logger"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"main/internal/specialty/dto"
+	"main/internal/specialty/model"
+	"main/internal/specialty/service"
+	"main/pkg/dbs"
+	pb "main/proto/gen/go/specialty"
+)
+
+type SpecialtyHandler struct {
+	service service.ISpecialtyService
+	pb.UnimplementedSpecialtyServiceServer
+}
+
+func NewSpecialtyHandler(service service.ISpecialtyService) *SpecialtyHandler {
+	return &SpecialtyHandler{service: service}
+}
+
+func (h *SpecialtyHandler) ListSpecialties(ctx context.Context, req *pb.ListSpecialtiesReq) (*pb.ListSpecialtiesRes, error) {
+	specialties, err := h.service.ListSpecialties(ctx)
+	if err != nil {
+		logger.Error("Failed to list specialties ", err)
+		return nil, specialtyError(err)
+	}
+
+	res := pb.ListSpecialtiesRes{}
+	for _, specialty := range dto.Tree(specialties, locale(req.Locale), "") {
+		res.Specialties = append(res.Specialties, toPb(specialty))
+	}
+	return &res, nil
+}
+
+func (h *SpecialtyHandler) GetSpecialty(ctx context.Context, req *pb.GetSpecialtyReq) (*pb.SpecialtyRes, error) {
+	specialty, specialties, err := h.service.GetSpecialty(ctx, req.Slug)
+	if err != nil {
+		logger.Error("Failed to get specialty ", err)
+		return nil, specialtyError(err)
+	}
+
+	tree := dto.Tree(specialties, locale(req.Locale), specialty.ID)
+	return &pb.SpecialtyRes{Specialty: toPb(tree[0])}, nil
+}
+
+func (h *SpecialtyHandler) CreateSpecialty(ctx context.Context, req *pb.CreateSpecialtyReq) (*pb.SpecialtyRes, error) {
+	specialty, err := h.service.Create(ctx, &dto.CreateSpecialtyReq{
+		ParentID: parentID(req.ParentId),
+		Slug:     req.Slug,
+		Names:    req.Names,
+		Icon:     req.Icon,
+		Aliases:  req.Aliases,
+	})
+	if err != nil {
+		logger.Error("Failed to create specialty ", err)
+		return nil, specialtyError(err)
+	}
+
+	return &pb.SpecialtyRes{Specialty: toPb(dto.NewSpecialty(specialty, model.DefaultLocale))}, nil
+}
+
+func (h *SpecialtyHandler) UpdateSpecialty(ctx context.Context, req *pb.UpdateSpecialtyReq) (*pb.SpecialtyRes, error) {
+	specialty, err := h.service.Update(ctx, req.Id, &dto.UpdateSpecialtyReq{
+		CreateSpecialtyReq: dto.CreateSpecialtyReq{
+			ParentID: parentID(req.ParentId),
+			Slug:     req.Slug,
+			Names:    req.Names,
+			Icon:     req.Icon,
+			Aliases:  req.Aliases,
+		},
+		Version: req.Version,
+	})
+	if err != nil {
+		logger.Error("Failed to update specialty ", err)
+		return nil, specialtyError(err)
+	}
+
+	return &pb.SpecialtyRes{Specialty: toPb(dto.NewSpecialty(specialty, model.DefaultLocale))}, nil
+}
+
+func (h *SpecialtyHandler) DeleteSpecialty(ctx context.Context, req *pb.DeleteSpecialtyReq) (*pb.DeleteSpecialtyRes, error) {
+	if err := h.service.Delete(ctx, req.Id, req.Version); err != nil {
+		logger.Error("Failed to delete specialty ", err)
+		return nil, specialtyError(err)
+	}
+
+	return &pb.DeleteSpecialtyRes{}, nil
+}
+
+func (h *SpecialtyHandler) SetDoctorSpecialties(ctx context.Context, req *pb.SetDoctorSpecialtiesReq) (*pb.SetDoctorSpecialtiesRes, error) {
+	var body dto.SetDoctorSpecialtiesReq
+	for _, link := range req.Specialties {
+		body.Specialties = append(body.Specialties, dto.DoctorSpecialty{SpecialtyID: link.SpecialtyId, Primary: link.Primary})
+	}
+
+	if err := h.service.SetDoctorSpecialties(ctx, req.DoctorId, &body); err != nil {
+		logger.Error("Failed to set doctor specialties ", err)
+		return nil, specialtyError(err)
+	}
+
+	return &pb.SetDoctorSpecialtiesRes{}, nil
+}
+
+func (h *SpecialtyHandler) MigrateSpecialists(ctx context.Context, req *pb.MigrateSpecialistsReq) (*pb.MigrationReport, error) {
+	report, err := h.service.MigrateSpecialists(ctx)
+	if err != nil {
+		logger.Error("Failed to migrate specialists ", err)
+		return nil, specialtyError(err)
+	}
+
+	res := pb.MigrationReport{Mapped: int32(report.Mapped), Unmatched: map[string]int32{}}
+	for value, doctors := range report.Unmatched {
+		res.Unmatched[value] = int32(doctors)
+	}
+	return &res, nil
+}
+
+func toPb(specialty *dto.Specialty) *pb.Specialty {
+	res := &pb.Specialty{
+		Id:        specialty.ID,
+		Slug:      specialty.Slug,
+		Name:      specialty.Name,
+		Names:     specialty.Names,
+		Icon:      specialty.Icon,
+		Aliases:   specialty.Aliases,
+		CreatedAt: specialty.CreatedAt.Format(time.RFC3339),
+		Version:   specialty.Version,
+	}
+	if specialty.ParentID != nil {
+		res.ParentId = *specialty.ParentID
+	}
+	for _, child := range specialty.Children {
+		res.Children = append(res.Children, toPb(child))
+	}
+	return res
+}
+
+func locale(locale string) string {
+	if locale == "" {
+		return model.DefaultLocale
+	}
+	return locale
+}
+
+// parentID is nil for the root specialties
+func parentID(id string) *string {
+	if id == "" {
+		return nil
+	}
+	return &id
+}
+
+func specialtyError(err error) error {
+	switch {
+	case errors.Is(err, service.ErrSpecialtyNotFound), errors.Is(err, service.ErrDoctorNotFound):
+		return status.New(codes.NotFound, err.Error()).Err()
+	case errors.Is(err, service.ErrSlugTaken):
+		return status.New(codes.AlreadyExists, err.Error()).Err()
+	case errors.Is(err, service.ErrSpecialtyInUse):
+		return status.New(codes.FailedPrecondition, err.Error()).Err()
+	case errors.Is(err, dbs.ErrStaleVersion):
+		return status.New(codes.Aborted, err.Error()).Err()
+	case errors.Is(err, service.ErrInvalidSlug), errors.Is(err, service.ErrDefaultName), errors.Is(err, service.ErrInvalidParent),
+		errors.Is(err, service.ErrPrimary), errors.Is(err, service.ErrDuplicate):
+		return status.New(codes.InvalidArgument, err.Error()).Err()
+	default:
+		return err
+	}
+}
@@ -0,0 +1,21 @@
+package grpc
+
+import (
+	"github.com/quangdangfit/gocommon/validation"
+	"google.golang.org/grpc"
+
+	doctorRepository "main/internal/doctor/repository"
+	"main/internal/specialty/repository"
+	"main/internal/specialty/service"
+	"main/pkg/dbs"
+	"main/pkg/events"
+	pb "main/proto/gen/go/specialty"
+)
+
+func RegisterHandlers(svr *grpc.Server, db dbs.IDatabase, validator validation.Validation, publisher events.Publisher) {
+	specialtyRepo := repository.NewSpecialtyRepository(db)
+	specialtySvc := service.NewSpecialtyService(validator, specialtyRepo, doctorRepository.NewDoctorRepository(db), publisher)
+	specialtyHandler := NewSpecialtyHandler(specialtySvc)
+
+	pb.RegisterSpecialtyServiceServer(svr, specialtyHandler)
+}
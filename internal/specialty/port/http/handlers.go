@@ -0,0 +1,287 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/quangdangfit/gocommon/logger"
+
+	"main/internal/specialty/dto"
+	"main/internal/specialty/model"
+	"main/internal/specialty/service"
+	"main/pkg/config"
+	"main/pkg/dbs"
+	"main/pkg/redis"
+	"main/pkg/response"
+	"main/pkg/tenant"
+)
+
+type SpecialtyHandler struct {
+	cache   redis.IRedis
+	service service.ISpecialtyService
+}
+
+func NewSpecialtyHandler(
+	cache redis.IRedis,
+	service service.ISpecialtyService,
+) *SpecialtyHandler {
+	return &SpecialtyHandler{
+		cache:   cache,
+		service: service,
+	}
+}
+
+// ListSpecialties godoc
+//
+//	@Summary	List the specialties catalogue as a tree
+//	@Tags		Specialty
+//	@Produce	json
+//	@Param		locale	query		string	false	"Locale of the names, Accept-Language by default"
+//	@Success	200		{object}	dto.ListSpecialtiesRes
+//	@Router		/specialties [get]
+func (h *SpecialtyHandler) ListSpecialties(c *gin.Context) {
+	var req dto.ListSpecialtiesReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.Error("Failed to get query params", err)
+		response.Error(c, http.StatusBadRequest, err, "Invalid parameters")
+		return
+	}
+
+	locale := requestLocale(c, req.Locale)
+	var res dto.ListSpecialtiesRes
+	cacheKey := tenant.CachePrefix + "specialties_" + locale
+	if err := h.cache.Get(c, cacheKey, &res); err == nil {
+		response.JSON(c, http.StatusOK, res)
+		return
+	}
+
+	specialties, err := h.service.ListSpecialties(c)
+	if err != nil {
+		logger.Error("Failed to list specialties ", err)
+		response.Error(c, http.StatusInternalServerError, err, "Something went wrong")
+		return
+	}
+
+	res.Specialties = dto.Tree(specialties, locale, "")
+	response.JSON(c, http.StatusOK, res)
+	_ = h.cache.SetWithExpiration(c, cacheKey, res, config.SpecialtyCachingTime)
+}
+
+// GetSpecialty godoc
+//
+//	@Summary	Get a specialty with its children
+//	@Tags		Specialty
+//	@Produce	json
+//	@Param		slug	path		string	true	"Slug"
+//	@Param		locale	query		string	false	"Locale of the names, Accept-Language by default"
+//	@Success	200		{object}	dto.Specialty
+//	@Router		/specialties/{slug} [get]
+func (h *SpecialtyHandler) GetSpecialty(c *gin.Context) {
+	var req dto.ListSpecialtiesReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.Error("Failed to get query params", err)
+		response.Error(c, http.StatusBadRequest, err, "Invalid parameters")
+		return
+	}
+
+	specialty, specialties, err := h.service.GetSpecialty(c, c.Param("slug"))
+	if err != nil {
+		logger.Error("Failed to get specialty ", err)
+		specialtyError(c, err)
+		return
+	}
+
+	response.JSON(c, http.StatusOK, dto.Tree(specialties, requestLocale(c, req.Locale), specialty.ID)[0])
+}
+
+// CreateSpecialty godoc
+//
+//	@Summary	Add a specialty to the catalogue
+//	@Tags		Specialty-admin
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		_	body		dto.CreateSpecialtyReq	true	"Body"
+//	@Success	200	{object}	dto.Specialty
+//	@Router		/specialty-admin/specialties [post]
+func (h *SpecialtyHandler) CreateSpecialty(c *gin.Context) {
+	var req dto.CreateSpecialtyReq
+	if err := c.ShouldBindJSON(&req); c.Request.Body == nil || err != nil {
+		logger.Error("Failed to get body", err)
+		response.Error(c, http.StatusBadRequest, err, "Invalid parameters")
+		return
+	}
+
+	specialty, err := h.service.Create(c, &req)
+	if err != nil {
+		logger.Error("Failed to create specialty ", err)
+		specialtyError(c, err)
+		return
+	}
+
+	response.JSON(c, http.StatusOK, dto.NewSpecialty(specialty, model.DefaultLocale))
+}
+
+// UpdateSpecialty godoc
+//
+//	@Summary	Replace a specialty
+//	@Tags		Specialty-admin
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		id			path		string					true	"Specialty ID"
+//	@Param		If-Match	header		string					true	"ETag of the specialty, its version"
+//	@Param		_			body		dto.UpdateSpecialtyReq	true	"Body"
+//	@Success	200			{object}	dto.Specialty
+//	@Failure	412			{object}	response.Response	"Changed since it was read"
+//	@Router		/specialty-admin/specialties/{id} [put]
+func (h *SpecialtyHandler) UpdateSpecialty(c *gin.Context) {
+	version, ok := response.IfMatch(c)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateSpecialtyReq
+	if err := c.ShouldBindJSON(&req); c.Request.Body == nil || err != nil {
+		logger.Error("Failed to get body", err)
+		response.Error(c, http.StatusBadRequest, err, "Invalid parameters")
+		return
+	}
+
+	req.Version = version
+	specialty, err := h.service.Update(c, c.Param("id"), &req)
+	if err != nil {
+		logger.Error("Failed to update specialty ", err)
+		specialtyError(c, err)
+		return
+	}
+
+	response.Versioned(c, http.StatusOK, specialty.Version, dto.NewSpecialty(specialty, model.DefaultLocale))
+}
+
+// DeleteSpecialty godoc
+//
+//	@Summary	Delete a specialty without children nor doctors
+//	@Tags		Specialty-admin
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		id			path	string	true	"Specialty ID"
+//	@Param		If-Match	header	string	true	"ETag of the specialty, its version"
+//	@Success	200
+//	@Failure	412	{object}	response.Response	"Changed since it was read"
+//	@Router		/specialty-admin/specialties/{id} [delete]
+func (h *SpecialtyHandler) DeleteSpecialty(c *gin.Context) {
+	version, ok := response.IfMatch(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(c, c.Param("id"), version); err != nil {
+		logger.Error("Failed to delete specialty ", err)
+		specialtyError(c, err)
+		return
+	}
+
+	response.JSON(c, http.StatusOK, nil)
+}
+
+// SetDoctorSpecialties godoc
+//
+//	@Summary	Replace the specialties of a doctor
+//	@Tags		Specialty-admin
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		id	path	string						true	"Doctor ID"
+//	@Param		_	body	dto.SetDoctorSpecialtiesReq	true	"Body"
+//	@Success	200
+//	@Router		/specialty-admin/doctors/{id}/specialties [put]
+func (h *SpecialtyHandler) SetDoctorSpecialties(c *gin.Context) {
+	var req dto.SetDoctorSpecialtiesReq
+	if err := c.ShouldBindJSON(&req); c.Request.Body == nil || err != nil {
+		logger.Error("Failed to get body", err)
+		response.Error(c, http.StatusBadRequest, err, "Invalid parameters")
+		return
+	}
+
+	if err := h.service.SetDoctorSpecialties(c, c.Param("id"), &req); err != nil {
+		logger.Error("Failed to set doctor specialties ", err)
+		specialtyError(c, err)
+		return
+	}
+
+	response.JSON(c, http.StatusOK, nil)
+}
+
+// SetOwnSpecialties godoc
+//
+//	@Summary	Replace the specialties of the signed-in doctor
+//	@Tags		Specialty
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		_	body	dto.SetDoctorSpecialtiesReq	true	"Body"
+//	@Success	200
+//	@Router		/doctor/specialties [put]
+func (h *SpecialtyHandler) SetOwnSpecialties(c *gin.Context) {
+	var req dto.SetDoctorSpecialtiesReq
+	if err := c.ShouldBindJSON(&req); c.Request.Body == nil || err != nil {
+		logger.Error("Failed to get body", err)
+		response.Error(c, http.StatusBadRequest, err, "Invalid parameters")
+		return
+	}
+
+	if err := h.service.SetOwnSpecialties(c, c.GetString("userId"), &req); err != nil {
+		logger.Error("Failed to set specialties ", err)
+		specialtyError(c, err)
+		return
+	}
+
+	response.JSON(c, http.StatusOK, nil)
+}
+
+// MigrateSpecialists godoc
+//
+//	@Summary	Map the free-text specialities of doctors to the catalogue
+//	@Tags		Specialty-admin
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Success	200	{object}	dto.MigrationReport
+//	@Router		/specialty-admin/migrate [post]
+func (h *SpecialtyHandler) MigrateSpecialists(c *gin.Context) {
+	report, err := h.service.MigrateSpecialists(c)
+	if err != nil {
+		logger.Error("Failed to migrate specialists ", err)
+		specialtyError(c, err)
+		return
+	}
+
+	response.JSON(c, http.StatusOK, report)
+}
+
+// requestLocale is the locale parameter, else the first language of the
+// Accept-Language header
+func requestLocale(c *gin.Context, locale string) string {
+	if locale != "" {
+		return strings.ToLower(locale)
+	}
+	language := strings.Split(c.GetHeader("Accept-Language"), ",")[0]
+	language = strings.TrimSpace(strings.Split(language, ";")[0])
+	if language == "" || language == "*" {
+		return model.DefaultLocale
+	}
+	return strings.ToLower(strings.Split(language, "-")[0])
+}
+
+func specialtyError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrSpecialtyNotFound):
+		response.Error(c, http.StatusNotFound, err, "Specialty not found")
+	case errors.Is(err, service.ErrDoctorNotFound):
+		response.Error(c, http.StatusNotFound, err, "Doctor not found")
+	case errors.Is(err, service.ErrSlugTaken), errors.Is(err, service.ErrSpecialtyInUse):
+		response.Error(c, http.StatusConflict, err, err.Error())
+	case errors.Is(err, dbs.ErrStaleVersion):
+		response.StaleVersion(c, err)
+	default:
+		response.Error(c, http.StatusBadRequest, err, err.Error())
+	}
+}
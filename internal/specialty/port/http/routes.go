@@ -0,0 +1,41 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/quangdangfit/gocommon/validation"
+
+	doctorRepository "main/internal/doctor/repository"
+	"main/internal/specialty/repository"
+	"main/internal/specialty/service"
+	"main/pkg/dbs"
+	"main/pkg/events"
+	"main/pkg/middleware"
+	"main/pkg/rbac"
+	"main/pkg/redis"
+)
+
+func Routes(r *gin.RouterGroup, sqlDB dbs.IDatabase, validator validation.Validation, cache redis.IRedis, auth *middleware.Authenticator, publisher events.Publisher) {
+	specialtyRepo := repository.NewSpecialtyRepository(sqlDB)
+	specialtySvc := service.NewSpecialtyService(validator, specialtyRepo, doctorRepository.NewDoctorRepository(sqlDB), publisher)
+	specialtyHandler := NewSpecialtyHandler(cache, specialtySvc)
+
+	userAuthMiddleware := middleware.JWTAuth(auth)
+	specialtiesWrite := middleware.JWTPermission(auth, rbac.SpecialtiesWrite)
+	specialtyRoute := r.Group("/specialties")
+	{
+		specialtyRoute.GET("", specialtyHandler.ListSpecialties)
+		specialtyRoute.GET("/:slug", specialtyHandler.GetSpecialty)
+	}
+
+	// specialties of the signed-in doctor
+	r.Group("/doctor").PUT("/specialties", userAuthMiddleware, specialtyHandler.SetOwnSpecialties)
+
+	specialtyRouteAdmin := r.Group("/specialty-admin")
+	{
+		specialtyRouteAdmin.POST("/specialties", specialtiesWrite, specialtyHandler.CreateSpecialty)
+		specialtyRouteAdmin.PUT("/specialties/:id", specialtiesWrite, specialtyHandler.UpdateSpecialty)
+		specialtyRouteAdmin.DELETE("/specialties/:id", specialtiesWrite, specialtyHandler.DeleteSpecialty)
+		specialtyRouteAdmin.PUT("/doctors/:id/specialties", specialtiesWrite, specialtyHandler.SetDoctorSpecialties)
+		specialtyRouteAdmin.POST("/migrate", specialtiesWrite, specialtyHandler.MigrateSpecialists)
+	}
+}
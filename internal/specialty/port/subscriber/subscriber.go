@@ -0,0 +1,16 @@
+package subscriber
+
+import (
+	"context"
+
+	"main/pkg/events"
+	"main/pkg/redis"
+	"main/pkg/tenant"
+)
+
+// Subscribe removes the cached catalogues when a specialty changes
+func Subscribe(ctx context.Context, bus *events.Bus, cache redis.IRedis) error {
+	return bus.Subscribe(ctx, "specialty-cache", func(ctx context.Context, _ *events.Event) error {
+		return cache.RemovePattern(ctx, tenant.CachePrefix+"*specialties*")
+	}, events.SpecialtyChanged)
+}
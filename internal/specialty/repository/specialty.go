@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	doctorModel "main/internal/doctor/model"
+	"main/internal/specialty/model"
+	"main/pkg/dbs"
+)
+
+//go:generate mockery --name=ISpecialtyRepository
+type ISpecialtyRepository interface {
+	Create(ctx context.Context, specialty *model.Specialty) error
+	Update(ctx context.Context, specialty *model.Specialty) error
+	Delete(ctx context.Context, id string, version int64) (bool, error)
+	GetSpecialtyByID(ctx context.Context, id string) (*model.Specialty, error)
+	GetSpecialtyBySlug(ctx context.Context, slug string) (*model.Specialty, error)
+	ListSpecialties(ctx context.Context) ([]*model.Specialty, error)
+	SetDoctorSpecialties(ctx context.Context, doctorID string, links []*model.DoctorSpecialty, specialist string) error
+	ListUnmappedDoctors(ctx context.Context) ([]*doctorModel.Doctor, error)
+}
+
+type SpecialtyRepo struct {
+	db dbs.IDatabase
+}
+
+func NewSpecialtyRepository(db dbs.IDatabase) *SpecialtyRepo {
+	return &SpecialtyRepo{db: db}
+}
+
+func (r *SpecialtyRepo) Create(ctx context.Context, specialty *model.Specialty) error {
+	return r.db.GetDB().WithContext(ctx).Create(specialty).Error
+}
+
+// Update saves the specialty if it is still at its version
+func (r *SpecialtyRepo) Update(ctx context.Context, specialty *model.Specialty) error {
+	return dbs.SaveVersion(r.db.GetDB().WithContext(ctx), specialty)
+}
+
+// Delete removes a specialty without children nor doctors, false otherwise.
+// It returns dbs.ErrStaleVersion when the specialty is no longer at version.
+func (r *SpecialtyRepo) Delete(ctx context.Context, id string, version int64) (bool, error) {
+	deleted := false
+	err := r.db.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var used int64
+		err := tx.Model(&model.Specialty{}).Where("parent_id = ?", id).Count(&used).Error
+		if err != nil || used > 0 {
+			return err
+		}
+		err = tx.Model(&model.DoctorSpecialty{}).Where("specialty_id = ?", id).Count(&used).Error
+		if err != nil || used > 0 {
+			return err
+		}
+		if err := dbs.DeleteVersion(tx, &model.Specialty{ID: id}, version); err != nil {
+			return err
+		}
+		deleted = true
+		return nil
+	})
+	return deleted, err
+}
+
+func (r *SpecialtyRepo) GetSpecialtyByID(ctx context.Context, id string) (*model.Specialty, error) {
+	var specialty model.Specialty
+	if err := r.db.GetDB().WithContext(ctx).Where("id = ?", id).First(&specialty).Error; err != nil {
+		return nil, err
+	}
+	return &specialty, nil
+}
+
+func (r *SpecialtyRepo) GetSpecialtyBySlug(ctx context.Context, slug string) (*model.Specialty, error) {
+	var specialty model.Specialty
+	if err := r.db.GetDB().WithContext(ctx).Where("slug = ?", slug).First(&specialty).Error; err != nil {
+		return nil, err
+	}
+	return &specialty, nil
+}
+
+// ListSpecialties lists the whole catalogue, which is small
+func (r *SpecialtyRepo) ListSpecialties(ctx context.Context) ([]*model.Specialty, error) {
+	var specialties []*model.Specialty
+	if err := r.db.GetDB().WithContext(ctx).Order("slug").Find(&specialties).Error; err != nil {
+		return nil, err
+	}
+	return specialties, nil
+}
+
+// SetDoctorSpecialties replaces the specialties of the doctor, specialist is
+// kept in the free-text field for the clients not reading the specialties
+func (r *SpecialtyRepo) SetDoctorSpecialties(ctx context.Context, doctorID string, links []*model.DoctorSpecialty, specialist string) error {
+	return r.db.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("doctor_id = ?", doctorID).Delete(&model.DoctorSpecialty{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Omit("Specialty").Create(links).Error; err != nil {
+			return err
+		}
+		return tx.Model(&doctorModel.Doctor{}).
+			Where("id = ?", doctorID).
+			Update("specalist", specialist).Error
+	})
+}
+
+// ListUnmappedDoctors lists the doctors with a free-text speciality but no
+// specialty of the catalogue
+func (r *SpecialtyRepo) ListUnmappedDoctors(ctx context.Context) ([]*doctorModel.Doctor, error) {
+	var doctors []*doctorModel.Doctor
+	err := r.db.GetDB().WithContext(ctx).
+		Select("id", "specalist").
+		Where("TRIM(COALESCE(specalist, '')) <> ''").
+		Where("NOT EXISTS (SELECT 1 FROM doctor_specialties ds WHERE ds.doctor_id = doctors.id)").
+		Find(&doctors).Error
+	if err != nil {
+		return nil, err
+	}
+	return doctors, nil
+}
@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"unicode"
+
+	"github.com/quangdangfit/gocommon/logger"
+
+	"main/internal/specialty/dto"
+	"main/internal/specialty/model"
+)
+
+// suffixes are stripped so the specialty and its practitioner match,
+// "cardiology" and "cardiologist" both become "cardiolog"
+var suffixes = []string{"ists", "ist", "ians", "ian", "ics", "ic", "y", "s"}
+
+// maxTypos is the edit distance tolerated between long enough words
+const maxTypos = 2
+
+// MigrateSpecialists links the doctors without specialties to the specialty
+// matching their free-text speciality, as primary. The values matching no or
+// several specialties are reported for the catalogue to be completed, with
+// aliases, and the migration run again.
+func (s *SpecialtyService) MigrateSpecialists(ctx context.Context) (*dto.MigrationReport, error) {
+	specialties, err := s.ListSpecialties(ctx)
+	if err != nil {
+		return nil, err
+	}
+	doctors, err := s.repo.ListUnmappedDoctors(ctx)
+	if err != nil {
+		logger.Errorf("MigrateSpecialists.ListUnmappedDoctors fail, error: %s", err)
+		return nil, err
+	}
+
+	m := newMatcher(specialties)
+	report := dto.MigrationReport{Unmatched: map[string]int{}}
+	for _, doctor := range doctors {
+		specialty := m.match(doctor.Specalist)
+		if specialty == nil {
+			report.Unmatched[strings.TrimSpace(doctor.Specalist)]++
+			continue
+		}
+
+		req := dto.SetDoctorSpecialtiesReq{Specialties: []dto.DoctorSpecialty{{SpecialtyID: specialty.ID, Primary: true}}}
+		if err := s.setDoctorSpecialties(ctx, doctor, &req); err != nil {
+			return nil, err
+		}
+		report.Mapped++
+	}
+
+	if report.Mapped > 0 || len(report.Unmatched) > 0 {
+		logger.Infof("Mapped %d doctors to specialties, %d values unmatched", report.Mapped, len(report.Unmatched))
+	}
+	return &report, nil
+}
+
+type matcher struct {
+	// terms are the normalized names, aliases and slugs of the specialties
+	terms map[string][]*model.Specialty
+}
+
+func newMatcher(specialties []*model.Specialty) *matcher {
+	m := &matcher{terms: map[string][]*model.Specialty{}}
+	for _, specialty := range specialties {
+		values := []string{strings.ReplaceAll(specialty.Slug, "-", " ")}
+		for _, name := range specialty.Names {
+			values = append(values, name)
+		}
+		values = append(values, specialty.Aliases...)
+
+		for _, value := range values {
+			term := normalize(value)
+			if term != "" && !contains(m.terms[term], specialty) {
+				m.terms[term] = append(m.terms[term], specialty)
+			}
+		}
+	}
+	return m
+}
+
+// match returns the specialty of value, nil when none or several match
+func (m *matcher) match(value string) *model.Specialty {
+	term := normalize(value)
+	if term == "" {
+		return nil
+	}
+	if specialties := m.terms[term]; len(specialties) > 0 {
+		return only(specialties)
+	}
+
+	// typos, only on long words so short ones do not match everything
+	if len([]rune(term)) < 6 {
+		return nil
+	}
+	var found []*model.Specialty
+	best := maxTypos + 1
+	for t, specialties := range m.terms {
+		d := distance(term, t)
+		if d < best {
+			best, found = d, nil
+		}
+		if d == best {
+			for _, specialty := range specialties {
+				if !contains(found, specialty) {
+					found = append(found, specialty)
+				}
+			}
+		}
+	}
+	if best > maxTypos {
+		return nil
+	}
+	return only(found)
+}
+
+// normalize lower cases value, keeps its letters and digits and strips the
+// suffixes of each word
+func normalize(value string) string {
+	words := strings.FieldsFunc(strings.ToLower(value), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, word := range words {
+		for _, suffix := range suffixes {
+			if len(word) > len(suffix)+3 && strings.HasSuffix(word, suffix) {
+				word = strings.TrimSuffix(word, suffix)
+				break
+			}
+		}
+		words[i] = word
+	}
+	return strings.Join(words, " ")
+}
+
+// distance is the Levenshtein distance of a and b
+func distance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
+
+func only(specialties []*model.Specialty) *model.Specialty {
+	if len(specialties) != 1 {
+		return nil
+	}
+	return specialties[0]
+}
+
+func contains(specialties []*model.Specialty, specialty *model.Specialty) bool {
+	for _, s := range specialties {
+		if s == specialty {
+			return true
+		}
+	}
+	return false
+}
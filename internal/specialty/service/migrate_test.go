@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/quangdangfit/gocommon/logger"
+	"github.com/quangdangfit/gocommon/validation"
+	"gorm.io/gorm"
+
+	doctorModel "main/internal/doctor/model"
+	"main/internal/specialty/model"
+	"main/internal/specialty/repository"
+	"main/pkg/config"
+	"main/pkg/events"
+)
+
+func TestMain(m *testing.M) {
+	logger.Initialize(config.ProductionEnv)
+	os.Exit(m.Run())
+}
+
+func catalogue() []*model.Specialty {
+	return []*model.Specialty{
+		{ID: "cardiology", Slug: "cardiology", Names: model.Names{model.DefaultLocale: "Cardiology", "fr": "Cardiologie"}, Aliases: model.Aliases{"heart doctor"}},
+		{ID: "dermatology", Slug: "dermatology", Names: model.Names{model.DefaultLocale: "Dermatology"}},
+		{ID: "general-surgery", Slug: "general-surgery", Names: model.Names{model.DefaultLocale: "General surgery"}, Aliases: model.Aliases{"surgeon"}},
+		{ID: "plastic-surgery", Slug: "plastic-surgery", Names: model.Names{model.DefaultLocale: "Plastic surgery"}, Aliases: model.Aliases{"surgeon"}},
+		{ID: "ent", Slug: "ent", Names: model.Names{model.DefaultLocale: "ENT"}},
+	}
+}
+
+func TestMatch(t *testing.T) {
+	m := newMatcher(catalogue())
+
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"Cardiology", "cardiology"},
+		{"  CARDIOLOGY ", "cardiology"},
+		{"Cardiologist", "cardiology"},
+		{"cardiologists", "cardiology"},
+		{"Cardiologie", "cardiology"},
+		{"Heart-doctor", "cardiology"},
+		{"Cardiolgy", "cardiology"},
+		{"Dermatologist", "dermatology"},
+		{"general surgery", "general-surgery"},
+		{"Plastic Surgeon", "plastic-surgery"},
+		{"ent", "ent"},
+		// typos are only tolerated on long words
+		{"ant", ""},
+		// an alias of several specialties matches none
+		{"Surgeon", ""},
+		{"Dentist", ""},
+		{"Neurology", ""},
+		{"", ""},
+		{"--", ""},
+	}
+	for _, tt := range tests {
+		got := ""
+		if specialty := m.match(tt.value); specialty != nil {
+			got = specialty.ID
+		}
+		if got != tt.want {
+			t.Errorf("match(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"Cardiology", "cardiolog"},
+		{"Cardiologist", "cardiolog"},
+		{"Pediatrician", "pediatric"},
+		{"General  Surgery!", "general surger"},
+		{"ENT", "ent"},
+		{"Ear, nose & throat", "ear nose throat"},
+	}
+	for _, tt := range tests {
+		if got := normalize(tt.value); got != tt.want {
+			t.Errorf("normalize(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+// specialtyRepo serves the catalogue and records the specialties set
+type specialtyRepo struct {
+	repository.ISpecialtyRepository
+	specialties []*model.Specialty
+	doctors     []*doctorModel.Doctor
+	links       map[string][]*model.DoctorSpecialty
+	specialists map[string]string
+}
+
+func (r *specialtyRepo) ListSpecialties(ctx context.Context) ([]*model.Specialty, error) {
+	return r.specialties, nil
+}
+
+func (r *specialtyRepo) GetSpecialtyByID(ctx context.Context, id string) (*model.Specialty, error) {
+	for _, specialty := range r.specialties {
+		if specialty.ID == id {
+			return specialty, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *specialtyRepo) ListUnmappedDoctors(ctx context.Context) ([]*doctorModel.Doctor, error) {
+	var unmapped []*doctorModel.Doctor
+	for _, doctor := range r.doctors {
+		if len(r.links[doctor.ID]) == 0 {
+			unmapped = append(unmapped, doctor)
+		}
+	}
+	return unmapped, nil
+}
+
+func (r *specialtyRepo) SetDoctorSpecialties(ctx context.Context, doctorID string, links []*model.DoctorSpecialty, specialist string) error {
+	r.links[doctorID] = links
+	r.specialists[doctorID] = specialist
+	return nil
+}
+
+// published counts the events of the service by subject
+type published map[string]int
+
+func (p published) Publish(ctx context.Context, event *events.Event) {
+	if event.Name == events.DoctorUpdated {
+		p[event.SubjectID]++
+	}
+}
+
+func TestMigrateSpecialists(t *testing.T) {
+	repo := &specialtyRepo{
+		specialties: catalogue(),
+		doctors: []*doctorModel.Doctor{
+			{ID: "ada", Specalist: "Cardiologist"},
+			{ID: "bob", Specalist: "dermatology"},
+			{ID: "cy", Specalist: "Surgeon"},
+			{ID: "di", Specalist: " Dentist"},
+			{ID: "ed", Specalist: "Dentist "},
+			{ID: "fay", Specalist: ""},
+		},
+		links:       map[string][]*model.DoctorSpecialty{},
+		specialists: map[string]string{},
+	}
+	sent := published{}
+	svc := NewSpecialtyService(validation.New(), repo, nil, sent)
+	ctx := context.Background()
+
+	report, err := svc.MigrateSpecialists(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Mapped != 2 {
+		t.Errorf("mapped %d doctors, want 2", report.Mapped)
+	}
+	if len(report.Unmatched) != 3 || report.Unmatched["Surgeon"] != 1 || report.Unmatched["Dentist"] != 2 || report.Unmatched[""] != 1 {
+		t.Errorf("unmatched = %v", report.Unmatched)
+	}
+
+	links := repo.links["ada"]
+	if len(links) != 1 || links[0].SpecialtyID != "cardiology" || !links[0].Primary {
+		t.Errorf("links of ada = %+v", links)
+	}
+	if repo.specialists["ada"] != "Cardiology" || repo.specialists["bob"] != "Dermatology" {
+		t.Errorf("specialists = %v", repo.specialists)
+	}
+	if len(sent) != 2 || sent["ada"] != 1 || sent["bob"] != 1 {
+		t.Errorf("events = %v", sent)
+	}
+
+	// once the catalogue is completed, the migration maps the rest only
+	repo.specialties = append(repo.specialties, &model.Specialty{ID: "dentistry", Slug: "dentistry", Names: model.Names{model.DefaultLocale: "Dentistry"}, Aliases: model.Aliases{"dentist"}})
+	report, err = svc.MigrateSpecialists(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Mapped != 2 || len(report.Unmatched) != 2 || sent["ada"] != 1 {
+		t.Errorf("second run mapped %d, unmatched %v, events %v", report.Mapped, report.Unmatched, sent)
+	}
+	if links := repo.links["di"]; len(links) != 1 || links[0].SpecialtyID != "dentistry" {
+		t.Errorf("links of di = %+v", links)
+	}
+}
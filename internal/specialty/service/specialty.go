@@ -0,0 +1,296 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"time"
+
+	"github.com/quangdangfit/gocommon/logger"
+	"github.com/quangdangfit/gocommon/validation"
+	"gorm.io/gorm"
+
+	doctorModel "main/internal/doctor/model"
+	"main/internal/specialty/dto"
+	"main/internal/specialty/model"
+	"main/internal/specialty/repository"
+	"main/pkg/events"
+)
+
+var (
+	ErrSpecialtyNotFound = errors.New("specialty not found")
+	ErrDoctorNotFound    = errors.New("doctor not found")
+	ErrSlugTaken         = errors.New("slug already used by another specialty")
+	ErrInvalidSlug       = errors.New("slug must be lower case words separated by dashes")
+	ErrDefaultName       = errors.New("a name in " + model.DefaultLocale + " is required")
+	ErrInvalidParent     = errors.New("parent cannot be the specialty or one of its children")
+	ErrSpecialtyInUse    = errors.New("specialty has children or doctors")
+	ErrPrimary           = errors.New("exactly one specialty must be primary")
+	ErrDuplicate         = errors.New("specialty listed twice")
+)
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+//go:generate mockery --name=ISpecialtyService
+type ISpecialtyService interface {
+	Create(ctx context.Context, req *dto.CreateSpecialtyReq) (*model.Specialty, error)
+	Update(ctx context.Context, id string, req *dto.UpdateSpecialtyReq) (*model.Specialty, error)
+	Delete(ctx context.Context, id string, version int64) error
+	GetSpecialty(ctx context.Context, slug string) (*model.Specialty, []*model.Specialty, error)
+	ListSpecialties(ctx context.Context) ([]*model.Specialty, error)
+	SetDoctorSpecialties(ctx context.Context, doctorID string, req *dto.SetDoctorSpecialtiesReq) error
+	SetOwnSpecialties(ctx context.Context, userID string, req *dto.SetDoctorSpecialtiesReq) error
+	MigrateSpecialists(ctx context.Context) (*dto.MigrationReport, error)
+}
+
+// Doctors finds the doctors specialties are linked to
+type Doctors interface {
+	GetDoctorByID(ctx context.Context, id string) (*doctorModel.Doctor, error)
+	GetDoctorByUserID(ctx context.Context, userID string) (*doctorModel.Doctor, error)
+}
+
+type SpecialtyService struct {
+	validator validation.Validation
+	repo      repository.ISpecialtyRepository
+	doctors   Doctors
+	publisher events.Publisher
+}
+
+func NewSpecialtyService(
+	validator validation.Validation,
+	repo repository.ISpecialtyRepository,
+	doctors Doctors,
+	publisher events.Publisher,
+) *SpecialtyService {
+	return &SpecialtyService{
+		validator: validator,
+		repo:      repo,
+		doctors:   doctors,
+		publisher: publisher,
+	}
+}
+
+func (s *SpecialtyService) Create(ctx context.Context, req *dto.CreateSpecialtyReq) (*model.Specialty, error) {
+	specialty := model.Specialty{}
+	if err := s.apply(ctx, &specialty, req); err != nil {
+		return nil, err
+	}
+
+	specialty.BeforeCreate()
+	if err := s.repo.Create(ctx, &specialty); err != nil {
+		logger.Errorf("Create fail, slug: %s, error: %s", specialty.Slug, err)
+		return nil, err
+	}
+	s.publish(ctx, specialty.ID, events.ActionCreated)
+
+	return &specialty, nil
+}
+
+func (s *SpecialtyService) Update(ctx context.Context, id string, req *dto.UpdateSpecialtyReq) (*model.Specialty, error) {
+	specialty, err := s.specialty(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.apply(ctx, specialty, &req.CreateSpecialtyReq); err != nil {
+		return nil, err
+	}
+
+	specialty.Version = req.Version
+	specialty.UpdatedAt = time.Now()
+	if err := s.repo.Update(ctx, specialty); err != nil {
+		logger.Errorf("Update fail, id: %s, error: %s", id, err)
+		return nil, err
+	}
+	s.publish(ctx, id, events.ActionUpdated)
+
+	return specialty, nil
+}
+
+// Delete removes a specialty without children nor doctors, if it is still at
+// version
+func (s *SpecialtyService) Delete(ctx context.Context, id string, version int64) error {
+	if _, err := s.specialty(ctx, id); err != nil {
+		return err
+	}
+
+	deleted, err := s.repo.Delete(ctx, id, version)
+	if err != nil {
+		logger.Errorf("Delete fail, id: %s, error: %s", id, err)
+		return err
+	}
+	if !deleted {
+		return ErrSpecialtyInUse
+	}
+	s.publish(ctx, id, events.ActionDeleted)
+	return nil
+}
+
+// GetSpecialty returns the specialty of slug with the whole catalogue to
+// build its children
+func (s *SpecialtyService) GetSpecialty(ctx context.Context, slug string) (*model.Specialty, []*model.Specialty, error) {
+	specialty, err := s.repo.GetSpecialtyBySlug(ctx, slug)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil, ErrSpecialtyNotFound
+	}
+	if err != nil {
+		logger.Errorf("GetSpecialtyBySlug fail, slug: %s, error: %s", slug, err)
+		return nil, nil, err
+	}
+
+	specialties, err := s.ListSpecialties(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return specialty, specialties, nil
+}
+
+func (s *SpecialtyService) ListSpecialties(ctx context.Context) ([]*model.Specialty, error) {
+	specialties, err := s.repo.ListSpecialties(ctx)
+	if err != nil {
+		logger.Errorf("ListSpecialties fail, error: %s", err)
+		return nil, err
+	}
+	return specialties, nil
+}
+
+// SetDoctorSpecialties replaces the specialties of the doctor
+func (s *SpecialtyService) SetDoctorSpecialties(ctx context.Context, doctorID string, req *dto.SetDoctorSpecialtiesReq) error {
+	doctor, err := s.doctors.GetDoctorByID(ctx, doctorID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrDoctorNotFound
+	}
+	if err != nil {
+		logger.Errorf("SetDoctorSpecialties.GetDoctorByID fail, id: %s, error: %s", doctorID, err)
+		return err
+	}
+
+	return s.setDoctorSpecialties(ctx, doctor, req)
+}
+
+// SetOwnSpecialties replaces the specialties of the doctor profile of userID
+func (s *SpecialtyService) SetOwnSpecialties(ctx context.Context, userID string, req *dto.SetDoctorSpecialtiesReq) error {
+	doctor, err := s.doctors.GetDoctorByUserID(ctx, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrDoctorNotFound
+	}
+	if err != nil {
+		logger.Errorf("SetOwnSpecialties.GetDoctorByUserID fail, user: %s, error: %s", userID, err)
+		return err
+	}
+
+	return s.setDoctorSpecialties(ctx, doctor, req)
+}
+
+func (s *SpecialtyService) setDoctorSpecialties(ctx context.Context, doctor *doctorModel.Doctor, req *dto.SetDoctorSpecialtiesReq) error {
+	if err := s.validator.ValidateStruct(req); err != nil {
+		return err
+	}
+
+	var (
+		links     []*model.DoctorSpecialty
+		primary   *model.Specialty
+		primaries int
+		seen      = map[string]bool{}
+	)
+	for _, link := range req.Specialties {
+		if seen[link.SpecialtyID] {
+			return ErrDuplicate
+		}
+		seen[link.SpecialtyID] = true
+
+		specialty, err := s.specialty(ctx, link.SpecialtyID)
+		if err != nil {
+			return err
+		}
+		if link.Primary {
+			primary = specialty
+			primaries++
+		}
+		links = append(links, &model.DoctorSpecialty{
+			DoctorID:    doctor.ID,
+			SpecialtyID: specialty.ID,
+			Primary:     link.Primary,
+			CreatedAt:   time.Now(),
+		})
+	}
+	if primaries != 1 {
+		return ErrPrimary
+	}
+
+	err := s.repo.SetDoctorSpecialties(ctx, doctor.ID, links, primary.Name(model.DefaultLocale))
+	if err != nil {
+		logger.Errorf("SetDoctorSpecialties fail, doctor: %s, error: %s", doctor.ID, err)
+		return err
+	}
+	s.publisher.Publish(ctx, events.New(ctx, events.DoctorUpdated, doctor.ID, &events.Change{Action: events.ActionUpdated, UserID: doctor.IDUser, Status: doctor.Status}))
+	return nil
+}
+
+// apply validates req and sets it on specialty
+func (s *SpecialtyService) apply(ctx context.Context, specialty *model.Specialty, req *dto.CreateSpecialtyReq) error {
+	if err := s.validator.ValidateStruct(req); err != nil {
+		return err
+	}
+	if !slugPattern.MatchString(req.Slug) {
+		return ErrInvalidSlug
+	}
+	if req.Names[model.DefaultLocale] == "" {
+		return ErrDefaultName
+	}
+
+	existing, err := s.repo.GetSpecialtyBySlug(ctx, req.Slug)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		logger.Errorf("GetSpecialtyBySlug fail, slug: %s, error: %s", req.Slug, err)
+		return err
+	}
+	if err == nil && existing.ID != specialty.ID {
+		return ErrSlugTaken
+	}
+
+	if req.ParentID != nil {
+		if err := s.checkParent(ctx, specialty.ID, *req.ParentID); err != nil {
+			return err
+		}
+	}
+
+	specialty.ParentID = req.ParentID
+	specialty.Slug = req.Slug
+	specialty.Names = req.Names
+	specialty.Icon = req.Icon
+	specialty.Aliases = req.Aliases
+	return nil
+}
+
+// checkParent walks up from parentID, the specialty id must not be met for the
+// hierarchy to stay a tree
+func (s *SpecialtyService) checkParent(ctx context.Context, id, parentID string) error {
+	for next := &parentID; next != nil; {
+		if id != "" && *next == id {
+			return ErrInvalidParent
+		}
+		parent, err := s.specialty(ctx, *next)
+		if err != nil {
+			return err
+		}
+		next = parent.ParentID
+	}
+	return nil
+}
+
+func (s *SpecialtyService) specialty(ctx context.Context, id string) (*model.Specialty, error) {
+	specialty, err := s.repo.GetSpecialtyByID(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrSpecialtyNotFound
+	}
+	if err != nil {
+		logger.Errorf("GetSpecialtyByID fail, id: %s, error: %s", id, err)
+		return nil, err
+	}
+	return specialty, nil
+}
+
+// publish tells the subscribers that the specialty id changed
+func (s *SpecialtyService) publish(ctx context.Context, id, action string) {
+	s.publisher.Publish(ctx, events.New(ctx, events.SpecialtyChanged, id, &events.Change{Action: action}))
+}
@@ -0,0 +1,60 @@
+package dto
+
+import "time"
+
+type CreateAPIKeyReq struct {
+	// Which service uses the key
+	// example: "billing"
+	Name string `json:"name" validate:"required"`
+	// Permissions granted to the key
+	// example: ["users:read","doctors:read"]
+	Scopes []string `json:"scopes" validate:"required,min=1"`
+	// The key never expires when empty
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+type APIKey struct {
+	ID string `json:"id"`
+	// Public part of the key, to tell keys apart
+	// example: "dk_a1B2c3D4"
+	Prefix     string     `json:"prefix"`
+	Name       string     `json:"name"`
+	Scopes     []string   `json:"scopes"`
+	CreatedBy  string     `json:"created_by"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+}
+
+type CreateAPIKeyRes struct {
+	APIKey *APIKey `json:"api_key"`
+	// The key, shown only once. Send it in the X-API-Key header, or use the
+	// key id and the key as client_id and client_secret of the client
+	// credentials grant.
+	Key string `json:"key"`
+}
+
+type ListAPIKeysRes struct {
+	APIKeys []*APIKey `json:"api_keys"`
+}
+
+// ClientTokenReq is the client credentials grant of RFC 6749, the client can
+// also authenticate with HTTP Basic
+type ClientTokenReq struct {
+	// example: "client_credentials"
+	GrantType    string `json:"grant_type" form:"grant_type" validate:"required"`
+	ClientID     string `json:"client_id" form:"client_id"`
+	ClientSecret string `json:"client_secret" form:"client_secret"`
+	// Space separated subset of the key scopes, all of them when empty
+	Scope string `json:"scope" form:"scope"`
+}
+
+type ClientTokenRes struct {
+	AccessToken string `json:"access_token"`
+	// example: "Bearer"
+	TokenType string `json:"token_type"`
+	// Seconds until the token expires
+	ExpiresIn int    `json:"expires_in"`
+	Scope     string `json:"scope"`
+}
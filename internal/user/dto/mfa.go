@@ -0,0 +1,39 @@
+package dto
+
+// EnrollMFARes holds the secret to add to an authenticator app
+type EnrollMFARes struct {
+	// Base32 secret for manual entry
+	// example: "JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"
+	Secret string `json:"secret"`
+	// otpauth:// provisioning URI
+	OTPAuthURI string `json:"otpauth_uri"`
+	// Provisioning URI rendered as a PNG data URI
+	QRCode string `json:"qr_code"`
+}
+
+type MFACodeReq struct {
+	// Code from the authenticator app
+	// example: "123456"
+	Code string `json:"code" validate:"required"`
+}
+
+type RecoveryCodesRes struct {
+	// Single use codes, shown only once
+	// example: ["abcde-fghij"]
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type VerifyMFAReq struct {
+	// Challenge token returned by login
+	MFAToken string `json:"mfa_token" validate:"required"`
+	// Code from the authenticator app or a recovery code
+	// example: "123456"
+	Code string `json:"code" validate:"required"`
+}
+
+type DisableMFAReq struct {
+	Password string `json:"password" validate:"required"`
+	// Code from the authenticator app or a recovery code
+	// example: "123456"
+	Code string `json:"code" validate:"required"`
+}
@@ -0,0 +1,30 @@
+package dto
+
+import "time"
+
+type DataRequest struct {
+	ID string `json:"id"`
+	// export or erasure
+	// example: "export"
+	Kind string `json:"kind"`
+	// pending, processing, completed, failed, cancelled or expired
+	// example: "pending"
+	Status      string     `json:"status"`
+	CreatedAt   time.Time  `json:"created_at"`
+	DueAt       time.Time  `json:"due_at"`
+	CompletedAt *time.Time `json:"completed_at"`
+	// Size of the export archive in bytes
+	Size int64 `json:"size"`
+	// ExpiresAt is when the export archive is deleted
+	ExpiresAt *time.Time `json:"expires_at"`
+	Error     string     `json:"error"`
+}
+
+type ListDataRequestsRes struct {
+	DataRequests []*DataRequest `json:"data_requests"`
+}
+
+type RequestErasureReq struct {
+	// Required when the account has a password
+	Password string `json:"password"`
+}
@@ -8,25 +8,42 @@ import (
 )
 
 type KUser struct {
-	ID                    string         `json:"id" gorm:"unique;not null;index;primary_key"`
-	CreatedAt             time.Time      `json:"created_at"`
-	UpdatedAt             time.Time      `json:"updated_at"`
-	DeletedAt             *time.Time     `json:"deleted_at" gorm:"index"`
-	Password              string         `json:"password"`
-	Role                  model.UserRole `json:"role"`
-	Email                 string         `json:"email" gorm:"unique;not null;index:idx_user_email"`
-	Name                  string         `json:"name"`
-	PhoneNumber           string         `json:"phone_number"`
-	VerifyCodeEmail       int            `json:"verify_code_email"`
-	VerifyCodePhoneNumber int            `json:"verify_code_phone_number"`
-	ApproveEmail          bool           `json:"approve_email"`
-	ApprovePhoneNumber    bool           `json:"approve_phone_number"`
+	ID                    string            `json:"id" gorm:"unique;not null;index;primary_key"`
+	CreatedAt             time.Time         `json:"created_at"`
+	UpdatedAt             time.Time         `json:"updated_at"`
+	DeletedAt             *time.Time        `json:"deleted_at" gorm:"index"`
+	Password              string            `json:"password"`
+	Role                  model.UserRole    `json:"role"`
+	TenantID              string            `json:"tenant_id"`
+	Email                 string            `json:"email" gorm:"unique;not null;index:idx_user_email"`
+	Name                  string            `json:"name"`
+	PhoneNumber           string            `json:"phone_number"`
+	VerifyCodeEmail       int               `json:"verify_code_email"`
+	VerifyCodePhoneNumber int               `json:"verify_code_phone_number"`
+	ApproveEmail          bool              `json:"approve_email"`
+	ApprovePhoneNumber    bool              `json:"approve_phone_number"`
+	Avatar                string            `json:"avatar"`
+	AvatarVariants        map[string]string `json:"avatar_variants"`
 }
 type User struct {
-	ID        string    `json:"id"`
-	Email     string    `json:"email"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID         string    `json:"id"`
+	Email      string    `json:"email"`
+	MFAEnabled bool      `json:"mfa_enabled"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	// Large jpeg variant of the picture
+	Avatar string `json:"avatar"`
+	// Thumbnails of the picture by size and format
+	// example: {"small.webp":"http://localhost:8888/api/v1/images/12345/small.webp"}
+	AvatarVariants map[string]string `json:"avatar_variants"`
+	// Version of the user, also sent as its ETag
+	Version int64 `json:"version"`
+}
+
+// SetAvatarReq sets an uploaded image as the picture of the signed-in user
+type SetAvatarReq struct {
+	// ID of the uploaded image
+	FileID string `json:"file_id" validate:"required"`
 }
 
 type RegisterReq struct {
@@ -51,6 +68,13 @@ type LoginRes struct {
 	User         User   `json:"user"`
 	AccessToken  string `json:"access_token"`
 	RefreshToken string `json:"refresh_token"`
+	// MFARequired is set instead of the tokens when a second factor is
+	// needed, MFAToken is then exchanged at /auth/mfa/verify
+	MFARequired bool   `json:"mfa_required,omitempty"`
+	MFAToken    string `json:"mfa_token,omitempty"`
+	// MFAEnrollmentRequired is set when the role enforces MFA and the user
+	// must enroll with MFAToken first
+	MFAEnrollmentRequired bool `json:"mfa_enrollment_required,omitempty"`
 }
 
 type RefreshTokenReq struct {
@@ -59,6 +83,8 @@ type RefreshTokenReq struct {
 
 type RefreshTokenRes struct {
 	AccessToken string `json:"access_token"`
+	// RefreshToken replaces the one used, which is no longer accepted
+	RefreshToken string `json:"refresh_token"`
 }
 
 type UpdateUserReq struct {
@@ -69,12 +95,30 @@ type UpdateUserReq struct {
 	Email       string         `json:"email" validate:"required,email"`
 	Name        string         `json:"name"`
 	PhoneNumber string         `json:"phone_number"`
+	// Version the change applies to, from If-Match over HTTP
+	Version int64 `json:"-"`
 }
 
 type UpdateUserRes struct {
 	Message string `json:"message"`
 }
 
+// UserProfile is the part of a user changed by a patch
+type UserProfile struct {
+	Name        string `json:"name"`
+	Email       string `json:"email"`
+	PhoneNumber string `json:"phone_number"`
+}
+
+// PatchUserReq holds the fields changed by a patch of a user, the others are
+// nil and kept. Changed email and phone number must be verified again.
+// swagger:model PatchUserReq
+type PatchUserReq struct {
+	Name        *string `json:"name,omitempty" validate:"omitnil,max=200"`
+	Email       *string `json:"email,omitempty" validate:"omitnil,email"`
+	PhoneNumber *string `json:"phone_number,omitempty" validate:"omitnil,e164"`
+}
+
 //***************************************************************************\\
 //***************************************************************************\\
 
@@ -114,6 +158,8 @@ type ListUsersReq struct {
 	// Limit number of items per page
 	// example: 10
 	Limit int64 `json:"-" form:"limit"`
+	// Also lists the deleted users, for admins
+	IncludeDeleted bool `json:"include_deleted,omitempty" form:"include_deleted"`
 }
 type ListUsersRes struct {
 	// List of Users
@@ -131,7 +177,42 @@ type DeleteUserReq struct {
 	// ID of the address
 	// example: "12345"
 	ID string `json:"id"`
+	// Version the change applies to, from If-Match over HTTP
+	Version int64 `json:"-"`
 }
 
 //***************************************************************************\\
 //***************************************************************************\\
+
+type OAuthURLRes struct {
+	// URL of the provider consent page
+	AuthorizationURL string `json:"authorization_url"`
+}
+
+type Identity struct {
+	// example: "google"
+	Provider  string    `json:"provider"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ListIdentitiesRes struct {
+	Identities []*Identity `json:"identities"`
+}
+
+type Session struct {
+	ID string `json:"id"`
+	// example: "Chrome on Windows"
+	Device     string    `json:"device"`
+	UserAgent  string    `json:"user_agent"`
+	IP         string    `json:"ip"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	// Current is set on the session of the request
+	Current bool `json:"current"`
+}
+
+type ListSessionsRes struct {
+	Sessions []*Session `json:"sessions"`
+}
@@ -0,0 +1,61 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Kinds of data requests
+const (
+	// DataExport bundles the personal data of the user in an archive
+	DataExport = "export"
+	// AccountErasure erases the account once its grace period is over
+	AccountErasure = "erasure"
+)
+
+const (
+	DataRequestPending    = "pending"
+	DataRequestProcessing = "processing"
+	// DataRequestCompleted exports can be downloaded until ExpiresAt
+	DataRequestCompleted = "completed"
+	DataRequestFailed    = "failed"
+	DataRequestCancelled = "cancelled"
+	// DataRequestExpired exports were deleted from the storage
+	DataRequestExpired = "expired"
+)
+
+// DataRequest is a request of a user to export or erase its personal data,
+// processed in the background once DueAt is reached. A user has at most one
+// pending request of each kind.
+type DataRequest struct {
+	ID          string     `json:"id" gorm:"unique;not null;index;primary_key"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	UserID      string     `json:"user_id" gorm:"not null;uniqueIndex:idx_data_request_pending,where:status = 'pending'"`
+	Kind        string     `json:"kind" gorm:"not null;uniqueIndex:idx_data_request_pending,where:status = 'pending'"`
+	Status      string     `json:"status" gorm:"not null;index:idx_data_request_due"`
+	DueAt       time.Time  `json:"due_at" gorm:"not null;index:idx_data_request_due"`
+	CompletedAt *time.Time `json:"completed_at"`
+	// Key is the archive of an export in the storage, until ExpiresAt
+	Key       string     `json:"-"`
+	Size      int64      `json:"size"`
+	ExpiresAt *time.Time `json:"expires_at"`
+	Error     string     `json:"error"`
+}
+
+func (DataRequest) TableName() string {
+	return "user_data_requests"
+}
+
+func (m *DataRequest) BeforeCreate() error {
+	m.ID = uuid.New().String()
+	m.CreatedAt = time.Now()
+	m.Status = DataRequestPending
+	return nil
+}
+
+// Downloadable reports whether the archive of an export can be downloaded
+func (m *DataRequest) Downloadable() bool {
+	return m.Kind == DataExport && m.Status == DataRequestCompleted && m.ExpiresAt != nil && time.Now().Before(*m.ExpiresAt)
+}
@@ -0,0 +1,21 @@
+package model
+
+import (
+	"time"
+)
+
+// UserIdentity links a user to an account at an OAuth provider, a user can
+// have one identity per provider
+type UserIdentity struct {
+	ID        string    `json:"id" gorm:"unique;not null;index;primary_key"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	UserID    string    `json:"user_id" gorm:"not null;index;uniqueIndex:idx_user_identity_user_provider"`
+	Provider  string    `json:"provider" gorm:"not null;uniqueIndex:idx_user_identity_subject;uniqueIndex:idx_user_identity_user_provider"`
+	Subject   string    `json:"-" gorm:"not null;uniqueIndex:idx_user_identity_subject"`
+	Email     string    `json:"email"`
+}
+
+func (UserIdentity) TableName() string {
+	return "user_identities"
+}
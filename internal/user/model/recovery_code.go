@@ -0,0 +1,32 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"main/pkg/utils"
+)
+
+// RecoveryCode is a single use code that replaces the TOTP code when the
+// authenticator is lost, only its hash is stored
+type RecoveryCode struct {
+	ID        string     `json:"id" gorm:"unique;not null;index;primary_key"`
+	CreatedAt time.Time  `json:"created_at"`
+	UserID    string     `json:"user_id" gorm:"not null;index"`
+	CodeHash  string     `json:"-" gorm:"not null"`
+	UsedAt    *time.Time `json:"used_at"`
+}
+
+func (RecoveryCode) TableName() string {
+	return "user_recovery_codes"
+}
+
+// NewRecoveryCode hashes code for userID
+func NewRecoveryCode(userID, code string) *RecoveryCode {
+	return &RecoveryCode{
+		ID:       uuid.New().String(),
+		UserID:   userID,
+		CodeHash: utils.HashAndSalt([]byte(code)),
+	}
+}
@@ -0,0 +1,33 @@
+package model
+
+import (
+	"time"
+)
+
+// Session is a login of a user on a device. Its refresh tokens form a family
+// rotated on every refresh, RefreshTokenID is the only one still accepted.
+type Session struct {
+	ID        string    `json:"id" gorm:"unique;not null;index;primary_key"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	UserID    string    `json:"user_id" gorm:"not null;index"`
+	// TenantID is the clinic of the user, so clinic admins only reach the
+	// sessions of their staff
+	TenantID       string     `json:"tenant_id" gorm:"not null;default:'';index"`
+	Device         string     `json:"device"`
+	UserAgent      string     `json:"user_agent"`
+	IP             string     `json:"ip"`
+	LastSeenAt     time.Time  `json:"last_seen_at"`
+	ExpiresAt      time.Time  `json:"expires_at"`
+	RevokedAt      *time.Time `json:"revoked_at"`
+	RefreshTokenID string     `json:"-" gorm:"not null"`
+}
+
+func (Session) TableName() string {
+	return "user_sessions"
+}
+
+// Active reports whether the tokens of the session are still accepted
+func (s *Session) Active() bool {
+	return s.RevokedAt == nil && time.Now().Before(s.ExpiresAt)
+}
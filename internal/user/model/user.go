@@ -5,7 +5,10 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 
+	"main/pkg/imaging"
+	"main/pkg/notify"
 	"main/pkg/utils"
 )
 
@@ -17,25 +20,48 @@ const (
 	UserRoleAdmin  UserRole = "admin"  // Administrator role
 	UserRoleDoctor UserRole = "doctor" // Doctor role
 	UserRoleClient UserRole = "client" // Client role
+	// UserRoleClinicAdmin manages the clinic of TenantID, it is given by the
+	// clinics and cannot be registered
+	UserRoleClinicAdmin UserRole = "clinic_admin"
 )
 
 // User represents a user in the system
 type User struct {
-	ID                    string     `json:"id" gorm:"unique;not null;index;primary_key"`
-	CreatedAt             time.Time  `json:"created_at"`
-	UpdatedAt             time.Time  `json:"updated_at"`
-	DeletedAt             *time.Time `json:"deleted_at" gorm:"index"`
-	Password              string     `json:"password"`
-	Role                  UserRole   `json:"role"`
-	Email                 string     `json:"email" gorm:"unique;not null;index:idx_user_email"`
+	ID        string         `json:"id" gorm:"unique;not null;index;primary_key"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"index"`
+	Password  string         `json:"password"`
+	Role      UserRole       `json:"role"`
+	// TenantID is the clinic of the staff users, empty for patients and
+	// global admins
+	TenantID string `json:"tenant_id" gorm:"not null;default:'';index"`
+	// Email, PhoneNumber, the verification codes and MFASecret are
+	// encrypted, users are found by the blind indexes of their email and
+	// phone number
+	Email                 string     `json:"email" gorm:"not null;type:text;serializer:encrypted"`
+	EmailIndex            string     `json:"-" gorm:"not null;default:'';uniqueIndex:idx_user_email_index,where:email_index <> ''" blindindex:"Email"`
 	Name                  string     `json:"name"`
-	PhoneNumber           string     `json:"phone_number"`
-	VerifyCodeEmail       int        `json:"verify_code_email"`
-	VerifyCodePhoneNumber int        `json:"verify_code_phone_number"`
+	PhoneNumber           string     `json:"phone_number" gorm:"type:text;serializer:encrypted"`
+	PhoneIndex            string     `json:"-" gorm:"not null;default:'';index" blindindex:"PhoneNumber"`
+	VerifyCodeEmail       int        `json:"verify_code_email" gorm:"type:text;serializer:encrypted"`
+	VerifyCodePhoneNumber int        `json:"verify_code_phone_number" gorm:"type:text;serializer:encrypted"`
 	ApproveEmail          bool       `json:"approve_email"`
 	ApprovePhoneNumber    bool       `json:"approve_phone_number"`
+	MFAEnabled            bool       `json:"mfa_enabled"`
+	MFASecret             string     `json:"-" gorm:"type:text;serializer:encrypted"`
+	MFALastStep           int64      `json:"-"`
+	MFAConfirmedAt        *time.Time `json:"mfa_confirmed_at"`
+	// Avatar is the large jpeg variant of the uploaded picture
+	Avatar         string           `json:"avatar"`
+	AvatarVariants imaging.Variants `json:"avatar_variants" gorm:"type:text"`
+	// Version is bumped by every change, it is the ETag of the user
+	Version int64 `json:"version" gorm:"not null;default:1"`
 }
 
+// AvatarVariant is the variant set as Avatar
+const AvatarVariant = "large.jpg"
+
 // BeforeCreate is a hook that is called before creating a new user
 // func (user *User) BeforeCreate(tx *gorm.DB) error {
 func (user *User) BeforeCreate() error {
@@ -50,17 +76,11 @@ func (user *User) BeforeCreate() error {
 		user.Role = UserRoleClient
 	}
 
-	// Generate verification codes
+	// Generate verification codes, they are sent once the user is created
 	user.VerifyCodePhoneNumber = utils.GenerateRandomCode()
 	user.VerifyCodeEmail = utils.GenerateRandomCode()
 	user.ApproveEmail = false
 	user.ApprovePhoneNumber = false
-
-	// Send verification codes
-	err := SendVerificationCodes(user)
-	if err != nil {
-		return err
-	}
 	return nil
 }
 
@@ -69,11 +89,6 @@ func (user *User) BeforeUpdate() error {
 	// Generate verification codes
 	user.VerifyCodeEmail = utils.GenerateRandomCode()
 	user.VerifyCodePhoneNumber = utils.GenerateRandomCode()
-	// Send verification codes
-	err := SendVerificationCodes(user)
-	if err != nil {
-		return err
-	}
 	return nil
 }
 
@@ -81,11 +96,6 @@ func (user *User) BeforeUpdate() error {
 func (user *User) BeforeUpdateVerificationEmail() error {
 	// Generate verification codes
 	user.VerifyCodeEmail = utils.GenerateRandomCode()
-	// Send verification codes
-	err := SendVerificationCodesEmail(user)
-	if err != nil {
-		return err
-	}
 	return nil
 }
 
@@ -93,52 +103,34 @@ func (user *User) BeforeUpdateVerificationEmail() error {
 func (user *User) BeforeUpdateVerificationPhone() error {
 	// Generate verification codes
 	user.VerifyCodePhoneNumber = utils.GenerateRandomCode()
-	// Send verification codes
-	err := SendVerificationCodesPhone(user)
-	if err != nil {
-		return err
-	}
 	return nil
 }
 
-// sendVerificationCodes sends the verification codes to the user's phone and email
-func SendVerificationCodes(user *User) error {
-	// Send email verification code
-	err := SendVerificationCodesEmail(user)
-	if err != nil {
-		return err
+// VerificationCodeEmail is the message sending the email verification code,
+// to the email of user even before it is saved
+func VerificationCodeEmail(user *User) *notify.Message {
+	return &notify.Message{
+		UserID:   user.ID,
+		Type:     notify.TypeVerificationCode,
+		Title:    "Your Email Verification Code",
+		Body:     fmt.Sprintf("Your verification code is %d", user.VerifyCodeEmail),
+		HTML:     fmt.Sprintf("<strong>Your verification code is %d</strong>", user.VerifyCodeEmail),
+		Channels: []string{notify.ChannelEmail},
+		Name:     user.Name,
+		Email:    user.Email,
 	}
-
-	// Send phone verification code
-	err = SendVerificationCodesPhone(user)
-	if err != nil {
-		return err
-	}
-
-	return nil
 }
 
-// Send phone verification code
-func SendVerificationCodesPhone(user *User) error {
-
-	phoneMessage := fmt.Sprintf("Your verification code is %d", user.VerifyCodePhoneNumber)
-	err := utils.SendSMS(user.PhoneNumber, phoneMessage)
-	if err != nil {
-		return fmt.Errorf("failed to send SMS verification code: %w", err)
+// VerificationCodePhone is the message sending the phone verification code,
+// to the phone number of user even before it is saved
+func VerificationCodePhone(user *User) *notify.Message {
+	return &notify.Message{
+		UserID:   user.ID,
+		Type:     notify.TypeVerificationCode,
+		Title:    "Your Phone Verification Code",
+		Body:     fmt.Sprintf("Your verification code is %d", user.VerifyCodePhoneNumber),
+		Channels: []string{notify.ChannelSMS},
+		Name:     user.Name,
+		Phone:    user.PhoneNumber,
 	}
-
-	return nil
-}
-
-// Send email verification code
-func SendVerificationCodesEmail(user *User) error {
-	emailSubject := "Your Email Verification Code"
-	emailPlainText := fmt.Sprintf("Your verification code is %d", user.VerifyCodeEmail)
-	emailHTMLContent := fmt.Sprintf("<strong>Your verification code is %d</strong>", user.VerifyCodeEmail)
-	err := utils.SendEmail(user.Name, user.Email, emailSubject, emailPlainText, emailHTMLContent)
-	if err != nil {
-		return fmt.Errorf("failed to send email verification code: %w", err)
-	}
-
-	return nil
 }
@@ -0,0 +1,88 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/quangdangfit/gocommon/logger"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"main/internal/user/dto"
+	"main/internal/user/service"
+	"main/pkg/utils"
+	pb "main/proto/gen/go/user"
+)
+
+func (h *UserHandler) CreateAPIKey(ctx context.Context, req *pb.CreateAPIKeyReq) (*pb.CreateAPIKeyRes, error) {
+	createReq := dto.CreateAPIKeyReq{
+		Name:   req.Name,
+		Scopes: req.Scopes,
+	}
+	if req.ExpiresAt != "" {
+		expiresAt, err := time.Parse(time.RFC3339, req.ExpiresAt)
+		if err != nil {
+			return nil, status.New(codes.InvalidArgument, "expires_at must be RFC 3339").Err()
+		}
+		createReq.ExpiresAt = &expiresAt
+	}
+
+	userID, _ := ctx.Value("userId").(string)
+	apiKey, key, err := h.apiKeys.CreateAPIKey(ctx, userID, &createReq)
+	if err != nil {
+		logger.Error("Failed to create api key ", err)
+		return nil, status.New(codes.InvalidArgument, err.Error()).Err()
+	}
+
+	res := pb.CreateAPIKeyRes{Key: key}
+	utils.Copy(&res.ApiKey, &apiKey)
+	return &res, nil
+}
+
+func (h *UserHandler) ListAPIKeys(ctx context.Context, _ *pb.ListAPIKeysReq) (*pb.ListAPIKeysRes, error) {
+	apiKeys, err := h.apiKeys.ListAPIKeys(ctx)
+	if err != nil {
+		logger.Error("Failed to list api keys ", err)
+		return nil, err
+	}
+
+	var res pb.ListAPIKeysRes
+	utils.Copy(&res.ApiKeys, &apiKeys)
+	return &res, nil
+}
+
+func (h *UserHandler) RevokeAPIKey(ctx context.Context, req *pb.RevokeAPIKeyReq) (*pb.RevokeAPIKeyRes, error) {
+	err := h.apiKeys.RevokeAPIKey(ctx, req.Id)
+	if errors.Is(err, service.ErrAPIKeyNotFound) {
+		return nil, status.New(codes.NotFound, err.Error()).Err()
+	}
+	if err != nil {
+		logger.Error("Failed to revoke api key ", err)
+		return nil, err
+	}
+
+	return &pb.RevokeAPIKeyRes{}, nil
+}
+
+func (h *UserHandler) ClientToken(ctx context.Context, req *pb.ClientTokenReq) (*pb.ClientTokenRes, error) {
+	token, err := h.apiKeys.ClientToken(ctx, &dto.ClientTokenReq{
+		GrantType:    req.GrantType,
+		ClientID:     req.ClientId,
+		ClientSecret: req.ClientSecret,
+		Scope:        req.Scope,
+	})
+	switch {
+	case errors.Is(err, service.ErrInvalidClient):
+		return nil, status.New(codes.Unauthenticated, err.Error()).Err()
+	case err != nil:
+		return nil, status.New(codes.InvalidArgument, err.Error()).Err()
+	}
+
+	return &pb.ClientTokenRes{
+		AccessToken: token.AccessToken,
+		TokenType:   token.TokenType,
+		ExpiresIn:   int32(token.ExpiresIn),
+		Scope:       token.Scope,
+	}, nil
+}
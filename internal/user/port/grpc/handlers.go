@@ -4,30 +4,45 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
+	"strconv"
 	"time"
 
 	"github.com/quangdangfit/gocommon/logger"
-	"golang.org/x/oauth2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"main/internal/user/dto"
 	"main/internal/user/model"
 	"main/internal/user/service"
+	"main/pkg/dbs"
+	"main/pkg/patch"
+	"main/pkg/ratelimit"
 	"main/pkg/redis"
+	"main/pkg/tenant"
 	"main/pkg/utils"
 	pb "main/proto/gen/go/user"
 )
 
 type UserHandler struct {
 	pb.UnimplementedUserServiceServer
-	cache         redis.IRedis
-	service       service.IUserService
-	fbOauthConfig *oauth2.Config
+	cache   redis.IRedis
+	service service.IUserService
+	apiKeys service.IAPIKeyService
 }
 
-func NewUserHandler(service service.IUserService) *UserHandler {
+func NewUserHandler(
+	cache redis.IRedis,
+	service service.IUserService,
+	apiKeys service.IAPIKeyService,
+) *UserHandler {
 	return &UserHandler{
+		cache:   cache,
 		service: service,
+		apiKeys: apiKeys,
 	}
 }
 
@@ -36,9 +51,12 @@ func (h *UserHandler) Login(ctx context.Context, req *pb.LoginReq) (*pb.LoginRes
 		Email:    req.Email,
 		Password: req.Password,
 	})
+	if res, ok := mfaChallenge(user, err); ok {
+		return res, nil
+	}
 	if err != nil {
 		logger.Error("Failed to register ", err)
-		return nil, err
+		return nil, authError(ctx, err)
 	}
 
 	var res pb.LoginRes
@@ -93,14 +111,17 @@ func (h *UserHandler) RefreshToken(ctx context.Context, req *pb.RefreshTokenReq)
 		return nil, errors.New("unauthorized")
 	}
 
-	accessToken, err := h.service.RefreshToken(ctx, userID)
+	sessionID, _ := ctx.Value("sessionId").(string)
+	tokenID, _ := ctx.Value("tokenId").(string)
+	accessToken, refreshToken, err := h.service.RefreshToken(ctx, sessionID, tokenID)
 	if err != nil {
-		logger.Error("Failed to register ", err)
-		return nil, err
+		logger.Error("Failed to refresh token ", err)
+		return nil, sessionError(err)
 	}
 
 	res := pb.RefreshTokenRes{
-		AccessToken: accessToken,
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
 	}
 	return &res, nil
 }
@@ -110,6 +131,9 @@ func (h *UserHandler) UpdateUser(ctx context.Context, req *pb.UpdateUserReq) (*p
 	if userID == "" {
 		return nil, errors.New("unauthorized")
 	}
+	if paths := req.GetUpdateMask().GetPaths(); len(paths) > 0 {
+		return h.patchUser(ctx, userID, req, paths)
+	}
 	protoRole, err2 := ConvertProtoToModelUserRole(req.Role)
 	if err2 != nil {
 		logger.Error("Failed to convert user role: ", err2)
@@ -123,10 +147,11 @@ func (h *UserHandler) UpdateUser(ctx context.Context, req *pb.UpdateUserReq) (*p
 		Name:        req.Name,
 		Role:        protoRole,
 		PhoneNumber: req.PhoneNumber,
+		Version:     req.Version,
 	})
 	if err != nil {
 		logger.Error("Failed to register ", err)
-		return nil, err
+		return nil, versionError(err)
 	}
 
 	return &pb.UpdateUserRes{}, nil
@@ -140,7 +165,7 @@ func (h *UserHandler) VerfiyCodePhoneNumber(ctx context.Context, req *pb.VerifyP
 
 	if err != nil {
 		logger.Error("Failed to register ", err)
-		return nil, err
+		return nil, authError(ctx, err)
 
 	}
 
@@ -154,7 +179,7 @@ func (h *UserHandler) VerfiyCodeEmail(ctx context.Context, req *pb.VerifyEmailRe
 
 	if err != nil {
 		logger.Error("Failed to register ", err)
-		return nil, err
+		return nil, authError(ctx, err)
 
 	}
 
@@ -191,6 +216,68 @@ func (h *UserHandler) VerfiyCodeEmailResend(ctx context.Context, req *pb.ResendV
 	return &pb.VerifyResponse{}, nil
 }
 
+// authError maps account lockouts to ResourceExhausted with a retry-after
+// header, other errors are returned unchanged
+func authError(ctx context.Context, err error) error {
+	var lockedErr *ratelimit.LockedError
+	if !errors.As(err, &lockedErr) {
+		return err
+	}
+
+	_ = grpc.SetHeader(ctx, metadata.Pairs("retry-after", strconv.Itoa(int(math.Ceil(lockedErr.RetryAfter.Seconds())))))
+	return status.New(codes.ResourceExhausted, err.Error()).Err()
+}
+
+// versionError tells the clients to read the user again when it changed
+// since their version
+func versionError(err error) error {
+	if errors.Is(err, dbs.ErrStaleVersion) {
+		return status.New(codes.Aborted, err.Error()).Err()
+	}
+	if errors.Is(err, service.ErrEmailInUse) {
+		return status.New(codes.AlreadyExists, err.Error()).Err()
+	}
+	if patch.StatusCode(err) != 0 {
+		return status.New(codes.InvalidArgument, err.Error()).Err()
+	}
+	return err
+}
+
+// patchUser changes the profile fields of req in the update mask instead of
+// the password
+func (h *UserHandler) patchUser(ctx context.Context, userID string, req *pb.UpdateUserReq, paths []string) (*pb.UpdateUserRes, error) {
+	fields := make(map[string]interface{}, len(paths))
+	for _, path := range paths {
+		switch path {
+		case "name":
+			fields["name"] = req.Name
+		case "email":
+			fields["email"] = req.Email
+		case "phoneNumber", "phone_number":
+			fields["phone_number"] = req.PhoneNumber
+		default:
+			return nil, status.Errorf(codes.InvalidArgument, "%q cannot be in update_mask", path)
+		}
+	}
+
+	user, err := h.service.PatchUser(ctx, userID, req.Version, patch.Merge(fields))
+	if err != nil {
+		logger.Error("Failed to patch user ", err)
+		return nil, versionError(err)
+	}
+
+	return &pb.UpdateUserRes{User: &pb.UserInfo{
+		Id:             user.ID,
+		Email:          user.Email,
+		CreatedAt:      user.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:      user.UpdatedAt.Format(time.RFC3339),
+		MfaEnabled:     user.MFAEnabled,
+		Avatar:         user.Avatar,
+		AvatarVariants: user.AvatarVariants,
+		Version:        user.Version,
+	}}, nil
+}
+
 // ConvertModelUserRoleToProto converts a model.UserRole to pb.UserRole
 func ConvertModelUserRoleToProto(role model.UserRole) (pb.UserRole, error) {
 	switch role {
@@ -200,6 +287,8 @@ func ConvertModelUserRoleToProto(role model.UserRole) (pb.UserRole, error) {
 		return pb.UserRole_ROLE_DOCTOR, nil
 	case model.UserRoleAdmin:
 		return pb.UserRole_ROLE_ADMIN, nil
+	case model.UserRoleClinicAdmin:
+		return pb.UserRole_ROLE_CLINIC_ADMIN, nil
 	default:
 		return pb.UserRole_ROLE_UNKNOWN, fmt.Errorf("unknown role: %v", role)
 	}
@@ -214,6 +303,8 @@ func ConvertProtoToModelUserRole(role pb.UserRole) (model.UserRole, error) {
 		return model.UserRoleDoctor, nil
 	case pb.UserRole_ROLE_ADMIN:
 		return model.UserRoleAdmin, nil
+	case pb.UserRole_ROLE_CLINIC_ADMIN:
+		return model.UserRoleClinicAdmin, nil
 	default:
 		return model.UserRoleClient, fmt.Errorf("unknown role: %v", role)
 	}
@@ -221,8 +312,8 @@ func ConvertProtoToModelUserRole(role pb.UserRole) (model.UserRole, error) {
 
 func (h *UserHandler) ListUsers(ctx context.Context, req *pb.ListUsersRequest) (*pb.ListUsersResponse, error) {
 	var res dto.ListUsersRes
-	cacheKey := "users_list"
-	err := h.cache.Get(cacheKey, &res)
+	cacheKey := tenant.CacheKey(ctx, "users_list")
+	err := h.cache.Get(ctx, cacheKey, &res)
 	if err == nil {
 		var pbUsers []*pb.User
 		for _, addr := range res.Users {
@@ -249,6 +340,8 @@ func (h *UserHandler) ListUsers(ctx context.Context, req *pb.ListUsersRequest) (
 				VerifyCodePhoneNumber: int32(addr.VerifyCodePhoneNumber),
 				ApproveEmail:          addr.ApproveEmail,
 				ApprovePhoneNumber:    addr.ApprovePhoneNumber,
+				Avatar:                addr.Avatar,
+				AvatarVariants:        addr.AvatarVariants,
 			})
 		}
 		return &pb.ListUsersResponse{Users: pbUsers}, nil
@@ -262,7 +355,7 @@ func (h *UserHandler) ListUsers(ctx context.Context, req *pb.ListUsersRequest) (
 
 	utils.Copy(&res.Users, &Users)
 	res.Pagination = pagination
-	_ = h.cache.SetWithExpiration(cacheKey, res, time.Hour) // Adjust caching time as needed
+	_ = h.cache.SetWithExpiration(ctx, cacheKey, res, time.Hour) // Adjust caching time as needed
 
 	var pbUsers []*pb.User
 	for _, addr := range Users {
@@ -272,8 +365,8 @@ func (h *UserHandler) ListUsers(ctx context.Context, req *pb.ListUsersRequest) (
 			continue // or handle the error as needed
 		}
 		var deletedAtProto *timestamppb.Timestamp
-		if addr.DeletedAt != nil {
-			deletedAtProto = timestamppb.New(*addr.DeletedAt)
+		if addr.DeletedAt.Valid {
+			deletedAtProto = timestamppb.New(addr.DeletedAt.Time)
 		}
 		pbUsers = append(pbUsers, &pb.User{
 			Id:                    addr.ID,
@@ -289,6 +382,8 @@ func (h *UserHandler) ListUsers(ctx context.Context, req *pb.ListUsersRequest) (
 			VerifyCodePhoneNumber: int32(addr.VerifyCodePhoneNumber),
 			ApproveEmail:          addr.ApproveEmail,
 			ApprovePhoneNumber:    addr.ApprovePhoneNumber,
+			Avatar:                addr.Avatar,
+			AvatarVariants:        addr.AvatarVariants,
 		})
 	}
 	return &pb.ListUsersResponse{Users: pbUsers}, nil
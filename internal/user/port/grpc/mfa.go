@@ -0,0 +1,137 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/quangdangfit/gocommon/logger"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"main/internal/user/dto"
+	"main/internal/user/model"
+	"main/internal/user/service"
+	"main/pkg/utils"
+	pb "main/proto/gen/go/user"
+)
+
+func (h *UserHandler) VerifyMFA(ctx context.Context, req *pb.VerifyMFAReq) (*pb.LoginRes, error) {
+	user, accessToken, refreshToken, err := h.service.VerifyMFA(ctx, &dto.VerifyMFAReq{
+		MFAToken: req.MfaToken,
+		Code:     req.Code,
+	})
+	if err != nil {
+		logger.Error("Failed to verify mfa ", err)
+		return nil, mfaError(ctx, err)
+	}
+
+	var res pb.LoginRes
+	utils.Copy(&res.User, &user)
+	res.AccessToken = accessToken
+	res.RefreshToken = refreshToken
+	return &res, nil
+}
+
+func (h *UserHandler) EnrollMFA(ctx context.Context, _ *pb.EnrollMFAReq) (*pb.EnrollMFARes, error) {
+	userID, _ := ctx.Value("userId").(string)
+	if userID == "" {
+		return nil, errors.New("unauthorized")
+	}
+
+	enrollment, err := h.service.EnrollMFA(ctx, userID)
+	if err != nil {
+		logger.Error("Failed to enroll mfa ", err)
+		return nil, mfaError(ctx, err)
+	}
+
+	return &pb.EnrollMFARes{
+		Secret:     enrollment.Secret,
+		OtpauthUri: enrollment.OTPAuthURI,
+		QrCode:     enrollment.QRCode,
+	}, nil
+}
+
+func (h *UserHandler) ConfirmMFA(ctx context.Context, req *pb.MFACodeReq) (*pb.RecoveryCodesRes, error) {
+	userID, _ := ctx.Value("userId").(string)
+	if userID == "" {
+		return nil, errors.New("unauthorized")
+	}
+
+	codes, err := h.service.ConfirmMFA(ctx, userID, &dto.MFACodeReq{Code: req.Code})
+	if err != nil {
+		logger.Error("Failed to confirm mfa ", err)
+		return nil, mfaError(ctx, err)
+	}
+
+	return &pb.RecoveryCodesRes{RecoveryCodes: codes}, nil
+}
+
+func (h *UserHandler) RegenerateRecoveryCodes(ctx context.Context, req *pb.MFACodeReq) (*pb.RecoveryCodesRes, error) {
+	userID, _ := ctx.Value("userId").(string)
+	if userID == "" {
+		return nil, errors.New("unauthorized")
+	}
+
+	codes, err := h.service.RegenerateRecoveryCodes(ctx, userID, &dto.MFACodeReq{Code: req.Code})
+	if err != nil {
+		logger.Error("Failed to regenerate recovery codes ", err)
+		return nil, mfaError(ctx, err)
+	}
+
+	return &pb.RecoveryCodesRes{RecoveryCodes: codes}, nil
+}
+
+func (h *UserHandler) DisableMFA(ctx context.Context, req *pb.DisableMFAReq) (*pb.DisableMFARes, error) {
+	userID, _ := ctx.Value("userId").(string)
+	if userID == "" {
+		return nil, errors.New("unauthorized")
+	}
+
+	err := h.service.DisableMFA(ctx, userID, &dto.DisableMFAReq{
+		Password: req.Password,
+		Code:     req.Code,
+	})
+	if err != nil {
+		logger.Error("Failed to disable mfa ", err)
+		return nil, mfaError(ctx, err)
+	}
+
+	return &pb.DisableMFARes{}, nil
+}
+
+func (h *UserHandler) ResetUserMFA(ctx context.Context, req *pb.ResetUserMFAReq) (*pb.ResetUserMFARes, error) {
+	if err := h.service.ResetMFA(ctx, req.Id); err != nil {
+		logger.Error("Failed to reset mfa ", err)
+		return nil, err
+	}
+
+	return &pb.ResetUserMFARes{}, nil
+}
+
+// mfaChallenge converts the MFA challenge returned by Login into a response
+// without tokens
+func mfaChallenge(user *model.User, err error) (*pb.LoginRes, bool) {
+	var challenge *service.MFARequiredError
+	if !errors.As(err, &challenge) {
+		return nil, false
+	}
+
+	var res pb.LoginRes
+	utils.Copy(&res.User, &user)
+	res.MfaRequired = true
+	res.MfaToken = challenge.Token
+	res.MfaEnrollmentRequired = challenge.EnrollmentRequired
+	return &res, true
+}
+
+func mfaError(ctx context.Context, err error) error {
+	switch {
+	case errors.Is(err, service.ErrInvalidMFACode), errors.Is(err, service.ErrInvalidMFAToken):
+		return status.New(codes.Unauthenticated, err.Error()).Err()
+	case errors.Is(err, service.ErrMFAAlreadyEnabled), errors.Is(err, service.ErrMFANotEnrolled),
+		errors.Is(err, service.ErrMFAEnforced):
+		return status.New(codes.FailedPrecondition, err.Error()).Err()
+	default:
+		return authError(ctx, err)
+	}
+}
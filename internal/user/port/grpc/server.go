@@ -2,19 +2,28 @@ package grpc
 
 import (
 	"github.com/quangdangfit/gocommon/validation"
-	"golang.org/x/oauth2"
 	"google.golang.org/grpc"
 
 	"main/internal/user/repository"
 	"main/internal/user/service"
+	"main/pkg/audit"
+	"main/pkg/config"
 	"main/pkg/dbs"
+	"main/pkg/events"
+	"main/pkg/middleware"
+	"main/pkg/notify"
+	"main/pkg/oauth"
+	"main/pkg/ratelimit"
+	"main/pkg/redis"
 	pb "main/proto/gen/go/user"
 )
 
-func RegisterHandlers(svr *grpc.Server, db dbs.IDatabase, validator validation.Validation, oauthConfig *oauth2.Config, fbOauthConfig *oauth2.Config) {
+func RegisterHandlers(svr *grpc.Server, db dbs.IDatabase, validator validation.Validation, cache redis.IRedis, oauthProviders *oauth.Registry, auth *middleware.Authenticator, images service.ImageProcessor, recorder audit.Recorder, publisher events.Publisher, notifier notify.Notifier) {
 	userRepo := repository.NewUserRepository(db)
-	userSvc := service.NewUserService(validator, oauthConfig, fbOauthConfig, userRepo)
-	userHandler := NewUserHandler(userSvc)
+	oauthFlow := oauth.NewFlow(oauthProviders, oauth.NewStateStore(cache))
+	userSvc := service.NewUserService(validator, oauthFlow, userRepo, ratelimit.LockoutFromConfig(cache, config.GetConfig()), auth.Sessions(), images, recorder, publisher, notifier)
+	apiKeySvc := service.NewAPIKeyService(validator, userRepo, cache, auth.Sessions(), recorder)
+	userHandler := NewUserHandler(cache, userSvc, apiKeySvc)
 
 	pb.RegisterUserServiceServer(svr, userHandler)
 }
@@ -0,0 +1,110 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/quangdangfit/gocommon/logger"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"main/internal/user/service"
+	"main/pkg/utils"
+	pb "main/proto/gen/go/user"
+)
+
+// currentSession is accepted instead of a session id for the session of the
+// call
+const currentSession = "current"
+
+func (h *UserHandler) ListSessions(ctx context.Context, _ *pb.ListSessionsReq) (*pb.ListSessionsRes, error) {
+	userID, _ := ctx.Value("userId").(string)
+	if userID == "" {
+		return nil, errors.New("unauthorized")
+	}
+
+	return h.listSessions(ctx, userID)
+}
+
+func (h *UserHandler) RevokeSession(ctx context.Context, req *pb.RevokeSessionReq) (*pb.RevokeSessionRes, error) {
+	userID, _ := ctx.Value("userId").(string)
+	if userID == "" {
+		return nil, errors.New("unauthorized")
+	}
+
+	sessionID := req.Id
+	if sessionID == currentSession {
+		sessionID, _ = ctx.Value("sessionId").(string)
+	}
+
+	if err := h.service.RevokeSession(ctx, userID, sessionID); err != nil {
+		logger.Error("Failed to revoke session ", err)
+		return nil, sessionError(err)
+	}
+
+	return &pb.RevokeSessionRes{}, nil
+}
+
+func (h *UserHandler) RevokeOtherSessions(ctx context.Context, _ *pb.RevokeOtherSessionsReq) (*pb.RevokeSessionRes, error) {
+	userID, _ := ctx.Value("userId").(string)
+	if userID == "" {
+		return nil, errors.New("unauthorized")
+	}
+
+	sessionID, _ := ctx.Value("sessionId").(string)
+	if err := h.service.RevokeOtherSessions(ctx, userID, sessionID); err != nil {
+		logger.Error("Failed to revoke sessions ", err)
+		return nil, sessionError(err)
+	}
+
+	return &pb.RevokeSessionRes{}, nil
+}
+
+func (h *UserHandler) ListUserSessions(ctx context.Context, req *pb.ListUserSessionsReq) (*pb.ListSessionsRes, error) {
+	return h.listSessions(ctx, req.UserId)
+}
+
+func (h *UserHandler) RevokeUserSession(ctx context.Context, req *pb.RevokeUserSessionReq) (*pb.RevokeSessionRes, error) {
+	if err := h.service.RevokeSession(ctx, req.UserId, req.SessionId); err != nil {
+		logger.Error("Failed to revoke session ", err)
+		return nil, sessionError(err)
+	}
+
+	return &pb.RevokeSessionRes{}, nil
+}
+
+func (h *UserHandler) RevokeUserSessions(ctx context.Context, req *pb.RevokeUserSessionsReq) (*pb.RevokeSessionRes, error) {
+	if err := h.service.RevokeOtherSessions(ctx, req.UserId, ""); err != nil {
+		logger.Error("Failed to revoke sessions ", err)
+		return nil, sessionError(err)
+	}
+
+	return &pb.RevokeSessionRes{}, nil
+}
+
+func (h *UserHandler) listSessions(ctx context.Context, userID string) (*pb.ListSessionsRes, error) {
+	sessions, err := h.service.ListSessions(ctx, userID)
+	if err != nil {
+		logger.Error("Failed to list sessions ", err)
+		return nil, sessionError(err)
+	}
+
+	var res pb.ListSessionsRes
+	utils.Copy(&res.Sessions, &sessions)
+	currentID, _ := ctx.Value("sessionId").(string)
+	for _, s := range res.Sessions {
+		s.Current = s.Id == currentID
+	}
+	return &res, nil
+}
+
+func sessionError(err error) error {
+	switch {
+	case errors.Is(err, service.ErrSessionNotFound):
+		return status.New(codes.NotFound, err.Error()).Err()
+	case errors.Is(err, service.ErrSessionRevoked), errors.Is(err, service.ErrInvalidRefreshToken):
+		return status.New(codes.Unauthenticated, err.Error()).Err()
+	default:
+		return err
+	}
+}
@@ -1,6 +1,7 @@
 package http
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -8,12 +9,16 @@ import (
 	"github.com/quangdangfit/gocommon/logger"
 
 	"main/internal/user/dto"
+	"main/internal/user/service"
 	"main/pkg/config"
+	"main/pkg/dbs"
+	"main/pkg/rbac"
 	"main/pkg/response"
+	"main/pkg/tenant"
 	"main/pkg/utils"
 )
 
-// ListUsers DeleteAdmin CreateAdmin UpdateAdmin LoginAdmin
+// ListUsers DeleteAdmin RestoreUser CreateAdmin UpdateAdmin LoginAdmin
 
 // ListUsers godoc
 //
@@ -25,6 +30,7 @@ import (
 //	@Param		name	path	string					true	"name"
 //	@Param		page	path	string					true	"page"
 //	@Param		limit	path	string					true	"limit"
+//	@Param		include_deleted	query	bool	false	"Also list the deleted users, for admins"
 //
 // @Success	200	{object}	dto.ListUsersRes
 // @Router		/auth-admin/users  [get]
@@ -59,9 +65,14 @@ func (p *UserHandler) ListUsers(c *gin.Context) {
 		return
 	}
 
+	if req.IncludeDeleted && !rbac.Has(c.GetStringSlice("permissions"), rbac.DeletedManage) {
+		response.Error(c, http.StatusForbidden, errors.New("forbidden"), "Forbidden")
+		return
+	}
+
 	var res dto.ListUsersRes
-	cacheKey := c.Request.URL.RequestURI()
-	err := p.cache.Get(cacheKey, &res)
+	cacheKey := tenant.CacheKey(c, c.Request.URL.RequestURI())
+	err := p.cache.Get(c, cacheKey, &res)
 	if err == nil {
 		response.JSON(c, http.StatusOK, res)
 		return
@@ -77,7 +88,7 @@ func (p *UserHandler) ListUsers(c *gin.Context) {
 	utils.Copy(&res.Users, &Users)
 	res.Pagination = pagination
 	response.JSON(c, http.StatusOK, res)
-	_ = p.cache.SetWithExpiration(cacheKey, res, config.UsersCachingTime)
+	_ = p.cache.SetWithExpiration(c, cacheKey, res, config.UsersCachingTime)
 }
 
 // DeleteUser godoc
@@ -86,10 +97,17 @@ func (p *UserHandler) ListUsers(c *gin.Context) {
 //	@Tags		users-admin
 //	@Produce	json
 //	@Security	ApiKeyAuth
+//	@Param		If-Match	header	string	true	"ETag of the user, its version"
 //	@Param		_	body	dto.DeleteUserReq	true	"Body"
 //	@Success	200	{object}	dto.User
+//	@Failure	412	{object}	response.Response	"Changed since it was read"
 //	@Router		/auth-admin/{id} [Delete]
 func (p *UserHandler) DeleteAdmin(c *gin.Context) {
+	version, ok := response.IfMatch(c)
+	if !ok {
+		return
+	}
+
 	var req dto.DeleteUserReq
 	if err := c.ShouldBindJSON(&req); c.Request.Body == nil || err != nil {
 		logger.Error("Failed to get body", err)
@@ -97,7 +115,12 @@ func (p *UserHandler) DeleteAdmin(c *gin.Context) {
 		return
 	}
 
+	req.Version = version
 	User, err := p.service.Delete(c, req.ID, &req)
+	if errors.Is(err, dbs.ErrStaleVersion) {
+		response.StaleVersion(c, err)
+		return
+	}
 	if err != nil {
 		logger.Error("Failed to Delete User", err.Error())
 		response.Error(c, http.StatusInternalServerError, err, "Something went wrong")
@@ -107,7 +130,32 @@ func (p *UserHandler) DeleteAdmin(c *gin.Context) {
 	var res dto.User
 	utils.Copy(&res, &User)
 	response.JSON(c, http.StatusOK, res)
-	_ = p.cache.RemovePattern("*User*")
+}
+
+// RestoreUser godoc
+//
+//	@Summary	Restore a deleted user
+//	@Tags		users-admin
+//	@Produce	json
+//	@Security	ApiKeyAuth
+//	@Param		id	path		string	true	"User ID"
+//	@Success	200	{object}	dto.User
+//	@Router		/auth-admin/users/{id}/restore [post]
+func (p *UserHandler) RestoreUser(c *gin.Context) {
+	user, err := p.service.Restore(c, c.Param("id"))
+	if errors.Is(err, service.ErrUserNotDeleted) {
+		response.Error(c, http.StatusNotFound, err, err.Error())
+		return
+	}
+	if err != nil {
+		logger.Error("Failed to restore user ", err)
+		response.Error(c, http.StatusInternalServerError, err, "Something went wrong")
+		return
+	}
+
+	var res dto.User
+	utils.Copy(&res, &user)
+	response.JSON(c, http.StatusOK, res)
 }
 
 // Create godoc
@@ -116,8 +164,11 @@ func (p *UserHandler) DeleteAdmin(c *gin.Context) {
 //	@Tags		users-admin
 //	@Security	ApiKeyAuth
 //	@Produce	json
+//	@Param		Idempotency-Key	header	string	false	"Key of the request and its retries, which get the first response"
 //	@Param		_	body		dto.RegisterReq	true	"Body"
 //	@Success	200	{object}	dto.RegisterRes
+//	@Failure	409	{object}	response.Response	"The first request with the key is running"
+//	@Failure	422	{object}	response.Response	"The key was used with another body"
 //	@Router		/auth-admin/create [post]
 func (h *UserHandler) CreateAdmin(c *gin.Context) {
 	var req dto.RegisterReq
@@ -128,6 +179,10 @@ func (h *UserHandler) CreateAdmin(c *gin.Context) {
 	}
 
 	user, err := h.service.Register(c, &req)
+	if errors.Is(err, service.ErrRoleNotAllowed) {
+		response.Error(c, http.StatusBadRequest, err, "Clinic admins are added by their clinic")
+		return
+	}
 	if err != nil {
 		logger.Error(err.Error())
 		response.Error(c, http.StatusInternalServerError, err, "Something went wrong")
@@ -145,10 +200,17 @@ func (h *UserHandler) CreateAdmin(c *gin.Context) {
 //	@Tags		users-admin
 //	@Security	ApiKeyAuth
 //	@Produce	json
+//	@Param		If-Match	header	string	true	"ETag of my profile, its version"
 //	@Param		_	body	dto.UpdateUserReq	true	"Body"
 //	@Success	200	{object}	dto.UpdateUserRes
+//	@Failure	412	{object}	response.Response	"Changed since it was read"
 //	@Router		/auth-admin/update [put]
 func (h *UserHandler) UpdateAdmin(c *gin.Context) {
+	version, ok := response.IfMatch(c)
+	if !ok {
+		return
+	}
+
 	var req dto.UpdateUserReq
 	if err := c.ShouldBindJSON(&req); c.Request.Body == nil || err != nil {
 		logger.Error("Failed to get body", err)
@@ -156,8 +218,13 @@ func (h *UserHandler) UpdateAdmin(c *gin.Context) {
 		return
 	}
 
+	req.Version = version
 	userID := c.GetString("userId")
 	err := h.service.UpdateUser(c, userID, &req)
+	if errors.Is(err, dbs.ErrStaleVersion) {
+		response.StaleVersion(c, err)
+		return
+	}
 	if err != nil {
 		logger.Error(err.Error())
 		response.Error(c, http.StatusInternalServerError, err, "Something went wrong")
@@ -183,9 +250,12 @@ func (h *UserHandler) LoginAdmin(c *gin.Context) {
 	}
 
 	user, accessToken, refreshToken, err := h.service.Login(c, &req)
+	if mfaChallenge(c, user, err) {
+		return
+	}
 	if err != nil {
 		logger.Error("Failed to login ", err)
-		response.Error(c, http.StatusInternalServerError, err, "Something went wrong")
+		authError(c, err)
 		return
 	}
 
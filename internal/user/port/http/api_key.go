@@ -0,0 +1,145 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/quangdangfit/gocommon/logger"
+
+	"main/internal/user/dto"
+	"main/internal/user/service"
+	"main/pkg/response"
+	"main/pkg/utils"
+)
+
+type APIKeyHandler struct {
+	service service.IAPIKeyService
+}
+
+func NewAPIKeyHandler(service service.IAPIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{
+		service: service,
+	}
+}
+
+// CreateAPIKey ListAPIKeys RevokeAPIKey ClientToken
+
+// CreateAPIKey godoc
+//
+//	@Summary	Create an API key for a machine client
+//	@Tags		users-admin
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		_	body		dto.CreateAPIKeyReq	true	"Body"
+//	@Success	200	{object}	dto.CreateAPIKeyRes
+//	@Router		/auth-admin/api-keys [post]
+func (h *APIKeyHandler) CreateAPIKey(c *gin.Context) {
+	var req dto.CreateAPIKeyReq
+	if err := c.ShouldBindJSON(&req); c.Request.Body == nil || err != nil {
+		logger.Error("Failed to get body", err)
+		response.Error(c, http.StatusBadRequest, err, "Invalid parameters")
+		return
+	}
+
+	apiKey, key, err := h.service.CreateAPIKey(c, c.GetString("userId"), &req)
+	if err != nil {
+		logger.Error("Failed to create api key ", err)
+		response.Error(c, http.StatusBadRequest, err, err.Error())
+		return
+	}
+
+	res := dto.CreateAPIKeyRes{Key: key}
+	utils.Copy(&res.APIKey, &apiKey)
+	response.JSON(c, http.StatusOK, res)
+}
+
+// ListAPIKeys godoc
+//
+//	@Summary	List the API keys
+//	@Tags		users-admin
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Success	200	{object}	dto.ListAPIKeysRes
+//	@Router		/auth-admin/api-keys [get]
+func (h *APIKeyHandler) ListAPIKeys(c *gin.Context) {
+	apiKeys, err := h.service.ListAPIKeys(c)
+	if err != nil {
+		logger.Error("Failed to list api keys ", err)
+		response.Error(c, http.StatusInternalServerError, err, "Something went wrong")
+		return
+	}
+
+	var res dto.ListAPIKeysRes
+	utils.Copy(&res.APIKeys, &apiKeys)
+	response.JSON(c, http.StatusOK, res)
+}
+
+// RevokeAPIKey godoc
+//
+//	@Summary	Revoke an API key and the tokens obtained with it
+//	@Tags		users-admin
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		id	path	string	true	"API key ID"
+//	@Success	200
+//	@Router		/auth-admin/api-keys/{id} [delete]
+func (h *APIKeyHandler) RevokeAPIKey(c *gin.Context) {
+	err := h.service.RevokeAPIKey(c, c.Param("id"))
+	if errors.Is(err, service.ErrAPIKeyNotFound) {
+		response.Error(c, http.StatusNotFound, err, "API key not found")
+		return
+	}
+	if err != nil {
+		logger.Error("Failed to revoke api key ", err)
+		response.Error(c, http.StatusInternalServerError, err, "Something went wrong")
+		return
+	}
+
+	response.JSON(c, http.StatusOK, nil)
+}
+
+// ClientToken godoc
+//
+//	@Summary	Get a client token with the client credentials grant
+//	@Tags		users-oauth
+//	@Accept		x-www-form-urlencoded
+//	@Produce	json
+//	@Param		grant_type		formData	string	true	"client_credentials"
+//	@Param		client_id		formData	string	false	"API key ID, or use HTTP Basic"
+//	@Param		client_secret	formData	string	false	"API key, or use HTTP Basic"
+//	@Param		scope			formData	string	false	"Space separated scopes"
+//	@Success	200				{object}	dto.ClientTokenRes
+//	@Router		/oauth/token [post]
+func (h *APIKeyHandler) ClientToken(c *gin.Context) {
+	var req dto.ClientTokenReq
+	if err := c.ShouldBind(&req); err != nil {
+		oauthTokenError(c, http.StatusBadRequest, "invalid_request")
+		return
+	}
+	if id, secret, ok := c.Request.BasicAuth(); ok {
+		req.ClientID, req.ClientSecret = id, secret
+	}
+
+	res, err := h.service.ClientToken(c, &req)
+	switch {
+	case errors.Is(err, service.ErrInvalidClient):
+		c.Header("WWW-Authenticate", `Basic realm="oauth"`)
+		oauthTokenError(c, http.StatusUnauthorized, err.Error())
+	case errors.Is(err, service.ErrInvalidScope), errors.Is(err, service.ErrUnsupportedGrantType):
+		oauthTokenError(c, http.StatusBadRequest, err.Error())
+	case err != nil:
+		logger.Error("Failed to issue client token ", err)
+		oauthTokenError(c, http.StatusBadRequest, "invalid_request")
+	default:
+		c.Header("Cache-Control", "no-store")
+		c.JSON(http.StatusOK, res)
+	}
+}
+
+// oauthTokenError answers with the error format of RFC 6749 that OAuth
+// client libraries expect from a token endpoint
+func oauthTokenError(c *gin.Context, status int, code string) {
+	c.Header("Cache-Control", "no-store")
+	c.JSON(status, gin.H{"error": code})
+}
@@ -8,7 +8,12 @@ import (
 	"github.com/quangdangfit/gocommon/logger"
 
 	"main/internal/user/dto"
+	"main/internal/user/model"
 	"main/internal/user/service"
+	"main/pkg/dbs"
+	"main/pkg/imaging"
+	"main/pkg/patch"
+	"main/pkg/ratelimit"
 	"main/pkg/redis"
 	"main/pkg/response"
 	"main/pkg/utils"
@@ -19,8 +24,12 @@ type UserHandler struct {
 	service service.IUserService
 }
 
-func NewUserHandler(service service.IUserService) *UserHandler {
+func NewUserHandler(
+	cache redis.IRedis,
+	service service.IUserService,
+) *UserHandler {
 	return &UserHandler{
+		cache:   cache,
 		service: service,
 	}
 }
@@ -31,7 +40,9 @@ func NewUserHandler(service service.IUserService) *UserHandler {
 //	@Tags		users
 //	@Security	ApiKeyAuth
 //	@Produce	json
+//	@Param		If-None-Match	header	string	false	"ETag of the profile already read"
 //	@Success	200	{object}	dto.User
+//	@Success	304	"Not changed since If-None-Match"
 //	@Router		/auth/me [get]
 func (h *UserHandler) GetMe(c *gin.Context) {
 	userID := c.GetString("userId")
@@ -49,7 +60,93 @@ func (h *UserHandler) GetMe(c *gin.Context) {
 
 	var res dto.User
 	utils.Copy(&res, &user)
-	response.JSON(c, http.StatusOK, res)
+	response.Versioned(c, http.StatusOK, res.Version, res)
+}
+
+// PatchMe godoc
+//
+//	@Summary	Change some fields of my profile
+//	@Description	JSON Merge Patch (application/merge-patch+json, also for application/json) or JSON Patch (application/json-patch+json) of name, email and phone_number. A changed email or phone number must be verified again.
+//	@Tags		users
+//	@Security	ApiKeyAuth
+//	@Accept		json
+//	@Produce	json
+//	@Param		If-Match	header	string	true	"ETag of my profile, its version"
+//	@Param		_	body	dto.PatchUserReq	true	"Body"
+//	@Success	200	{object}	dto.User
+//	@Failure	409	{object}	response.Response	"Email used by another account or a test operation failed"
+//	@Failure	412	{object}	response.Response	"Changed since it was read"
+//	@Failure	415	{object}	response.Response	"Not a patch media type"
+//	@Failure	422	{object}	response.Response	"Invalid or read-only fields"
+//	@Router		/auth/me [patch]
+func (h *UserHandler) PatchMe(c *gin.Context) {
+	userID := c.GetString("userId")
+	if userID == "" {
+		response.Error(c, http.StatusUnauthorized, errors.New("unauthorized"), "Unauthorized")
+		return
+	}
+	version, ok := response.IfMatch(c)
+	if !ok {
+		return
+	}
+
+	changes, err := patch.FromRequest(c.Request)
+	if err != nil {
+		response.Error(c, patch.StatusCode(err), err, err.Error())
+		return
+	}
+
+	user, err := h.service.PatchUser(c, userID, version, changes)
+	switch {
+	case errors.Is(err, dbs.ErrStaleVersion):
+		response.StaleVersion(c, err)
+		return
+	case errors.Is(err, service.ErrEmailInUse):
+		response.Error(c, http.StatusConflict, err, err.Error())
+		return
+	case patch.StatusCode(err) != 0:
+		response.Error(c, patch.StatusCode(err), err, err.Error())
+		return
+	case err != nil:
+		logger.Error("Failed to patch user ", err)
+		response.Error(c, http.StatusInternalServerError, err, "Something went wrong")
+		return
+	}
+
+	var res dto.User
+	utils.Copy(&res, &user)
+	response.Versioned(c, http.StatusOK, res.Version, res)
+}
+
+// SetAvatar godoc
+//
+//	@Summary	Set an uploaded image as my picture
+//	@Tags		users
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		_	body	dto.SetAvatarReq	true	"Body"
+//	@Success	202
+//	@Router		/auth/avatar [put]
+func (h *UserHandler) SetAvatar(c *gin.Context) {
+	var req dto.SetAvatarReq
+	if err := c.ShouldBindJSON(&req); c.Request.Body == nil || err != nil {
+		logger.Error("Failed to get body", err)
+		response.Error(c, http.StatusBadRequest, err, "Invalid parameters")
+		return
+	}
+
+	if err := h.service.SetAvatar(c, c.GetString("userId"), &req); err != nil {
+		logger.Error("Failed to set avatar ", err)
+		if errors.Is(err, imaging.ErrQueueFull) {
+			response.Error(c, http.StatusServiceUnavailable, err, err.Error())
+			return
+		}
+		response.Error(c, http.StatusBadRequest, err, err.Error())
+		return
+	}
+
+	// the picture is set once resized
+	response.JSON(c, http.StatusAccepted, nil)
 }
 
 // GetMe godoc
@@ -68,15 +165,16 @@ func (h *UserHandler) RefreshToken(c *gin.Context) {
 		return
 	}
 
-	accessToken, err := h.service.RefreshToken(c, userID)
+	accessToken, refreshToken, err := h.service.RefreshToken(c, c.GetString("sessionId"), c.GetString("tokenId"))
 	if err != nil {
 		logger.Error("Failed to refresh token", err)
-		response.Error(c, http.StatusInternalServerError, err, "Something went wrong")
+		sessionError(c, err)
 		return
 	}
 
 	res := dto.RefreshTokenRes{
-		AccessToken: accessToken,
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
 	}
 	response.JSON(c, http.StatusOK, res)
 }
@@ -101,7 +199,7 @@ func (h *UserHandler) VerfiyCodeEmail(c *gin.Context) {
 	resp, err := h.service.VerifyEmail(c, req)
 	if err != nil {
 		logger.Error(err.Error())
-		response.Error(c, http.StatusInternalServerError, err, "Something went wrong")
+		authError(c, err)
 		return
 	}
 	response.JSON(c, http.StatusOK, resp)
@@ -127,7 +225,7 @@ func (h *UserHandler) VerfiyCodePhoneNumber(c *gin.Context) {
 	resp, err := h.service.VerifyPhoneNumber(c, req)
 	if err != nil {
 		logger.Error(err.Error())
-		response.Error(c, http.StatusInternalServerError, err, "Something went wrong")
+		authError(c, err)
 		return
 	}
 	response.JSON(c, http.StatusOK, resp)
@@ -185,74 +283,31 @@ func (h *UserHandler) VerfiyCodeEmailResend(c *gin.Context) {
 	response.JSON(c, http.StatusOK, resp)
 }
 
-// LoginWithGoogleHandler handles Google OAuth2 login flow
-
-// @Summary Login with Google
-//
-//	@Tags		users
-//
-// @Description Logs user in with Google OAuth2
-// @Accept json
-// @Produce json
-// @Param code query string true "Authorization code received from Google"
-//
-//	@Success	200	{object}	dto.LoginRes
-//
-// @Router /auth/google/callback [get]
-func (h *UserHandler) LoginWithGoogleHandler(c *gin.Context) {
-
-	code := c.Query("code")
-	if code == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Authorization code not provided"})
-		return
-	}
-	// var user dto.LoginRes
-	// Use a user service to handle login or registration
-	user, accessToken, refreshToken, err := h.service.LoginWithGoogle(c, code)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to login or register user"})
-		return
+// mfaChallenge responds with the MFA challenge instead of the tokens and
+// returns true when err asks for a second factor
+func mfaChallenge(c *gin.Context, user *model.User, err error) bool {
+	var challenge *service.MFARequiredError
+	if !errors.As(err, &challenge) {
+		return false
 	}
 
 	var res dto.LoginRes
 	utils.Copy(&res.User, &user)
-	res.AccessToken = accessToken
-	res.RefreshToken = refreshToken
+	res.MFARequired = true
+	res.MFAToken = challenge.Token
+	res.MFAEnrollmentRequired = challenge.EnrollmentRequired
 	response.JSON(c, http.StatusOK, res)
-
-	c.JSON(http.StatusOK, res)
+	return true
 }
 
-// HandleFacebookCallback handles Facebook OAuth2 callback
-
-// @Router /auth/facebook/callback [get]
-// @Summary Handles Facebook OAuth2 callback
-//
-//	@Tags		users
-//
-// @Param code query string true "Authorization Code from Facebook"
-// @Produce json
-//
-//	@Success	200	{object}	dto.LoginRes
-func (h *UserHandler) HandleFacebookCallback(c *gin.Context) {
-	code := c.Query("code")
-	if code == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid state or code parameter"})
-		return
-	}
-
-	// Use a user service to handle login or registration
-	user, accessToken, refreshToken, err := h.service.LoginWithFacebook(c, code)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+// authError responds 429 with Retry-After when the account is locked out
+// after repeated failures, and 500 otherwise
+func authError(c *gin.Context, err error) {
+	var lockedErr *ratelimit.LockedError
+	if errors.As(err, &lockedErr) {
+		response.TooManyRequests(c, lockedErr.RetryAfter, err)
 		return
 	}
 
-	var res dto.LoginRes
-	utils.Copy(&res.User, &user)
-	res.AccessToken = accessToken
-	res.RefreshToken = refreshToken
-	response.JSON(c, http.StatusOK, res)
-
-	c.JSON(http.StatusOK, res)
+	response.Error(c, http.StatusInternalServerError, err, "Something went wrong")
 }
@@ -0,0 +1,180 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/quangdangfit/gocommon/logger"
+
+	"main/internal/user/dto"
+	"main/internal/user/service"
+	"main/pkg/response"
+	"main/pkg/utils"
+)
+
+// EnrollMFA ConfirmMFA VerifyMFA RegenerateRecoveryCodes DisableMFA ResetUserMFA
+
+// EnrollMFA godoc
+//
+//	@Summary	Start TOTP enrollment
+//	@Tags		users-mfa
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Success	200	{object}	dto.EnrollMFARes
+//	@Router		/auth/mfa/enroll [post]
+func (h *UserHandler) EnrollMFA(c *gin.Context) {
+	userID := c.GetString("userId")
+	res, err := h.service.EnrollMFA(c, userID)
+	if err != nil {
+		logger.Error("Failed to enroll mfa ", err)
+		mfaError(c, err)
+		return
+	}
+
+	response.JSON(c, http.StatusOK, res)
+}
+
+// ConfirmMFA godoc
+//
+//	@Summary	Confirm TOTP enrollment and get recovery codes
+//	@Tags		users-mfa
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		_	body		dto.MFACodeReq	true	"Body"
+//	@Success	200	{object}	dto.RecoveryCodesRes
+//	@Router		/auth/mfa/confirm [post]
+func (h *UserHandler) ConfirmMFA(c *gin.Context) {
+	var req dto.MFACodeReq
+	if err := c.ShouldBindJSON(&req); c.Request.Body == nil || err != nil {
+		logger.Error("Failed to get body", err)
+		response.Error(c, http.StatusBadRequest, err, "Invalid parameters")
+		return
+	}
+
+	userID := c.GetString("userId")
+	codes, err := h.service.ConfirmMFA(c, userID, &req)
+	if err != nil {
+		logger.Error("Failed to confirm mfa ", err)
+		mfaError(c, err)
+		return
+	}
+
+	response.JSON(c, http.StatusOK, dto.RecoveryCodesRes{RecoveryCodes: codes})
+}
+
+// VerifyMFA godoc
+//
+//	@Summary	Exchange the login MFA token and a code for tokens
+//	@Tags		users-mfa
+//	@Produce	json
+//	@Param		_	body		dto.VerifyMFAReq	true	"Body"
+//	@Success	200	{object}	dto.LoginRes
+//	@Router		/auth/mfa/verify [post]
+func (h *UserHandler) VerifyMFA(c *gin.Context) {
+	var req dto.VerifyMFAReq
+	if err := c.ShouldBindJSON(&req); c.Request.Body == nil || err != nil {
+		logger.Error("Failed to get body", err)
+		response.Error(c, http.StatusBadRequest, err, "Invalid parameters")
+		return
+	}
+
+	user, accessToken, refreshToken, err := h.service.VerifyMFA(c, &req)
+	if err != nil {
+		logger.Error("Failed to verify mfa ", err)
+		mfaError(c, err)
+		return
+	}
+
+	var res dto.LoginRes
+	utils.Copy(&res.User, &user)
+	res.AccessToken = accessToken
+	res.RefreshToken = refreshToken
+	response.JSON(c, http.StatusOK, res)
+}
+
+// RegenerateRecoveryCodes godoc
+//
+//	@Summary	Replace the recovery codes
+//	@Tags		users-mfa
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		_	body		dto.MFACodeReq	true	"Body"
+//	@Success	200	{object}	dto.RecoveryCodesRes
+//	@Router		/auth/mfa/recovery-codes [post]
+func (h *UserHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	var req dto.MFACodeReq
+	if err := c.ShouldBindJSON(&req); c.Request.Body == nil || err != nil {
+		logger.Error("Failed to get body", err)
+		response.Error(c, http.StatusBadRequest, err, "Invalid parameters")
+		return
+	}
+
+	userID := c.GetString("userId")
+	codes, err := h.service.RegenerateRecoveryCodes(c, userID, &req)
+	if err != nil {
+		logger.Error("Failed to regenerate recovery codes ", err)
+		mfaError(c, err)
+		return
+	}
+
+	response.JSON(c, http.StatusOK, dto.RecoveryCodesRes{RecoveryCodes: codes})
+}
+
+// DisableMFA godoc
+//
+//	@Summary	Disable MFA
+//	@Tags		users-mfa
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		_	body	dto.DisableMFAReq	true	"Body"
+//	@Success	200
+//	@Router		/auth/mfa/disable [post]
+func (h *UserHandler) DisableMFA(c *gin.Context) {
+	var req dto.DisableMFAReq
+	if err := c.ShouldBindJSON(&req); c.Request.Body == nil || err != nil {
+		logger.Error("Failed to get body", err)
+		response.Error(c, http.StatusBadRequest, err, "Invalid parameters")
+		return
+	}
+
+	userID := c.GetString("userId")
+	if err := h.service.DisableMFA(c, userID, &req); err != nil {
+		logger.Error("Failed to disable mfa ", err)
+		mfaError(c, err)
+		return
+	}
+
+	response.JSON(c, http.StatusOK, nil)
+}
+
+// ResetUserMFA godoc
+//
+//	@Summary	Reset the MFA of a user
+//	@Tags		users-admin
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		id	path	string	true	"User ID"
+//	@Success	200
+//	@Router		/auth-admin/users/{id}/mfa/reset [post]
+func (h *UserHandler) ResetUserMFA(c *gin.Context) {
+	if err := h.service.ResetMFA(c, c.Param("id")); err != nil {
+		logger.Error("Failed to reset mfa ", err)
+		response.Error(c, http.StatusInternalServerError, err, "Something went wrong")
+		return
+	}
+
+	response.JSON(c, http.StatusOK, nil)
+}
+
+func mfaError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidMFACode), errors.Is(err, service.ErrInvalidMFAToken):
+		response.Error(c, http.StatusUnauthorized, err, "Invalid code")
+	case errors.Is(err, service.ErrMFAAlreadyEnabled), errors.Is(err, service.ErrMFANotEnrolled),
+		errors.Is(err, service.ErrMFAEnforced):
+		response.Error(c, http.StatusConflict, err, err.Error())
+	default:
+		authError(c, err)
+	}
+}
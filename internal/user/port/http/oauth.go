@@ -0,0 +1,145 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/quangdangfit/gocommon/logger"
+
+	"main/internal/user/dto"
+	"main/internal/user/service"
+	"main/pkg/oauth"
+	"main/pkg/response"
+	"main/pkg/utils"
+)
+
+// OAuthLogin OAuthLink OAuthCallback ListIdentities UnlinkIdentity
+
+// OAuthLogin godoc
+//
+//	@Summary	Redirect to the provider consent page
+//	@Tags		users-oauth
+//	@Param		provider	path	string	true	"google, facebook or the configured oidc name"
+//	@Success	302
+//	@Router		/auth/oauth/{provider}/login [get]
+func (h *UserHandler) OAuthLogin(c *gin.Context) {
+	url, err := h.service.OAuthURL(c, c.Param("provider"), "")
+	if err != nil {
+		logger.Error("Failed to start oauth login ", err)
+		oauthError(c, err)
+		return
+	}
+
+	c.Redirect(http.StatusFound, url)
+}
+
+// OAuthLink godoc
+//
+//	@Summary	Start linking a provider account to the signed-in user
+//	@Tags		users-oauth
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		provider	path		string	true	"google, facebook or the configured oidc name"
+//	@Success	200			{object}	dto.OAuthURLRes
+//	@Router		/auth/oauth/{provider}/link [post]
+func (h *UserHandler) OAuthLink(c *gin.Context) {
+	userID := c.GetString("userId")
+	url, err := h.service.OAuthURL(c, c.Param("provider"), userID)
+	if err != nil {
+		logger.Error("Failed to start oauth link ", err)
+		oauthError(c, err)
+		return
+	}
+
+	response.JSON(c, http.StatusOK, dto.OAuthURLRes{AuthorizationURL: url})
+}
+
+// OAuthCallback godoc
+//
+//	@Summary	Complete a provider login or link
+//	@Tags		users-oauth
+//	@Produce	json
+//	@Param		provider	path		string	true	"google, facebook or the configured oidc name"
+//	@Param		state		query		string	true	"State returned by the provider"
+//	@Param		code		query		string	true	"Authorization code returned by the provider"
+//	@Success	200			{object}	dto.LoginRes
+//	@Router		/auth/oauth/{provider}/callback [get]
+func (h *UserHandler) OAuthCallback(c *gin.Context) {
+	if errMsg := c.Query("error"); errMsg != "" {
+		response.Error(c, http.StatusUnauthorized, errors.New(errMsg), "Login cancelled")
+		return
+	}
+
+	user, accessToken, refreshToken, err := h.service.OAuthLogin(c, c.Param("provider"), c.Query("state"), c.Query("code"))
+	if mfaChallenge(c, user, err) {
+		return
+	}
+	if err != nil {
+		logger.Error("Failed to login with oauth ", err)
+		oauthError(c, err)
+		return
+	}
+
+	var res dto.LoginRes
+	utils.Copy(&res.User, &user)
+	res.AccessToken = accessToken
+	res.RefreshToken = refreshToken
+	response.JSON(c, http.StatusOK, res)
+}
+
+// ListIdentities godoc
+//
+//	@Summary	List the provider accounts linked to the signed-in user
+//	@Tags		users-oauth
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Success	200	{object}	dto.ListIdentitiesRes
+//	@Router		/auth/identities [get]
+func (h *UserHandler) ListIdentities(c *gin.Context) {
+	identities, err := h.service.ListIdentities(c, c.GetString("userId"))
+	if err != nil {
+		logger.Error("Failed to list identities ", err)
+		response.Error(c, http.StatusInternalServerError, err, "Something went wrong")
+		return
+	}
+
+	var res dto.ListIdentitiesRes
+	utils.Copy(&res.Identities, &identities)
+	response.JSON(c, http.StatusOK, res)
+}
+
+// UnlinkIdentity godoc
+//
+//	@Summary	Unlink a provider account from the signed-in user
+//	@Tags		users-oauth
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		provider	path	string	true	"Provider name"
+//	@Success	200
+//	@Router		/auth/identities/{provider} [delete]
+func (h *UserHandler) UnlinkIdentity(c *gin.Context) {
+	if err := h.service.UnlinkIdentity(c, c.GetString("userId"), c.Param("provider")); err != nil {
+		logger.Error("Failed to unlink identity ", err)
+		oauthError(c, err)
+		return
+	}
+
+	response.JSON(c, http.StatusOK, nil)
+}
+
+func oauthError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, oauth.ErrUnknownProvider):
+		response.Error(c, http.StatusNotFound, err, "Unknown provider")
+	case errors.Is(err, oauth.ErrInvalidState):
+		response.Error(c, http.StatusBadRequest, err, "Login expired, please try again")
+	case errors.Is(err, service.ErrAccountExists), errors.Is(err, service.ErrIdentityLinked),
+		errors.Is(err, service.ErrLastLoginMethod):
+		response.Error(c, http.StatusConflict, err, err.Error())
+	case errors.Is(err, service.ErrEmailRequired):
+		response.Error(c, http.StatusBadRequest, err, err.Error())
+	default:
+		authError(c, err)
+	}
+}
@@ -1,6 +1,7 @@
 package http
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -8,6 +9,7 @@ import (
 
 	"main/internal/user/dto"
 	"main/internal/user/model"
+	"main/pkg/dbs"
 	"main/pkg/response"
 	"main/pkg/utils"
 )
@@ -35,9 +37,12 @@ func (h *UserHandler) LoginPatient(c *gin.Context) {
 		Role:     model.UserRoleClient,
 	}
 	user, accessToken, refreshToken, err := h.service.Login(c, &req2)
+	if mfaChallenge(c, user, err) {
+		return
+	}
 	if err != nil {
 		logger.Error("Failed to login ", err)
-		response.Error(c, http.StatusInternalServerError, err, "Something went wrong")
+		authError(c, err)
 		return
 	}
 
@@ -53,8 +58,11 @@ func (h *UserHandler) LoginPatient(c *gin.Context) {
 //	@Summary	Register new user
 //	@Tags		users-patient
 //	@Produce	json
+//	@Param		Idempotency-Key	header	string	false	"Key of the request and its retries, which get the first response"
 //	@Param		_	body		dto.KRegisterReq	true	"Body"
 //	@Success	200	{object}	dto.RegisterRes
+//	@Failure	409	{object}	response.Response	"The first request with the key is running"
+//	@Failure	422	{object}	response.Response	"The key was used with another body"
 //	@Router		/auth-patient/register [post]
 func (h *UserHandler) RegisterPatient(c *gin.Context) {
 	var req dto.KRegisterReq
@@ -88,10 +96,17 @@ func (h *UserHandler) RegisterPatient(c *gin.Context) {
 //	@Tags		users-patient
 //	@Security	ApiKeyAuth
 //	@Produce	json
+//	@Param		If-Match	header	string	true	"ETag of my profile, its version"
 //	@Param		_	body	dto.KUpdateUserReq	true	"Body"
 //	@Success	200	{object}	dto.UpdateUserRes
+//	@Failure	412	{object}	response.Response	"Changed since it was read"
 //	@Router		/auth-patient/update-user [put]
 func (h *UserHandler) UpdatePatient(c *gin.Context) {
+	version, ok := response.IfMatch(c)
+	if !ok {
+		return
+	}
+
 	var req dto.KUpdateUserReq
 	if err := c.ShouldBindJSON(&req); c.Request.Body == nil || err != nil {
 		logger.Error("Failed to get body", err)
@@ -106,9 +121,14 @@ func (h *UserHandler) UpdatePatient(c *gin.Context) {
 		Name:        req.Name,
 		PhoneNumber: req.PhoneNumber,
 		Role:        model.UserRoleClient,
+		Version:     version,
 	}
 	userID := c.GetString("userId")
 	err := h.service.UpdateUser(c, userID, &req2)
+	if errors.Is(err, dbs.ErrStaleVersion) {
+		response.StaleVersion(c, err)
+		return
+	}
 	if err != nil {
 		logger.Error(err.Error())
 		response.Error(c, http.StatusInternalServerError, err, "Something went wrong")
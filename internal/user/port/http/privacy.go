@@ -0,0 +1,157 @@
+package http
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/quangdangfit/gocommon/logger"
+	"gorm.io/gorm"
+
+	"main/internal/user/dto"
+	"main/internal/user/service"
+	"main/pkg/response"
+	"main/pkg/utils"
+)
+
+type PrivacyHandler struct {
+	service service.IPrivacyService
+}
+
+func NewPrivacyHandler(service service.IPrivacyService) *PrivacyHandler {
+	return &PrivacyHandler{
+		service: service,
+	}
+}
+
+// RequestExport ListDataRequests DownloadExport RequestErasure CancelErasure
+
+// RequestExport godoc
+//
+//	@Summary	Request an archive of my personal data
+//	@Tags		users-privacy
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Success	202	{object}	dto.DataRequest
+//	@Router		/auth/account/export [post]
+func (h *PrivacyHandler) RequestExport(c *gin.Context) {
+	request, err := h.service.RequestExport(c, c.GetString("userId"))
+	if err != nil {
+		logger.Error("Failed to request export ", err)
+		privacyError(c, err)
+		return
+	}
+
+	var res dto.DataRequest
+	utils.Copy(&res, &request)
+	response.JSON(c, http.StatusAccepted, res)
+}
+
+// ListDataRequests godoc
+//
+//	@Summary	List my export and erasure requests
+//	@Tags		users-privacy
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Success	200	{object}	dto.ListDataRequestsRes
+//	@Router		/auth/account/data-requests [get]
+func (h *PrivacyHandler) ListDataRequests(c *gin.Context) {
+	requests, err := h.service.ListDataRequests(c, c.GetString("userId"))
+	if err != nil {
+		logger.Error("Failed to list data requests ", err)
+		privacyError(c, err)
+		return
+	}
+
+	var res dto.ListDataRequestsRes
+	utils.Copy(&res.DataRequests, &requests)
+	response.JSON(c, http.StatusOK, res)
+}
+
+// DownloadExport godoc
+//
+//	@Summary	Download the archive of a completed export
+//	@Tags		users-privacy
+//	@Security	ApiKeyAuth
+//	@Produce	application/zip
+//	@Param		id	path	string	true	"Data request ID"
+//	@Success	200
+//	@Router		/auth/account/export/{id} [get]
+func (h *PrivacyHandler) DownloadExport(c *gin.Context) {
+	request, content, err := h.service.DownloadExport(c, c.GetString("userId"), c.Param("id"))
+	if err != nil {
+		logger.Error("Failed to download export ", err)
+		privacyError(c, err)
+		return
+	}
+	defer content.Close()
+
+	name := "personal-data-" + request.CreatedAt.Format("2006-01-02") + ".zip"
+	c.DataFromReader(http.StatusOK, request.Size, "application/zip", content, map[string]string{
+		"Content-Disposition":    mime.FormatMediaType("attachment", map[string]string{"filename": name}),
+		"Cache-Control":          "private, no-store",
+		"X-Content-Type-Options": "nosniff",
+	})
+}
+
+// RequestErasure godoc
+//
+//	@Summary	Delete my account once the grace period is over
+//	@Tags		users-privacy
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		_	body		dto.RequestErasureReq	true	"Body"
+//	@Success	202	{object}	dto.DataRequest
+//	@Router		/auth/account/erasure [post]
+func (h *PrivacyHandler) RequestErasure(c *gin.Context) {
+	var req dto.RequestErasureReq
+	if err := c.ShouldBindJSON(&req); c.Request.Body == nil || err != nil {
+		logger.Error("Failed to get body", err)
+		response.Error(c, http.StatusBadRequest, err, "Invalid parameters")
+		return
+	}
+
+	request, err := h.service.RequestErasure(c, c.GetString("userId"), &req)
+	if err != nil {
+		logger.Error("Failed to request erasure ", err)
+		privacyError(c, err)
+		return
+	}
+
+	var res dto.DataRequest
+	utils.Copy(&res, &request)
+	response.JSON(c, http.StatusAccepted, res)
+}
+
+// CancelErasure godoc
+//
+//	@Summary	Cancel the pending deletion of my account
+//	@Tags		users-privacy
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Success	200
+//	@Router		/auth/account/erasure [delete]
+func (h *PrivacyHandler) CancelErasure(c *gin.Context) {
+	if err := h.service.CancelErasure(c, c.GetString("userId")); err != nil {
+		logger.Error("Failed to cancel erasure ", err)
+		privacyError(c, err)
+		return
+	}
+
+	response.JSON(c, http.StatusOK, nil)
+}
+
+func privacyError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrDataRequestNotFound), errors.Is(err, service.ErrNoErasure),
+		errors.Is(err, gorm.ErrRecordNotFound):
+		response.Error(c, http.StatusNotFound, err, err.Error())
+	case errors.Is(err, service.ErrDataRequestPending), errors.Is(err, service.ErrExportNotReady):
+		response.Error(c, http.StatusConflict, err, err.Error())
+	case errors.Is(err, service.ErrWrongPassword):
+		response.Error(c, http.StatusUnauthorized, err, "Wrong password")
+	default:
+		response.Error(c, http.StatusInternalServerError, err, "Something went wrong")
+	}
+}
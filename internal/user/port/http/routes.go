@@ -3,58 +3,123 @@ package http
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/quangdangfit/gocommon/validation"
-	"golang.org/x/oauth2"
 
 	"main/internal/user/repository"
 	"main/internal/user/service"
+	"main/pkg/audit"
+	"main/pkg/config"
 	"main/pkg/dbs"
+	"main/pkg/events"
+	"main/pkg/idempotency"
 	"main/pkg/middleware"
+	"main/pkg/notify"
+	"main/pkg/oauth"
+	"main/pkg/ratelimit"
+	"main/pkg/rbac"
+	"main/pkg/redis"
+	"main/pkg/storage"
 )
 
-func Routes(r *gin.RouterGroup, sqlDB dbs.IDatabase, validator validation.Validation, fbOauthConfig *oauth2.Config, oauthConfig *oauth2.Config) {
+func Routes(r *gin.RouterGroup, sqlDB dbs.IDatabase, validator validation.Validation, cache redis.IRedis, oauthProviders *oauth.Registry, auth *middleware.Authenticator, keys *idempotency.Keys, images service.ImageProcessor, store storage.Storage, files service.FileRemover, recorder audit.Recorder, publisher events.Publisher, notifier notify.Notifier) {
+	cfg := config.GetConfig()
 	userRepo := repository.NewUserRepository(sqlDB)
-	userSvc := service.NewUserService(validator, oauthConfig, fbOauthConfig, userRepo)
-	userHandler := NewUserHandler(userSvc)
+	oauthFlow := oauth.NewFlow(oauthProviders, oauth.NewStateStore(cache))
+	userSvc := service.NewUserService(validator, oauthFlow, userRepo, ratelimit.LockoutFromConfig(cache, cfg), auth.Sessions(), images, recorder, publisher, notifier)
+	userHandler := NewUserHandler(cache, userSvc)
+	apiKeySvc := service.NewAPIKeyService(validator, userRepo, cache, auth.Sessions(), recorder)
+	apiKeyHandler := NewAPIKeyHandler(apiKeySvc)
+	privacySvc := service.NewPrivacyService(validator, userRepo, store, files, auth.Sessions(), recorder, publisher, cfg.DataExportTTL, cfg.ErasureGracePeriod)
+	privacyHandler := NewPrivacyHandler(privacySvc)
 
-	authMiddleware := middleware.JWTAuth()
-	refreshAuthMiddleware := middleware.JWTRefresh()
+	authMiddleware := middleware.JWTAuth(auth)
+	refreshAuthMiddleware := middleware.JWTRefresh(auth)
+	mfaAuthMiddleware := middleware.JWTAuthOrMFA(auth)
+	usersRead := middleware.JWTPermission(auth, rbac.UsersRead)
+	usersWrite := middleware.JWTPermission(auth, rbac.UsersWrite)
+	usersAdmin := middleware.JWTPermission(auth, rbac.UsersAdmin)
+	apiKeysManage := middleware.JWTPermission(auth, rbac.APIKeysManage)
+	deletedManage := middleware.JWTPermission(auth, rbac.DeletedManage)
+	// retried registrations return the first account
+	idempotent := middleware.Idempotency(keys)
+
+	limiter := ratelimit.New(cache)
+	rules := ratelimit.RulesFromConfig(cfg)
+	loginLimit := middleware.RateLimit(limiter, rules.Login, middleware.RateLimitByIP(), middleware.RateLimitByEmail())
+	registerLimit := middleware.RateLimit(limiter, rules.Register, middleware.RateLimitByIP())
+	verifyLimit := middleware.RateLimit(limiter, rules.Verify, middleware.RateLimitByIP(), middleware.RateLimitByUserID())
+	mfaVerifyLimit := middleware.RateLimit(limiter, rules.Verify, middleware.RateLimitByIP())
 
 	// GetMe RefreshToken  --  VerfiyCodeEmail  VerfiyCodePhoneNumber  VerfiyCodePhoneNumberResend VerfiyCodeEmailResend
 	authRoute := r.Group("/auth")
 	{
-		authRoute.GET("/google/callback", userHandler.LoginWithGoogleHandler)
-		authRoute.GET("/facebook/callback", userHandler.HandleFacebookCallback)
+		// google, facebook and the configured oidc provider
+		authRoute.GET("/oauth/:provider/login", loginLimit, userHandler.OAuthLogin)
+		authRoute.GET("/oauth/:provider/callback", loginLimit, userHandler.OAuthCallback)
+		authRoute.POST("/oauth/:provider/link", authMiddleware, userHandler.OAuthLink)
+		authRoute.GET("/identities", authMiddleware, userHandler.ListIdentities)
+		authRoute.DELETE("/identities/:provider", authMiddleware, userHandler.UnlinkIdentity)
 		authRoute.GET("/me", authMiddleware, userHandler.GetMe)
+		authRoute.PATCH("/me", authMiddleware, userHandler.PatchMe)
+		authRoute.PUT("/avatar", authMiddleware, userHandler.SetAvatar)
 		authRoute.POST("/refresh-token", refreshAuthMiddleware, userHandler.RefreshToken)
 		//for doctor or Patient only
-		authRoute.PUT("/verfiy-code-email", authMiddleware, userHandler.VerfiyCodeEmail)
-		authRoute.PUT("/verfiy-code-phone-number", authMiddleware, userHandler.VerfiyCodePhoneNumber)
-		authRoute.PUT("/resend-verfiy-code-phone-number", authMiddleware, userHandler.VerfiyCodePhoneNumberResend)
-		authRoute.PUT("/resend-verfiy-code-email", authMiddleware, userHandler.VerfiyCodeEmailResend)
+		authRoute.PUT("/verfiy-code-email", authMiddleware, verifyLimit, userHandler.VerfiyCodeEmail)
+		authRoute.PUT("/verfiy-code-phone-number", authMiddleware, verifyLimit, userHandler.VerfiyCodePhoneNumber)
+		authRoute.PUT("/resend-verfiy-code-phone-number", authMiddleware, verifyLimit, userHandler.VerfiyCodePhoneNumberResend)
+		authRoute.PUT("/resend-verfiy-code-email", authMiddleware, verifyLimit, userHandler.VerfiyCodeEmailResend)
+		// two-factor authentication, enroll and confirm also accept the login
+		// MFA token so roles enforcing MFA can enroll
+		authRoute.POST("/mfa/verify", mfaVerifyLimit, userHandler.VerifyMFA)
+		authRoute.POST("/mfa/enroll", mfaAuthMiddleware, userHandler.EnrollMFA)
+		authRoute.POST("/mfa/confirm", mfaAuthMiddleware, verifyLimit, userHandler.ConfirmMFA)
+		authRoute.POST("/mfa/recovery-codes", authMiddleware, verifyLimit, userHandler.RegenerateRecoveryCodes)
+		authRoute.POST("/mfa/disable", authMiddleware, verifyLimit, userHandler.DisableMFA)
+		// devices the user is logged in on, DELETE /sessions/current logs out
+		authRoute.GET("/sessions", authMiddleware, userHandler.ListSessions)
+		authRoute.DELETE("/sessions", authMiddleware, userHandler.RevokeOtherSessions)
+		authRoute.DELETE("/sessions/:id", authMiddleware, userHandler.RevokeSession)
+		// download my data and delete my account, processed in the background
+		authRoute.POST("/account/export", authMiddleware, privacyHandler.RequestExport)
+		authRoute.GET("/account/export/:id", authMiddleware, privacyHandler.DownloadExport)
+		authRoute.GET("/account/data-requests", authMiddleware, privacyHandler.ListDataRequests)
+		authRoute.POST("/account/erasure", authMiddleware, verifyLimit, privacyHandler.RequestErasure)
+		authRoute.DELETE("/account/erasure", authMiddleware, privacyHandler.CancelErasure)
 	}
 
 	// ListUsers DeleteAdmin CreateAdmin UpdateAdmin LoginAdmin
 	authRouteAdmin := r.Group("/auth-admin")
 	{
-		authRouteAdmin.POST("/login", userHandler.LoginAdmin)
-		authRouteAdmin.POST("/create", userHandler.CreateAdmin)
+		authRouteAdmin.POST("/login", loginLimit, userHandler.LoginAdmin)
+		authRouteAdmin.POST("/create", registerLimit, idempotent, userHandler.CreateAdmin)
 		authRouteAdmin.PUT("/update", authMiddleware, userHandler.UpdateAdmin)
-		authRouteAdmin.GET("/users", authMiddleware, userHandler.ListUsers)
-		authRouteAdmin.DELETE("/", authMiddleware, userHandler.DeleteAdmin)
+		authRouteAdmin.GET("/users", usersRead, userHandler.ListUsers)
+		authRouteAdmin.DELETE("/", usersWrite, userHandler.DeleteAdmin)
+		authRouteAdmin.POST("/users/:id/restore", deletedManage, userHandler.RestoreUser)
+		authRouteAdmin.POST("/users/:id/mfa/reset", usersAdmin, userHandler.ResetUserMFA)
+		authRouteAdmin.GET("/users/:id/sessions", usersAdmin, userHandler.ListUserSessions)
+		authRouteAdmin.DELETE("/users/:id/sessions", usersAdmin, userHandler.RevokeUserSessions)
+		authRouteAdmin.DELETE("/users/:id/sessions/:sessionId", usersAdmin, userHandler.RevokeUserSession)
+		// machine clients, the key is only returned by create
+		authRouteAdmin.POST("/api-keys", apiKeysManage, apiKeyHandler.CreateAPIKey)
+		authRouteAdmin.GET("/api-keys", apiKeysManage, apiKeyHandler.ListAPIKeys)
+		authRouteAdmin.DELETE("/api-keys/:id", apiKeysManage, apiKeyHandler.RevokeAPIKey)
 	}
 
+	// client credentials grant for machine clients
+	r.POST("/oauth/token", loginLimit, apiKeyHandler.ClientToken)
+
 	// LoginDoctor RegisterDoctor UpdateDoctor
 	authRouteDoctor := r.Group("/auth-doctor")
 	{
-		authRouteDoctor.POST("/login", userHandler.LoginDoctor)
-		authRouteDoctor.POST("/register", userHandler.RegisterDoctor)
+		authRouteDoctor.POST("/login", loginLimit, userHandler.LoginDoctor)
+		authRouteDoctor.POST("/register", registerLimit, idempotent, userHandler.RegisterDoctor)
 		authRouteDoctor.PUT("/update-user", authMiddleware, userHandler.UpdateDoctor)
 	}
 	// LoginPatient RegisterPatient UpdatePatient
 	authRoutePatient := r.Group("/auth-patient")
 	{
-		authRoutePatient.POST("/login", userHandler.LoginPatient)
-		authRoutePatient.POST("/register", userHandler.RegisterPatient)
+		authRoutePatient.POST("/login", loginLimit, userHandler.LoginPatient)
+		authRoutePatient.POST("/register", registerLimit, idempotent, userHandler.RegisterPatient)
 		authRoutePatient.PUT("/update-user", authMiddleware, userHandler.UpdatePatient)
 	}
 }
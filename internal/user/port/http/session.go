@@ -0,0 +1,154 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/quangdangfit/gocommon/logger"
+
+	"main/internal/user/dto"
+	"main/internal/user/service"
+	"main/pkg/response"
+	"main/pkg/utils"
+)
+
+// currentSession is accepted instead of a session id for the session of the
+// request
+const currentSession = "current"
+
+// ListSessions RevokeSession RevokeOtherSessions ListUserSessions RevokeUserSession RevokeUserSessions
+
+// ListSessions godoc
+//
+//	@Summary	List where the signed-in user is logged in
+//	@Tags		users-sessions
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Success	200	{object}	dto.ListSessionsRes
+//	@Router		/auth/sessions [get]
+func (h *UserHandler) ListSessions(c *gin.Context) {
+	h.listSessions(c, c.GetString("userId"))
+}
+
+// RevokeSession godoc
+//
+//	@Summary	Log out a session of the signed-in user
+//	@Tags		users-sessions
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		id	path	string	true	"Session ID, or current"
+//	@Success	200
+//	@Router		/auth/sessions/{id} [delete]
+func (h *UserHandler) RevokeSession(c *gin.Context) {
+	sessionID := c.Param("id")
+	if sessionID == currentSession {
+		sessionID = c.GetString("sessionId")
+	}
+
+	if err := h.service.RevokeSession(c, c.GetString("userId"), sessionID); err != nil {
+		logger.Error("Failed to revoke session ", err)
+		sessionError(c, err)
+		return
+	}
+
+	response.JSON(c, http.StatusOK, nil)
+}
+
+// RevokeOtherSessions godoc
+//
+//	@Summary	Log out every other session of the signed-in user
+//	@Tags		users-sessions
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Success	200
+//	@Router		/auth/sessions [delete]
+func (h *UserHandler) RevokeOtherSessions(c *gin.Context) {
+	if err := h.service.RevokeOtherSessions(c, c.GetString("userId"), c.GetString("sessionId")); err != nil {
+		logger.Error("Failed to revoke sessions ", err)
+		sessionError(c, err)
+		return
+	}
+
+	response.JSON(c, http.StatusOK, nil)
+}
+
+// ListUserSessions godoc
+//
+//	@Summary	List the sessions of a user
+//	@Tags		users-admin
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		id	path		string	true	"User ID"
+//	@Success	200	{object}	dto.ListSessionsRes
+//	@Router		/auth-admin/users/{id}/sessions [get]
+func (h *UserHandler) ListUserSessions(c *gin.Context) {
+	h.listSessions(c, c.Param("id"))
+}
+
+// RevokeUserSession godoc
+//
+//	@Summary	Log out a session of a user
+//	@Tags		users-admin
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		id			path	string	true	"User ID"
+//	@Param		sessionId	path	string	true	"Session ID"
+//	@Success	200
+//	@Router		/auth-admin/users/{id}/sessions/{sessionId} [delete]
+func (h *UserHandler) RevokeUserSession(c *gin.Context) {
+	if err := h.service.RevokeSession(c, c.Param("id"), c.Param("sessionId")); err != nil {
+		logger.Error("Failed to revoke session ", err)
+		sessionError(c, err)
+		return
+	}
+
+	response.JSON(c, http.StatusOK, nil)
+}
+
+// RevokeUserSessions godoc
+//
+//	@Summary	Log out every session of a user
+//	@Tags		users-admin
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		id	path	string	true	"User ID"
+//	@Success	200
+//	@Router		/auth-admin/users/{id}/sessions [delete]
+func (h *UserHandler) RevokeUserSessions(c *gin.Context) {
+	if err := h.service.RevokeOtherSessions(c, c.Param("id"), ""); err != nil {
+		logger.Error("Failed to revoke sessions ", err)
+		sessionError(c, err)
+		return
+	}
+
+	response.JSON(c, http.StatusOK, nil)
+}
+
+func (h *UserHandler) listSessions(c *gin.Context, userID string) {
+	sessions, err := h.service.ListSessions(c, userID)
+	if err != nil {
+		logger.Error("Failed to list sessions ", err)
+		sessionError(c, err)
+		return
+	}
+
+	var res dto.ListSessionsRes
+	utils.Copy(&res.Sessions, &sessions)
+	currentID := c.GetString("sessionId")
+	for _, s := range res.Sessions {
+		s.Current = s.ID == currentID
+	}
+	response.JSON(c, http.StatusOK, res)
+}
+
+func sessionError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrSessionNotFound):
+		response.Error(c, http.StatusNotFound, err, "Session not found")
+	case errors.Is(err, service.ErrSessionRevoked), errors.Is(err, service.ErrInvalidRefreshToken):
+		response.Error(c, http.StatusUnauthorized, err, "Session expired, please log in again")
+	default:
+		response.Error(c, http.StatusInternalServerError, err, "Something went wrong")
+	}
+}
@@ -0,0 +1,19 @@
+package subscriber
+
+import (
+	"context"
+
+	"main/pkg/events"
+	"main/pkg/redis"
+	"main/pkg/tenant"
+)
+
+// Subscribe removes the cached users and lists of users when a user changes
+func Subscribe(ctx context.Context, bus *events.Bus, cache redis.IRedis) error {
+	return bus.Subscribe(ctx, "user-cache", func(ctx context.Context, _ *events.Event) error {
+		if err := cache.RemovePattern(ctx, tenant.CachePrefix+"*users*"); err != nil {
+			return err
+		}
+		return cache.RemovePattern(ctx, tenant.CachePrefix+"*User*")
+	}, events.UserRegistered, events.EmailVerified, events.PhoneVerified, events.UserUpdated, events.UserDeleted)
+}
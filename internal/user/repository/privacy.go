@@ -0,0 +1,355 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	addressModel "main/internal/address/model"
+	auditModel "main/internal/audit/model"
+	consultationModel "main/internal/consultation/model"
+	doctorModel "main/internal/doctor/model"
+	fileModel "main/internal/file/model"
+	healthModel "main/internal/health/model"
+	notificationModel "main/internal/notification/model"
+	reviewModel "main/internal/review/model"
+	specialtyModel "main/internal/specialty/model"
+	"main/internal/user/model"
+	"main/pkg/audit"
+	"main/pkg/dbs"
+	"main/pkg/tenant"
+)
+
+// PersonalData is what is stored about a user, bundled by a data export
+type PersonalData struct {
+	User              *model.User                       `json:"user"`
+	Identities        []*model.UserIdentity             `json:"identities"`
+	Sessions          []*model.Session                  `json:"sessions"`
+	Addresses         []*addressModel.Address           `json:"addresses"`
+	Doctors           []*doctorModel.Doctor             `json:"doctors"`
+	Verifications     []*doctorModel.Verification       `json:"verifications"`
+	Reviews           []*reviewModel.Review             `json:"reviews"`
+	HealthProfile     *healthModel.Profile              `json:"health_profile"`
+	Allergies         []*healthModel.Allergy            `json:"allergies"`
+	Conditions        []*healthModel.Condition          `json:"conditions"`
+	Medications       []*healthModel.Medication         `json:"medications"`
+	Vitals            []*healthModel.Vital              `json:"vitals"`
+	HealthGrants      []*healthModel.Grant              `json:"health_grants"`
+	Consultations     []*consultationModel.Consultation `json:"consultations"`
+	Files             []*fileModel.File                 `json:"files"`
+	AuditEvents       []*auditModel.Event               `json:"audit_events"`
+	DataRequests      []*model.DataRequest              `json:"data_requests"`
+	Notifications     []*notificationModel.Notification `json:"notifications"`
+	NotificationPrefs []*notificationModel.Preference   `json:"notification_preferences"`
+	RecoveryCodesLeft int64                             `json:"recovery_codes_left"`
+}
+
+// Erasure is what erasing a user removed outside of the database
+type Erasure struct {
+	// Sessions are the revoked sessions
+	Sessions []string
+	// Files are the uploaded files of the user
+	Files []*fileModel.File
+	// Exports are the storage keys of the export archives
+	Exports []string
+}
+
+// erasedUser is the condition of the users whose erasure was completed
+const erasedUser = `EXISTS (SELECT 1 FROM user_data_requests r
+	WHERE r.user_id = users.id AND r.kind = 'erasure' AND r.status = 'completed')`
+
+// Restore restores the deleted user id, false when it is not deleted or
+// was erased
+func (r *UserRepo) Restore(ctx context.Context, id string) (bool, error) {
+	return dbs.Restore(r.db.GetDB().WithContext(ctx).Where("NOT "+erasedUser), &model.User{}, id)
+}
+
+// ListPurgeableUsers returns the users deleted before before that are
+// neither erased nor waiting for their erasure
+func (r *UserRepo) ListPurgeableUsers(ctx context.Context, before time.Time) ([]string, error) {
+	var ids []string
+	err := r.db.GetDB().WithContext(tenant.Global(ctx)).Unscoped().Model(&model.User{}).
+		Where("deleted_at < ? AND NOT "+erasedUser, before).
+		Where(`NOT EXISTS (SELECT 1 FROM user_data_requests r
+			WHERE r.user_id = users.id AND r.kind = 'erasure' AND r.status IN ('pending', 'processing'))`).
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// CreateDataRequest creates request, false when the user already has a
+// pending request of its kind
+func (r *UserRepo) CreateDataRequest(ctx context.Context, request *model.DataRequest) (bool, error) {
+	result := r.db.GetDB().WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(request)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *UserRepo) GetDataRequest(ctx context.Context, userID, id string) (*model.DataRequest, error) {
+	var request model.DataRequest
+	if err := r.db.GetDB().WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).First(&request).Error; err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+func (r *UserRepo) ListDataRequests(ctx context.Context, userID string) ([]*model.DataRequest, error) {
+	var requests []*model.DataRequest
+	if err := r.db.GetDB().WithContext(ctx).Where("user_id = ?", userID).Order("created_at DESC").Find(&requests).Error; err != nil {
+		return nil, err
+	}
+	return requests, nil
+}
+
+// CancelErasure cancels the pending erasure of userID, false when there is
+// none
+func (r *UserRepo) CancelErasure(ctx context.Context, userID string) (bool, error) {
+	result := r.db.GetDB().WithContext(ctx).Model(&model.DataRequest{}).
+		Where("user_id = ? AND kind = ? AND status = ?", userID, model.AccountErasure, model.DataRequestPending).
+		Update("status", model.DataRequestCancelled)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ClaimDataRequest marks the oldest pending request due at now as
+// processing and returns it, nil when none is due. Concurrent workers never
+// claim the same request.
+func (r *UserRepo) ClaimDataRequest(ctx context.Context, now time.Time) (*model.DataRequest, error) {
+	var requests []*model.DataRequest
+	err := r.db.GetDB().WithContext(tenant.Global(ctx)).Raw(`
+		UPDATE user_data_requests SET status = ?, updated_at = ?
+		WHERE id = (
+			SELECT id FROM user_data_requests
+			WHERE status = ? AND due_at <= ?
+			ORDER BY due_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`,
+		model.DataRequestProcessing, now, model.DataRequestPending, now,
+	).Scan(&requests).Error
+	if err != nil || len(requests) == 0 {
+		return nil, err
+	}
+	return requests[0], nil
+}
+
+// CompleteDataRequest saves the outcome of a processed request
+func (r *UserRepo) CompleteDataRequest(ctx context.Context, request *model.DataRequest) error {
+	return r.db.GetDB().WithContext(tenant.Global(ctx)).Save(request).Error
+}
+
+// ListExpiredExports returns the completed exports whose archive expired at
+// now
+func (r *UserRepo) ListExpiredExports(ctx context.Context, now time.Time) ([]*model.DataRequest, error) {
+	var requests []*model.DataRequest
+	err := r.db.GetDB().WithContext(tenant.Global(ctx)).
+		Where("kind = ? AND status = ? AND expires_at <= ?", model.DataExport, model.DataRequestCompleted, now).
+		Find(&requests).Error
+	if err != nil {
+		return nil, err
+	}
+	return requests, nil
+}
+
+// GetPersonalData collects the rows of every module about userID, with its
+// soft-deleted addresses and doctor profiles. Only the
+// signed consultations of the user as a patient are included, the notes of
+// its doctors are shared once signed.
+func (r *UserRepo) GetPersonalData(ctx context.Context, userID string) (*PersonalData, error) {
+	db := r.db.GetDB().WithContext(tenant.Global(ctx))
+
+	data := PersonalData{User: &model.User{}}
+	if err := db.Where("id = ?", userID).First(data.User).Error; err != nil {
+		return nil, err
+	}
+
+	queries := []func() error{
+		func() error { return db.Where("user_id = ?", userID).Find(&data.Identities).Error },
+		func() error { return db.Where("user_id = ?", userID).Order("created_at").Find(&data.Sessions).Error },
+		func() error { return db.Unscoped().Where("id_user = ?", userID).Find(&data.Addresses).Error },
+		func() error {
+			return db.Unscoped().Where("id_user = ?", userID).Preload("Specialties").Find(&data.Doctors).Error
+		},
+		func() error { return db.Where("patient_id = ?", userID).Order("created_at").Find(&data.Reviews).Error },
+		func() error { return db.Where("patient_id = ?", userID).Find(&data.Allergies).Error },
+		func() error { return db.Where("patient_id = ?", userID).Find(&data.Conditions).Error },
+		func() error { return db.Where("patient_id = ?", userID).Find(&data.Medications).Error },
+		func() error { return db.Where("patient_id = ?", userID).Order("recorded_at").Find(&data.Vitals).Error },
+		func() error { return db.Where("patient_id = ?", userID).Find(&data.HealthGrants).Error },
+		func() error { return db.Where("owner_id = ?", userID).Order("created_at").Find(&data.Files).Error },
+		func() error {
+			return db.Where("user_id = ?", userID).Order("created_at").Find(&data.DataRequests).Error
+		},
+		func() error {
+			return db.Where("user_id = ?", userID).Order("created_at").Find(&data.Notifications).Error
+		},
+		func() error { return db.Where("user_id = ?", userID).Find(&data.NotificationPrefs).Error },
+		func() error {
+			return db.Where("actor_id = ? OR (target_type = ? AND target_id = ?)", userID, audit.TargetUser, userID).
+				Order("seq").Find(&data.AuditEvents).Error
+		},
+		func() error {
+			return db.Where("patient_id = ? AND status = ?", userID, consultationModel.ConsultationSigned).
+				Preload("Medications", func(db *gorm.DB) *gorm.DB { return db.Order("position") }).
+				Preload("Amendments", func(db *gorm.DB) *gorm.DB { return db.Order("created_at") }).
+				Order("visited_at").Find(&data.Consultations).Error
+		},
+		func() error {
+			return db.Model(&model.RecoveryCode{}).Where("user_id = ? AND used_at IS NULL", userID).Count(&data.RecoveryCodesLeft).Error
+		},
+		func() error {
+			var profile healthModel.Profile
+			err := db.Where("patient_id = ?", userID).First(&profile).Error
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			data.HealthProfile = &profile
+			return err
+		},
+	}
+	for _, query := range queries {
+		if err := query(); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(data.Doctors) > 0 {
+		ids := make([]string, len(data.Doctors))
+		for i, doctor := range data.Doctors {
+			ids[i] = doctor.ID
+		}
+		if err := db.Where("doctor_id IN ?", ids).Order("created_at").Find(&data.Verifications).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	return &data, nil
+}
+
+// EraseUser erases userID, anonymized as name, in a transaction:
+//   - its addresses, sessions, identities, recovery codes, health records,
+//     files and notifications are deleted, with the health grants given to its doctor
+//     profiles
+//   - its user row and doctor profiles are anonymized, so the consultations,
+//     prescriptions and reviews that must be kept only refer to a pseudonym,
+//     the text of its reviews is removed
+//   - the license documents of its verifications are dropped, the reviews
+//     of the licenses are kept
+//   - its export archives are expired and its pending requests cancelled
+//
+// The audit log is append-only and refers to the user by id only.
+func (r *UserRepo) EraseUser(ctx context.Context, userID, name string) (*Erasure, error) {
+	var erasure Erasure
+	now := time.Now()
+	err := r.db.GetDB().WithContext(tenant.Global(ctx)).Transaction(func(tx *gorm.DB) error {
+		var user model.User
+		if err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", userID).First(&user).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&model.Session{}).Where("user_id = ? AND revoked_at IS NULL", userID).Pluck("id", &erasure.Sessions).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("owner_id = ?", userID).Find(&erasure.Files).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&model.DataRequest{}).Where("user_id = ? AND key <> ''", userID).Pluck("key", &erasure.Exports).Error; err != nil {
+			return err
+		}
+
+		var doctorIDs []string
+		if err := tx.Unscoped().Model(&doctorModel.Doctor{}).Where("id_user = ?", userID).Pluck("id", &doctorIDs).Error; err != nil {
+			return err
+		}
+
+		deletes := []struct {
+			model any
+			query string
+			args  []any
+		}{
+			{&addressModel.Address{}, "id_user = ?", []any{userID}},
+			{&model.Session{}, "user_id = ?", []any{userID}},
+			{&model.UserIdentity{}, "user_id = ?", []any{userID}},
+			{&model.RecoveryCode{}, "user_id = ?", []any{userID}},
+			{&healthModel.Profile{}, "patient_id = ?", []any{userID}},
+			{&healthModel.Allergy{}, "patient_id = ?", []any{userID}},
+			{&healthModel.Condition{}, "patient_id = ?", []any{userID}},
+			{&healthModel.Medication{}, "patient_id = ?", []any{userID}},
+			{&healthModel.Vital{}, "patient_id = ?", []any{userID}},
+			{&healthModel.Change{}, "patient_id = ?", []any{userID}},
+			{&healthModel.Grant{}, "patient_id = ? OR doctor_id IN ?", []any{userID, append(doctorIDs, "")}},
+			{&specialtyModel.DoctorSpecialty{}, "doctor_id IN ?", []any{append(doctorIDs, "")}},
+			{&fileModel.File{}, "owner_id = ?", []any{userID}},
+			{&notificationModel.Notification{}, "user_id = ?", []any{userID}},
+			{&notificationModel.Preference{}, "user_id = ?", []any{userID}},
+		}
+		for _, d := range deletes {
+			if err := tx.Unscoped().Where(d.query, d.args...).Delete(d.model).Error; err != nil {
+				return err
+			}
+		}
+
+		err := tx.Model(&reviewModel.Review{}).Where("patient_id = ?", userID).
+			Updates(map[string]interface{}{"text": ""}).Error
+		if err != nil {
+			return err
+		}
+
+		if len(doctorIDs) > 0 {
+			err = tx.Unscoped().Model(&doctorModel.Doctor{}).Where("id IN ?", doctorIDs).Updates(map[string]interface{}{
+				"name":              name,
+				"image":             "",
+				"image_variants":    nil,
+				"license_number":    "",
+				"issuing_authority": "",
+				"status":            doctorModel.DoctorSuspended,
+				"deleted_at":        now,
+			}).Error
+			if err != nil {
+				return err
+			}
+			err = tx.Model(&doctorModel.Verification{}).Where("doctor_id IN ?", doctorIDs).
+				Updates(map[string]interface{}{"documents": nil}).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		err = tx.Model(&model.DataRequest{}).Where("user_id = ? AND key <> ''", userID).
+			Updates(map[string]interface{}{"key": "", "status": model.DataRequestExpired}).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Model(&model.DataRequest{}).Where("user_id = ? AND status = ?", userID, model.DataRequestPending).
+			Update("status", model.DataRequestCancelled).Error
+		if err != nil {
+			return err
+		}
+
+		// saved as a struct so the encrypted columns and the blind indexes
+		// are written through the serializer
+		anonymized := model.User{
+			ID:        user.ID,
+			CreatedAt: user.CreatedAt,
+			DeletedAt: gorm.DeletedAt{Time: now, Valid: true},
+			Role:      user.Role,
+			TenantID:  user.TenantID,
+			Name:      name,
+			Version:   user.Version + 1,
+		}
+		return tx.Unscoped().Save(&anonymized).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &erasure, nil
+}
@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	doctorModel "main/internal/doctor/model"
+	"main/internal/user/model"
+	"main/pkg/dbs"
+	"main/pkg/encryption"
+)
+
+// newMock is a repository on a mocked database expecting the statements in
+// order, the encrypted columns are written with a test keyring
+func newMock(t *testing.T) (*UserRepo, sqlmock.Sqlmock) {
+	t.Helper()
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+		_ = sqlDB.Close()
+	})
+
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyring, err := encryption.NewKeyring(map[string][]byte{"test": bytes.Repeat([]byte{1}, encryption.KeySize)}, "test", bytes.Repeat([]byte{2}, encryption.KeySize))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := encryption.Register(db, keyring); err != nil {
+		t.Fatal(err)
+	}
+	return NewUserRepository(dbs.Wrap(db)), mock
+}
+
+func TestEraseUser(t *testing.T) {
+	repo, mock := newMock(t)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE id = $1`)+`.* FOR UPDATE`).
+		WithArgs("user", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "role", "tenant_id", "name", "version"}).
+			AddRow("user", model.UserRoleDoctor, "", "Ada", 3))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id" FROM "user_sessions" WHERE user_id = $1 AND revoked_at IS NULL`)).
+		WithArgs("user").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("session"))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "files" WHERE owner_id = $1`)).
+		WithArgs("user").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "key"}).AddRow("file", "files/file"))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "key" FROM "user_data_requests" WHERE user_id = $1 AND key <> ''`)).
+		WithArgs("user").
+		WillReturnRows(sqlmock.NewRows([]string{"key"}).AddRow("exports/user/export"))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id" FROM "doctors" WHERE id_user = $1`)).
+		WithArgs("user").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("doctor"))
+
+	// the rows about the user only are deleted, hard deleted for the
+	// soft-deleted ones
+	deleted := []struct {
+		table string
+		where string
+		args  []interface{}
+	}{
+		{"addresses", "id_user = $1", []interface{}{"user"}},
+		{"user_sessions", "user_id = $1", []interface{}{"user"}},
+		{"user_identities", "user_id = $1", []interface{}{"user"}},
+		{"user_recovery_codes", "user_id = $1", []interface{}{"user"}},
+		{"health_profiles", "patient_id = $1", []interface{}{"user"}},
+		{"health_allergies", "patient_id = $1", []interface{}{"user"}},
+		{"health_conditions", "patient_id = $1", []interface{}{"user"}},
+		{"health_medications", "patient_id = $1", []interface{}{"user"}},
+		{"health_vitals", "patient_id = $1", []interface{}{"user"}},
+		{"health_changes", "patient_id = $1", []interface{}{"user"}},
+		{"health_grants", "patient_id = $1 OR doctor_id IN ($2,$3)", []interface{}{"user", "doctor", ""}},
+		{"doctor_specialties", "doctor_id IN ($1,$2)", []interface{}{"doctor", ""}},
+		{"files", "owner_id = $1", []interface{}{"user"}},
+		{"notifications", "user_id = $1", []interface{}{"user"}},
+		{"notification_preferences", "user_id = $1", []interface{}{"user"}},
+	}
+	for _, d := range deleted {
+		args := make([]driver.Value, len(d.args))
+		for i, arg := range d.args {
+			args[i] = arg
+		}
+		mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "`+d.table+`" WHERE `+d.where) + `$`).
+			WithArgs(args...).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+
+	// the rows that must be kept are anonymized
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "reviews" SET "text"=$1,"updated_at"=$2 WHERE patient_id = $3`)).
+		WithArgs("", sqlmock.AnyArg(), "user").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "doctors" SET "deleted_at"=$1,"image"=$2,"image_variants"=$3,"issuing_authority"=$4,"license_number"=$5,"name"=$6,"status"=$7,"updated_at"=$8 WHERE id IN ($9)`)).
+		WithArgs(sqlmock.AnyArg(), "", nil, "", "", "Deleted user", doctorModel.DoctorSuspended, sqlmock.AnyArg(), "doctor").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "doctor_verifications" SET "documents"=$1,"updated_at"=$2 WHERE doctor_id IN ($3)`)).
+		WithArgs(nil, sqlmock.AnyArg(), "doctor").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "user_data_requests" SET "key"=$1,"status"=$2,"updated_at"=$3 WHERE user_id = $4 AND key <> ''`)).
+		WithArgs("", model.DataRequestExpired, sqlmock.AnyArg(), "user").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "user_data_requests" SET "status"=$1,"updated_at"=$2 WHERE user_id = $3 AND status = $4`)).
+		WithArgs(model.DataRequestCancelled, sqlmock.AnyArg(), "user", model.DataRequestPending).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "users" SET`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	erasure, err := repo.EraseUser(context.Background(), "user", "Deleted user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(erasure.Sessions) != 1 || erasure.Sessions[0] != "session" {
+		t.Errorf("sessions = %v", erasure.Sessions)
+	}
+	if len(erasure.Files) != 1 || erasure.Files[0].Key != "files/file" {
+		t.Errorf("files = %+v", erasure.Files)
+	}
+	if len(erasure.Exports) != 1 || erasure.Exports[0] != "exports/user/export" {
+		t.Errorf("exports = %v", erasure.Exports)
+	}
+}
+
+func TestEraseUserNotFound(t *testing.T) {
+	repo, mock := newMock(t)
+
+	// nothing is erased
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE id = $1`)).
+		WithArgs("user", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectRollback()
+
+	if _, err := repo.EraseUser(context.Background(), "user", "Deleted user"); err != gorm.ErrRecordNotFound {
+		t.Errorf("EraseUser = %v, want ErrRecordNotFound", err)
+	}
+}
+
+func TestCancelErasure(t *testing.T) {
+	repo, mock := newMock(t)
+	cancel := regexp.QuoteMeta(`UPDATE "user_data_requests" SET "status"=$1,"updated_at"=$2 WHERE user_id = $3 AND kind = $4 AND status = $5`)
+
+	// only the pending erasure is cancelled, the second time there is none
+	for _, rows := range []int64{1, 0} {
+		mock.ExpectBegin()
+		mock.ExpectExec(cancel).
+			WithArgs(model.DataRequestCancelled, sqlmock.AnyArg(), "user", model.AccountErasure, model.DataRequestPending).
+			WillReturnResult(sqlmock.NewResult(0, rows))
+		mock.ExpectCommit()
+	}
+
+	if cancelled, err := repo.CancelErasure(context.Background(), "user"); err != nil || !cancelled {
+		t.Errorf("CancelErasure = %v, %v", cancelled, err)
+	}
+	if cancelled, err := repo.CancelErasure(context.Background(), "user"); err != nil || cancelled {
+		t.Errorf("CancelErasure without erasure = %v, %v", cancelled, err)
+	}
+}
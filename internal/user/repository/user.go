@@ -2,7 +2,11 @@ package repository
 
 import (
 	"context"
+	"crypto/subtle"
 	"errors"
+	"strconv"
+	"strings"
+	"time"
 
 	"gorm.io/gorm"
 
@@ -10,7 +14,10 @@ import (
 	"main/internal/user/model"
 	"main/pkg/config"
 	"main/pkg/dbs"
+	"main/pkg/encryption"
+	"main/pkg/imaging"
 	"main/pkg/paging"
+	"main/pkg/tenant"
 )
 
 //go:generate mockery --name=IUserRepository
@@ -27,8 +34,43 @@ type IUserRepository interface {
 	UpdatePhone(ctx context.Context, user *model.User) error
 	UpdateEmail(ctx context.Context, user *model.User) error
 	Delete(ctx context.Context, User *model.User) error
-	FindOrCreateByGoogleID(ctx context.Context, googleID, email, name string) (*model.User, error)
-	FindOrCreateByFacebookID(ctx context.Context, facebookID, email, name string) (*model.User, error)
+	GetIdentity(ctx context.Context, provider, subject string) (*model.UserIdentity, error)
+	ListIdentities(ctx context.Context, userID string) ([]*model.UserIdentity, error)
+	CreateIdentity(ctx context.Context, identity *model.UserIdentity) error
+	CreateUserWithIdentity(ctx context.Context, user *model.User, identity *model.UserIdentity) error
+	DeleteIdentity(ctx context.Context, userID, provider string) error
+	UpdateMFA(ctx context.Context, user *model.User) error
+	ConsumeMFAStep(ctx context.Context, userID string, step int64) (bool, error)
+	ResetMFA(ctx context.Context, userID string) error
+	ReplaceRecoveryCodes(ctx context.Context, userID string, codes []*model.RecoveryCode) error
+	ListUnusedRecoveryCodes(ctx context.Context, userID string) ([]*model.RecoveryCode, error)
+	UseRecoveryCode(ctx context.Context, id string) (bool, error)
+	CreateSession(ctx context.Context, session *model.Session) error
+	GetSession(ctx context.Context, id string) (*model.Session, error)
+	ListActiveSessions(ctx context.Context, userID string) ([]*model.Session, error)
+	RotateRefreshToken(ctx context.Context, id, tokenID, newTokenID string) (bool, error)
+	RevokeSession(ctx context.Context, userID, id string) (bool, error)
+	RevokeUserSessions(ctx context.Context, userID, exceptID string) ([]string, error)
+	IsSessionRevoked(ctx context.Context, id string) (bool, error)
+	CreateAPIKey(ctx context.Context, key *model.APIKey) error
+	ListAPIKeys(ctx context.Context) ([]*model.APIKey, error)
+	GetAPIKey(ctx context.Context, id string) (*model.APIKey, error)
+	GetAPIKeyByHash(ctx context.Context, hash string) (*model.APIKey, error)
+	TouchAPIKey(ctx context.Context, id string) error
+	RevokeAPIKey(ctx context.Context, id string) (bool, error)
+	IsAPIKeyRevoked(ctx context.Context, id string) (bool, error)
+	UpdateAvatar(ctx context.Context, userID string, variants imaging.Variants) error
+	CreateDataRequest(ctx context.Context, request *model.DataRequest) (bool, error)
+	GetDataRequest(ctx context.Context, userID, id string) (*model.DataRequest, error)
+	ListDataRequests(ctx context.Context, userID string) ([]*model.DataRequest, error)
+	CancelErasure(ctx context.Context, userID string) (bool, error)
+	ClaimDataRequest(ctx context.Context, now time.Time) (*model.DataRequest, error)
+	CompleteDataRequest(ctx context.Context, request *model.DataRequest) error
+	ListExpiredExports(ctx context.Context, now time.Time) ([]*model.DataRequest, error)
+	GetPersonalData(ctx context.Context, userID string) (*PersonalData, error)
+	EraseUser(ctx context.Context, userID, name string) (*Erasure, error)
+	Restore(ctx context.Context, id string) (bool, error)
+	ListPurgeableUsers(ctx context.Context, before time.Time) ([]string, error)
 }
 
 type UserRepo struct {
@@ -57,79 +99,78 @@ func (r *UserRepo) GetUserByID(ctx context.Context, id string) (*model.User, err
 	return &user, nil
 }
 
+// GetUserByEmail finds the user by the blind index of its encrypted email
 func (r *UserRepo) GetUserByEmail(ctx context.Context, email string) (*model.User, error) {
 	var user model.User
-	query := dbs.NewQuery("email = ?", email)
+	query := dbs.NewQuery("email_index = ?", encryption.BlindIndex("email", email))
 	if err := r.db.FindOne(ctx, &user, dbs.WithQuery(query)); err != nil {
 		return nil, err
 	}
 
 	return &user, nil
 }
-func (r *UserRepo) FindByEmailAndVerifyCode(ctx context.Context, email, verifyCode string) (*model.User, error) {
-	var user model.User
-	query := "SELECT id, email, verify_code_email, approve FROM users WHERE email = ? AND verify_code_email = ?"
 
-	result := r.db.GetDB().WithContext(ctx).Raw(query, email, verifyCode).Scan(&user)
-	if result.Error != nil {
-		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return nil, nil
-		}
-		return nil, result.Error
+// FindByEmailAndVerifyCode returns nil when no user has the email or the
+// code does not match, the codes are encrypted so they are compared here
+func (r *UserRepo) FindByEmailAndVerifyCode(ctx context.Context, email, verifyCode string) (*model.User, error) {
+	user, err := r.FindByEmail(ctx, email)
+	if err != nil || user == nil {
+		return nil, err
 	}
-	return &user, nil
+	if !codeMatches(user.VerifyCodeEmail, verifyCode) {
+		return nil, nil
+	}
+	return user, nil
 }
-func (r *UserRepo) FindByEmail(ctx context.Context, email string) (*model.User, error) {
-	var user model.User
-	query := "SELECT id, email, verify_code_email, approve FROM users WHERE email = ?"
 
-	result := r.db.GetDB().WithContext(ctx).Raw(query, email).Scan(&user)
-	if result.Error != nil {
-		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return nil, nil
-		}
-		return nil, result.Error
+// FindByEmail returns nil when no user has the email
+func (r *UserRepo) FindByEmail(ctx context.Context, email string) (*model.User, error) {
+	user, err := r.GetUserByEmail(ctx, email)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
 	}
-	return &user, nil
+	return user, err
 }
+
 func (r *UserRepo) UpdateEmail(ctx context.Context, user *model.User) error {
-	query := "UPDATE users SET approve = ? WHERE email = ?"
-	err := r.db.Exec(ctx, query, user.ApproveEmail, user.Email)
-	return err
+	return r.db.GetDB().WithContext(ctx).Model(&model.User{}).
+		Where("email_index = ?", encryption.BlindIndex("email", user.Email)).
+		Update("approve_email", user.ApproveEmail).Error
 }
-func (r *UserRepo) FindByPhoneAndVerifyCode(ctx context.Context, PhoneNumber, verifyCode string) (*model.User, error) {
-	var user model.User
-	query := "SELECT id, phone_number, verify_code_phone_number, approve FROM users WHERE phone_number = ? AND verify_code_phone_number = ?"
 
-	result := r.db.GetDB().WithContext(ctx).Raw(query, PhoneNumber, verifyCode).Scan(&user)
-	if result.Error != nil {
-		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return nil, nil
-		}
-		return nil, result.Error
+// FindByPhoneAndVerifyCode returns nil when no user has the phone number or
+// the code does not match
+func (r *UserRepo) FindByPhoneAndVerifyCode(ctx context.Context, PhoneNumber, verifyCode string) (*model.User, error) {
+	user, err := r.FindByPhone(ctx, PhoneNumber)
+	if err != nil || user == nil {
+		return nil, err
 	}
-	return &user, nil
+	if !codeMatches(user.VerifyCodePhoneNumber, verifyCode) {
+		return nil, nil
+	}
+	return user, nil
 }
 
+// FindByPhone finds the user by the blind index of its encrypted phone
+// number, nil when none has it
 func (r *UserRepo) FindByPhone(ctx context.Context, PhoneNumber string) (*model.User, error) {
 	var user model.User
-	query := "SELECT id, phone_number, verify_code_phone_number, approve FROM users WHERE phone_number = ? "
-
-	result := r.db.GetDB().WithContext(ctx).Raw(query, PhoneNumber).Scan(&user)
-	if result.Error != nil {
-		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return nil, nil
-		}
-		return nil, result.Error
+	query := dbs.NewQuery("phone_index = ?", encryption.BlindIndex("phone_number", PhoneNumber))
+	err := r.db.FindOne(ctx, &user, dbs.WithQuery(query))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
 	}
 
 	return &user, nil
 }
 
 func (r *UserRepo) UpdatePhone(ctx context.Context, user *model.User) error {
-	query := "UPDATE users SET approve = ? WHERE phone_number = ?"
-	err := r.db.Exec(ctx, query, user.ApprovePhoneNumber, user.PhoneNumber)
-	return err
+	return r.db.GetDB().WithContext(ctx).Model(&model.User{}).
+		Where("phone_index = ?", encryption.BlindIndex("phone_number", user.PhoneNumber)).
+		Update("approve_phone_number", user.ApprovePhoneNumber).Error
 }
 
 func (r *UserRepo) ListUsers(ctx context.Context, req dto.ListUsersReq) ([]*model.User, *paging.Pagination, error) {
@@ -152,8 +193,13 @@ func (r *UserRepo) ListUsers(ctx context.Context, req dto.ListUsersReq) ([]*mode
 	// 	}
 	// }
 
+	opts := []dbs.FindOption{dbs.WithQuery(query...)}
+	if req.IncludeDeleted {
+		opts = append(opts, dbs.WithDeleted())
+	}
+
 	var total int64
-	if err := r.db.Count(ctx, &model.User{}, &total, dbs.WithQuery(query...)); err != nil {
+	if err := r.db.Count(ctx, &model.User{}, &total, opts...); err != nil {
 		return nil, nil, err
 	}
 
@@ -163,52 +209,287 @@ func (r *UserRepo) ListUsers(ctx context.Context, req dto.ListUsersReq) ([]*mode
 	if err := r.db.Find(
 		ctx,
 		&Users,
-		dbs.WithQuery(query...),
-		dbs.WithLimit(int(pagination.Limit)),
-		dbs.WithOffset(int(pagination.Skip)),
-		// dbs.WithOrder(order),
+		append(opts,
+			dbs.WithLimit(int(pagination.Limit)),
+			dbs.WithOffset(int(pagination.Skip)),
+			// dbs.WithOrder(order),
+		)...,
 	); err != nil {
 		return nil, nil, err
 	}
 
 	return Users, pagination, nil
 }
+
+// Delete deletes the user if it is still at its version
 func (r *UserRepo) Delete(ctx context.Context, User *model.User) error {
-	return r.db.Delete(ctx, User)
+	return dbs.DeleteVersion(r.db.GetDB().WithContext(ctx), User, User.Version)
 }
 
-func (r *UserRepo) FindOrCreateByGoogleID(ctx context.Context, googleID, email, name string) (*model.User, error) {
-	var user model.User
-	err := r.db.GetDB().WithContext(ctx).Where("id = ?", googleID).First(&user).Error
-	if err == gorm.ErrRecordNotFound {
-		user = model.User{
-			ID:    googleID,
-			Email: email,
-			Name:  name,
+// GetIdentity returns nil when no user is linked to the provider account
+func (r *UserRepo) GetIdentity(ctx context.Context, provider, subject string) (*model.UserIdentity, error) {
+	var identity model.UserIdentity
+	err := r.db.GetDB().WithContext(ctx).
+		Where("provider = ? AND subject = ?", provider, subject).
+		First(&identity).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &identity, nil
+}
+
+func (r *UserRepo) ListIdentities(ctx context.Context, userID string) ([]*model.UserIdentity, error) {
+	var identities []*model.UserIdentity
+	err := r.db.GetDB().WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at").
+		Find(&identities).Error
+	if err != nil {
+		return nil, err
+	}
+	return identities, nil
+}
+
+func (r *UserRepo) CreateIdentity(ctx context.Context, identity *model.UserIdentity) error {
+	return r.db.GetDB().WithContext(ctx).Create(identity).Error
+}
+
+// CreateUserWithIdentity creates a user signing up with a provider
+func (r *UserRepo) CreateUserWithIdentity(ctx context.Context, user *model.User, identity *model.UserIdentity) error {
+	return r.db.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+		identity.UserID = user.ID
+		return tx.Create(identity).Error
+	})
+}
+
+func (r *UserRepo) DeleteIdentity(ctx context.Context, userID, provider string) error {
+	return r.db.GetDB().WithContext(ctx).
+		Where("user_id = ? AND provider = ?", userID, provider).
+		Delete(&model.UserIdentity{}).Error
+}
+
+// UpdateMFA saves the MFA columns only, so partially loaded users cannot
+// overwrite the rest of the row
+func (r *UserRepo) UpdateMFA(ctx context.Context, user *model.User) error {
+	return r.db.GetDB().WithContext(ctx).Model(user).
+		Select("mfa_enabled", "mfa_secret", "mfa_last_step", "mfa_confirmed_at").
+		Updates(user).Error
+}
+
+// ConsumeMFAStep records the time step of an accepted TOTP code and reports
+// false when the step, or a later one, was already used
+func (r *UserRepo) ConsumeMFAStep(ctx context.Context, userID string, step int64) (bool, error) {
+	result := r.db.GetDB().WithContext(ctx).Model(&model.User{}).
+		Where("id = ? AND mfa_last_step < ?", userID, step).
+		Update("mfa_last_step", step)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// ResetMFA disables MFA and drops the secret and the recovery codes
+func (r *UserRepo) ResetMFA(ctx context.Context, userID string) error {
+	return r.db.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&model.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+			"mfa_enabled":      false,
+			"mfa_secret":       "",
+			"mfa_last_step":    0,
+			"mfa_confirmed_at": nil,
+		}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&model.RecoveryCode{}).Error
+	})
+}
+
+func (r *UserRepo) ReplaceRecoveryCodes(ctx context.Context, userID string, codes []*model.RecoveryCode) error {
+	return r.db.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&model.RecoveryCode{}).Error; err != nil {
+			return err
 		}
-		if err := r.db.Create(ctx, &user); err != nil {
-			return nil, err
+		if len(codes) == 0 {
+			return nil
 		}
-	} else if err != nil {
+		return tx.Create(codes).Error
+	})
+}
+
+func (r *UserRepo) ListUnusedRecoveryCodes(ctx context.Context, userID string) ([]*model.RecoveryCode, error) {
+	var codes []*model.RecoveryCode
+	err := r.db.GetDB().WithContext(ctx).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Find(&codes).Error
+	if err != nil {
 		return nil, err
 	}
-	return &user, nil
+	return codes, nil
 }
 
-func (r *UserRepo) FindOrCreateByFacebookID(ctx context.Context, facebookID, email, name string) (*model.User, error) {
-	var user model.User
-	err := r.db.GetDB().WithContext(ctx).Where("id = ?", facebookID).First(&user).Error
-	if err == gorm.ErrRecordNotFound {
-		user = model.User{
-			ID:    facebookID,
-			Email: email,
-			Name:  name,
-		}
-		if err := r.db.Create(ctx, &user); err != nil {
-			return nil, err
+// UseRecoveryCode marks the code used and reports false when a concurrent
+// request used it first
+func (r *UserRepo) UseRecoveryCode(ctx context.Context, id string) (bool, error) {
+	result := r.db.GetDB().WithContext(ctx).Model(&model.RecoveryCode{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *UserRepo) CreateSession(ctx context.Context, session *model.Session) error {
+	return r.db.GetDB().WithContext(ctx).Create(session).Error
+}
+
+func (r *UserRepo) GetSession(ctx context.Context, id string) (*model.Session, error) {
+	var session model.Session
+	if err := r.db.GetDB().WithContext(ctx).Where("id = ?", id).First(&session).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *UserRepo) ListActiveSessions(ctx context.Context, userID string) ([]*model.Session, error) {
+	var sessions []*model.Session
+	err := r.db.GetDB().WithContext(ctx).
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("last_seen_at DESC").
+		Find(&sessions).Error
+	if err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// RotateRefreshToken replaces the current refresh token of an active session
+// and reports false when tokenID is no longer the current one
+func (r *UserRepo) RotateRefreshToken(ctx context.Context, id, tokenID, newTokenID string) (bool, error) {
+	result := r.db.GetDB().WithContext(ctx).Model(&model.Session{}).
+		Where("id = ? AND refresh_token_id = ? AND revoked_at IS NULL", id, tokenID).
+		Updates(map[string]interface{}{
+			"refresh_token_id": newTokenID,
+			"last_seen_at":     time.Now(),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// RevokeSession reports false when userID has no active session id
+func (r *UserRepo) RevokeSession(ctx context.Context, userID, id string) (bool, error) {
+	result := r.db.GetDB().WithContext(ctx).Model(&model.Session{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// RevokeUserSessions revokes the sessions of userID but exceptID and returns
+// the revoked ids
+func (r *UserRepo) RevokeUserSessions(ctx context.Context, userID, exceptID string) ([]string, error) {
+	var ids []string
+	err := r.db.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&model.Session{}).
+			Where("user_id = ? AND id <> ? AND revoked_at IS NULL", userID, exceptID).
+			Pluck("id", &ids).Error
+		if err != nil || len(ids) == 0 {
+			return err
 		}
-	} else if err != nil {
+		return tx.Model(&model.Session{}).
+			Where("id IN ?", ids).
+			Update("revoked_at", time.Now()).Error
+	})
+	if err != nil {
 		return nil, err
 	}
-	return &user, nil
+	return ids, nil
+}
+
+// IsSessionRevoked reports true when the session id was revoked or deleted
+func (r *UserRepo) IsSessionRevoked(ctx context.Context, id string) (bool, error) {
+	var count int64
+	err := r.db.GetDB().WithContext(tenant.Global(ctx)).Model(&model.Session{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Count(&count).Error
+	return count == 0, err
+}
+
+func (r *UserRepo) CreateAPIKey(ctx context.Context, key *model.APIKey) error {
+	return r.db.GetDB().WithContext(ctx).Create(key).Error
+}
+
+func (r *UserRepo) ListAPIKeys(ctx context.Context) ([]*model.APIKey, error) {
+	var keys []*model.APIKey
+	if err := r.db.GetDB().WithContext(ctx).Order("created_at DESC").Find(&keys).Error; err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+func (r *UserRepo) GetAPIKey(ctx context.Context, id string) (*model.APIKey, error) {
+	var key model.APIKey
+	if err := r.db.GetDB().WithContext(ctx).Where("id = ?", id).First(&key).Error; err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (r *UserRepo) GetAPIKeyByHash(ctx context.Context, hash string) (*model.APIKey, error) {
+	var key model.APIKey
+	if err := r.db.GetDB().WithContext(ctx).Where("hash = ?", hash).First(&key).Error; err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (r *UserRepo) TouchAPIKey(ctx context.Context, id string) error {
+	return r.db.GetDB().WithContext(ctx).Model(&model.APIKey{}).
+		Where("id = ?", id).
+		Update("last_used_at", time.Now()).Error
+}
+
+// RevokeAPIKey reports false when the key does not exist or was revoked
+func (r *UserRepo) RevokeAPIKey(ctx context.Context, id string) (bool, error) {
+	result := r.db.GetDB().WithContext(ctx).Model(&model.APIKey{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// IsAPIKeyRevoked reports true when the key id was revoked or deleted
+func (r *UserRepo) IsAPIKeyRevoked(ctx context.Context, id string) (bool, error) {
+	var count int64
+	err := r.db.GetDB().WithContext(tenant.Global(ctx)).Model(&model.APIKey{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Count(&count).Error
+	return count == 0, err
+}
+
+// UpdateAvatar sets the picture variants, Avatar being the large jpeg one
+func (r *UserRepo) UpdateAvatar(ctx context.Context, userID string, variants imaging.Variants) error {
+	return r.db.GetDB().WithContext(ctx).Model(&model.User{}).
+		Where("id = ?", userID).
+		Updates(map[string]interface{}{
+			"avatar":          variants[model.AvatarVariant],
+			"avatar_variants": variants,
+		}).Error
+}
+
+// codeMatches compares the verification codes in constant time
+func codeMatches(code int, verifyCode string) bool {
+	return code != 0 && subtle.ConstantTimeCompare([]byte(strconv.Itoa(code)), []byte(strings.TrimSpace(verifyCode))) == 1
 }
@@ -0,0 +1,259 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/quangdangfit/gocommon/logger"
+	"github.com/quangdangfit/gocommon/validation"
+	"gorm.io/gorm"
+
+	"main/internal/user/dto"
+	"main/internal/user/model"
+	"main/internal/user/repository"
+	"main/pkg/apikey"
+	"main/pkg/audit"
+	"main/pkg/jtoken"
+	"main/pkg/rbac"
+	"main/pkg/redis"
+	"main/pkg/session"
+)
+
+const (
+	apiKeyCacheKeyPrefix = "apikey:"
+	// apiKeyCacheTTL bounds how often a key in use hits the database and
+	// updates its last use
+	apiKeyCacheTTL = 1 * time.Minute
+)
+
+var (
+	ErrAPIKeyNotFound       = errors.New("api key not found")
+	ErrInvalidClient        = errors.New("invalid_client")
+	ErrInvalidScope         = errors.New("invalid_scope")
+	ErrUnsupportedGrantType = errors.New("unsupported_grant_type")
+)
+
+//go:generate mockery --name=IAPIKeyService
+type IAPIKeyService interface {
+	CreateAPIKey(ctx context.Context, createdBy string, req *dto.CreateAPIKeyReq) (*model.APIKey, string, error)
+	ListAPIKeys(ctx context.Context) ([]*model.APIKey, error)
+	RevokeAPIKey(ctx context.Context, id string) error
+	VerifyAPIKey(ctx context.Context, key string) (*apikey.Principal, error)
+	ClientToken(ctx context.Context, req *dto.ClientTokenReq) (*dto.ClientTokenRes, error)
+}
+
+type APIKeyService struct {
+	validator validation.Validation
+	repo      repository.IUserRepository
+	cache     redis.IRedis
+	sessions  *session.Store
+	recorder  audit.Recorder
+}
+
+func NewAPIKeyService(
+	validator validation.Validation,
+	repo repository.IUserRepository,
+	cache redis.IRedis,
+	sessions *session.Store,
+	recorder audit.Recorder) *APIKeyService {
+
+	return &APIKeyService{
+		validator: validator,
+		repo:      repo,
+		cache:     cache,
+		sessions:  sessions,
+		recorder:  recorder,
+	}
+}
+
+// CreateAPIKey returns the key with its clear value, the only time it is
+// available
+func (s *APIKeyService) CreateAPIKey(ctx context.Context, createdBy string, req *dto.CreateAPIKeyReq) (*model.APIKey, string, error) {
+	if err := s.validator.ValidateStruct(req); err != nil {
+		return nil, "", err
+	}
+	if err := rbac.ValidateScopes(req.Scopes); err != nil {
+		return nil, "", err
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, "", errors.New("expires_at must be in the future")
+	}
+
+	key, prefix, err := apikey.Generate()
+	if err != nil {
+		return nil, "", err
+	}
+
+	apiKey := &model.APIKey{
+		ID:        uuid.New().String(),
+		Name:      req.Name,
+		Prefix:    prefix,
+		Hash:      apikey.Hash(key),
+		Scopes:    req.Scopes,
+		CreatedBy: createdBy,
+		ExpiresAt: req.ExpiresAt,
+	}
+	if err := s.repo.CreateAPIKey(ctx, apiKey); err != nil {
+		logger.Errorf("CreateAPIKey fail, name: %s, error: %s", req.Name, err)
+		return nil, "", err
+	}
+	s.recorder.Record(ctx, &audit.Event{Action: audit.APIKeyCreate, TargetType: audit.TargetAPIKey, TargetID: apiKey.ID, After: apiKey})
+
+	return apiKey, key, nil
+}
+
+func (s *APIKeyService) ListAPIKeys(ctx context.Context) ([]*model.APIKey, error) {
+	return s.repo.ListAPIKeys(ctx)
+}
+
+// RevokeAPIKey rejects the key and the client tokens obtained with it
+// immediately
+func (s *APIKeyService) RevokeAPIKey(ctx context.Context, id string) error {
+	apiKey, err := s.repo.GetAPIKey(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrAPIKeyNotFound
+	}
+	if err != nil {
+		logger.Errorf("RevokeAPIKey.GetAPIKey fail, id: %s, error: %s", id, err)
+		return err
+	}
+
+	revoked, err := s.repo.RevokeAPIKey(ctx, id)
+	if err != nil {
+		logger.Errorf("RevokeAPIKey fail, id: %s, error: %s", id, err)
+		return err
+	}
+	if !revoked {
+		return ErrAPIKeyNotFound
+	}
+	s.recorder.Record(ctx, &audit.Event{Action: audit.APIKeyRevoke, TargetType: audit.TargetAPIKey, TargetID: id, Before: apiKey})
+
+	_ = s.cache.Remove(ctx, apiKeyCacheKeyPrefix+apiKey.Hash)
+	s.sessions.Revoke(ctx, clientSessionID(id))
+	return nil
+}
+
+// VerifyAPIKey returns the machine client owning key
+func (s *APIKeyService) VerifyAPIKey(ctx context.Context, key string) (*apikey.Principal, error) {
+	if _, err := apikey.ParsePrefix(key); err != nil {
+		return nil, err
+	}
+	hash := apikey.Hash(key)
+
+	var principal apikey.Principal
+	if err := s.cache.Get(ctx, apiKeyCacheKeyPrefix+hash, &principal); err == nil {
+		if principal.ExpiresAt.IsZero() || time.Now().Before(principal.ExpiresAt) {
+			return &principal, nil
+		}
+		return nil, apikey.ErrInvalidKey
+	}
+
+	apiKey, err := s.repo.GetAPIKeyByHash(ctx, hash)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apikey.ErrInvalidKey
+	}
+	if err != nil {
+		logger.Errorf("VerifyAPIKey.GetAPIKeyByHash fail, error: %s", err)
+		return nil, err
+	}
+	if !apiKey.Active() {
+		return nil, apikey.ErrInvalidKey
+	}
+
+	if err := s.repo.TouchAPIKey(ctx, apiKey.ID); err != nil {
+		logger.Errorf("VerifyAPIKey.TouchAPIKey fail, id: %s, error: %s", apiKey.ID, err)
+	}
+
+	principal = apikey.Principal{
+		KeyID:  apiKey.ID,
+		Name:   apiKey.Name,
+		Scopes: apiKey.Scopes,
+	}
+	if apiKey.ExpiresAt != nil {
+		principal.ExpiresAt = *apiKey.ExpiresAt
+	}
+	_ = s.cache.SetWithExpiration(ctx, apiKeyCacheKeyPrefix+hash, principal, apiKeyCacheTTL)
+
+	return &principal, nil
+}
+
+// ClientToken implements the client credentials grant, the client id is the
+// key id and the secret the key itself
+func (s *APIKeyService) ClientToken(ctx context.Context, req *dto.ClientTokenReq) (*dto.ClientTokenRes, error) {
+	res, err := s.clientToken(ctx, req)
+	event := audit.Event{Action: audit.ClientToken, Outcome: audit.Success, TargetType: audit.TargetAPIKey, TargetID: req.ClientID}
+	if err != nil {
+		event.Outcome, event.Reason = audit.Failure, failureReason(err)
+	}
+	s.recorder.Record(ctx, &event)
+	return res, err
+}
+
+func (s *APIKeyService) clientToken(ctx context.Context, req *dto.ClientTokenReq) (*dto.ClientTokenRes, error) {
+	if err := s.validator.ValidateStruct(req); err != nil {
+		return nil, err
+	}
+	if req.GrantType != "client_credentials" {
+		return nil, ErrUnsupportedGrantType
+	}
+
+	principal, err := s.VerifyAPIKey(ctx, req.ClientSecret)
+	if err != nil || principal.KeyID != req.ClientID {
+		return nil, ErrInvalidClient
+	}
+
+	scopes := principal.Scopes
+	if req.Scope != "" {
+		scopes, err = rbac.ParseScopes(req.Scope)
+		if err != nil {
+			return nil, ErrInvalidScope
+		}
+		for _, scope := range scopes {
+			if !rbac.Has(principal.Scopes, scope) {
+				return nil, ErrInvalidScope
+			}
+		}
+	}
+
+	accessToken := jtoken.GenerateClientToken(map[string]interface{}{
+		"id":     principal.KeyID,
+		"role":   rbac.ServiceRole,
+		"sid":    clientSessionID(principal.KeyID),
+		"scopes": scopes,
+	})
+
+	return &dto.ClientTokenRes{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   jtoken.ClientTokenExpiredTime,
+		Scope:       strings.Join(scopes, " "),
+	}, nil
+}
+
+// clientSessionID is the session of the client tokens of a key, revoked with
+// the key
+func clientSessionID(keyID string) string {
+	return clientSessionPrefix + keyID
+}
+
+const clientSessionPrefix = "apikey:"
+
+type sessionRevocations struct {
+	repo repository.IUserRepository
+}
+
+// NewSessionRevocations reads the revocations of the user sessions and of the
+// client sessions, those of the API keys
+func NewSessionRevocations(repo repository.IUserRepository) session.Revocations {
+	return sessionRevocations{repo: repo}
+}
+
+func (r sessionRevocations) Revoked(ctx context.Context, id string) (bool, error) {
+	if keyID, ok := strings.CutPrefix(id, clientSessionPrefix); ok {
+		return r.repo.IsAPIKeyRevoked(ctx, keyID)
+	}
+	return r.repo.IsSessionRevoked(ctx, id)
+}
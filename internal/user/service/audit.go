@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"main/internal/user/model"
+	"main/pkg/audit"
+	"main/pkg/ratelimit"
+)
+
+// recordLogin records a login attempt of action on user, nil when the
+// account is unknown, and returns the result of the login. The user is only
+// returned with its tokens or an MFA challenge.
+func (s *UserService) recordLogin(ctx context.Context, action string, user *model.User, accessToken, refreshToken string, err error) (*model.User, string, string, error) {
+	event := audit.Event{Action: action, Outcome: audit.Success, TargetType: audit.TargetUser}
+	if user != nil {
+		event.TargetID = user.ID
+	}
+
+	var challenge *MFARequiredError
+	switch {
+	case err == nil:
+	case errors.As(err, &challenge):
+		event.Reason = "mfa_required"
+	default:
+		event.Outcome, event.Reason = audit.Failure, failureReason(err)
+	}
+	s.recorder.Record(ctx, &event)
+
+	if err != nil && challenge == nil {
+		return nil, "", "", err
+	}
+	return user, accessToken, refreshToken, err
+}
+
+// failureReason is the reason recorded for a failed login or refresh
+func failureReason(err error) string {
+	var locked *ratelimit.LockedError
+	switch {
+	case errors.As(err, &locked):
+		return "locked"
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return "unknown_user"
+	case errors.Is(err, ErrWrongRole):
+		return "wrong_role"
+	case errors.Is(err, ErrWrongPassword):
+		return "wrong_password"
+	case errors.Is(err, ErrInvalidMFACode):
+		return "invalid_mfa_code"
+	case errors.Is(err, ErrInvalidMFAToken):
+		return "invalid_mfa_token"
+	case errors.Is(err, ErrMFANotEnrolled):
+		return "mfa_not_enrolled"
+	case errors.Is(err, ErrSessionRevoked):
+		return "session_revoked"
+	case errors.Is(err, ErrInvalidRefreshToken):
+		return "invalid_refresh_token"
+	case errors.Is(err, ErrInvalidClient):
+		return "invalid_client"
+	case errors.Is(err, ErrInvalidScope):
+		return "invalid_scope"
+	default:
+		return err.Error()
+	}
+}
@@ -0,0 +1,339 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/quangdangfit/gocommon/logger"
+	"golang.org/x/crypto/bcrypt"
+
+	"main/internal/user/dto"
+	"main/internal/user/model"
+	"main/pkg/audit"
+	"main/pkg/config"
+	"main/pkg/events"
+	"main/pkg/jtoken"
+	"main/pkg/totp"
+)
+
+const recoveryCodeCount = 10
+
+var (
+	ErrMFAAlreadyEnabled = errors.New("mfa already enabled")
+	ErrMFANotEnrolled    = errors.New("mfa not enrolled")
+	ErrMFAEnforced       = errors.New("mfa is required for this role")
+	ErrInvalidMFACode    = errors.New("invalid mfa code")
+	ErrInvalidMFAToken   = errors.New("invalid mfa token")
+)
+
+// MFARequiredError is returned instead of tokens when the password was right
+// but a second factor is needed
+type MFARequiredError struct {
+	// Token is exchanged for access and refresh tokens by VerifyMFA
+	Token string
+	// EnrollmentRequired is set when the role enforces MFA and the user did
+	// not enroll yet, Token then also allows EnrollMFA and ConfirmMFA
+	EnrollmentRequired bool
+}
+
+func (e *MFARequiredError) Error() string {
+	return "second factor required"
+}
+
+// mfaChallenge returns a *MFARequiredError when user must pass a second
+// factor before tokens are issued
+func (s *UserService) mfaChallenge(user *model.User) error {
+	if !user.MFAEnabled && !mfaEnforced(user.Role) {
+		return nil
+	}
+
+	return &MFARequiredError{
+		Token:              jtoken.GenerateMFAToken(map[string]interface{}{"id": user.ID, "role": user.Role}),
+		EnrollmentRequired: !user.MFAEnabled,
+	}
+}
+
+func mfaEnforced(role model.UserRole) bool {
+	for _, r := range config.GetConfig().MFAEnforcedRoles {
+		if model.UserRole(r) == role {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *UserService) EnrollMFA(ctx context.Context, userID string) (*dto.EnrollMFARes, error) {
+	user, err := s.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		logger.Errorf("EnrollMFA.GetUserByID fail, id: %s, error: %s", userID, err)
+		return nil, err
+	}
+
+	if user.MFAEnabled {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	// the secret stays pending until ConfirmMFA proves the app has it
+	user.MFASecret = secret
+	if err := s.repo.UpdateMFA(ctx, user); err != nil {
+		logger.Errorf("EnrollMFA.UpdateMFA fail, id: %s, error: %s", userID, err)
+		return nil, err
+	}
+
+	uri := totp.ProvisioningURI(config.GetConfig().MFAIssuer, user.Email, secret)
+	qrCode, err := totp.QRCode(uri)
+	if err != nil {
+		logger.Errorf("EnrollMFA.QRCode fail, id: %s, error: %s", userID, err)
+	}
+
+	return &dto.EnrollMFARes{
+		Secret:     secret,
+		OTPAuthURI: uri,
+		QRCode:     qrCode,
+	}, nil
+}
+
+func (s *UserService) ConfirmMFA(ctx context.Context, userID string, req *dto.MFACodeReq) ([]string, error) {
+	if err := s.validator.ValidateStruct(req); err != nil {
+		return nil, err
+	}
+
+	user, err := s.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		logger.Errorf("ConfirmMFA.GetUserByID fail, id: %s, error: %s", userID, err)
+		return nil, err
+	}
+
+	if user.MFAEnabled {
+		return nil, ErrMFAAlreadyEnabled
+	}
+	if user.MFASecret == "" {
+		return nil, ErrMFANotEnrolled
+	}
+
+	lockKey := "mfa:" + user.ID
+	if err := s.lockout.Check(ctx, lockKey); err != nil {
+		return nil, err
+	}
+
+	step, err := totp.Validate(user.MFASecret, req.Code, time.Now(), user.MFALastStep)
+	if err != nil {
+		if lockErr := s.lockout.Fail(ctx, lockKey); lockErr != nil {
+			return nil, lockErr
+		}
+		return nil, ErrInvalidMFACode
+	}
+	s.lockout.Reset(ctx, lockKey)
+
+	now := time.Now()
+	user.MFAEnabled = true
+	user.MFALastStep = step
+	user.MFAConfirmedAt = &now
+	if err := s.repo.UpdateMFA(ctx, user); err != nil {
+		logger.Errorf("ConfirmMFA.UpdateMFA fail, id: %s, error: %s", userID, err)
+		return nil, err
+	}
+	s.publish(ctx, events.UserUpdated, user, events.ActionUpdated)
+
+	return s.newRecoveryCodes(ctx, user.ID)
+}
+
+// VerifyMFA exchanges the challenge token of Login and a TOTP or recovery
+// code for access and refresh tokens
+func (s *UserService) VerifyMFA(ctx context.Context, req *dto.VerifyMFAReq) (*model.User, string, string, error) {
+	user, accessToken, refreshToken, err := s.verifyMFA(ctx, req)
+	return s.recordLogin(ctx, audit.LoginMFA, user, accessToken, refreshToken, err)
+}
+
+func (s *UserService) verifyMFA(ctx context.Context, req *dto.VerifyMFAReq) (*model.User, string, string, error) {
+	if err := s.validator.ValidateStruct(req); err != nil {
+		return nil, "", "", err
+	}
+
+	payload, err := jtoken.ValidateToken(req.MFAToken)
+	if err != nil || payload["type"] != jtoken.MFATokenType {
+		return nil, "", "", ErrInvalidMFAToken
+	}
+	userID, _ := payload["id"].(string)
+
+	lockKey := "mfa:" + userID
+	if err := s.lockout.Check(ctx, lockKey); err != nil {
+		return nil, "", "", err
+	}
+
+	user, err := s.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		logger.Errorf("VerifyMFA.GetUserByID fail, id: %s, error: %s", userID, err)
+		return nil, "", "", err
+	}
+
+	if !user.MFAEnabled {
+		return user, "", "", ErrMFANotEnrolled
+	}
+
+	if err := s.checkSecondFactor(ctx, user, req.Code, lockKey); err != nil {
+		return user, "", "", err
+	}
+
+	accessToken, refreshToken, err := s.startSession(ctx, user)
+	if err != nil {
+		return user, "", "", err
+	}
+	return user, accessToken, refreshToken, nil
+}
+
+func (s *UserService) RegenerateRecoveryCodes(ctx context.Context, userID string, req *dto.MFACodeReq) ([]string, error) {
+	if err := s.validator.ValidateStruct(req); err != nil {
+		return nil, err
+	}
+
+	user, err := s.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		logger.Errorf("RegenerateRecoveryCodes.GetUserByID fail, id: %s, error: %s", userID, err)
+		return nil, err
+	}
+
+	if !user.MFAEnabled {
+		return nil, ErrMFANotEnrolled
+	}
+
+	// a recovery code must not be able to mint new ones
+	if !totp.IsCode(req.Code) {
+		return nil, ErrInvalidMFACode
+	}
+	if err := s.checkSecondFactor(ctx, user, req.Code, "mfa:"+user.ID); err != nil {
+		return nil, err
+	}
+
+	return s.newRecoveryCodes(ctx, user.ID)
+}
+
+func (s *UserService) DisableMFA(ctx context.Context, userID string, req *dto.DisableMFAReq) error {
+	if err := s.validator.ValidateStruct(req); err != nil {
+		return err
+	}
+
+	user, err := s.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		logger.Errorf("DisableMFA.GetUserByID fail, id: %s, error: %s", userID, err)
+		return err
+	}
+
+	if !user.MFAEnabled {
+		return ErrMFANotEnrolled
+	}
+	if mfaEnforced(user.Role) {
+		return ErrMFAEnforced
+	}
+
+	if err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
+		return ErrWrongPassword
+	}
+
+	if err := s.checkSecondFactor(ctx, user, req.Code, "mfa:"+user.ID); err != nil {
+		return err
+	}
+
+	if err := s.repo.ResetMFA(ctx, user.ID); err != nil {
+		logger.Errorf("DisableMFA.ResetMFA fail, id: %s, error: %s", userID, err)
+		return err
+	}
+	s.publish(ctx, events.UserUpdated, user, events.ActionUpdated)
+
+	return nil
+}
+
+// ResetMFA lets an admin clear the MFA of a user who lost the authenticator
+// and the recovery codes, the user enrolls again on next login
+func (s *UserService) ResetMFA(ctx context.Context, userID string) error {
+	user, err := s.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		logger.Errorf("ResetMFA.GetUserByID fail, id: %s, error: %s", userID, err)
+		return err
+	}
+
+	if err := s.repo.ResetMFA(ctx, userID); err != nil {
+		logger.Errorf("ResetMFA fail, id: %s, error: %s", userID, err)
+		return err
+	}
+	s.lockout.Reset(ctx, "mfa:"+userID)
+	s.recorder.Record(ctx, &audit.Event{Action: audit.MFAReset, TargetType: audit.TargetUser, TargetID: userID})
+	s.publish(ctx, events.UserUpdated, user, events.ActionUpdated)
+
+	return nil
+}
+
+// checkSecondFactor accepts a TOTP code or an unused recovery code, and
+// counts failures against lockKey
+func (s *UserService) checkSecondFactor(ctx context.Context, user *model.User, code, lockKey string) error {
+	if err := s.lockout.Check(ctx, lockKey); err != nil {
+		return err
+	}
+
+	ok, err := s.matchSecondFactor(ctx, user, code)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		if lockErr := s.lockout.Fail(ctx, lockKey); lockErr != nil {
+			return lockErr
+		}
+		return ErrInvalidMFACode
+	}
+	s.lockout.Reset(ctx, lockKey)
+
+	return nil
+}
+
+func (s *UserService) matchSecondFactor(ctx context.Context, user *model.User, code string) (bool, error) {
+	if totp.IsCode(code) {
+		step, err := totp.Validate(user.MFASecret, code, time.Now(), user.MFALastStep)
+		if err != nil {
+			return false, nil
+		}
+		// a code is accepted once even when two requests race with it
+		return s.repo.ConsumeMFAStep(ctx, user.ID, step)
+	}
+
+	codes, err := s.repo.ListUnusedRecoveryCodes(ctx, user.ID)
+	if err != nil {
+		logger.Errorf("ListUnusedRecoveryCodes fail, id: %s, error: %s", user.ID, err)
+		return false, err
+	}
+
+	code = totp.NormalizeRecoveryCode(code)
+	for _, recoveryCode := range codes {
+		if bcrypt.CompareHashAndPassword([]byte(recoveryCode.CodeHash), []byte(code)) == nil {
+			return s.repo.UseRecoveryCode(ctx, recoveryCode.ID)
+		}
+	}
+
+	return false, nil
+}
+
+// newRecoveryCodes replaces the recovery codes of userID and returns them in
+// clear, the only time they are available
+func (s *UserService) newRecoveryCodes(ctx context.Context, userID string) ([]string, error) {
+	codes, err := totp.GenerateRecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		return nil, err
+	}
+
+	recoveryCodes := make([]*model.RecoveryCode, 0, len(codes))
+	for _, code := range codes {
+		recoveryCodes = append(recoveryCodes, model.NewRecoveryCode(userID, code))
+	}
+
+	if err := s.repo.ReplaceRecoveryCodes(ctx, userID, recoveryCodes); err != nil {
+		logger.Errorf("ReplaceRecoveryCodes fail, id: %s, error: %s", userID, err)
+		return nil, err
+	}
+
+	return codes, nil
+}
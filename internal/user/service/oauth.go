@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/quangdangfit/gocommon/logger"
+	"gorm.io/gorm"
+
+	"main/internal/user/model"
+	"main/pkg/audit"
+	"main/pkg/events"
+	"main/pkg/oauth"
+)
+
+var (
+	ErrAccountExists   = errors.New("an account already uses this email, sign in and link the provider from your account")
+	ErrIdentityLinked  = errors.New("this provider account is linked to another user")
+	ErrLastLoginMethod = errors.New("cannot unlink the only way to sign in, set a password first")
+	ErrEmailRequired   = errors.New("the provider did not share an email address")
+)
+
+// OAuthURL starts a login with provider, or links the provider account to
+// linkUserID when it is set
+func (s *UserService) OAuthURL(ctx context.Context, provider, linkUserID string) (string, error) {
+	return s.oauth.Start(ctx, provider, linkUserID)
+}
+
+// OAuthLogin completes the login started by OAuthURL. The user is the one
+// linked to the provider account, else the signed-in user who started a
+// link, else the owner of the email when the provider verified it, else a
+// new patient.
+func (s *UserService) OAuthLogin(ctx context.Context, provider, state, code string) (*model.User, string, string, error) {
+	user, accessToken, refreshToken, err := s.oauthLogin(ctx, provider, state, code)
+	return s.recordLogin(ctx, audit.LoginOAuth, user, accessToken, refreshToken, err)
+}
+
+func (s *UserService) oauthLogin(ctx context.Context, provider, state, code string) (*model.User, string, string, error) {
+	identity, oauthState, err := s.oauth.Finish(ctx, provider, state, code)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	user, err := s.resolveIdentity(ctx, identity, oauthState.LinkUserID)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	if err := s.mfaChallenge(user); err != nil {
+		return user, "", "", err
+	}
+
+	accessToken, refreshToken, err := s.startSession(ctx, user)
+	if err != nil {
+		return user, "", "", err
+	}
+	return user, accessToken, refreshToken, nil
+}
+
+func (s *UserService) resolveIdentity(ctx context.Context, identity *oauth.Identity, linkUserID string) (*model.User, error) {
+	linked, err := s.repo.GetIdentity(ctx, identity.Provider, identity.Subject)
+	if err != nil {
+		logger.Errorf("OAuthLogin.GetIdentity fail, provider: %s, error: %s", identity.Provider, err)
+		return nil, err
+	}
+
+	if linked != nil {
+		if linkUserID != "" && linked.UserID != linkUserID {
+			return nil, ErrIdentityLinked
+		}
+		return s.repo.GetUserByID(ctx, linked.UserID)
+	}
+
+	if linkUserID != "" {
+		return s.linkIdentity(ctx, linkUserID, identity)
+	}
+
+	// users created before linked identities used the Google subject as id
+	if identity.Provider == "google" {
+		if user, err := s.repo.GetUserByID(ctx, identity.Subject); err == nil {
+			return s.linkIdentity(ctx, user.ID, identity)
+		}
+	}
+
+	if identity.Email == "" {
+		return nil, ErrEmailRequired
+	}
+
+	user, err := s.repo.GetUserByEmail(ctx, identity.Email)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		logger.Errorf("OAuthLogin.GetUserByEmail fail, email: %s, error: %s", identity.Email, err)
+		return nil, err
+	}
+	if user != nil {
+		// linking on an unverified email would hand the account to whoever
+		// registered that address at the provider
+		if !identity.EmailVerified {
+			return nil, ErrAccountExists
+		}
+		return s.linkIdentity(ctx, user.ID, identity)
+	}
+
+	user = &model.User{
+		ID:           uuid.New().String(),
+		Role:         model.UserRoleClient,
+		Email:        identity.Email,
+		Name:         identity.Name,
+		ApproveEmail: identity.EmailVerified,
+	}
+	if err := s.repo.CreateUserWithIdentity(ctx, user, newUserIdentity(user.ID, identity)); err != nil {
+		logger.Errorf("OAuthLogin.CreateUserWithIdentity fail, provider: %s, error: %s", identity.Provider, err)
+		return nil, err
+	}
+	s.publish(ctx, events.UserRegistered, user, events.ActionCreated)
+
+	return user, nil
+}
+
+func (s *UserService) linkIdentity(ctx context.Context, userID string, identity *oauth.Identity) (*model.User, error) {
+	user, err := s.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		logger.Errorf("OAuthLogin.GetUserByID fail, id: %s, error: %s", userID, err)
+		return nil, err
+	}
+
+	if err := s.repo.CreateIdentity(ctx, newUserIdentity(user.ID, identity)); err != nil {
+		logger.Errorf("OAuthLogin.CreateIdentity fail, id: %s, error: %s", userID, err)
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (s *UserService) ListIdentities(ctx context.Context, userID string) ([]*model.UserIdentity, error) {
+	return s.repo.ListIdentities(ctx, userID)
+}
+
+func (s *UserService) UnlinkIdentity(ctx context.Context, userID, provider string) error {
+	user, err := s.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		logger.Errorf("UnlinkIdentity.GetUserByID fail, id: %s, error: %s", userID, err)
+		return err
+	}
+
+	identities, err := s.repo.ListIdentities(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.Password == "" && len(identities) <= 1 {
+		return ErrLastLoginMethod
+	}
+
+	return s.repo.DeleteIdentity(ctx, userID, provider)
+}
+
+func newUserIdentity(userID string, identity *oauth.Identity) *model.UserIdentity {
+	return &model.UserIdentity{
+		ID:       uuid.New().String(),
+		UserID:   userID,
+		Provider: identity.Provider,
+		Subject:  identity.Subject,
+		Email:    identity.Email,
+	}
+}
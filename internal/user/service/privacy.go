@@ -0,0 +1,417 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"time"
+
+	"github.com/quangdangfit/gocommon/logger"
+	"github.com/quangdangfit/gocommon/validation"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+
+	fileModel "main/internal/file/model"
+	"main/internal/user/dto"
+	"main/internal/user/model"
+	"main/internal/user/repository"
+	"main/pkg/audit"
+	"main/pkg/events"
+	"main/pkg/session"
+	"main/pkg/storage"
+)
+
+var (
+	ErrDataRequestPending  = errors.New("a request of this kind is already pending")
+	ErrDataRequestNotFound = errors.New("data request not found")
+	ErrExportNotReady      = errors.New("export is not ready or expired")
+	ErrNoErasure           = errors.New("no pending account erasure")
+)
+
+// erasedName replaces the name of the erased users and doctors
+const erasedName = "Deleted user"
+
+// exportsPrefix groups the export archives in the storage, they are private
+const exportsPrefix = "exports/"
+
+//go:generate mockery --name=IPrivacyService
+type IPrivacyService interface {
+	RequestExport(ctx context.Context, userID string) (*model.DataRequest, error)
+	RequestErasure(ctx context.Context, userID string, req *dto.RequestErasureReq) (*model.DataRequest, error)
+	CancelErasure(ctx context.Context, userID string) error
+	ListDataRequests(ctx context.Context, userID string) ([]*model.DataRequest, error)
+	DownloadExport(ctx context.Context, userID, id string) (*model.DataRequest, io.ReadCloser, error)
+}
+
+// FileRemover deletes the content and the image variants of files from the
+// storage
+type FileRemover interface {
+	RemoveStored(ctx context.Context, files []*fileModel.File)
+}
+
+type PrivacyService struct {
+	validator validation.Validation
+	repo      repository.IUserRepository
+	storage   storage.Storage
+	files     FileRemover
+	sessions  *session.Store
+	recorder  audit.Recorder
+	publisher events.Publisher
+	// exportTTL is how long an export can be downloaded
+	exportTTL time.Duration
+	// gracePeriod is how long an erasure can be cancelled
+	gracePeriod time.Duration
+}
+
+func NewPrivacyService(
+	validator validation.Validation,
+	repo repository.IUserRepository,
+	store storage.Storage,
+	files FileRemover,
+	sessions *session.Store,
+	recorder audit.Recorder,
+	publisher events.Publisher,
+	exportTTL time.Duration,
+	gracePeriod time.Duration) *PrivacyService {
+
+	return &PrivacyService{
+		validator:   validator,
+		repo:        repo,
+		storage:     store,
+		files:       files,
+		sessions:    sessions,
+		recorder:    recorder,
+		publisher:   publisher,
+		exportTTL:   exportTTL,
+		gracePeriod: gracePeriod,
+	}
+}
+
+// RequestExport queues an export of the personal data of userID, processed
+// in the background
+func (s *PrivacyService) RequestExport(ctx context.Context, userID string) (*model.DataRequest, error) {
+	request, err := s.createRequest(ctx, userID, model.DataExport, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	s.recorder.Record(ctx, &audit.Event{
+		Action:     audit.DataExport,
+		TargetType: audit.TargetUser,
+		TargetID:   userID,
+		After:      request,
+	})
+	return request, nil
+}
+
+// RequestErasure erases the account of userID once the grace period is
+// over, until then the user can cancel it
+func (s *PrivacyService) RequestErasure(ctx context.Context, userID string, req *dto.RequestErasureReq) (*model.DataRequest, error) {
+	if err := s.validator.ValidateStruct(req); err != nil {
+		return nil, err
+	}
+
+	user, err := s.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		logger.Errorf("RequestErasure.GetUserByID fail, id: %s, error: %s", userID, err)
+		return nil, err
+	}
+	// accounts created with a social login have no password
+	if user.Password != "" {
+		if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
+			return nil, ErrWrongPassword
+		}
+	}
+
+	request, err := s.createRequest(ctx, userID, model.AccountErasure, time.Now().Add(s.gracePeriod))
+	if err != nil {
+		return nil, err
+	}
+	s.recorder.Record(ctx, &audit.Event{
+		Action:     audit.ErasureRequest,
+		TargetType: audit.TargetUser,
+		TargetID:   userID,
+		After:      request,
+	})
+	return request, nil
+}
+
+func (s *PrivacyService) CancelErasure(ctx context.Context, userID string) error {
+	cancelled, err := s.repo.CancelErasure(ctx, userID)
+	if err != nil {
+		logger.Errorf("CancelErasure fail, user: %s, error: %s", userID, err)
+		return err
+	}
+	if !cancelled {
+		return ErrNoErasure
+	}
+	s.recorder.Record(ctx, &audit.Event{
+		Action:     audit.ErasureCancel,
+		TargetType: audit.TargetUser,
+		TargetID:   userID,
+	})
+	return nil
+}
+
+func (s *PrivacyService) ListDataRequests(ctx context.Context, userID string) ([]*model.DataRequest, error) {
+	requests, err := s.repo.ListDataRequests(ctx, userID)
+	if err != nil {
+		logger.Errorf("ListDataRequests fail, user: %s, error: %s", userID, err)
+		return nil, err
+	}
+	return requests, nil
+}
+
+// DownloadExport returns the archive of an export of userID until it
+// expires
+func (s *PrivacyService) DownloadExport(ctx context.Context, userID, id string) (*model.DataRequest, io.ReadCloser, error) {
+	request, err := s.repo.GetDataRequest(ctx, userID, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil, ErrDataRequestNotFound
+	}
+	if err != nil {
+		logger.Errorf("DownloadExport.GetDataRequest fail, id: %s, error: %s", id, err)
+		return nil, nil, err
+	}
+	if !request.Downloadable() {
+		return nil, nil, ErrExportNotReady
+	}
+
+	content, err := s.storage.Get(ctx, request.Key)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, nil, ErrExportNotReady
+	}
+	if err != nil {
+		logger.Errorf("DownloadExport.Get fail, id: %s, error: %s", id, err)
+		return nil, nil, err
+	}
+	return request, content, nil
+}
+
+// ProcessDataRequests processes the requests due now and deletes the
+// expired exports, it returns the number of requests processed
+func (s *PrivacyService) ProcessDataRequests(ctx context.Context) (int, error) {
+	count := 0
+	for {
+		request, err := s.repo.ClaimDataRequest(ctx, time.Now())
+		if err != nil {
+			logger.Errorf("ProcessDataRequests.Claim fail, error: %s", err)
+			return count, err
+		}
+		if request == nil {
+			break
+		}
+		s.process(ctx, request)
+		count++
+	}
+
+	return count, s.expireExports(ctx)
+}
+
+// RunDataRequests processes the data requests every interval until ctx is
+// done
+func (s *PrivacyService) RunDataRequests(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		_, _ = s.ProcessDataRequests(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Purge queues the erasure of the users deleted before before, the rows
+// that must be kept are anonymized rather than deleted
+func (s *PrivacyService) Purge(ctx context.Context, before time.Time) (int64, error) {
+	ids, err := s.repo.ListPurgeableUsers(ctx, before)
+	if err != nil {
+		logger.Errorf("Purge.ListPurgeableUsers fail, error: %s", err)
+		return 0, err
+	}
+
+	var queued int64
+	for _, id := range ids {
+		if _, err := s.createRequest(ctx, id, model.AccountErasure, time.Now()); err == nil {
+			queued++
+		}
+	}
+	return queued, nil
+}
+
+func (s *PrivacyService) createRequest(ctx context.Context, userID, kind string, dueAt time.Time) (*model.DataRequest, error) {
+	request := model.DataRequest{UserID: userID, Kind: kind, DueAt: dueAt}
+	request.BeforeCreate()
+
+	created, err := s.repo.CreateDataRequest(ctx, &request)
+	if err != nil {
+		logger.Errorf("CreateDataRequest fail, user: %s, kind: %s, error: %s", userID, kind, err)
+		return nil, err
+	}
+	if !created {
+		return nil, ErrDataRequestPending
+	}
+	return &request, nil
+}
+
+func (s *PrivacyService) process(ctx context.Context, request *model.DataRequest) {
+	var err error
+	switch request.Kind {
+	case model.DataExport:
+		err = s.export(ctx, request)
+	case model.AccountErasure:
+		err = s.erase(ctx, request)
+	}
+
+	now := time.Now()
+	request.Status, request.CompletedAt = model.DataRequestCompleted, &now
+	if err != nil {
+		logger.Errorf("ProcessDataRequest fail, id: %s, kind: %s, error: %s", request.ID, request.Kind, err)
+		request.Status, request.Error = model.DataRequestFailed, err.Error()
+	}
+	if err := s.repo.CompleteDataRequest(ctx, request); err != nil {
+		logger.Errorf("ProcessDataRequest.Complete fail, id: %s, error: %s", request.ID, err)
+	}
+}
+
+// export writes the personal data of the user to a zip archive in the
+// storage, one json file per section with the uploaded files. The archive
+// is built in a temporary file, the files are never held in memory.
+func (s *PrivacyService) export(ctx context.Context, request *model.DataRequest) error {
+	data, err := s.repo.GetPersonalData(ctx, request.UserID)
+	if err != nil {
+		return err
+	}
+	// secrets are not personal data
+	data.User.Password = ""
+	data.User.VerifyCodeEmail, data.User.VerifyCodePhoneNumber = 0, 0
+
+	archive, err := os.CreateTemp("", "export-*.zip")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(archive.Name())
+	defer archive.Close()
+
+	if err := s.writeArchive(ctx, archive, data); err != nil {
+		return err
+	}
+	size, err := archive.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	if _, err := archive.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	key := exportsPrefix + request.UserID + "/" + request.ID
+	if err := s.storage.Put(ctx, key, archive, size, "application/zip"); err != nil {
+		return err
+	}
+
+	expiresAt := time.Now().Add(s.exportTTL)
+	request.Key, request.Size, request.ExpiresAt = key, size, &expiresAt
+	return nil
+}
+
+func (s *PrivacyService) writeArchive(ctx context.Context, w io.Writer, data *repository.PersonalData) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	var sections map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &sections); err != nil {
+		return err
+	}
+
+	archive := zip.NewWriter(w)
+	for name, section := range sections {
+		var indented bytes.Buffer
+		if err := json.Indent(&indented, section, "", "  "); err != nil {
+			return err
+		}
+		f, err := archive.Create(name + ".json")
+		if err != nil {
+			return err
+		}
+		if _, err := indented.WriteTo(f); err != nil {
+			return err
+		}
+	}
+	for _, file := range data.Files {
+		if err := s.writeFile(ctx, archive, file); err != nil {
+			return err
+		}
+	}
+	return archive.Close()
+}
+
+func (s *PrivacyService) writeFile(ctx context.Context, archive *zip.Writer, file *fileModel.File) error {
+	content, err := s.storage.Get(ctx, file.Key)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+
+	f, err := archive.Create("files/" + file.ID + "/" + file.Name)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(f, content)
+	return err
+}
+
+// erase erases the account of the user, then what is outside of the
+// database: its sessions, files and export archives
+func (s *PrivacyService) erase(ctx context.Context, request *model.DataRequest) error {
+	erasure, err := s.repo.EraseUser(ctx, request.UserID, erasedName)
+	if err != nil {
+		return err
+	}
+
+	s.sessions.Revoke(ctx, erasure.Sessions...)
+	s.files.RemoveStored(ctx, erasure.Files)
+	for _, key := range erasure.Exports {
+		if err := s.storage.Delete(ctx, key); err != nil {
+			logger.Errorf("EraseUser.Delete fail, key: %s, error: %s", key, err)
+		}
+	}
+
+	s.recorder.Record(ctx, &audit.Event{
+		Action:     audit.UserErase,
+		TargetType: audit.TargetUser,
+		TargetID:   request.UserID,
+	})
+	s.publisher.Publish(ctx, events.New(ctx, events.UserDeleted, request.UserID, &events.Change{Action: events.ActionDeleted, UserID: request.UserID}))
+	return nil
+}
+
+// expireExports deletes the archives of the expired exports
+func (s *PrivacyService) expireExports(ctx context.Context) error {
+	requests, err := s.repo.ListExpiredExports(ctx, time.Now())
+	if err != nil {
+		logger.Errorf("ListExpiredExports fail, error: %s", err)
+		return err
+	}
+
+	for _, request := range requests {
+		if err := s.storage.Delete(ctx, request.Key); err != nil {
+			logger.Errorf("ExpireExport.Delete fail, id: %s, error: %s", request.ID, err)
+			continue
+		}
+		request.Status, request.Key = model.DataRequestExpired, ""
+		if err := s.repo.CompleteDataRequest(ctx, request); err != nil {
+			logger.Errorf("ExpireExport.Complete fail, id: %s, error: %s", request.ID, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,368 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/quangdangfit/gocommon/logger"
+	"github.com/quangdangfit/gocommon/validation"
+	"gorm.io/gorm"
+
+	fileModel "main/internal/file/model"
+	"main/internal/user/dto"
+	"main/internal/user/model"
+	"main/internal/user/repository"
+	"main/pkg/audit"
+	"main/pkg/config"
+	"main/pkg/events"
+	"main/pkg/redis"
+	"main/pkg/session"
+	"main/pkg/storage"
+	"main/pkg/utils"
+)
+
+func TestMain(m *testing.M) {
+	logger.Initialize(config.ProductionEnv)
+	os.Exit(m.Run())
+}
+
+// privacyRepo keeps the data requests in memory like the queries of
+// repository.UserRepo, the erasures are recorded
+type privacyRepo struct {
+	repository.IUserRepository
+	user     *model.User
+	files    []*fileModel.File
+	requests []*model.DataRequest
+	erased   []string
+}
+
+func (r *privacyRepo) GetUserByID(ctx context.Context, id string) (*model.User, error) {
+	if r.user.ID != id {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *r.user
+	return &copied, nil
+}
+
+func (r *privacyRepo) CreateDataRequest(ctx context.Context, request *model.DataRequest) (bool, error) {
+	for _, existing := range r.requests {
+		if existing.UserID == request.UserID && existing.Kind == request.Kind && existing.Status == model.DataRequestPending {
+			return false, nil
+		}
+	}
+	copied := *request
+	r.requests = append(r.requests, &copied)
+	return true, nil
+}
+
+func (r *privacyRepo) GetDataRequest(ctx context.Context, userID, id string) (*model.DataRequest, error) {
+	for _, request := range r.requests {
+		if request.ID == id && request.UserID == userID {
+			copied := *request
+			return &copied, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *privacyRepo) ListDataRequests(ctx context.Context, userID string) ([]*model.DataRequest, error) {
+	// newest first
+	var requests []*model.DataRequest
+	for i := len(r.requests) - 1; i >= 0; i-- {
+		if request := r.requests[i]; request.UserID == userID {
+			copied := *request
+			requests = append(requests, &copied)
+		}
+	}
+	return requests, nil
+}
+
+func (r *privacyRepo) CancelErasure(ctx context.Context, userID string) (bool, error) {
+	cancelled := false
+	for _, request := range r.requests {
+		if request.UserID == userID && request.Kind == model.AccountErasure && request.Status == model.DataRequestPending {
+			request.Status, cancelled = model.DataRequestCancelled, true
+		}
+	}
+	return cancelled, nil
+}
+
+func (r *privacyRepo) ClaimDataRequest(ctx context.Context, now time.Time) (*model.DataRequest, error) {
+	for _, request := range r.requests {
+		if request.Status == model.DataRequestPending && !request.DueAt.After(now) {
+			request.Status = model.DataRequestProcessing
+			copied := *request
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *privacyRepo) CompleteDataRequest(ctx context.Context, request *model.DataRequest) error {
+	for i, stored := range r.requests {
+		if stored.ID == request.ID {
+			copied := *request
+			r.requests[i] = &copied
+		}
+	}
+	return nil
+}
+
+func (r *privacyRepo) ListExpiredExports(ctx context.Context, now time.Time) ([]*model.DataRequest, error) {
+	var expired []*model.DataRequest
+	for _, request := range r.requests {
+		if request.Kind == model.DataExport && request.Status == model.DataRequestCompleted && !request.ExpiresAt.After(now) {
+			copied := *request
+			expired = append(expired, &copied)
+		}
+	}
+	return expired, nil
+}
+
+func (r *privacyRepo) GetPersonalData(ctx context.Context, userID string) (*repository.PersonalData, error) {
+	user, err := r.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &repository.PersonalData{User: user, Files: r.files}, nil
+}
+
+func (r *privacyRepo) EraseUser(ctx context.Context, userID, name string) (*repository.Erasure, error) {
+	r.erased = append(r.erased, userID)
+	erasure := repository.Erasure{Sessions: []string{"session"}, Files: r.files}
+	for _, request := range r.requests {
+		if request.Key != "" {
+			erasure.Exports = append(erasure.Exports, request.Key)
+		}
+	}
+	return &erasure, nil
+}
+
+// due makes the pending requests due now
+func (r *privacyRepo) due() {
+	for _, request := range r.requests {
+		if request.Status == model.DataRequestPending {
+			request.DueAt = time.Now().Add(-time.Second)
+		}
+	}
+}
+
+// removedFiles records the files removed from the storage
+type removedFiles []*fileModel.File
+
+func (f *removedFiles) RemoveStored(ctx context.Context, files []*fileModel.File) {
+	*f = append(*f, files...)
+}
+
+// activeSessions are never revoked in the database
+type activeSessions struct{}
+
+func (activeSessions) Revoked(ctx context.Context, id string) (bool, error) {
+	return false, nil
+}
+
+// published records the events of the service
+type published []*events.Event
+
+func (p *published) Publish(ctx context.Context, event *events.Event) {
+	*p = append(*p, event)
+}
+
+type privacyFixture struct {
+	svc      *PrivacyService
+	repo     *privacyRepo
+	store    storage.Storage
+	sessions *session.Store
+	removed  *removedFiles
+	sent     *published
+}
+
+func newPrivacyService(t *testing.T) *privacyFixture {
+	t.Helper()
+	store, err := storage.NewLocal(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := miniredis.RunT(t)
+	f := &privacyFixture{
+		repo: &privacyRepo{
+			user:  &model.User{ID: "user", Name: "Ada", Password: utils.HashAndSalt([]byte("secret")), VerifyCodeEmail: 123456},
+			files: []*fileModel.File{{ID: "file", OwnerID: "user", Key: "files/file", Name: "scan.pdf"}},
+		},
+		store:    store,
+		sessions: session.NewStore(redis.New(redis.Config{Address: server.Addr(), Timeout: time.Second}), activeSessions{}),
+		removed:  &removedFiles{},
+		sent:     &published{},
+	}
+	f.svc = NewPrivacyService(validation.New(), f.repo, store, f.removed, f.sessions, audit.Nop(), f.sent, time.Hour, 14*24*time.Hour)
+	return f
+}
+
+func TestErasureGracePeriod(t *testing.T) {
+	f := newPrivacyService(t)
+	ctx := context.Background()
+
+	if _, err := f.svc.RequestErasure(ctx, "user", &dto.RequestErasureReq{Password: "wrong"}); !errors.Is(err, ErrWrongPassword) {
+		t.Errorf("erasure with a wrong password = %v, want ErrWrongPassword", err)
+	}
+	request, err := f.svc.RequestErasure(ctx, "user", &dto.RequestErasureReq{Password: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if request.Status != model.DataRequestPending || request.DueAt.Before(time.Now().Add(13*24*time.Hour)) {
+		t.Errorf("erasure %s due at %s", request.Status, request.DueAt)
+	}
+	if _, err := f.svc.RequestErasure(ctx, "user", &dto.RequestErasureReq{Password: "secret"}); !errors.Is(err, ErrDataRequestPending) {
+		t.Errorf("second erasure = %v, want ErrDataRequestPending", err)
+	}
+
+	// nothing is erased during the grace period
+	if count, err := f.svc.ProcessDataRequests(ctx); err != nil || count != 0 {
+		t.Errorf("processed %d requests in the grace period, error %v", count, err)
+	}
+
+	if err := f.svc.CancelErasure(ctx, "user"); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.svc.CancelErasure(ctx, "user"); !errors.Is(err, ErrNoErasure) {
+		t.Errorf("second cancel = %v, want ErrNoErasure", err)
+	}
+	f.repo.due()
+	if count, err := f.svc.ProcessDataRequests(ctx); err != nil || count != 0 {
+		t.Errorf("processed %d cancelled requests, error %v", count, err)
+	}
+	if len(f.repo.erased) != 0 || len(*f.sent) != 0 {
+		t.Errorf("cancelled erasure erased %v, published %d events", f.repo.erased, len(*f.sent))
+	}
+	if err := f.sessions.Check(ctx, "session"); err != nil {
+		t.Errorf("session of the cancelled erasure = %v", err)
+	}
+
+	// once the grace period is over, the account is erased
+	if _, err := f.svc.RequestErasure(ctx, "user", &dto.RequestErasureReq{Password: "secret"}); err != nil {
+		t.Fatal(err)
+	}
+	f.repo.due()
+	if count, err := f.svc.ProcessDataRequests(ctx); err != nil || count != 1 {
+		t.Fatalf("processed %d requests, error %v", count, err)
+	}
+	if len(f.repo.erased) != 1 || f.repo.erased[0] != "user" {
+		t.Errorf("erased %v", f.repo.erased)
+	}
+	if err := f.sessions.Check(ctx, "session"); !errors.Is(err, session.ErrRevoked) {
+		t.Errorf("session of the erased user = %v, want ErrRevoked", err)
+	}
+	if len(*f.removed) != 1 || (*f.removed)[0].ID != "file" {
+		t.Errorf("removed files %+v", *f.removed)
+	}
+	if len(*f.sent) != 1 || (*f.sent)[0].Name != events.UserDeleted || (*f.sent)[0].SubjectID != "user" {
+		t.Errorf("events %+v", *f.sent)
+	}
+
+	requests, err := f.svc.ListDataRequests(ctx, "user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(requests) != 2 || requests[0].Status != model.DataRequestCompleted || requests[1].Status != model.DataRequestCancelled {
+		t.Errorf("requests %+v %+v", requests[0], requests[1])
+	}
+}
+
+func TestExport(t *testing.T) {
+	f := newPrivacyService(t)
+	ctx := context.Background()
+	content := []byte("%PDF-1.4 scan")
+	if err := f.store.Put(ctx, "files/file", bytes.NewReader(content), int64(len(content)), "application/pdf"); err != nil {
+		t.Fatal(err)
+	}
+
+	request, err := f.svc.RequestExport(ctx, "user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.svc.RequestExport(ctx, "user"); !errors.Is(err, ErrDataRequestPending) {
+		t.Errorf("second export = %v, want ErrDataRequestPending", err)
+	}
+	if _, _, err := f.svc.DownloadExport(ctx, "user", request.ID); !errors.Is(err, ErrExportNotReady) {
+		t.Errorf("download before processing = %v, want ErrExportNotReady", err)
+	}
+	if count, err := f.svc.ProcessDataRequests(ctx); err != nil || count != 1 {
+		t.Fatalf("processed %d requests, error %v", count, err)
+	}
+	if _, _, err := f.svc.DownloadExport(ctx, "other", request.ID); !errors.Is(err, ErrDataRequestNotFound) {
+		t.Errorf("download by another user = %v, want ErrDataRequestNotFound", err)
+	}
+
+	completed, archive, err := f.svc.DownloadExport(ctx, "user", request.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := readArchive(t, archive, completed.Size)
+	var user model.User
+	if err := json.Unmarshal(files["user.json"], &user); err != nil {
+		t.Fatal(err)
+	}
+	// the secrets are not exported
+	if user.Name != "Ada" || user.Password != "" || user.VerifyCodeEmail != 0 {
+		t.Errorf("exported user %+v", user)
+	}
+	if !bytes.Equal(files["files/file/scan.pdf"], content) {
+		t.Errorf("exported file %q", files["files/file/scan.pdf"])
+	}
+
+	// expired, the archive is deleted
+	expired := time.Now().Add(-time.Second)
+	f.repo.requests[0].ExpiresAt = &expired
+	if _, err := f.svc.ProcessDataRequests(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if f.repo.requests[0].Status != model.DataRequestExpired || f.repo.requests[0].Key != "" {
+		t.Errorf("expired export %+v", f.repo.requests[0])
+	}
+	if _, err := f.store.Get(ctx, completed.Key); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("expired archive = %v, want ErrNotFound", err)
+	}
+	if _, _, err := f.svc.DownloadExport(ctx, "user", request.ID); !errors.Is(err, ErrExportNotReady) {
+		t.Errorf("download of expired = %v, want ErrExportNotReady", err)
+	}
+}
+
+// readArchive returns the content of the files of a zip archive by name
+func readArchive(t *testing.T, archive io.ReadCloser, size int64) map[string][]byte {
+	t.Helper()
+	defer archive.Close()
+	raw, err := io.ReadAll(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if int64(len(raw)) != size {
+		t.Errorf("archive of %d bytes, want %d", len(raw), size)
+	}
+	reader, err := zip.NewReader(bytes.NewReader(raw), int64(len(raw)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files := map[string][]byte{}
+	for _, file := range reader.File {
+		r, err := file.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, err := io.ReadAll(r)
+		_ = r.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[file.Name] = content
+	}
+	return files
+}
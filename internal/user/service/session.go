@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/quangdangfit/gocommon/logger"
+	"gorm.io/gorm"
+
+	"main/internal/user/model"
+	"main/pkg/audit"
+	"main/pkg/jtoken"
+	"main/pkg/session"
+)
+
+var (
+	ErrSessionNotFound     = errors.New("session not found")
+	ErrSessionRevoked      = errors.New("session revoked or expired")
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+)
+
+// startSession records the login of user and returns its access and refresh
+// tokens
+func (s *UserService) startSession(ctx context.Context, user *model.User) (string, string, error) {
+	client := session.ClientFromContext(ctx)
+	now := time.Now()
+	userSession := &model.Session{
+		ID:             uuid.New().String(),
+		UserID:         user.ID,
+		TenantID:       user.TenantID,
+		Device:         client.Device(),
+		UserAgent:      client.UserAgent,
+		IP:             client.IP,
+		LastSeenAt:     now,
+		ExpiresAt:      now.Add(session.TTL),
+		RefreshTokenID: uuid.New().String(),
+	}
+	if err := s.repo.CreateSession(ctx, userSession); err != nil {
+		logger.Errorf("CreateSession fail, id: %s, error: %s", user.ID, err)
+		return "", "", err
+	}
+
+	accessToken, refreshToken := issueTokens(user, userSession)
+	return accessToken, refreshToken, nil
+}
+
+// RefreshToken rotates the refresh token of a session. A refresh token used
+// twice means it leaked, the whole session is then revoked.
+func (s *UserService) RefreshToken(ctx context.Context, sessionID, tokenID string) (string, string, error) {
+	accessToken, refreshToken, err := s.refreshToken(ctx, sessionID, tokenID)
+	event := audit.Event{Action: audit.TokenRefresh, TargetType: audit.TargetUser, TargetID: audit.ActorFromContext(ctx).UserID}
+	if err != nil {
+		event.Outcome, event.Reason = audit.Failure, failureReason(err)
+	}
+	s.recorder.Record(ctx, &event)
+	return accessToken, refreshToken, err
+}
+
+func (s *UserService) refreshToken(ctx context.Context, sessionID, tokenID string) (string, string, error) {
+	if sessionID == "" || tokenID == "" {
+		return "", "", ErrInvalidRefreshToken
+	}
+
+	userSession, err := s.repo.GetSession(ctx, sessionID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", "", ErrSessionRevoked
+	}
+	if err != nil {
+		logger.Errorf("RefreshToken.GetSession fail, id: %s, error: %s", sessionID, err)
+		return "", "", err
+	}
+	if !userSession.Active() {
+		return "", "", ErrSessionRevoked
+	}
+
+	user, err := s.repo.GetUserByID(ctx, userSession.UserID)
+	if err != nil {
+		logger.Errorf("RefreshToken.GetUserByID fail, id: %s, error: %s", userSession.UserID, err)
+		return "", "", err
+	}
+
+	newTokenID := uuid.New().String()
+	rotated, err := s.repo.RotateRefreshToken(ctx, sessionID, tokenID, newTokenID)
+	if err != nil {
+		logger.Errorf("RefreshToken.RotateRefreshToken fail, id: %s, error: %s", sessionID, err)
+		return "", "", err
+	}
+	if !rotated {
+		logger.Errorf("RefreshToken reused, revoking session, id: %s", sessionID)
+		if err := s.revoke(ctx, userSession.UserID, sessionID); err != nil && !errors.Is(err, ErrSessionNotFound) {
+			return "", "", err
+		}
+		return "", "", ErrSessionRevoked
+	}
+
+	userSession.RefreshTokenID = newTokenID
+	accessToken, refreshToken := issueTokens(user, userSession)
+	return accessToken, refreshToken, nil
+}
+
+// ListSessions returns the active sessions of userID, most recently used
+// first
+func (s *UserService) ListSessions(ctx context.Context, userID string) ([]*model.Session, error) {
+	sessions, err := s.repo.ListActiveSessions(ctx, userID)
+	if err != nil {
+		logger.Errorf("ListActiveSessions fail, id: %s, error: %s", userID, err)
+		return nil, err
+	}
+
+	ids := make([]string, len(sessions))
+	for i, userSession := range sessions {
+		ids[i] = userSession.ID
+	}
+	lastSeen := s.sessions.LastSeen(ctx, ids)
+	for _, userSession := range sessions {
+		if seen, ok := lastSeen[userSession.ID]; ok && seen.After(userSession.LastSeenAt) {
+			userSession.LastSeenAt = seen
+		}
+	}
+
+	return sessions, nil
+}
+
+func (s *UserService) RevokeSession(ctx context.Context, userID, sessionID string) error {
+	if err := s.revoke(ctx, userID, sessionID); err != nil {
+		return err
+	}
+	s.recorder.Record(ctx, &audit.Event{
+		Action:     audit.SessionsRevoke,
+		TargetType: audit.TargetUser,
+		TargetID:   userID,
+		After:      revokedSessions{Sessions: []string{sessionID}},
+	})
+	return nil
+}
+
+// revokedSessions is what the audit log records of a revocation
+type revokedSessions struct {
+	Sessions []string `json:"sessions"`
+}
+
+// RevokeOtherSessions signs userID out everywhere but currentSessionID, an
+// empty currentSessionID revokes every session
+func (s *UserService) RevokeOtherSessions(ctx context.Context, userID, currentSessionID string) error {
+	ids, err := s.repo.RevokeUserSessions(ctx, userID, currentSessionID)
+	if err != nil {
+		logger.Errorf("RevokeUserSessions fail, id: %s, error: %s", userID, err)
+		return err
+	}
+	s.recorder.Record(ctx, &audit.Event{
+		Action:     audit.SessionsRevoke,
+		TargetType: audit.TargetUser,
+		TargetID:   userID,
+		After:      revokedSessions{Sessions: ids},
+	})
+
+	s.sessions.Revoke(ctx, ids...)
+	return nil
+}
+
+func (s *UserService) revoke(ctx context.Context, userID, sessionID string) error {
+	revoked, err := s.repo.RevokeSession(ctx, userID, sessionID)
+	if err != nil {
+		logger.Errorf("RevokeSession fail, id: %s, error: %s", sessionID, err)
+		return err
+	}
+	if !revoked {
+		return ErrSessionNotFound
+	}
+
+	s.sessions.Revoke(ctx, sessionID)
+	return nil
+}
+
+func issueTokens(user *model.User, userSession *model.Session) (string, string) {
+	accessToken := jtoken.GenerateAccessToken(map[string]interface{}{
+		"id":               user.ID,
+		"email":            user.Email,
+		"role":             user.Role,
+		"sid":              userSession.ID,
+		jtoken.TenantClaim: user.TenantID,
+	})
+	refreshToken := jtoken.GenerateRefreshToken(map[string]interface{}{
+		"id":               user.ID,
+		"email":            user.Email,
+		"role":             user.Role,
+		"sid":              userSession.ID,
+		"jti":              userSession.RefreshTokenID,
+		jtoken.TenantClaim: user.TenantID,
+	})
+	return accessToken, refreshToken
+}
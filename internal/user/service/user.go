@@ -3,85 +3,162 @@ package service
 import (
 	"context"
 	"errors"
+	"strings"
 
 	"github.com/quangdangfit/gocommon/logger"
 	"github.com/quangdangfit/gocommon/validation"
 	"golang.org/x/crypto/bcrypt"
-	"golang.org/x/oauth2"
-	googleOauth2 "google.golang.org/api/oauth2/v2"
-	"google.golang.org/api/option"
+	"gorm.io/gorm"
 
 	"main/internal/user/dto"
 	"main/internal/user/model"
 	"main/internal/user/repository"
-	"main/pkg/jtoken"
+	"main/pkg/audit"
+	"main/pkg/events"
+	"main/pkg/imaging"
+	"main/pkg/notify"
+	"main/pkg/oauth"
 	"main/pkg/paging"
+	"main/pkg/patch"
+	"main/pkg/ratelimit"
+	"main/pkg/session"
 	"main/pkg/utils"
 )
 
+var (
+	// ErrRoleNotAllowed is returned when registering a role given by clinics
+	ErrRoleNotAllowed = errors.New("role cannot be registered")
+	ErrWrongRole      = errors.New("wrong Role")
+	ErrWrongPassword  = errors.New("wrong password")
+	ErrEmailInUse     = errors.New("email is used by another account")
+	// ErrUserNotDeleted is also returned for the erased users, they cannot
+	// be restored
+	ErrUserNotDeleted = errors.New("user not found or not deleted")
+)
+
 //go:generate mockery --name=IUserService
 type IUserService interface {
 	Login(ctx context.Context, req *dto.LoginReq) (*model.User, string, string, error)
 	Register(ctx context.Context, req *dto.RegisterReq) (*model.User, error)
 	GetUserByID(ctx context.Context, id string) (*model.User, error)
-	RefreshToken(ctx context.Context, userID string) (string, error)
+	RefreshToken(ctx context.Context, sessionID, tokenID string) (string, string, error)
 	VerifyEmail(ctx context.Context, request dto.VerifyEmailRequest) (dto.VerifyResponse, error)
 	VerifyPhoneNumber(ctx context.Context, request dto.VerifyPhoneNumberRequest) (dto.VerifyResponse, error)
 	ResendVerfiyCodePhone(ctx context.Context, request dto.ResendVerifyPhoneNumberRequest) (dto.VerifyResponse, error)
 	ResendVerfiyCodeEmail(ctx context.Context, request dto.ResendVerifyEmailRequest) (dto.VerifyResponse, error)
 	ListUsers(ctx context.Context, request dto.ListUsersReq) ([]*model.User, *paging.Pagination, error)
 	UpdateUser(ctx context.Context, id string, req *dto.UpdateUserReq) error
+	PatchUser(ctx context.Context, id string, version int64, changes patch.Patch) (*model.User, error)
 	Delete(ctx context.Context, id string, req *dto.DeleteUserReq) (*model.User, error)
-	LoginWithGoogle(ctx context.Context, code string) (*model.User, string, string, error)
-	LoginWithFacebook(ctx context.Context, code string) (*model.User, string, string, error)
+	OAuthURL(ctx context.Context, provider, linkUserID string) (string, error)
+	OAuthLogin(ctx context.Context, provider, state, code string) (*model.User, string, string, error)
+	ListIdentities(ctx context.Context, userID string) ([]*model.UserIdentity, error)
+	UnlinkIdentity(ctx context.Context, userID, provider string) error
+	EnrollMFA(ctx context.Context, userID string) (*dto.EnrollMFARes, error)
+	ConfirmMFA(ctx context.Context, userID string, req *dto.MFACodeReq) ([]string, error)
+	VerifyMFA(ctx context.Context, req *dto.VerifyMFAReq) (*model.User, string, string, error)
+	RegenerateRecoveryCodes(ctx context.Context, userID string, req *dto.MFACodeReq) ([]string, error)
+	DisableMFA(ctx context.Context, userID string, req *dto.DisableMFAReq) error
+	ResetMFA(ctx context.Context, userID string) error
+	ListSessions(ctx context.Context, userID string) ([]*model.Session, error)
+	RevokeSession(ctx context.Context, userID, sessionID string) error
+	RevokeOtherSessions(ctx context.Context, userID, currentSessionID string) error
+	SetAvatar(ctx context.Context, userID string, req *dto.SetAvatarReq) error
+	Restore(ctx context.Context, id string) (*model.User, error)
+}
+
+// ImageProcessor generates the variants of an uploaded image in the
+// background, calling done with their urls
+type ImageProcessor interface {
+	ProcessImage(ctx context.Context, ownerID, fileID string, done func(ctx context.Context, variants imaging.Variants) error) error
 }
 
 type UserService struct {
-	validator     validation.Validation
-	repo          repository.IUserRepository
-	oauthConfig   *oauth2.Config
-	fbOauthConfig *oauth2.Config
+	validator validation.Validation
+	repo      repository.IUserRepository
+	oauth     *oauth.Flow
+	lockout   *ratelimit.Lockout
+	sessions  *session.Store
+	images    ImageProcessor
+	recorder  audit.Recorder
+	publisher events.Publisher
+	// notifier sends the verification codes
+	notifier notify.Notifier
 }
 
 func NewUserService(
 	validator validation.Validation,
-	oauthConfig *oauth2.Config,
-	fbOauthConfig *oauth2.Config, repo repository.IUserRepository) *UserService {
+	oauthFlow *oauth.Flow,
+	repo repository.IUserRepository,
+	lockout *ratelimit.Lockout,
+	sessions *session.Store,
+	images ImageProcessor,
+	recorder audit.Recorder,
+	publisher events.Publisher,
+	notifier notify.Notifier) *UserService {
 
 	return &UserService{
-		validator:     validator,
-		repo:          repo,
-		oauthConfig:   oauthConfig,
-		fbOauthConfig: fbOauthConfig,
+		validator: validator,
+		repo:      repo,
+		oauth:     oauthFlow,
+		lockout:   lockout,
+		sessions:  sessions,
+		images:    images,
+		recorder:  recorder,
+		publisher: publisher,
+		notifier:  notifier,
 	}
 }
 
 func (s *UserService) Login(ctx context.Context, req *dto.LoginReq) (*model.User, string, string, error) {
+	user, accessToken, refreshToken, err := s.login(ctx, req)
+	return s.recordLogin(ctx, audit.Login, user, accessToken, refreshToken, err)
+}
+
+// login returns the user once found, even when the login fails, for the
+// audit log
+func (s *UserService) login(ctx context.Context, req *dto.LoginReq) (*model.User, string, string, error) {
 	if err := s.validator.ValidateStruct(req); err != nil {
 		return nil, "", "", err
 	}
 
+	lockKey := "login:" + strings.ToLower(req.Email)
+	if err := s.lockout.Check(ctx, lockKey); err != nil {
+		return nil, "", "", err
+	}
+
 	user, err := s.repo.GetUserByEmail(ctx, req.Email)
 	if err != nil {
 		logger.Errorf("Login.GetUserByEmail fail, email: %s, error: %s", req.Email, err)
+		if lockErr := s.lockout.Fail(ctx, lockKey); lockErr != nil {
+			return nil, "", "", lockErr
+		}
 		return nil, "", "", err
 	}
 
 	if !(req.Role == user.Role) {
-		return nil, "", "", errors.New("wrong Role")
+		if lockErr := s.lockout.Fail(ctx, lockKey); lockErr != nil {
+			return user, "", "", lockErr
+		}
+		return user, "", "", ErrWrongRole
 	}
 
 	if err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
-		return nil, "", "", errors.New("wrong password")
+		if lockErr := s.lockout.Fail(ctx, lockKey); lockErr != nil {
+			return user, "", "", lockErr
+		}
+		return user, "", "", ErrWrongPassword
 	}
+	s.lockout.Reset(ctx, lockKey)
 
-	tokenData := map[string]interface{}{
-		"id":    user.ID,
-		"email": user.Email,
-		"role":  user.Role,
+	if err := s.mfaChallenge(user); err != nil {
+		return user, "", "", err
+	}
+
+	accessToken, refreshToken, err := s.startSession(ctx, user)
+	if err != nil {
+		return user, "", "", err
 	}
-	accessToken := jtoken.GenerateAccessToken(tokenData)
-	refreshToken := jtoken.GenerateRefreshToken(tokenData)
 	return user, accessToken, refreshToken, nil
 }
 
@@ -89,6 +166,9 @@ func (s *UserService) Register(ctx context.Context, req *dto.RegisterReq) (*mode
 	if err := s.validator.ValidateStruct(req); err != nil {
 		return nil, err
 	}
+	if req.Role == model.UserRoleClinicAdmin {
+		return nil, ErrRoleNotAllowed
+	}
 
 	var user model.User
 	utils.Copy(&user, &req)
@@ -98,6 +178,11 @@ func (s *UserService) Register(ctx context.Context, req *dto.RegisterReq) (*mode
 		logger.Errorf("Register.Create fail, email: %s, error: %s", req.Email, err)
 		return nil, err
 	}
+	s.recorder.Record(ctx, &audit.Event{Action: audit.UserCreate, TargetType: audit.TargetUser, TargetID: user.ID, After: &user})
+	s.publish(ctx, events.UserRegistered, &user, events.ActionCreated)
+	// the user can ask for the codes again
+	s.sendCode(ctx, model.VerificationCodeEmail(&user))
+	s.sendCode(ctx, model.VerificationCodePhone(&user))
 	return &user, nil
 }
 
@@ -111,22 +196,6 @@ func (s *UserService) GetUserByID(ctx context.Context, id string) (*model.User,
 	return user, nil
 }
 
-func (s *UserService) RefreshToken(ctx context.Context, userID string) (string, error) {
-	user, err := s.repo.GetUserByID(ctx, userID)
-	if err != nil {
-		logger.Errorf("RefreshToken.GetUserByID fail, id: %s, error: %s", userID, err)
-		return "", err
-	}
-
-	tokenData := map[string]interface{}{
-		"id":    user.ID,
-		"email": user.Email,
-		"role":  user.Role,
-	}
-	accessToken := jtoken.GenerateAccessToken(tokenData)
-	return accessToken, nil
-}
-
 func (s *UserService) UpdateUser(ctx context.Context, id string, req *dto.UpdateUserReq) error {
 	if err := s.validator.ValidateStruct(req); err != nil {
 		return err
@@ -137,52 +206,140 @@ func (s *UserService) UpdateUser(ctx context.Context, id string, req *dto.Update
 		return err
 	}
 
+	event := audit.Event{Action: audit.UserUpdate, TargetType: audit.TargetUser, TargetID: id, Reason: "password_change"}
 	if err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
-		return errors.New("wrong password")
+		event.Outcome, event.Reason = audit.Failure, "wrong_password"
+		s.recorder.Record(ctx, &event)
+		return ErrWrongPassword
 	}
 
 	user.Password = utils.HashAndSalt([]byte(req.NewPassword))
+	user.Version = req.Version
 	err = s.repo.Update(ctx, user)
 	if err != nil {
 		logger.Errorf("UpdateUser.Update fail, id: %s, error: %s", id, err)
 		return err
 	}
+	s.recorder.Record(ctx, &event)
+	s.publish(ctx, events.UserUpdated, user, events.ActionUpdated)
 
 	return nil
 }
 
+// PatchUser applies changes to the profile of the user at version. A changed
+// email or phone number is not approved anymore and gets a new verification
+// code.
+func (s *UserService) PatchUser(ctx context.Context, id string, version int64, changes patch.Patch) (*model.User, error) {
+	user, err := s.repo.GetUserByID(ctx, id)
+	if err != nil {
+		logger.Errorf("PatchUser.GetUserByID fail, id: %s, error: %s", id, err)
+		return nil, err
+	}
+
+	var current dto.UserProfile
+	utils.Copy(&current, user)
+	var req dto.PatchUserReq
+	if err := patch.Apply(changes, &current, &req); err != nil {
+		return nil, err
+	}
+	if err := s.validator.ValidateStruct(&req); err != nil {
+		return nil, patch.Invalid(err)
+	}
+
+	if req.Name != nil {
+		user.Name = *req.Name
+	}
+	if req.Email != nil {
+		other, err := s.repo.GetUserByEmail(ctx, *req.Email)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Errorf("PatchUser.GetUserByEmail fail, id: %s, error: %s", id, err)
+			return nil, err
+		}
+		if other != nil && other.ID != user.ID {
+			return nil, ErrEmailInUse
+		}
+		user.Email = *req.Email
+		user.ApproveEmail = false
+		user.BeforeUpdateVerificationEmail()
+	}
+	if req.PhoneNumber != nil {
+		user.PhoneNumber = *req.PhoneNumber
+		user.ApprovePhoneNumber = false
+		user.BeforeUpdateVerificationPhone()
+	}
+
+	// the whole user is saved so that the blind indexes follow the changes
+	user.Version = version
+	if err := s.repo.Update(ctx, user); err != nil {
+		logger.Errorf("PatchUser.Update fail, id: %s, error: %s", id, err)
+		return nil, err
+	}
+	s.recorder.Record(ctx, &audit.Event{Action: audit.UserUpdate, TargetType: audit.TargetUser, TargetID: id, Reason: "profile_change"})
+	s.publish(ctx, events.UserUpdated, user, events.ActionUpdated)
+
+	// the codes are only sent once saved, a stale version sends none
+	if req.Email != nil {
+		s.sendCode(ctx, model.VerificationCodeEmail(user))
+	}
+	if req.PhoneNumber != nil {
+		s.sendCode(ctx, model.VerificationCodePhone(user))
+	}
+
+	return user, nil
+}
+
 func (s *UserService) VerifyEmail(ctx context.Context, request dto.VerifyEmailRequest) (dto.VerifyResponse, error) {
+	lockKey := "verify-email:" + strings.ToLower(request.Email)
+	if err := s.lockout.Check(ctx, lockKey); err != nil {
+		return dto.VerifyResponse{Message: "Too many failed attempts"}, err
+	}
+
 	user, err := s.repo.FindByEmailAndVerifyCode(ctx, request.Email, request.VerifyCodeEmail)
 	if err != nil {
 		return dto.VerifyResponse{Message: "Verification failed"}, err
 	}
 
 	if user == nil {
+		if lockErr := s.lockout.Fail(ctx, lockKey); lockErr != nil {
+			return dto.VerifyResponse{Message: "Too many failed attempts"}, lockErr
+		}
 		return dto.VerifyResponse{Message: "Verify code not correct"}, errors.New("verify code not correct")
 	}
+	s.lockout.Reset(ctx, lockKey)
 
 	user.ApproveEmail = true
 	if err := s.repo.Update(ctx, user); err != nil {
 		return dto.VerifyResponse{Message: "Failed to update user"}, err
 	}
+	s.publish(ctx, events.EmailVerified, user, events.ActionUpdated)
 
 	return dto.VerifyResponse{Message: "Verification successful"}, nil
 }
 
 func (s *UserService) VerifyPhoneNumber(ctx context.Context, request dto.VerifyPhoneNumberRequest) (dto.VerifyResponse, error) {
+	lockKey := "verify-phone:" + request.PhoneNumber
+	if err := s.lockout.Check(ctx, lockKey); err != nil {
+		return dto.VerifyResponse{Message: "Too many failed attempts"}, err
+	}
+
 	user, err := s.repo.FindByPhoneAndVerifyCode(ctx, request.PhoneNumber, request.VerifyCodePhoneNumber)
 	if err != nil {
 		return dto.VerifyResponse{Message: "Verification failed"}, err
 	}
 
 	if user == nil {
+		if lockErr := s.lockout.Fail(ctx, lockKey); lockErr != nil {
+			return dto.VerifyResponse{Message: "Too many failed attempts"}, lockErr
+		}
 		return dto.VerifyResponse{Message: "Verify code not correct"}, errors.New("verify code not correct")
 	}
+	s.lockout.Reset(ctx, lockKey)
 
 	user.ApprovePhoneNumber = true
 	if err := s.repo.Update(ctx, user); err != nil {
 		return dto.VerifyResponse{Message: "Failed to update user"}, err
 	}
+	s.publish(ctx, events.PhoneVerified, user, events.ActionUpdated)
 
 	return dto.VerifyResponse{Message: "Verification successful"}, nil
 }
@@ -197,11 +354,11 @@ func (s *UserService) ResendVerfiyCodePhone(ctx context.Context, request dto.Res
 	if user == nil {
 		return dto.VerifyResponse{Message: "Resend Verify code not correct"}, errors.New("verify code not correct")
 	}
-	// sendVerificationCodes sends the verification codes to the user's phone and email
 	user.BeforeUpdateVerificationPhone()
 	if err := s.repo.Update(ctx, user); err != nil {
 		return dto.VerifyResponse{Message: "Failed to Resend user"}, err
 	}
+	s.sendCode(ctx, model.VerificationCodePhone(user))
 
 	return dto.VerifyResponse{Message: "Resend Verify code is successful"}, nil
 }
@@ -216,11 +373,11 @@ func (s *UserService) ResendVerfiyCodeEmail(ctx context.Context, request dto.Res
 	if user == nil {
 		return dto.VerifyResponse{Message: "Resend Verify code not correct"}, errors.New("verify code not correct")
 	}
-	// sendVerificationCodes sends the verification codes to the user's phone and email
 	user.BeforeUpdateVerificationEmail()
 	if err := s.repo.Update(ctx, user); err != nil {
 		return dto.VerifyResponse{Message: "Failed to Resend Resend"}, err
 	}
+	s.sendCode(ctx, model.VerificationCodeEmail(user))
 
 	return dto.VerifyResponse{Message: "Resend Verify code is successful"}, nil
 }
@@ -245,88 +402,65 @@ func (p *UserService) Delete(ctx context.Context, id string, req *dto.DeleteUser
 		return nil, err
 	}
 
-	utils.Copy(User, req)
+	User.Version = req.Version
 	err = p.repo.Delete(ctx, User)
 	if err != nil {
 		logger.Errorf("Delete fail, id: %s, error: %s", id, err)
 		return nil, err
 	}
+	p.recorder.Record(ctx, &audit.Event{Action: audit.UserDelete, TargetType: audit.TargetUser, TargetID: id, Before: User})
+	p.publish(ctx, events.UserDeleted, User, events.ActionDeleted)
 
 	return User, nil
 }
 
-func (uc *UserService) LoginWithGoogle(ctx context.Context, code string) (*model.User, string, string, error) {
-	token, err := uc.oauthConfig.Exchange(ctx, code)
+// Restore restores a deleted user, the erased ones cannot be
+func (p *UserService) Restore(ctx context.Context, id string) (*model.User, error) {
+	restored, err := p.repo.Restore(ctx, id)
 	if err != nil {
-		return nil, "", "", err
+		logger.Errorf("Restore fail, id: %s, error: %s", id, err)
+		return nil, err
 	}
-
-	oauth2Service, err := googleOauth2.NewService(ctx, option.WithTokenSource(uc.oauthConfig.TokenSource(ctx, token)))
-	if err != nil {
-		return nil, "", "", err
+	if !restored {
+		return nil, ErrUserNotDeleted
 	}
 
-	userInfo, err := oauth2Service.Userinfo.Get().Do()
+	user, err := p.repo.GetUserByID(ctx, id)
 	if err != nil {
-		return nil, "", "", err
+		logger.Errorf("Restore.GetUserByID fail, id: %s, error: %s", id, err)
+		return nil, err
 	}
+	p.recorder.Record(ctx, &audit.Event{Action: audit.UserRestore, TargetType: audit.TargetUser, TargetID: id, After: user})
+	p.publish(ctx, events.UserUpdated, user, events.ActionRestored)
 
-	user, err := uc.repo.FindOrCreateByGoogleID(ctx, userInfo.Id, userInfo.Email, userInfo.Name)
-	if err != nil {
-		return nil, "", "", err
-	}
-	tokenData := map[string]interface{}{
-		"id":    user.ID,
-		"email": user.Email,
-		"role":  user.Role,
-	}
-	accessToken := jtoken.GenerateAccessToken(tokenData)
-	refreshToken := jtoken.GenerateRefreshToken(tokenData)
-	return user, accessToken, refreshToken, nil
+	return user, nil
 }
 
-func (s *UserService) LoginWithFacebook(ctx context.Context, code string) (*model.User, string, string, error) {
-	token, err := s.fbOauthConfig.Exchange(ctx, code)
-	if err != nil {
-		return nil, "", "", err
-	}
-
-	// Assuming Facebook does not have a similar NewService function like Google.
-	// You need to use Facebook Graph API to get user information here.
-
-	// Pseudo code:
-	userInfo, err := fetchFacebookUserInfo(token)
-	if err != nil {
-		return nil, "", "", err
-	}
-
-	user, err := s.repo.FindOrCreateByFacebookID(ctx, userInfo.Id, userInfo.Email, userInfo.Name)
-	if err != nil {
-		return nil, "", "", err
+// SetAvatar sets an image uploaded by the user as its picture once its
+// variants are generated
+func (s *UserService) SetAvatar(ctx context.Context, userID string, req *dto.SetAvatarReq) error {
+	if err := s.validator.ValidateStruct(req); err != nil {
+		return err
 	}
 
-	tokenData := map[string]interface{}{
-		"id":    user.ID,
-		"email": user.Email,
-		"role":  user.Role,
-	}
-	accessToken := jtoken.GenerateAccessToken(tokenData)
-	refreshToken := jtoken.GenerateRefreshToken(tokenData)
-	return user, accessToken, refreshToken, nil
+	return s.images.ProcessImage(ctx, userID, req.FileID, func(ctx context.Context, variants imaging.Variants) error {
+		if err := s.repo.UpdateAvatar(ctx, userID, variants); err != nil {
+			return err
+		}
+		s.publisher.Publish(ctx, events.New(ctx, events.UserUpdated, userID, &events.Change{Action: events.ActionUpdated, UserID: userID}))
+		return nil
+	})
 }
 
-// You need to implement the fetchFacebookUserInfo function to interact with the Facebook Graph API.
-func fetchFacebookUserInfo(token *oauth2.Token) (*FacebookUserInfo, error) {
-	// Implement your Facebook Graph API call here.
-	return &FacebookUserInfo{
-		Id:    "example-id",
-		Email: "example-email",
-		Name:  "example-name",
-	}, nil
+// publish tells the subscribers that user changed
+func (s *UserService) publish(ctx context.Context, name string, user *model.User, action string) {
+	s.publisher.Publish(ctx, events.New(ctx, name, user.ID, &events.Change{Action: action, UserID: user.ID}))
 }
 
-type FacebookUserInfo struct {
-	Id    string
-	Email string
-	Name  string
+// sendCode sends a verification code, a failure is only logged since the user
+// can ask for the code again
+func (s *UserService) sendCode(ctx context.Context, msg *notify.Message) {
+	if err := s.notifier.Notify(ctx, msg); err != nil {
+		logger.Errorf("sendCode fail, user: %s, error: %s", msg.UserID, err)
+	}
 }
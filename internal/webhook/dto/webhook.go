@@ -0,0 +1,95 @@
+package dto
+
+import (
+	"time"
+
+	"main/pkg/paging"
+)
+
+// swagger:model WebhookSubscription
+type Subscription struct {
+	ID          string    `json:"id"`
+	URL         string    `json:"url"`
+	Description string    `json:"description"`
+	Events      []string  `json:"events"`
+	ClinicID    string    `json:"clinic_id"`
+	CreatedBy   string    `json:"created_by"`
+	Active      bool      `json:"active"`
+	Failures    int       `json:"failures"`
+	CreatedAt   time.Time `json:"created_at"`
+	// DisabledAt is set when the subscription was disabled, by an admin or
+	// after too many failed deliveries
+	DisabledAt     *time.Time `json:"disabled_at"`
+	DisabledReason string     `json:"disabled_reason"`
+}
+
+// swagger:model CreateWebhookSubscriptionReq
+type CreateSubscriptionReq struct {
+	// example: "https://partner.example.com/webhooks"
+	URL         string `json:"url" validate:"required,url,max=2000"`
+	Description string `json:"description" validate:"max=500"`
+	// Names of the events, or "*" for all of them
+	// example: ["doctor.created","doctor.updated"]
+	Events []string `json:"events" validate:"required,min=1,dive,required"`
+	// ClinicID restricts the subscription to the events of a clinic
+	ClinicID string `json:"clinic_id"`
+}
+
+// CreateSubscriptionRes has the secret signing the callbacks, the only time
+// it is returned
+// swagger:model CreateWebhookSubscriptionRes
+type CreateSubscriptionRes struct {
+	Subscription Subscription `json:"subscription"`
+	Secret       string       `json:"secret"`
+}
+
+// UpdateSubscriptionReq replaces the url and events. Activating a disabled
+// subscription resets its failures.
+// swagger:model UpdateWebhookSubscriptionReq
+type UpdateSubscriptionReq struct {
+	URL         string   `json:"url" validate:"required,url,max=2000"`
+	Description string   `json:"description" validate:"max=500"`
+	Events      []string `json:"events" validate:"required,min=1,dive,required"`
+	Active      bool     `json:"active"`
+}
+
+// swagger:model ListWebhookSubscriptionsRes
+type ListSubscriptionsRes struct {
+	Subscriptions []*Subscription `json:"subscriptions"`
+}
+
+// swagger:model WebhookDelivery
+type Delivery struct {
+	ID             string     `json:"id"`
+	SubscriptionID string     `json:"subscription_id"`
+	EventID        string     `json:"event_id"`
+	EventName      string     `json:"event_name"`
+	Payload        string     `json:"payload"`
+	Status         string     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	NextAttemptAt  time.Time  `json:"next_attempt_at"`
+	ResponseStatus int        `json:"response_status"`
+	ResponseBody   string     `json:"response_body"`
+	Error          string     `json:"error"`
+	DurationMS     int64      `json:"duration_ms"`
+	DeliveredAt    *time.Time `json:"delivered_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+type ListDeliveriesReq struct {
+	// example: "failed"
+	Status string `json:"status,omitempty" form:"status" validate:"omitempty,oneof=pending succeeded failed"`
+	Page   int64  `json:"page,omitempty" form:"page"`
+	Limit  int64  `json:"limit,omitempty" form:"limit"`
+}
+
+// swagger:model ListWebhookDeliveriesRes
+type ListDeliveriesRes struct {
+	Deliveries []*Delivery        `json:"deliveries"`
+	Pagination *paging.Pagination `json:"pagination"`
+}
+
+// swagger:model RotateWebhookSecretRes
+type RotateSecretRes struct {
+	Secret string `json:"secret"`
+}
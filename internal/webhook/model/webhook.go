@@ -0,0 +1,123 @@
+package model
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AllEvents subscribes to every event
+const AllEvents = "*"
+
+// Subscription posts the events it subscribes to to the URL of a partner,
+// signed with its secret. It is disabled after too many failed deliveries in
+// a row.
+type Subscription struct {
+	ID          string    `json:"id" gorm:"unique;not null;index;primary_key"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	URL         string    `json:"url" gorm:"not null"`
+	Description string    `json:"description"`
+	Events      Events    `json:"events" gorm:"type:text;not null"`
+	Secret      string    `json:"-" gorm:"not null;type:text;serializer:encrypted"`
+	// ClinicID restricts the subscription to the events of a clinic, empty
+	// for the events of all clinics
+	ClinicID  string `json:"clinic_id" gorm:"not null;default:'';index"`
+	CreatedBy string `json:"created_by"`
+	Active    bool   `json:"active" gorm:"not null;default:true;index"`
+	// Failures counts the deliveries that failed in a row
+	Failures       int        `json:"failures" gorm:"not null;default:0"`
+	DisabledAt     *time.Time `json:"disabled_at"`
+	DisabledReason string     `json:"disabled_reason"`
+}
+
+func (Subscription) TableName() string {
+	return "webhook_subscriptions"
+}
+
+func (m *Subscription) BeforeCreate() error {
+	m.ID = uuid.New().String()
+	m.CreatedAt = time.Now()
+	m.Active = true
+	return nil
+}
+
+// Wants reports whether the subscription gets the event name of clinicID
+func (m *Subscription) Wants(name, clinicID string) bool {
+	if !m.Active || (m.ClinicID != "" && m.ClinicID != clinicID) {
+		return false
+	}
+	for _, event := range m.Events {
+		if event == AllEvents || event == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Events are stored space separated
+type Events []string
+
+func (e Events) Value() (driver.Value, error) {
+	return strings.Join(e, " "), nil
+}
+
+func (e *Events) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case string:
+		*e = strings.Fields(v)
+	case []byte:
+		*e = strings.Fields(string(v))
+	case nil:
+		*e = nil
+	default:
+		return fmt.Errorf("cannot scan %T into Events", value)
+	}
+	return nil
+}
+
+const (
+	DeliveryPending   = "pending"
+	DeliverySucceeded = "succeeded"
+	// DeliveryFailed deliveries were attempted the maximum number of times,
+	// or their subscription was disabled. They can be redelivered.
+	DeliveryFailed = "failed"
+)
+
+// Delivery is the log of the callbacks of an event to a subscription, the
+// attempts are made until one succeeds or the maximum is reached
+type Delivery struct {
+	ID             string    `json:"id" gorm:"unique;not null;index;primary_key"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+	SubscriptionID string    `json:"subscription_id" gorm:"not null;uniqueIndex:idx_webhook_delivery_event"`
+	EventID        string    `json:"event_id" gorm:"not null;uniqueIndex:idx_webhook_delivery_event"`
+	EventName      string    `json:"event_name" gorm:"not null"`
+	Payload        string    `json:"payload" gorm:"type:text;not null"`
+	Status         string    `json:"status" gorm:"not null;index:idx_webhook_delivery_due"`
+	Attempts       int       `json:"attempts" gorm:"not null;default:0"`
+	// NextAttemptAt is when a pending delivery is attempted
+	NextAttemptAt time.Time `json:"next_attempt_at" gorm:"not null;index:idx_webhook_delivery_due"`
+	// ResponseStatus and ResponseBody are the response to the last attempt,
+	// Error why it failed
+	ResponseStatus int        `json:"response_status"`
+	ResponseBody   string     `json:"response_body" gorm:"type:text"`
+	Error          string     `json:"error"`
+	DurationMS     int64      `json:"duration_ms"`
+	DeliveredAt    *time.Time `json:"delivered_at"`
+}
+
+func (Delivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+func (m *Delivery) BeforeCreate() error {
+	m.ID = uuid.New().String()
+	m.CreatedAt = time.Now()
+	m.Status = DeliveryPending
+	m.NextAttemptAt = m.CreatedAt
+	return nil
+}
@@ -0,0 +1,250 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/quangdangfit/gocommon/logger"
+
+	"main/internal/webhook/dto"
+	"main/internal/webhook/service"
+	"main/pkg/response"
+	"main/pkg/utils"
+)
+
+type WebhookHandler struct {
+	service service.IWebhookService
+}
+
+func NewWebhookHandler(service service.IWebhookService) *WebhookHandler {
+	return &WebhookHandler{service: service}
+}
+
+// CreateSubscription godoc
+//
+//	@Summary	Subscribe a partner URL to events, the callbacks are signed with the returned secret
+//	@Tags		Webhook-admin
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		_	body		dto.CreateSubscriptionReq	true	"Body"
+//	@Success	200	{object}	dto.CreateSubscriptionRes
+//	@Router		/webhook-admin/subscriptions [post]
+func (h *WebhookHandler) CreateSubscription(c *gin.Context) {
+	var req dto.CreateSubscriptionReq
+	if err := c.ShouldBindJSON(&req); c.Request.Body == nil || err != nil {
+		logger.Error("Failed to get body", err)
+		response.Error(c, http.StatusBadRequest, err, "Invalid parameters")
+		return
+	}
+
+	subscription, secret, err := h.service.CreateSubscription(c, c.GetString("userId"), &req)
+	if err != nil {
+		logger.Error("Failed to create webhook subscription ", err)
+		response.Error(c, http.StatusBadRequest, err, err.Error())
+		return
+	}
+
+	res := dto.CreateSubscriptionRes{Secret: secret}
+	utils.Copy(&res.Subscription, subscription)
+	response.JSON(c, http.StatusOK, res)
+}
+
+// ListSubscriptions godoc
+//
+//	@Summary	List the webhook subscriptions
+//	@Tags		Webhook-admin
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Success	200	{object}	dto.ListSubscriptionsRes
+//	@Router		/webhook-admin/subscriptions [get]
+func (h *WebhookHandler) ListSubscriptions(c *gin.Context) {
+	subscriptions, err := h.service.ListSubscriptions(c)
+	if err != nil {
+		logger.Error("Failed to list webhook subscriptions ", err)
+		response.Error(c, http.StatusInternalServerError, err, "Something went wrong")
+		return
+	}
+
+	var res dto.ListSubscriptionsRes
+	utils.Copy(&res.Subscriptions, &subscriptions)
+	response.JSON(c, http.StatusOK, res)
+}
+
+// GetSubscription godoc
+//
+//	@Summary	Get a webhook subscription
+//	@Tags		Webhook-admin
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		id	path		string	true	"Subscription ID"
+//	@Success	200	{object}	dto.Subscription
+//	@Router		/webhook-admin/subscriptions/{id} [get]
+func (h *WebhookHandler) GetSubscription(c *gin.Context) {
+	subscription, err := h.service.GetSubscription(c, c.Param("id"))
+	if err != nil {
+		webhookError(c, err, "Failed to get webhook subscription ")
+		return
+	}
+
+	var res dto.Subscription
+	utils.Copy(&res, subscription)
+	response.JSON(c, http.StatusOK, res)
+}
+
+// UpdateSubscription godoc
+//
+//	@Summary	Update a webhook subscription, activating it again resets its failures
+//	@Tags		Webhook-admin
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		id	path		string						true	"Subscription ID"
+//	@Param		_	body		dto.UpdateSubscriptionReq	true	"Body"
+//	@Success	200	{object}	dto.Subscription
+//	@Router		/webhook-admin/subscriptions/{id} [put]
+func (h *WebhookHandler) UpdateSubscription(c *gin.Context) {
+	var req dto.UpdateSubscriptionReq
+	if err := c.ShouldBindJSON(&req); c.Request.Body == nil || err != nil {
+		logger.Error("Failed to get body", err)
+		response.Error(c, http.StatusBadRequest, err, "Invalid parameters")
+		return
+	}
+
+	subscription, err := h.service.UpdateSubscription(c, c.Param("id"), &req)
+	if err != nil {
+		webhookError(c, err, "Failed to update webhook subscription ")
+		return
+	}
+
+	var res dto.Subscription
+	utils.Copy(&res, subscription)
+	response.JSON(c, http.StatusOK, res)
+}
+
+// DeleteSubscription godoc
+//
+//	@Summary	Delete a webhook subscription and its deliveries
+//	@Tags		Webhook-admin
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		id	path	string	true	"Subscription ID"
+//	@Success	200
+//	@Router		/webhook-admin/subscriptions/{id} [delete]
+func (h *WebhookHandler) DeleteSubscription(c *gin.Context) {
+	if err := h.service.DeleteSubscription(c, c.Param("id")); err != nil {
+		webhookError(c, err, "Failed to delete webhook subscription ")
+		return
+	}
+
+	response.JSON(c, http.StatusOK, nil)
+}
+
+// RotateSecret godoc
+//
+//	@Summary	Replace the secret signing the callbacks of a subscription
+//	@Tags		Webhook-admin
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		id	path		string	true	"Subscription ID"
+//	@Success	200	{object}	dto.RotateSecretRes
+//	@Router		/webhook-admin/subscriptions/{id}/rotate-secret [post]
+func (h *WebhookHandler) RotateSecret(c *gin.Context) {
+	secret, err := h.service.RotateSecret(c, c.Param("id"))
+	if err != nil {
+		webhookError(c, err, "Failed to rotate webhook secret ")
+		return
+	}
+
+	response.JSON(c, http.StatusOK, dto.RotateSecretRes{Secret: secret})
+}
+
+// Ping godoc
+//
+//	@Summary	Send a webhook.ping event to a subscription and return its delivery
+//	@Tags		Webhook-admin
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		id	path		string	true	"Subscription ID"
+//	@Success	200	{object}	dto.Delivery
+//	@Failure	409	{object}	response.Response	"Subscription disabled"
+//	@Router		/webhook-admin/subscriptions/{id}/ping [post]
+func (h *WebhookHandler) Ping(c *gin.Context) {
+	delivery, err := h.service.Ping(c, c.Param("id"))
+	if err != nil {
+		webhookError(c, err, "Failed to ping webhook subscription ")
+		return
+	}
+
+	var res dto.Delivery
+	utils.Copy(&res, delivery)
+	response.JSON(c, http.StatusOK, res)
+}
+
+// ListDeliveries godoc
+//
+//	@Summary	List the deliveries of a subscription, the latest first
+//	@Tags		Webhook-admin
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		id		path		string	true	"Subscription ID"
+//	@Param		status	query		string	false	"pending, succeeded or failed"
+//	@Param		page	query		int		false	"Page"
+//	@Param		limit	query		int		false	"Limit"
+//	@Success	200		{object}	dto.ListDeliveriesRes
+//	@Router		/webhook-admin/subscriptions/{id}/deliveries [get]
+func (h *WebhookHandler) ListDeliveries(c *gin.Context) {
+	var req dto.ListDeliveriesReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.Error("Failed to get query params", err)
+		response.Error(c, http.StatusBadRequest, err, "Invalid parameters")
+		return
+	}
+
+	deliveries, pagination, err := h.service.ListDeliveries(c, c.Param("id"), &req)
+	if err != nil {
+		webhookError(c, err, "Failed to list webhook deliveries ")
+		return
+	}
+
+	var res dto.ListDeliveriesRes
+	utils.Copy(&res.Deliveries, &deliveries)
+	res.Pagination = pagination
+	response.JSON(c, http.StatusOK, res)
+}
+
+// Redeliver godoc
+//
+//	@Summary	Attempt a delivery again, with as many attempts as a new one
+//	@Tags		Webhook-admin
+//	@Security	ApiKeyAuth
+//	@Produce	json
+//	@Param		id			path		string	true	"Subscription ID"
+//	@Param		delivery_id	path		string	true	"Delivery ID"
+//	@Success	200			{object}	dto.Delivery
+//	@Failure	409			{object}	response.Response	"Subscription disabled"
+//	@Router		/webhook-admin/subscriptions/{id}/deliveries/{delivery_id}/redeliver [post]
+func (h *WebhookHandler) Redeliver(c *gin.Context) {
+	delivery, err := h.service.Redeliver(c, c.Param("id"), c.Param("delivery_id"))
+	if err != nil {
+		webhookError(c, err, "Failed to redeliver webhook ")
+		return
+	}
+
+	var res dto.Delivery
+	utils.Copy(&res, delivery)
+	response.JSON(c, http.StatusOK, res)
+}
+
+func webhookError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrSubscriptionNotFound), errors.Is(err, service.ErrDeliveryNotFound):
+		response.Error(c, http.StatusNotFound, err, err.Error())
+	case errors.Is(err, service.ErrSubscriptionDisabled):
+		response.Error(c, http.StatusConflict, err, err.Error())
+	case errors.Is(err, service.ErrUnknownEvent):
+		response.Error(c, http.StatusBadRequest, err, err.Error())
+	default:
+		logger.Error(message, err)
+		response.Error(c, http.StatusBadRequest, err, err.Error())
+	}
+}
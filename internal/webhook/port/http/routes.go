@@ -0,0 +1,36 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/quangdangfit/gocommon/validation"
+
+	"main/internal/webhook/repository"
+	"main/internal/webhook/service"
+	"main/pkg/audit"
+	"main/pkg/config"
+	"main/pkg/dbs"
+	"main/pkg/middleware"
+	"main/pkg/rbac"
+	"main/pkg/webhook"
+)
+
+func Routes(r *gin.RouterGroup, sqlDB dbs.IDatabase, validator validation.Validation, auth *middleware.Authenticator, recorder audit.Recorder) {
+	cfg := config.GetConfig()
+	webhookSvc := service.NewWebhookService(validator, repository.NewWebhookRepository(sqlDB), webhook.NewSender(cfg.WebhookTimeout), recorder, service.OptionsFromConfig(cfg))
+	webhookHandler := NewWebhookHandler(webhookSvc)
+
+	webhooksManage := middleware.JWTPermission(auth, rbac.WebhooksManage)
+	webhookRouteAdmin := r.Group("/webhook-admin", webhooksManage)
+	{
+		webhookRouteAdmin.GET("/subscriptions", webhookHandler.ListSubscriptions)
+		webhookRouteAdmin.POST("/subscriptions", webhookHandler.CreateSubscription)
+		webhookRouteAdmin.GET("/subscriptions/:id", webhookHandler.GetSubscription)
+		webhookRouteAdmin.PUT("/subscriptions/:id", webhookHandler.UpdateSubscription)
+		webhookRouteAdmin.DELETE("/subscriptions/:id", webhookHandler.DeleteSubscription)
+		webhookRouteAdmin.POST("/subscriptions/:id/rotate-secret", webhookHandler.RotateSecret)
+		// sends a webhook.ping right away to check the receiver
+		webhookRouteAdmin.POST("/subscriptions/:id/ping", webhookHandler.Ping)
+		webhookRouteAdmin.GET("/subscriptions/:id/deliveries", webhookHandler.ListDeliveries)
+		webhookRouteAdmin.POST("/subscriptions/:id/deliveries/:delivery_id/redeliver", webhookHandler.Redeliver)
+	}
+}
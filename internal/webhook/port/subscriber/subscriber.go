@@ -0,0 +1,14 @@
+package subscriber
+
+import (
+	"context"
+
+	"main/internal/webhook/service"
+	"main/pkg/events"
+)
+
+// Subscribe queues the deliveries of every event to the webhook
+// subscriptions wanting it
+func Subscribe(ctx context.Context, bus *events.Bus, webhookSvc *service.WebhookService) error {
+	return bus.Subscribe(ctx, "webhooks", webhookSvc.Enqueue, events.Names...)
+}
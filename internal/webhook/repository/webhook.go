@@ -0,0 +1,217 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"main/internal/webhook/dto"
+	"main/internal/webhook/model"
+	"main/pkg/config"
+	"main/pkg/dbs"
+	"main/pkg/paging"
+	"main/pkg/tenant"
+)
+
+//go:generate mockery --name=IWebhookRepository
+type IWebhookRepository interface {
+	CreateSubscription(ctx context.Context, subscription *model.Subscription) error
+	UpdateSubscription(ctx context.Context, subscription *model.Subscription) error
+	GetSubscription(ctx context.Context, id string) (*model.Subscription, error)
+	ListSubscriptions(ctx context.Context) ([]*model.Subscription, error)
+	ListActiveSubscriptions(ctx context.Context) ([]*model.Subscription, error)
+	DeleteSubscription(ctx context.Context, id string) (bool, error)
+	CreateDeliveries(ctx context.Context, deliveries []*model.Delivery) error
+	GetDelivery(ctx context.Context, subscriptionID, id string) (*model.Delivery, error)
+	ListDeliveries(ctx context.Context, subscriptionID string, req *dto.ListDeliveriesReq) ([]*model.Delivery, *paging.Pagination, error)
+	ClaimDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*model.Delivery, error)
+	SaveDelivery(ctx context.Context, delivery *model.Delivery) error
+	RecordFailure(ctx context.Context, subscriptionID string, disableAfter int, reason string) (bool, error)
+	ResetFailures(ctx context.Context, subscriptionID string) error
+	FailPendingDeliveries(ctx context.Context, subscriptionID, reason string) error
+	Purge(ctx context.Context, before time.Time) (int64, error)
+}
+
+// WebhookRepo keeps the subscriptions and deliveries of every clinic, they
+// are managed by the admins and the delivery worker outside of any clinic
+type WebhookRepo struct {
+	db dbs.IDatabase
+}
+
+func NewWebhookRepository(db dbs.IDatabase) *WebhookRepo {
+	return &WebhookRepo{db: db}
+}
+
+func (r *WebhookRepo) gorm(ctx context.Context) *gorm.DB {
+	return r.db.GetDB().WithContext(tenant.Global(ctx))
+}
+
+func (r *WebhookRepo) CreateSubscription(ctx context.Context, subscription *model.Subscription) error {
+	return r.gorm(ctx).Create(subscription).Error
+}
+
+func (r *WebhookRepo) UpdateSubscription(ctx context.Context, subscription *model.Subscription) error {
+	return r.gorm(ctx).Save(subscription).Error
+}
+
+func (r *WebhookRepo) GetSubscription(ctx context.Context, id string) (*model.Subscription, error) {
+	var subscription model.Subscription
+	if err := r.gorm(ctx).Where("id = ?", id).First(&subscription).Error; err != nil {
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+func (r *WebhookRepo) ListSubscriptions(ctx context.Context) ([]*model.Subscription, error) {
+	var subscriptions []*model.Subscription
+	if err := r.gorm(ctx).Order("created_at").Find(&subscriptions).Error; err != nil {
+		return nil, err
+	}
+	return subscriptions, nil
+}
+
+func (r *WebhookRepo) ListActiveSubscriptions(ctx context.Context) ([]*model.Subscription, error) {
+	var subscriptions []*model.Subscription
+	if err := r.gorm(ctx).Where("active = ?", true).Find(&subscriptions).Error; err != nil {
+		return nil, err
+	}
+	return subscriptions, nil
+}
+
+// DeleteSubscription deletes the subscription and its delivery logs, false
+// when it does not exist
+func (r *WebhookRepo) DeleteSubscription(ctx context.Context, id string) (bool, error) {
+	deleted := false
+	err := r.gorm(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("subscription_id = ?", id).Delete(&model.Delivery{}).Error; err != nil {
+			return err
+		}
+		result := tx.Where("id = ?", id).Delete(&model.Subscription{})
+		deleted = result.RowsAffected > 0
+		return result.Error
+	})
+	return deleted, err
+}
+
+// CreateDeliveries creates the deliveries of an event, an event delivered
+// again to the subscribers keeps its first deliveries
+func (r *WebhookRepo) CreateDeliveries(ctx context.Context, deliveries []*model.Delivery) error {
+	return r.gorm(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&deliveries).Error
+}
+
+func (r *WebhookRepo) GetDelivery(ctx context.Context, subscriptionID, id string) (*model.Delivery, error) {
+	var delivery model.Delivery
+	err := r.gorm(ctx).
+		Where("id = ? AND subscription_id = ?", id, subscriptionID).
+		First(&delivery).Error
+	if err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+func (r *WebhookRepo) ListDeliveries(ctx context.Context, subscriptionID string, req *dto.ListDeliveriesReq) ([]*model.Delivery, *paging.Pagination, error) {
+	ctx, cancel := context.WithTimeout(tenant.Global(ctx), config.DatabaseTimeout)
+	defer cancel()
+
+	query := []dbs.Query{dbs.NewQuery("subscription_id = ?", subscriptionID)}
+	if req.Status != "" {
+		query = append(query, dbs.NewQuery("status = ?", req.Status))
+	}
+
+	var total int64
+	if err := r.db.Count(ctx, &model.Delivery{}, &total, dbs.WithQuery(query...)); err != nil {
+		return nil, nil, err
+	}
+
+	pagination := paging.New(req.Page, req.Limit, total)
+
+	var deliveries []*model.Delivery
+	if err := r.db.Find(
+		ctx,
+		&deliveries,
+		dbs.WithQuery(query...),
+		dbs.WithLimit(int(pagination.Limit)),
+		dbs.WithOffset(int(pagination.Skip)),
+		dbs.WithOrder("created_at DESC"),
+	); err != nil {
+		return nil, nil, err
+	}
+
+	return deliveries, pagination, nil
+}
+
+// ClaimDeliveries returns up to limit pending deliveries due at now and
+// leaves them to the caller until leaseUntil, when they are due again if the
+// caller stopped. Concurrent workers never claim the same delivery.
+func (r *WebhookRepo) ClaimDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*model.Delivery, error) {
+	var deliveries []*model.Delivery
+	err := r.gorm(ctx).Raw(`
+		UPDATE webhook_deliveries SET next_attempt_at = ?, updated_at = ?
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status = ? AND next_attempt_at <= ?
+			ORDER BY next_attempt_at
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`,
+		leaseUntil, now, model.DeliveryPending, now, limit,
+	).Scan(&deliveries).Error
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// SaveDelivery saves the outcome of an attempt
+func (r *WebhookRepo) SaveDelivery(ctx context.Context, delivery *model.Delivery) error {
+	return r.gorm(ctx).Save(delivery).Error
+}
+
+// RecordFailure counts a failed delivery of the subscription and disables
+// it once disableAfter deliveries failed in a row, true when it did
+func (r *WebhookRepo) RecordFailure(ctx context.Context, subscriptionID string, disableAfter int, reason string) (bool, error) {
+	err := r.gorm(ctx).Model(&model.Subscription{}).
+		Where("id = ?", subscriptionID).
+		Update("failures", gorm.Expr("failures + 1")).Error
+	if err != nil {
+		return false, err
+	}
+
+	result := r.gorm(ctx).Model(&model.Subscription{}).
+		Where("id = ? AND active = ? AND failures >= ?", subscriptionID, true, disableAfter).
+		Updates(map[string]interface{}{
+			"active":          false,
+			"disabled_at":     time.Now(),
+			"disabled_reason": reason,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *WebhookRepo) ResetFailures(ctx context.Context, subscriptionID string) error {
+	return r.gorm(ctx).Model(&model.Subscription{}).
+		Where("id = ? AND failures > 0", subscriptionID).
+		Update("failures", 0).Error
+}
+
+// FailPendingDeliveries stops the pending deliveries of a disabled
+// subscription, they can be redelivered once it is active again
+func (r *WebhookRepo) FailPendingDeliveries(ctx context.Context, subscriptionID, reason string) error {
+	return r.gorm(ctx).Model(&model.Delivery{}).
+		Where("subscription_id = ? AND status = ?", subscriptionID, model.DeliveryPending).
+		Updates(map[string]interface{}{
+			"status": model.DeliveryFailed,
+			"error":  reason,
+		}).Error
+}
+
+// Purge deletes the delivery logs completed before before
+func (r *WebhookRepo) Purge(ctx context.Context, before time.Time) (int64, error) {
+	result := r.gorm(ctx).
+		Where("status <> ? AND updated_at < ?", model.DeliveryPending, before).
+		Delete(&model.Delivery{})
+	return result.RowsAffected, result.Error
+}
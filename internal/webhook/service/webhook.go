@@ -0,0 +1,464 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/quangdangfit/gocommon/logger"
+	"github.com/quangdangfit/gocommon/validation"
+	"gorm.io/gorm"
+
+	"main/internal/webhook/dto"
+	"main/internal/webhook/model"
+	"main/internal/webhook/repository"
+	"main/pkg/audit"
+	"main/pkg/config"
+	"main/pkg/events"
+	"main/pkg/paging"
+	"main/pkg/webhook"
+)
+
+// PingEvent is the event sent by Ping to check a receiver
+const PingEvent = "webhook.ping"
+
+// deliveryBatch is how many deliveries a worker claims at once
+const deliveryBatch = 50
+
+var (
+	ErrSubscriptionNotFound = errors.New("webhook subscription not found")
+	ErrDeliveryNotFound     = errors.New("webhook delivery not found")
+	ErrSubscriptionDisabled = errors.New("webhook subscription is disabled")
+	ErrUnknownEvent         = errors.New("unknown event")
+)
+
+//go:generate mockery --name=IWebhookService
+type IWebhookService interface {
+	CreateSubscription(ctx context.Context, createdBy string, req *dto.CreateSubscriptionReq) (*model.Subscription, string, error)
+	UpdateSubscription(ctx context.Context, id string, req *dto.UpdateSubscriptionReq) (*model.Subscription, error)
+	GetSubscription(ctx context.Context, id string) (*model.Subscription, error)
+	ListSubscriptions(ctx context.Context) ([]*model.Subscription, error)
+	DeleteSubscription(ctx context.Context, id string) error
+	RotateSecret(ctx context.Context, id string) (string, error)
+	Ping(ctx context.Context, id string) (*model.Delivery, error)
+	ListDeliveries(ctx context.Context, subscriptionID string, req *dto.ListDeliveriesReq) ([]*model.Delivery, *paging.Pagination, error)
+	Redeliver(ctx context.Context, subscriptionID, id string) (*model.Delivery, error)
+}
+
+// Options are the retries of the deliveries
+type Options struct {
+	MaxAttempts int
+	Backoff     time.Duration
+	MaxBackoff  time.Duration
+	// DisableAfter failed attempts in a row disable a subscription
+	DisableAfter int
+	// Lease is how long a claimed delivery is left to its worker
+	Lease time.Duration
+}
+
+func OptionsFromConfig(cfg *config.Schema) Options {
+	return Options{
+		MaxAttempts:  cfg.WebhookMaxAttempts,
+		Backoff:      cfg.WebhookBackoff,
+		MaxBackoff:   cfg.WebhookMaxBackoff,
+		DisableAfter: cfg.WebhookDisableAfter,
+		Lease:        2 * cfg.WebhookTimeout,
+	}
+}
+
+type WebhookService struct {
+	validator validation.Validation
+	repo      repository.IWebhookRepository
+	sender    *webhook.Sender
+	recorder  audit.Recorder
+	options   Options
+}
+
+func NewWebhookService(
+	validator validation.Validation,
+	repo repository.IWebhookRepository,
+	sender *webhook.Sender,
+	recorder audit.Recorder,
+	options Options,
+) *WebhookService {
+	return &WebhookService{
+		validator: validator,
+		repo:      repo,
+		sender:    sender,
+		recorder:  recorder,
+		options:   options,
+	}
+}
+
+// CreateSubscription returns the subscription with the secret signing its
+// callbacks, the only time it is available
+func (s *WebhookService) CreateSubscription(ctx context.Context, createdBy string, req *dto.CreateSubscriptionReq) (*model.Subscription, string, error) {
+	if err := s.validator.ValidateStruct(req); err != nil {
+		return nil, "", err
+	}
+	if err := validateEvents(req.Events); err != nil {
+		return nil, "", err
+	}
+
+	secret, err := webhook.GenerateSecret()
+	if err != nil {
+		return nil, "", err
+	}
+	subscription := &model.Subscription{
+		URL:         req.URL,
+		Description: req.Description,
+		Events:      req.Events,
+		Secret:      secret,
+		ClinicID:    req.ClinicID,
+		CreatedBy:   createdBy,
+	}
+	subscription.BeforeCreate()
+	if err := s.repo.CreateSubscription(ctx, subscription); err != nil {
+		logger.Errorf("CreateSubscription fail, url: %s, error: %s", req.URL, err)
+		return nil, "", err
+	}
+	s.recorder.Record(ctx, &audit.Event{Action: audit.WebhookCreate, TargetType: audit.TargetWebhook, TargetID: subscription.ID, After: subscription})
+
+	return subscription, secret, nil
+}
+
+// UpdateSubscription replaces the url and events of the subscription, an
+// activated subscription starts again without failures
+func (s *WebhookService) UpdateSubscription(ctx context.Context, id string, req *dto.UpdateSubscriptionReq) (*model.Subscription, error) {
+	if err := s.validator.ValidateStruct(req); err != nil {
+		return nil, err
+	}
+	if err := validateEvents(req.Events); err != nil {
+		return nil, err
+	}
+
+	subscription, err := s.GetSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	before := *subscription
+
+	subscription.URL = req.URL
+	subscription.Description = req.Description
+	subscription.Events = req.Events
+	switch {
+	case req.Active && !subscription.Active:
+		subscription.Failures = 0
+		subscription.DisabledAt = nil
+		subscription.DisabledReason = ""
+	case !req.Active && subscription.Active:
+		now := time.Now()
+		subscription.DisabledAt = &now
+		subscription.DisabledReason = "disabled by an admin"
+	}
+	subscription.Active = req.Active
+	if err := s.repo.UpdateSubscription(ctx, subscription); err != nil {
+		logger.Errorf("UpdateSubscription fail, id: %s, error: %s", id, err)
+		return nil, err
+	}
+	s.recorder.Record(ctx, &audit.Event{Action: audit.WebhookUpdate, TargetType: audit.TargetWebhook, TargetID: id, Before: &before, After: subscription})
+
+	return subscription, nil
+}
+
+func (s *WebhookService) GetSubscription(ctx context.Context, id string) (*model.Subscription, error) {
+	subscription, err := s.repo.GetSubscription(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrSubscriptionNotFound
+	}
+	if err != nil {
+		logger.Errorf("GetSubscription fail, id: %s, error: %s", id, err)
+		return nil, err
+	}
+	return subscription, nil
+}
+
+func (s *WebhookService) ListSubscriptions(ctx context.Context) ([]*model.Subscription, error) {
+	return s.repo.ListSubscriptions(ctx)
+}
+
+// DeleteSubscription deletes the subscription with its delivery logs
+func (s *WebhookService) DeleteSubscription(ctx context.Context, id string) error {
+	deleted, err := s.repo.DeleteSubscription(ctx, id)
+	if err != nil {
+		logger.Errorf("DeleteSubscription fail, id: %s, error: %s", id, err)
+		return err
+	}
+	if !deleted {
+		return ErrSubscriptionNotFound
+	}
+	s.recorder.Record(ctx, &audit.Event{Action: audit.WebhookDelete, TargetType: audit.TargetWebhook, TargetID: id})
+
+	return nil
+}
+
+// RotateSecret replaces the secret of the subscription and returns the new
+// one, the pending deliveries are signed with it
+func (s *WebhookService) RotateSecret(ctx context.Context, id string) (string, error) {
+	subscription, err := s.GetSubscription(ctx, id)
+	if err != nil {
+		return "", err
+	}
+
+	secret, err := webhook.GenerateSecret()
+	if err != nil {
+		return "", err
+	}
+	subscription.Secret = secret
+	if err := s.repo.UpdateSubscription(ctx, subscription); err != nil {
+		logger.Errorf("RotateSecret fail, id: %s, error: %s", id, err)
+		return "", err
+	}
+	s.recorder.Record(ctx, &audit.Event{Action: audit.WebhookRotate, TargetType: audit.TargetWebhook, TargetID: id})
+
+	return secret, nil
+}
+
+// Ping sends a PingEvent to the subscription right away and returns its
+// delivery, retried like the others when it failed
+func (s *WebhookService) Ping(ctx context.Context, id string) (*model.Delivery, error) {
+	subscription, err := s.GetSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !subscription.Active {
+		return nil, ErrSubscriptionDisabled
+	}
+
+	event := events.New(ctx, PingEvent, subscription.ID, nil)
+	delivery, err := newDelivery(subscription, event)
+	if err != nil {
+		return nil, err
+	}
+	// left to this call rather than to the workers
+	delivery.NextAttemptAt = time.Now().Add(s.options.Lease)
+	if err := s.repo.CreateDeliveries(ctx, []*model.Delivery{delivery}); err != nil {
+		logger.Errorf("Ping.CreateDeliveries fail, id: %s, error: %s", id, err)
+		return nil, err
+	}
+
+	s.attempt(ctx, subscription, delivery)
+	return delivery, nil
+}
+
+func (s *WebhookService) ListDeliveries(ctx context.Context, subscriptionID string, req *dto.ListDeliveriesReq) ([]*model.Delivery, *paging.Pagination, error) {
+	if err := s.validator.ValidateStruct(req); err != nil {
+		return nil, nil, err
+	}
+	if _, err := s.GetSubscription(ctx, subscriptionID); err != nil {
+		return nil, nil, err
+	}
+
+	return s.repo.ListDeliveries(ctx, subscriptionID, req)
+}
+
+// Redeliver attempts a delivery again as soon as possible, with as many
+// attempts as a new one. Receivers see the same Webhook-Id.
+func (s *WebhookService) Redeliver(ctx context.Context, subscriptionID, id string) (*model.Delivery, error) {
+	subscription, err := s.GetSubscription(ctx, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	if !subscription.Active {
+		return nil, ErrSubscriptionDisabled
+	}
+
+	delivery, err := s.repo.GetDelivery(ctx, subscriptionID, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrDeliveryNotFound
+	}
+	if err != nil {
+		logger.Errorf("Redeliver.GetDelivery fail, id: %s, error: %s", id, err)
+		return nil, err
+	}
+
+	delivery.Status = model.DeliveryPending
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = time.Now()
+	delivery.Error = ""
+	if err := s.repo.SaveDelivery(ctx, delivery); err != nil {
+		logger.Errorf("Redeliver.SaveDelivery fail, id: %s, error: %s", id, err)
+		return nil, err
+	}
+
+	return delivery, nil
+}
+
+// Enqueue creates the deliveries of event to the subscriptions wanting it,
+// the subscriber of the event bus
+func (s *WebhookService) Enqueue(ctx context.Context, event *events.Event) error {
+	subscriptions, err := s.repo.ListActiveSubscriptions(ctx)
+	if err != nil {
+		logger.Errorf("Enqueue.ListActiveSubscriptions fail, event: %s, error: %s", event.ID, err)
+		return err
+	}
+
+	var deliveries []*model.Delivery
+	for _, subscription := range subscriptions {
+		if !subscription.Wants(event.Name, event.TenantID) {
+			continue
+		}
+		delivery, err := newDelivery(subscription, event)
+		if err != nil {
+			return err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	if err := s.repo.CreateDeliveries(ctx, deliveries); err != nil {
+		logger.Errorf("Enqueue.CreateDeliveries fail, event: %s, error: %s", event.ID, err)
+		return err
+	}
+	return nil
+}
+
+// ProcessDeliveries attempts the deliveries due now, it returns the number
+// of attempts made
+func (s *WebhookService) ProcessDeliveries(ctx context.Context) (int, error) {
+	count := 0
+	subscriptions := map[string]*model.Subscription{}
+	for {
+		now := time.Now()
+		deliveries, err := s.repo.ClaimDeliveries(ctx, now, now.Add(s.options.Lease), deliveryBatch)
+		if err != nil {
+			logger.Errorf("ProcessDeliveries.Claim fail, error: %s", err)
+			return count, err
+		}
+		if len(deliveries) == 0 {
+			return count, nil
+		}
+
+		for _, delivery := range deliveries {
+			subscription, ok := subscriptions[delivery.SubscriptionID]
+			if !ok {
+				subscription, err = s.repo.GetSubscription(ctx, delivery.SubscriptionID)
+				if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+					logger.Errorf("ProcessDeliveries.GetSubscription fail, id: %s, error: %s", delivery.SubscriptionID, err)
+					continue
+				}
+				subscriptions[delivery.SubscriptionID] = subscription
+			}
+			s.attempt(ctx, subscription, delivery)
+			count++
+		}
+	}
+}
+
+// RunDeliveries attempts the due deliveries every interval until ctx is
+// done
+func (s *WebhookService) RunDeliveries(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		_, _ = s.ProcessDeliveries(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// attempt sends delivery to subscription and saves the outcome, a failure
+// is retried after a backoff and counts against the subscription
+func (s *WebhookService) attempt(ctx context.Context, subscription *model.Subscription, delivery *model.Delivery) {
+	if subscription == nil || !subscription.Active {
+		delivery.Status = model.DeliveryFailed
+		delivery.Error = ErrSubscriptionDisabled.Error()
+		s.saveDelivery(ctx, delivery)
+		return
+	}
+
+	result, err := s.sender.Send(ctx, &webhook.Callback{
+		URL:    subscription.URL,
+		Secret: subscription.Secret,
+		ID:     delivery.ID,
+		Event:  delivery.EventName,
+		Body:   []byte(delivery.Payload),
+	})
+	delivery.Attempts++
+	delivery.ResponseStatus = result.Status
+	delivery.ResponseBody = result.Body
+	delivery.DurationMS = result.Duration.Milliseconds()
+	if err == nil {
+		now := time.Now()
+		delivery.Status = model.DeliverySucceeded
+		delivery.DeliveredAt = &now
+		delivery.Error = ""
+		s.saveDelivery(ctx, delivery)
+		if subscription.Failures > 0 {
+			if err := s.repo.ResetFailures(ctx, subscription.ID); err != nil {
+				logger.Errorf("ResetFailures fail, id: %s, error: %s", subscription.ID, err)
+			}
+			subscription.Failures = 0
+		}
+		return
+	}
+
+	delivery.Error = err.Error()
+	if delivery.Attempts >= s.options.MaxAttempts {
+		delivery.Status = model.DeliveryFailed
+	} else {
+		delivery.NextAttemptAt = time.Now().Add(webhook.Backoff(delivery.Attempts, s.options.Backoff, s.options.MaxBackoff))
+	}
+	s.saveDelivery(ctx, delivery)
+	subscription.Failures++
+
+	reason := fmt.Sprintf("%d failed attempts in a row, last: %s", s.options.DisableAfter, err)
+	disabled, err := s.repo.RecordFailure(ctx, subscription.ID, s.options.DisableAfter, reason)
+	if err != nil {
+		logger.Errorf("RecordFailure fail, id: %s, error: %s", subscription.ID, err)
+		return
+	}
+	if disabled {
+		subscription.Active = false
+		logger.Errorf("Webhook subscription disabled, id: %s, url: %s, reason: %s", subscription.ID, subscription.URL, reason)
+		if err := s.repo.FailPendingDeliveries(ctx, subscription.ID, ErrSubscriptionDisabled.Error()); err != nil {
+			logger.Errorf("FailPendingDeliveries fail, id: %s, error: %s", subscription.ID, err)
+		}
+		s.recorder.Record(ctx, &audit.Event{Action: audit.WebhookDisable, TargetType: audit.TargetWebhook, TargetID: subscription.ID, Reason: "delivery_failures"})
+	}
+}
+
+func (s *WebhookService) saveDelivery(ctx context.Context, delivery *model.Delivery) {
+	if err := s.repo.SaveDelivery(ctx, delivery); err != nil {
+		logger.Errorf("SaveDelivery fail, id: %s, error: %s", delivery.ID, err)
+	}
+}
+
+func newDelivery(subscription *model.Subscription, event *events.Event) (*model.Delivery, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+	delivery := &model.Delivery{
+		SubscriptionID: subscription.ID,
+		EventID:        event.ID,
+		EventName:      event.Name,
+		Payload:        string(payload),
+	}
+	delivery.BeforeCreate()
+	return delivery, nil
+}
+
+func validateEvents(names []string) error {
+	for _, name := range names {
+		if name == model.AllEvents {
+			continue
+		}
+		known := false
+		for _, n := range events.Names {
+			known = known || n == name
+		}
+		if !known {
+			return fmt.Errorf("%w: %s", ErrUnknownEvent, name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package apikey
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"strings"
+	"time"
+)
+
+const (
+	// Prefix starts every key so leaked keys are easy to spot in code and
+	// logs
+	Prefix = "dk_"
+
+	idLength     = 8
+	secretLength = 32
+	alphabet     = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+)
+
+var ErrInvalidKey = errors.New("invalid api key")
+
+// Principal is the machine client a key or client token authenticates
+type Principal struct {
+	KeyID     string    `json:"key_id"`
+	Name      string    `json:"name"`
+	Scopes    []string  `json:"scopes"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Generate returns a new key and its public prefix, dk_<id>_<secret>. Only
+// the prefix and the Hash of the key are stored.
+func Generate() (key string, prefix string, err error) {
+	id, err := randomString(idLength)
+	if err != nil {
+		return "", "", err
+	}
+	secret, err := randomString(secretLength)
+	if err != nil {
+		return "", "", err
+	}
+
+	prefix = Prefix + id
+	return prefix + "_" + secret, prefix, nil
+}
+
+// ParsePrefix returns the public prefix of key
+func ParsePrefix(key string) (string, error) {
+	if !strings.HasPrefix(key, Prefix) {
+		return "", ErrInvalidKey
+	}
+	parts := strings.Split(strings.TrimPrefix(key, Prefix), "_")
+	if len(parts) != 2 || len(parts[0]) != idLength || len(parts[1]) != secretLength {
+		return "", ErrInvalidKey
+	}
+	return Prefix + parts[0], nil
+}
+
+// Hash is the stored form of a key, keys are random enough for a fast hash
+func Hash(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomString(n int) (string, error) {
+	b := make([]byte, n)
+	max := big.NewInt(int64(len(alphabet)))
+	for i := range b {
+		v, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		b[i] = alphabet[v.Int64()]
+	}
+	return string(b), nil
+}
@@ -0,0 +1,34 @@
+package apikey
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	key, prefix, err := Generate()
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if !strings.HasPrefix(key, prefix+"_") {
+		t.Errorf("key %q does not start with prefix %q", key, prefix)
+	}
+
+	parsed, err := ParsePrefix(key)
+	if err != nil || parsed != prefix {
+		t.Errorf("ParsePrefix() = %q, %v, want %q", parsed, err, prefix)
+	}
+
+	other, _, _ := Generate()
+	if Hash(key) == Hash(other) {
+		t.Error("two keys have the same hash")
+	}
+}
+
+func TestParsePrefixRejectsMalformedKeys(t *testing.T) {
+	for _, key := range []string{"", "dk_", "dk_abc_def", "xx_ABCDEFGH_" + strings.Repeat("a", 32), "dk_ABCDEFGH_" + strings.Repeat("a", 31)} {
+		if _, err := ParsePrefix(key); err != ErrInvalidKey {
+			t.Errorf("ParsePrefix(%q) error = %v, want ErrInvalidKey", key, err)
+		}
+	}
+}
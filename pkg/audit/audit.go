@@ -0,0 +1,227 @@
+// Package audit describes the security and data-access events recorded in
+// the audit log, and who and where they come from.
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"main/pkg/session"
+	"main/pkg/tenant"
+)
+
+// RequestIDKey is the context key of the request id, a string so the gin
+// context resolves it from its keys
+const RequestIDKey = "requestId"
+
+// Outcomes of an event
+const (
+	Success = "success"
+	Failure = "failure"
+)
+
+// Actions recorded in the audit log
+const (
+	Login          = "auth.login"
+	LoginMFA       = "auth.mfa"
+	LoginOAuth     = "auth.oauth"
+	ClientToken    = "auth.client_token"
+	TokenRefresh   = "auth.refresh"
+	UserCreate     = "user.create"
+	UserUpdate     = "user.update"
+	UserDelete     = "user.delete"
+	UserRestore    = "user.restore"
+	RoleChange     = "user.role_change"
+	MFAReset       = "user.mfa_reset"
+	SessionsRevoke = "user.sessions_revoke"
+	DataExport     = "user.data_export"
+	ErasureRequest = "user.erasure_request"
+	ErasureCancel  = "user.erasure_cancel"
+	UserErase      = "user.erase"
+	APIKeyCreate   = "api_key.create"
+	APIKeyRevoke   = "api_key.revoke"
+	DoctorCreate   = "doctor.create"
+	DoctorUpdate   = "doctor.update"
+	DoctorDelete   = "doctor.delete"
+	DoctorRestore  = "doctor.restore"
+	DoctorReview   = "doctor.verification_review"
+	DoctorSuspend  = "doctor.license_suspend"
+	AddressCreate  = "address.create"
+	AddressUpdate  = "address.update"
+	AddressDelete  = "address.delete"
+	AddressRestore = "address.restore"
+	WebhookCreate  = "webhook.create"
+	WebhookUpdate  = "webhook.update"
+	WebhookDelete  = "webhook.delete"
+	WebhookRotate  = "webhook.secret_rotate"
+	WebhookDisable = "webhook.disable"
+	Broadcast      = "realtime.broadcast"
+)
+
+// Target types of the events
+const (
+	TargetUser      = "user"
+	TargetAPIKey    = "api_key"
+	TargetDoctor    = "doctor"
+	TargetAddress   = "address"
+	TargetWebhook   = "webhook"
+	TargetBroadcast = "broadcast"
+)
+
+// Event is a security or data-access event. Before and After are the target
+// before and after a change, marshalled to json with the secrets redacted.
+type Event struct {
+	Action     string
+	Outcome    string
+	TargetType string
+	TargetID   string
+	// Reason qualifies the outcome, such as wrong_password or mfa_required
+	Reason string
+	Before any
+	After  any
+}
+
+// Recorder appends events to the audit log. It must not fail the action
+// being recorded, errors are logged.
+//
+//go:generate mockery --name=Recorder
+type Recorder interface {
+	Record(ctx context.Context, event *Event)
+}
+
+type nop struct{}
+
+func (nop) Record(context.Context, *Event) {}
+
+// Nop discards the events, for the tools and tests that do not audit
+func Nop() Recorder {
+	return nop{}
+}
+
+// Actor is who did an event and where from
+type Actor struct {
+	UserID    string
+	Role      string
+	ClientID  string
+	TenantID  string
+	IP        string
+	UserAgent string
+	RequestID string
+}
+
+// ActorFromContext reads the actor from the keys set by the auth, session
+// client and request id middlewares
+func ActorFromContext(ctx context.Context) Actor {
+	str := func(key string) string {
+		v, _ := ctx.Value(key).(string)
+		return v
+	}
+	client := session.ClientFromContext(ctx)
+	tenantID, _ := tenant.FromContext(ctx)
+	return Actor{
+		UserID:    str("userId"),
+		Role:      str("role"),
+		ClientID:  str("clientId"),
+		TenantID:  tenantID,
+		IP:        client.IP,
+		UserAgent: client.UserAgent,
+		RequestID: str(RequestIDKey),
+	}
+}
+
+// WithRequestID sets the request id of ctx
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, RequestIDKey, id)
+}
+
+// Redacted are the json fields never written to the audit log, the secrets
+// and the contact details since the log outlives erased accounts
+var Redacted = []string{
+	"password",
+	"email",
+	"phone_number",
+	"verify_code_email",
+	"verify_code_phone_number",
+	"mfa_secret",
+	"access_token",
+	"refresh_token",
+	"key",
+	"secret",
+}
+
+// Ignored are the json fields left out of diffs, they change on every update
+var Ignored = []string{"updated_at"}
+
+// Snapshot marshals v to a json object with the Redacted fields removed, nil
+// when v is nil or not an object
+func Snapshot(v any) map[string]any {
+	if v == nil {
+		return nil
+	}
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Pointer && rv.IsNil() {
+		return nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var m map[string]any
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil
+	}
+	for key := range m {
+		if redacted(key) {
+			delete(m, key)
+		}
+	}
+	return m
+}
+
+// Change is a field changed by an event
+type Change struct {
+	Before any `json:"before"`
+	After  any `json:"after"`
+}
+
+// Diff returns the fields of the snapshots that differ, nil when nothing
+// changed
+func Diff(before, after map[string]any) map[string]Change {
+	diff := map[string]Change{}
+	for key, b := range before {
+		if ignored(key) {
+			continue
+		}
+		if a, ok := after[key]; !ok || !reflect.DeepEqual(a, b) {
+			diff[key] = Change{Before: b, After: after[key]}
+		}
+	}
+	for key, a := range after {
+		if _, ok := before[key]; !ok && !ignored(key) {
+			diff[key] = Change{After: a}
+		}
+	}
+	if len(diff) == 0 {
+		return nil
+	}
+	return diff
+}
+
+func redacted(key string) bool {
+	for _, field := range Redacted {
+		if strings.EqualFold(key, field) {
+			return true
+		}
+	}
+	return false
+}
+
+func ignored(key string) bool {
+	for _, field := range Ignored {
+		if key == field {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,67 @@
+package audit
+
+import (
+	"context"
+	"testing"
+
+	"main/pkg/session"
+	"main/pkg/tenant"
+)
+
+type account struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Password  string `json:"password"`
+	Role      string `json:"role"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+func TestSnapshotRedacts(t *testing.T) {
+	snapshot := Snapshot(&account{ID: "1", Name: "a", Password: "hash"})
+	if _, ok := snapshot["password"]; ok {
+		t.Errorf("password not redacted: %v", snapshot)
+	}
+	if snapshot["name"] != "a" {
+		t.Errorf("name missing: %v", snapshot)
+	}
+
+	var none *account
+	if Snapshot(none) != nil || Snapshot(nil) != nil {
+		t.Error("snapshot of nil not nil")
+	}
+}
+
+func TestDiff(t *testing.T) {
+	before := Snapshot(account{ID: "1", Name: "a", Password: "old", Role: "client", UpdatedAt: "t1"})
+	after := Snapshot(account{ID: "1", Name: "b", Password: "new", Role: "client", UpdatedAt: "t2"})
+
+	diff := Diff(before, after)
+	if len(diff) != 1 {
+		t.Fatalf("diff = %v, want the name only", diff)
+	}
+	if change := diff["name"]; change.Before != "a" || change.After != "b" {
+		t.Errorf("name change = %+v", change)
+	}
+
+	if diff := Diff(before, before); diff != nil {
+		t.Errorf("diff of unchanged = %v", diff)
+	}
+
+	added := Diff(map[string]any{}, map[string]any{"role": "doctor"})
+	if change, ok := added["role"]; !ok || change.Before != nil || change.After != "doctor" {
+		t.Errorf("added field = %v", added)
+	}
+}
+
+func TestActorFromContext(t *testing.T) {
+	ctx := context.WithValue(context.Background(), "userId", "u1")
+	ctx = context.WithValue(ctx, "role", "admin")
+	ctx = tenant.WithID(ctx, "clinic-1")
+	ctx = session.WithClient(ctx, session.Client{IP: "10.0.0.1", UserAgent: "curl/8"})
+	ctx = WithRequestID(ctx, "req-1")
+
+	want := Actor{UserID: "u1", Role: "admin", TenantID: "clinic-1", IP: "10.0.0.1", UserAgent: "curl/8", RequestID: "req-1"}
+	if got := ActorFromContext(ctx); got != want {
+		t.Errorf("actor = %+v, want %+v", got, want)
+	}
+}
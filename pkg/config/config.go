@@ -8,38 +8,161 @@ import (
 
 	"github.com/caarlos0/env"
 	"github.com/joho/godotenv"
+
+	"main/pkg/rbac"
 )
 
 const (
 	ProductionEnv = "production"
 
-	DatabaseTimeout    = 5 * time.Second
-	ProductCachingTime = 1 * time.Minute
-	AddressCachingTime = 1 * time.Minute
-	DoctorCachingTime  = 1 * time.Minute
-	UsersCachingTime   = 1 * time.Minute
+	DatabaseTimeout      = 5 * time.Second
+	ProductCachingTime   = 1 * time.Minute
+	AddressCachingTime   = 1 * time.Minute
+	DoctorCachingTime    = 1 * time.Minute
+	UsersCachingTime     = 1 * time.Minute
+	SpecialtyCachingTime = 10 * time.Minute
 )
 
 var AuthIgnoreMethods = []string{
 	"/user.UserService/Login",
 	"/user.UserService/Register",
+	"/user.UserService/VerifyMFA",
+	"/user.UserService/ClientToken",
+	"/specialty.SpecialtyService/ListSpecialties",
+	"/specialty.SpecialtyService/GetSpecialty",
+}
+
+// AuthMFAMethods also accept the MFA challenge token returned by Login, so
+// users of roles enforcing MFA can enroll before their first full login
+var AuthMFAMethods = []string{
+	"/user.UserService/EnrollMFA",
+	"/user.UserService/ConfirmMFA",
+}
+
+// AuthRefreshMethods only accept refresh tokens, which no other method accepts
+var AuthRefreshMethods = []string{
+	"/user.UserService/RefreshToken",
+}
+
+// AuthMethodPermissions are the methods machine clients can call, with the
+// permission both users and clients need
+var AuthMethodPermissions = map[string]string{
+	"/user.UserService/ListUsers":                      rbac.UsersRead,
+	"/user.UserService/DeleteUser":                     rbac.UsersWrite,
+	"/user.UserService/ResetUserMFA":                   rbac.UsersAdmin,
+	"/user.UserService/ListUserSessions":               rbac.UsersAdmin,
+	"/user.UserService/RevokeUserSession":              rbac.UsersAdmin,
+	"/user.UserService/RevokeUserSessions":             rbac.UsersAdmin,
+	"/user.UserService/CreateAPIKey":                   rbac.APIKeysManage,
+	"/user.UserService/ListAPIKeys":                    rbac.APIKeysManage,
+	"/user.UserService/RevokeAPIKey":                   rbac.APIKeysManage,
+	"/address.AddressService/GetAddressByID":           rbac.AddressesRead,
+	"/address.AddressService/ListAddresses":            rbac.AddressesRead,
+	"/address.AddressService/CreateAddress":            rbac.AddressesWrite,
+	"/address.AddressService/UpdateAddress":            rbac.AddressesWrite,
+	"/address.AddressService/DeleteAddress":            rbac.AddressesWrite,
+	"/doctor.DoctorService/GetDoctorByID":              rbac.DoctorsRead,
+	"/doctor.DoctorService/ListDoctors":                rbac.DoctorsRead,
+	"/doctor.DoctorService/CreateDoctor":               rbac.DoctorsWrite,
+	"/doctor.DoctorService/UpdateDoctor":               rbac.DoctorsWrite,
+	"/doctor.DoctorService/DeleteDoctor":               rbac.DoctorsWrite,
+	"/specialty.SpecialtyService/CreateSpecialty":      rbac.SpecialtiesWrite,
+	"/specialty.SpecialtyService/UpdateSpecialty":      rbac.SpecialtiesWrite,
+	"/specialty.SpecialtyService/DeleteSpecialty":      rbac.SpecialtiesWrite,
+	"/specialty.SpecialtyService/SetDoctorSpecialties": rbac.SpecialtiesWrite,
+	"/specialty.SpecialtyService/MigrateSpecialists":   rbac.SpecialtiesWrite,
+	"/realtime.RealtimeService/Broadcast":              rbac.BroadcastsSend,
 }
 
 type Schema struct {
-	Environment            string `env:"environment"`
-	HttpPort               int    `env:"http_port"`
-	GrpcPort               int    `env:"grpc_port"`
-	AuthSecret             string `env:"auth_secret"`
-	DatabaseURI            string `env:"database_uri"`
-	RedisURI               string `env:"redis_uri"`
-	RedisPassword          string `env:"redis_password"`
-	RedisDB                int    `env:"redis_db"`
-	GOOGLE_CLIENT_ID       string `env:"google_client_id"`
-	GOOGLE_CLIENT_SECRET   string `env:"google_client_secret"`
-	GOOGLE_REDIRECT_URL    string `env:"google_redirect_url"`
-	FACEBOOK_CLIENT_ID     string `env:"facebook_client_id"`
-	FACEBOOK_CLIENT_SECRET string `env:"facebook_client_secret"`
-	FACEBOOK_REDIRECT_URL  string `env:"facebook_redirect_url"`
+	Environment            string        `env:"environment"`
+	HttpPort               int           `env:"http_port"`
+	GrpcPort               int           `env:"grpc_port"`
+	AuthSecret             string        `env:"auth_secret"`
+	DatabaseURI            string        `env:"database_uri"`
+	RedisURI               string        `env:"redis_uri"`
+	RedisPassword          string        `env:"redis_password"`
+	RedisDB                int           `env:"redis_db"`
+	RedisMode              string        `env:"redis_mode"`
+	RedisUsername          string        `env:"redis_username"`
+	RedisMasterName        string        `env:"redis_master_name"`
+	RedisSentinelAddrs     []string      `env:"redis_sentinel_addrs" envSeparator:","`
+	RedisSentinelPassword  string        `env:"redis_sentinel_password"`
+	RedisClusterAddrs      []string      `env:"redis_cluster_addrs" envSeparator:","`
+	RedisTLS               bool          `env:"redis_tls"`
+	RedisTLSCAFile         string        `env:"redis_tls_ca_file"`
+	RedisTLSCertFile       string        `env:"redis_tls_cert_file"`
+	RedisTLSKeyFile        string        `env:"redis_tls_key_file"`
+	RedisTLSServerName     string        `env:"redis_tls_server_name"`
+	RedisTLSSkipVerify     bool          `env:"redis_tls_skip_verify"`
+	RedisPoolSize          int           `env:"redis_pool_size"`
+	RedisTimeout           time.Duration `env:"redis_timeout"`
+	RedisRetryInterval     time.Duration `env:"redis_retry_interval"`
+	RateLimitDefault       string        `env:"rate_limit_default" envDefault:"300/1m"`
+	RateLimitLogin         string        `env:"rate_limit_login" envDefault:"10/1m"`
+	RateLimitRegister      string        `env:"rate_limit_register" envDefault:"5/1m"`
+	RateLimitVerify        string        `env:"rate_limit_verify" envDefault:"5/1m"`
+	LockoutThreshold       int           `env:"lockout_threshold" envDefault:"5"`
+	LockoutDuration        time.Duration `env:"lockout_duration" envDefault:"1m"`
+	LockoutMaxDuration     time.Duration `env:"lockout_max_duration" envDefault:"1h"`
+	MFAIssuer              string        `env:"mfa_issuer" envDefault:"Doctoral"`
+	MFAEnforcedRoles       []string      `env:"mfa_enforced_roles" envSeparator:"," envDefault:"admin,doctor"`
+	GOOGLE_CLIENT_ID       string        `env:"google_client_id"`
+	GOOGLE_CLIENT_SECRET   string        `env:"google_client_secret"`
+	GOOGLE_REDIRECT_URL    string        `env:"google_redirect_url"`
+	FACEBOOK_CLIENT_ID     string        `env:"facebook_client_id"`
+	FACEBOOK_CLIENT_SECRET string        `env:"facebook_client_secret"`
+	FACEBOOK_REDIRECT_URL  string        `env:"facebook_redirect_url"`
+	OIDCName               string        `env:"oidc_name" envDefault:"oidc"`
+	OIDCIssuer             string        `env:"oidc_issuer"`
+	OIDCClientID           string        `env:"oidc_client_id"`
+	OIDCClientSecret       string        `env:"oidc_client_secret"`
+	OIDCRedirectURL        string        `env:"oidc_redirect_url"`
+	OIDCScopes             []string      `env:"oidc_scopes" envSeparator:"," envDefault:"openid,email,profile"`
+	LicenseExpiryInterval  time.Duration `env:"license_expiry_interval" envDefault:"1h"`
+	StorageDriver          string        `env:"storage_driver" envDefault:"local"`
+	StorageLocalPath       string        `env:"storage_local_path" envDefault:"./uploads"`
+	StorageS3Endpoint      string        `env:"storage_s3_endpoint"`
+	StorageS3Region        string        `env:"storage_s3_region"`
+	StorageS3Bucket        string        `env:"storage_s3_bucket"`
+	StorageS3AccessKey     string        `env:"storage_s3_access_key"`
+	StorageS3SecretKey     string        `env:"storage_s3_secret_key"`
+	StorageS3UseSSL        bool          `env:"storage_s3_use_ssl"`
+	StorageMaxSize         int64         `env:"storage_max_size" envDefault:"10485760"`
+	StorageURLBase         string        `env:"storage_url_base" envDefault:"http://localhost:8888/api/v1/files"`
+	StorageURLTTL          time.Duration `env:"storage_url_ttl" envDefault:"15m"`
+	StorageImageURLBase    string        `env:"storage_image_url_base" envDefault:"http://localhost:8888/api/v1/images"`
+	ImageWorkers           int           `env:"image_workers" envDefault:"2"`
+	ImageQueueSize         int           `env:"image_queue_size" envDefault:"100"`
+	PrescriptionSigningKey string        `env:"prescription_signing_key"`
+	PrescriptionVerifyURL  string        `env:"prescription_verify_url" envDefault:"http://localhost:8888/api/v1/prescriptions/verify"`
+	EncryptionKeys         []string      `env:"encryption_keys" envSeparator:","`
+	EncryptionKeyFile      string        `env:"encryption_key_file"`
+	EncryptionActiveKey    string        `env:"encryption_active_key"`
+	EncryptionIndexKey     string        `env:"encryption_index_key"`
+	DataExportTTL          time.Duration `env:"data_export_ttl" envDefault:"168h"`
+	ErasureGracePeriod     time.Duration `env:"erasure_grace_period" envDefault:"720h"`
+	DataRequestInterval    time.Duration `env:"data_request_interval" envDefault:"1m"`
+	DeletedRetention       time.Duration `env:"deleted_retention" envDefault:"2160h"`
+	PurgeInterval          time.Duration `env:"purge_interval" envDefault:"24h"`
+	IdempotencyStore       string        `env:"idempotency_store" envDefault:"redis"`
+	IdempotencyWindow      time.Duration `env:"idempotency_window" envDefault:"24h"`
+	EventsTransport        string        `env:"events_transport" envDefault:"memory"`
+	EventsMaxDeliveries    int           `env:"events_max_deliveries" envDefault:"5"`
+	EventsRetryAttempts    int           `env:"events_retry_attempts" envDefault:"3"`
+	EventsRetryBackoff     time.Duration `env:"events_retry_backoff" envDefault:"200ms"`
+	EventsStreamMaxLen     int64         `env:"events_stream_max_len" envDefault:"100000"`
+	EventsNATSURL          string        `env:"events_nats_url" envDefault:"nats://localhost:4222"`
+	WebhookTimeout         time.Duration `env:"webhook_timeout" envDefault:"10s"`
+	WebhookMaxAttempts     int           `env:"webhook_max_attempts" envDefault:"8"`
+	WebhookBackoff         time.Duration `env:"webhook_backoff" envDefault:"30s"`
+	WebhookMaxBackoff      time.Duration `env:"webhook_max_backoff" envDefault:"6h"`
+	WebhookDisableAfter    int           `env:"webhook_disable_after" envDefault:"20"`
+	WebhookInterval        time.Duration `env:"webhook_interval" envDefault:"5s"`
+	WebhookLogRetention    time.Duration `env:"webhook_log_retention" envDefault:"720h"`
+	RealtimeBuffer         int           `env:"realtime_buffer" envDefault:"32"`
+	RealtimeHeartbeat      time.Duration `env:"realtime_heartbeat" envDefault:"25s"`
+	NotificationTTL        time.Duration `env:"notification_ttl" envDefault:"2160h"`
 }
 
 var (
@@ -51,6 +51,12 @@ func NewDatabase(uri string) (*Database, error) {
 	if err != nil {
 		return nil, err
 	}
+	if err := RegisterTenantScope(database); err != nil {
+		return nil, err
+	}
+	if err := RegisterVersioning(database); err != nil {
+		return nil, err
+	}
 
 	// Set up connection pool
 	sqlDB, err := database.DB()
@@ -65,6 +71,12 @@ func NewDatabase(uri string) (*Database, error) {
 	}, nil
 }
 
+// Wrap returns the Database of db, opened by the caller with the callbacks it
+// needs registered
+func Wrap(db *gorm.DB) *Database {
+	return &Database{db: db}
+}
+
 func (d *Database) AutoMigrate(models ...any) error {
 	return d.db.AutoMigrate(models...)
 }
@@ -96,28 +108,28 @@ func (d *Database) Create(ctx context.Context, doc any) error {
 	ctx, cancel := context.WithTimeout(ctx, DatabaseTimeout)
 	defer cancel()
 
-	return d.db.Create(doc).Error
+	return d.db.WithContext(ctx).Create(doc).Error
 }
 
 func (d *Database) CreateInBatches(ctx context.Context, docs any, batchSize int) error {
 	ctx, cancel := context.WithTimeout(ctx, DatabaseTimeout)
 	defer cancel()
 
-	return d.db.CreateInBatches(docs, batchSize).Error
+	return d.db.WithContext(ctx).CreateInBatches(docs, batchSize).Error
 }
 
 func (d *Database) Update(ctx context.Context, doc any) error {
 	ctx, cancel := context.WithTimeout(ctx, DatabaseTimeout)
 	defer cancel()
 
-	return d.db.Save(doc).Error
+	return SaveVersion(d.db.WithContext(ctx), doc)
 }
 
 func (d *Database) Delete(ctx context.Context, value any, opts ...FindOption) error {
 	ctx, cancel := context.WithTimeout(ctx, DatabaseTimeout)
 	defer cancel()
 
-	query := d.applyOptions(opts...)
+	query := d.applyOptions(ctx, opts...)
 	return query.Delete(value).Error
 }
 
@@ -125,7 +137,7 @@ func (d *Database) FindById(ctx context.Context, id string, result any) error {
 	ctx, cancel := context.WithTimeout(ctx, DatabaseTimeout)
 	defer cancel()
 
-	if err := d.db.Where("id = ? ", id).First(result).Error; err != nil {
+	if err := d.db.WithContext(ctx).Where("id = ? ", id).First(result).Error; err != nil {
 		return err
 	}
 
@@ -136,7 +148,7 @@ func (d *Database) FindOne(ctx context.Context, result any, opts ...FindOption)
 	ctx, cancel := context.WithTimeout(ctx, DatabaseTimeout)
 	defer cancel()
 
-	query := d.applyOptions(opts...)
+	query := d.applyOptions(ctx, opts...)
 	if err := query.First(result).Error; err != nil {
 		return err
 	}
@@ -148,7 +160,7 @@ func (d *Database) Find(ctx context.Context, result any, opts ...FindOption) err
 	ctx, cancel := context.WithTimeout(ctx, DatabaseTimeout)
 	defer cancel()
 
-	query := d.applyOptions(opts...)
+	query := d.applyOptions(ctx, opts...)
 	if err := query.Find(result).Error; err != nil {
 		return err
 	}
@@ -160,7 +172,7 @@ func (d *Database) Count(ctx context.Context, model any, total *int64, opts ...F
 	ctx, cancel := context.WithTimeout(ctx, DatabaseTimeout)
 	defer cancel()
 
-	query := d.applyOptions(opts...)
+	query := d.applyOptions(ctx, opts...)
 	if err := query.Model(model).Count(total).Error; err != nil {
 		return err
 	}
@@ -172,11 +184,15 @@ func (d *Database) GetDB() *gorm.DB {
 	return d.db
 }
 
-func (d *Database) applyOptions(opts ...FindOption) *gorm.DB {
-	query := d.db
+func (d *Database) applyOptions(ctx context.Context, opts ...FindOption) *gorm.DB {
+	query := d.db.WithContext(ctx)
 
 	opt := getOption(opts...)
 
+	if opt.deleted {
+		query = query.Unscoped()
+	}
+
 	if len(opt.preloads) != 0 {
 		for _, preload := range opt.preloads {
 			query = query.Preload(preload)
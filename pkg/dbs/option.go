@@ -10,6 +10,7 @@ type option struct {
 	offset   int
 	limit    int
 	preloads []string
+	deleted  bool
 }
 
 type optionFn func(*option)
@@ -48,6 +49,13 @@ func WithPreload(preloads []string) FindOption {
 	})
 }
 
+// WithDeleted includes the soft-deleted rows, they are left out by default
+func WithDeleted() FindOption {
+	return optionFn(func(opt *option) {
+		opt.deleted = true
+	})
+}
+
 func getOption(opts ...FindOption) option {
 	opt := option{
 		query:  []Query{},
@@ -0,0 +1,59 @@
+package dbs
+
+import (
+	"context"
+	"time"
+
+	"github.com/quangdangfit/gocommon/logger"
+	"gorm.io/gorm"
+)
+
+// Models with a gorm.DeletedAt field are soft-deleted: Delete sets deleted_at
+// and every query leaves the deleted rows out, unless WithDeleted or
+// Unscoped is used. They are hard deleted by their Purger once the retention
+// is over.
+
+// Restore clears the deletion of the row id of model, false when it is not
+// deleted
+func Restore(db *gorm.DB, model any, id string) (bool, error) {
+	result := db.Unscoped().Model(model).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Update("deleted_at", nil)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// Purge hard deletes the rows of model deleted before before
+func Purge(db *gorm.DB, model any, before time.Time) (int64, error) {
+	result := db.Unscoped().Where("deleted_at < ?", before).Delete(model)
+	return result.RowsAffected, result.Error
+}
+
+// Purger hard deletes the rows of a module deleted before a time, and
+// returns how many were purged
+type Purger interface {
+	Purge(ctx context.Context, before time.Time) (int64, error)
+}
+
+// RunPurge purges the rows deleted for longer than retention every interval
+// until ctx is done
+func RunPurge(ctx context.Context, interval, retention time.Duration, purgers ...Purger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		before := time.Now().Add(-retention)
+		for _, purger := range purgers {
+			if _, err := purger.Purge(ctx, before); err != nil {
+				logger.Errorf("Purge fail, purger: %T, error: %s", purger, err)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package dbs
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type deletable struct {
+	ID        string
+	Name      string
+	DeletedAt gorm.DeletedAt
+}
+
+func TestSoftDelete(t *testing.T) {
+	db := &Database{db: dryRun(t)}
+	ctx := context.Background()
+
+	var rows []deletable
+	sql := db.applyOptions(ctx).Find(&rows).Statement.SQL.String()
+	if !strings.Contains(sql, `"deletables"."deleted_at" IS NULL`) {
+		t.Errorf("deleted rows not left out: %s", sql)
+	}
+
+	sql = db.applyOptions(ctx, WithDeleted()).Find(&rows).Statement.SQL.String()
+	if strings.Contains(sql, "deleted_at") {
+		t.Errorf("deleted rows left out: %s", sql)
+	}
+
+	sql = db.db.Where("id = ?", "1").Delete(&deletable{}).Statement.SQL.String()
+	if !strings.HasPrefix(sql, "UPDATE") {
+		t.Errorf("delete not soft: %s", sql)
+	}
+}
+
+func TestRestoreAndPurge(t *testing.T) {
+	db := dryRun(t)
+	var sql string
+	capture := func(db *gorm.DB) { sql = db.Statement.SQL.String() }
+	if err := db.Callback().Update().After("gorm:update").Register("test:capture", capture); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Callback().Delete().After("gorm:delete").Register("test:capture", capture); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Restore(db, &deletable{}, "1"); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(sql, `SET "deleted_at"=$1`) || !strings.Contains(sql, "deleted_at IS NOT NULL") {
+		t.Errorf("restore = %s", sql)
+	}
+
+	if _, err := Purge(db, &deletable{}, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(sql, "DELETE") || !strings.Contains(sql, "deleted_at < $1") {
+		t.Errorf("purge = %s", sql)
+	}
+}
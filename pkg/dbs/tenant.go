@@ -0,0 +1,98 @@
+package dbs
+
+import (
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+
+	"main/pkg/tenant"
+)
+
+// TenantField is the field of the models owned by a clinic
+const TenantField = "TenantID"
+
+// RegisterTenantScope scopes the queries, updates and deletes of the models
+// with a TenantField to the clinic of their context, and assigns it to the
+// created rows. Raw queries are not scoped.
+func RegisterTenantScope(db *gorm.DB) error {
+	callbacks := db.Callback()
+	if err := callbacks.Query().Before("gorm:query").Register("tenant:query", scopeTenant); err != nil {
+		return err
+	}
+	if err := callbacks.Row().Before("gorm:row").Register("tenant:row", scopeTenant); err != nil {
+		return err
+	}
+	if err := callbacks.Update().Before("gorm:update").Register("tenant:update", scopeTenant); err != nil {
+		return err
+	}
+	if err := callbacks.Delete().Before("gorm:delete").Register("tenant:delete", scopeTenant); err != nil {
+		return err
+	}
+	return callbacks.Create().Before("gorm:create").Register("tenant:create", assignTenant)
+}
+
+func tenantField(db *gorm.DB) (*schema.Field, string, bool) {
+	if db.Error != nil || db.Statement.Schema == nil {
+		return nil, "", false
+	}
+	field := db.Statement.Schema.LookUpField(TenantField)
+	if field == nil {
+		return nil, "", false
+	}
+	id, ok := tenant.FromContext(db.Statement.Context)
+	return field, id, ok
+}
+
+func scopeTenant(db *gorm.DB) {
+	field, id, ok := tenantField(db)
+	if !ok {
+		return
+	}
+	db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{
+		clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName}, Value: id},
+	}})
+}
+
+// assignTenant sets the clinic of the created rows and rejects the rows of
+// another clinic. Upserts only update the rows of the clinic.
+func assignTenant(db *gorm.DB) {
+	field, id, ok := tenantField(db)
+	if !ok {
+		return
+	}
+
+	rv := db.Statement.ReflectValue
+	set := func(i int) {
+		target := rv
+		if i >= 0 {
+			target = rv.Index(i)
+		}
+		current, zero := field.ValueOf(db.Statement.Context, target)
+		if zero {
+			_ = db.AddError(field.Set(db.Statement.Context, target, id))
+		} else if current != id {
+			_ = db.AddError(tenant.ErrCrossTenant)
+		}
+	}
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			set(i)
+		}
+	case reflect.Struct:
+		set(-1)
+	}
+
+	if c, ok := db.Statement.Clauses["ON CONFLICT"]; ok {
+		if onConflict, ok := c.Expression.(clause.OnConflict); ok && !onConflict.DoNothing {
+			onConflict.Where.Exprs = append(onConflict.Where.Exprs, clause.Eq{
+				Column: clause.Column{Table: db.Statement.Table, Name: field.DBName},
+				Value:  id,
+			})
+			c.Expression = onConflict
+			db.Statement.Clauses["ON CONFLICT"] = c
+		}
+	}
+}
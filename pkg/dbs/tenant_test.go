@@ -0,0 +1,121 @@
+package dbs
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"main/pkg/tenant"
+)
+
+type owned struct {
+	ID       string
+	Name     string
+	TenantID string
+}
+
+type shared struct {
+	ID   string
+	Name string
+}
+
+func dryRun(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{
+		DryRun:                 true,
+		SkipDefaultTransaction: true,
+		DisableAutomaticPing:   true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := RegisterTenantScope(db); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestTenantScope(t *testing.T) {
+	db := dryRun(t)
+	ctx := tenant.WithID(context.Background(), "clinic-1")
+
+	var rows []owned
+	sql := db.WithContext(ctx).Where("name = ?", "a").Find(&rows).Statement.SQL.String()
+	if !strings.Contains(sql, `"owneds"."tenant_id" = $2`) {
+		t.Errorf("query not scoped: %s", sql)
+	}
+
+	sql = db.WithContext(ctx).Model(&owned{}).Where("id = ?", "1").Update("name", "b").Statement.SQL.String()
+	if !strings.Contains(sql, `"owneds"."tenant_id" =`) {
+		t.Errorf("update not scoped: %s", sql)
+	}
+
+	sql = db.WithContext(ctx).Where("id = ?", "1").Delete(&owned{}).Statement.SQL.String()
+	if !strings.Contains(sql, `"owneds"."tenant_id" =`) {
+		t.Errorf("delete not scoped: %s", sql)
+	}
+
+	var total int64
+	sql = db.WithContext(ctx).Model(&owned{}).Count(&total).Statement.SQL.String()
+	if !strings.Contains(sql, `"owneds"."tenant_id" =`) {
+		t.Errorf("count not scoped: %s", sql)
+	}
+
+	var other []shared
+	sql = db.WithContext(ctx).Find(&other).Statement.SQL.String()
+	if strings.Contains(sql, "tenant_id") {
+		t.Errorf("model without tenant scoped: %s", sql)
+	}
+
+	sql = db.WithContext(context.Background()).Find(&rows).Statement.SQL.String()
+	if strings.Contains(sql, "tenant_id") {
+		t.Errorf("unbound context scoped: %s", sql)
+	}
+
+	sql = db.WithContext(tenant.Global(ctx)).Find(&rows).Statement.SQL.String()
+	if strings.Contains(sql, "tenant_id") {
+		t.Errorf("global context scoped: %s", sql)
+	}
+}
+
+func TestTenantAssign(t *testing.T) {
+	db := dryRun(t)
+	ctx := tenant.WithID(context.Background(), "clinic-1")
+
+	row := owned{ID: "1"}
+	if err := db.WithContext(ctx).Create(&row).Error; err != nil {
+		t.Fatal(err)
+	}
+	if row.TenantID != "clinic-1" {
+		t.Errorf("tenant not assigned: %q", row.TenantID)
+	}
+
+	rows := []*owned{{ID: "2"}, {ID: "3", TenantID: "clinic-1"}}
+	if err := db.WithContext(ctx).Create(&rows).Error; err != nil {
+		t.Fatal(err)
+	}
+	if rows[0].TenantID != "clinic-1" {
+		t.Errorf("tenant not assigned in batch: %q", rows[0].TenantID)
+	}
+
+	foreign := owned{ID: "4", TenantID: "clinic-2"}
+	if err := db.WithContext(ctx).Create(&foreign).Error; !errors.Is(err, tenant.ErrCrossTenant) {
+		t.Errorf("row of another clinic created: %v", err)
+	}
+
+	upsert := owned{ID: "5"}
+	sql := db.WithContext(ctx).Clauses(clause.OnConflict{UpdateAll: true}).Create(&upsert).Statement.SQL.String()
+	if !strings.Contains(sql, `DO UPDATE SET`) || !strings.Contains(sql, `WHERE "owneds"."tenant_id" =`) {
+		t.Errorf("upsert not scoped: %s", sql)
+	}
+
+	unbound := owned{ID: "6", TenantID: "clinic-2"}
+	if err := db.WithContext(context.Background()).Create(&unbound).Error; err != nil || unbound.TenantID != "clinic-2" {
+		t.Errorf("unbound create changed: %v %q", err, unbound.TenantID)
+	}
+}
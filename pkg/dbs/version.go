@@ -0,0 +1,164 @@
+package dbs
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// VersionField is the field of the models updated with optimistic locking
+const VersionField = "Version"
+
+// AnyVersion saves or deletes the row whatever its version, as long as it
+// exists, it is the version of If-Match: *
+const AnyVersion int64 = -1
+
+// ErrStaleVersion is returned when the row was changed or deleted since its
+// version was read
+var ErrStaleVersion = errors.New("record was changed since it was read")
+
+// ErrMissingPrimaryKey is returned when the doc saved or deleted with its
+// version has no primary key, which would write every row at that version
+var ErrMissingPrimaryKey = errors.New("record has no primary key")
+
+// Models with a VersionField start at version 1 and are saved by SaveVersion,
+// which only updates the row still at the version of the saved value and
+// bumps it. Readers send the version back, as the If-Match ETag over HTTP or
+// the version field over gRPC, so that two writers do not overwrite each
+// other. The column updates of the other writes bump it too, see
+// RegisterVersioning.
+
+// RegisterVersioning bumps the version of the rows changed by Update and
+// Updates with a map, so that their ETag changes. Saves of the whole model
+// go through SaveVersion.
+func RegisterVersioning(db *gorm.DB) error {
+	return db.Callback().Update().Before("gorm:update").Register("version:update", bumpVersion)
+}
+
+func bumpVersion(db *gorm.DB) {
+	if db.Error != nil || db.Statement.Schema == nil {
+		return
+	}
+	field := db.Statement.Schema.LookUpField(VersionField)
+	if field == nil {
+		return
+	}
+	values, ok := db.Statement.Dest.(map[string]interface{})
+	if !ok {
+		return
+	}
+	if _, ok := values[field.DBName]; ok {
+		return
+	}
+
+	// the map of the caller is left untouched
+	bumped := make(map[string]interface{}, len(values)+1)
+	for k, v := range values {
+		bumped[k] = v
+	}
+	bumped[field.DBName] = gorm.Expr(field.DBName + " + 1")
+	db.Statement.Dest = bumped
+}
+
+// versionField parses the model of doc, and returns its VersionField or nil,
+// or ErrMissingPrimaryKey when a primary key of doc is zero
+func versionField(db *gorm.DB, doc any) (*schema.Field, error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(doc); err != nil {
+		return nil, err
+	}
+
+	value := reflect.ValueOf(doc)
+	if len(stmt.Schema.PrimaryFields) == 0 {
+		return nil, fmt.Errorf("%s: %w", stmt.Schema.Table, ErrMissingPrimaryKey)
+	}
+	for _, pk := range stmt.Schema.PrimaryFields {
+		if _, zero := pk.ValueOf(db.Statement.Context, value); zero {
+			return nil, fmt.Errorf("%s: %w", stmt.Schema.Table, ErrMissingPrimaryKey)
+		}
+	}
+	return stmt.Schema.LookUpField(VersionField), nil
+}
+
+// SaveVersion saves doc like Save when its model has no VersionField.
+// Otherwise it updates all the columns of the row of doc at the version of
+// doc, and bumps the version of both, or returns ErrStaleVersion. Docs at
+// AnyVersion are saved at the current version of their row. Docs without a
+// primary key are not saved, ErrMissingPrimaryKey.
+func SaveVersion(db *gorm.DB, doc any) error {
+	field, err := versionField(db, doc)
+	if err != nil {
+		return err
+	}
+	if field == nil {
+		return db.Save(doc).Error
+	}
+
+	ctx := db.Statement.Context
+	value := reflect.ValueOf(doc)
+	current, _ := field.ValueOf(ctx, value)
+	version, _ := current.(int64)
+	if version == AnyVersion {
+		if version, err = currentVersion(db, doc, field); err != nil {
+			return err
+		}
+	}
+	if err := field.Set(ctx, value, version+1); err != nil {
+		return err
+	}
+
+	result := db.Model(doc).Where(field.DBName+" = ?", version).Select("*").Updates(doc)
+	if result.Error == nil && result.RowsAffected == 0 {
+		result.Error = ErrStaleVersion
+	}
+	if result.Error != nil {
+		_ = field.Set(ctx, value, current)
+		return result.Error
+	}
+	return nil
+}
+
+// currentVersion reads the version of the row of doc, ErrStaleVersion when
+// there is none
+func currentVersion(db *gorm.DB, doc any, field *schema.Field) (int64, error) {
+	pk := field.Schema.PrioritizedPrimaryField
+	if pk == nil {
+		return 0, fmt.Errorf("%s: versions need a single primary key", field.Schema.Table)
+	}
+	id, _ := pk.ValueOf(db.Statement.Context, reflect.ValueOf(doc))
+
+	var versions []int64
+	if err := db.Model(doc).Where(pk.DBName+" = ?", id).Limit(1).Pluck(field.DBName, &versions).Error; err != nil {
+		return 0, err
+	}
+	if len(versions) == 0 {
+		return 0, ErrStaleVersion
+	}
+	return versions[0], nil
+}
+
+// DeleteVersion deletes the row of doc if it is still at version, or at
+// AnyVersion if it exists, or returns ErrStaleVersion. Docs without a primary
+// key are not deleted, ErrMissingPrimaryKey.
+func DeleteVersion(db *gorm.DB, doc any, version int64) error {
+	field, err := versionField(db, doc)
+	if err != nil {
+		return err
+	}
+	if field == nil {
+		return db.Delete(doc).Error
+	}
+
+	query := db
+	if version != AnyVersion {
+		query = query.Where(field.DBName+" = ?", version)
+	}
+	result := query.Delete(doc)
+	if result.Error == nil && result.RowsAffected == 0 {
+		return ErrStaleVersion
+	}
+	return result.Error
+}
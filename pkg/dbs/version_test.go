@@ -0,0 +1,105 @@
+package dbs
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+type versioned struct {
+	ID      string
+	Name    string
+	Version int64
+}
+
+func TestSaveAndDeleteVersion(t *testing.T) {
+	db := dryRun(t)
+	var sql string
+	capture := func(db *gorm.DB) { sql = db.Statement.SQL.String() }
+	if err := db.Callback().Update().After("gorm:update").Register("test:capture", capture); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Callback().Delete().After("gorm:delete").Register("test:capture", capture); err != nil {
+		t.Fatal(err)
+	}
+
+	// dry runs affect no rows, so they all look stale
+	doc := versioned{ID: "1", Name: "name", Version: 3}
+	if err := SaveVersion(db, &doc); !errors.Is(err, ErrStaleVersion) {
+		t.Errorf("save error = %v", err)
+	}
+	if !strings.Contains(sql, `"version"=$`) || !strings.Contains(sql, "version = $") {
+		t.Errorf("save = %s", sql)
+	}
+	if doc.Version != 3 {
+		t.Errorf("stale save bumped the version to %d", doc.Version)
+	}
+
+	if err := DeleteVersion(db, &doc, 3); !errors.Is(err, ErrStaleVersion) {
+		t.Errorf("delete error = %v", err)
+	}
+	if !strings.HasPrefix(sql, "DELETE") || !strings.Contains(sql, "version = $") {
+		t.Errorf("delete = %s", sql)
+	}
+
+	// If-Match: * matches the current version, none in a dry run
+	var query string
+	if err := db.Callback().Query().After("gorm:query").Register("test:capture", func(db *gorm.DB) { query = db.Statement.SQL.String() }); err != nil {
+		t.Fatal(err)
+	}
+	doc.Version = AnyVersion
+	if err := SaveVersion(db, &doc); !errors.Is(err, ErrStaleVersion) {
+		t.Errorf("save of any version error = %v", err)
+	}
+	if !strings.Contains(query, `SELECT "version"`) || !strings.Contains(query, "id = $") || doc.Version != AnyVersion {
+		t.Errorf("save of any version = %s, version %d", query, doc.Version)
+	}
+	if err := DeleteVersion(db, &doc, AnyVersion); !errors.Is(err, ErrStaleVersion) {
+		t.Errorf("delete of any version error = %v", err)
+	}
+	if strings.Contains(sql, "version = $") {
+		t.Errorf("delete of any version = %s", sql)
+	}
+
+	// without a primary key every row at the version would be written
+	sql = ""
+	if err := SaveVersion(db, &versioned{Name: "name", Version: 3}); !errors.Is(err, ErrMissingPrimaryKey) {
+		t.Errorf("save without id error = %v", err)
+	}
+	if err := DeleteVersion(db, &versioned{}, 3); !errors.Is(err, ErrMissingPrimaryKey) {
+		t.Errorf("delete without id error = %v", err)
+	}
+	if sql != "" {
+		t.Errorf("write without id = %s", sql)
+	}
+
+	if err := SaveVersion(db, &deletable{ID: "1"}); err != nil {
+		t.Errorf("unversioned save error = %v", err)
+	}
+	if strings.Contains(sql, "version") {
+		t.Errorf("unversioned save = %s", sql)
+	}
+}
+
+func TestRegisterVersioning(t *testing.T) {
+	db := dryRun(t)
+	if err := RegisterVersioning(db); err != nil {
+		t.Fatal(err)
+	}
+
+	values := map[string]interface{}{"name": "name"}
+	sql := db.Model(&versioned{}).Where("id = ?", "1").Updates(values).Statement.SQL.String()
+	if !strings.Contains(sql, `"version"=version + 1`) {
+		t.Errorf("updates = %s", sql)
+	}
+	if len(values) != 1 {
+		t.Errorf("values of the caller changed: %v", values)
+	}
+
+	sql = db.Model(&deletable{}).Where("id = ?", "1").Update("name", "name").Statement.SQL.String()
+	if strings.Contains(sql, "version") {
+		t.Errorf("unversioned update = %s", sql)
+	}
+}
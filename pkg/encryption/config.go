@@ -0,0 +1,103 @@
+package encryption
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+
+	"main/pkg/config"
+)
+
+// DerivedKeyID is the id of the master key derived from the auth secret when
+// no key is configured
+const DerivedKeyID = "derived"
+
+// FromConfig returns the keyring of the configured master keys, "id:base64"
+// entries of the config and of the key file. Without keys, the master and
+// index keys are derived from the auth secret, which is fine for development
+// only.
+func FromConfig(cfg *config.Schema) (*Keyring, error) {
+	entries := append([]string{}, cfg.EncryptionKeys...)
+	if cfg.EncryptionKeyFile != "" {
+		lines, err := readKeyFile(cfg.EncryptionKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, lines...)
+	}
+
+	keys := map[string][]byte{}
+	for _, entry := range entries {
+		id, encoded, ok := strings.Cut(strings.TrimSpace(entry), ":")
+		if !ok {
+			return nil, ErrInvalidKey
+		}
+		key, err := decodeKey(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("encryption key %s: %w", id, err)
+		}
+		keys[id] = key
+	}
+
+	active := cfg.EncryptionActiveKey
+	if len(keys) == 0 {
+		keys[DerivedKeyID] = derive("encryption", cfg.AuthSecret)
+		active = DerivedKeyID
+	}
+	if active == "" && len(keys) == 1 {
+		for id := range keys {
+			active = id
+		}
+	}
+
+	indexKey := derive("blind-index", cfg.AuthSecret)
+	if cfg.EncryptionIndexKey != "" {
+		key, err := decodeKey(cfg.EncryptionIndexKey)
+		if err != nil {
+			return nil, fmt.Errorf("encryption index key: %w", err)
+		}
+		indexKey = key
+	}
+
+	return NewKeyring(keys, active, indexKey)
+}
+
+// readKeyFile reads the keys of path, one per line, skipping the empty lines
+// and comments
+func readKeyFile(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			lines = append(lines, line)
+		}
+	}
+	return lines, scanner.Err()
+}
+
+func decodeKey(encoded string) ([]byte, error) {
+	encoded = strings.TrimSpace(encoded)
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		key, err = base64.RawStdEncoding.DecodeString(encoded)
+	}
+	if err != nil || len(key) != KeySize {
+		return nil, ErrInvalidKey
+	}
+	return key, nil
+}
+
+func derive(purpose, secret string) []byte {
+	sum := sha256.Sum256([]byte(purpose + "\n" + secret))
+	return sum[:]
+}
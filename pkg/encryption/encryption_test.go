@@ -0,0 +1,161 @@
+package encryption
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+
+	"main/pkg/config"
+)
+
+type patient struct {
+	ID         string
+	Email      string `gorm:"type:text;serializer:encrypted"`
+	EmailIndex string `blindindex:"Email"`
+	Code       int    `gorm:"type:text;serializer:encrypted"`
+}
+
+func key(b byte) []byte {
+	return bytes.Repeat([]byte{b}, KeySize)
+}
+
+func keyring(t *testing.T, active string) *Keyring {
+	t.Helper()
+	k, err := NewKeyring(map[string][]byte{"old": key(1), "new": key(2)}, active, key(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return k
+}
+
+func TestEncryptRotation(t *testing.T) {
+	old := keyring(t, "old")
+	value, err := old.Encrypt([]byte("+33 6 12 34 56 78"), []byte("users.phone_number"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id, ok := KeyID(value); !ok || id != "old" {
+		t.Fatalf("KeyID = %s, %v, want old", id, ok)
+	}
+
+	// the keyring of the rotated key still opens the older values
+	plaintext, err := keyring(t, "new").Decrypt(value, []byte("users.phone_number"))
+	if err != nil || string(plaintext) != "+33 6 12 34 56 78" {
+		t.Fatalf("Decrypt = %q, %v", plaintext, err)
+	}
+
+	if _, err := old.Decrypt(value, []byte("users.email")); !errors.Is(err, ErrCiphertext) {
+		t.Errorf("value opened from another column, error: %v", err)
+	}
+	tampered := value[:len(value)-2] + "AA"
+	if _, err := old.Decrypt(tampered, []byte("users.phone_number")); !errors.Is(err, ErrCiphertext) {
+		t.Errorf("tampered value opened, error: %v", err)
+	}
+
+	other, _ := NewKeyring(map[string][]byte{"new": key(2)}, "new", key(3))
+	if _, err := other.Decrypt(value, []byte("users.phone_number")); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("value opened without its key, error: %v", err)
+	}
+}
+
+func TestBlindIndex(t *testing.T) {
+	k := keyring(t, "old")
+	if k.BlindIndex("email", " Jane@Example.com ") != k.BlindIndex("email", "jane@example.com") {
+		t.Error("normalized values have different indexes")
+	}
+	if k.BlindIndex("email", "jane@example.com") == k.BlindIndex("phone_number", "jane@example.com") {
+		t.Error("columns share their indexes")
+	}
+	if k.BlindIndex("email", "") != "" {
+		t.Error("empty value indexed")
+	}
+	// the index does not depend on the master keys
+	if keyring(t, "new").BlindIndex("email", "jane@example.com") != k.BlindIndex("email", "jane@example.com") {
+		t.Error("index changed with the active key")
+	}
+}
+
+func TestFromConfig(t *testing.T) {
+	derived, err := FromConfig(&config.Schema{AuthSecret: "secret"})
+	if err != nil || derived.Active() != DerivedKeyID {
+		t.Fatalf("FromConfig without keys = %v, %v", derived, err)
+	}
+
+	encoded := base64.StdEncoding.EncodeToString(key(4))
+	k, err := FromConfig(&config.Schema{EncryptionKeys: []string{"2024:" + encoded}})
+	if err != nil || k.Active() != "2024" {
+		t.Fatalf("FromConfig with a key = %v, %v", k, err)
+	}
+
+	_, err = FromConfig(&config.Schema{EncryptionKeys: []string{"a:" + encoded, "b:" + encoded}})
+	if !errors.Is(err, ErrNoActiveKey) {
+		t.Errorf("FromConfig without active key, error: %v", err)
+	}
+	_, err = FromConfig(&config.Schema{EncryptionKeys: []string{"a:c2hvcnQ="}})
+	if !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("FromConfig with a short key, error: %v", err)
+	}
+}
+
+func TestSerializer(t *testing.T) {
+	k := keyring(t, "new")
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{
+		DryRun:                 true,
+		SkipDefaultTransaction: true,
+		DisableAutomaticPing:   true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Register(db, k); err != nil {
+		t.Fatal(err)
+	}
+
+	row := patient{ID: "1", Email: "Jane@Example.com", Code: 123456}
+	stmt := db.Create(&row).Statement
+	if row.EmailIndex != k.BlindIndex("email", "jane@example.com") {
+		t.Errorf("EmailIndex = %q", row.EmailIndex)
+	}
+	var encrypted []string
+	for _, v := range stmt.Vars {
+		if valuer, ok := v.(driver.Valuer); ok {
+			v, _ = valuer.Value()
+		}
+		if s, ok := v.(string); ok && strings.HasPrefix(s, Prefix) {
+			encrypted = append(encrypted, s)
+		}
+	}
+	if len(encrypted) != 2 || strings.Contains(stmt.SQL.String(), "Jane") {
+		t.Fatalf("create not encrypted: %s %v", stmt.SQL.String(), stmt.Vars)
+	}
+
+	s, err := schema.Parse(&patient{}, &sync.Map{}, db.NamingStrategy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var read patient
+	dst := reflect.ValueOf(&read).Elem()
+	for i, name := range []string{"Email", "Code"} {
+		if err := (Serializer{}).Scan(context.Background(), s.LookUpField(name), dst, encrypted[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if read.Email != "Jane@Example.com" || read.Code != 123456 {
+		t.Errorf("read %+v", read)
+	}
+
+	// plaintext written before the encryption is read as is
+	if err := (Serializer{}).Scan(context.Background(), s.LookUpField("Code"), dst, "42"); err != nil || read.Code != 42 {
+		t.Errorf("plaintext read as %d, error: %v", read.Code, err)
+	}
+}
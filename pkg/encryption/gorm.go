@@ -0,0 +1,229 @@
+package encryption
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"sync/atomic"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// SerializerName encrypts the fields tagged `gorm:"serializer:encrypted"`,
+// their column must be text
+const SerializerName = "encrypted"
+
+// IndexTag names the field a blind index field is computed from, as in
+// `blindindex:"Email"`
+const IndexTag = "blindindex"
+
+var registered atomic.Pointer[Keyring]
+
+func init() {
+	schema.RegisterSerializer(SerializerName, Serializer{})
+}
+
+// Register sets the keyring of the encrypted fields and computes the blind
+// indexes of the created and saved rows. Columns updated by name keep their
+// blind index, they must be saved with the model.
+func Register(db *gorm.DB, keyring *Keyring) error {
+	registered.Store(keyring)
+
+	callbacks := db.Callback()
+	if err := callbacks.Create().Before("gorm:create").Register("encryption:create", setBlindIndexes); err != nil {
+		return err
+	}
+	return callbacks.Update().Before("gorm:update").Register("encryption:update", setBlindIndexes)
+}
+
+// BlindIndex returns the blind index of value with the registered keyring,
+// purpose is the column value is stored in
+func BlindIndex(purpose, value string) string {
+	keyring := registered.Load()
+	if keyring == nil {
+		return ""
+	}
+	return keyring.BlindIndex(purpose, value)
+}
+
+// Serializer encrypts strings, integers and the types implementing
+// driver.Valuer and sql.Scanner. Empty strings are stored as is, plaintext
+// values are read as is until they are rotated.
+type Serializer struct{}
+
+func (Serializer) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	keyring := registered.Load()
+	if keyring == nil {
+		return nil, ErrNoKeyring
+	}
+
+	plaintext, err := marshal(fieldValue)
+	if err != nil || plaintext == "" {
+		return plaintext, err
+	}
+	return keyring.Encrypt([]byte(plaintext), additionalData(field.Schema.Table, field.DBName))
+}
+
+func (Serializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	value, err := toString(dbValue)
+	if err != nil {
+		return fmt.Errorf("%s: %w", field.Name, err)
+	}
+
+	if _, encrypted := KeyID(value); encrypted {
+		keyring := registered.Load()
+		if keyring == nil {
+			return ErrNoKeyring
+		}
+		plaintext, err := keyring.Decrypt(value, additionalData(field.Schema.Table, field.DBName))
+		if err != nil {
+			return fmt.Errorf("%s: %w", field.Name, err)
+		}
+		value = string(plaintext)
+	}
+
+	target := reflect.New(field.FieldType)
+	if err := unmarshal(value, target); err != nil {
+		return fmt.Errorf("%s: %w", field.Name, err)
+	}
+	field.ReflectValueOf(ctx, dst).Set(target.Elem())
+	return nil
+}
+
+// additionalData binds the encrypted values to their column, they cannot be
+// copied to another one
+func additionalData(table, column string) []byte {
+	return []byte(table + "." + column)
+}
+
+func marshal(value interface{}) (string, error) {
+	if valuer, ok := value.(driver.Valuer); ok {
+		v, err := valuer.Value()
+		if err != nil {
+			return "", err
+		}
+		return toString(v)
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.String:
+		return rv.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(rv.Uint(), 10), nil
+	case reflect.Bool:
+		return strconv.FormatBool(rv.Bool()), nil
+	}
+	b, err := json.Marshal(value)
+	return string(b), err
+}
+
+// unmarshal sets value on target, a pointer to the field type
+func unmarshal(value string, target reflect.Value) error {
+	if value == "" {
+		return nil
+	}
+	if scanner, ok := target.Interface().(sql.Scanner); ok {
+		return scanner.Scan(value)
+	}
+
+	elem := target.Elem()
+	switch elem.Kind() {
+	case reflect.String:
+		elem.SetString(value)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		elem.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		i, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		elem.SetUint(i)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		elem.SetBool(b)
+	default:
+		return json.Unmarshal([]byte(value), target.Interface())
+	}
+	return nil
+}
+
+func toString(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case []byte:
+		return string(v), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	default:
+		return "", fmt.Errorf("cannot encrypt %T", value)
+	}
+}
+
+// indexField is a blind index field with the field it is computed from
+type indexField struct {
+	index  *schema.Field
+	source *schema.Field
+}
+
+func indexFields(s *schema.Schema) []indexField {
+	var fields []indexField
+	for _, field := range s.Fields {
+		if name := field.Tag.Get(IndexTag); name != "" {
+			if source := s.LookUpField(name); source != nil {
+				fields = append(fields, indexField{index: field, source: source})
+			}
+		}
+	}
+	return fields
+}
+
+func setBlindIndexes(db *gorm.DB) {
+	keyring := registered.Load()
+	if db.Error != nil || db.Statement.Schema == nil || keyring == nil {
+		return
+	}
+	// updates of columns by name do not carry the model values
+	if dest := reflect.Indirect(reflect.ValueOf(db.Statement.Dest)); dest.Kind() == reflect.Map {
+		return
+	}
+	fields := indexFields(db.Statement.Schema)
+	if len(fields) == 0 {
+		return
+	}
+
+	ctx := db.Statement.Context
+	set := func(target reflect.Value) {
+		for _, field := range fields {
+			value := fmt.Sprint(field.source.ReflectValueOf(ctx, target).Interface())
+			_ = db.AddError(field.index.Set(ctx, target, keyring.BlindIndex(field.source.DBName, value)))
+		}
+	}
+	switch rv := db.Statement.ReflectValue; rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			set(reflect.Indirect(rv.Index(i)))
+		}
+	case reflect.Struct:
+		set(rv)
+	}
+}
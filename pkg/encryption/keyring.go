@@ -0,0 +1,211 @@
+// Package encryption encrypts selected columns with envelope encryption:
+// values are sealed with AES-GCM by a data key, itself sealed by a master key
+// of the keyring. Blind indexes keep the encrypted columns searchable by
+// equality.
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Prefix starts the encrypted values, values without it are plaintext
+// written before the column was encrypted
+const Prefix = "enc:v1:"
+
+// KeySize is the size of the master, data and index keys
+const KeySize = 32
+
+var (
+	ErrNoKeyring   = errors.New("encryption keyring not registered")
+	ErrUnknownKey  = errors.New("unknown encryption key")
+	ErrInvalidKey  = errors.New("encryption keys must be 32 bytes with an id without colon")
+	ErrCiphertext  = errors.New("invalid encrypted value")
+	ErrNoActiveKey = errors.New("active encryption key not in the keyring")
+)
+
+var encoding = base64.RawURLEncoding
+
+// Keyring holds the master keys by id, values are encrypted with the active
+// one and decrypted with the one they name so the keys can be rotated
+type Keyring struct {
+	keys     map[string]cipher.AEAD
+	active   string
+	indexKey []byte
+
+	// the data key of the process, sealed once by the active key
+	once       sync.Once
+	dataKey    cipher.AEAD
+	sealedKey  string
+	dataKeyErr error
+	// opened data keys by sealed key
+	dataKeys sync.Map
+}
+
+// NewKeyring returns a keyring encrypting with the active key, indexKey
+// keys the blind indexes and cannot be rotated
+func NewKeyring(keys map[string][]byte, active string, indexKey []byte) (*Keyring, error) {
+	k := &Keyring{keys: map[string]cipher.AEAD{}, active: active, indexKey: indexKey}
+	for id, key := range keys {
+		if id == "" || strings.Contains(id, ":") || len(key) != KeySize {
+			return nil, ErrInvalidKey
+		}
+		aead, err := newAEAD(key)
+		if err != nil {
+			return nil, err
+		}
+		k.keys[id] = aead
+	}
+	if _, ok := k.keys[active]; !ok {
+		return nil, ErrNoActiveKey
+	}
+	if len(indexKey) != KeySize {
+		return nil, ErrInvalidKey
+	}
+	return k, nil
+}
+
+// Active returns the id of the key values are encrypted with
+func (k *Keyring) Active() string {
+	return k.active
+}
+
+// Encrypt seals plaintext, aad binds it to where it is stored
+func (k *Keyring) Encrypt(plaintext, aad []byte) (string, error) {
+	k.once.Do(func() {
+		key := make([]byte, KeySize)
+		if _, k.dataKeyErr = rand.Read(key); k.dataKeyErr != nil {
+			return
+		}
+		if k.dataKey, k.dataKeyErr = newAEAD(key); k.dataKeyErr != nil {
+			return
+		}
+		sealed, err := seal(k.keys[k.active], key, []byte(k.active))
+		k.sealedKey, k.dataKeyErr = encoding.EncodeToString(sealed), err
+	})
+	if k.dataKeyErr != nil {
+		return "", k.dataKeyErr
+	}
+
+	sealed, err := seal(k.dataKey, plaintext, aad)
+	if err != nil {
+		return "", err
+	}
+	return Prefix + k.active + ":" + k.sealedKey + ":" + encoding.EncodeToString(sealed), nil
+}
+
+// Decrypt opens a value returned by Encrypt with the same aad
+func (k *Keyring) Decrypt(value string, aad []byte) ([]byte, error) {
+	id, sealedKey, sealed, err := split(value)
+	if err != nil {
+		return nil, err
+	}
+
+	dataKey, err := k.openDataKey(id, sealedKey)
+	if err != nil {
+		return nil, err
+	}
+	ciphertext, err := encoding.DecodeString(sealed)
+	if err != nil {
+		return nil, ErrCiphertext
+	}
+	return open(dataKey, ciphertext, aad)
+}
+
+// KeyID returns the id of the master key value was encrypted with, false
+// for plaintext
+func KeyID(value string) (string, bool) {
+	id, _, _, err := split(value)
+	return id, err == nil
+}
+
+// BlindIndex returns the keyed hash of the normalized value, purpose keeps
+// the indexes of different columns apart. Empty values have no index.
+func (k *Keyring) BlindIndex(purpose, value string) string {
+	value = Normalize(value)
+	if value == "" {
+		return ""
+	}
+	mac := hmac.New(sha256.New, k.indexKey)
+	mac.Write([]byte(purpose + "\n" + value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Normalize makes the values differing only by case or surrounding spaces
+// share their blind index
+func Normalize(value string) string {
+	return strings.ToLower(strings.TrimSpace(value))
+}
+
+func (k *Keyring) openDataKey(id, sealedKey string) (cipher.AEAD, error) {
+	if cached, ok := k.dataKeys.Load(sealedKey); ok {
+		return cached.(cipher.AEAD), nil
+	}
+
+	master, ok := k.keys[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKey, id)
+	}
+	sealed, err := encoding.DecodeString(sealedKey)
+	if err != nil {
+		return nil, ErrCiphertext
+	}
+	key, err := open(master, sealed, []byte(id))
+	if err != nil {
+		return nil, err
+	}
+	dataKey, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	k.dataKeys.Store(sealedKey, dataKey)
+	return dataKey, nil
+}
+
+func split(value string) (string, string, string, error) {
+	if !strings.HasPrefix(value, Prefix) {
+		return "", "", "", ErrCiphertext
+	}
+	parts := strings.Split(strings.TrimPrefix(value, Prefix), ":")
+	if len(parts) != 3 {
+		return "", "", "", ErrCiphertext
+	}
+	return parts[0], parts[1], parts[2], nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal returns the nonce followed by the ciphertext
+func seal(aead cipher.AEAD, plaintext, aad []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, aad), nil
+}
+
+func open(aead cipher.AEAD, sealed, aad []byte) ([]byte, error) {
+	if len(sealed) < aead.NonceSize() {
+		return nil, ErrCiphertext
+	}
+	plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], aad)
+	if err != nil {
+		return nil, ErrCiphertext
+	}
+	return plaintext, nil
+}
@@ -0,0 +1,221 @@
+package encryption
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// Rotate encrypts with the active key the encrypted columns of model still
+// in plaintext or encrypted with another key, and fills their blind indexes.
+// Rows changed meanwhile are skipped, they are rotated by the next run. It
+// returns the number of rows updated.
+func Rotate(ctx context.Context, db *gorm.DB, model interface{}, batchSize int) (int64, error) {
+	keyring := registered.Load()
+	if keyring == nil {
+		return 0, ErrNoKeyring
+	}
+
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(model); err != nil {
+		return 0, err
+	}
+	s := stmt.Schema
+	pk := s.PrioritizedPrimaryField
+	if pk == nil {
+		return 0, fmt.Errorf("%s: rotation needs a single primary key", s.Table)
+	}
+
+	encrypted := map[string]bool{}
+	columns := []string{pk.DBName}
+	for _, field := range s.Fields {
+		if _, ok := field.Serializer.(Serializer); ok {
+			encrypted[field.DBName] = true
+			columns = append(columns, field.DBName)
+		}
+	}
+	indexes := indexFields(s)
+	for _, field := range indexes {
+		columns = append(columns, field.index.DBName)
+		if !encrypted[field.source.DBName] {
+			columns = append(columns, field.source.DBName)
+		}
+	}
+	if len(columns) == 1 {
+		return 0, nil
+	}
+
+	var updated int64
+	var last interface{}
+	for {
+		query := db.WithContext(ctx).Table(s.Table).Select(columns).Order(clause.OrderByColumn{Column: clause.Column{Name: pk.DBName}}).Limit(batchSize)
+		if last != nil {
+			query = query.Where(clause.Gt{Column: clause.Column{Name: pk.DBName}, Value: last})
+		}
+		var rows []map[string]interface{}
+		if err := query.Find(&rows).Error; err != nil {
+			return updated, err
+		}
+
+		for _, row := range rows {
+			last = row[pk.DBName]
+			changes, err := rotateRow(keyring, s, encrypted, indexes, row)
+			if err != nil {
+				return updated, fmt.Errorf("%s %v: %w", s.Table, last, err)
+			}
+			if len(changes) == 0 {
+				continue
+			}
+
+			update := db.WithContext(ctx).Table(s.Table).Where(clause.Eq{Column: clause.Column{Name: pk.DBName}, Value: last})
+			for column := range changes {
+				if encrypted[column] {
+					update = update.Where(clause.Eq{Column: clause.Column{Name: column}, Value: row[column]})
+				}
+			}
+			result := update.Updates(changes)
+			if result.Error != nil {
+				return updated, result.Error
+			}
+			updated += result.RowsAffected
+		}
+
+		if len(rows) < batchSize {
+			return updated, nil
+		}
+	}
+}
+
+// rotateRow returns the new values of the columns of row to update
+func rotateRow(keyring *Keyring, s *schema.Schema, encrypted map[string]bool, indexes []indexField, row map[string]interface{}) (map[string]interface{}, error) {
+	changes := map[string]interface{}{}
+	plaintexts := map[string]string{}
+	for column := range encrypted {
+		value, err := toString(row[column])
+		if err != nil {
+			return nil, err
+		}
+
+		id, isEncrypted := KeyID(value)
+		if isEncrypted {
+			plaintext, err := keyring.Decrypt(value, additionalData(s.Table, column))
+			if err != nil {
+				return nil, err
+			}
+			plaintexts[column] = string(plaintext)
+		} else {
+			plaintexts[column] = value
+		}
+
+		if plaintexts[column] == "" || (isEncrypted && id == keyring.Active()) {
+			continue
+		}
+		ciphertext, err := keyring.Encrypt([]byte(plaintexts[column]), additionalData(s.Table, column))
+		if err != nil {
+			return nil, err
+		}
+		changes[column] = ciphertext
+	}
+
+	for _, field := range indexes {
+		source, ok := plaintexts[field.source.DBName]
+		if !ok {
+			source, _ = toString(row[field.source.DBName])
+		}
+		current, _ := toString(row[field.index.DBName])
+		if index := keyring.BlindIndex(field.source.DBName, source); index != current {
+			changes[field.index.DBName] = index
+		}
+	}
+	return changes, nil
+}
+
+// FillIndexes computes the blind indexes still empty in the rows of model,
+// such as those of the rows written before the index was added. The other
+// rows are left to Rotate, so it is cheap enough to run on every startup.
+// Rows whose index cannot be set, such as a duplicate email, are skipped and
+// their errors returned once every row was visited.
+func FillIndexes(ctx context.Context, db *gorm.DB, model interface{}, batchSize int) (int64, error) {
+	keyring := registered.Load()
+	if keyring == nil {
+		return 0, ErrNoKeyring
+	}
+
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(model); err != nil {
+		return 0, err
+	}
+	s := stmt.Schema
+	pk := s.PrioritizedPrimaryField
+	indexes := indexFields(s)
+	if len(indexes) == 0 {
+		return 0, nil
+	}
+	if pk == nil {
+		return 0, fmt.Errorf("%s: filling indexes needs a single primary key", s.Table)
+	}
+
+	columns := []string{pk.DBName}
+	empty := make([]clause.Expression, 0, len(indexes))
+	for _, field := range indexes {
+		columns = append(columns, field.index.DBName, field.source.DBName)
+		empty = append(empty, clause.Eq{Column: clause.Column{Name: field.index.DBName}, Value: ""})
+	}
+
+	var updated int64
+	var errs []error
+	var last interface{}
+	for {
+		query := db.WithContext(ctx).Table(s.Table).Select(columns).Where(clause.Or(empty...)).
+			Order(clause.OrderByColumn{Column: clause.Column{Name: pk.DBName}}).Limit(batchSize)
+		if last != nil {
+			query = query.Where(clause.Gt{Column: clause.Column{Name: pk.DBName}, Value: last})
+		}
+		var rows []map[string]interface{}
+		if err := query.Find(&rows).Error; err != nil {
+			return updated, err
+		}
+
+		for _, row := range rows {
+			last = row[pk.DBName]
+			changes := map[string]interface{}{}
+			for _, field := range indexes {
+				if current, _ := toString(row[field.index.DBName]); current != "" {
+					continue
+				}
+				source, err := toString(row[field.source.DBName])
+				if err != nil {
+					return updated, fmt.Errorf("%s %v: %w", s.Table, last, err)
+				}
+				if _, isEncrypted := KeyID(source); isEncrypted {
+					plaintext, err := keyring.Decrypt(source, additionalData(s.Table, field.source.DBName))
+					if err != nil {
+						return updated, fmt.Errorf("%s %v: %w", s.Table, last, err)
+					}
+					source = string(plaintext)
+				}
+				if index := keyring.BlindIndex(field.source.DBName, source); index != "" {
+					changes[field.index.DBName] = index
+				}
+			}
+			if len(changes) == 0 {
+				continue
+			}
+
+			result := db.WithContext(ctx).Table(s.Table).Where(clause.Eq{Column: clause.Column{Name: pk.DBName}, Value: last}).Updates(changes)
+			if result.Error != nil {
+				errs = append(errs, fmt.Errorf("%s %v: %w", s.Table, last, result.Error))
+				continue
+			}
+			updated += result.RowsAffected
+		}
+
+		if len(rows) < batchSize {
+			return updated, errors.Join(errs...)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package events
+
+import (
+	"errors"
+	"fmt"
+
+	"main/pkg/config"
+	"main/pkg/redis"
+)
+
+// FromConfig is the bus of events_transport, "memory", "redis" or "nats"
+// with JetStream at events_nats_url
+func FromConfig(cfg *config.Schema, cache redis.IRedis) (*Bus, error) {
+	retry := Retry{Attempts: cfg.EventsRetryAttempts, Backoff: cfg.EventsRetryBackoff}
+	switch cfg.EventsTransport {
+	case "", "memory":
+		return NewBus(NewMemory(cfg.EventsMaxDeliveries), retry), nil
+	case "redis":
+		client := cache.Client()
+		if client == nil {
+			return nil, errors.New("events: the redis transport needs a Redis client, check the redis settings")
+		}
+		return NewBus(NewRedisStreams(client, cfg.EventsStreamMaxLen, cfg.EventsMaxDeliveries), retry), nil
+	case "nats":
+		conn, err := DialJetStream(cfg.EventsNATSURL, cfg.EventsStreamMaxLen, cfg.EventsMaxDeliveries)
+		if err != nil {
+			return nil, fmt.Errorf("events: connect to NATS: %w", err)
+		}
+		return NewBus(NewNATS(conn), retry), nil
+	}
+	return nil, fmt.Errorf("events: unknown transport %q", cfg.EventsTransport)
+}
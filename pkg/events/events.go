@@ -0,0 +1,232 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/quangdangfit/gocommon/logger"
+
+	"main/pkg/tenant"
+)
+
+// Names of the domain events
+const (
+	UserRegistered = "user.registered"
+	EmailVerified  = "user.email_verified"
+	PhoneVerified  = "user.phone_verified"
+	UserUpdated    = "user.updated"
+	UserDeleted    = "user.deleted"
+	DoctorCreated  = "doctor.created"
+	DoctorUpdated  = "doctor.updated"
+	DoctorDeleted  = "doctor.deleted"
+	AddressChanged = "address.changed"
+	// SpecialtyChanged is a change of the catalogue, the names of the
+	// specialties are part of the doctors
+	SpecialtyChanged = "specialty.changed"
+)
+
+// Names are the names of all the events, for the subscribers of every event
+var Names = []string{
+	UserRegistered,
+	EmailVerified,
+	PhoneVerified,
+	UserUpdated,
+	UserDeleted,
+	DoctorCreated,
+	DoctorUpdated,
+	DoctorDeleted,
+	AddressChanged,
+	SpecialtyChanged,
+}
+
+// Actions of the changes
+const (
+	ActionCreated   = "created"
+	ActionUpdated   = "updated"
+	ActionDeleted   = "deleted"
+	ActionRestored  = "restored"
+	ActionReviewed  = "reviewed"
+	ActionSuspended = "suspended"
+)
+
+// Change is the data of the events of users, doctors, addresses and
+// specialties
+type Change struct {
+	Action string `json:"action"`
+	// UserID is the user owning the doctor or address
+	UserID string `json:"user_id,omitempty"`
+	// Status is the status of the doctor after the change
+	Status string `json:"status,omitempty"`
+}
+
+// Event is a change of the domain, published by the services once it is
+// committed. Handlers get each event at least once, so they must tolerate
+// duplicates.
+type Event struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// SubjectID is the id of the changed user, doctor, address or specialty,
+	// empty when a job changed many
+	SubjectID  string          `json:"subject_id"`
+	TenantID   string          `json:"tenant_id,omitempty"`
+	ActorID    string          `json:"actor_id,omitempty"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Data       json.RawMessage `json:"data,omitempty"`
+	// Deliveries counts the deliveries of the event, from 1
+	Deliveries int `json:"-"`
+}
+
+// New is the event name of subjectID, with data as json and the tenant and
+// actor of ctx
+func New(ctx context.Context, name, subjectID string, data any) *Event {
+	event := &Event{
+		ID:         uuid.New().String(),
+		Name:       name,
+		SubjectID:  subjectID,
+		OccurredAt: time.Now().UTC(),
+	}
+	event.TenantID, _ = tenant.FromContext(ctx)
+	event.ActorID, _ = ctx.Value("userId").(string)
+	if data != nil {
+		if b, err := json.Marshal(data); err == nil {
+			event.Data = b
+		}
+	}
+	return event
+}
+
+// Decode unmarshals the data of the event into v
+func (e *Event) Decode(v any) error {
+	if len(e.Data) == 0 {
+		return errors.New("events: event has no data")
+	}
+	return json.Unmarshal(e.Data, v)
+}
+
+// Handler handles an event, an error has it delivered again
+type Handler func(ctx context.Context, event *Event) error
+
+// Publisher is what the services publish their events to
+//
+//go:generate mockery --name=Publisher
+type Publisher interface {
+	Publish(ctx context.Context, event *Event)
+}
+
+type nop struct{}
+
+func (nop) Publish(context.Context, *Event) {}
+
+// Nop discards the events, for the tools and tests without subscribers
+func Nop() Publisher {
+	return nop{}
+}
+
+// Transport carries the events from the publishers to the subscribers
+type Transport interface {
+	Publish(ctx context.Context, event *Event) error
+	// Subscribe delivers the events named names to handler until ctx is
+	// done. Each event goes to one subscriber of every group, the group
+	// being the consumer group, and again when handler fails until it was
+	// delivered maxDeliveries times.
+	Subscribe(ctx context.Context, group string, names []string, handler Handler) error
+	Close() error
+}
+
+// Retry is how often a handler is called for one delivery of an event
+// before it is left to the transport to deliver again
+type Retry struct {
+	Attempts int
+	Backoff  time.Duration
+}
+
+// DefaultRetry calls handlers 3 times, 200ms then 400ms apart
+var DefaultRetry = Retry{Attempts: 3, Backoff: 200 * time.Millisecond}
+
+// Bus publishes the events of the services to a transport and runs the
+// handlers of the subscribers, retrying them
+type Bus struct {
+	transport Transport
+	retry     Retry
+}
+
+func NewBus(transport Transport, retry Retry) *Bus {
+	if retry.Attempts <= 0 {
+		retry.Attempts = 1
+	}
+	return &Bus{transport: transport, retry: retry}
+}
+
+// Publish sends the event, failures are logged since the change is already
+// committed
+func (b *Bus) Publish(ctx context.Context, event *Event) {
+	if err := b.transport.Publish(ctx, event); err != nil {
+		logger.Errorf("Publish event fail, name: %s, subject: %s, error: %s", event.Name, event.SubjectID, err)
+	}
+}
+
+// Subscribe runs handler for the events named names in group until ctx is
+// done, see Transport.Subscribe
+func (b *Bus) Subscribe(ctx context.Context, group string, handler Handler, names ...string) error {
+	return b.transport.Subscribe(ctx, group, names, b.withRetry(group, handler))
+}
+
+// Close stops the transport
+func (b *Bus) Close() error {
+	return b.transport.Close()
+}
+
+func (b *Bus) withRetry(group string, handler Handler) Handler {
+	return func(ctx context.Context, event *Event) error {
+		if event.TenantID != "" {
+			ctx = tenant.WithID(ctx, event.TenantID)
+		}
+
+		var err error
+		backoff := b.retry.Backoff
+		for attempt := 1; attempt <= b.retry.Attempts; attempt++ {
+			if err = safeHandle(ctx, handler, event); err == nil {
+				return nil
+			}
+			if attempt == b.retry.Attempts {
+				break
+			}
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+		logger.Errorf("Handle event fail, group: %s, name: %s, id: %s, delivery: %d, error: %s", group, event.Name, event.ID, event.Deliveries, err)
+		return err
+	}
+}
+
+// safeHandle turns the panics of handler into errors so that the event is
+// delivered again instead of stopping the consumer
+func safeHandle(ctx context.Context, handler Handler, event *Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("events: handler panic: %v", r)
+		}
+	}()
+	return handler(ctx, event)
+}
+
+// subscribed reports whether name is one of names, empty names match all
+func subscribed(names []string, name string) bool {
+	if len(names) == 0 {
+		return true
+	}
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
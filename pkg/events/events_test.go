@@ -0,0 +1,202 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/quangdangfit/gocommon/logger"
+
+	"main/pkg/config"
+	"main/pkg/tenant"
+)
+
+func TestMain(m *testing.M) {
+	logger.Initialize(config.ProductionEnv)
+	os.Exit(m.Run())
+}
+
+// collector records the events handled by a subscriber
+type collector struct {
+	mu     sync.Mutex
+	events []*Event
+	seen   chan struct{}
+}
+
+func newCollector() *collector {
+	return &collector{seen: make(chan struct{}, 100)}
+}
+
+func (c *collector) handle(_ context.Context, event *Event) error {
+	c.mu.Lock()
+	c.events = append(c.events, event)
+	c.mu.Unlock()
+	c.seen <- struct{}{}
+	return nil
+}
+
+func (c *collector) wait(t *testing.T, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		select {
+		case <-c.seen:
+		case <-time.After(time.Second):
+			t.Fatalf("got %d events, want %d", i, n)
+		}
+	}
+}
+
+func (c *collector) count() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.events)
+}
+
+func TestMemoryGroups(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	bus := NewBus(NewMemory(3), Retry{Attempts: 1})
+	defer bus.Close()
+
+	cache, index, other := newCollector(), newCollector(), newCollector()
+	workers := []*collector{newCollector(), newCollector()}
+	for group, c := range map[string]*collector{"cache": cache, "index": index} {
+		if err := bus.Subscribe(ctx, group, c.handle, DoctorCreated); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := bus.Subscribe(ctx, "other", other.handle, AddressChanged); err != nil {
+		t.Fatal(err)
+	}
+	for _, w := range workers {
+		if err := bus.Subscribe(ctx, "workers", w.handle); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx = tenant.WithID(ctx, "clinic-1")
+	for i := 0; i < 4; i++ {
+		bus.Publish(ctx, New(ctx, DoctorCreated, "doctor-1", &Change{Action: ActionCreated}))
+	}
+	cache.wait(t, 4)
+	index.wait(t, 4)
+	workers[0].wait(t, 2)
+	workers[1].wait(t, 2)
+
+	if n := other.count(); n != 0 {
+		t.Errorf("subscriber of other names got %d events", n)
+	}
+	event := cache.events[0]
+	var change Change
+	if err := event.Decode(&change); err != nil || change.Action != ActionCreated {
+		t.Errorf("Decode = %+v, %v", change, err)
+	}
+	if event.TenantID != "clinic-1" || event.Deliveries != 1 {
+		t.Errorf("event = %+v, want tenant clinic-1 delivered once", event)
+	}
+}
+
+func TestMemoryRedelivery(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	bus := NewBus(NewMemory(2), Retry{Attempts: 2, Backoff: time.Millisecond})
+	defer bus.Close()
+
+	var mu sync.Mutex
+	calls := map[string]int{}
+	tenants := make(chan string, 10)
+	done := make(chan struct{}, 10)
+	handler := func(ctx context.Context, event *Event) error {
+		mu.Lock()
+		defer mu.Unlock()
+		calls[event.SubjectID]++
+		id, _ := tenant.FromContext(ctx)
+		tenants <- id
+		defer func() { done <- struct{}{} }()
+		switch {
+		case event.SubjectID == "panics":
+			panic("handler bug")
+		case event.SubjectID == "flaky" && calls[event.SubjectID] < 3:
+			return errors.New("try again")
+		}
+		return nil
+	}
+	if err := bus.Subscribe(ctx, "g", handler, UserRegistered); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx = tenant.WithID(ctx, "clinic-1")
+	bus.Publish(ctx, New(ctx, UserRegistered, "flaky", nil))
+	bus.Publish(ctx, New(ctx, UserRegistered, "panics", nil))
+	// flaky: 2 attempts, then 1 on the redelivery; panics: 2 attempts on
+	// each of the 2 deliveries
+	for i := 0; i < 7; i++ {
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatalf("handled %d times, want 7", i)
+		}
+	}
+	select {
+	case <-done:
+		t.Fatal("event delivered after max deliveries")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if calls["flaky"] != 3 || calls["panics"] != 4 {
+		t.Errorf("calls = %v, want flaky 3 and panics 4", calls)
+	}
+	if id := <-tenants; id != "clinic-1" {
+		t.Errorf("handler tenant = %q, want clinic-1", id)
+	}
+}
+
+func TestMemoryFullGroup(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	memory := NewMemory(1)
+	defer memory.Close()
+
+	started, release := make(chan struct{}, 1), make(chan struct{})
+	defer close(release)
+	stuck := func(context.Context, *Event) error {
+		started <- struct{}{}
+		<-release
+		return nil
+	}
+	handled := make(chan struct{}, 1)
+	counted := func(context.Context, *Event) error {
+		handled <- struct{}{}
+		return nil
+	}
+	if err := memory.Subscribe(ctx, "stuck", nil, stuck); err != nil {
+		t.Fatal(err)
+	}
+	if err := memory.Subscribe(ctx, "cache", nil, counted); err != nil {
+		t.Fatal(err)
+	}
+
+	// the stuck handler holds the first event and its queue the next
+	// memoryQueueSize, the last one finds it full
+	var err error
+	for i := 0; i < memoryQueueSize+2; i++ {
+		err = memory.Publish(ctx, New(ctx, DoctorUpdated, "doctor-1", nil))
+		if i == 0 {
+			<-started
+		}
+		select {
+		case <-handled:
+		case <-time.After(time.Second):
+			t.Fatalf("cache group handled %d events, want %d", i, memoryQueueSize+2)
+		}
+	}
+	if !errors.Is(err, ErrQueueFull) || !strings.Contains(err.Error(), "group stuck") || strings.Contains(err.Error(), "group cache") {
+		t.Errorf("Publish() error = %v, want the stuck group full", err)
+	}
+}
@@ -0,0 +1,112 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/nats-io/nats.go"
+)
+
+// NATSStream is the JetStream stream keeping the events, on the subjects
+// "events.>"
+const NATSStream = "EVENTS"
+
+// durableName turns the "." and wildcards of subjects, not allowed in
+// consumer names, into "_"
+var durableName = strings.NewReplacer(".", "_", "*", "_", ">", "_")
+
+// JetStream is the NATSConn of a NATS server with JetStream enabled. Every
+// group gets a durable consumer per subject, shared by the instances and
+// kept when they stop, which delivers each event again until it is acked or
+// was delivered maxDeliveries times.
+type JetStream struct {
+	conn          *nats.Conn
+	js            nats.JetStreamContext
+	maxDeliveries int
+}
+
+// DialJetStream connects to the NATS servers of url, comma separated, and
+// creates NATSStream with at most maxLen events when it is missing
+func DialJetStream(url string, maxLen int64, maxDeliveries int) (*JetStream, error) {
+	conn, err := nats.Connect(url, nats.Name("events"), nats.MaxReconnects(-1))
+	if err != nil {
+		return nil, err
+	}
+	js, err := conn.JetStream()
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	_, err = js.StreamInfo(NATSStream)
+	if errors.Is(err, nats.ErrStreamNotFound) {
+		_, err = js.AddStream(&nats.StreamConfig{
+			Name:     NATSStream,
+			Subjects: []string{subjectPrefix + ">"},
+			MaxMsgs:  maxLen,
+		})
+	}
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return &JetStream{conn: conn, js: js, maxDeliveries: maxDeliveries}, nil
+}
+
+func (j *JetStream) Publish(ctx context.Context, subject string, data []byte) error {
+	_, err := j.js.Publish(subject, data, nats.Context(ctx))
+	return err
+}
+
+// QueueSubscribe binds to the durable consumer of queue and subject, created
+// beforehand so that unsubscribing does not delete it
+func (j *JetStream) QueueSubscribe(subject, queue string, handler func(NATSMsg)) (NATSSubscription, error) {
+	durable := durableName.Replace(queue + "_" + subject)
+	_, err := j.js.AddConsumer(NATSStream, &nats.ConsumerConfig{
+		Durable:        durable,
+		DeliverSubject: "deliver." + durable,
+		DeliverGroup:   queue,
+		FilterSubject:  subject,
+		AckPolicy:      nats.AckExplicitPolicy,
+		MaxDeliver:     j.maxDeliveries,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return j.js.QueueSubscribe(subject, queue, func(msg *nats.Msg) {
+		handler(jetStreamMsg{msg: msg})
+	}, nats.Bind(NATSStream, durable), nats.ManualAck())
+}
+
+// Close closes the connection, the subscriptions with it
+func (j *JetStream) Close() error {
+	j.conn.Close()
+	return nil
+}
+
+type jetStreamMsg struct {
+	msg *nats.Msg
+}
+
+func (m jetStreamMsg) Data() []byte {
+	return m.msg.Data
+}
+
+func (m jetStreamMsg) Ack() error {
+	return m.msg.Ack()
+}
+
+func (m jetStreamMsg) Nak() error {
+	return m.msg.Nak()
+}
+
+func (m jetStreamMsg) NumDelivered() uint64 {
+	metadata, err := m.msg.Metadata()
+	if err != nil {
+		return 1
+	}
+	return metadata.NumDelivered
+}
@@ -0,0 +1,91 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+)
+
+func runJetStream(t *testing.T) string {
+	t.Helper()
+	srv, err := server.NewServer(&server.Options{Host: "127.0.0.1", Port: -1, JetStream: true, StoreDir: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	go srv.Start()
+	if !srv.ReadyForConnections(5 * time.Second) {
+		t.Fatal("NATS server not ready")
+	}
+	t.Cleanup(srv.Shutdown)
+	return srv.ClientURL()
+}
+
+func TestJetStream(t *testing.T) {
+	url := runJetStream(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	conn, err := DialJetStream(url, 1000, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bus := NewBus(NewNATS(conn), Retry{Attempts: 1})
+
+	cache := newCollector()
+	if err := bus.Subscribe(ctx, "cache", cache.handle, DoctorCreated); err != nil {
+		t.Fatal(err)
+	}
+	var mu sync.Mutex
+	deliveries := map[string][]int{}
+	done := make(chan struct{}, 10)
+	flaky := func(_ context.Context, event *Event) error {
+		mu.Lock()
+		defer mu.Unlock()
+		deliveries[event.SubjectID] = append(deliveries[event.SubjectID], event.Deliveries)
+		done <- struct{}{}
+		if len(deliveries[event.SubjectID]) == 1 {
+			return errors.New("try again")
+		}
+		return nil
+	}
+	if err := bus.Subscribe(ctx, "index", flaky, DoctorCreated); err != nil {
+		t.Fatal(err)
+	}
+
+	bus.Publish(ctx, New(ctx, DoctorCreated, "doctor-1", &Change{Action: ActionCreated}))
+	bus.Publish(ctx, New(ctx, AddressChanged, "address-1", nil))
+	cache.wait(t, 1)
+	for i := 0; i < 2; i++ {
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("index group handled %d deliveries, want 2", i)
+		}
+	}
+
+	mu.Lock()
+	if got := deliveries["doctor-1"]; len(got) != 2 || got[0] != 1 || got[1] != 2 {
+		t.Errorf("deliveries of the failed event = %v, want [1 2]", got)
+	}
+	mu.Unlock()
+	if event := cache.events[0]; event.SubjectID != "doctor-1" || event.Deliveries != 1 {
+		t.Errorf("event = %+v, want doctor-1 delivered once", event)
+	}
+
+	// the consumers outlive the instances
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+	again, err := DialJetStream(url, 1000, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer again.Close()
+	if _, err := again.js.ConsumerInfo(NATSStream, durableName.Replace("cache_"+subjectPrefix+DoctorCreated)); err != nil {
+		t.Errorf("consumer of the cache group after Close: %v", err)
+	}
+}
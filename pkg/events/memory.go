@@ -0,0 +1,168 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/quangdangfit/gocommon/logger"
+)
+
+// memoryQueueSize bounds the events waiting for each subscriber
+const memoryQueueSize = 1024
+
+var (
+	ErrClosed = errors.New("events: transport closed")
+	// ErrQueueFull is returned for the groups whose subscribers all have
+	// memoryQueueSize events waiting
+	ErrQueueFull = errors.New("events: subscriber queues are full")
+)
+
+// Memory delivers the events to the subscribers of this process. Events
+// waiting when the process stops are lost, use RedisStreams or NATS to keep
+// them.
+type Memory struct {
+	maxDeliveries int
+
+	mu     sync.Mutex
+	groups map[string]*memoryGroup
+	closed bool
+	done   chan struct{}
+}
+
+// memoryGroup hands the events round robin to its subscribers
+type memoryGroup struct {
+	subscribers []*memorySubscriber
+	next        int
+}
+
+type memorySubscriber struct {
+	names  []string
+	queue  chan *Event
+	cancel context.CancelFunc
+}
+
+func NewMemory(maxDeliveries int) *Memory {
+	return &Memory{
+		maxDeliveries: maxDeliveries,
+		groups:        make(map[string]*memoryGroup),
+		done:          make(chan struct{}),
+	}
+}
+
+// Publish hands event to every group on its own, a group that cannot take
+// it does not keep it from the others. The error joins the failures of each
+// group.
+func (m *Memory) Publish(_ context.Context, event *Event) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return ErrClosed
+	}
+
+	var errs []error
+	for name, group := range m.groups {
+		if err := m.enqueue(group, event); err != nil {
+			logger.Errorf("Publish event fail, group: %s, name: %s, error: %s", name, event.Name, err)
+			errs = append(errs, fmt.Errorf("group %s: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// enqueue hands a copy of event to the next subscriber of group having its
+// name and room in its queue, it is called with mu held
+func (m *Memory) enqueue(group *memoryGroup, event *Event) error {
+	full := false
+	for i := 0; i < len(group.subscribers); i++ {
+		subscriber := group.subscribers[(group.next+i)%len(group.subscribers)]
+		if !subscribed(subscriber.names, event.Name) {
+			continue
+		}
+
+		delivery := *event
+		select {
+		case subscriber.queue <- &delivery:
+			group.next = (group.next + i + 1) % len(group.subscribers)
+			return nil
+		default:
+			full = true
+		}
+	}
+	if full {
+		return ErrQueueFull
+	}
+	return nil
+}
+
+func (m *Memory) Subscribe(ctx context.Context, group string, names []string, handler Handler) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return ErrClosed
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	subscriber := &memorySubscriber{names: names, queue: make(chan *Event, memoryQueueSize), cancel: cancel}
+	g, ok := m.groups[group]
+	if !ok {
+		g = &memoryGroup{}
+		m.groups[group] = g
+	}
+	g.subscribers = append(g.subscribers, subscriber)
+
+	go m.consume(ctx, g, subscriber, handler)
+	return nil
+}
+
+func (m *Memory) consume(ctx context.Context, group *memoryGroup, subscriber *memorySubscriber, handler Handler) {
+	defer m.unsubscribe(group, subscriber)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-m.done:
+			return
+		case event := <-subscriber.queue:
+			event.Deliveries++
+			if err := handler(ctx, event); err == nil || event.Deliveries >= m.maxDeliveries {
+				if err != nil {
+					logger.Errorf("Drop event, name: %s, id: %s, deliveries: %d, error: %s", event.Name, event.ID, event.Deliveries, err)
+				}
+				continue
+			}
+			// delivered again, maybe to another subscriber of the group
+			m.mu.Lock()
+			if err := m.enqueue(group, event); err != nil {
+				logger.Errorf("Redeliver event fail, name: %s, id: %s, error: %s", event.Name, event.ID, err)
+			}
+			m.mu.Unlock()
+		}
+	}
+}
+
+func (m *Memory) unsubscribe(group *memoryGroup, subscriber *memorySubscriber) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, s := range group.subscribers {
+		if s == subscriber {
+			group.subscribers = append(group.subscribers[:i], group.subscribers[i+1:]...)
+			break
+		}
+	}
+	if group.next >= len(group.subscribers) {
+		group.next = 0
+	}
+	subscriber.cancel()
+}
+
+func (m *Memory) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.closed {
+		m.closed = true
+		close(m.done)
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/quangdangfit/gocommon/logger"
+)
+
+const subjectPrefix = "events."
+
+// NATSConn is the part of a NATS JetStream connection the NATS transport
+// uses, see JetStream. QueueSubscribe must deliver the messages of subject to
+// one subscriber of queue with manual acks.
+type NATSConn interface {
+	Publish(ctx context.Context, subject string, data []byte) error
+	QueueSubscribe(subject, queue string, handler func(NATSMsg)) (NATSSubscription, error)
+}
+
+// NATSMsg is a message received from NATSConn
+type NATSMsg interface {
+	Data() []byte
+	Ack() error
+	// Nak has the message delivered again
+	Nak() error
+	NumDelivered() uint64
+}
+
+type NATSSubscription interface {
+	Unsubscribe() error
+}
+
+// NATS publishes each event on the subject "events.<name>". The redelivery
+// limit is the MaxDeliver of the consumer on the server.
+type NATS struct {
+	conn NATSConn
+
+	mu            sync.Mutex
+	subscriptions []NATSSubscription
+}
+
+func NewNATS(conn NATSConn) *NATS {
+	return &NATS{conn: conn}
+}
+
+func (n *NATS) Publish(ctx context.Context, event *Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return n.conn.Publish(ctx, subjectPrefix+event.Name, data)
+}
+
+func (n *NATS) Subscribe(ctx context.Context, group string, names []string, handler Handler) error {
+	subjects := make([]string, 0, len(names))
+	for _, name := range names {
+		subjects = append(subjects, subjectPrefix+name)
+	}
+	if len(subjects) == 0 {
+		subjects = append(subjects, subjectPrefix+">")
+	}
+
+	var subscriptions []NATSSubscription
+	for _, subject := range subjects {
+		subscription, err := n.conn.QueueSubscribe(subject, group, func(msg NATSMsg) {
+			n.handle(ctx, msg, handler)
+		})
+		if err != nil {
+			_ = unsubscribe(subscriptions)
+			return err
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+	n.mu.Lock()
+	n.subscriptions = append(n.subscriptions, subscriptions...)
+	n.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		_ = unsubscribe(subscriptions)
+	}()
+	return nil
+}
+
+func (n *NATS) handle(ctx context.Context, msg NATSMsg, handler Handler) {
+	var event Event
+	if err := json.Unmarshal(msg.Data(), &event); err != nil {
+		logger.Errorf("Decode event fail, error: %s", err)
+		// never handled, redelivering it would not help
+		_ = msg.Ack()
+		return
+	}
+	event.Deliveries = int(msg.NumDelivered())
+	if err := handler(ctx, &event); err != nil {
+		_ = msg.Nak()
+		return
+	}
+	if err := msg.Ack(); err != nil {
+		logger.Errorf("Ack event fail, name: %s, id: %s, error: %s", event.Name, event.ID, err)
+	}
+}
+
+// Close unsubscribes, and closes the connection when it is an io.Closer
+func (n *NATS) Close() error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	err := unsubscribe(n.subscriptions)
+	n.subscriptions = nil
+	if closer, ok := n.conn.(io.Closer); ok {
+		err = errors.Join(err, closer.Close())
+	}
+	return err
+}
+
+func unsubscribe(subscriptions []NATSSubscription) error {
+	var errs []error
+	for _, subscription := range subscriptions {
+		if err := subscription.Unsubscribe(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,236 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	goredis "github.com/go-redis/redis/v8"
+	"github.com/quangdangfit/gocommon/logger"
+)
+
+const (
+	streamPrefix = "events:"
+	// DeadLetterStream keeps the events whose handlers failed every delivery
+	DeadLetterStream = "events:dead"
+
+	redisBlock      = 2 * time.Second
+	redisBatch      = 16
+	redisRetryPause = time.Second
+)
+
+// RedisStreams keeps the events in a stream per name, "events:<name>", read
+// by consumer groups. Events are acknowledged once handled, the pending ones
+// are claimed again after MinIdle and moved to DeadLetterStream after
+// MaxDeliveries.
+type RedisStreams struct {
+	client goredis.UniversalClient
+	// Consumer names this process in the groups
+	Consumer      string
+	MaxLen        int64
+	MinIdle       time.Duration
+	MaxDeliveries int64
+
+	wg     sync.WaitGroup
+	mu     sync.Mutex
+	cancel []context.CancelFunc
+}
+
+func NewRedisStreams(client goredis.UniversalClient, maxLen int64, maxDeliveries int) *RedisStreams {
+	host, _ := os.Hostname()
+	return &RedisStreams{
+		client:        client,
+		Consumer:      fmt.Sprintf("%s-%d", host, os.Getpid()),
+		MaxLen:        maxLen,
+		MinIdle:       30 * time.Second,
+		MaxDeliveries: int64(maxDeliveries),
+	}
+}
+
+func (r *RedisStreams) Publish(ctx context.Context, event *Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return r.client.XAdd(ctx, &goredis.XAddArgs{
+		Stream: streamPrefix + event.Name,
+		MaxLen: r.MaxLen,
+		Approx: true,
+		Values: map[string]interface{}{"event": data},
+	}).Err()
+}
+
+// Subscribe reads each stream in its own goroutine, streams of different
+// names can be on different nodes of a cluster
+func (r *RedisStreams) Subscribe(ctx context.Context, group string, names []string, handler Handler) error {
+	if len(names) == 0 {
+		return errors.New("events: redis streams subscribers need event names")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	r.mu.Lock()
+	r.cancel = append(r.cancel, cancel)
+	r.mu.Unlock()
+
+	for _, name := range names {
+		stream := streamPrefix + name
+		// new groups start with the events published from now on
+		err := r.client.XGroupCreateMkStream(ctx, stream, group, "$").Err()
+		if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+			cancel()
+			return err
+		}
+
+		r.wg.Add(2)
+		go r.read(ctx, stream, group, handler)
+		go r.reclaim(ctx, stream, group, handler)
+	}
+	return nil
+}
+
+func (r *RedisStreams) read(ctx context.Context, stream, group string, handler Handler) {
+	defer r.wg.Done()
+	for ctx.Err() == nil {
+		streams, err := r.client.XReadGroup(ctx, &goredis.XReadGroupArgs{
+			Group:    group,
+			Consumer: r.Consumer,
+			Streams:  []string{stream, ">"},
+			Count:    redisBatch,
+			Block:    redisBlock,
+		}).Result()
+		if errors.Is(err, goredis.Nil) {
+			continue
+		}
+		if err != nil {
+			if ctx.Err() == nil {
+				logger.Errorf("Read events fail, stream: %s, group: %s, error: %s", stream, group, err)
+				pause(ctx, redisRetryPause)
+			}
+			continue
+		}
+		for _, s := range streams {
+			for _, message := range s.Messages {
+				r.handle(ctx, stream, group, message, 1, handler)
+			}
+		}
+	}
+}
+
+// reclaim delivers again the events left pending by failed handlers and by
+// consumers that stopped
+func (r *RedisStreams) reclaim(ctx context.Context, stream, group string, handler Handler) {
+	defer r.wg.Done()
+	ticker := time.NewTicker(r.MinIdle)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		pending, err := r.client.XPendingExt(ctx, &goredis.XPendingExtArgs{
+			Stream: stream,
+			Group:  group,
+			Idle:   r.MinIdle,
+			Start:  "-",
+			End:    "+",
+			Count:  redisBatch,
+		}).Result()
+		if err != nil {
+			if ctx.Err() == nil {
+				logger.Errorf("Read pending events fail, stream: %s, group: %s, error: %s", stream, group, err)
+			}
+			continue
+		}
+
+		for _, p := range pending {
+			messages, err := r.client.XClaim(ctx, &goredis.XClaimArgs{
+				Stream:   stream,
+				Group:    group,
+				Consumer: r.Consumer,
+				MinIdle:  r.MinIdle,
+				Messages: []string{p.ID},
+			}).Result()
+			if err != nil || len(messages) == 0 {
+				// claimed by another consumer in between
+				continue
+			}
+			// the claim counts as a delivery
+			deliveries := p.RetryCount + 1
+			if deliveries > r.MaxDeliveries {
+				r.deadLetter(ctx, stream, group, messages[0])
+				continue
+			}
+			r.handle(ctx, stream, group, messages[0], int(deliveries), handler)
+		}
+	}
+}
+
+func (r *RedisStreams) handle(ctx context.Context, stream, group string, message goredis.XMessage, deliveries int, handler Handler) {
+	event, err := decodeMessage(message)
+	if err != nil {
+		logger.Errorf("Decode event fail, stream: %s, id: %s, error: %s", stream, message.ID, err)
+		r.deadLetter(ctx, stream, group, message)
+		return
+	}
+	event.Deliveries = deliveries
+	if err := handler(ctx, event); err != nil {
+		// left pending, reclaimed after MinIdle
+		return
+	}
+	if err := r.client.XAck(ctx, stream, group, message.ID).Err(); err != nil {
+		logger.Errorf("Ack event fail, stream: %s, id: %s, error: %s", stream, message.ID, err)
+	}
+}
+
+// deadLetter moves a message out of the pending events of group
+func (r *RedisStreams) deadLetter(ctx context.Context, stream, group string, message goredis.XMessage) {
+	values := map[string]interface{}{"stream": stream, "group": group, "id": message.ID}
+	for k, v := range message.Values {
+		values[k] = v
+	}
+	if err := r.client.XAdd(ctx, &goredis.XAddArgs{Stream: DeadLetterStream, MaxLen: r.MaxLen, Approx: true, Values: values}).Err(); err != nil {
+		logger.Errorf("Dead letter event fail, stream: %s, id: %s, error: %s", stream, message.ID, err)
+		return
+	}
+	logger.Errorf("Event moved to %s, stream: %s, group: %s, id: %s", DeadLetterStream, stream, group, message.ID)
+	_ = r.client.XAck(ctx, stream, group, message.ID).Err()
+}
+
+func decodeMessage(message goredis.XMessage) (*Event, error) {
+	raw, ok := message.Values["event"].(string)
+	if !ok {
+		return nil, errors.New("events: message without event")
+	}
+	var event Event
+	if err := json.Unmarshal([]byte(raw), &event); err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// Close stops the consumers and waits for the handlers running
+func (r *RedisStreams) Close() error {
+	r.mu.Lock()
+	for _, cancel := range r.cancel {
+		cancel()
+	}
+	r.cancel = nil
+	r.mu.Unlock()
+	r.wg.Wait()
+	return nil
+}
+
+func pause(ctx context.Context, d time.Duration) {
+	select {
+	case <-ctx.Done():
+	case <-time.After(d):
+	}
+}
@@ -0,0 +1,142 @@
+package idempotency
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+)
+
+const (
+	// Header is the HTTP header of the key chosen by the client for a
+	// request and its retries
+	Header = "Idempotency-Key"
+	// MetadataKey is the gRPC metadata key of the key
+	MetadataKey = "idempotency-key"
+	// ReplayedHeader is set on the responses replayed from a record
+	ReplayedHeader = "Idempotent-Replayed"
+
+	// MaxKeyLength bounds the keys, UUIDs are expected
+	MaxKeyLength = 255
+	// DefaultWindow is how long the responses are kept when not configured
+	DefaultWindow = 24 * time.Hour
+)
+
+var (
+	ErrKeyTooLong  = errors.New("idempotency key is longer than 255 characters")
+	ErrKeyReused   = errors.New("idempotency key was already used for another request")
+	ErrInProgress  = errors.New("a request with this idempotency key is in progress")
+	errNotReserved = errors.New("idempotency key is not reserved")
+)
+
+// Record is the outcome of the first request sent with a key. Retries with
+// the same key and fingerprint get its response instead of running again.
+type Record struct {
+	// Key is the key of the client hashed with its caller and route, see Scope
+	Key         string `json:"-" gorm:"primaryKey"`
+	Fingerprint string `json:"fingerprint" gorm:"not null"`
+	// Completed is false while the first request runs
+	Completed bool `json:"completed"`
+	// Status is the HTTP status, 0 for gRPC
+	Status int `json:"status"`
+	// ContentType is the media type of Body, its message name for gRPC
+	ContentType string    `json:"content_type"`
+	Body        []byte    `json:"body"`
+	CreatedAt   time.Time `json:"created_at"`
+	ExpiresAt   time.Time `json:"expires_at" gorm:"index"`
+}
+
+func (Record) TableName() string {
+	return "idempotency_keys"
+}
+
+// Store keeps the records for the window of the keys
+type Store interface {
+	// Reserve records that the request of fingerprint runs under key, or
+	// returns the record of the request that already used key
+	Reserve(ctx context.Context, key, fingerprint string, window time.Duration) (*Record, error)
+	// Complete stores the response of the request that reserved key
+	Complete(ctx context.Context, record *Record, window time.Duration) error
+	// Release forgets key so that a retry runs again, after failures
+	Release(ctx context.Context, key string) error
+}
+
+// Keys reserves keys for requests and replays the stored responses
+type Keys struct {
+	store  Store
+	window time.Duration
+}
+
+// New keeps the responses in store for window
+func New(store Store, window time.Duration) *Keys {
+	if window <= 0 {
+		window = DefaultWindow
+	}
+	return &Keys{store: store, window: window}
+}
+
+// Begin reserves the key of the client for the request of fingerprint. It
+// returns the stored record to replay when the request already completed,
+// ErrInProgress while it runs and ErrKeyReused when the key was used for a
+// request with another fingerprint. Both nil means the request must run and
+// be completed or released.
+func (k *Keys) Begin(ctx context.Context, key, fingerprint string) (*Record, error) {
+	record, err := k.store.Reserve(ctx, key, fingerprint, k.window)
+	if err != nil || record == nil {
+		return nil, err
+	}
+	if record.Fingerprint != fingerprint {
+		return nil, ErrKeyReused
+	}
+	if !record.Completed {
+		return nil, ErrInProgress
+	}
+	return record, nil
+}
+
+// Complete stores the response of the request reserved with Begin
+func (k *Keys) Complete(ctx context.Context, key, fingerprint string, status int, contentType string, body []byte) error {
+	now := time.Now()
+	return k.store.Complete(ctx, &Record{
+		Key:         key,
+		Fingerprint: fingerprint,
+		Completed:   true,
+		Status:      status,
+		ContentType: contentType,
+		Body:        body,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(k.window),
+	}, k.window)
+}
+
+// Release lets a retry of the request reserved with Begin run again
+func (k *Keys) Release(ctx context.Context, key string) error {
+	return k.store.Release(ctx, key)
+}
+
+// Scope is the stored key of the key of the client, the same key sent by
+// another caller or to another route is another key. It is a hash, so that
+// the patterns removing the cached responses never match it, whatever the
+// route and the key.
+func Scope(caller, route, key string) (string, error) {
+	if len(key) > MaxKeyLength {
+		return "", ErrKeyTooLong
+	}
+	if caller == "" {
+		caller = "anonymous"
+	}
+	return Fingerprint([]byte(caller), []byte(route), []byte(key)), nil
+}
+
+// Fingerprint identifies the payload of a request, the parts are the route
+// and the body
+func Fingerprint(parts ...[]byte) string {
+	hash := sha256.New()
+	for _, part := range parts {
+		// the length keeps ("ab", "c") and ("a", "bc") apart
+		hash.Write([]byte{byte(len(part) >> 24), byte(len(part) >> 16), byte(len(part) >> 8), byte(len(part))})
+		hash.Write(part)
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,119 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/quangdangfit/gocommon/logger"
+
+	"main/pkg/config"
+	"main/pkg/redis"
+)
+
+func TestMain(m *testing.M) {
+	logger.Initialize(config.ProductionEnv)
+	os.Exit(m.Run())
+}
+
+type memoryStore struct {
+	records map[string]Record
+}
+
+func (s *memoryStore) Reserve(_ context.Context, key, fingerprint string, _ time.Duration) (*Record, error) {
+	if record, ok := s.records[key]; ok {
+		return &record, nil
+	}
+	s.records[key] = Record{Key: key, Fingerprint: fingerprint}
+	return nil, nil
+}
+
+func (s *memoryStore) Complete(_ context.Context, record *Record, _ time.Duration) error {
+	s.records[record.Key] = *record
+	return nil
+}
+
+func (s *memoryStore) Release(_ context.Context, key string) error {
+	delete(s.records, key)
+	return nil
+}
+
+func TestKeys(t *testing.T) {
+	ctx := context.Background()
+	keys := New(&memoryStore{records: map[string]Record{}}, time.Hour)
+	first := Fingerprint([]byte("/address"), []byte(`{"name":"Home"}`))
+	other := Fingerprint([]byte("/address"), []byte(`{"name":"Work"}`))
+
+	if record, err := keys.Begin(ctx, "k", first); record != nil || err != nil {
+		t.Fatalf("first Begin = %v, %v", record, err)
+	}
+	if _, err := keys.Begin(ctx, "k", first); !errors.Is(err, ErrInProgress) {
+		t.Errorf("Begin while running = %v, want ErrInProgress", err)
+	}
+
+	if err := keys.Complete(ctx, "k", first, 200, "application/json", []byte(`{"id":"1"}`)); err != nil {
+		t.Fatal(err)
+	}
+	record, err := keys.Begin(ctx, "k", first)
+	if err != nil || record == nil || record.Status != 200 || string(record.Body) != `{"id":"1"}` {
+		t.Errorf("retry Begin = %+v, %v", record, err)
+	}
+	if _, err := keys.Begin(ctx, "k", other); !errors.Is(err, ErrKeyReused) {
+		t.Errorf("Begin with another payload = %v, want ErrKeyReused", err)
+	}
+
+	if err := keys.Release(ctx, "k"); err != nil {
+		t.Fatal(err)
+	}
+	if record, err := keys.Begin(ctx, "k", other); record != nil || err != nil {
+		t.Errorf("Begin after Release = %v, %v", record, err)
+	}
+}
+
+func TestScope(t *testing.T) {
+	a, _ := Scope("user-1", "POST /address", "k")
+	b, _ := Scope("user-2", "POST /address", "k")
+	if a == b {
+		t.Errorf("keys of two users are the same: %s", a)
+	}
+	if _, err := Scope("", "POST /address", strings.Repeat("k", MaxKeyLength+1)); !errors.Is(err, ErrKeyTooLong) {
+		t.Errorf("Scope with a long key = %v, want ErrKeyTooLong", err)
+	}
+	if Fingerprint([]byte("ab"), []byte("c")) == Fingerprint([]byte("a"), []byte("bc")) {
+		t.Error("fingerprints of different parts are the same")
+	}
+}
+
+func TestRedisStoreSurvivesCacheInvalidation(t *testing.T) {
+	ctx := context.Background()
+	server := miniredis.RunT(t)
+	cache := redis.New(redis.Config{Address: server.Addr(), Timeout: time.Second})
+	t.Cleanup(func() { _ = cache.Close() })
+	keys := New(NewRedisStore(cache), time.Hour)
+
+	key, err := Scope("user-1", "POST /api/v1/doctor", "new-doctor")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fingerprint := Fingerprint([]byte("/api/v1/doctor"), []byte(`{"name":"Jane"}`))
+	if _, err := keys.Begin(ctx, key, fingerprint); err != nil {
+		t.Fatal(err)
+	}
+	if err := keys.Complete(ctx, key, fingerprint, 200, "application/json", []byte(`{"id":"1"}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	// what the doctor handlers and subscribers run after a change
+	if err := cache.RemovePattern(ctx, "*doctor*"); err != nil {
+		t.Fatal(err)
+	}
+
+	record, err := keys.Begin(ctx, key, fingerprint)
+	if err != nil || record == nil || string(record.Body) != `{"id":"1"}` {
+		t.Errorf("retry Begin after the invalidation = %+v, %v, want the stored response", record, err)
+	}
+}
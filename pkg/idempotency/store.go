@@ -0,0 +1,123 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"main/pkg/config"
+	"main/pkg/redis"
+)
+
+const keyPrefix = "idempotency:"
+
+// FromConfig keeps the keys in Redis, or in Postgres when idempotency_store
+// is "postgres"
+func FromConfig(cfg *config.Schema, db *gorm.DB, cache redis.IRedis) (*Keys, error) {
+	switch cfg.IdempotencyStore {
+	case "", "redis":
+		return New(NewRedisStore(cache), cfg.IdempotencyWindow), nil
+	case "postgres":
+		return New(NewPostgresStore(db), cfg.IdempotencyWindow), nil
+	}
+	return nil, fmt.Errorf("idempotency: unknown store %q", cfg.IdempotencyStore)
+}
+
+// RedisStore keeps the records as expiring keys
+type RedisStore struct {
+	cache redis.IRedis
+}
+
+func NewRedisStore(cache redis.IRedis) *RedisStore {
+	return &RedisStore{cache: cache}
+}
+
+func (s *RedisStore) Reserve(ctx context.Context, key, fingerprint string, window time.Duration) (*Record, error) {
+	reserved, err := s.cache.SetNX(ctx, keyPrefix+key, &Record{Fingerprint: fingerprint}, window)
+	if err != nil || reserved {
+		return nil, err
+	}
+
+	var record Record
+	if err := s.cache.Get(ctx, keyPrefix+key, &record); err != nil {
+		// expired in between
+		if errors.Is(err, redis.ErrCacheMiss) {
+			return s.Reserve(ctx, key, fingerprint, window)
+		}
+		return nil, err
+	}
+	return &record, nil
+}
+
+func (s *RedisStore) Complete(ctx context.Context, record *Record, window time.Duration) error {
+	return s.cache.SetWithExpiration(ctx, keyPrefix+record.Key, record, window)
+}
+
+func (s *RedisStore) Release(ctx context.Context, key string) error {
+	return s.cache.Remove(ctx, keyPrefix+key)
+}
+
+// PostgresStore keeps the records in the idempotency_keys table, so that
+// they survive Redis restarts. The expired records are ignored and deleted
+// by Purge.
+type PostgresStore struct {
+	db *gorm.DB
+}
+
+func NewPostgresStore(db *gorm.DB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+func (s *PostgresStore) Reserve(ctx context.Context, key, fingerprint string, window time.Duration) (*Record, error) {
+	db := s.db.WithContext(ctx)
+	now := time.Now()
+	if err := db.Where("key = ? AND expires_at < ?", key, now).Delete(&Record{}).Error; err != nil {
+		return nil, err
+	}
+
+	result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&Record{
+		Key:         key,
+		Fingerprint: fingerprint,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(window),
+	})
+	if result.Error != nil || result.RowsAffected == 1 {
+		return nil, result.Error
+	}
+
+	var record Record
+	if err := db.Where("key = ?", key).First(&record).Error; err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+func (s *PostgresStore) Complete(ctx context.Context, record *Record, _ time.Duration) error {
+	result := s.db.WithContext(ctx).Model(&Record{}).
+		Where("key = ? AND fingerprint = ?", record.Key, record.Fingerprint).
+		Updates(map[string]interface{}{
+			"completed":    true,
+			"status":       record.Status,
+			"content_type": record.ContentType,
+			"body":         record.Body,
+			"expires_at":   record.ExpiresAt,
+		})
+	if result.Error == nil && result.RowsAffected == 0 {
+		return errNotReserved
+	}
+	return result.Error
+}
+
+func (s *PostgresStore) Release(ctx context.Context, key string) error {
+	return s.db.WithContext(ctx).Where("key = ?", key).Delete(&Record{}).Error
+}
+
+// Purge deletes the records expired before before
+func (s *PostgresStore) Purge(ctx context.Context, before time.Time) (int64, error) {
+	result := s.db.WithContext(ctx).Where("expires_at < ?", before).Delete(&Record{})
+	return result.RowsAffected, result.Error
+}
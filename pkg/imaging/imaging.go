@@ -0,0 +1,138 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+
+	"github.com/chai2010/webp"
+	"golang.org/x/image/draw"
+	// registers the webp decoder
+	_ "golang.org/x/image/webp"
+)
+
+// MaxPixels bounds the size of decoded images, against decompression bombs
+const MaxPixels = 40_000_000
+
+const (
+	jpegQuality = 85
+	webpQuality = 80
+)
+
+var ErrInvalidImage = errors.New("invalid image")
+
+// Size is a square thumbnail of Width pixels
+type Size struct {
+	Name  string
+	Width int
+}
+
+// Sizes are the thumbnails generated for profile pictures
+var Sizes = []Size{
+	{Name: "small", Width: 64},
+	{Name: "medium", Width: 256},
+	{Name: "large", Width: 512},
+}
+
+// Formats the thumbnails are encoded in, by extension
+var Formats = map[string]string{
+	"jpg":  "image/jpeg",
+	"webp": "image/webp",
+}
+
+// Variant is an encoded thumbnail
+type Variant struct {
+	// <size>.<format>, e.g. small.webp
+	Name        string
+	ContentType string
+	Data        []byte
+}
+
+// Process validates r is an image and returns its thumbnails in every size
+// and format. Images are re-encoded, which strips their metadata, EXIF
+// locations included, once the EXIF orientation is applied.
+func Process(r io.Reader) ([]Variant, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidImage, err)
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > MaxPixels {
+		return nil, fmt.Errorf("%w: %dx%d pixels", ErrInvalidImage, config.Width, config.Height)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidImage, err)
+	}
+	img = orient(img, exifOrientation(data))
+
+	variants := make([]Variant, 0, len(Sizes)*len(Formats))
+	for _, size := range Sizes {
+		thumb := thumbnail(img, size.Width)
+		for ext, contentType := range Formats {
+			var buf bytes.Buffer
+			if err := encode(&buf, thumb, contentType); err != nil {
+				return nil, err
+			}
+			variants = append(variants, Variant{
+				Name:        size.Name + "." + ext,
+				ContentType: contentType,
+				Data:        buf.Bytes(),
+			})
+		}
+	}
+	return variants, nil
+}
+
+// VariantNames are the names of the variants Process returns
+func VariantNames() []string {
+	names := make([]string, 0, len(Sizes)*len(Formats))
+	for _, size := range Sizes {
+		for ext := range Formats {
+			names = append(names, size.Name+"."+ext)
+		}
+	}
+	return names
+}
+
+// thumbnail crops the center square of img and scales it to width
+func thumbnail(img image.Image, width int) image.Image {
+	b := img.Bounds()
+	side := b.Dx()
+	if b.Dy() < side {
+		side = b.Dy()
+	}
+	x := b.Min.X + (b.Dx()-side)/2
+	y := b.Min.Y + (b.Dy()-side)/2
+	crop := image.Rect(x, y, x+side, y+side)
+
+	// small images are not scaled up
+	if side < width {
+		width = side
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, width, width))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, crop, draw.Src, nil)
+	return dst
+}
+
+func encode(w io.Writer, img image.Image, contentType string) error {
+	switch contentType {
+	case "image/jpeg":
+		return jpeg.Encode(w, img, &jpeg.Options{Quality: jpegQuality})
+	case "image/webp":
+		return webp.Encode(w, img, &webp.Options{Quality: webpQuality})
+	case "image/png":
+		return png.Encode(w, img)
+	default:
+		return fmt.Errorf("cannot encode %s", contentType)
+	}
+}
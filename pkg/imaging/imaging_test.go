@@ -0,0 +1,99 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"testing"
+)
+
+// withOrientation inserts an APP1 Exif segment with the orientation tag after
+// the SOI marker of a JPEG
+func withOrientation(jpg []byte, orientation uint16) []byte {
+	tiff := []byte("MM\x00\x2A\x00\x00\x00\x08")
+	tiff = binary.BigEndian.AppendUint16(tiff, 1)
+	tiff = binary.BigEndian.AppendUint16(tiff, 0x0112)
+	tiff = binary.BigEndian.AppendUint16(tiff, 3)
+	tiff = binary.BigEndian.AppendUint32(tiff, 1)
+	tiff = binary.BigEndian.AppendUint16(tiff, orientation)
+	tiff = append(tiff, 0, 0, 0, 0, 0, 0)
+
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+	app1 := []byte{0xFF, 0xE1}
+	app1 = binary.BigEndian.AppendUint16(app1, uint16(len(segment)+2))
+	app1 = append(app1, segment...)
+
+	return append(append(jpg[:2:2], app1...), jpg[2:]...)
+}
+
+func testJPEG(t *testing.T, w, h int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for x := 0; x < w; x++ {
+		for y := 0; y < h; y++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, nil); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestProcess(t *testing.T) {
+	data := withOrientation(testJPEG(t, 600, 300), 6)
+	if got := exifOrientation(data); got != 6 {
+		t.Fatalf("exifOrientation() = %d, want 6", got)
+	}
+
+	variants, err := Process(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(variants) != len(VariantNames()) {
+		t.Fatalf("Process() returned %d variants", len(variants))
+	}
+
+	widths := map[string]int{}
+	for _, size := range Sizes {
+		widths[size.Name] = size.Width
+	}
+	for _, v := range variants {
+		if bytes.Contains(v.Data, []byte("Exif")) {
+			t.Errorf("%s kept the EXIF metadata", v.Name)
+		}
+		config, format, err := image.DecodeConfig(bytes.NewReader(v.Data))
+		if err != nil {
+			t.Fatalf("%s: %v", v.Name, err)
+		}
+		size := v.Name[:bytes.IndexByte([]byte(v.Name), '.')]
+		// the image is 300 pixels high, large is not scaled up
+		want := widths[size]
+		if want > 300 {
+			want = 300
+		}
+		if config.Width != want || config.Height != want || "image/"+format != v.ContentType {
+			t.Errorf("%s is a %dx%d %s", v.Name, config.Width, config.Height, format)
+		}
+	}
+
+	if _, err := Process(bytes.NewReader([]byte("not an image"))); err == nil {
+		t.Error("Process() accepted an invalid image")
+	}
+}
+
+func TestOrient(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	img.Set(0, 0, color.RGBA{R: 255, A: 255})
+
+	// rotated 90 degrees clockwise, the left pixel goes on top
+	rotated := orient(img, 6)
+	if b := rotated.Bounds(); b.Dx() != 1 || b.Dy() != 2 {
+		t.Fatalf("orient(6) bounds = %v", b)
+	}
+	if r, _, _, _ := rotated.At(0, 0).RGBA(); r == 0 {
+		t.Error("orient(6) moved the top left pixel")
+	}
+}
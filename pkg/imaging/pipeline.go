@@ -0,0 +1,56 @@
+package imaging
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+var ErrQueueFull = errors.New("image processing queue is full")
+
+// Job is work run in the background by a Pipeline
+type Job func(ctx context.Context)
+
+// Pipeline runs jobs in the background on a fixed number of workers. Jobs
+// queued when the process stops are lost, callers keep the original image.
+type Pipeline struct {
+	jobs    chan Job
+	workers int
+}
+
+func NewPipeline(workers, queueSize int) *Pipeline {
+	if workers < 1 {
+		workers = 1
+	}
+	return &Pipeline{jobs: make(chan Job, queueSize), workers: workers}
+}
+
+// Submit queues job, without waiting for room in the queue
+func (p *Pipeline) Submit(job Job) error {
+	select {
+	case p.jobs <- job:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// Run runs the queued jobs until ctx is done
+func (p *Pipeline) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for i := 0; i < p.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case job := <-p.jobs:
+					job(ctx)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,32 @@
+package imaging
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// Variants are the urls of the variants of an image by name, stored as json
+type Variants map[string]string
+
+func (v Variants) Value() (driver.Value, error) {
+	if v == nil {
+		return "{}", nil
+	}
+	b, err := json.Marshal(map[string]string(v))
+	return string(b), err
+}
+
+func (v *Variants) Scan(value interface{}) error {
+	switch s := value.(type) {
+	case string:
+		return json.Unmarshal([]byte(s), v)
+	case []byte:
+		return json.Unmarshal(s, v)
+	case nil:
+		*v = nil
+		return nil
+	default:
+		return fmt.Errorf("cannot scan %T into Variants", value)
+	}
+}
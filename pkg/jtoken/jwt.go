@@ -14,8 +14,14 @@ import (
 const (
 	AccessTokenExpiredTime  = 5 * 60 * 60 // 5 hours
 	RefreshTokenExpiredTime = 30 * 24 * 3600
+	MFATokenExpiredTime     = 5 * 60
+	ClientTokenExpiredTime  = 60 * 60
 	AccessTokenType         = "x-access"  // 5 minutes
 	RefreshTokenType        = "x-refresh" // 30 days
+	MFATokenType            = "x-mfa"     // 5 minutes
+	ClientTokenType         = "x-client"  // 1 hour
+	// TenantClaim is the clinic of the user, users of no clinic have none
+	TenantClaim = "tid"
 )
 
 func GenerateAccessToken(payload map[string]interface{}) string {
@@ -52,6 +58,44 @@ func GenerateRefreshToken(payload map[string]interface{}) string {
 	return token
 }
 
+// GenerateMFAToken returns the short-lived challenge token exchanged for
+// access and refresh tokens once the second factor is verified
+func GenerateMFAToken(payload map[string]interface{}) string {
+	cfg := config.GetConfig()
+	payload["type"] = MFATokenType
+	tokenContent := jwt.MapClaims{
+		"payload": payload,
+		"exp":     time.Now().Add(time.Second * MFATokenExpiredTime).Unix(),
+	}
+	jwtToken := jwt.NewWithClaims(jwt.GetSigningMethod("HS256"), tokenContent)
+	token, err := jwtToken.SignedString([]byte(cfg.AuthSecret))
+	if err != nil {
+		logger.Error("Failed to generate mfa token: ", err)
+		return ""
+	}
+
+	return token
+}
+
+// GenerateClientToken returns the access token of a machine client obtained
+// with the client credentials grant
+func GenerateClientToken(payload map[string]interface{}) string {
+	cfg := config.GetConfig()
+	payload["type"] = ClientTokenType
+	tokenContent := jwt.MapClaims{
+		"payload": payload,
+		"exp":     time.Now().Add(time.Second * ClientTokenExpiredTime).Unix(),
+	}
+	jwtToken := jwt.NewWithClaims(jwt.GetSigningMethod("HS256"), tokenContent)
+	token, err := jwtToken.SignedString([]byte(cfg.AuthSecret))
+	if err != nil {
+		logger.Error("Failed to generate client token: ", err)
+		return ""
+	}
+
+	return token
+}
+
 func ValidateToken(jwtToken string) (map[string]interface{}, error) {
 	cfg := config.GetConfig()
 	cleanJWT := strings.Replace(jwtToken, "Bearer ", "", -1)
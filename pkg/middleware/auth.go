@@ -6,34 +6,103 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"main/pkg/jtoken"
+	"main/pkg/rbac"
+	"main/pkg/session"
+	"main/pkg/tenant"
 )
 
-func JWTAuth() gin.HandlerFunc {
-	return JWT(jtoken.AccessTokenType)
+// APIKeyHeader carries an API key, which can also be sent as a bearer token
+const APIKeyHeader = "X-API-Key"
+
+func JWTAuth(auth *Authenticator) gin.HandlerFunc {
+	return JWT(auth, jtoken.AccessTokenType)
+}
+
+func JWTRefresh(auth *Authenticator) gin.HandlerFunc {
+	return JWT(auth, jtoken.RefreshTokenType)
 }
 
-func JWTRefresh() gin.HandlerFunc {
-	return JWT(jtoken.RefreshTokenType)
+// JWTAuthOrMFA also accepts the MFA challenge token returned by login, for
+// the endpoints a user must reach to enroll before the first full login
+func JWTAuthOrMFA(auth *Authenticator) gin.HandlerFunc {
+	return JWT(auth, jtoken.AccessTokenType, jtoken.MFATokenType)
+}
+
+// JWTStream is JWTAuth also reading the access token from the access_token
+// query parameter, browsers cannot set headers on WebSocket and EventSource
+// connections
+func JWTStream(auth *Authenticator) gin.HandlerFunc {
+	authenticate := JWTAuth(auth)
+	return func(c *gin.Context) {
+		if token := c.Query("access_token"); token != "" && c.GetHeader("Authorization") == "" {
+			c.Request.Header.Set("Authorization", "Bearer "+token)
+		}
+		authenticate(c)
+	}
 }
 
-func JWT(tokenType string) gin.HandlerFunc {
-	print("Authorization:- ", tokenType)
+// JWTPermission accepts users whose role grants permission and machine
+// clients, by API key or client token, holding permission as a scope
+func JWTPermission(auth *Authenticator, permission string) gin.HandlerFunc {
+	authenticate := JWT(auth, jtoken.AccessTokenType, jtoken.ClientTokenType)
+	return func(c *gin.Context) {
+		if authenticate(c); c.IsAborted() {
+			return
+		}
+
+		if !rbac.Has(c.GetStringSlice("permissions"), permission) {
+			c.JSON(http.StatusForbidden, nil)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// JWT accepts tokens of tokenTypes, access and refresh tokens are rejected
+// once their session is revoked. API keys are accepted with
+// jtoken.ClientTokenType.
+func JWT(auth *Authenticator, tokenTypes ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		token := c.GetHeader("Authorization")
-		if token == "" {
+		key := c.GetHeader(APIKeyHeader)
+		if token == "" && key == "" {
 			c.JSON(http.StatusUnauthorized, nil)
 			c.Abort()
 			return
 		}
 
-		payload, err := jtoken.ValidateToken(token)
-		if err != nil || payload == nil || payload["type"] != tokenType {
+		id, err := auth.authenticate(c, token, key, tokenTypes)
+		if err != nil {
 			c.JSON(http.StatusUnauthorized, nil)
 			c.Abort()
 			return
 		}
-		c.Set("userId", payload["id"])
-		c.Set("role", payload["role"])
+
+		if id.UserID != "" {
+			c.Set("userId", id.UserID)
+		}
+		c.Set("role", id.Role)
+		c.Set("tokenType", id.TokenType)
+		c.Set("sessionId", id.SessionID)
+		c.Set("tokenId", id.TokenID)
+		c.Set("clientId", id.ClientID)
+		c.Set("permissions", id.Permissions)
+		if id.TenantID != "" {
+			c.Set(tenant.Key, id.TenantID)
+		}
+		c.Next()
+	}
+}
+
+// SessionClient records the user agent and IP of the request for the session
+// a login starts
+func SessionClient() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(session.ClientKey, session.Client{
+			UserAgent: c.Request.UserAgent(),
+			IP:        c.ClientIP(),
+		})
 		c.Next()
 	}
 }
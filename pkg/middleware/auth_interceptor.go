@@ -2,24 +2,40 @@ package middleware
 
 import (
 	"context"
-	"encoding/json"
-	"log"
+	"net"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 
 	"main/pkg/jtoken"
+	"main/pkg/rbac"
+	"main/pkg/session"
+	"main/pkg/tenant"
 )
 
 type AuthInterceptor struct {
-	ignoredMethods []string
+	ignoredMethods    []string
+	mfaMethods        []string
+	refreshMethods    []string
+	methodPermissions map[string]string
+	auth              *Authenticator
 }
 
-func NewAuthInterceptor(ignoredMethods []string) *AuthInterceptor {
+// NewAuthInterceptor skips authentication for ignoredMethods, MFA challenge
+// tokens are only accepted by mfaMethods and refreshMethods only accept
+// refresh tokens. Machine clients can only call the methods of
+// methodPermissions, holding the permission as a scope, users need their
+// role to grant it. Tokens of revoked sessions are rejected.
+func NewAuthInterceptor(ignoredMethods, mfaMethods, refreshMethods []string, methodPermissions map[string]string, auth *Authenticator) *AuthInterceptor {
 	return &AuthInterceptor{
-		ignoredMethods: ignoredMethods,
+		ignoredMethods:    ignoredMethods,
+		mfaMethods:        mfaMethods,
+		refreshMethods:    refreshMethods,
+		methodPermissions: methodPermissions,
+		auth:              auth,
 	}
 }
 
@@ -30,44 +46,158 @@ func (ai *AuthInterceptor) Unary() grpc.UnaryServerInterceptor {
 		info *grpc.UnaryServerInfo,
 		handler grpc.UnaryHandler,
 	) (interface{}, error) {
-		for _, m := range ai.ignoredMethods {
-			if info.FullMethod == m {
-				return handler(ctx, req)
-			}
+		ctx, err := ai.authenticate(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
 		}
 
-		ctx, userID, err := ai.authorize(ctx)
+		return handler(ctx, req)
+	}
+}
+
+func (ai *AuthInterceptor) Stream() grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		ctx, err := ai.authenticate(ss.Context(), info.FullMethod)
 		if err != nil {
-			return nil, status.New(codes.Internal, err.Error()).Err()
+			return err
 		}
 
-		// attach "userId" to context
-		ctx = context.WithValue(ctx, "userId", userID)
+		return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+func (ai *AuthInterceptor) authenticate(ctx context.Context, method string) (context.Context, error) {
+	for _, m := range ai.ignoredMethods {
+		if method == m {
+			return ctx, nil
+		}
+	}
 
-		return handler(ctx, req)
+	id, err := ai.authorize(ctx, ai.tokenTypes(method))
+	if err != nil {
+		return nil, err
 	}
+
+	if permission, ok := ai.methodPermissions[method]; ok && !rbac.Has(id.Permissions, permission) {
+		return nil, status.New(codes.PermissionDenied, "forbidden").Err()
+	}
+
+	// attach "userId", "role", the session, the permissions and the clinic to
+	// context
+	if id.UserID != "" {
+		ctx = context.WithValue(ctx, "userId", id.UserID)
+	}
+	ctx = context.WithValue(ctx, "role", id.Role)
+	ctx = context.WithValue(ctx, "sessionId", id.SessionID)
+	ctx = context.WithValue(ctx, "tokenId", id.TokenID)
+	ctx = context.WithValue(ctx, "clientId", id.ClientID)
+	ctx = context.WithValue(ctx, "permissions", id.Permissions)
+	if id.TenantID != "" {
+		ctx = tenant.WithID(ctx, id.TenantID)
+	}
+
+	return ctx, nil
 }
 
-func (ai *AuthInterceptor) authorize(ctx context.Context) (context.Context, string, error) {
+// tokenTypes returns the token types method accepts
+func (ai *AuthInterceptor) tokenTypes(method string) []string {
+	if contains(ai.refreshMethods, method) {
+		return []string{jtoken.RefreshTokenType}
+	}
+
+	tokenTypes := []string{jtoken.AccessTokenType}
+	if contains(ai.mfaMethods, method) {
+		tokenTypes = append(tokenTypes, jtoken.MFATokenType)
+	}
+	if _, ok := ai.methodPermissions[method]; ok {
+		tokenTypes = append(tokenTypes, jtoken.ClientTokenType)
+	}
+	return tokenTypes
+}
+
+func (ai *AuthInterceptor) authorize(ctx context.Context, tokenTypes []string) (*identity, error) {
 	m, ok := metadata.FromIncomingContext(ctx)
-	if !ok || len(m["token"]) == 0 {
-		return ctx, "", status.New(codes.Unauthenticated, "missing token").Err()
+	if !ok || (len(m["token"]) == 0 && len(m["x-api-key"]) == 0) {
+		return nil, status.New(codes.Unauthenticated, "missing token").Err()
 	}
 
-	payload, err := jtoken.ValidateToken(m["token"][0])
-	if err != nil {
-		return ctx, "", status.New(codes.Unauthenticated, "unauthorized").Err()
+	var token, key string
+	if len(m["token"]) > 0 {
+		token = m["token"][0]
+	}
+	if len(m["x-api-key"]) > 0 {
+		key = m["x-api-key"][0]
 	}
 
-	var meta map[string]interface{}
-	b, err := json.Marshal(payload)
+	id, err := ai.auth.authenticate(ctx, token, key, tokenTypes)
+	if err == session.ErrRevoked {
+		return nil, status.New(codes.Unauthenticated, "session revoked").Err()
+	}
 	if err != nil {
-		return ctx, "", status.New(codes.Unauthenticated, "unauthorized").Err()
-	} else {
-		if err := json.Unmarshal(b, &meta); err != nil {
-			log.Println("Error while unmarshalling auth data", err)
+		return nil, status.New(codes.Unauthenticated, "unauthorized").Err()
+	}
+
+	return id, nil
+}
+
+// SessionClientUnary records the user agent and peer address of the call for
+// the session a login starts
+func SessionClientUnary() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		return handler(withSessionClient(ctx), req)
+	}
+}
+
+func SessionClientStream() grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		return handler(srv, &contextStream{ServerStream: ss, ctx: withSessionClient(ss.Context())})
+	}
+}
+
+func withSessionClient(ctx context.Context) context.Context {
+	var client session.Client
+	if m, ok := metadata.FromIncomingContext(ctx); ok && len(m["user-agent"]) > 0 {
+		client.UserAgent = m["user-agent"][0]
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		client.IP = p.Addr.String()
+		if host, _, err := net.SplitHostPort(client.IP); err == nil {
+			client.IP = host
 		}
 	}
+	return session.WithClient(ctx, client)
+}
 
-	return ctx, payload["id"].(string), nil
+// contextStream is a stream with the context of the interceptors
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,153 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"main/pkg/apikey"
+	"main/pkg/jtoken"
+	"main/pkg/rbac"
+	"main/pkg/session"
+)
+
+var errUnauthorized = errors.New("unauthorized")
+
+// APIKeyVerifier resolves an API key to the machine client owning it
+type APIKeyVerifier interface {
+	VerifyAPIKey(ctx context.Context, key string) (*apikey.Principal, error)
+}
+
+// Authenticator checks what a token alone cannot tell: whether its session
+// was revoked, and which machine client an API key belongs to
+type Authenticator struct {
+	sessions *session.Store
+	apiKeys  APIKeyVerifier
+}
+
+func NewAuthenticator(sessions *session.Store, apiKeys APIKeyVerifier) *Authenticator {
+	return &Authenticator{
+		sessions: sessions,
+		apiKeys:  apiKeys,
+	}
+}
+
+func (a *Authenticator) Sessions() *session.Store {
+	return a.sessions
+}
+
+// identity is who a request is made by, a user or a machine client
+type identity struct {
+	UserID      string
+	Role        string
+	TokenType   string
+	SessionID   string
+	TokenID     string
+	ClientID    string
+	TenantID    string
+	Permissions []string
+}
+
+// authenticate resolves an API key or a JWT of tokenTypes, client tokens and
+// API keys are only accepted when tokenTypes has jtoken.ClientTokenType
+func (a *Authenticator) authenticate(ctx context.Context, token, key string, tokenTypes []string) (*identity, error) {
+	if key != "" {
+		return a.authenticateAPIKey(ctx, key, tokenTypes)
+	}
+
+	token = strings.TrimPrefix(token, "Bearer ")
+	if strings.HasPrefix(token, apikey.Prefix) {
+		return a.authenticateAPIKey(ctx, token, tokenTypes)
+	}
+
+	payload, err := jtoken.ValidateToken(token)
+	if err != nil || payload == nil || !acceptsTokenType(tokenTypes, payload["type"]) {
+		return nil, errUnauthorized
+	}
+
+	sessionID, err := a.checkSession(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	id := &identity{
+		TokenType: stringClaim(payload, "type"),
+		SessionID: sessionID,
+		TokenID:   stringClaim(payload, "jti"),
+		Role:      stringClaim(payload, "role"),
+	}
+	if id.TokenType == jtoken.ClientTokenType {
+		id.ClientID = stringClaim(payload, "id")
+		id.Permissions = stringsClaim(payload, "scopes")
+		return id, nil
+	}
+
+	id.UserID = stringClaim(payload, "id")
+	id.TenantID = stringClaim(payload, jtoken.TenantClaim)
+	// a clinic admin of no clinic would not be scoped to any
+	if id.TokenType != jtoken.MFATokenType && (id.TenantID != "" || id.Role != rbac.ClinicAdminRole) {
+		id.Permissions = rbac.ForRole(id.Role)
+	}
+	return id, nil
+}
+
+func (a *Authenticator) authenticateAPIKey(ctx context.Context, key string, tokenTypes []string) (*identity, error) {
+	if a == nil || a.apiKeys == nil || !acceptsTokenType(tokenTypes, jtoken.ClientTokenType) {
+		return nil, errUnauthorized
+	}
+
+	principal, err := a.apiKeys.VerifyAPIKey(ctx, key)
+	if err != nil {
+		return nil, errUnauthorized
+	}
+
+	return &identity{
+		Role:        rbac.ServiceRole,
+		TokenType:   jtoken.ClientTokenType,
+		ClientID:    principal.KeyID,
+		Permissions: principal.Scopes,
+	}, nil
+}
+
+// checkSession returns the session of an access, refresh or client token,
+// tokens issued before sessions existed have none and are rejected
+func (a *Authenticator) checkSession(ctx context.Context, payload map[string]interface{}) (string, error) {
+	if payload["type"] == jtoken.MFATokenType {
+		return "", nil
+	}
+
+	sessionID := stringClaim(payload, "sid")
+	if sessionID == "" {
+		return "", session.ErrRevoked
+	}
+	if a == nil || a.sessions == nil {
+		return sessionID, nil
+	}
+
+	return sessionID, a.sessions.Check(ctx, sessionID)
+}
+
+func acceptsTokenType(tokenTypes []string, tokenType interface{}) bool {
+	for _, t := range tokenTypes {
+		if tokenType == t {
+			return true
+		}
+	}
+	return false
+}
+
+func stringClaim(payload map[string]interface{}, name string) string {
+	value, _ := payload[name].(string)
+	return value
+}
+
+func stringsClaim(payload map[string]interface{}, name string) []string {
+	values, _ := payload[name].([]interface{})
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		if s, ok := v.(string); ok {
+			result = append(result, s)
+		}
+	}
+	return result
+}
@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/quangdangfit/gocommon/logger"
+
+	"main/pkg/idempotency"
+	"main/pkg/response"
+)
+
+// Idempotency replays the stored response to the retries of a request sent
+// with the same Idempotency-Key header, and rejects the key sent with
+// another payload. It must run after JWTAuth so that the keys are scoped by
+// user. Requests without the header and failures of the store run as usual,
+// 5xx responses are not stored so that retries run again.
+func Idempotency(keys *idempotency.Keys) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(idempotency.Header)
+		if key == "" || keys == nil {
+			c.Next()
+			return
+		}
+
+		scoped, err := idempotency.Scope(c.GetString("userId"), c.Request.Method+" "+c.FullPath(), key)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, err, err.Error())
+			c.Abort()
+			return
+		}
+
+		var body []byte
+		if c.Request.Body != nil {
+			if body, err = io.ReadAll(c.Request.Body); err != nil {
+				response.Error(c, http.StatusBadRequest, err, "Invalid parameters")
+				c.Abort()
+				return
+			}
+			c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		}
+		fingerprint := idempotency.Fingerprint([]byte(c.Request.URL.RequestURI()), body)
+
+		record, err := keys.Begin(c, scoped, fingerprint)
+		switch {
+		case errors.Is(err, idempotency.ErrKeyReused):
+			response.Error(c, http.StatusUnprocessableEntity, err, err.Error())
+			c.Abort()
+			return
+		case errors.Is(err, idempotency.ErrInProgress):
+			response.Error(c, http.StatusConflict, err, err.Error())
+			c.Abort()
+			return
+		case err != nil:
+			logger.Error("Idempotency key not reserved ", err)
+			c.Next()
+			return
+		case record != nil:
+			c.Header(idempotency.ReplayedHeader, "true")
+			c.Data(record.Status, record.ContentType, record.Body)
+			c.Abort()
+			return
+		}
+
+		writer := &recordingWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		completed := false
+		defer func() {
+			// panics and server errors let the retries run again
+			if !completed {
+				if err := keys.Release(c, scoped); err != nil {
+					logger.Error("Idempotency key not released ", err)
+				}
+			}
+		}()
+
+		c.Next()
+
+		if writer.Status() >= http.StatusInternalServerError {
+			return
+		}
+		if err := keys.Complete(c, scoped, fingerprint, writer.Status(), writer.Header().Get("Content-Type"), writer.body.Bytes()); err != nil {
+			logger.Error("Idempotency response not stored ", err)
+			return
+		}
+		completed = true
+	}
+}
+
+// recordingWriter keeps a copy of the body written to the client
+type recordingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *recordingWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *recordingWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,122 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+
+	"github.com/quangdangfit/gocommon/logger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+
+	"main/pkg/idempotency"
+)
+
+type IdempotencyInterceptor struct {
+	keys    *idempotency.Keys
+	methods map[string]bool
+}
+
+// NewIdempotencyInterceptor replays the stored response to the retries of
+// the calls of methods, full method names, sent with the same
+// idempotency-key metadata. It must run after the AuthInterceptor so that
+// the keys are scoped by user. Failed calls are not stored.
+func NewIdempotencyInterceptor(keys *idempotency.Keys, methods ...string) *IdempotencyInterceptor {
+	set := make(map[string]bool, len(methods))
+	for _, method := range methods {
+		set[method] = true
+	}
+	return &IdempotencyInterceptor{
+		keys:    keys,
+		methods: set,
+	}
+}
+
+func (ii *IdempotencyInterceptor) Unary() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		if ii.keys == nil || !ii.methods[info.FullMethod] {
+			return handler(ctx, req)
+		}
+		values := metadata.ValueFromIncomingContext(ctx, idempotency.MetadataKey)
+		message, ok := req.(proto.Message)
+		if len(values) == 0 || values[0] == "" || !ok {
+			return handler(ctx, req)
+		}
+
+		userID, _ := ctx.Value("userId").(string)
+		scoped, err := idempotency.Scope(userID, info.FullMethod, values[0])
+		if err != nil {
+			return nil, status.New(codes.InvalidArgument, err.Error()).Err()
+		}
+		payload, err := proto.MarshalOptions{Deterministic: true}.Marshal(message)
+		if err != nil {
+			return nil, status.New(codes.InvalidArgument, err.Error()).Err()
+		}
+		fingerprint := idempotency.Fingerprint([]byte(info.FullMethod), payload)
+
+		record, err := ii.keys.Begin(ctx, scoped, fingerprint)
+		switch {
+		case errors.Is(err, idempotency.ErrKeyReused):
+			return nil, status.New(codes.InvalidArgument, err.Error()).Err()
+		case errors.Is(err, idempotency.ErrInProgress):
+			return nil, status.New(codes.Aborted, err.Error()).Err()
+		case err != nil:
+			logger.Error("Idempotency key not reserved ", err)
+			return handler(ctx, req)
+		case record != nil:
+			res, err := replay(record)
+			if err != nil {
+				return nil, status.New(codes.Internal, err.Error()).Err()
+			}
+			_ = grpc.SetHeader(ctx, metadata.Pairs(idempotency.ReplayedHeader, "true"))
+			return res, nil
+		}
+
+		res, err := handler(ctx, req)
+		if err == nil {
+			err = ii.complete(ctx, scoped, fingerprint, res)
+			if err == nil {
+				return res, nil
+			}
+			logger.Error("Idempotency response not stored ", err)
+		}
+		if err := ii.keys.Release(ctx, scoped); err != nil {
+			logger.Error("Idempotency key not released ", err)
+		}
+		return res, err
+	}
+}
+
+func (ii *IdempotencyInterceptor) complete(ctx context.Context, key, fingerprint string, res interface{}) error {
+	message, ok := res.(proto.Message)
+	if !ok {
+		return errors.New("response is not a proto message")
+	}
+	body, err := proto.Marshal(message)
+	if err != nil {
+		return err
+	}
+	return ii.keys.Complete(ctx, key, fingerprint, 0, string(proto.MessageName(message)), body)
+}
+
+// replay decodes the stored response of record
+func replay(record *idempotency.Record) (proto.Message, error) {
+	messageType, err := protoregistry.GlobalTypes.FindMessageByName(protoreflect.FullName(record.ContentType))
+	if err != nil {
+		return nil, err
+	}
+	message := messageType.New().Interface()
+	if err := proto.Unmarshal(record.Body, message); err != nil {
+		return nil, err
+	}
+	return message, nil
+}
@@ -0,0 +1,118 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"main/pkg/ratelimit"
+	"main/pkg/response"
+)
+
+// maxPeekBody bounds how much of the body RateLimitByEmail reads
+const maxPeekBody = 64 << 10
+
+// RateLimitKey returns the key a request is counted under, "" skips the key
+type RateLimitKey func(c *gin.Context) string
+
+// RateLimitByIP counts requests per client IP
+func RateLimitByIP() RateLimitKey {
+	return func(c *gin.Context) string {
+		return "ip:" + c.ClientIP()
+	}
+}
+
+// RateLimitByUserID counts requests per authenticated user, it must run
+// after JWTAuth
+func RateLimitByUserID() RateLimitKey {
+	return func(c *gin.Context) string {
+		userID := c.GetString("userId")
+		if userID == "" {
+			return ""
+		}
+		return "user:" + userID
+	}
+}
+
+// RateLimitByEmail counts requests per "email" field of the JSON body. The
+// body is restored so the handler can still bind it.
+func RateLimitByEmail() RateLimitKey {
+	return func(c *gin.Context) string {
+		if c.Request.Body == nil {
+			return ""
+		}
+
+		body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxPeekBody))
+		if err != nil {
+			return ""
+		}
+		c.Request.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), c.Request.Body))
+
+		var req struct {
+			Email string `json:"email"`
+		}
+		if err := json.Unmarshal(body, &req); err != nil || req.Email == "" {
+			return ""
+		}
+		return "email:" + strings.ToLower(strings.TrimSpace(req.Email))
+	}
+}
+
+// RateLimit rejects requests with 429 once any of the keys exceeds rule, and
+// reports the most restrictive key in the RateLimit-* headers
+func RateLimit(limiter *ratelimit.Limiter, rule ratelimit.Rule, keys ...RateLimitKey) gin.HandlerFunc {
+	if len(keys) == 0 {
+		keys = []RateLimitKey{RateLimitByIP()}
+	}
+
+	return func(c *gin.Context) {
+		if !rule.Enabled() {
+			c.Next()
+			return
+		}
+
+		var strictest *ratelimit.Result
+		for _, key := range keys {
+			k := key(c)
+			if k == "" {
+				continue
+			}
+
+			result := limiter.Allow(c, rule, k)
+			if strictest == nil || !result.Allowed || result.Remaining < strictest.Remaining {
+				strictest = &result
+			}
+			if !result.Allowed {
+				break
+			}
+		}
+
+		if strictest == nil {
+			c.Next()
+			return
+		}
+
+		c.Header("RateLimit-Limit", strconv.Itoa(strictest.Limit))
+		c.Header("RateLimit-Remaining", strconv.Itoa(strictest.Remaining))
+		c.Header("RateLimit-Reset", seconds(strictest.Reset))
+
+		if !strictest.Allowed {
+			response.TooManyRequests(c, strictest.RetryAfter, errTooManyRequests)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// seconds rounds d up to whole seconds as header value
+func seconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
@@ -0,0 +1,156 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"main/pkg/ratelimit"
+)
+
+var errTooManyRequests = errors.New("too many requests")
+
+// InterceptorRateLimitKey returns the key a call is counted under, "" skips
+// the key
+type InterceptorRateLimitKey func(ctx context.Context, req interface{}) string
+
+// RateLimitByPeer counts calls per client IP
+func RateLimitByPeer() InterceptorRateLimitKey {
+	return func(ctx context.Context, _ interface{}) string {
+		p, ok := peer.FromContext(ctx)
+		if !ok || p.Addr == nil {
+			return ""
+		}
+		host, _, err := net.SplitHostPort(p.Addr.String())
+		if err != nil {
+			host = p.Addr.String()
+		}
+		return "ip:" + host
+	}
+}
+
+// RateLimitByCaller counts calls per authenticated user, it must run after
+// the AuthInterceptor
+func RateLimitByCaller() InterceptorRateLimitKey {
+	return func(ctx context.Context, _ interface{}) string {
+		userID, _ := ctx.Value("userId").(string)
+		if userID == "" {
+			return ""
+		}
+		return "user:" + userID
+	}
+}
+
+// RateLimitByRequestEmail counts calls per email of requests having one
+func RateLimitByRequestEmail() InterceptorRateLimitKey {
+	return func(_ context.Context, req interface{}) string {
+		r, ok := req.(interface{ GetEmail() string })
+		if !ok || r.GetEmail() == "" {
+			return ""
+		}
+		return "email:" + strings.ToLower(strings.TrimSpace(r.GetEmail()))
+	}
+}
+
+// RateLimitPolicy is a rule and the keys it is applied to
+type RateLimitPolicy struct {
+	Rule ratelimit.Rule
+	Keys []InterceptorRateLimitKey
+}
+
+type RateLimitInterceptor struct {
+	limiter  *ratelimit.Limiter
+	global   RateLimitPolicy
+	policies map[string]RateLimitPolicy
+}
+
+// NewRateLimitInterceptor applies global to every method and the policy
+// registered for the full method name on top of it
+func NewRateLimitInterceptor(limiter *ratelimit.Limiter, global RateLimitPolicy, policies map[string]RateLimitPolicy) *RateLimitInterceptor {
+	return &RateLimitInterceptor{
+		limiter:  limiter,
+		global:   global,
+		policies: policies,
+	}
+}
+
+func (ri *RateLimitInterceptor) Unary() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		if err := ri.allow(ctx, req, ri.global); err != nil {
+			return nil, err
+		}
+
+		if policy, ok := ri.policies[info.FullMethod]; ok {
+			if err := ri.allow(ctx, req, policy); err != nil {
+				return nil, err
+			}
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// Stream counts a streaming call once, keys reading the request see none
+func (ri *RateLimitInterceptor) Stream() grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		if err := ri.allow(ss.Context(), nil, ri.global); err != nil {
+			return err
+		}
+
+		if policy, ok := ri.policies[info.FullMethod]; ok {
+			if err := ri.allow(ss.Context(), nil, policy); err != nil {
+				return err
+			}
+		}
+
+		return handler(srv, ss)
+	}
+}
+
+func (ri *RateLimitInterceptor) allow(ctx context.Context, req interface{}, policy RateLimitPolicy) error {
+	if !policy.Rule.Enabled() {
+		return nil
+	}
+
+	keys := policy.Keys
+	if len(keys) == 0 {
+		keys = []InterceptorRateLimitKey{RateLimitByPeer()}
+	}
+
+	for _, key := range keys {
+		k := key(ctx, req)
+		if k == "" {
+			continue
+		}
+
+		result := ri.limiter.Allow(ctx, policy.Rule, k)
+		if !result.Allowed {
+			_ = grpc.SetHeader(ctx, metadata.Pairs(
+				"retry-after", seconds(result.RetryAfter),
+				"ratelimit-limit", strconv.Itoa(result.Limit),
+				"ratelimit-remaining", "0",
+			))
+			return status.New(codes.ResourceExhausted, errTooManyRequests.Error()).Err()
+		}
+	}
+
+	return nil
+}
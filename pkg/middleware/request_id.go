@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"main/pkg/audit"
+)
+
+// RequestIDHeader carries the id of a request, generated when the client
+// does not send a valid one
+const RequestIDHeader = "X-Request-ID"
+
+// request ids sent by clients are kept when they are short and printable
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,64}$`)
+
+func requestID(sent string) string {
+	if validRequestID.MatchString(sent) {
+		return sent
+	}
+	return uuid.New().String()
+}
+
+// RequestID sets the id of the request, echoed in the response, for the
+// audit log to correlate the events of a request
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := requestID(c.GetHeader(RequestIDHeader))
+		c.Set(audit.RequestIDKey, id)
+		c.Header(RequestIDHeader, id)
+		c.Next()
+	}
+}
+
+// RequestIDUnary sets the id of the call from the x-request-id metadata,
+// sent back in the response header
+func RequestIDUnary() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		return handler(withRequestID(ctx), req)
+	}
+}
+
+func RequestIDStream() grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		return handler(srv, &contextStream{ServerStream: ss, ctx: withRequestID(ss.Context())})
+	}
+}
+
+func withRequestID(ctx context.Context) context.Context {
+	var sent string
+	if m, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := m.Get(RequestIDHeader); len(values) > 0 {
+			sent = values[0]
+		}
+	}
+	id := requestID(sent)
+	_ = grpc.SetHeader(ctx, metadata.Pairs(RequestIDHeader, id))
+	return audit.WithRequestID(ctx, id)
+}
@@ -0,0 +1,108 @@
+// Package notify sends the notifications of the users by email, SMS and in
+// the app. Every sender goes through a Notifier so the channels each user
+// opted in for a type of notifications are honoured.
+package notify
+
+import (
+	"context"
+	"time"
+
+	"main/pkg/utils"
+)
+
+// Channels of the notifications
+const (
+	ChannelEmail = "email"
+	ChannelSMS   = "sms"
+	ChannelInApp = "in_app"
+)
+
+// Types of the notifications
+const (
+	// TypeVerificationCode carries the codes verifying an email or phone
+	// number, it cannot be turned off
+	TypeVerificationCode = "verification_code"
+	// TypeVerificationStatus tells doctors their license was reviewed
+	TypeVerificationStatus = "verification_status"
+)
+
+// Preference is the channels a user receives a type of notifications on
+type Preference struct {
+	Email bool `json:"email"`
+	SMS   bool `json:"sms"`
+	InApp bool `json:"in_app"`
+}
+
+// Has tells whether channel is turned on
+func (p Preference) Has(channel string) bool {
+	switch channel {
+	case ChannelEmail:
+		return p.Email
+	case ChannelSMS:
+		return p.SMS
+	case ChannelInApp:
+		return p.InApp
+	}
+	return false
+}
+
+// Kind is a type of notifications, users without a preference for it get
+// Default. Required types ignore the preferences.
+type Kind struct {
+	Default  Preference
+	Required bool
+}
+
+// Types are the kinds of every type of notifications
+var Types = map[string]Kind{
+	TypeVerificationCode:   {Default: Preference{Email: true, SMS: true}, Required: true},
+	TypeVerificationStatus: {Default: Preference{Email: true, InApp: true}},
+}
+
+// Message is a notification to a user
+type Message struct {
+	UserID string
+	Type   string
+	Title  string
+	Body   string
+	// HTML is the body of the email, Body when empty
+	HTML string
+	// Payload is kept as json with the in-app notification
+	Payload any
+	// Channels restricts the channels of the message, the preference of the
+	// user decides among them. All the channels when empty.
+	Channels []string
+	// TTL is how long the in-app notification is kept, the default of the
+	// notifier when 0
+	TTL time.Duration
+	// Name, Email and Phone are where the message is sent, those of the user
+	// when empty
+	Name  string
+	Email string
+	Phone string
+}
+
+// Notifier sends messages on the channels the preference of their user has
+type Notifier interface {
+	Notify(ctx context.Context, msg *Message) error
+}
+
+type nop struct{}
+
+func (nop) Notify(context.Context, *Message) error { return nil }
+
+// Nop drops the messages
+func Nop() Notifier {
+	return nop{}
+}
+
+// Senders deliver the messages outside of the app
+type Senders struct {
+	Email func(toName, toEmail, subject, plainTextContent, htmlContent string) error
+	SMS   func(toPhoneNumber, message string) error
+}
+
+// DefaultSenders send the emails with SendGrid and the SMS with Twilio
+func DefaultSenders() Senders {
+	return Senders{Email: utils.SendEmail, SMS: utils.SendSMS}
+}
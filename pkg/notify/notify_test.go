@@ -0,0 +1,22 @@
+package notify
+
+import "testing"
+
+func TestPreferenceHas(t *testing.T) {
+	p := Preference{Email: true, InApp: true}
+	for channel, want := range map[string]bool{ChannelEmail: true, ChannelSMS: false, ChannelInApp: true, "push": false} {
+		if got := p.Has(channel); got != want {
+			t.Errorf("Has(%q) = %v, want %v", channel, got, want)
+		}
+	}
+}
+
+func TestRequiredTypes(t *testing.T) {
+	kind, ok := Types[TypeVerificationCode]
+	if !ok || !kind.Required {
+		t.Fatalf("%s must be required", TypeVerificationCode)
+	}
+	if !kind.Default.Has(ChannelEmail) || !kind.Default.Has(ChannelSMS) {
+		t.Errorf("%s must be sent by email and SMS", TypeVerificationCode)
+	}
+}
@@ -0,0 +1,35 @@
+package oauth
+
+import (
+	"main/pkg/config"
+)
+
+// ProvidersFromConfig registers every provider having a client id
+func ProvidersFromConfig(cfg *config.Schema) *Registry {
+	var providers []Provider
+
+	if cfg.GOOGLE_CLIENT_ID != "" {
+		providers = append(providers, NewGoogle(cfg.GOOGLE_CLIENT_ID, cfg.GOOGLE_CLIENT_SECRET, cfg.GOOGLE_REDIRECT_URL))
+	}
+
+	if cfg.FACEBOOK_CLIENT_ID != "" {
+		providers = append(providers, NewFacebook(FacebookConfig{
+			ClientID:     cfg.FACEBOOK_CLIENT_ID,
+			ClientSecret: cfg.FACEBOOK_CLIENT_SECRET,
+			RedirectURL:  cfg.FACEBOOK_REDIRECT_URL,
+		}))
+	}
+
+	if cfg.OIDCIssuer != "" && cfg.OIDCClientID != "" {
+		providers = append(providers, NewOIDC(OIDCConfig{
+			Name:         cfg.OIDCName,
+			Issuer:       cfg.OIDCIssuer,
+			ClientID:     cfg.OIDCClientID,
+			ClientSecret: cfg.OIDCClientSecret,
+			RedirectURL:  cfg.OIDCRedirectURL,
+			Scopes:       cfg.OIDCScopes,
+		}))
+	}
+
+	return NewRegistry(providers...)
+}
@@ -0,0 +1,157 @@
+package oauth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// StateTTL is how long a started login can be completed
+const StateTTL = 10 * time.Minute
+
+var (
+	ErrUnknownProvider = errors.New("oauth: unknown provider")
+	ErrInvalidState    = errors.New("oauth: invalid or expired state")
+)
+
+// Identity is the account of a user at a provider
+type Identity struct {
+	Provider string
+	// Subject is the stable user id at the provider
+	Subject string
+	Email   string
+	// EmailVerified is set only when the provider asserts the user owns the
+	// email, accounts are linked by email on that condition only
+	EmailVerified bool
+	Name          string
+}
+
+// Provider is an OAuth 2.0 authorization code provider
+type Provider interface {
+	Name() string
+	// AuthCodeURL returns the consent page URL, the S256 PKCE challenge of
+	// verifier is added to it
+	AuthCodeURL(ctx context.Context, state, verifier string) (string, error)
+	// Exchange trades the authorization code for the identity of the user
+	Exchange(ctx context.Context, code, verifier string) (*Identity, error)
+}
+
+// Registry holds the configured providers by name
+type Registry struct {
+	providers map[string]Provider
+}
+
+func NewRegistry(providers ...Provider) *Registry {
+	r := &Registry{providers: make(map[string]Provider, len(providers))}
+	for _, p := range providers {
+		r.providers[p.Name()] = p
+	}
+	return r
+}
+
+func (r *Registry) Get(name string) (Provider, error) {
+	if r != nil {
+		if p, ok := r.providers[name]; ok {
+			return p, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnknownProvider, name)
+}
+
+// Names returns the sorted names of the registered providers
+func (r *Registry) Names() []string {
+	if r == nil {
+		return nil
+	}
+
+	names := make([]string, 0, len(r.providers))
+	for name := range r.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Flow runs the authorization code flow with a one-time state and PKCE
+type Flow struct {
+	registry *Registry
+	store    StateStore
+}
+
+func NewFlow(registry *Registry, store StateStore) *Flow {
+	return &Flow{
+		registry: registry,
+		store:    store,
+	}
+}
+
+// Start returns the URL to send the user to. linkUserID is set when a
+// signed-in user links the provider account to their own.
+func (f *Flow) Start(ctx context.Context, provider, linkUserID string) (string, error) {
+	p, err := f.registry.Get(provider)
+	if err != nil {
+		return "", err
+	}
+
+	key, err := randomString()
+	if err != nil {
+		return "", err
+	}
+
+	state := State{
+		Provider:   provider,
+		Verifier:   oauth2.GenerateVerifier(),
+		LinkUserID: linkUserID,
+	}
+	if err := f.store.Save(ctx, key, state, StateTTL); err != nil {
+		return "", err
+	}
+
+	return p.AuthCodeURL(ctx, key, state.Verifier)
+}
+
+// Finish consumes the state returned on the callback and exchanges code
+func (f *Flow) Finish(ctx context.Context, provider, stateKey, code string) (*Identity, *State, error) {
+	p, err := f.registry.Get(provider)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if stateKey == "" || code == "" {
+		return nil, nil, ErrInvalidState
+	}
+
+	state, err := f.store.Consume(ctx, stateKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	// a state started for another provider must not be replayed here
+	if state.Provider != provider {
+		return nil, nil, ErrInvalidState
+	}
+
+	identity, err := p.Exchange(ctx, code, state.Verifier)
+	if err != nil {
+		return nil, nil, err
+	}
+	if identity.Subject == "" {
+		return nil, nil, fmt.Errorf("oauth: %s returned no subject", provider)
+	}
+	identity.Provider = provider
+
+	return identity, state, nil
+}
+
+func randomString() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
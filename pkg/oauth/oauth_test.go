@@ -0,0 +1,125 @@
+package oauth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"golang.org/x/oauth2"
+)
+
+// fakeServer is a minimal OpenID Connect and Graph API server checking the
+// PKCE verifier of the challenge it was given
+type fakeServer struct {
+	*httptest.Server
+	challenge string
+}
+
+func newFakeServer(t *testing.T) *fakeServer {
+	f := &fakeServer{}
+	mux := http.NewServeMux()
+	mux.HandleFunc(discoveryPath, func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 f.URL,
+			"authorization_endpoint": f.URL + "/authorize",
+			"token_endpoint":         f.URL + "/token",
+			"userinfo_endpoint":      f.URL + "/userinfo",
+		})
+	})
+	token := func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		if r.Form.Get("code") != "good-code" || oauth2.S256ChallengeFromVerifier(r.Form.Get("code_verifier")) != f.challenge {
+			http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"at","token_type":"Bearer"}`))
+	}
+	mux.HandleFunc("/token", token)
+	mux.HandleFunc("/oauth/access_token", token)
+	mux.HandleFunc("/userinfo", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer at" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"sub":"42","email":"Jane@Example.com","email_verified":"true","name":"Jane"}`))
+	})
+	mux.HandleFunc("/me", func(w http.ResponseWriter, r *http.Request) {
+		mac := hmac.New(sha256.New, []byte("secret"))
+		mac.Write([]byte("at"))
+		if r.URL.Query().Get("appsecret_proof") != hex.EncodeToString(mac.Sum(nil)) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":"7","email":"jane@example.com","name":"Jane"}`))
+	})
+	f.Server = httptest.NewServer(mux)
+	t.Cleanup(f.Close)
+	return f
+}
+
+func (f *fakeServer) start(t *testing.T, flow *Flow, provider string) string {
+	authURL, err := flow.Start(context.Background(), provider, "")
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	u, _ := url.Parse(authURL)
+	if u.Query().Get("code_challenge_method") != "S256" {
+		t.Fatalf("no PKCE challenge in %s", authURL)
+	}
+	f.challenge = u.Query().Get("code_challenge")
+	return u.Query().Get("state")
+}
+
+func TestFlowOIDC(t *testing.T) {
+	server := newFakeServer(t)
+	flow := NewFlow(NewRegistry(NewOIDC(OIDCConfig{Name: "oidc", Issuer: server.URL, ClientID: "client"})), NewStateStore(nil))
+	ctx := context.Background()
+
+	state := server.start(t, flow, "oidc")
+	identity, _, err := flow.Finish(ctx, "oidc", state, "good-code")
+	if err != nil {
+		t.Fatalf("Finish() error = %v", err)
+	}
+	want := Identity{Provider: "oidc", Subject: "42", Email: "jane@example.com", EmailVerified: true, Name: "Jane"}
+	if *identity != want {
+		t.Errorf("Finish() = %+v, want %+v", *identity, want)
+	}
+
+	if _, _, err := flow.Finish(ctx, "oidc", state, "good-code"); err != ErrInvalidState {
+		t.Errorf("state replay error = %v, want ErrInvalidState", err)
+	}
+}
+
+func TestFlowFacebook(t *testing.T) {
+	server := newFakeServer(t)
+	facebook := NewFacebook(FacebookConfig{ClientID: "client", ClientSecret: "secret", GraphURL: server.URL})
+	flow := NewFlow(NewRegistry(facebook), NewStateStore(nil))
+	ctx := context.Background()
+
+	state := server.start(t, flow, "facebook")
+	identity, _, err := flow.Finish(ctx, "facebook", state, "good-code")
+	if err != nil {
+		t.Fatalf("Finish() error = %v", err)
+	}
+	if identity.Subject != "7" || identity.EmailVerified {
+		t.Errorf("Finish() = %+v, want subject 7 with an unverified email", *identity)
+	}
+}
+
+func TestFlowRejectsWrongVerifier(t *testing.T) {
+	server := newFakeServer(t)
+	flow := NewFlow(NewRegistry(NewOIDC(OIDCConfig{Name: "oidc", Issuer: server.URL})), NewStateStore(nil))
+
+	state := server.start(t, flow, "oidc")
+	server.challenge = "tampered"
+	if _, _, err := flow.Finish(context.Background(), "oidc", state, "good-code"); err == nil {
+		t.Fatal("Finish() accepted a verifier not matching the challenge")
+	}
+}
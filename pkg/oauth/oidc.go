@@ -0,0 +1,207 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"golang.org/x/oauth2"
+)
+
+const (
+	GoogleIssuer = "https://accounts.google.com"
+
+	discoveryPath = "/.well-known/openid-configuration"
+	maxBodySize   = 1 << 20
+)
+
+// OIDCConfig configures an OpenID Connect provider. Endpoints left empty are
+// read from the discovery document of Issuer.
+type OIDCConfig struct {
+	Name         string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+
+	AuthURL     string
+	TokenURL    string
+	UserInfoURL string
+
+	// HTTPClient is used for discovery, token and userinfo calls,
+	// http.DefaultClient when nil
+	HTTPClient *http.Client
+}
+
+type oidcProvider struct {
+	config OIDCConfig
+
+	mu          sync.Mutex
+	oauth2      *oauth2.Config
+	userInfoURL string
+}
+
+// NewOIDC creates a provider for any OpenID Connect compliant server. The
+// identity is read from the userinfo endpoint with the access token obtained
+// directly from the token endpoint.
+func NewOIDC(config OIDCConfig) Provider {
+	if len(config.Scopes) == 0 {
+		config.Scopes = []string{"openid", "email", "profile"}
+	}
+	return &oidcProvider{config: config}
+}
+
+// NewGoogle creates the Google provider
+func NewGoogle(clientID, clientSecret, redirectURL string) Provider {
+	return NewOIDC(OIDCConfig{
+		Name:         "google",
+		Issuer:       GoogleIssuer,
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		RedirectURL:  redirectURL,
+	})
+}
+
+func (p *oidcProvider) Name() string {
+	return p.config.Name
+}
+
+func (p *oidcProvider) AuthCodeURL(ctx context.Context, state, verifier string) (string, error) {
+	cfg, _, err := p.endpoints(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return cfg.AuthCodeURL(state, oauth2.S256ChallengeOption(verifier)), nil
+}
+
+func (p *oidcProvider) Exchange(ctx context.Context, code, verifier string) (*Identity, error) {
+	cfg, userInfoURL, err := p.endpoints(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = withHTTPClient(ctx, p.config.HTTPClient)
+	token, err := cfg.Exchange(ctx, code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return nil, fmt.Errorf("oauth: %s token exchange: %w", p.config.Name, err)
+	}
+
+	var info struct {
+		Subject       string      `json:"sub"`
+		Email         string      `json:"email"`
+		EmailVerified interface{} `json:"email_verified"`
+		Name          string      `json:"name"`
+	}
+	if err := getJSON(cfg.Client(ctx, token), userInfoURL, &info); err != nil {
+		return nil, fmt.Errorf("oauth: %s userinfo: %w", p.config.Name, err)
+	}
+
+	return &Identity{
+		Subject:       info.Subject,
+		Email:         strings.ToLower(strings.TrimSpace(info.Email)),
+		EmailVerified: isTrue(info.EmailVerified),
+		Name:          info.Name,
+	}, nil
+}
+
+// endpoints discovers the endpoints on first use and keeps them once found,
+// a failed discovery is retried on the next login
+func (p *oidcProvider) endpoints(ctx context.Context) (*oauth2.Config, string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.oauth2 != nil {
+		return p.oauth2, p.userInfoURL, nil
+	}
+
+	authURL, tokenURL, userInfoURL := p.config.AuthURL, p.config.TokenURL, p.config.UserInfoURL
+	if authURL == "" || tokenURL == "" || userInfoURL == "" {
+		var doc struct {
+			Issuer                string `json:"issuer"`
+			AuthorizationEndpoint string `json:"authorization_endpoint"`
+			TokenEndpoint         string `json:"token_endpoint"`
+			UserInfoEndpoint      string `json:"userinfo_endpoint"`
+		}
+		issuer := strings.TrimSuffix(p.config.Issuer, "/")
+		if err := getJSON(httpClient(p.config.HTTPClient), issuer+discoveryPath, &doc); err != nil {
+			return nil, "", fmt.Errorf("oauth: %s discovery: %w", p.config.Name, err)
+		}
+		if strings.TrimSuffix(doc.Issuer, "/") != issuer {
+			return nil, "", fmt.Errorf("oauth: %s discovery: issuer mismatch %q", p.config.Name, doc.Issuer)
+		}
+
+		if authURL == "" {
+			authURL = doc.AuthorizationEndpoint
+		}
+		if tokenURL == "" {
+			tokenURL = doc.TokenEndpoint
+		}
+		if userInfoURL == "" {
+			userInfoURL = doc.UserInfoEndpoint
+		}
+	}
+
+	p.oauth2 = &oauth2.Config{
+		ClientID:     p.config.ClientID,
+		ClientSecret: p.config.ClientSecret,
+		RedirectURL:  p.config.RedirectURL,
+		Scopes:       p.config.Scopes,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  authURL,
+			TokenURL: tokenURL,
+		},
+	}
+	p.userInfoURL = userInfoURL
+
+	return p.oauth2, p.userInfoURL, nil
+}
+
+// isTrue accepts the boolean claim and its string form some servers send
+func isTrue(v interface{}) bool {
+	switch v := v.(type) {
+	case bool:
+		return v
+	case string:
+		return strings.EqualFold(v, "true")
+	default:
+		return false
+	}
+}
+
+func httpClient(client *http.Client) *http.Client {
+	if client == nil {
+		return http.DefaultClient
+	}
+	return client
+}
+
+func withHTTPClient(ctx context.Context, client *http.Client) context.Context {
+	if client == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, oauth2.HTTPClient, client)
+}
+
+func getJSON(client *http.Client, url string, v interface{}) error {
+	res, err := client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, maxBodySize))
+	if err != nil {
+		return err
+	}
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d: %s", res.StatusCode, body)
+	}
+
+	return json.Unmarshal(body, v)
+}
@@ -0,0 +1,114 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	goredis "github.com/go-redis/redis/v8"
+
+	"main/pkg/redis"
+)
+
+const stateKeyPrefix = "oauth:state:"
+
+// State is what the callback needs from the request that started the login
+type State struct {
+	Provider string `json:"provider"`
+	// Verifier is the PKCE code verifier
+	Verifier string `json:"verifier"`
+	// LinkUserID is set when a signed-in user links an identity
+	LinkUserID string `json:"link_user_id,omitempty"`
+}
+
+// StateStore keeps started logins until their callback, each state can be
+// consumed once
+type StateStore interface {
+	Save(ctx context.Context, key string, state State, ttl time.Duration) error
+	// Consume returns ErrInvalidState when key is unknown, expired or used
+	Consume(ctx context.Context, key string) (*State, error)
+}
+
+// NewStateStore keeps states in Redis so the callback can reach any
+// instance, a nil cache keeps them in memory
+func NewStateStore(cache redis.IRedis) StateStore {
+	if cache == nil {
+		return &memoryStateStore{states: make(map[string]memoryState)}
+	}
+	return &redisStateStore{cache: cache}
+}
+
+// getDelScript is GETDEL for servers older than Redis 6.2
+var getDelScript = goredis.NewScript(`
+local value = redis.call("GET", KEYS[1])
+if value then
+	redis.call("DEL", KEYS[1])
+end
+return value
+`)
+
+type redisStateStore struct {
+	cache redis.IRedis
+}
+
+func (s *redisStateStore) Save(ctx context.Context, key string, state State, ttl time.Duration) error {
+	return s.cache.SetWithExpiration(ctx, stateKeyPrefix+key, state, ttl)
+}
+
+func (s *redisStateStore) Consume(ctx context.Context, key string) (*State, error) {
+	result, err := s.cache.RunScript(ctx, getDelScript, []string{stateKeyPrefix + key})
+	if err == goredis.Nil {
+		return nil, ErrInvalidState
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	value, ok := result.(string)
+	if !ok {
+		return nil, ErrInvalidState
+	}
+
+	var state State
+	if err := json.Unmarshal([]byte(value), &state); err != nil {
+		return nil, ErrInvalidState
+	}
+	return &state, nil
+}
+
+type memoryState struct {
+	state     State
+	expiresAt time.Time
+}
+
+type memoryStateStore struct {
+	mu     sync.Mutex
+	states map[string]memoryState
+}
+
+func (s *memoryStateStore) Save(_ context.Context, key string, state State, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for k, v := range s.states {
+		if now.After(v.expiresAt) {
+			delete(s.states, k)
+		}
+	}
+	s.states[key] = memoryState{state: state, expiresAt: now.Add(ttl)}
+	return nil
+}
+
+func (s *memoryStateStore) Consume(_ context.Context, key string) (*State, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	v, ok := s.states[key]
+	delete(s.states, key)
+	if !ok || time.Now().After(v.expiresAt) {
+		return nil, ErrInvalidState
+	}
+	return &v.state, nil
+}
@@ -0,0 +1,262 @@
+package patch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// jsonPatch is a JSON Patch, RFC 6902: operations applied in order, all or
+// nothing
+type jsonPatch []operation
+
+type operation struct {
+	Op    string           `json:"op"`
+	Path  string           `json:"path"`
+	From  string           `json:"from"`
+	Value *json.RawMessage `json:"value"`
+}
+
+var errPath = errors.New("path not found")
+
+func (o *operation) check() error {
+	if _, err := pointer(o.Path); err != nil {
+		return err
+	}
+	switch o.Op {
+	case "add", "replace", "test":
+		if o.Value == nil {
+			return fmt.Errorf("%s without value", o.Op)
+		}
+	case "move", "copy":
+		if _, err := pointer(o.From); err != nil {
+			return err
+		}
+		if o.Op == "move" && strings.HasPrefix(o.Path, o.From+"/") {
+			return errors.New("move into a child of from")
+		}
+	case "remove":
+	default:
+		return fmt.Errorf("unknown op %q", o.Op)
+	}
+	return nil
+}
+
+func (p jsonPatch) apply(doc interface{}) (interface{}, error) {
+	for i := range p {
+		var err error
+		if doc, err = p[i].apply(doc); err != nil {
+			if errors.Is(err, ErrTestFailed) {
+				return nil, fmt.Errorf("%w: operation %d at %s", err, i, p[i].Path)
+			}
+			return nil, fmt.Errorf("%w: operation %d at %s: %s", ErrInvalidPatch, i, p[i].Path, err)
+		}
+	}
+	return doc, nil
+}
+
+func (o *operation) apply(doc interface{}) (interface{}, error) {
+	path, _ := pointer(o.Path)
+	switch o.Op {
+	case "add":
+		value, err := o.value()
+		if err != nil {
+			return nil, err
+		}
+		return add(doc, path, value)
+	case "remove":
+		doc, _, err := remove(doc, path)
+		return doc, err
+	case "replace":
+		value, err := o.value()
+		if err != nil {
+			return nil, err
+		}
+		if doc, _, err = remove(doc, path); err != nil {
+			return nil, err
+		}
+		return add(doc, path, value)
+	case "move":
+		from, _ := pointer(o.From)
+		doc, value, err := remove(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		return add(doc, path, value)
+	case "copy":
+		from, _ := pointer(o.From)
+		value, err := get(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		// the copy must not share containers with the source
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		var clone interface{}
+		if err := decode(data, &clone); err != nil {
+			return nil, err
+		}
+		return add(doc, path, clone)
+	case "test":
+		value, err := o.value()
+		if err != nil {
+			return nil, err
+		}
+		current, err := get(doc, path)
+		if err != nil || !equal(current, value) {
+			return nil, ErrTestFailed
+		}
+		return doc, nil
+	}
+	return nil, fmt.Errorf("unknown op %q", o.Op)
+}
+
+func (o *operation) value() (interface{}, error) {
+	var value interface{}
+	if err := decode(*o.Value, &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// pointer splits the JSON Pointer path, RFC 6901, into its reference tokens
+func pointer(path string) ([]string, error) {
+	if path == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(path, "/") {
+		return nil, fmt.Errorf("invalid pointer %q", path)
+	}
+	tokens := strings.Split(path[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+// index is the array index of token, end is the index of "-"
+func index(token string, end, max int) (int, error) {
+	if token == "-" && end >= 0 {
+		return end, nil
+	}
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || i > max || (len(token) > 1 && token[0] == '0') {
+		return 0, errPath
+	}
+	return i, nil
+}
+
+func get(node interface{}, path []string) (interface{}, error) {
+	for _, token := range path {
+		switch n := node.(type) {
+		case map[string]interface{}:
+			child, ok := n[token]
+			if !ok {
+				return nil, errPath
+			}
+			node = child
+		case []interface{}:
+			i, err := index(token, -1, len(n)-1)
+			if err != nil {
+				return nil, err
+			}
+			node = n[i]
+		default:
+			return nil, errPath
+		}
+	}
+	return node, nil
+}
+
+// add adds value at path of node and returns node, which is replaced when
+// path is the root and reallocated when it is an array
+func add(node interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	token, last := path[0], len(path) == 1
+	switch n := node.(type) {
+	case map[string]interface{}:
+		if last {
+			n[token] = value
+			return n, nil
+		}
+		child, ok := n[token]
+		if !ok {
+			return nil, errPath
+		}
+		child, err := add(child, path[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		n[token] = child
+		return n, nil
+	case []interface{}:
+		if last {
+			i, err := index(token, len(n), len(n))
+			if err != nil {
+				return nil, err
+			}
+			n = append(n, nil)
+			copy(n[i+1:], n[i:])
+			n[i] = value
+			return n, nil
+		}
+		i, err := index(token, -1, len(n)-1)
+		if err != nil {
+			return nil, err
+		}
+		child, err := add(n[i], path[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		n[i] = child
+		return n, nil
+	}
+	return nil, errPath
+}
+
+// remove removes the value at path of node and returns node and the value
+func remove(node interface{}, path []string) (interface{}, interface{}, error) {
+	if len(path) == 0 {
+		return nil, nil, errors.New("cannot remove the document")
+	}
+	token, last := path[0], len(path) == 1
+	switch n := node.(type) {
+	case map[string]interface{}:
+		child, ok := n[token]
+		if !ok {
+			return nil, nil, errPath
+		}
+		if last {
+			delete(n, token)
+			return n, child, nil
+		}
+		child, removed, err := remove(child, path[1:])
+		if err != nil {
+			return nil, nil, err
+		}
+		n[token] = child
+		return n, removed, nil
+	case []interface{}:
+		i, err := index(token, -1, len(n)-1)
+		if err != nil {
+			return nil, nil, err
+		}
+		if last {
+			removed := n[i]
+			return append(n[:i], n[i+1:]...), removed, nil
+		}
+		child, removed, err := remove(n[i], path[1:])
+		if err != nil {
+			return nil, nil, err
+		}
+		n[i] = child
+		return n, removed, nil
+	}
+	return nil, nil, errPath
+}
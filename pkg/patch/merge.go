@@ -0,0 +1,30 @@
+package patch
+
+// mergePatch is a JSON Merge Patch, RFC 7396: its members replace those of
+// the document, objects are merged and nulls remove members
+type mergePatch struct {
+	doc interface{}
+}
+
+func (p mergePatch) apply(doc interface{}) (interface{}, error) {
+	return merge(doc, p.doc), nil
+}
+
+func merge(target, patch interface{}) interface{} {
+	members, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	object, ok := target.(map[string]interface{})
+	if !ok {
+		object = make(map[string]interface{}, len(members))
+	}
+	for k, v := range members {
+		if v == nil {
+			delete(object, k)
+			continue
+		}
+		object[k] = merge(object[k], v)
+	}
+	return object
+}
@@ -0,0 +1,240 @@
+package patch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+)
+
+const (
+	// MergePatchType is the media type of JSON Merge Patch, RFC 7396
+	MergePatchType = "application/merge-patch+json"
+	// JSONPatchType is the media type of JSON Patch, RFC 6902
+	JSONPatchType = "application/json-patch+json"
+
+	maxBodySize = 1 << 20
+)
+
+var (
+	ErrUnsupportedType = errors.New("unsupported patch media type, use application/merge-patch+json or application/json-patch+json")
+	ErrMalformed       = errors.New("malformed patch")
+	ErrInvalidPatch    = errors.New("invalid patch")
+	ErrTestFailed      = errors.New("patch test failed")
+)
+
+// A Patch changes a JSON document. Services apply it to the editable fields of
+// a record with Apply, so that the fields it does not change are kept.
+type Patch interface {
+	apply(doc interface{}) (interface{}, error)
+}
+
+// Parse parses body as a patch of contentType. Plain JSON bodies are merge
+// patches.
+func Parse(contentType string, body []byte) (Patch, error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, ErrUnsupportedType
+	}
+
+	switch mediaType {
+	case MergePatchType, "application/json":
+		var doc interface{}
+		if err := decode(body, &doc); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrMalformed, err)
+		}
+		return mergePatch{doc: doc}, nil
+	case JSONPatchType:
+		var ops []operation
+		if err := decode(body, &ops); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrMalformed, err)
+		}
+		for i := range ops {
+			if err := ops[i].check(); err != nil {
+				return nil, fmt.Errorf("%w: operation %d: %s", ErrMalformed, i, err)
+			}
+		}
+		return jsonPatch(ops), nil
+	}
+	return nil, ErrUnsupportedType
+}
+
+// FromRequest parses the body of r as a patch of its Content-Type
+func FromRequest(r *http.Request) (Patch, error) {
+	if r.Body == nil {
+		return nil, fmt.Errorf("%w: empty body", ErrMalformed)
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize+1))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrMalformed, err)
+	}
+	if len(body) > maxBodySize {
+		return nil, fmt.Errorf("%w: body too large", ErrMalformed)
+	}
+	return Parse(r.Header.Get("Content-Type"), body)
+}
+
+// Merge is the merge patch setting fields, the gRPC field masks are turned
+// into one
+func Merge(fields map[string]interface{}) Patch {
+	doc := make(map[string]interface{}, len(fields))
+	for k, v := range fields {
+		doc[k] = v
+	}
+	return mergePatch{doc: doc}
+}
+
+// StatusCode is the HTTP status of the errors of Parse and Apply, 0 for the
+// other errors
+func StatusCode(err error) int {
+	switch {
+	case errors.Is(err, ErrUnsupportedType):
+		return http.StatusUnsupportedMediaType
+	case errors.Is(err, ErrMalformed):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrInvalidPatch):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, ErrTestFailed):
+		return http.StatusConflict
+	}
+	return 0
+}
+
+// Invalid wraps err, the validation error of the patched fields, in
+// ErrInvalidPatch
+func Invalid(err error) error {
+	return fmt.Errorf("%w: %s", ErrInvalidPatch, err)
+}
+
+// Apply applies p to the JSON document of current, the editable fields of a
+// record, and decodes the top-level fields it changed into changes, a struct
+// of pointers to only get the provided fields. Fields removed by p are reset
+// to their zero value. Changes to fields that changes does not have fail with
+// ErrInvalidPatch.
+func Apply(p Patch, current, changes interface{}) error {
+	original, err := document(current)
+	if err != nil {
+		return err
+	}
+	doc, err := document(current)
+	if err != nil {
+		return err
+	}
+	patched, err := p.apply(doc)
+	if err != nil {
+		return err
+	}
+	object, ok := patched.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("%w: the result is not an object", ErrInvalidPatch)
+	}
+
+	// round trip through the type of current so that the removed fields get
+	// their zero value and the values their canonical form
+	encoded, err := json.Marshal(object)
+	if err != nil {
+		return err
+	}
+	view := reflect.New(reflect.TypeOf(current).Elem()).Interface()
+	if err := json.Unmarshal(encoded, view); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidPatch, err)
+	}
+	normalized, err := document(view)
+	if err != nil {
+		return err
+	}
+
+	changed := make(map[string]interface{})
+	for k, v := range normalized.(map[string]interface{}) {
+		if !equal(v, original.(map[string]interface{})[k]) {
+			changed[k] = v
+		}
+	}
+	// unknown fields are kept so that decoding them fails
+	for k, v := range object {
+		if _, ok := normalized.(map[string]interface{})[k]; !ok {
+			changed[k] = v
+		}
+	}
+
+	encoded, err = json.Marshal(changed)
+	if err != nil {
+		return err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(changes); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidPatch, err)
+	}
+	return nil
+}
+
+func decode(data []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(v); err != nil {
+		return err
+	}
+	if decoder.More() {
+		return errors.New("trailing data after the document")
+	}
+	return nil
+}
+
+// document is the generic JSON document of v
+func document(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var doc interface{}
+	if err := decode(data, &doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// equal compares JSON values, numbers by value
+func equal(a, b interface{}) bool {
+	switch a := a.(type) {
+	case json.Number:
+		b, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		if a == b {
+			return true
+		}
+		x, errA := a.Float64()
+		y, errB := b.Float64()
+		return errA == nil && errB == nil && x == y
+	case map[string]interface{}:
+		b, ok := b.(map[string]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for k, v := range a {
+			w, ok := b[k]
+			if !ok || !equal(v, w) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		b, ok := b.([]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !equal(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
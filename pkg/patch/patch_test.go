@@ -0,0 +1,103 @@
+package patch
+
+import (
+	"errors"
+	"testing"
+)
+
+type view struct {
+	Name       string  `json:"name"`
+	Price      float32 `json:"price"`
+	Experience int     `json:"experience"`
+}
+
+type changes struct {
+	Name       *string  `json:"name,omitempty"`
+	Price      *float32 `json:"price,omitempty"`
+	Experience *int     `json:"experience,omitempty"`
+}
+
+func TestApply(t *testing.T) {
+	current := &view{Name: "Dr. Who", Price: 10, Experience: 7}
+	tests := []struct {
+		contentType string
+		body        string
+		want        changes
+		err         error
+	}{
+		{contentType: MergePatchType, body: `{"price": 12.5}`, want: changes{Price: ptr(float32(12.5))}},
+		{contentType: "application/json", body: `{"price": 10, "experience": 8}`, want: changes{Experience: ptr(8)}},
+		{contentType: MergePatchType, body: `{"experience": null}`, want: changes{Experience: ptr(0)}},
+		{contentType: MergePatchType, body: `{"id": "1"}`, err: ErrInvalidPatch},
+		{contentType: MergePatchType, body: `{"price": "free"}`, err: ErrInvalidPatch},
+		{contentType: MergePatchType, body: `{"price":`, err: ErrMalformed},
+		{contentType: JSONPatchType, body: `[{"op": "replace", "path": "/name", "value": "Dr. No"}]`, want: changes{Name: ptr("Dr. No")}},
+		{contentType: JSONPatchType, body: `[{"op": "test", "path": "/experience", "value": 7}, {"op": "move", "from": "/experience", "path": "/price"}]`, want: changes{Price: ptr(float32(7)), Experience: ptr(0)}},
+		{contentType: JSONPatchType, body: `[{"op": "test", "path": "/experience", "value": 6}]`, err: ErrTestFailed},
+		{contentType: JSONPatchType, body: `[{"op": "remove", "path": "/missing"}]`, err: ErrInvalidPatch},
+		{contentType: JSONPatchType, body: `[{"op": "add", "path": "/name"}]`, err: ErrMalformed},
+		{contentType: "text/plain", body: `{}`, err: ErrUnsupportedType},
+	}
+	for _, tt := range tests {
+		var got changes
+		p, err := Parse(tt.contentType, []byte(tt.body))
+		if err == nil {
+			err = Apply(p, current, &got)
+		}
+		if !errors.Is(err, tt.err) {
+			t.Errorf("%s %s: error %v, want %v", tt.contentType, tt.body, err, tt.err)
+			continue
+		}
+		if tt.err == nil && !equalChanges(got, tt.want) {
+			t.Errorf("%s %s: got %+v, want %+v", tt.contentType, tt.body, got, tt.want)
+		}
+	}
+	if current.Name != "Dr. Who" || current.Experience != 7 {
+		t.Errorf("current was changed: %+v", current)
+	}
+}
+
+func TestJSONPatchArrays(t *testing.T) {
+	p, err := Parse(JSONPatchType, []byte(`[
+		{"op": "add", "path": "/tags/-", "value": "c"},
+		{"op": "add", "path": "/tags/0", "value": "z"},
+		{"op": "remove", "path": "/tags/1"},
+		{"op": "copy", "from": "/tags/0", "path": "/a~1b"}
+	]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := p.apply(map[string]interface{}{"tags": []interface{}{"a", "b"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{"tags": []interface{}{"z", "b", "c"}, "a/b": "z"}
+	if !equal(doc, want) {
+		t.Errorf("got %v, want %v", doc, want)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	var got changes
+	if err := Apply(Merge(map[string]interface{}{"name": "Dr. No", "price": float32(10)}), &view{Price: 10}, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !equalChanges(got, changes{Name: ptr("Dr. No")}) {
+		t.Errorf("got %+v", got)
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func equalChanges(a, b changes) bool {
+	return equalPtr(a.Name, b.Name) && equalPtr(a.Price, b.Price) && equalPtr(a.Experience, b.Experience)
+}
+
+func equalPtr[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
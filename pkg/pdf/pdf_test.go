@@ -0,0 +1,63 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+)
+
+func TestDocumentXref(t *testing.T) {
+	doc := New()
+	page := doc.AddPage()
+	page.Text(50, 50, HelveticaBold, 18, "Prescription")
+	page.Line(50, 60, 545, 60, 1)
+	doc.AddPage().Bitmap(50, 50, 100, [][]bool{{true, false}, {false, true}})
+
+	data := doc.Bytes()
+	if !bytes.HasPrefix(data, []byte("%PDF-1.4")) || !bytes.HasSuffix(data, []byte("%%EOF\n")) {
+		t.Fatal("missing header or trailer")
+	}
+
+	// every xref entry points at its object
+	entries := regexp.MustCompile(`(\d{10}) 00000 n`).FindAllSubmatch(data, -1)
+	if len(entries) != 8 {
+		t.Fatalf("got %d objects, want 8", len(entries))
+	}
+	for i, entry := range entries {
+		offset, _ := strconv.Atoi(string(entry[1]))
+		want := fmt.Sprintf("%d 0 obj", i+1)
+		if !bytes.HasPrefix(data[offset:], []byte(want)) {
+			t.Errorf("offset of object %d points at %q", i+1, data[offset:offset+10])
+		}
+	}
+
+	startxref := regexp.MustCompile(`startxref\n(\d+)`).FindSubmatch(data)
+	offset, _ := strconv.Atoi(string(startxref[1]))
+	if !bytes.HasPrefix(data[offset:], []byte("xref")) {
+		t.Error("startxref does not point at the xref table")
+	}
+}
+
+func TestEscape(t *testing.T) {
+	cases := map[string]string{
+		`a (b) \c`: `a \(b\) \\c`,
+		"café":     "caf\xe9",
+		"日本":       "??",
+		"a\tb":     "a?b",
+	}
+	for text, want := range cases {
+		if got := escape(text); got != want {
+			t.Errorf("escape(%q) = %q, want %q", text, got, want)
+		}
+	}
+}
+
+func TestWrap(t *testing.T) {
+	lines := Wrap("take one tablet twice a day\nwith food", 10, 60)
+	want := []string{"take one", "tablet", "twice a", "day", "with food"}
+	if fmt.Sprint(lines) != fmt.Sprint(want) {
+		t.Errorf("Wrap = %q, want %q", lines, want)
+	}
+}
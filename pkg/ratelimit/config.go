@@ -0,0 +1,57 @@
+package ratelimit
+
+import (
+	"github.com/quangdangfit/gocommon/logger"
+
+	"main/pkg/config"
+	"main/pkg/redis"
+)
+
+// Rule names, also used in the Redis keys
+const (
+	RuleDefault  = "default"
+	RuleLogin    = "login"
+	RuleRegister = "register"
+	RuleVerify   = "verify"
+)
+
+// Rules are the rules applied per route group
+type Rules struct {
+	// Default applies to every API request per client IP
+	Default Rule
+	// Login applies to login endpoints per IP and per email
+	Login Rule
+	// Register applies to registration endpoints per IP
+	Register Rule
+	// Verify applies to verification code endpoints per IP and per user
+	Verify Rule
+}
+
+// RulesFromConfig parses the rules of the configuration. Invalid rules are
+// logged and disabled.
+func RulesFromConfig(cfg *config.Schema) Rules {
+	return Rules{
+		Default:  parseOrDisable(RuleDefault, cfg.RateLimitDefault),
+		Login:    parseOrDisable(RuleLogin, cfg.RateLimitLogin),
+		Register: parseOrDisable(RuleRegister, cfg.RateLimitRegister),
+		Verify:   parseOrDisable(RuleVerify, cfg.RateLimitVerify),
+	}
+}
+
+// LockoutFromConfig creates the account lockout of the configuration
+func LockoutFromConfig(cache redis.IRedis, cfg *config.Schema) *Lockout {
+	return NewLockout(cache, LockoutConfig{
+		Threshold:   cfg.LockoutThreshold,
+		Duration:    cfg.LockoutDuration,
+		MaxDuration: cfg.LockoutMaxDuration,
+	})
+}
+
+func parseOrDisable(name, value string) Rule {
+	rule, err := ParseRule(name, value)
+	if err != nil {
+		logger.Error("Invalid rate limit rule, disabling it: ", err)
+		return Rule{Name: name}
+	}
+	return rule
+}
@@ -0,0 +1,121 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"main/pkg/redis"
+)
+
+const keyPrefix = "ratelimit:"
+
+// Rule allows Limit requests per key in any sliding Window. A zero Limit
+// disables the rule.
+type Rule struct {
+	Name   string
+	Limit  int
+	Window time.Duration
+}
+
+// ParseRule parses a "<limit>/<window>" rule such as "10/1m" or "100/h".
+// An empty value returns a disabled rule.
+func ParseRule(name, value string) (Rule, error) {
+	rule := Rule{Name: name}
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return rule, nil
+	}
+
+	parts := strings.SplitN(value, "/", 2)
+	if len(parts) != 2 {
+		return rule, fmt.Errorf("ratelimit: invalid rule %q, expected <limit>/<window>", value)
+	}
+
+	limit, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil || limit < 0 {
+		return rule, fmt.Errorf("ratelimit: invalid limit in rule %q", value)
+	}
+
+	window := strings.TrimSpace(parts[1])
+	if window != "" && (window[0] < '0' || window[0] > '9') {
+		// "m" is shorthand for "1m"
+		window = "1" + window
+	}
+	duration, err := time.ParseDuration(window)
+	if err != nil || duration <= 0 {
+		return rule, fmt.Errorf("ratelimit: invalid window in rule %q", value)
+	}
+
+	rule.Limit = limit
+	rule.Window = duration
+	return rule, nil
+}
+
+// Enabled reports whether the rule limits anything
+func (r Rule) Enabled() bool {
+	return r.Limit > 0 && r.Window > 0
+}
+
+// Result describes the state of a key after a request was counted
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset is the time until the oldest request leaves the window
+	Reset time.Duration
+	// RetryAfter is set when the request was rejected
+	RetryAfter time.Duration
+}
+
+// Limiter is a sliding window rate limiter backed by Redis, with an
+// in-memory fallback used when Redis is unavailable
+type Limiter struct {
+	store store
+	now   func() time.Time
+}
+
+// New creates a limiter on top of the cache. A nil cache keeps every counter
+// in memory, which only limits per instance.
+func New(cache redis.IRedis) *Limiter {
+	return &Limiter{
+		store: newStore(cache),
+		now:   time.Now,
+	}
+}
+
+// Allow counts a request for key under rule and reports whether it is allowed
+func (l *Limiter) Allow(ctx context.Context, rule Rule, key string) Result {
+	if !rule.Enabled() {
+		return Result{Allowed: true}
+	}
+
+	now := l.now()
+	allowed, count, oldest, err := l.store.hit(ctx, keyPrefix+rule.Name+":"+key, now, rule.Window, rule.Limit)
+	if err != nil {
+		// never lock everybody out because the limiter is broken
+		return Result{Allowed: true, Limit: rule.Limit, Remaining: rule.Limit}
+	}
+
+	reset := oldest.Add(rule.Window).Sub(now)
+	if reset < 0 {
+		reset = 0
+	}
+
+	result := Result{
+		Allowed:   allowed,
+		Limit:     rule.Limit,
+		Remaining: rule.Limit - count,
+		Reset:     reset,
+	}
+	if result.Remaining < 0 {
+		result.Remaining = 0
+	}
+	if !allowed {
+		result.RetryAfter = reset
+	}
+
+	return result
+}
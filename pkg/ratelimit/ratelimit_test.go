@@ -0,0 +1,101 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseRule(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    Rule
+		wantErr bool
+	}{
+		{
+			name:  "empty disables",
+			value: "",
+			want:  Rule{Name: "test"},
+		},
+		{
+			name:  "limit per minute",
+			value: "10/1m",
+			want:  Rule{Name: "test", Limit: 10, Window: time.Minute},
+		},
+		{
+			name:  "unit shorthand",
+			value: "100/h",
+			want:  Rule{Name: "test", Limit: 100, Window: time.Hour},
+		},
+		{
+			name:    "missing window",
+			value:   "10",
+			wantErr: true,
+		},
+		{
+			name:    "invalid window",
+			value:   "10/0s",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRule("test", tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRule() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("ParseRule() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLimiterAllow(t *testing.T) {
+	now := time.Now()
+	limiter := New(nil)
+	limiter.now = func() time.Time { return now }
+	rule := Rule{Name: "test", Limit: 2, Window: time.Minute}
+
+	for i := 0; i < 2; i++ {
+		if res := limiter.Allow(context.Background(), rule, "key"); !res.Allowed {
+			t.Fatalf("request %d rejected", i)
+		}
+	}
+
+	res := limiter.Allow(context.Background(), rule, "key")
+	if res.Allowed || res.RetryAfter != time.Minute {
+		t.Fatalf("Allow() = %+v, want rejected for a minute", res)
+	}
+
+	now = now.Add(time.Minute + time.Millisecond)
+	if res := limiter.Allow(context.Background(), rule, "key"); !res.Allowed {
+		t.Fatalf("request rejected after the window")
+	}
+}
+
+func TestLockout(t *testing.T) {
+	ctx := context.Background()
+	lockout := NewLockout(nil, LockoutConfig{Threshold: 2, Duration: time.Minute, MaxDuration: 3 * time.Minute})
+
+	if err := lockout.Fail(ctx, "key"); err != nil {
+		t.Fatalf("first failure locked the key: %v", err)
+	}
+
+	var lockedErr *LockedError
+	for _, want := range []time.Duration{time.Minute, 2 * time.Minute, 3 * time.Minute} {
+		if err := lockout.Fail(ctx, "key"); !errors.As(err, &lockedErr) || lockedErr.RetryAfter != want {
+			t.Fatalf("Fail() = %v, want lock for %s", err, want)
+		}
+	}
+	if err := lockout.Check(ctx, "key"); !errors.As(err, &lockedErr) {
+		t.Fatalf("Check() = %v, want locked", err)
+	}
+
+	lockout.Reset(ctx, "key")
+	if err := lockout.Check(ctx, "key"); err != nil {
+		t.Fatalf("Check() after reset = %v", err)
+	}
+}
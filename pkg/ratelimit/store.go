@@ -0,0 +1,278 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	goredis "github.com/go-redis/redis/v8"
+
+	"main/pkg/redis"
+)
+
+// store keeps the counters behind the limiter and the lockout
+type store interface {
+	// hit records a request at now in the sliding window of key when fewer
+	// than limit requests were recorded, and returns the number of requests
+	// in the window and the time of the oldest one
+	hit(ctx context.Context, key string, now time.Time, window time.Duration, limit int) (allowed bool, count int, oldest time.Time, err error)
+	incr(ctx context.Context, key string, ttl time.Duration) (int64, error)
+	lock(ctx context.Context, key string, ttl time.Duration) error
+	ttl(ctx context.Context, key string) (time.Duration, error)
+	remove(ctx context.Context, keys ...string) error
+}
+
+// slidingWindowScript implements a sliding window log on a sorted set whose
+// scores are request timestamps in milliseconds
+var slidingWindowScript = goredis.NewScript(`
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+redis.call("ZREMRANGEBYSCORE", KEYS[1], "-inf", now - window)
+local count = redis.call("ZCARD", KEYS[1])
+local allowed = 0
+if count < limit then
+	redis.call("ZADD", KEYS[1], now, ARGV[4])
+	count = count + 1
+	allowed = 1
+end
+redis.call("PEXPIRE", KEYS[1], window)
+local oldest = redis.call("ZRANGE", KEYS[1], 0, 0, "WITHSCORES")
+local oldestScore = now
+if oldest[2] then
+	oldestScore = tonumber(oldest[2])
+end
+return {allowed, count, oldestScore}
+`)
+
+// memberSeq makes sorted set members unique for requests in the same instant
+var memberSeq atomic.Uint64
+
+type redisStore struct {
+	cache redis.IRedis
+}
+
+func (s *redisStore) hit(ctx context.Context, key string, now time.Time, window time.Duration, limit int) (bool, int, time.Time, error) {
+	member := fmt.Sprintf("%d-%d", now.UnixNano(), memberSeq.Add(1))
+	result, err := s.cache.RunScript(ctx, slidingWindowScript, []string{key},
+		now.UnixMilli(), window.Milliseconds(), limit, member)
+	if err != nil {
+		return false, 0, time.Time{}, err
+	}
+
+	values, ok := result.([]interface{})
+	if !ok || len(values) != 3 {
+		return false, 0, time.Time{}, fmt.Errorf("ratelimit: unexpected script result %v", result)
+	}
+	allowed, _ := values[0].(int64)
+	count, _ := values[1].(int64)
+	oldest, _ := values[2].(int64)
+
+	return allowed == 1, int(count), time.UnixMilli(oldest), nil
+}
+
+func (s *redisStore) incr(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	return s.cache.Incr(ctx, key, ttl)
+}
+
+func (s *redisStore) lock(ctx context.Context, key string, ttl time.Duration) error {
+	return s.cache.SetWithExpiration(ctx, key, true, ttl)
+}
+
+func (s *redisStore) ttl(ctx context.Context, key string) (time.Duration, error) {
+	ttl, err := s.cache.TTL(ctx, key)
+	if err != nil {
+		return 0, err
+	}
+	// -2 means missing, -1 means no expiration: neither is an active lock
+	if ttl < 0 {
+		return 0, nil
+	}
+
+	return ttl, nil
+}
+
+func (s *redisStore) remove(ctx context.Context, keys ...string) error {
+	return s.cache.Remove(ctx, keys...)
+}
+
+// memoryStore is the per-instance fallback used while Redis is unavailable
+type memoryStore struct {
+	mu       sync.Mutex
+	windows  map[string][]time.Time
+	counters map[string]memoryCounter
+	lastGC   time.Time
+}
+
+type memoryCounter struct {
+	value     int64
+	expiresAt time.Time
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{
+		windows:  make(map[string][]time.Time),
+		counters: make(map[string]memoryCounter),
+	}
+}
+
+func (s *memoryStore) hit(_ context.Context, key string, now time.Time, window time.Duration, limit int) (bool, int, time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.gc(now)
+
+	hits := prune(s.windows[key], now.Add(-window))
+	allowed := len(hits) < limit
+	if allowed {
+		hits = append(hits, now)
+	}
+	s.windows[key] = hits
+
+	oldest := now
+	if len(hits) > 0 {
+		oldest = hits[0]
+	}
+
+	return allowed, len(hits), oldest, nil
+}
+
+func (s *memoryStore) incr(_ context.Context, key string, ttl time.Duration) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.gc(now)
+
+	counter, ok := s.counters[key]
+	if !ok || (!counter.expiresAt.IsZero() && now.After(counter.expiresAt)) {
+		counter = memoryCounter{}
+		if ttl > 0 {
+			counter.expiresAt = now.Add(ttl)
+		}
+	}
+	counter.value++
+	s.counters[key] = counter
+
+	return counter.value, nil
+}
+
+func (s *memoryStore) lock(_ context.Context, key string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.counters[key] = memoryCounter{value: 1, expiresAt: time.Now().Add(ttl)}
+	return nil
+}
+
+func (s *memoryStore) ttl(_ context.Context, key string) (time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	counter, ok := s.counters[key]
+	if !ok || counter.expiresAt.IsZero() {
+		return 0, nil
+	}
+
+	ttl := time.Until(counter.expiresAt)
+	if ttl < 0 {
+		return 0, nil
+	}
+
+	return ttl, nil
+}
+
+func (s *memoryStore) remove(_ context.Context, keys ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, key := range keys {
+		delete(s.counters, key)
+		delete(s.windows, key)
+	}
+
+	return nil
+}
+
+// gc drops expired entries at most once a minute so idle keys do not pile up
+func (s *memoryStore) gc(now time.Time) {
+	if now.Sub(s.lastGC) < time.Minute {
+		return
+	}
+	s.lastGC = now
+
+	for key, counter := range s.counters {
+		if !counter.expiresAt.IsZero() && now.After(counter.expiresAt) {
+			delete(s.counters, key)
+		}
+	}
+	for key, hits := range s.windows {
+		// windows are at most a day long in practice
+		if len(hits) == 0 || now.Sub(hits[len(hits)-1]) > 24*time.Hour {
+			delete(s.windows, key)
+		}
+	}
+}
+
+func prune(hits []time.Time, from time.Time) []time.Time {
+	i := 0
+	for i < len(hits) && !hits[i].After(from) {
+		i++
+	}
+	return hits[i:]
+}
+
+// fallbackStore uses Redis so limits are shared between instances and falls
+// back to the local memory store whenever Redis fails
+type fallbackStore struct {
+	primary  store
+	fallback store
+}
+
+func newStore(cache redis.IRedis) store {
+	memory := newMemoryStore()
+	if cache == nil {
+		return memory
+	}
+
+	return &fallbackStore{
+		primary:  &redisStore{cache: cache},
+		fallback: memory,
+	}
+}
+
+func (s *fallbackStore) hit(ctx context.Context, key string, now time.Time, window time.Duration, limit int) (bool, int, time.Time, error) {
+	allowed, count, oldest, err := s.primary.hit(ctx, key, now, window, limit)
+	if err != nil {
+		return s.fallback.hit(ctx, key, now, window, limit)
+	}
+	return allowed, count, oldest, nil
+}
+
+func (s *fallbackStore) incr(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	value, err := s.primary.incr(ctx, key, ttl)
+	if err != nil {
+		return s.fallback.incr(ctx, key, ttl)
+	}
+	return value, nil
+}
+
+func (s *fallbackStore) lock(ctx context.Context, key string, ttl time.Duration) error {
+	// keep a local copy so the lock survives Redis going away
+	_ = s.fallback.lock(ctx, key, ttl)
+	return s.primary.lock(ctx, key, ttl)
+}
+
+func (s *fallbackStore) ttl(ctx context.Context, key string) (time.Duration, error) {
+	ttl, err := s.primary.ttl(ctx, key)
+	if err != nil || ttl == 0 {
+		return s.fallback.ttl(ctx, key)
+	}
+	return ttl, nil
+}
+
+func (s *fallbackStore) remove(ctx context.Context, keys ...string) error {
+	_ = s.fallback.remove(ctx, keys...)
+	return s.primary.remove(ctx, keys...)
+}
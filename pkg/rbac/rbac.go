@@ -0,0 +1,121 @@
+package rbac
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ServiceRole is the role of machine clients, its permissions are the scopes
+// of the client
+const ServiceRole = "service"
+
+// ClinicAdminRole manages a clinic, its permissions only apply to the rows of
+// the clinic
+const ClinicAdminRole = "clinic_admin"
+
+// Permissions granted to user roles, and to machine clients as scopes
+const (
+	// Profile covers the endpoints acting on the signed-in user itself, it
+	// cannot be granted to a machine client
+	Profile = "profile"
+
+	UsersRead      = "users:read"
+	UsersWrite     = "users:write"
+	UsersAdmin     = "users:admin"
+	DoctorsRead    = "doctors:read"
+	DoctorsWrite   = "doctors:write"
+	AddressesRead  = "addresses:read"
+	AddressesWrite = "addresses:write"
+	// FilesRead reads the files of every user
+	FilesRead = "files:read"
+	// SpecialtiesWrite manages the specialties catalogue and the specialties
+	// of every doctor
+	SpecialtiesWrite = "specialties:write"
+	// DoctorsVerify reviews doctor licenses, it cannot be granted to a machine
+	// client either
+	DoctorsVerify = "doctors:verify"
+	// ReviewsWrite reviews doctors as a patient and ReviewsModerate hides
+	// abusive reviews, neither can be granted to a machine client
+	ReviewsWrite    = "reviews:write"
+	ReviewsModerate = "reviews:moderate"
+	// ClinicsAdmin creates the clinics and their first admins, ClinicManage
+	// manages the clinic and the staff of a clinic admin. Neither can be
+	// granted to a machine client.
+	ClinicsAdmin = "clinics:admin"
+	ClinicManage = "clinic:manage"
+	// APIKeysManage cannot be granted to a machine client either, keys are
+	// only issued by admins
+	APIKeysManage = "api-keys:manage"
+	// AuditRead reads and exports the audit log, it cannot be granted to a
+	// machine client either
+	AuditRead = "audit:read"
+	// DeletedManage lists and restores the deleted users, doctors and
+	// addresses, it cannot be granted to a machine client either
+	DeletedManage = "deleted:manage"
+	// WebhooksManage manages the webhook subscriptions of the partners and
+	// reads their deliveries, it cannot be granted to a machine client either
+	WebhooksManage = "webhooks:manage"
+	// BroadcastsSend pushes a message to every connected user
+	BroadcastsSend = "broadcasts:send"
+)
+
+// Scopes are the permissions a machine client can be granted
+var Scopes = []string{
+	UsersRead,
+	UsersWrite,
+	UsersAdmin,
+	DoctorsRead,
+	DoctorsWrite,
+	AddressesRead,
+	AddressesWrite,
+	FilesRead,
+	SpecialtiesWrite,
+}
+
+// doctor and client keep the access they had before permissions existed
+var rolePermissions = map[string][]string{
+	"admin": append([]string{Profile, APIKeysManage, DoctorsVerify, ReviewsModerate, ClinicsAdmin, AuditRead, DeletedManage, WebhooksManage, BroadcastsSend}, Scopes...),
+	ClinicAdminRole: {
+		Profile, ClinicManage, UsersRead, UsersWrite, UsersAdmin, DoctorsRead, DoctorsWrite, AddressesRead, AddressesWrite,
+	},
+	"doctor": {
+		Profile, UsersRead, UsersWrite, DoctorsRead, DoctorsWrite, AddressesRead, AddressesWrite,
+	},
+	"client": {
+		Profile, UsersRead, UsersWrite, DoctorsRead, DoctorsWrite, AddressesRead, AddressesWrite, ReviewsWrite,
+	},
+}
+
+// ForRole returns the permissions of a user role, none for unknown roles
+func ForRole(role string) []string {
+	return rolePermissions[role]
+}
+
+// Has reports whether permissions include permission
+func Has(permissions []string, permission string) bool {
+	for _, p := range permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseScopes splits a space separated scope parameter and checks each scope
+// can be granted
+func ParseScopes(scope string) ([]string, error) {
+	scopes := strings.Fields(scope)
+	if err := ValidateScopes(scopes); err != nil {
+		return nil, err
+	}
+	return scopes, nil
+}
+
+func ValidateScopes(scopes []string) error {
+	for _, scope := range scopes {
+		if !Has(Scopes, scope) {
+			return fmt.Errorf("invalid scope %q", scope)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,223 @@
+// Package realtime pushes messages to the users connected to the gateway, over
+// WebSocket, Server-Sent Events or gRPC streams.
+//
+// Each user has a channel and all the users share the broadcast channel.
+// Messages are published on Redis pub/sub so every instance delivers them to
+// the connections it holds. Delivery is at most once: a client reconnecting
+// refetches what it shows instead of expecting the messages it missed.
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	goredis "github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"github.com/quangdangfit/gocommon/logger"
+)
+
+// Types of the messages
+const (
+	TypeVerification = "verification.status"
+	TypeProfile      = "profile.updated"
+	TypeBroadcast    = "broadcast"
+	TypeNotification = "notification.created"
+	// TypeHeartbeat keeps idle connections open through proxies
+	TypeHeartbeat = "heartbeat"
+)
+
+// Redis pub/sub channels
+const (
+	userChannelPrefix = "realtime:user:"
+	BroadcastChannel  = "realtime:broadcast"
+)
+
+// UserChannel is the Redis channel of the messages of userID
+func UserChannel(userID string) string {
+	return userChannelPrefix + userID
+}
+
+// Message is pushed to the connections of a user
+type Message struct {
+	ID     string          `json:"id"`
+	Type   string          `json:"type"`
+	Data   json.RawMessage `json:"data,omitempty"`
+	SentAt time.Time       `json:"sent_at"`
+}
+
+// NewMessage is a message of typ with data as json
+func NewMessage(typ string, data any) *Message {
+	msg := &Message{
+		ID:     uuid.New().String(),
+		Type:   typ,
+		SentAt: time.Now().UTC(),
+	}
+	if data != nil {
+		msg.Data, _ = json.Marshal(data)
+	}
+	return msg
+}
+
+// Subscription receives the messages of a user on C until it is closed. C is
+// also closed when the subscriber is too slow to keep up, the client must
+// reconnect.
+type Subscription struct {
+	UserID string
+	C      <-chan *Message
+
+	c    chan *Message
+	hub  *Hub
+	once sync.Once
+}
+
+// Close stops the subscription and closes C
+func (s *Subscription) Close() {
+	s.once.Do(func() {
+		s.hub.mu.Lock()
+		defer s.hub.mu.Unlock()
+		delete(s.hub.subs[s.UserID], s)
+		if len(s.hub.subs[s.UserID]) == 0 {
+			delete(s.hub.subs, s.UserID)
+		}
+		close(s.c)
+	})
+}
+
+// Hub delivers the messages to the subscriptions of the process
+type Hub struct {
+	// client is nil when the messages stay in the process
+	client goredis.UniversalClient
+	buffer int
+
+	mu   sync.RWMutex
+	subs map[string]map[*Subscription]struct{}
+}
+
+// NewHub publishes the messages on Redis pub/sub through client, or only to
+// the process when client is nil. Subscriptions buffer buffer messages.
+func NewHub(client goredis.UniversalClient, buffer int) *Hub {
+	if buffer <= 0 {
+		buffer = 1
+	}
+	return &Hub{
+		client: client,
+		buffer: buffer,
+		subs:   make(map[string]map[*Subscription]struct{}),
+	}
+}
+
+// Subscribe receives the messages of userID and the broadcasts
+func (h *Hub) Subscribe(userID string) *Subscription {
+	c := make(chan *Message, h.buffer)
+	s := &Subscription{UserID: userID, C: c, c: c, hub: h}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.subs[userID] == nil {
+		h.subs[userID] = make(map[*Subscription]struct{})
+	}
+	h.subs[userID][s] = struct{}{}
+	return s
+}
+
+// Send pushes msg to the connections of userID on every instance
+func (h *Hub) Send(ctx context.Context, userID string, msg *Message) error {
+	return h.publish(ctx, UserChannel(userID), userID, msg)
+}
+
+// Broadcast pushes msg to every connected user
+func (h *Hub) Broadcast(ctx context.Context, msg *Message) error {
+	return h.publish(ctx, BroadcastChannel, "", msg)
+}
+
+func (h *Hub) publish(ctx context.Context, channel, userID string, msg *Message) error {
+	if h.client == nil {
+		h.deliver(userID, msg)
+		return nil
+	}
+
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return h.client.Publish(ctx, channel, payload).Err()
+}
+
+// Run delivers the messages published on Redis by every instance until ctx
+// is done, then closes the subscriptions. It only waits for ctx without
+// Redis.
+func (h *Hub) Run(ctx context.Context) {
+	defer h.closeAll()
+	if h.client == nil {
+		<-ctx.Done()
+		return
+	}
+
+	ps := h.client.PSubscribe(ctx, UserChannel("*"), BroadcastChannel)
+	defer ps.Close()
+
+	messages := ps.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case m, ok := <-messages:
+			if !ok {
+				return
+			}
+			var msg Message
+			if err := json.Unmarshal([]byte(m.Payload), &msg); err != nil {
+				logger.Errorf("Realtime message of %s is invalid: %s", m.Channel, err)
+				continue
+			}
+			var userID string
+			if m.Channel != BroadcastChannel {
+				userID = strings.TrimPrefix(m.Channel, userChannelPrefix)
+			}
+			h.deliver(userID, &msg)
+		}
+	}
+}
+
+// deliver pushes msg to the subscriptions of userID, or all of them when
+// userID is empty
+func (h *Hub) deliver(userID string, msg *Message) {
+	var slow []*Subscription
+	h.mu.RLock()
+	for id, subs := range h.subs {
+		if userID != "" && id != userID {
+			continue
+		}
+		for s := range subs {
+			select {
+			case s.c <- msg:
+			default:
+				slow = append(slow, s)
+			}
+		}
+	}
+	h.mu.RUnlock()
+
+	for _, s := range slow {
+		logger.Infof("Realtime subscription of user %s is too slow, closing it", s.UserID)
+		s.Close()
+	}
+}
+
+func (h *Hub) closeAll() {
+	h.mu.RLock()
+	var subs []*Subscription
+	for _, userSubs := range h.subs {
+		for s := range userSubs {
+			subs = append(subs, s)
+		}
+	}
+	h.mu.RUnlock()
+
+	for _, s := range subs {
+		s.Close()
+	}
+}
@@ -0,0 +1,98 @@
+package realtime
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/quangdangfit/gocommon/logger"
+
+	"main/pkg/config"
+)
+
+func TestMain(m *testing.M) {
+	logger.Initialize(config.ProductionEnv)
+	os.Exit(m.Run())
+}
+
+func receive(t *testing.T, s *Subscription) *Message {
+	t.Helper()
+	select {
+	case msg := <-s.C:
+		return msg
+	case <-time.After(time.Second):
+		t.Fatalf("no message for %s", s.UserID)
+		return nil
+	}
+}
+
+func TestHubChannels(t *testing.T) {
+	ctx := context.Background()
+	hub := NewHub(nil, 4)
+	alice, alice2, bob := hub.Subscribe("alice"), hub.Subscribe("alice"), hub.Subscribe("bob")
+	defer bob.Close()
+
+	if err := hub.Send(ctx, "alice", NewMessage(TypeProfile, map[string]string{"action": "updated"})); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []*Subscription{alice, alice2} {
+		if msg := receive(t, s); msg.Type != TypeProfile || string(msg.Data) != `{"action":"updated"}` {
+			t.Errorf("message of %s = %+v", s.UserID, msg)
+		}
+	}
+	select {
+	case msg := <-bob.C:
+		t.Fatalf("bob got the message of alice: %+v", msg)
+	default:
+	}
+
+	alice2.Close()
+	if err := hub.Broadcast(ctx, NewMessage(TypeBroadcast, nil)); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []*Subscription{alice, bob} {
+		if msg := receive(t, s); msg.Type != TypeBroadcast {
+			t.Errorf("broadcast to %s = %+v", s.UserID, msg)
+		}
+	}
+	if _, ok := <-alice2.C; ok {
+		t.Error("closed subscription got the broadcast")
+	}
+}
+
+func TestHubSlowSubscriber(t *testing.T) {
+	ctx := context.Background()
+	hub := NewHub(nil, 2)
+	s := hub.Subscribe("alice")
+	for i := 0; i < 3; i++ {
+		_ = hub.Send(ctx, "alice", NewMessage(TypeProfile, nil))
+	}
+
+	var n int
+	for range s.C {
+		n++
+	}
+	if n != 2 {
+		t.Errorf("got %d messages before the close, want 2", n)
+	}
+	// closing it again is harmless
+	s.Close()
+}
+
+func TestHubRunClosesSubscriptions(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	hub := NewHub(nil, 1)
+	s := hub.Subscribe("alice")
+	done := make(chan struct{})
+	go func() {
+		hub.Run(ctx)
+		close(done)
+	}()
+
+	cancel()
+	<-done
+	if _, ok := <-s.C; ok {
+		t.Error("subscription still open after Run returned")
+	}
+}
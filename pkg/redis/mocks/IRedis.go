@@ -3,9 +3,13 @@
 package mocks
 
 import (
+	context "context"
+
 	mock "github.com/stretchr/testify/mock"
 
 	time "time"
+
+	redis "github.com/go-redis/redis/v8"
 )
 
 // IRedis is an autogenerated mock type for the IRedis type
@@ -13,13 +17,29 @@ type IRedis struct {
 	mock.Mock
 }
 
-// Get provides a mock function with given fields: key, value
-func (_m *IRedis) Get(key string, value interface{}) error {
-	ret := _m.Called(key, value)
+// Client provides a mock function with given fields:
+func (_m *IRedis) Client() redis.UniversalClient {
+	ret := _m.Called()
+
+	var r0 redis.UniversalClient
+	if rf, ok := ret.Get(0).(func() redis.UniversalClient); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(redis.UniversalClient)
+		}
+	}
+
+	return r0
+}
+
+// Close provides a mock function with given fields:
+func (_m *IRedis) Close() error {
+	ret := _m.Called()
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, interface{}) error); ok {
-		r0 = rf(key, value)
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -27,13 +47,89 @@ func (_m *IRedis) Get(key string, value interface{}) error {
 	return r0
 }
 
-// IsConnected provides a mock function with given fields:
-func (_m *IRedis) IsConnected() bool {
-	ret := _m.Called()
+// Expire provides a mock function with given fields: ctx, key, expiration
+func (_m *IRedis) Expire(ctx context.Context, key string, expiration time.Duration) error {
+	ret := _m.Called(ctx, key, expiration)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Duration) error); ok {
+		r0 = rf(ctx, key, expiration)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Get provides a mock function with given fields: ctx, key, value
+func (_m *IRedis) Get(ctx context.Context, key string, value interface{}) error {
+	ret := _m.Called(ctx, key, value)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, interface{}) error); ok {
+		r0 = rf(ctx, key, value)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Incr provides a mock function with given fields: ctx, key, expiration
+func (_m *IRedis) Incr(ctx context.Context, key string, expiration time.Duration) (int64, error) {
+	ret := _m.Called(ctx, key, expiration)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Duration) (int64, error)); ok {
+		return rf(ctx, key, expiration)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Duration) int64); ok {
+		r0 = rf(ctx, key, expiration)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Duration) error); ok {
+		r1 = rf(ctx, key, expiration)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IncrBy provides a mock function with given fields: ctx, key, value, expiration
+func (_m *IRedis) IncrBy(ctx context.Context, key string, value int64, expiration time.Duration) (int64, error) {
+	ret := _m.Called(ctx, key, value, expiration)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, time.Duration) (int64, error)); ok {
+		return rf(ctx, key, value, expiration)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, time.Duration) int64); ok {
+		r0 = rf(ctx, key, value, expiration)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64, time.Duration) error); ok {
+		r1 = rf(ctx, key, value, expiration)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IsConnected provides a mock function with given fields: ctx
+func (_m *IRedis) IsConnected(ctx context.Context) bool {
+	ret := _m.Called(ctx)
 
 	var r0 bool
-	if rf, ok := ret.Get(0).(func() bool); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context) bool); ok {
+		r0 = rf(ctx)
 	} else {
 		r0 = ret.Get(0).(bool)
 	}
@@ -41,25 +137,77 @@ func (_m *IRedis) IsConnected() bool {
 	return r0
 }
 
-// Keys provides a mock function with given fields: pattern
-func (_m *IRedis) Keys(pattern string) ([]string, error) {
-	ret := _m.Called(pattern)
+// Keys provides a mock function with given fields: ctx, pattern
+func (_m *IRedis) Keys(ctx context.Context, pattern string) ([]string, error) {
+	ret := _m.Called(ctx, pattern)
 
 	var r0 []string
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string) ([]string, error)); ok {
-		return rf(pattern)
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]string, error)); ok {
+		return rf(ctx, pattern)
 	}
-	if rf, ok := ret.Get(0).(func(string) []string); ok {
-		r0 = rf(pattern)
+	if rf, ok := ret.Get(0).(func(context.Context, string) []string); ok {
+		r0 = rf(ctx, pattern)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]string)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(pattern)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, pattern)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MGet provides a mock function with given fields: ctx, keys, values
+func (_m *IRedis) MGet(ctx context.Context, keys []string, values []interface{}) ([]bool, error) {
+	ret := _m.Called(ctx, keys, values)
+
+	var r0 []bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string, []interface{}) ([]bool, error)); ok {
+		return rf(ctx, keys, values)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string, []interface{}) []bool); ok {
+		r0 = rf(ctx, keys, values)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]bool)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string, []interface{}) error); ok {
+		r1 = rf(ctx, keys, values)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Pipelined provides a mock function with given fields: ctx, fn
+func (_m *IRedis) Pipelined(ctx context.Context, fn func(redis.Pipeliner) error) ([]redis.Cmder, error) {
+	ret := _m.Called(ctx, fn)
+
+	var r0 []redis.Cmder
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, func(redis.Pipeliner) error) ([]redis.Cmder, error)); ok {
+		return rf(ctx, fn)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, func(redis.Pipeliner) error) []redis.Cmder); ok {
+		r0 = rf(ctx, fn)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]redis.Cmder)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, func(redis.Pipeliner) error) error); ok {
+		r1 = rf(ctx, fn)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -67,19 +215,20 @@ func (_m *IRedis) Keys(pattern string) ([]string, error) {
 	return r0, r1
 }
 
-// Remove provides a mock function with given fields: keys
-func (_m *IRedis) Remove(keys ...string) error {
+// Remove provides a mock function with given fields: ctx, keys
+func (_m *IRedis) Remove(ctx context.Context, keys ...string) error {
 	_va := make([]interface{}, len(keys))
 	for _i := range keys {
 		_va[_i] = keys[_i]
 	}
 	var _ca []interface{}
+	_ca = append(_ca, ctx)
 	_ca = append(_ca, _va...)
 	ret := _m.Called(_ca...)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(...string) error); ok {
-		r0 = rf(keys...)
+	if rf, ok := ret.Get(0).(func(context.Context, ...string) error); ok {
+		r0 = rf(ctx, keys...)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -87,13 +236,13 @@ func (_m *IRedis) Remove(keys ...string) error {
 	return r0
 }
 
-// RemovePattern provides a mock function with given fields: pattern
-func (_m *IRedis) RemovePattern(pattern string) error {
-	ret := _m.Called(pattern)
+// RemovePattern provides a mock function with given fields: ctx, pattern
+func (_m *IRedis) RemovePattern(ctx context.Context, pattern string) error {
+	ret := _m.Called(ctx, pattern)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string) error); ok {
-		r0 = rf(pattern)
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, pattern)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -101,13 +250,42 @@ func (_m *IRedis) RemovePattern(pattern string) error {
 	return r0
 }
 
-// Set provides a mock function with given fields: key, value
-func (_m *IRedis) Set(key string, value interface{}) error {
-	ret := _m.Called(key, value)
+// RunScript provides a mock function with given fields: ctx, script, keys, args
+func (_m *IRedis) RunScript(ctx context.Context, script *redis.Script, keys []string, args ...interface{}) (interface{}, error) {
+	var _ca []interface{}
+	_ca = append(_ca, ctx, script, keys)
+	_ca = append(_ca, args...)
+	ret := _m.Called(_ca...)
+
+	var r0 interface{}
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *redis.Script, []string, ...interface{}) (interface{}, error)); ok {
+		return rf(ctx, script, keys, args...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *redis.Script, []string, ...interface{}) interface{}); ok {
+		r0 = rf(ctx, script, keys, args...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(interface{})
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *redis.Script, []string, ...interface{}) error); ok {
+		r1 = rf(ctx, script, keys, args...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Set provides a mock function with given fields: ctx, key, value
+func (_m *IRedis) Set(ctx context.Context, key string, value interface{}) error {
+	ret := _m.Called(ctx, key, value)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, interface{}) error); ok {
-		r0 = rf(key, value)
+	if rf, ok := ret.Get(0).(func(context.Context, string, interface{}) error); ok {
+		r0 = rf(ctx, key, value)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -115,13 +293,37 @@ func (_m *IRedis) Set(key string, value interface{}) error {
 	return r0
 }
 
-// SetWithExpiration provides a mock function with given fields: key, value, expiration
-func (_m *IRedis) SetWithExpiration(key string, value interface{}, expiration time.Duration) error {
-	ret := _m.Called(key, value, expiration)
+// SetNX provides a mock function with given fields: ctx, key, value, expiration
+func (_m *IRedis) SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error) {
+	ret := _m.Called(ctx, key, value, expiration)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, interface{}, time.Duration) (bool, error)); ok {
+		return rf(ctx, key, value, expiration)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, interface{}, time.Duration) bool); ok {
+		r0 = rf(ctx, key, value, expiration)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, interface{}, time.Duration) error); ok {
+		r1 = rf(ctx, key, value, expiration)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetWithExpiration provides a mock function with given fields: ctx, key, value, expiration
+func (_m *IRedis) SetWithExpiration(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	ret := _m.Called(ctx, key, value, expiration)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, interface{}, time.Duration) error); ok {
-		r0 = rf(key, value, expiration)
+	if rf, ok := ret.Get(0).(func(context.Context, string, interface{}, time.Duration) error); ok {
+		r0 = rf(ctx, key, value, expiration)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -129,6 +331,30 @@ func (_m *IRedis) SetWithExpiration(key string, value interface{}, expiration ti
 	return r0
 }
 
+// TTL provides a mock function with given fields: ctx, key
+func (_m *IRedis) TTL(ctx context.Context, key string) (time.Duration, error) {
+	ret := _m.Called(ctx, key)
+
+	var r0 time.Duration
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (time.Duration, error)); ok {
+		return rf(ctx, key)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) time.Duration); ok {
+		r0 = rf(ctx, key)
+	} else {
+		r0 = ret.Get(0).(time.Duration)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewIRedis creates a new instance of IRedis. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewIRedis(t interface {
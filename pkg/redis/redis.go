@@ -3,6 +3,8 @@ package redis
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"sync/atomic"
 	"time"
 
 	goredis "github.com/go-redis/redis/v8"
@@ -11,130 +13,322 @@ import (
 )
 
 const (
-	Timeout = 1
+	// DefaultTimeout is applied to every operation when Config.Timeout is not set
+	DefaultTimeout = 1 * time.Second
+	// DefaultRetryInterval is how long the client stays in degraded mode after
+	// a connection error before it talks to Redis again
+	DefaultRetryInterval = 5 * time.Second
+	// ScanCount is the COUNT hint passed to SCAN when iterating keys
+	ScanCount = 500
+)
+
+var (
+	// ErrCacheMiss is returned by Get when the key does not exist or Redis is
+	// unavailable, so callers can fall back to the database in both cases
+	ErrCacheMiss = errors.New("redis: cache miss")
+	// ErrUnavailable is returned by write operations while Redis is down
+	ErrUnavailable = errors.New("redis: unavailable")
 )
 
 // IRedis interface
 //
 //go:generate mockery --name=IRedis
 type IRedis interface {
-	IsConnected() bool
-	Get(key string, value interface{}) error
-	Set(key string, value interface{}) error
-	SetWithExpiration(key string, value interface{}, expiration time.Duration) error
-	Remove(keys ...string) error
-	Keys(pattern string) ([]string, error)
-	RemovePattern(pattern string) error
+	IsConnected(ctx context.Context) bool
+	Get(ctx context.Context, key string, value interface{}) error
+	MGet(ctx context.Context, keys []string, values []interface{}) ([]bool, error)
+	Set(ctx context.Context, key string, value interface{}) error
+	SetWithExpiration(ctx context.Context, key string, value interface{}, expiration time.Duration) error
+	SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error)
+	Incr(ctx context.Context, key string, expiration time.Duration) (int64, error)
+	IncrBy(ctx context.Context, key string, value int64, expiration time.Duration) (int64, error)
+	Expire(ctx context.Context, key string, expiration time.Duration) error
+	TTL(ctx context.Context, key string) (time.Duration, error)
+	Remove(ctx context.Context, keys ...string) error
+	Keys(ctx context.Context, pattern string) ([]string, error)
+	RemovePattern(ctx context.Context, pattern string) error
+	Pipelined(ctx context.Context, fn func(pipe goredis.Pipeliner) error) ([]goredis.Cmder, error)
+	RunScript(ctx context.Context, script *goredis.Script, keys []string, args ...interface{}) (interface{}, error)
+	Client() goredis.UniversalClient
+	Close() error
 }
 
-// Config redis
-type Config struct {
-	Address  string
-	Password string
-	Database int
-}
+// incrScript increments a counter and sets its TTL only once, so a fixed
+// window is not extended by every hit
+var incrScript = goredis.NewScript(`
+local counter = redis.call("INCRBY", KEYS[1], ARGV[1])
+if tonumber(ARGV[2]) > 0 and redis.call("PTTL", KEYS[1]) < 0 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return counter
+`)
 
 type redis struct {
-	cmd goredis.Cmdable
+	cmd           goredis.UniversalClient
+	cluster       bool
+	timeout       time.Duration
+	retryInterval time.Duration
+	// downUntil holds the unix nano time until which Redis is considered
+	// unavailable; zero means the last operation succeeded
+	downUntil atomic.Int64
 }
 
-// New Redis interface with config
+// New Redis interface with config. A failed ping is logged and the client is
+// returned anyway: every operation degrades to a cache miss until Redis is
+// reachable again.
 func New(config Config) IRedis {
-	ctx, cancel := context.WithTimeout(context.Background(), Timeout*time.Second)
-	defer cancel()
-
-	rdb := goredis.NewClient(&goredis.Options{
-		Addr:     config.Address,
-		Password: config.Password,
-		DB:       config.Database,
-	})
-
-	pong, err := rdb.Ping(ctx).Result()
+	client, err := newUniversalClient(config)
 	if err != nil {
-		logger.Fatal(pong, err)
-		return nil
+		logger.Error("Failed to configure redis client: ", err)
+		return &redis{timeout: DefaultTimeout, retryInterval: DefaultRetryInterval}
 	}
 
-	return &redis{
-		cmd: rdb,
+	r := &redis{
+		cmd:           client,
+		cluster:       config.Mode == ModeCluster,
+		timeout:       config.Timeout,
+		retryInterval: config.RetryInterval,
 	}
+	if r.timeout <= 0 {
+		r.timeout = DefaultTimeout
+	}
+	if r.retryInterval <= 0 {
+		r.retryInterval = DefaultRetryInterval
+	}
+
+	if !r.IsConnected(context.Background()) {
+		logger.Warn("Redis is not reachable, caching is disabled until it recovers: ", config.Address)
+	}
+
+	return r
 }
 
-func (r *redis) IsConnected() bool {
-	ctx, cancel := context.WithTimeout(context.Background(), Timeout*time.Second)
-	defer cancel()
+func (r *redis) Client() goredis.UniversalClient {
+	return r.cmd
+}
 
+func (r *redis) Close() error {
 	if r.cmd == nil {
-		return false
+		return nil
 	}
+	return r.cmd.Close()
+}
 
-	_, err := r.cmd.Ping(ctx).Result()
-	if err != nil {
+func (r *redis) IsConnected(ctx context.Context) bool {
+	if r.cmd == nil {
 		return false
 	}
-	return true
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	err := r.cmd.Ping(ctx).Err()
+	r.track(err)
+	return err == nil
 }
 
-func (r *redis) Get(key string, value interface{}) error {
-	ctx, cancel := context.WithTimeout(context.Background(), Timeout*time.Second)
+func (r *redis) Get(ctx context.Context, key string, value interface{}) error {
+	if !r.available() {
+		return ErrCacheMiss
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
 	defer cancel()
 
 	strValue, err := r.cmd.Get(ctx, key).Result()
-	if err != nil {
-		return err
+	if r.track(err) != nil {
+		return ErrCacheMiss
 	}
 
-	err = json.Unmarshal([]byte(strValue), value)
-	if err != nil {
-		return err
+	return json.Unmarshal([]byte(strValue), value)
+}
+
+// MGet fetches several keys in one round trip and decodes each hit into the
+// value at the same index. The returned slice reports which keys were found.
+func (r *redis) MGet(ctx context.Context, keys []string, values []interface{}) ([]bool, error) {
+	found := make([]bool, len(keys))
+	if len(keys) == 0 {
+		return found, nil
+	}
+	if len(values) != len(keys) {
+		return nil, errors.New("redis: keys and values length mismatch")
+	}
+	if !r.available() {
+		return found, ErrCacheMiss
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	var results []interface{}
+	if r.cluster {
+		// keys may live in different hash slots, so fan out over a pipeline
+		cmds, err := r.cmd.Pipelined(ctx, func(pipe goredis.Pipeliner) error {
+			for _, key := range keys {
+				pipe.Get(ctx, key)
+			}
+			return nil
+		})
+		if err != nil && err != goredis.Nil {
+			r.track(err)
+			return found, ErrCacheMiss
+		}
+		results = make([]interface{}, len(cmds))
+		for i, cmd := range cmds {
+			if v, err := cmd.(*goredis.StringCmd).Result(); err == nil {
+				results[i] = v
+			}
+		}
+	} else {
+		var err error
+		results, err = r.cmd.MGet(ctx, keys...).Result()
+		if r.track(err) != nil {
+			return found, ErrCacheMiss
+		}
+	}
+
+	for i, result := range results {
+		strValue, ok := result.(string)
+		if !ok {
+			continue
+		}
+		if err := json.Unmarshal([]byte(strValue), values[i]); err == nil {
+			found[i] = true
+		}
 	}
 
-	return nil
+	return found, nil
 }
 
-func (r *redis) SetWithExpiration(key string, value interface{}, expiration time.Duration) error {
-	ctx, cancel := context.WithTimeout(context.Background(), Timeout*time.Second)
+func (r *redis) SetWithExpiration(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	if !r.available() {
+		return ErrUnavailable
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
 	defer cancel()
 
-	bData, _ := json.Marshal(value)
-	err := r.cmd.Set(ctx, key, bData, expiration).Err()
+	bData, err := json.Marshal(value)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	return r.track(r.cmd.Set(ctx, key, bData, expiration).Err())
+}
+
+func (r *redis) Set(ctx context.Context, key string, value interface{}) error {
+	return r.SetWithExpiration(ctx, key, value, 0)
 }
 
-func (r *redis) Set(key string, value interface{}) error {
-	ctx, cancel := context.WithTimeout(context.Background(), Timeout*time.Second)
+// SetNX stores the value only if the key does not exist yet and reports
+// whether it was stored
+func (r *redis) SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error) {
+	if !r.available() {
+		return false, ErrUnavailable
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
 	defer cancel()
 
-	bData, _ := json.Marshal(value)
-	err := r.cmd.Set(ctx, key, bData, 0).Err()
+	bData, err := json.Marshal(value)
 	if err != nil {
-		return err
+		return false, err
 	}
 
-	return nil
+	ok, err := r.cmd.SetNX(ctx, key, bData, expiration).Result()
+	if r.track(err) != nil {
+		return false, err
+	}
+
+	return ok, nil
+}
+
+// Incr atomically increments the counter at key. When expiration is set and
+// the key has no TTL yet, the expiration is applied in the same script.
+func (r *redis) Incr(ctx context.Context, key string, expiration time.Duration) (int64, error) {
+	return r.IncrBy(ctx, key, 1, expiration)
 }
 
-func (r *redis) Remove(keys ...string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), Timeout*time.Second)
+func (r *redis) IncrBy(ctx context.Context, key string, value int64, expiration time.Duration) (int64, error) {
+	if !r.available() {
+		return 0, ErrUnavailable
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
 	defer cancel()
 
-	err := r.cmd.Del(ctx, keys...).Err()
-	if err != nil {
-		return err
+	counter, err := incrScript.Run(ctx, r.cmd, []string{key}, value, expiration.Milliseconds()).Int64()
+	if r.track(err) != nil {
+		return 0, err
 	}
 
-	return nil
+	return counter, nil
 }
 
-func (r *redis) Keys(pattern string) ([]string, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), Timeout*time.Second)
+func (r *redis) Expire(ctx context.Context, key string, expiration time.Duration) error {
+	if !r.available() {
+		return ErrUnavailable
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	return r.track(r.cmd.Expire(ctx, key, expiration).Err())
+}
+
+func (r *redis) TTL(ctx context.Context, key string) (time.Duration, error) {
+	if !r.available() {
+		return 0, ErrUnavailable
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
 	defer cancel()
 
-	keys, err := r.cmd.Keys(ctx, pattern).Result()
+	ttl, err := r.cmd.TTL(ctx, key).Result()
+	if r.track(err) != nil {
+		return 0, err
+	}
+
+	return ttl, nil
+}
+
+func (r *redis) Remove(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	if !r.available() {
+		return ErrUnavailable
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	if r.cluster {
+		// DEL with keys from different slots is rejected by the cluster
+		_, err := r.cmd.Pipelined(ctx, func(pipe goredis.Pipeliner) error {
+			for _, key := range keys {
+				pipe.Del(ctx, key)
+			}
+			return nil
+		})
+		return r.track(err)
+	}
+
+	return r.track(r.cmd.Del(ctx, keys...).Err())
+}
+
+// Keys returns the keys matching pattern using SCAN, so large keyspaces do
+// not block the server the way KEYS does
+func (r *redis) Keys(ctx context.Context, pattern string) ([]string, error) {
+	if !r.available() {
+		return nil, ErrUnavailable
+	}
+
+	var keys []string
+	err := r.scan(ctx, pattern, func(batch []string) error {
+		keys = append(keys, batch...)
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -142,20 +336,126 @@ func (r *redis) Keys(pattern string) ([]string, error) {
 	return keys, nil
 }
 
-func (r *redis) RemovePattern(pattern string) error {
-	keys, err := r.Keys(pattern)
-	if err != nil {
-		return err
+// RemovePattern deletes the keys matching pattern batch by batch while
+// scanning, without loading the whole key list in memory
+func (r *redis) RemovePattern(ctx context.Context, pattern string) error {
+	if !r.available() {
+		return ErrUnavailable
 	}
 
-	if len(keys) == 0 {
-		return nil
+	return r.scan(ctx, pattern, func(batch []string) error {
+		return r.Remove(ctx, batch...)
+	})
+}
+
+// Pipelined queues the commands issued by fn and sends them in one round trip
+func (r *redis) Pipelined(ctx context.Context, fn func(pipe goredis.Pipeliner) error) ([]goredis.Cmder, error) {
+	if !r.available() {
+		return nil, ErrUnavailable
 	}
 
-	err = r.Remove(keys...)
-	if err != nil {
-		return err
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	cmds, err := r.cmd.Pipelined(ctx, fn)
+	if err == goredis.Nil {
+		return cmds, nil
+	}
+	if r.track(err) != nil {
+		return cmds, err
+	}
+
+	return cmds, nil
+}
+
+// RunScript evaluates a Lua script, loading it on the server when needed
+func (r *redis) RunScript(ctx context.Context, script *goredis.Script, keys []string, args ...interface{}) (interface{}, error) {
+	if !r.available() {
+		return nil, ErrUnavailable
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	result, err := script.Run(ctx, r.cmd, keys, args...).Result()
+	if r.track(err) != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (r *redis) scan(ctx context.Context, pattern string, fn func(batch []string) error) error {
+	scanNode := func(ctx context.Context, node goredis.Cmdable) error {
+		var cursor uint64
+		for {
+			scanCtx, cancel := context.WithTimeout(ctx, r.timeout)
+			keys, next, err := node.Scan(scanCtx, cursor, pattern, ScanCount).Result()
+			cancel()
+			if r.track(err) != nil {
+				return err
+			}
+
+			if len(keys) > 0 {
+				if err := fn(keys); err != nil {
+					return err
+				}
+			}
+
+			cursor = next
+			if cursor == 0 {
+				return nil
+			}
+		}
+	}
+
+	if cluster, ok := r.cmd.(*goredis.ClusterClient); ok {
+		return cluster.ForEachMaster(ctx, func(ctx context.Context, node *goredis.Client) error {
+			return scanNode(ctx, node)
+		})
+	}
+
+	return scanNode(ctx, r.cmd)
+}
+
+// available reports whether operations should be sent to Redis. After a
+// connection error the client short-circuits for retryInterval instead of
+// waiting for the timeout on every request.
+func (r *redis) available() bool {
+	if r.cmd == nil {
+		return false
+	}
+
+	until := r.downUntil.Load()
+	return until == 0 || time.Now().UnixNano() >= until
+}
+
+// track records the outcome of an operation and returns err unchanged.
+// goredis.Nil (missing key) is not a connection problem.
+func (r *redis) track(err error) error {
+	switch {
+	case err == nil, err == goredis.Nil:
+		r.downUntil.Store(0)
+	case isConnectionError(err):
+		if r.downUntil.Swap(time.Now().Add(r.retryInterval).UnixNano()) == 0 {
+			logger.Warn("Redis is unavailable, serving without cache: ", err)
+		}
+	}
+
+	return err
+}
+
+func isConnectionError(err error) bool {
+	// the caller gave up, Redis may be fine
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
 	}
 
-	return nil
+	var redisErr goredis.Error
+	// server replies (WRONGTYPE, MOVED, ...) implement goredis.Error,
+	// network failures do not
+	return !errors.As(err, &redisErr)
 }
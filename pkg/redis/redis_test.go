@@ -0,0 +1,142 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/quangdangfit/gocommon/logger"
+
+	"main/pkg/config"
+)
+
+func TestMain(m *testing.M) {
+	logger.Initialize(config.ProductionEnv)
+	os.Exit(m.Run())
+}
+
+func newTestRedis(t *testing.T, retryInterval time.Duration) (*miniredis.Miniredis, IRedis) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	client := New(Config{Address: server.Addr(), Timeout: 200 * time.Millisecond, RetryInterval: retryInterval})
+	t.Cleanup(func() { _ = client.Close() })
+	return server, client
+}
+
+func TestDegradedMode(t *testing.T) {
+	ctx := context.Background()
+	server, client := newTestRedis(t, 300*time.Millisecond)
+
+	if err := client.Set(ctx, "key", "value"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	server.Close()
+	var value string
+	if err := client.Get(ctx, "key", &value); !errors.Is(err, ErrCacheMiss) {
+		t.Fatalf("Get() with Redis down error = %v, want ErrCacheMiss", err)
+	}
+
+	// operations short-circuit during the retry interval, even once Redis is
+	// back
+	if err := server.Restart(); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Set(ctx, "key", "value"); !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("Set() during retry interval error = %v, want ErrUnavailable", err)
+	}
+
+	time.Sleep(400 * time.Millisecond)
+	if err := client.Set(ctx, "key", "again"); err != nil {
+		t.Fatalf("Set() after retry interval error = %v", err)
+	}
+	if err := client.Get(ctx, "key", &value); err != nil || value != "again" {
+		t.Fatalf("Get() = %q, %v, want again", value, err)
+	}
+}
+
+func TestCanceledContextKeepsRedisUp(t *testing.T) {
+	_, client := newTestRedis(t, time.Hour)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var value string
+	if err := client.Get(ctx, "key", &value); !errors.Is(err, ErrCacheMiss) {
+		t.Fatalf("Get() with canceled context error = %v, want ErrCacheMiss", err)
+	}
+
+	if err := client.Set(context.Background(), "key", "value"); err != nil {
+		t.Fatalf("Set() after a canceled request error = %v, want nil", err)
+	}
+}
+
+func TestKeysScansEveryBatch(t *testing.T) {
+	server, client := newTestRedis(t, time.Hour)
+
+	want := ScanCount*2 + 10
+	for i := 0; i < want; i++ {
+		server.Set(fmt.Sprintf("cache:doctor:%d", i), "{}")
+	}
+	server.Set("cache:address:1", "{}")
+
+	keys, err := client.Keys(context.Background(), "cache:*doctor*")
+	if err != nil {
+		t.Fatalf("Keys() error = %v", err)
+	}
+	if len(keys) != want {
+		t.Errorf("Keys() returned %d keys, want %d", len(keys), want)
+	}
+}
+
+func TestRemovePattern(t *testing.T) {
+	server, client := newTestRedis(t, time.Hour)
+
+	// miniredis pages SCAN by offset, deleting while scanning skips keys
+	// there, so the keys fit in one batch
+	for i := 0; i < 100; i++ {
+		server.Set(fmt.Sprintf("cache:doctor:%d", i), "{}")
+	}
+	server.Set("cache:address:1", "{}")
+	server.Set("lockout:doctor@example.com", "1")
+
+	if err := client.RemovePattern(context.Background(), "cache:*doctor*"); err != nil {
+		t.Fatalf("RemovePattern() error = %v", err)
+	}
+
+	keys := server.Keys()
+	if len(keys) != 2 || keys[0] != "cache:address:1" || keys[1] != "lockout:doctor@example.com" {
+		t.Errorf("keys left = %v", keys)
+	}
+}
+
+func TestMGet(t *testing.T) {
+	ctx := context.Background()
+	_, client := newTestRedis(t, time.Hour)
+
+	if err := client.Set(ctx, "a", 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Set(ctx, "c", 3); err != nil {
+		t.Fatal(err)
+	}
+
+	var a, b, c int
+	found, err := client.MGet(ctx, []string{"a", "b", "c"}, []interface{}{&a, &b, &c})
+	if err != nil {
+		t.Fatalf("MGet() error = %v", err)
+	}
+	if !found[0] || found[1] || !found[2] {
+		t.Errorf("MGet() found = %v, want [true false true]", found)
+	}
+	if a != 1 || c != 3 {
+		t.Errorf("MGet() values = %d, %d, want 1, 3", a, c)
+	}
+
+	if _, err := client.MGet(ctx, []string{"a"}, nil); err == nil {
+		t.Error("MGet() with mismatched values, want an error")
+	}
+}
@@ -1,6 +1,11 @@
 package response
 
 import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
 	"github.com/gin-gonic/gin"
 
 	"main/pkg/config"
@@ -18,3 +23,9 @@ func Error(c *gin.Context, status int, err error, message string) {
 
 	c.JSON(status, Response{Error: errorRes})
 }
+
+// TooManyRequests responds 429 with a Retry-After header in seconds
+func TooManyRequests(c *gin.Context, retryAfter time.Duration, err error) {
+	c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+	Error(c, http.StatusTooManyRequests, err, "Too many requests")
+}
@@ -0,0 +1,73 @@
+package response
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"main/pkg/dbs"
+)
+
+var (
+	ErrIfMatchRequired = errors.New("missing If-Match header")
+	ErrIfMatchInvalid  = errors.New("the If-Match header is not the ETag of a version")
+)
+
+// ETag is the strong entity tag of the representation at version
+func ETag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// Versioned responds data with the ETag of version. GET requests with a
+// matching If-None-Match get 304 instead.
+func Versioned(c *gin.Context, status int, version int64, data interface{}) {
+	etag := ETag(version)
+	c.Header("ETag", etag)
+	if c.Request.Method == http.MethodGet && noneMatch(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	JSON(c, status, data)
+}
+
+func noneMatch(header, etag string) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		// the comparison of If-None-Match is weak
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// IfMatch returns the version of the ETag of the If-Match header, or
+// dbs.AnyVersion for *, which matches any current version. It responds 428
+// when the header is missing and 412 when it is not a strong ETag from ETag,
+// and returns false.
+func IfMatch(c *gin.Context) (int64, bool) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" {
+		Error(c, http.StatusPreconditionRequired, ErrIfMatchRequired, "Send the ETag of the resource in If-Match")
+		return 0, false
+	}
+	if header == "*" {
+		return dbs.AnyVersion, true
+	}
+
+	version, err := strconv.ParseInt(strings.Trim(header, `"`), 10, 64)
+	if err != nil || header != ETag(version) {
+		Error(c, http.StatusPreconditionFailed, ErrIfMatchInvalid, "The resource was changed, fetch it again")
+		return 0, false
+	}
+	return version, true
+}
+
+// StaleVersion responds 412 to an update or delete whose If-Match version is
+// no longer the current one
+func StaleVersion(c *gin.Context, err error) {
+	Error(c, http.StatusPreconditionFailed, err, "The resource was changed, fetch it again")
+}
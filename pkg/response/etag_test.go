@@ -0,0 +1,62 @@
+package response
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"main/pkg/dbs"
+)
+
+func testContext(method string, header http.Header) (*gin.Context, *httptest.ResponseRecorder) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(method, "/", nil)
+	c.Request.Header = header
+	return c, w
+}
+
+func TestIfMatch(t *testing.T) {
+	tests := []struct {
+		header  string
+		version int64
+		status  int
+	}{
+		{header: `"3"`, version: 3, status: http.StatusOK},
+		{header: "", status: http.StatusPreconditionRequired},
+		{header: `W/"3"`, status: http.StatusPreconditionFailed},
+		{header: "*", version: dbs.AnyVersion, status: http.StatusOK},
+		{header: "3", status: http.StatusPreconditionFailed},
+	}
+	for _, tt := range tests {
+		c, w := testContext(http.MethodPut, http.Header{"If-Match": {tt.header}})
+		version, ok := IfMatch(c)
+		if ok != (tt.status == http.StatusOK) || version != tt.version || w.Code != tt.status {
+			t.Errorf("IfMatch(%q) = %d, %v, status %d", tt.header, version, ok, w.Code)
+		}
+	}
+}
+
+func TestVersioned(t *testing.T) {
+	c, w := testContext(http.MethodGet, http.Header{"If-None-Match": {`"2", W/"3"`}})
+	Versioned(c, http.StatusOK, 3, "data")
+	c.Writer.WriteHeaderNow()
+	if w.Code != http.StatusNotModified || w.Header().Get("ETag") != `"3"` || w.Body.Len() != 0 {
+		t.Errorf("matching GET = %d, etag %q, body %q", w.Code, w.Header().Get("ETag"), w.Body)
+	}
+
+	c, w = testContext(http.MethodGet, http.Header{"If-None-Match": {`"2"`}})
+	Versioned(c, http.StatusOK, 3, "data")
+	if w.Code != http.StatusOK || w.Body.Len() == 0 {
+		t.Errorf("changed GET = %d", w.Code)
+	}
+
+	c, w = testContext(http.MethodPut, http.Header{"If-None-Match": {"*"}})
+	Versioned(c, http.StatusOK, 3, "data")
+	if w.Code != http.StatusOK || w.Header().Get("ETag") != `"3"` {
+		t.Errorf("PUT = %d, etag %q", w.Code, w.Header().Get("ETag"))
+	}
+}
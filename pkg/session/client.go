@@ -0,0 +1,77 @@
+package session
+
+import (
+	"context"
+	"strings"
+)
+
+// ClientKey is the context key of the Client, a string so the gin context
+// resolves it from its keys
+const ClientKey = "sessionClient"
+
+// Client describes where a login comes from
+type Client struct {
+	UserAgent string
+	IP        string
+}
+
+func WithClient(ctx context.Context, client Client) context.Context {
+	return context.WithValue(ctx, ClientKey, client)
+}
+
+func ClientFromContext(ctx context.Context) Client {
+	client, _ := ctx.Value(ClientKey).(Client)
+	return client
+}
+
+// the first match wins, so more specific tokens come first
+var (
+	browsers = [][2]string{
+		{"Edg/", "Edge"},
+		{"OPR/", "Opera"},
+		{"SamsungBrowser/", "Samsung Internet"},
+		{"Firefox/", "Firefox"},
+		{"Chrome/", "Chrome"},
+		{"Safari/", "Safari"},
+		{"grpc-", "gRPC client"},
+		{"okhttp/", "Android app"},
+		{"Dart/", "Mobile app"},
+		{"curl/", "curl"},
+	}
+	systems = [][2]string{
+		{"Windows", "Windows"},
+		{"Android", "Android"},
+		{"iPhone", "iOS"},
+		{"iPad", "iPadOS"},
+		{"Mac OS X", "macOS"},
+		{"CrOS", "ChromeOS"},
+		{"Linux", "Linux"},
+	}
+)
+
+// Device returns a short description of the user agent such as
+// "Chrome on Windows"
+func (c Client) Device() string {
+	browser := match(c.UserAgent, browsers)
+	system := match(c.UserAgent, systems)
+
+	switch {
+	case browser != "" && system != "":
+		return browser + " on " + system
+	case browser != "":
+		return browser
+	case system != "":
+		return system
+	default:
+		return "Unknown device"
+	}
+}
+
+func match(userAgent string, tokens [][2]string) string {
+	for _, token := range tokens {
+		if strings.Contains(userAgent, token[0]) {
+			return token[1]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,107 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/quangdangfit/gocommon/logger"
+
+	"main/pkg/jtoken"
+	"main/pkg/redis"
+)
+
+const (
+	revokedKeyPrefix = "session:revoked:"
+	seenKeyPrefix    = "session:seen:"
+
+	// TTL is how long a session can live, the lifetime of a refresh token
+	TTL = jtoken.RefreshTokenExpiredTime * time.Second
+
+	// activeTTL is how long a session found active in the database is
+	// trusted, the delay of a revocation whose Redis write failed
+	activeTTL = time.Minute
+)
+
+var ErrRevoked = errors.New("session revoked")
+
+// Revocations is the source of truth of the revoked sessions, the sessions
+// unknown to it being revoked
+type Revocations interface {
+	Revoked(ctx context.Context, id string) (bool, error)
+}
+
+// Store holds what the auth middlewares check on every request: the sessions
+// revoked before their tokens expire and when each session was last used.
+// Sessions themselves are kept by the user module, Redis only caches their
+// revocation: when it misses or is unavailable the revocations are read from
+// the database.
+type Store struct {
+	cache       redis.IRedis
+	revocations Revocations
+}
+
+func NewStore(cache redis.IRedis, revocations Revocations) *Store {
+	return &Store{cache: cache, revocations: revocations}
+}
+
+// Check returns ErrRevoked when the session was revoked, else it records the
+// session as seen now
+func (s *Store) Check(ctx context.Context, id string) error {
+	var revoked bool
+	if err := s.cache.Get(ctx, revokedKeyPrefix+id, &revoked); err != nil {
+		if revoked, err = s.revocations.Revoked(ctx, id); err != nil {
+			return err
+		}
+
+		ttl := activeTTL
+		if revoked {
+			ttl = TTL
+		}
+		_ = s.cache.SetWithExpiration(ctx, revokedKeyPrefix+id, revoked, ttl)
+	}
+	if revoked {
+		return ErrRevoked
+	}
+
+	_ = s.cache.SetWithExpiration(ctx, seenKeyPrefix+id, time.Now().Unix(), TTL)
+	return nil
+}
+
+// Revoke makes the middlewares reject the tokens of the sessions, revoked in
+// the database beforehand, immediately. When Redis is unavailable they are
+// rejected once it no longer holds them as active.
+func (s *Store) Revoke(ctx context.Context, ids ...string) {
+	for _, id := range ids {
+		if err := s.cache.SetWithExpiration(ctx, revokedKeyPrefix+id, true, TTL); err != nil {
+			logger.Errorf("Revoke session fail, id: %s, error: %s", id, err)
+		}
+	}
+}
+
+// LastSeen returns when each session was last used, sessions not seen since
+// Redis last lost its data are missing
+func (s *Store) LastSeen(ctx context.Context, ids []string) map[string]time.Time {
+	seen := make(map[string]time.Time, len(ids))
+	if len(ids) == 0 {
+		return seen
+	}
+
+	keys := make([]string, len(ids))
+	values := make([]interface{}, len(ids))
+	for i, id := range ids {
+		keys[i] = seenKeyPrefix + id
+		values[i] = new(int64)
+	}
+
+	found, err := s.cache.MGet(ctx, keys, values)
+	if err != nil {
+		return seen
+	}
+	for i, id := range ids {
+		if found[i] {
+			seen[id] = time.Unix(*values[i].(*int64), 0)
+		}
+	}
+	return seen
+}
@@ -0,0 +1,93 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/quangdangfit/gocommon/logger"
+
+	"main/pkg/config"
+	"main/pkg/redis"
+)
+
+func TestMain(m *testing.M) {
+	logger.Initialize(config.ProductionEnv)
+	os.Exit(m.Run())
+}
+
+// revocations are the sessions table, the sessions missing from it are
+// revoked
+type revocations struct {
+	active map[string]bool
+	err    error
+	reads  int
+}
+
+func (r *revocations) Revoked(ctx context.Context, id string) (bool, error) {
+	r.reads++
+	return !r.active[id], r.err
+}
+
+func TestStoreCheck(t *testing.T) {
+	server := miniredis.RunT(t)
+	db := &revocations{active: map[string]bool{"active": true, "revoked": true}}
+	store := NewStore(redis.New(redis.Config{Address: server.Addr(), Timeout: time.Second}), db)
+	ctx := context.Background()
+
+	// the database answers the misses, which are cached
+	if err := store.Check(ctx, "active"); err != nil {
+		t.Fatalf("Check(active) = %v", err)
+	}
+	if err := store.Check(ctx, "active"); err != nil || db.reads != 1 {
+		t.Errorf("Check(active) = %v after %d reads, want one", err, db.reads)
+	}
+	if err := store.Check(ctx, "unknown"); !errors.Is(err, ErrRevoked) {
+		t.Errorf("Check(unknown) = %v, want ErrRevoked", err)
+	}
+
+	// a revoked session is rejected at once
+	db.active["revoked"] = false
+	store.Revoke(ctx, "revoked")
+	if err := store.Check(ctx, "revoked"); !errors.Is(err, ErrRevoked) {
+		t.Errorf("Check(revoked) = %v, want ErrRevoked", err)
+	}
+
+	// evicted, the revocation is read from the database again
+	server.FlushAll()
+	if err := store.Check(ctx, "revoked"); !errors.Is(err, ErrRevoked) {
+		t.Errorf("Check(revoked) after eviction = %v, want ErrRevoked", err)
+	}
+
+	// the sessions active in the database are trusted activeTTL
+	db.active["active"] = false
+	server.FastForward(activeTTL)
+	if err := store.Check(ctx, "active"); !errors.Is(err, ErrRevoked) {
+		t.Errorf("Check(active) after activeTTL = %v, want ErrRevoked", err)
+	}
+}
+
+func TestStoreRedisDown(t *testing.T) {
+	server := miniredis.RunT(t)
+	db := &revocations{active: map[string]bool{"active": true}}
+	store := NewStore(redis.New(redis.Config{Address: server.Addr(), Timeout: 100 * time.Millisecond}), db)
+	ctx := context.Background()
+	server.Close()
+
+	store.Revoke(ctx, "revoked")
+	if err := store.Check(ctx, "revoked"); !errors.Is(err, ErrRevoked) {
+		t.Errorf("Check(revoked) = %v, want ErrRevoked", err)
+	}
+	if err := store.Check(ctx, "active"); err != nil {
+		t.Errorf("Check(active) = %v", err)
+	}
+
+	// neither Redis nor the database can tell, the session is rejected
+	db.err = errors.New("database down")
+	if err := store.Check(ctx, "active"); err == nil {
+		t.Error("Check(active) without a database succeeded")
+	}
+}
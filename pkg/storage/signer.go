@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+var (
+	ErrInvalidSignature = errors.New("invalid signature")
+	ErrURLExpired       = errors.New("url expired")
+)
+
+// Signer signs download urls so they can be shared without a token, until
+// they expire
+type Signer struct {
+	secret []byte
+	// baseURL is joined with the object id
+	baseURL string
+}
+
+func NewSigner(secret, baseURL string) *Signer {
+	return &Signer{secret: []byte(secret), baseURL: baseURL}
+}
+
+// URL returns the download url of id, valid for ttl
+func (s *Signer) URL(id string, ttl time.Duration) (string, time.Time) {
+	expiresAt := time.Now().Add(ttl).Truncate(time.Second)
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expiresAt.Unix(), 10))
+	query.Set("signature", s.sign(id, expiresAt.Unix()))
+	return s.baseURL + "/" + url.PathEscape(id) + "/download?" + query.Encode(), expiresAt
+}
+
+// Verify checks the expires and signature parameters of a download url of id
+func (s *Signer) Verify(id, expires, signature string) error {
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if !hmac.Equal([]byte(signature), []byte(s.sign(id, unix))) {
+		return ErrInvalidSignature
+	}
+	if time.Now().Unix() > unix {
+		return ErrURLExpired
+	}
+	return nil
+}
+
+func (s *Signer) sign(id string, expires int64) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(id + "\n" + strconv.FormatInt(expires, 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,46 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"main/pkg/config"
+)
+
+const (
+	LocalDriver = "local"
+	S3Driver    = "s3"
+)
+
+var ErrNotFound = errors.New("object not found")
+
+// Storage stores objects by key. Keys are generated by the callers and only
+// made of letters, digits, dashes and slashes.
+type Storage interface {
+	// Put stores size bytes of r under key, replacing any object
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Get fails with ErrNotFound when there is no object under key
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// FromConfig returns the storage of the configured driver
+func FromConfig(cfg *config.Schema) (Storage, error) {
+	switch cfg.StorageDriver {
+	case LocalDriver, "":
+		return NewLocal(cfg.StorageLocalPath)
+	case S3Driver:
+		return NewS3(S3Config{
+			Endpoint:  cfg.StorageS3Endpoint,
+			Region:    cfg.StorageS3Region,
+			Bucket:    cfg.StorageS3Bucket,
+			AccessKey: cfg.StorageS3AccessKey,
+			SecretKey: cfg.StorageS3SecretKey,
+			UseSSL:    cfg.StorageS3UseSSL,
+		})
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.StorageDriver)
+	}
+}
@@ -0,0 +1,98 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+var png = append([]byte("\x89PNG\x0D\x0A\x1A\x0A"), bytes.Repeat([]byte{0}, 600)...)
+
+func TestUpload(t *testing.T) {
+	store, err := NewLocal(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	limits := Limits{MaxSize: 1024, ContentTypes: []string{"image/png"}}
+	ctx := context.Background()
+
+	object, err := Upload(ctx, store, "user/file", bytes.NewReader(png), limits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256(png)
+	if object.ContentType != "image/png" || object.Size != int64(len(png)) || object.Checksum != hex.EncodeToString(sum[:]) {
+		t.Errorf("Upload() = %+v", object)
+	}
+
+	r, err := store.Get(ctx, "user/file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	if content, _ := io.ReadAll(r); !bytes.Equal(content, png) {
+		t.Error("stored content differs")
+	}
+
+	tests := []struct {
+		name    string
+		content []byte
+		err     error
+	}{
+		{"empty", nil, ErrEmpty},
+		{"too large", append(png, make([]byte, 1024)...), ErrTooLarge},
+		{"content type", []byte("<html><script>alert(1)</script></html>"), ErrContentType},
+	}
+	for _, tt := range tests {
+		if _, err := Upload(ctx, store, "user/"+strings.ReplaceAll(tt.name, " ", "-"), bytes.NewReader(tt.content), limits); !errors.Is(err, tt.err) {
+			t.Errorf("Upload(%s) error = %v, want %v", tt.name, err, tt.err)
+		}
+		if _, err := store.Get(ctx, "user/"+strings.ReplaceAll(tt.name, " ", "-")); err != ErrNotFound {
+			t.Errorf("Upload(%s) stored the file", tt.name)
+		}
+	}
+}
+
+func TestLocalKeys(t *testing.T) {
+	store, err := NewLocal(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, key := range []string{"", "../escape", "a/../../escape", "/abs", "a/.hidden"} {
+		if err := store.Put(context.Background(), key, strings.NewReader("x"), 1, "text/plain"); err == nil {
+			t.Errorf("Put(%q) accepted the key", key)
+		}
+	}
+}
+
+func TestSigner(t *testing.T) {
+	signer := NewSigner("secret", "http://localhost/files")
+
+	raw, expiresAt := signer.URL("id", time.Minute)
+	u, err := url.Parse(raw)
+	if err != nil || u.Path != "/files/id/download" || !expiresAt.After(time.Now()) {
+		t.Fatalf("URL() = %s, %s", raw, expiresAt)
+	}
+	expires, signature := u.Query().Get("expires"), u.Query().Get("signature")
+
+	if err := signer.Verify("id", expires, signature); err != nil {
+		t.Errorf("Verify() error = %v", err)
+	}
+	if err := signer.Verify("other", expires, signature); err != ErrInvalidSignature {
+		t.Errorf("Verify(other id) error = %v", err)
+	}
+	if err := NewSigner("other", "").Verify("id", expires, signature); err != ErrInvalidSignature {
+		t.Errorf("Verify(other secret) error = %v", err)
+	}
+	if err := signer.Verify("id", "1", signer.sign("id", 1)); err != ErrURLExpired {
+		t.Errorf("Verify(expired) error = %v", err)
+	}
+}
@@ -0,0 +1,106 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+)
+
+// sniffLen is how much http.DetectContentType looks at
+const sniffLen = 512
+
+var (
+	ErrEmpty       = errors.New("file is empty")
+	ErrTooLarge    = errors.New("file is too large")
+	ErrContentType = errors.New("file type is not allowed")
+)
+
+// Limits of an upload, the content type is sniffed from the content and the
+// one claimed by the client ignored
+type Limits struct {
+	MaxSize      int64
+	ContentTypes []string
+}
+
+// Object is a stored upload
+type Object struct {
+	Key         string
+	ContentType string
+	Size        int64
+	// hex sha256 of the content
+	Checksum string
+}
+
+// Upload streams r to a temporary file within limits, computing its checksum,
+// then stores it under key. Nothing is stored when r breaks the limits.
+func Upload(ctx context.Context, s Storage, key string, r io.Reader, limits Limits) (*Object, error) {
+	head := make([]byte, sniffLen)
+	n, err := io.ReadFull(r, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return nil, err
+	}
+	if n == 0 {
+		return nil, ErrEmpty
+	}
+	head = head[:n]
+
+	contentType, err := sniff(head, limits.ContentTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	tmp, err := os.CreateTemp("", "upload-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	hash := sha256.New()
+	w := io.MultiWriter(tmp, hash)
+	if _, err := w.Write(head); err != nil {
+		return nil, err
+	}
+	// one byte over the limit tells a too large file apart
+	rest, err := io.Copy(w, io.LimitReader(r, limits.MaxSize-int64(n)+1))
+	if err != nil {
+		return nil, err
+	}
+	size := int64(n) + rest
+	if size > limits.MaxSize {
+		return nil, ErrTooLarge
+	}
+
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	if err := s.Put(ctx, key, tmp, size, contentType); err != nil {
+		return nil, err
+	}
+
+	return &Object{
+		Key:         key,
+		ContentType: contentType,
+		Size:        size,
+		Checksum:    hex.EncodeToString(hash.Sum(nil)),
+	}, nil
+}
+
+func sniff(head []byte, allowed []string) (string, error) {
+	contentType, _, err := mime.ParseMediaType(http.DetectContentType(head))
+	if err != nil {
+		return "", err
+	}
+	for _, t := range allowed {
+		if t == contentType {
+			return contentType, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s", ErrContentType, contentType)
+}
@@ -0,0 +1,50 @@
+// Package tenant carries the clinic a request is bound to. Users of a clinic
+// only see its rows, the other users and the background jobs are not bound
+// to any clinic.
+package tenant
+
+import (
+	"context"
+	"errors"
+)
+
+// Key is the context key of the clinic id, set by the auth middlewares
+const Key = "tenantId"
+
+// ErrCrossTenant is returned when a row of another clinic is written
+var ErrCrossTenant = errors.New("row belongs to another clinic")
+
+// FromContext returns the clinic ctx is bound to
+func FromContext(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+	id, _ := ctx.Value(Key).(string)
+	return id, id != ""
+}
+
+// WithID binds ctx to the clinic id
+func WithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, Key, id)
+}
+
+// Global unbinds ctx, for the lookups that must cross clinics such as adding
+// a user to one
+func Global(ctx context.Context) context.Context {
+	return context.WithValue(ctx, Key, "")
+}
+
+// CachePrefix starts the keys of the cached responses. The invalidation
+// patterns start with it too, so that they never remove the other keys of
+// Redis, such as the rate limits and the lockouts of an email with doctor in
+// it.
+const CachePrefix = "cache:"
+
+// CacheKey prefixes key with the clinic of ctx so cached rows of a clinic are
+// not served to another, under CachePrefix
+func CacheKey(ctx context.Context, key string) string {
+	if id, ok := FromContext(ctx); ok {
+		return CachePrefix + "tenant:" + id + ":" + key
+	}
+	return CachePrefix + key
+}
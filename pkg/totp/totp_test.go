@@ -0,0 +1,60 @@
+package totp
+
+import (
+	"encoding/base32"
+	"testing"
+	"time"
+)
+
+// RFC 6238 appendix B, SHA1 key truncated to 6 digits
+var rfcSecret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+func TestCode(t *testing.T) {
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{unix: 59, want: "287082"},
+		{unix: 1111111109, want: "081804"},
+		{unix: 1234567890, want: "005924"},
+		{unix: 20000000000, want: "353130"},
+	}
+	for _, tt := range tests {
+		got, err := Code(rfcSecret, time.Unix(tt.unix, 0))
+		if err != nil {
+			t.Fatalf("Code() error = %v", err)
+		}
+		if got != tt.want {
+			t.Errorf("Code(%d) = %s, want %s", tt.unix, got, tt.want)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	code, _ := Code(rfcSecret, now.Add(-Period))
+
+	s, err := Validate(rfcSecret, code, now, 0)
+	if err != nil {
+		t.Fatalf("previous period rejected: %v", err)
+	}
+	if _, err := Validate(rfcSecret, code, now, s); err != ErrInvalidCode {
+		t.Errorf("replayed code accepted")
+	}
+	if _, err := Validate(rfcSecret, code, now.Add(2*Period), 0); err != ErrInvalidCode {
+		t.Errorf("expired code accepted")
+	}
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	codes, err := GenerateRecoveryCodes(10)
+	if err != nil || len(codes) != 10 {
+		t.Fatalf("GenerateRecoveryCodes() = %v, %v", codes, err)
+	}
+	if got := NormalizeRecoveryCode(" " + codes[0][:5] + codes[0][6:] + " "); got != codes[0] {
+		t.Errorf("NormalizeRecoveryCode() = %s, want %s", got, codes[0])
+	}
+	if IsCode(codes[0]) {
+		t.Errorf("recovery code taken for a TOTP code")
+	}
+}
@@ -7,8 +7,6 @@ import (
 
 func Copy(dest interface{}, src interface{}) {
 	data, err := json.Marshal(src)
-	fmt.Print(data)
-
 	if err != nil {
 		fmt.Print(err)
 		return
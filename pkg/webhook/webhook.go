@@ -0,0 +1,186 @@
+// Package webhook signs and sends the callbacks of the webhook subscriptions,
+// and verifies them on the receiving side.
+//
+// The body is signed with HMAC-SHA256 of "<timestamp>.<body>" keyed by the
+// secret of the subscription and sent as
+//
+//	Webhook-Signature: t=<unix timestamp>,v1=<hex signature>
+//
+// Receivers recompute the signature and reject old timestamps so a captured
+// callback cannot be replayed.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Headers of the callbacks
+const (
+	IDHeader        = "Webhook-Id"
+	EventHeader     = "Webhook-Event"
+	SignatureHeader = "Webhook-Signature"
+)
+
+const (
+	// DefaultTolerance is how old a callback Verify accepts
+	DefaultTolerance = 5 * time.Minute
+	// MaxResponseSize is the part of the responses kept in the delivery logs
+	MaxResponseSize = 1024
+
+	secretPrefix = "whsec_"
+)
+
+var (
+	ErrInvalidSignature = errors.New("webhook: invalid signature")
+	ErrExpiredTimestamp = errors.New("webhook: timestamp outside of the tolerance")
+)
+
+// GenerateSecret returns a random secret to sign the callbacks of a
+// subscription
+func GenerateSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return secretPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Sign returns the Webhook-Signature header of body sent at timestamp
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	t := strconv.FormatInt(timestamp.Unix(), 10)
+	return "t=" + t + ",v1=" + signature(secret, t, body)
+}
+
+func signature(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks the Webhook-Signature header of body was signed with secret
+// less than tolerance before now. Several v1 signatures are accepted, one
+// per secret while it is rotated.
+func Verify(secret, header string, body []byte, tolerance time.Duration, now time.Time) error {
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || len(signatures) == 0 {
+		return ErrInvalidSignature
+	}
+	if age := now.Sub(time.Unix(unix, 0)); age > tolerance || age < -tolerance {
+		return ErrExpiredTimestamp
+	}
+
+	expected := signature(secret, timestamp, body)
+	for _, s := range signatures {
+		if hmac.Equal([]byte(s), []byte(expected)) {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
+
+// Backoff is the delay before the attempt following attempt, base doubling
+// up to max
+func Backoff(attempt int, base, max time.Duration) time.Duration {
+	delay := base
+	for i := 1; i < attempt && delay < max; i++ {
+		delay *= 2
+	}
+	if delay > max {
+		return max
+	}
+	return delay
+}
+
+// Callback is a signed POST of an event to a subscription
+type Callback struct {
+	URL    string
+	Secret string
+	// ID is the id of the delivery, the same for its retries so receivers
+	// can ignore the duplicates
+	ID    string
+	Event string
+	Body  []byte
+}
+
+// Result is the response of the receiver, Status is 0 when none was
+// received
+type Result struct {
+	Status   int
+	Body     string
+	Duration time.Duration
+}
+
+// Sender posts the callbacks
+type Sender struct {
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewSender posts the callbacks with timeout, redirects are not followed
+func NewSender(timeout time.Duration) *Sender {
+	return &Sender{
+		client: &http.Client{
+			Timeout: timeout,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		now: time.Now,
+	}
+}
+
+// Send posts callback, it fails unless the receiver answers with a 2xx
+// status
+func (s *Sender) Send(ctx context.Context, callback *Callback) (*Result, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, callback.URL, bytes.NewReader(callback.Body))
+	if err != nil {
+		return &Result{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Doctoral-Webhooks/1.0")
+	req.Header.Set(IDHeader, callback.ID)
+	req.Header.Set(EventHeader, callback.Event)
+	req.Header.Set(SignatureHeader, Sign(callback.Secret, s.now(), callback.Body))
+
+	start := time.Now()
+	res, err := s.client.Do(req)
+	result := &Result{Duration: time.Since(start)}
+	if err != nil {
+		return result, err
+	}
+	defer res.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(res.Body, MaxResponseSize))
+	result.Status = res.StatusCode
+	result.Body = string(body)
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return result, fmt.Errorf("webhook: receiver answered %d", res.StatusCode)
+	}
+	return result, nil
+}
@@ -0,0 +1,75 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSignVerify(t *testing.T) {
+	now := time.Now()
+	body := []byte(`{"name":"doctor.created"}`)
+	header := Sign("secret", now, body)
+
+	if err := Verify("secret", header, body, DefaultTolerance, now); err != nil {
+		t.Errorf("Verify = %v", err)
+	}
+	if err := Verify("other", header, body, DefaultTolerance, now); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Verify with another secret = %v", err)
+	}
+	if err := Verify("secret", header, []byte(`{}`), DefaultTolerance, now); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Verify of another body = %v", err)
+	}
+	if err := Verify("secret", header, body, DefaultTolerance, now.Add(time.Hour)); !errors.Is(err, ErrExpiredTimestamp) {
+		t.Errorf("Verify of an old callback = %v", err)
+	}
+	rotated := Sign("old", now, body) + ",v1=" + strings.Split(header, "v1=")[1]
+	if err := Verify("secret", rotated, body, DefaultTolerance, now); err != nil {
+		t.Errorf("Verify with two signatures = %v", err)
+	}
+}
+
+func TestSend(t *testing.T) {
+	status := http.StatusNoContent
+	var got *http.Request
+	var body []byte
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer receiver.Close()
+
+	sender := NewSender(time.Second)
+	callback := &Callback{URL: receiver.URL, Secret: "secret", ID: "delivery-1", Event: "doctor.created", Body: []byte(`{"id":"1"}`)}
+	result, err := sender.Send(context.Background(), callback)
+	if err != nil || result.Status != http.StatusNoContent {
+		t.Fatalf("Send = %+v, %v", result, err)
+	}
+	if got.Header.Get(IDHeader) != "delivery-1" || got.Header.Get(EventHeader) != "doctor.created" {
+		t.Errorf("headers = %v", got.Header)
+	}
+	if err := Verify("secret", got.Header.Get(SignatureHeader), body, DefaultTolerance, time.Now()); err != nil {
+		t.Errorf("receiver Verify = %v", err)
+	}
+
+	status = http.StatusInternalServerError
+	result, err = sender.Send(context.Background(), callback)
+	if err == nil || result.Status != http.StatusInternalServerError || result.Body != "ok" {
+		t.Errorf("Send to a failing receiver = %+v, %v", result, err)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	for attempt, want := range map[int]time.Duration{1: time.Minute, 2: 2 * time.Minute, 3: 4 * time.Minute, 10: time.Hour} {
+		if got := Backoff(attempt, time.Minute, time.Hour); got != want {
+			t.Errorf("Backoff(%d) = %s, want %s", attempt, got, want)
+		}
+	}
+}
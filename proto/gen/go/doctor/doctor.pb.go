@@ -9,6 +9,7 @@ package proto
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
 	reflect "reflect"
 	sync "sync"
 )
@@ -26,13 +27,18 @@ type Doctor struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Id         string  `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`                       // ID of the Doctor
-	IdUser     string  `protobuf:"bytes,2,opt,name=id_user,json=idUser,proto3" json:"id_user,omitempty"` // User ID associated with the Doctor
-	Name       string  `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`                   // Name of the Doctor
-	Image      string  `protobuf:"bytes,4,opt,name=image,proto3" json:"image,omitempty"`                 // Image URL of the Doctor
-	Price      float32 `protobuf:"fixed32,5,opt,name=price,proto3" json:"price,omitempty"`               // Price of the Doctor
-	Specialist string  `protobuf:"bytes,6,opt,name=specialist,proto3" json:"specialist,omitempty"`       // Specialist of the Doctor
-	Experience int32   `protobuf:"varint,7,opt,name=experience,proto3" json:"experience,omitempty"`      // Experience of the Doctor in years
+	Id            string             `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`                                                                                                                                    // ID of the Doctor
+	IdUser        string             `protobuf:"bytes,2,opt,name=id_user,json=idUser,proto3" json:"id_user,omitempty"`                                                                                                              // User ID associated with the Doctor
+	Name          string             `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`                                                                                                                                // Name of the Doctor
+	Image         string             `protobuf:"bytes,4,opt,name=image,proto3" json:"image,omitempty"`                                                                                                                              // Image URL of the Doctor
+	Price         float32            `protobuf:"fixed32,5,opt,name=price,proto3" json:"price,omitempty"`                                                                                                                            // Price of the Doctor
+	Specialist    string             `protobuf:"bytes,6,opt,name=specialist,proto3" json:"specialist,omitempty"`                                                                                                                    // Specialist of the Doctor
+	Experience    int32              `protobuf:"varint,7,opt,name=experience,proto3" json:"experience,omitempty"`                                                                                                                   // Experience of the Doctor in years
+	ImageVariants map[string]string  `protobuf:"bytes,8,rep,name=image_variants,json=imageVariants,proto3" json:"image_variants,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"` // Thumbnails of the image by size and format
+	RatingAverage float64            `protobuf:"fixed64,9,opt,name=rating_average,json=ratingAverage,proto3" json:"rating_average,omitempty"`                                                                                       // Average rating of the visible reviews
+	RatingCount   int64              `protobuf:"varint,10,opt,name=rating_count,json=ratingCount,proto3" json:"rating_count,omitempty"`                                                                                             // Number of visible reviews
+	Specialties   []*DoctorSpecialty `protobuf:"bytes,11,rep,name=specialties,proto3" json:"specialties,omitempty"`                                                                                                                 // Specialties of the catalogue, one is primary
+	Version       int64              `protobuf:"varint,12,opt,name=version,proto3" json:"version,omitempty"`                                                                                                                        // Version of the Doctor, sent back by the updates and deletes
 }
 
 func (x *Doctor) Reset() {
@@ -116,6 +122,113 @@ func (x *Doctor) GetExperience() int32 {
 	return 0
 }
 
+func (x *Doctor) GetImageVariants() map[string]string {
+	if x != nil {
+		return x.ImageVariants
+	}
+	return nil
+}
+
+func (x *Doctor) GetRatingAverage() float64 {
+	if x != nil {
+		return x.RatingAverage
+	}
+	return 0
+}
+
+func (x *Doctor) GetRatingCount() int64 {
+	if x != nil {
+		return x.RatingCount
+	}
+	return 0
+}
+
+func (x *Doctor) GetSpecialties() []*DoctorSpecialty {
+	if x != nil {
+		return x.Specialties
+	}
+	return nil
+}
+
+func (x *Doctor) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+// DoctorSpecialty is a specialty of the catalogue of a Doctor
+type DoctorSpecialty struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SpecialtyId string `protobuf:"bytes,1,opt,name=specialty_id,json=specialtyId,proto3" json:"specialty_id,omitempty"`
+	Slug        string `protobuf:"bytes,2,opt,name=slug,proto3" json:"slug,omitempty"`
+	Name        string `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"` // Name in the default locale
+	Primary     bool   `protobuf:"varint,4,opt,name=primary,proto3" json:"primary,omitempty"`
+}
+
+func (x *DoctorSpecialty) Reset() {
+	*x = DoctorSpecialty{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_doctor_doctor_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DoctorSpecialty) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DoctorSpecialty) ProtoMessage() {}
+
+func (x *DoctorSpecialty) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_doctor_doctor_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DoctorSpecialty.ProtoReflect.Descriptor instead.
+func (*DoctorSpecialty) Descriptor() ([]byte, []int) {
+	return file_proto_doctor_doctor_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *DoctorSpecialty) GetSpecialtyId() string {
+	if x != nil {
+		return x.SpecialtyId
+	}
+	return ""
+}
+
+func (x *DoctorSpecialty) GetSlug() string {
+	if x != nil {
+		return x.Slug
+	}
+	return ""
+}
+
+func (x *DoctorSpecialty) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *DoctorSpecialty) GetPrimary() bool {
+	if x != nil {
+		return x.Primary
+	}
+	return false
+}
+
 // CreateDoctorReq message represents a request to create a new Doctor
 type CreateDoctorReq struct {
 	state         protoimpl.MessageState
@@ -133,7 +246,7 @@ type CreateDoctorReq struct {
 func (x *CreateDoctorReq) Reset() {
 	*x = CreateDoctorReq{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_doctor_doctor_proto_msgTypes[1]
+		mi := &file_proto_doctor_doctor_proto_msgTypes[2]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -146,7 +259,7 @@ func (x *CreateDoctorReq) String() string {
 func (*CreateDoctorReq) ProtoMessage() {}
 
 func (x *CreateDoctorReq) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_doctor_doctor_proto_msgTypes[1]
+	mi := &file_proto_doctor_doctor_proto_msgTypes[2]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -159,7 +272,7 @@ func (x *CreateDoctorReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateDoctorReq.ProtoReflect.Descriptor instead.
 func (*CreateDoctorReq) Descriptor() ([]byte, []int) {
-	return file_proto_doctor_doctor_proto_rawDescGZIP(), []int{1}
+	return file_proto_doctor_doctor_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *CreateDoctorReq) GetIdUser() string {
@@ -217,12 +330,16 @@ type UpdateDoctorReq struct {
 	Price      float32 `protobuf:"fixed32,5,opt,name=price,proto3" json:"price,omitempty"`               // Price of the Doctor
 	Specialist string  `protobuf:"bytes,6,opt,name=specialist,proto3" json:"specialist,omitempty"`       // Specialist of the Doctor
 	Experience int32   `protobuf:"varint,7,opt,name=experience,proto3" json:"experience,omitempty"`      // Experience of the Doctor in years
+	Version    int64   `protobuf:"varint,8,opt,name=version,proto3" json:"version,omitempty"`            // Version of the Doctor read, the update fails when it changed since
+	// Fields to change, among name, image, price, specialist and experience,
+	// the others are kept. All the fields are replaced when empty.
+	UpdateMask *fieldmaskpb.FieldMask `protobuf:"bytes,9,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
 }
 
 func (x *UpdateDoctorReq) Reset() {
 	*x = UpdateDoctorReq{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_doctor_doctor_proto_msgTypes[2]
+		mi := &file_proto_doctor_doctor_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -235,7 +352,7 @@ func (x *UpdateDoctorReq) String() string {
 func (*UpdateDoctorReq) ProtoMessage() {}
 
 func (x *UpdateDoctorReq) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_doctor_doctor_proto_msgTypes[2]
+	mi := &file_proto_doctor_doctor_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -248,7 +365,7 @@ func (x *UpdateDoctorReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateDoctorReq.ProtoReflect.Descriptor instead.
 func (*UpdateDoctorReq) Descriptor() ([]byte, []int) {
-	return file_proto_doctor_doctor_proto_rawDescGZIP(), []int{2}
+	return file_proto_doctor_doctor_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *UpdateDoctorReq) GetId() string {
@@ -300,6 +417,20 @@ func (x *UpdateDoctorReq) GetExperience() int32 {
 	return 0
 }
 
+func (x *UpdateDoctorReq) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *UpdateDoctorReq) GetUpdateMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
 // ListDoctorReq message represents query parameters for listing Doctors
 type ListDoctorReq struct {
 	state         protoimpl.MessageState
@@ -316,7 +447,7 @@ type ListDoctorReq struct {
 func (x *ListDoctorReq) Reset() {
 	*x = ListDoctorReq{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_doctor_doctor_proto_msgTypes[3]
+		mi := &file_proto_doctor_doctor_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -329,7 +460,7 @@ func (x *ListDoctorReq) String() string {
 func (*ListDoctorReq) ProtoMessage() {}
 
 func (x *ListDoctorReq) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_doctor_doctor_proto_msgTypes[3]
+	mi := &file_proto_doctor_doctor_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -342,7 +473,7 @@ func (x *ListDoctorReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListDoctorReq.ProtoReflect.Descriptor instead.
 func (*ListDoctorReq) Descriptor() ([]byte, []int) {
-	return file_proto_doctor_doctor_proto_rawDescGZIP(), []int{3}
+	return file_proto_doctor_doctor_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *ListDoctorReq) GetSearch() string {
@@ -392,7 +523,7 @@ type OrderBy struct {
 func (x *OrderBy) Reset() {
 	*x = OrderBy{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_doctor_doctor_proto_msgTypes[4]
+		mi := &file_proto_doctor_doctor_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -405,7 +536,7 @@ func (x *OrderBy) String() string {
 func (*OrderBy) ProtoMessage() {}
 
 func (x *OrderBy) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_doctor_doctor_proto_msgTypes[4]
+	mi := &file_proto_doctor_doctor_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -418,7 +549,7 @@ func (x *OrderBy) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use OrderBy.ProtoReflect.Descriptor instead.
 func (*OrderBy) Descriptor() ([]byte, []int) {
-	return file_proto_doctor_doctor_proto_rawDescGZIP(), []int{4}
+	return file_proto_doctor_doctor_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *OrderBy) GetOrderBy() string {
@@ -448,7 +579,7 @@ type ListDoctorRes struct {
 func (x *ListDoctorRes) Reset() {
 	*x = ListDoctorRes{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_doctor_doctor_proto_msgTypes[5]
+		mi := &file_proto_doctor_doctor_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -461,7 +592,7 @@ func (x *ListDoctorRes) String() string {
 func (*ListDoctorRes) ProtoMessage() {}
 
 func (x *ListDoctorRes) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_doctor_doctor_proto_msgTypes[5]
+	mi := &file_proto_doctor_doctor_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -474,7 +605,7 @@ func (x *ListDoctorRes) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListDoctorRes.ProtoReflect.Descriptor instead.
 func (*ListDoctorRes) Descriptor() ([]byte, []int) {
-	return file_proto_doctor_doctor_proto_rawDescGZIP(), []int{5}
+	return file_proto_doctor_doctor_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *ListDoctorRes) GetDoctors() []*Doctor {
@@ -497,14 +628,15 @@ type DeleteDoctorReq struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Id     string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`                       // ID of the Doctor
-	IdUser string `protobuf:"bytes,2,opt,name=id_user,json=idUser,proto3" json:"id_user,omitempty"` // User ID associated with the Doctor
+	Id      string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`                       // ID of the Doctor
+	IdUser  string `protobuf:"bytes,2,opt,name=id_user,json=idUser,proto3" json:"id_user,omitempty"` // User ID associated with the Doctor
+	Version int64  `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`            // Version of the Doctor read, the delete fails when it changed since
 }
 
 func (x *DeleteDoctorReq) Reset() {
 	*x = DeleteDoctorReq{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_doctor_doctor_proto_msgTypes[6]
+		mi := &file_proto_doctor_doctor_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -517,7 +649,7 @@ func (x *DeleteDoctorReq) String() string {
 func (*DeleteDoctorReq) ProtoMessage() {}
 
 func (x *DeleteDoctorReq) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_doctor_doctor_proto_msgTypes[6]
+	mi := &file_proto_doctor_doctor_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -530,7 +662,7 @@ func (x *DeleteDoctorReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteDoctorReq.ProtoReflect.Descriptor instead.
 func (*DeleteDoctorReq) Descriptor() ([]byte, []int) {
-	return file_proto_doctor_doctor_proto_rawDescGZIP(), []int{6}
+	return file_proto_doctor_doctor_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *DeleteDoctorReq) GetId() string {
@@ -547,6 +679,13 @@ func (x *DeleteDoctorReq) GetIdUser() string {
 	return ""
 }
 
+func (x *DeleteDoctorReq) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
 //	 // Doctor message
 //	 message Doctor {
 //	    // ID of the Doctor
@@ -584,7 +723,7 @@ type DoctorResponse struct {
 func (x *DoctorResponse) Reset() {
 	*x = DoctorResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_doctor_doctor_proto_msgTypes[7]
+		mi := &file_proto_doctor_doctor_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -597,7 +736,7 @@ func (x *DoctorResponse) String() string {
 func (*DoctorResponse) ProtoMessage() {}
 
 func (x *DoctorResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_doctor_doctor_proto_msgTypes[7]
+	mi := &file_proto_doctor_doctor_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -610,7 +749,7 @@ func (x *DoctorResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DoctorResponse.ProtoReflect.Descriptor instead.
 func (*DoctorResponse) Descriptor() ([]byte, []int) {
-	return file_proto_doctor_doctor_proto_rawDescGZIP(), []int{7}
+	return file_proto_doctor_doctor_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *DoctorResponse) GetDoctor() *Doctor {
@@ -636,7 +775,7 @@ type GetDoctorByIDRequest struct {
 func (x *GetDoctorByIDRequest) Reset() {
 	*x = GetDoctorByIDRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_doctor_doctor_proto_msgTypes[8]
+		mi := &file_proto_doctor_doctor_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -649,7 +788,7 @@ func (x *GetDoctorByIDRequest) String() string {
 func (*GetDoctorByIDRequest) ProtoMessage() {}
 
 func (x *GetDoctorByIDRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_doctor_doctor_proto_msgTypes[8]
+	mi := &file_proto_doctor_doctor_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -662,7 +801,7 @@ func (x *GetDoctorByIDRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetDoctorByIDRequest.ProtoReflect.Descriptor instead.
 func (*GetDoctorByIDRequest) Descriptor() ([]byte, []int) {
-	return file_proto_doctor_doctor_proto_rawDescGZIP(), []int{8}
+	return file_proto_doctor_doctor_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *GetDoctorByIDRequest) GetId() string {
@@ -692,7 +831,7 @@ type Pagination struct {
 func (x *Pagination) Reset() {
 	*x = Pagination{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_doctor_doctor_proto_msgTypes[9]
+		mi := &file_proto_doctor_doctor_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -705,7 +844,7 @@ func (x *Pagination) String() string {
 func (*Pagination) ProtoMessage() {}
 
 func (x *Pagination) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_doctor_doctor_proto_msgTypes[9]
+	mi := &file_proto_doctor_doctor_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -718,7 +857,7 @@ func (x *Pagination) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Pagination.ProtoReflect.Descriptor instead.
 func (*Pagination) Descriptor() ([]byte, []int) {
-	return file_proto_doctor_doctor_proto_rawDescGZIP(), []int{9}
+	return file_proto_doctor_doctor_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *Pagination) GetTotal() int64 {
@@ -747,99 +886,135 @@ var File_proto_doctor_doctor_proto protoreflect.FileDescriptor
 var file_proto_doctor_doctor_proto_rawDesc = []byte{
 	0x0a, 0x19, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x64, 0x6f, 0x63, 0x74, 0x6f, 0x72, 0x2f, 0x64,
 	0x6f, 0x63, 0x74, 0x6f, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x06, 0x64, 0x6f, 0x63,
-	0x74, 0x6f, 0x72, 0x22, 0xb1, 0x01, 0x0a, 0x06, 0x44, 0x6f, 0x63, 0x74, 0x6f, 0x72, 0x12, 0x0e,
-	0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x17,
-	0x0a, 0x07, 0x69, 0x64, 0x5f, 0x75, 0x73, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x06, 0x69, 0x64, 0x55, 0x73, 0x65, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18,
-	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x69,
-	0x6d, 0x61, 0x67, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x69, 0x6d, 0x61, 0x67,
-	0x65, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x72, 0x69, 0x63, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x02,
-	0x52, 0x05, 0x70, 0x72, 0x69, 0x63, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x73, 0x70, 0x65, 0x63, 0x69,
-	0x61, 0x6c, 0x69, 0x73, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x73, 0x70, 0x65,
-	0x63, 0x69, 0x61, 0x6c, 0x69, 0x73, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x65, 0x78, 0x70, 0x65, 0x72,
-	0x69, 0x65, 0x6e, 0x63, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x65, 0x78, 0x70,
-	0x65, 0x72, 0x69, 0x65, 0x6e, 0x63, 0x65, 0x22, 0xaa, 0x01, 0x0a, 0x0f, 0x43, 0x72, 0x65, 0x61,
-	0x74, 0x65, 0x44, 0x6f, 0x63, 0x74, 0x6f, 0x72, 0x52, 0x65, 0x71, 0x12, 0x17, 0x0a, 0x07, 0x69,
-	0x64, 0x5f, 0x75, 0x73, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x69, 0x64,
-	0x55, 0x73, 0x65, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6d, 0x61, 0x67,
-	0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x12, 0x14,
-	0x0a, 0x05, 0x70, 0x72, 0x69, 0x63, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x02, 0x52, 0x05, 0x70,
-	0x72, 0x69, 0x63, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x73, 0x70, 0x65, 0x63, 0x69, 0x61, 0x6c, 0x69,
-	0x73, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x73, 0x70, 0x65, 0x63, 0x69, 0x61,
-	0x6c, 0x69, 0x73, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x65, 0x78, 0x70, 0x65, 0x72, 0x69, 0x65, 0x6e,
-	0x63, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x65, 0x78, 0x70, 0x65, 0x72, 0x69,
-	0x65, 0x6e, 0x63, 0x65, 0x22, 0xba, 0x01, 0x0a, 0x0f, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x44,
-	0x6f, 0x63, 0x74, 0x6f, 0x72, 0x52, 0x65, 0x71, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x17, 0x0a, 0x07, 0x69, 0x64, 0x5f, 0x75,
-	0x73, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x69, 0x64, 0x55, 0x73, 0x65,
-	0x72, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x18, 0x04,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x70,
-	0x72, 0x69, 0x63, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x02, 0x52, 0x05, 0x70, 0x72, 0x69, 0x63,
-	0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x73, 0x70, 0x65, 0x63, 0x69, 0x61, 0x6c, 0x69, 0x73, 0x74, 0x18,
-	0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x73, 0x70, 0x65, 0x63, 0x69, 0x61, 0x6c, 0x69, 0x73,
-	0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x65, 0x78, 0x70, 0x65, 0x72, 0x69, 0x65, 0x6e, 0x63, 0x65, 0x18,
-	0x07, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x65, 0x78, 0x70, 0x65, 0x72, 0x69, 0x65, 0x6e, 0x63,
-	0x65, 0x22, 0x9a, 0x01, 0x0a, 0x0d, 0x4c, 0x69, 0x73, 0x74, 0x44, 0x6f, 0x63, 0x74, 0x6f, 0x72,
-	0x52, 0x65, 0x71, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x65, 0x61, 0x72, 0x63, 0x68, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x65, 0x61, 0x72, 0x63, 0x68, 0x12, 0x17, 0x0a, 0x07, 0x69,
-	0x64, 0x5f, 0x75, 0x73, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x69, 0x64,
-	0x55, 0x73, 0x65, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x67, 0x65, 0x18, 0x03, 0x20, 0x01,
-	0x28, 0x03, 0x52, 0x04, 0x70, 0x61, 0x67, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69,
-	0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x2e,
-	0x0a, 0x0a, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x5f, 0x6c, 0x69, 0x73, 0x74, 0x18, 0x05, 0x20, 0x03,
-	0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x64, 0x6f, 0x63, 0x74, 0x6f, 0x72, 0x2e, 0x4f, 0x72, 0x64, 0x65,
-	0x72, 0x42, 0x79, 0x52, 0x09, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x4c, 0x69, 0x73, 0x74, 0x22, 0x41,
-	0x0a, 0x07, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x42, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x4f, 0x72, 0x64,
-	0x65, 0x72, 0x42, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x4f, 0x72, 0x64, 0x65,
-	0x72, 0x42, 0x79, 0x12, 0x1c, 0x0a, 0x09, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x44, 0x65, 0x73, 0x63,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x44, 0x65, 0x73,
-	0x63, 0x22, 0x6d, 0x0a, 0x0d, 0x4c, 0x69, 0x73, 0x74, 0x44, 0x6f, 0x63, 0x74, 0x6f, 0x72, 0x52,
-	0x65, 0x73, 0x12, 0x28, 0x0a, 0x07, 0x64, 0x6f, 0x63, 0x74, 0x6f, 0x72, 0x73, 0x18, 0x01, 0x20,
-	0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x64, 0x6f, 0x63, 0x74, 0x6f, 0x72, 0x2e, 0x44, 0x6f, 0x63,
-	0x74, 0x6f, 0x72, 0x52, 0x07, 0x64, 0x6f, 0x63, 0x74, 0x6f, 0x72, 0x73, 0x12, 0x32, 0x0a, 0x0a,
-	0x70, 0x61, 0x67, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x12, 0x2e, 0x64, 0x6f, 0x63, 0x74, 0x6f, 0x72, 0x2e, 0x50, 0x61, 0x67, 0x69, 0x6e, 0x61,
-	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0a, 0x70, 0x61, 0x67, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e,
-	0x22, 0x3a, 0x0a, 0x0f, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x44, 0x6f, 0x63, 0x74, 0x6f, 0x72,
-	0x52, 0x65, 0x71, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x02, 0x69, 0x64, 0x12, 0x17, 0x0a, 0x07, 0x69, 0x64, 0x5f, 0x75, 0x73, 0x65, 0x72, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x69, 0x64, 0x55, 0x73, 0x65, 0x72, 0x22, 0x38, 0x0a, 0x0e,
-	0x44, 0x6f, 0x63, 0x74, 0x6f, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x26,
-	0x0a, 0x06, 0x44, 0x6f, 0x63, 0x74, 0x6f, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e,
-	0x2e, 0x64, 0x6f, 0x63, 0x74, 0x6f, 0x72, 0x2e, 0x44, 0x6f, 0x63, 0x74, 0x6f, 0x72, 0x52, 0x06,
-	0x44, 0x6f, 0x63, 0x74, 0x6f, 0x72, 0x22, 0x26, 0x0a, 0x14, 0x47, 0x65, 0x74, 0x44, 0x6f, 0x63,
-	0x74, 0x6f, 0x72, 0x42, 0x79, 0x49, 0x44, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e,
-	0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0x4c,
-	0x0a, 0x0a, 0x50, 0x61, 0x67, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x14, 0x0a, 0x05,
-	0x74, 0x6f, 0x74, 0x61, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x74, 0x6f, 0x74,
-	0x61, 0x6c, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03,
-	0x52, 0x04, 0x70, 0x61, 0x67, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18,
-	0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x32, 0xd6, 0x02, 0x0a,
-	0x0d, 0x44, 0x6f, 0x63, 0x74, 0x6f, 0x72, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x45,
-	0x0a, 0x0d, 0x47, 0x65, 0x74, 0x44, 0x6f, 0x63, 0x74, 0x6f, 0x72, 0x42, 0x79, 0x49, 0x44, 0x12,
-	0x1c, 0x2e, 0x64, 0x6f, 0x63, 0x74, 0x6f, 0x72, 0x2e, 0x47, 0x65, 0x74, 0x44, 0x6f, 0x63, 0x74,
-	0x6f, 0x72, 0x42, 0x79, 0x49, 0x44, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e,
-	0x64, 0x6f, 0x63, 0x74, 0x6f, 0x72, 0x2e, 0x44, 0x6f, 0x63, 0x74, 0x6f, 0x72, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3b, 0x0a, 0x0b, 0x4c, 0x69, 0x73, 0x74, 0x44, 0x6f, 0x63,
-	0x74, 0x6f, 0x72, 0x73, 0x12, 0x15, 0x2e, 0x64, 0x6f, 0x63, 0x74, 0x6f, 0x72, 0x2e, 0x4c, 0x69,
-	0x73, 0x74, 0x44, 0x6f, 0x63, 0x74, 0x6f, 0x72, 0x52, 0x65, 0x71, 0x1a, 0x15, 0x2e, 0x64, 0x6f,
-	0x63, 0x74, 0x6f, 0x72, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x44, 0x6f, 0x63, 0x74, 0x6f, 0x72, 0x52,
-	0x65, 0x73, 0x12, 0x3f, 0x0a, 0x0c, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x44, 0x6f, 0x63, 0x74,
-	0x6f, 0x72, 0x12, 0x17, 0x2e, 0x64, 0x6f, 0x63, 0x74, 0x6f, 0x72, 0x2e, 0x43, 0x72, 0x65, 0x61,
-	0x74, 0x65, 0x44, 0x6f, 0x63, 0x74, 0x6f, 0x72, 0x52, 0x65, 0x71, 0x1a, 0x16, 0x2e, 0x64, 0x6f,
-	0x63, 0x74, 0x6f, 0x72, 0x2e, 0x44, 0x6f, 0x63, 0x74, 0x6f, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x12, 0x3f, 0x0a, 0x0c, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x44, 0x6f, 0x63,
-	0x74, 0x6f, 0x72, 0x12, 0x17, 0x2e, 0x64, 0x6f, 0x63, 0x74, 0x6f, 0x72, 0x2e, 0x55, 0x70, 0x64,
-	0x61, 0x74, 0x65, 0x44, 0x6f, 0x63, 0x74, 0x6f, 0x72, 0x52, 0x65, 0x71, 0x1a, 0x16, 0x2e, 0x64,
-	0x6f, 0x63, 0x74, 0x6f, 0x72, 0x2e, 0x44, 0x6f, 0x63, 0x74, 0x6f, 0x72, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3f, 0x0a, 0x0c, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x44, 0x6f,
-	0x63, 0x74, 0x6f, 0x72, 0x12, 0x17, 0x2e, 0x64, 0x6f, 0x63, 0x74, 0x6f, 0x72, 0x2e, 0x44, 0x65,
-	0x6c, 0x65, 0x74, 0x65, 0x44, 0x6f, 0x63, 0x74, 0x6f, 0x72, 0x52, 0x65, 0x71, 0x1a, 0x16, 0x2e,
-	0x64, 0x6f, 0x63, 0x74, 0x6f, 0x72, 0x2e, 0x44, 0x6f, 0x63, 0x74, 0x6f, 0x72, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x0c, 0x5a, 0x0a, 0x6d, 0x61, 0x69, 0x6e, 0x2f, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x74, 0x6f, 0x72, 0x1a, 0x20, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2f, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x5f, 0x6d, 0x61, 0x73, 0x6b, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xdc, 0x03, 0x0a, 0x06, 0x44, 0x6f, 0x63, 0x74, 0x6f, 0x72,
+	0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64,
+	0x12, 0x17, 0x0a, 0x07, 0x69, 0x64, 0x5f, 0x75, 0x73, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x69, 0x64, 0x55, 0x73, 0x65, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d,
+	0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a,
+	0x05, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x69, 0x6d,
+	0x61, 0x67, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x72, 0x69, 0x63, 0x65, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x02, 0x52, 0x05, 0x70, 0x72, 0x69, 0x63, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x73, 0x70, 0x65,
+	0x63, 0x69, 0x61, 0x6c, 0x69, 0x73, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x73,
+	0x70, 0x65, 0x63, 0x69, 0x61, 0x6c, 0x69, 0x73, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x65, 0x78, 0x70,
+	0x65, 0x72, 0x69, 0x65, 0x6e, 0x63, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x65,
+	0x78, 0x70, 0x65, 0x72, 0x69, 0x65, 0x6e, 0x63, 0x65, 0x12, 0x48, 0x0a, 0x0e, 0x69, 0x6d, 0x61,
+	0x67, 0x65, 0x5f, 0x76, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x73, 0x18, 0x08, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x21, 0x2e, 0x64, 0x6f, 0x63, 0x74, 0x6f, 0x72, 0x2e, 0x44, 0x6f, 0x63, 0x74, 0x6f,
+	0x72, 0x2e, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x56, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x73, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x52, 0x0d, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x56, 0x61, 0x72, 0x69, 0x61,
+	0x6e, 0x74, 0x73, 0x12, 0x25, 0x0a, 0x0e, 0x72, 0x61, 0x74, 0x69, 0x6e, 0x67, 0x5f, 0x61, 0x76,
+	0x65, 0x72, 0x61, 0x67, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0d, 0x72, 0x61, 0x74,
+	0x69, 0x6e, 0x67, 0x41, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x72, 0x61,
+	0x74, 0x69, 0x6e, 0x67, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x0b, 0x72, 0x61, 0x74, 0x69, 0x6e, 0x67, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x39, 0x0a,
+	0x0b, 0x73, 0x70, 0x65, 0x63, 0x69, 0x61, 0x6c, 0x74, 0x69, 0x65, 0x73, 0x18, 0x0b, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x17, 0x2e, 0x64, 0x6f, 0x63, 0x74, 0x6f, 0x72, 0x2e, 0x44, 0x6f, 0x63, 0x74,
+	0x6f, 0x72, 0x53, 0x70, 0x65, 0x63, 0x69, 0x61, 0x6c, 0x74, 0x79, 0x52, 0x0b, 0x73, 0x70, 0x65,
+	0x63, 0x69, 0x61, 0x6c, 0x74, 0x69, 0x65, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x1a, 0x40, 0x0a, 0x12, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x56, 0x61, 0x72, 0x69, 0x61,
+	0x6e, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x3a, 0x02, 0x38, 0x01, 0x22, 0x76, 0x0a, 0x0f, 0x44, 0x6f, 0x63, 0x74, 0x6f, 0x72, 0x53, 0x70,
+	0x65, 0x63, 0x69, 0x61, 0x6c, 0x74, 0x79, 0x12, 0x21, 0x0a, 0x0c, 0x73, 0x70, 0x65, 0x63, 0x69,
+	0x61, 0x6c, 0x74, 0x79, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x73,
+	0x70, 0x65, 0x63, 0x69, 0x61, 0x6c, 0x74, 0x79, 0x49, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x6c,
+	0x75, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x73, 0x6c, 0x75, 0x67, 0x12, 0x12,
+	0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x72, 0x69, 0x6d, 0x61, 0x72, 0x79, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x07, 0x70, 0x72, 0x69, 0x6d, 0x61, 0x72, 0x79, 0x22, 0xaa, 0x01, 0x0a,
+	0x0f, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x44, 0x6f, 0x63, 0x74, 0x6f, 0x72, 0x52, 0x65, 0x71,
+	0x12, 0x17, 0x0a, 0x07, 0x69, 0x64, 0x5f, 0x75, 0x73, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x69, 0x64, 0x55, 0x73, 0x65, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a,
+	0x05, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x69, 0x6d,
+	0x61, 0x67, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x72, 0x69, 0x63, 0x65, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x02, 0x52, 0x05, 0x70, 0x72, 0x69, 0x63, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x73, 0x70, 0x65,
+	0x63, 0x69, 0x61, 0x6c, 0x69, 0x73, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x73,
+	0x70, 0x65, 0x63, 0x69, 0x61, 0x6c, 0x69, 0x73, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x65, 0x78, 0x70,
+	0x65, 0x72, 0x69, 0x65, 0x6e, 0x63, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x65,
+	0x78, 0x70, 0x65, 0x72, 0x69, 0x65, 0x6e, 0x63, 0x65, 0x22, 0x91, 0x02, 0x0a, 0x0f, 0x55, 0x70,
+	0x64, 0x61, 0x74, 0x65, 0x44, 0x6f, 0x63, 0x74, 0x6f, 0x72, 0x52, 0x65, 0x71, 0x12, 0x0e, 0x0a,
+	0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x17, 0x0a,
+	0x07, 0x69, 0x64, 0x5f, 0x75, 0x73, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x69, 0x64, 0x55, 0x73, 0x65, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6d,
+	0x61, 0x67, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x69, 0x6d, 0x61, 0x67, 0x65,
+	0x12, 0x14, 0x0a, 0x05, 0x70, 0x72, 0x69, 0x63, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x02, 0x52,
+	0x05, 0x70, 0x72, 0x69, 0x63, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x73, 0x70, 0x65, 0x63, 0x69, 0x61,
+	0x6c, 0x69, 0x73, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x73, 0x70, 0x65, 0x63,
+	0x69, 0x61, 0x6c, 0x69, 0x73, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x65, 0x78, 0x70, 0x65, 0x72, 0x69,
+	0x65, 0x6e, 0x63, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x65, 0x78, 0x70, 0x65,
+	0x72, 0x69, 0x65, 0x6e, 0x63, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x18, 0x08, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x12, 0x3b, 0x0a, 0x0b, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x5f, 0x6d, 0x61, 0x73, 0x6b, 0x18,
+	0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x4d, 0x61, 0x73,
+	0x6b, 0x52, 0x0a, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x4d, 0x61, 0x73, 0x6b, 0x22, 0x9a, 0x01,
+	0x0a, 0x0d, 0x4c, 0x69, 0x73, 0x74, 0x44, 0x6f, 0x63, 0x74, 0x6f, 0x72, 0x52, 0x65, 0x71, 0x12,
+	0x16, 0x0a, 0x06, 0x73, 0x65, 0x61, 0x72, 0x63, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x73, 0x65, 0x61, 0x72, 0x63, 0x68, 0x12, 0x17, 0x0a, 0x07, 0x69, 0x64, 0x5f, 0x75, 0x73,
+	0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x69, 0x64, 0x55, 0x73, 0x65, 0x72,
+	0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04,
+	0x70, 0x61, 0x67, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x2e, 0x0a, 0x0a, 0x6f, 0x72,
+	0x64, 0x65, 0x72, 0x5f, 0x6c, 0x69, 0x73, 0x74, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f,
+	0x2e, 0x64, 0x6f, 0x63, 0x74, 0x6f, 0x72, 0x2e, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x42, 0x79, 0x52,
+	0x09, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x4c, 0x69, 0x73, 0x74, 0x22, 0x41, 0x0a, 0x07, 0x4f, 0x72,
+	0x64, 0x65, 0x72, 0x42, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x42, 0x79,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x42, 0x79, 0x12,
+	0x1c, 0x0a, 0x09, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x44, 0x65, 0x73, 0x63, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x09, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x44, 0x65, 0x73, 0x63, 0x22, 0x6d, 0x0a,
+	0x0d, 0x4c, 0x69, 0x73, 0x74, 0x44, 0x6f, 0x63, 0x74, 0x6f, 0x72, 0x52, 0x65, 0x73, 0x12, 0x28,
+	0x0a, 0x07, 0x64, 0x6f, 0x63, 0x74, 0x6f, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x0e, 0x2e, 0x64, 0x6f, 0x63, 0x74, 0x6f, 0x72, 0x2e, 0x44, 0x6f, 0x63, 0x74, 0x6f, 0x72, 0x52,
+	0x07, 0x64, 0x6f, 0x63, 0x74, 0x6f, 0x72, 0x73, 0x12, 0x32, 0x0a, 0x0a, 0x70, 0x61, 0x67, 0x69,
+	0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x64,
+	0x6f, 0x63, 0x74, 0x6f, 0x72, 0x2e, 0x50, 0x61, 0x67, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x52, 0x0a, 0x70, 0x61, 0x67, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x54, 0x0a, 0x0f,
+	0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x44, 0x6f, 0x63, 0x74, 0x6f, 0x72, 0x52, 0x65, 0x71, 0x12,
+	0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12,
+	0x17, 0x0a, 0x07, 0x69, 0x64, 0x5f, 0x75, 0x73, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x69, 0x64, 0x55, 0x73, 0x65, 0x72, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x22, 0x38, 0x0a, 0x0e, 0x44, 0x6f, 0x63, 0x74, 0x6f, 0x72, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x26, 0x0a, 0x06, 0x44, 0x6f, 0x63, 0x74, 0x6f, 0x72, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x64, 0x6f, 0x63, 0x74, 0x6f, 0x72, 0x2e, 0x44, 0x6f,
+	0x63, 0x74, 0x6f, 0x72, 0x52, 0x06, 0x44, 0x6f, 0x63, 0x74, 0x6f, 0x72, 0x22, 0x26, 0x0a, 0x14,
+	0x47, 0x65, 0x74, 0x44, 0x6f, 0x63, 0x74, 0x6f, 0x72, 0x42, 0x79, 0x49, 0x44, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x02, 0x69, 0x64, 0x22, 0x4c, 0x0a, 0x0a, 0x50, 0x61, 0x67, 0x69, 0x6e, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x67, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x70, 0x61, 0x67, 0x65, 0x12, 0x14, 0x0a, 0x05,
+	0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x6c, 0x69, 0x6d,
+	0x69, 0x74, 0x32, 0xd6, 0x02, 0x0a, 0x0d, 0x44, 0x6f, 0x63, 0x74, 0x6f, 0x72, 0x53, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x12, 0x45, 0x0a, 0x0d, 0x47, 0x65, 0x74, 0x44, 0x6f, 0x63, 0x74, 0x6f,
+	0x72, 0x42, 0x79, 0x49, 0x44, 0x12, 0x1c, 0x2e, 0x64, 0x6f, 0x63, 0x74, 0x6f, 0x72, 0x2e, 0x47,
+	0x65, 0x74, 0x44, 0x6f, 0x63, 0x74, 0x6f, 0x72, 0x42, 0x79, 0x49, 0x44, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x64, 0x6f, 0x63, 0x74, 0x6f, 0x72, 0x2e, 0x44, 0x6f, 0x63,
+	0x74, 0x6f, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3b, 0x0a, 0x0b, 0x4c,
+	0x69, 0x73, 0x74, 0x44, 0x6f, 0x63, 0x74, 0x6f, 0x72, 0x73, 0x12, 0x15, 0x2e, 0x64, 0x6f, 0x63,
+	0x74, 0x6f, 0x72, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x44, 0x6f, 0x63, 0x74, 0x6f, 0x72, 0x52, 0x65,
+	0x71, 0x1a, 0x15, 0x2e, 0x64, 0x6f, 0x63, 0x74, 0x6f, 0x72, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x44,
+	0x6f, 0x63, 0x74, 0x6f, 0x72, 0x52, 0x65, 0x73, 0x12, 0x3f, 0x0a, 0x0c, 0x43, 0x72, 0x65, 0x61,
+	0x74, 0x65, 0x44, 0x6f, 0x63, 0x74, 0x6f, 0x72, 0x12, 0x17, 0x2e, 0x64, 0x6f, 0x63, 0x74, 0x6f,
+	0x72, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x44, 0x6f, 0x63, 0x74, 0x6f, 0x72, 0x52, 0x65,
+	0x71, 0x1a, 0x16, 0x2e, 0x64, 0x6f, 0x63, 0x74, 0x6f, 0x72, 0x2e, 0x44, 0x6f, 0x63, 0x74, 0x6f,
+	0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3f, 0x0a, 0x0c, 0x55, 0x70, 0x64,
+	0x61, 0x74, 0x65, 0x44, 0x6f, 0x63, 0x74, 0x6f, 0x72, 0x12, 0x17, 0x2e, 0x64, 0x6f, 0x63, 0x74,
+	0x6f, 0x72, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x44, 0x6f, 0x63, 0x74, 0x6f, 0x72, 0x52,
+	0x65, 0x71, 0x1a, 0x16, 0x2e, 0x64, 0x6f, 0x63, 0x74, 0x6f, 0x72, 0x2e, 0x44, 0x6f, 0x63, 0x74,
+	0x6f, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3f, 0x0a, 0x0c, 0x44, 0x65,
+	0x6c, 0x65, 0x74, 0x65, 0x44, 0x6f, 0x63, 0x74, 0x6f, 0x72, 0x12, 0x17, 0x2e, 0x64, 0x6f, 0x63,
+	0x74, 0x6f, 0x72, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x44, 0x6f, 0x63, 0x74, 0x6f, 0x72,
+	0x52, 0x65, 0x71, 0x1a, 0x16, 0x2e, 0x64, 0x6f, 0x63, 0x74, 0x6f, 0x72, 0x2e, 0x44, 0x6f, 0x63,
+	0x74, 0x6f, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x0c, 0x5a, 0x0a, 0x6d,
+	0x61, 0x69, 0x6e, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x33,
 }
 
 var (
@@ -854,39 +1029,45 @@ func file_proto_doctor_doctor_proto_rawDescGZIP() []byte {
 	return file_proto_doctor_doctor_proto_rawDescData
 }
 
-var file_proto_doctor_doctor_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_proto_doctor_doctor_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
 var file_proto_doctor_doctor_proto_goTypes = []any{
-	(*Doctor)(nil),               // 0: doctor.Doctor
-	(*CreateDoctorReq)(nil),      // 1: doctor.CreateDoctorReq
-	(*UpdateDoctorReq)(nil),      // 2: doctor.UpdateDoctorReq
-	(*ListDoctorReq)(nil),        // 3: doctor.ListDoctorReq
-	(*OrderBy)(nil),              // 4: doctor.OrderBy
-	(*ListDoctorRes)(nil),        // 5: doctor.ListDoctorRes
-	(*DeleteDoctorReq)(nil),      // 6: doctor.DeleteDoctorReq
-	(*DoctorResponse)(nil),       // 7: doctor.DoctorResponse
-	(*GetDoctorByIDRequest)(nil), // 8: doctor.GetDoctorByIDRequest
-	(*Pagination)(nil),           // 9: doctor.Pagination
+	(*Doctor)(nil),                // 0: doctor.Doctor
+	(*DoctorSpecialty)(nil),       // 1: doctor.DoctorSpecialty
+	(*CreateDoctorReq)(nil),       // 2: doctor.CreateDoctorReq
+	(*UpdateDoctorReq)(nil),       // 3: doctor.UpdateDoctorReq
+	(*ListDoctorReq)(nil),         // 4: doctor.ListDoctorReq
+	(*OrderBy)(nil),               // 5: doctor.OrderBy
+	(*ListDoctorRes)(nil),         // 6: doctor.ListDoctorRes
+	(*DeleteDoctorReq)(nil),       // 7: doctor.DeleteDoctorReq
+	(*DoctorResponse)(nil),        // 8: doctor.DoctorResponse
+	(*GetDoctorByIDRequest)(nil),  // 9: doctor.GetDoctorByIDRequest
+	(*Pagination)(nil),            // 10: doctor.Pagination
+	nil,                           // 11: doctor.Doctor.ImageVariantsEntry
+	(*fieldmaskpb.FieldMask)(nil), // 12: google.protobuf.FieldMask
 }
 var file_proto_doctor_doctor_proto_depIdxs = []int32{
-	4, // 0: doctor.ListDoctorReq.order_list:type_name -> doctor.OrderBy
-	0, // 1: doctor.ListDoctorRes.doctors:type_name -> doctor.Doctor
-	9, // 2: doctor.ListDoctorRes.pagination:type_name -> doctor.Pagination
-	0, // 3: doctor.DoctorResponse.Doctor:type_name -> doctor.Doctor
-	8, // 4: doctor.DoctorService.GetDoctorByID:input_type -> doctor.GetDoctorByIDRequest
-	3, // 5: doctor.DoctorService.ListDoctors:input_type -> doctor.ListDoctorReq
-	1, // 6: doctor.DoctorService.CreateDoctor:input_type -> doctor.CreateDoctorReq
-	2, // 7: doctor.DoctorService.UpdateDoctor:input_type -> doctor.UpdateDoctorReq
-	6, // 8: doctor.DoctorService.DeleteDoctor:input_type -> doctor.DeleteDoctorReq
-	7, // 9: doctor.DoctorService.GetDoctorByID:output_type -> doctor.DoctorResponse
-	5, // 10: doctor.DoctorService.ListDoctors:output_type -> doctor.ListDoctorRes
-	7, // 11: doctor.DoctorService.CreateDoctor:output_type -> doctor.DoctorResponse
-	7, // 12: doctor.DoctorService.UpdateDoctor:output_type -> doctor.DoctorResponse
-	7, // 13: doctor.DoctorService.DeleteDoctor:output_type -> doctor.DoctorResponse
-	9, // [9:14] is the sub-list for method output_type
-	4, // [4:9] is the sub-list for method input_type
-	4, // [4:4] is the sub-list for extension type_name
-	4, // [4:4] is the sub-list for extension extendee
-	0, // [0:4] is the sub-list for field type_name
+	11, // 0: doctor.Doctor.image_variants:type_name -> doctor.Doctor.ImageVariantsEntry
+	1,  // 1: doctor.Doctor.specialties:type_name -> doctor.DoctorSpecialty
+	12, // 2: doctor.UpdateDoctorReq.update_mask:type_name -> google.protobuf.FieldMask
+	5,  // 3: doctor.ListDoctorReq.order_list:type_name -> doctor.OrderBy
+	0,  // 4: doctor.ListDoctorRes.doctors:type_name -> doctor.Doctor
+	10, // 5: doctor.ListDoctorRes.pagination:type_name -> doctor.Pagination
+	0,  // 6: doctor.DoctorResponse.Doctor:type_name -> doctor.Doctor
+	9,  // 7: doctor.DoctorService.GetDoctorByID:input_type -> doctor.GetDoctorByIDRequest
+	4,  // 8: doctor.DoctorService.ListDoctors:input_type -> doctor.ListDoctorReq
+	2,  // 9: doctor.DoctorService.CreateDoctor:input_type -> doctor.CreateDoctorReq
+	3,  // 10: doctor.DoctorService.UpdateDoctor:input_type -> doctor.UpdateDoctorReq
+	7,  // 11: doctor.DoctorService.DeleteDoctor:input_type -> doctor.DeleteDoctorReq
+	8,  // 12: doctor.DoctorService.GetDoctorByID:output_type -> doctor.DoctorResponse
+	6,  // 13: doctor.DoctorService.ListDoctors:output_type -> doctor.ListDoctorRes
+	8,  // 14: doctor.DoctorService.CreateDoctor:output_type -> doctor.DoctorResponse
+	8,  // 15: doctor.DoctorService.UpdateDoctor:output_type -> doctor.DoctorResponse
+	8,  // 16: doctor.DoctorService.DeleteDoctor:output_type -> doctor.DoctorResponse
+	12, // [12:17] is the sub-list for method output_type
+	7,  // [7:12] is the sub-list for method input_type
+	7,  // [7:7] is the sub-list for extension type_name
+	7,  // [7:7] is the sub-list for extension extendee
+	0,  // [0:7] is the sub-list for field type_name
 }
 
 func init() { file_proto_doctor_doctor_proto_init() }
@@ -908,7 +1089,7 @@ func file_proto_doctor_doctor_proto_init() {
 			}
 		}
 		file_proto_doctor_doctor_proto_msgTypes[1].Exporter = func(v any, i int) any {
-			switch v := v.(*CreateDoctorReq); i {
+			switch v := v.(*DoctorSpecialty); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -920,7 +1101,7 @@ func file_proto_doctor_doctor_proto_init() {
 			}
 		}
 		file_proto_doctor_doctor_proto_msgTypes[2].Exporter = func(v any, i int) any {
-			switch v := v.(*UpdateDoctorReq); i {
+			switch v := v.(*CreateDoctorReq); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -932,7 +1113,7 @@ func file_proto_doctor_doctor_proto_init() {
 			}
 		}
 		file_proto_doctor_doctor_proto_msgTypes[3].Exporter = func(v any, i int) any {
-			switch v := v.(*ListDoctorReq); i {
+			switch v := v.(*UpdateDoctorReq); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -944,7 +1125,7 @@ func file_proto_doctor_doctor_proto_init() {
 			}
 		}
 		file_proto_doctor_doctor_proto_msgTypes[4].Exporter = func(v any, i int) any {
-			switch v := v.(*OrderBy); i {
+			switch v := v.(*ListDoctorReq); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -956,7 +1137,7 @@ func file_proto_doctor_doctor_proto_init() {
 			}
 		}
 		file_proto_doctor_doctor_proto_msgTypes[5].Exporter = func(v any, i int) any {
-			switch v := v.(*ListDoctorRes); i {
+			switch v := v.(*OrderBy); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -968,7 +1149,7 @@ func file_proto_doctor_doctor_proto_init() {
 			}
 		}
 		file_proto_doctor_doctor_proto_msgTypes[6].Exporter = func(v any, i int) any {
-			switch v := v.(*DeleteDoctorReq); i {
+			switch v := v.(*ListDoctorRes); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -980,7 +1161,7 @@ func file_proto_doctor_doctor_proto_init() {
 			}
 		}
 		file_proto_doctor_doctor_proto_msgTypes[7].Exporter = func(v any, i int) any {
-			switch v := v.(*DoctorResponse); i {
+			switch v := v.(*DeleteDoctorReq); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -992,7 +1173,7 @@ func file_proto_doctor_doctor_proto_init() {
 			}
 		}
 		file_proto_doctor_doctor_proto_msgTypes[8].Exporter = func(v any, i int) any {
-			switch v := v.(*GetDoctorByIDRequest); i {
+			switch v := v.(*DoctorResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1004,6 +1185,18 @@ func file_proto_doctor_doctor_proto_init() {
 			}
 		}
 		file_proto_doctor_doctor_proto_msgTypes[9].Exporter = func(v any, i int) any {
+			switch v := v.(*GetDoctorByIDRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_doctor_doctor_proto_msgTypes[10].Exporter = func(v any, i int) any {
 			switch v := v.(*Pagination); i {
 			case 0:
 				return &v.state
@@ -1022,7 +1215,7 @@ func file_proto_doctor_doctor_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_proto_doctor_doctor_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   10,
+			NumMessages:   12,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -0,0 +1,588 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.4.0
+// - protoc             v5.26.1
+// source: proto/health/health.proto
+
+package proto
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.62.0 or later.
+const _ = grpc.SupportPackageIsVersion8
+
+const (
+	HealthService_GetProfile_FullMethodName     = "/health.HealthService/GetProfile"
+	HealthService_SetBloodType_FullMethodName   = "/health.HealthService/SetBloodType"
+	HealthService_SaveAllergy_FullMethodName    = "/health.HealthService/SaveAllergy"
+	HealthService_SaveCondition_FullMethodName  = "/health.HealthService/SaveCondition"
+	HealthService_SaveMedication_FullMethodName = "/health.HealthService/SaveMedication"
+	HealthService_DeleteItem_FullMethodName     = "/health.HealthService/DeleteItem"
+	HealthService_RecordVital_FullMethodName    = "/health.HealthService/RecordVital"
+	HealthService_ListVitals_FullMethodName     = "/health.HealthService/ListVitals"
+	HealthService_ListChanges_FullMethodName    = "/health.HealthService/ListChanges"
+	HealthService_ListGrants_FullMethodName     = "/health.HealthService/ListGrants"
+	HealthService_GrantAccess_FullMethodName    = "/health.HealthService/GrantAccess"
+	HealthService_RevokeAccess_FullMethodName   = "/health.HealthService/RevokeAccess"
+	HealthService_ListPatients_FullMethodName   = "/health.HealthService/ListPatients"
+)
+
+// HealthServiceClient is the client API for HealthService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// =============================================================================//
+// HealthService manages the health profile of patients, for the patient
+// itself and the doctors it gave access. patient_id "me" is the signed-in
+// user.
+type HealthServiceClient interface {
+	GetProfile(ctx context.Context, in *GetProfileReq, opts ...grpc.CallOption) (*HealthProfile, error)
+	SetBloodType(ctx context.Context, in *SetBloodTypeReq, opts ...grpc.CallOption) (*SetBloodTypeRes, error)
+	// SaveAllergy adds an allergy when id is empty, else updates it, so do
+	// SaveCondition and SaveMedication
+	SaveAllergy(ctx context.Context, in *SaveAllergyReq, opts ...grpc.CallOption) (*Allergy, error)
+	SaveCondition(ctx context.Context, in *SaveConditionReq, opts ...grpc.CallOption) (*Condition, error)
+	SaveMedication(ctx context.Context, in *SaveMedicationReq, opts ...grpc.CallOption) (*Medication, error)
+	// DeleteItem deletes an allergy, condition or medication
+	DeleteItem(ctx context.Context, in *DeleteItemReq, opts ...grpc.CallOption) (*DeleteItemRes, error)
+	RecordVital(ctx context.Context, in *RecordVitalReq, opts ...grpc.CallOption) (*Vital, error)
+	ListVitals(ctx context.Context, in *ListVitalsReq, opts ...grpc.CallOption) (*ListVitalsRes, error)
+	ListChanges(ctx context.Context, in *ListChangesReq, opts ...grpc.CallOption) (*ListChangesRes, error)
+	// ListGrants, GrantAccess and RevokeAccess act on the doctors the
+	// signed-in patient gave access
+	ListGrants(ctx context.Context, in *ListGrantsReq, opts ...grpc.CallOption) (*ListGrantsRes, error)
+	GrantAccess(ctx context.Context, in *GrantAccessReq, opts ...grpc.CallOption) (*Grant, error)
+	RevokeAccess(ctx context.Context, in *RevokeAccessReq, opts ...grpc.CallOption) (*RevokeAccessRes, error)
+	// ListPatients lists the patients who gave the signed-in doctor access
+	ListPatients(ctx context.Context, in *ListPatientsReq, opts ...grpc.CallOption) (*ListGrantsRes, error)
+}
+
+type healthServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewHealthServiceClient(cc grpc.ClientConnInterface) HealthServiceClient {
+	return &healthServiceClient{cc}
+}
+
+func (c *healthServiceClient) GetProfile(ctx context.Context, in *GetProfileReq, opts ...grpc.CallOption) (*HealthProfile, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HealthProfile)
+	err := c.cc.Invoke(ctx, HealthService_GetProfile_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *healthServiceClient) SetBloodType(ctx context.Context, in *SetBloodTypeReq, opts ...grpc.CallOption) (*SetBloodTypeRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetBloodTypeRes)
+	err := c.cc.Invoke(ctx, HealthService_SetBloodType_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *healthServiceClient) SaveAllergy(ctx context.Context, in *SaveAllergyReq, opts ...grpc.CallOption) (*Allergy, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Allergy)
+	err := c.cc.Invoke(ctx, HealthService_SaveAllergy_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *healthServiceClient) SaveCondition(ctx context.Context, in *SaveConditionReq, opts ...grpc.CallOption) (*Condition, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Condition)
+	err := c.cc.Invoke(ctx, HealthService_SaveCondition_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *healthServiceClient) SaveMedication(ctx context.Context, in *SaveMedicationReq, opts ...grpc.CallOption) (*Medication, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Medication)
+	err := c.cc.Invoke(ctx, HealthService_SaveMedication_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *healthServiceClient) DeleteItem(ctx context.Context, in *DeleteItemReq, opts ...grpc.CallOption) (*DeleteItemRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteItemRes)
+	err := c.cc.Invoke(ctx, HealthService_DeleteItem_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *healthServiceClient) RecordVital(ctx context.Context, in *RecordVitalReq, opts ...grpc.CallOption) (*Vital, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Vital)
+	err := c.cc.Invoke(ctx, HealthService_RecordVital_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *healthServiceClient) ListVitals(ctx context.Context, in *ListVitalsReq, opts ...grpc.CallOption) (*ListVitalsRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListVitalsRes)
+	err := c.cc.Invoke(ctx, HealthService_ListVitals_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *healthServiceClient) ListChanges(ctx context.Context, in *ListChangesReq, opts ...grpc.CallOption) (*ListChangesRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListChangesRes)
+	err := c.cc.Invoke(ctx, HealthService_ListChanges_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *healthServiceClient) ListGrants(ctx context.Context, in *ListGrantsReq, opts ...grpc.CallOption) (*ListGrantsRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListGrantsRes)
+	err := c.cc.Invoke(ctx, HealthService_ListGrants_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *healthServiceClient) GrantAccess(ctx context.Context, in *GrantAccessReq, opts ...grpc.CallOption) (*Grant, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Grant)
+	err := c.cc.Invoke(ctx, HealthService_GrantAccess_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *healthServiceClient) RevokeAccess(ctx context.Context, in *RevokeAccessReq, opts ...grpc.CallOption) (*RevokeAccessRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RevokeAccessRes)
+	err := c.cc.Invoke(ctx, HealthService_RevokeAccess_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *healthServiceClient) ListPatients(ctx context.Context, in *ListPatientsReq, opts ...grpc.CallOption) (*ListGrantsRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListGrantsRes)
+	err := c.cc.Invoke(ctx, HealthService_ListPatients_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// HealthServiceServer is the server API for HealthService service.
+// All implementations must embed UnimplementedHealthServiceServer
+// for forward compatibility
+//
+// =============================================================================//
+// HealthService manages the health profile of patients, for the patient
+// itself and the doctors it gave access. patient_id "me" is the signed-in
+// user.
+type HealthServiceServer interface {
+	GetProfile(context.Context, *GetProfileReq) (*HealthProfile, error)
+	SetBloodType(context.Context, *SetBloodTypeReq) (*SetBloodTypeRes, error)
+	// SaveAllergy adds an allergy when id is empty, else updates it, so do
+	// SaveCondition and SaveMedication
+	SaveAllergy(context.Context, *SaveAllergyReq) (*Allergy, error)
+	SaveCondition(context.Context, *SaveConditionReq) (*Condition, error)
+	SaveMedication(context.Context, *SaveMedicationReq) (*Medication, error)
+	// DeleteItem deletes an allergy, condition or medication
+	DeleteItem(context.Context, *DeleteItemReq) (*DeleteItemRes, error)
+	RecordVital(context.Context, *RecordVitalReq) (*Vital, error)
+	ListVitals(context.Context, *ListVitalsReq) (*ListVitalsRes, error)
+	ListChanges(context.Context, *ListChangesReq) (*ListChangesRes, error)
+	// ListGrants, GrantAccess and RevokeAccess act on the doctors the
+	// signed-in patient gave access
+	ListGrants(context.Context, *ListGrantsReq) (*ListGrantsRes, error)
+	GrantAccess(context.Context, *GrantAccessReq) (*Grant, error)
+	RevokeAccess(context.Context, *RevokeAccessReq) (*RevokeAccessRes, error)
+	// ListPatients lists the patients who gave the signed-in doctor access
+	ListPatients(context.Context, *ListPatientsReq) (*ListGrantsRes, error)
+	mustEmbedUnimplementedHealthServiceServer()
+}
+
+// UnimplementedHealthServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedHealthServiceServer struct {
+}
+
+func (UnimplementedHealthServiceServer) GetProfile(context.Context, *GetProfileReq) (*HealthProfile, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetProfile not implemented")
+}
+func (UnimplementedHealthServiceServer) SetBloodType(context.Context, *SetBloodTypeReq) (*SetBloodTypeRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetBloodType not implemented")
+}
+func (UnimplementedHealthServiceServer) SaveAllergy(context.Context, *SaveAllergyReq) (*Allergy, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SaveAllergy not implemented")
+}
+func (UnimplementedHealthServiceServer) SaveCondition(context.Context, *SaveConditionReq) (*Condition, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SaveCondition not implemented")
+}
+func (UnimplementedHealthServiceServer) SaveMedication(context.Context, *SaveMedicationReq) (*Medication, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SaveMedication not implemented")
+}
+func (UnimplementedHealthServiceServer) DeleteItem(context.Context, *DeleteItemReq) (*DeleteItemRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteItem not implemented")
+}
+func (UnimplementedHealthServiceServer) RecordVital(context.Context, *RecordVitalReq) (*Vital, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RecordVital not implemented")
+}
+func (UnimplementedHealthServiceServer) ListVitals(context.Context, *ListVitalsReq) (*ListVitalsRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListVitals not implemented")
+}
+func (UnimplementedHealthServiceServer) ListChanges(context.Context, *ListChangesReq) (*ListChangesRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListChanges not implemented")
+}
+func (UnimplementedHealthServiceServer) ListGrants(context.Context, *ListGrantsReq) (*ListGrantsRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListGrants not implemented")
+}
+func (UnimplementedHealthServiceServer) GrantAccess(context.Context, *GrantAccessReq) (*Grant, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GrantAccess not implemented")
+}
+func (UnimplementedHealthServiceServer) RevokeAccess(context.Context, *RevokeAccessReq) (*RevokeAccessRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeAccess not implemented")
+}
+func (UnimplementedHealthServiceServer) ListPatients(context.Context, *ListPatientsReq) (*ListGrantsRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPatients not implemented")
+}
+func (UnimplementedHealthServiceServer) mustEmbedUnimplementedHealthServiceServer() {}
+
+// UnsafeHealthServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to HealthServiceServer will
+// result in compilation errors.
+type UnsafeHealthServiceServer interface {
+	mustEmbedUnimplementedHealthServiceServer()
+}
+
+func RegisterHealthServiceServer(s grpc.ServiceRegistrar, srv HealthServiceServer) {
+	s.RegisterService(&HealthService_ServiceDesc, srv)
+}
+
+func _HealthService_GetProfile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetProfileReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HealthServiceServer).GetProfile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HealthService_GetProfile_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HealthServiceServer).GetProfile(ctx, req.(*GetProfileReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HealthService_SetBloodType_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetBloodTypeReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HealthServiceServer).SetBloodType(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HealthService_SetBloodType_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HealthServiceServer).SetBloodType(ctx, req.(*SetBloodTypeReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HealthService_SaveAllergy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SaveAllergyReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HealthServiceServer).SaveAllergy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HealthService_SaveAllergy_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HealthServiceServer).SaveAllergy(ctx, req.(*SaveAllergyReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HealthService_SaveCondition_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SaveConditionReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HealthServiceServer).SaveCondition(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HealthService_SaveCondition_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HealthServiceServer).SaveCondition(ctx, req.(*SaveConditionReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HealthService_SaveMedication_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SaveMedicationReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HealthServiceServer).SaveMedication(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HealthService_SaveMedication_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HealthServiceServer).SaveMedication(ctx, req.(*SaveMedicationReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HealthService_DeleteItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteItemReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HealthServiceServer).DeleteItem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HealthService_DeleteItem_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HealthServiceServer).DeleteItem(ctx, req.(*DeleteItemReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HealthService_RecordVital_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RecordVitalReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HealthServiceServer).RecordVital(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HealthService_RecordVital_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HealthServiceServer).RecordVital(ctx, req.(*RecordVitalReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HealthService_ListVitals_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListVitalsReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HealthServiceServer).ListVitals(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HealthService_ListVitals_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HealthServiceServer).ListVitals(ctx, req.(*ListVitalsReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HealthService_ListChanges_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListChangesReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HealthServiceServer).ListChanges(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HealthService_ListChanges_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HealthServiceServer).ListChanges(ctx, req.(*ListChangesReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HealthService_ListGrants_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListGrantsReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HealthServiceServer).ListGrants(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HealthService_ListGrants_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HealthServiceServer).ListGrants(ctx, req.(*ListGrantsReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HealthService_GrantAccess_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GrantAccessReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HealthServiceServer).GrantAccess(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HealthService_GrantAccess_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HealthServiceServer).GrantAccess(ctx, req.(*GrantAccessReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HealthService_RevokeAccess_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeAccessReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HealthServiceServer).RevokeAccess(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HealthService_RevokeAccess_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HealthServiceServer).RevokeAccess(ctx, req.(*RevokeAccessReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HealthService_ListPatients_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPatientsReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HealthServiceServer).ListPatients(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HealthService_ListPatients_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HealthServiceServer).ListPatients(ctx, req.(*ListPatientsReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// HealthService_ServiceDesc is the grpc.ServiceDesc for HealthService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var HealthService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "health.HealthService",
+	HandlerType: (*HealthServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetProfile",
+			Handler:    _HealthService_GetProfile_Handler,
+		},
+		{
+			MethodName: "SetBloodType",
+			Handler:    _HealthService_SetBloodType_Handler,
+		},
+		{
+			MethodName: "SaveAllergy",
+			Handler:    _HealthService_SaveAllergy_Handler,
+		},
+		{
+			MethodName: "SaveCondition",
+			Handler:    _HealthService_SaveCondition_Handler,
+		},
+		{
+			MethodName: "SaveMedication",
+			Handler:    _HealthService_SaveMedication_Handler,
+		},
+		{
+			MethodName: "DeleteItem",
+			Handler:    _HealthService_DeleteItem_Handler,
+		},
+		{
+			MethodName: "RecordVital",
+			Handler:    _HealthService_RecordVital_Handler,
+		},
+		{
+			MethodName: "ListVitals",
+			Handler:    _HealthService_ListVitals_Handler,
+		},
+		{
+			MethodName: "ListChanges",
+			Handler:    _HealthService_ListChanges_Handler,
+		},
+		{
+			MethodName: "ListGrants",
+			Handler:    _HealthService_ListGrants_Handler,
+		},
+		{
+			MethodName: "GrantAccess",
+			Handler:    _HealthService_GrantAccess_Handler,
+		},
+		{
+			MethodName: "RevokeAccess",
+			Handler:    _HealthService_RevokeAccess_Handler,
+		},
+		{
+			MethodName: "ListPatients",
+			Handler:    _HealthService_ListPatients_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/health/health.proto",
+}
@@ -0,0 +1,188 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.4.0
+// - protoc             v5.26.1
+// source: proto/realtime/realtime.proto
+
+package proto
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.62.0 or later.
+const _ = grpc.SupportPackageIsVersion8
+
+const (
+	RealtimeService_Subscribe_FullMethodName = "/realtime.RealtimeService/Subscribe"
+	RealtimeService_Broadcast_FullMethodName = "/realtime.RealtimeService/Broadcast"
+)
+
+// RealtimeServiceClient is the client API for RealtimeService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// =============================================================================//
+// RealtimeService pushes the messages of the users as they happen
+type RealtimeServiceClient interface {
+	// Subscribe streams the messages of the caller and the broadcasts until
+	// the call is cancelled, a heartbeat is sent when it is idle
+	Subscribe(ctx context.Context, in *SubscribeReq, opts ...grpc.CallOption) (RealtimeService_SubscribeClient, error)
+	// Broadcast pushes a message to every connected user
+	Broadcast(ctx context.Context, in *BroadcastReq, opts ...grpc.CallOption) (*Message, error)
+}
+
+type realtimeServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewRealtimeServiceClient(cc grpc.ClientConnInterface) RealtimeServiceClient {
+	return &realtimeServiceClient{cc}
+}
+
+func (c *realtimeServiceClient) Subscribe(ctx context.Context, in *SubscribeReq, opts ...grpc.CallOption) (RealtimeService_SubscribeClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &RealtimeService_ServiceDesc.Streams[0], RealtimeService_Subscribe_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &realtimeServiceSubscribeClient{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type RealtimeService_SubscribeClient interface {
+	Recv() (*Message, error)
+	grpc.ClientStream
+}
+
+type realtimeServiceSubscribeClient struct {
+	grpc.ClientStream
+}
+
+func (x *realtimeServiceSubscribeClient) Recv() (*Message, error) {
+	m := new(Message)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *realtimeServiceClient) Broadcast(ctx context.Context, in *BroadcastReq, opts ...grpc.CallOption) (*Message, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Message)
+	err := c.cc.Invoke(ctx, RealtimeService_Broadcast_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RealtimeServiceServer is the server API for RealtimeService service.
+// All implementations must embed UnimplementedRealtimeServiceServer
+// for forward compatibility
+//
+// =============================================================================//
+// RealtimeService pushes the messages of the users as they happen
+type RealtimeServiceServer interface {
+	// Subscribe streams the messages of the caller and the broadcasts until
+	// the call is cancelled, a heartbeat is sent when it is idle
+	Subscribe(*SubscribeReq, RealtimeService_SubscribeServer) error
+	// Broadcast pushes a message to every connected user
+	Broadcast(context.Context, *BroadcastReq) (*Message, error)
+	mustEmbedUnimplementedRealtimeServiceServer()
+}
+
+// UnimplementedRealtimeServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedRealtimeServiceServer struct {
+}
+
+func (UnimplementedRealtimeServiceServer) Subscribe(*SubscribeReq, RealtimeService_SubscribeServer) error {
+	return status.Errorf(codes.Unimplemented, "method Subscribe not implemented")
+}
+func (UnimplementedRealtimeServiceServer) Broadcast(context.Context, *BroadcastReq) (*Message, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Broadcast not implemented")
+}
+func (UnimplementedRealtimeServiceServer) mustEmbedUnimplementedRealtimeServiceServer() {}
+
+// UnsafeRealtimeServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to RealtimeServiceServer will
+// result in compilation errors.
+type UnsafeRealtimeServiceServer interface {
+	mustEmbedUnimplementedRealtimeServiceServer()
+}
+
+func RegisterRealtimeServiceServer(s grpc.ServiceRegistrar, srv RealtimeServiceServer) {
+	s.RegisterService(&RealtimeService_ServiceDesc, srv)
+}
+
+func _RealtimeService_Subscribe_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeReq)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RealtimeServiceServer).Subscribe(m, &realtimeServiceSubscribeServer{ServerStream: stream})
+}
+
+type RealtimeService_SubscribeServer interface {
+	Send(*Message) error
+	grpc.ServerStream
+}
+
+type realtimeServiceSubscribeServer struct {
+	grpc.ServerStream
+}
+
+func (x *realtimeServiceSubscribeServer) Send(m *Message) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _RealtimeService_Broadcast_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BroadcastReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RealtimeServiceServer).Broadcast(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RealtimeService_Broadcast_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RealtimeServiceServer).Broadcast(ctx, req.(*BroadcastReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// RealtimeService_ServiceDesc is the grpc.ServiceDesc for RealtimeService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var RealtimeService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "realtime.RealtimeService",
+	HandlerType: (*RealtimeServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Broadcast",
+			Handler:    _RealtimeService_Broadcast_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Subscribe",
+			Handler:       _RealtimeService_Subscribe_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "proto/realtime/realtime.proto",
+}
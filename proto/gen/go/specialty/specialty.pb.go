@@ -0,0 +1,1265 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.2
+// 	protoc        v5.26.1
+// source: proto/specialty/specialty.proto
+
+package proto
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// =============================================================================//
+type Specialty struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Empty for the root specialties
+	ParentId string `protobuf:"bytes,2,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"`
+	// example: "cardiology"
+	Slug string `protobuf:"bytes,3,opt,name=slug,proto3" json:"slug,omitempty"`
+	// Name in the requested locale
+	Name string `protobuf:"bytes,4,opt,name=name,proto3" json:"name,omitempty"`
+	// Names by locale
+	Names map[string]string `protobuf:"bytes,5,rep,name=names,proto3" json:"names,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// Url of the icon
+	Icon      string       `protobuf:"bytes,6,opt,name=icon,proto3" json:"icon,omitempty"`
+	Aliases   []string     `protobuf:"bytes,7,rep,name=aliases,proto3" json:"aliases,omitempty"`
+	CreatedAt string       `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	Children  []*Specialty `protobuf:"bytes,9,rep,name=children,proto3" json:"children,omitempty"`
+	// Version, sent back by the updates and deletes
+	Version int64 `protobuf:"varint,10,opt,name=version,proto3" json:"version,omitempty"`
+}
+
+func (x *Specialty) Reset() {
+	*x = Specialty{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_specialty_specialty_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Specialty) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Specialty) ProtoMessage() {}
+
+func (x *Specialty) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_specialty_specialty_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Specialty.ProtoReflect.Descriptor instead.
+func (*Specialty) Descriptor() ([]byte, []int) {
+	return file_proto_specialty_specialty_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Specialty) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Specialty) GetParentId() string {
+	if x != nil {
+		return x.ParentId
+	}
+	return ""
+}
+
+func (x *Specialty) GetSlug() string {
+	if x != nil {
+		return x.Slug
+	}
+	return ""
+}
+
+func (x *Specialty) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Specialty) GetNames() map[string]string {
+	if x != nil {
+		return x.Names
+	}
+	return nil
+}
+
+func (x *Specialty) GetIcon() string {
+	if x != nil {
+		return x.Icon
+	}
+	return ""
+}
+
+func (x *Specialty) GetAliases() []string {
+	if x != nil {
+		return x.Aliases
+	}
+	return nil
+}
+
+func (x *Specialty) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *Specialty) GetChildren() []*Specialty {
+	if x != nil {
+		return x.Children
+	}
+	return nil
+}
+
+func (x *Specialty) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type SpecialtyRes struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Specialty *Specialty `protobuf:"bytes,1,opt,name=specialty,proto3" json:"specialty,omitempty"`
+}
+
+func (x *SpecialtyRes) Reset() {
+	*x = SpecialtyRes{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_specialty_specialty_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SpecialtyRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SpecialtyRes) ProtoMessage() {}
+
+func (x *SpecialtyRes) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_specialty_specialty_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SpecialtyRes.ProtoReflect.Descriptor instead.
+func (*SpecialtyRes) Descriptor() ([]byte, []int) {
+	return file_proto_specialty_specialty_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SpecialtyRes) GetSpecialty() *Specialty {
+	if x != nil {
+		return x.Specialty
+	}
+	return nil
+}
+
+type ListSpecialtiesReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Locale string `protobuf:"bytes,1,opt,name=locale,proto3" json:"locale,omitempty"`
+}
+
+func (x *ListSpecialtiesReq) Reset() {
+	*x = ListSpecialtiesReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_specialty_specialty_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListSpecialtiesReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSpecialtiesReq) ProtoMessage() {}
+
+func (x *ListSpecialtiesReq) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_specialty_specialty_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSpecialtiesReq.ProtoReflect.Descriptor instead.
+func (*ListSpecialtiesReq) Descriptor() ([]byte, []int) {
+	return file_proto_specialty_specialty_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListSpecialtiesReq) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+type ListSpecialtiesRes struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Root specialties with their children
+	Specialties []*Specialty `protobuf:"bytes,1,rep,name=specialties,proto3" json:"specialties,omitempty"`
+}
+
+func (x *ListSpecialtiesRes) Reset() {
+	*x = ListSpecialtiesRes{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_specialty_specialty_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListSpecialtiesRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSpecialtiesRes) ProtoMessage() {}
+
+func (x *ListSpecialtiesRes) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_specialty_specialty_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSpecialtiesRes.ProtoReflect.Descriptor instead.
+func (*ListSpecialtiesRes) Descriptor() ([]byte, []int) {
+	return file_proto_specialty_specialty_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListSpecialtiesRes) GetSpecialties() []*Specialty {
+	if x != nil {
+		return x.Specialties
+	}
+	return nil
+}
+
+type GetSpecialtyReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Slug   string `protobuf:"bytes,1,opt,name=slug,proto3" json:"slug,omitempty"`
+	Locale string `protobuf:"bytes,2,opt,name=locale,proto3" json:"locale,omitempty"`
+}
+
+func (x *GetSpecialtyReq) Reset() {
+	*x = GetSpecialtyReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_specialty_specialty_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetSpecialtyReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSpecialtyReq) ProtoMessage() {}
+
+func (x *GetSpecialtyReq) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_specialty_specialty_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSpecialtyReq.ProtoReflect.Descriptor instead.
+func (*GetSpecialtyReq) Descriptor() ([]byte, []int) {
+	return file_proto_specialty_specialty_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetSpecialtyReq) GetSlug() string {
+	if x != nil {
+		return x.Slug
+	}
+	return ""
+}
+
+func (x *GetSpecialtyReq) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+type CreateSpecialtyReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ParentId string `protobuf:"bytes,1,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"`
+	Slug     string `protobuf:"bytes,2,opt,name=slug,proto3" json:"slug,omitempty"`
+	// en is required
+	Names   map[string]string `protobuf:"bytes,3,rep,name=names,proto3" json:"names,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Icon    string            `protobuf:"bytes,4,opt,name=icon,proto3" json:"icon,omitempty"`
+	Aliases []string          `protobuf:"bytes,5,rep,name=aliases,proto3" json:"aliases,omitempty"`
+}
+
+func (x *CreateSpecialtyReq) Reset() {
+	*x = CreateSpecialtyReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_specialty_specialty_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateSpecialtyReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateSpecialtyReq) ProtoMessage() {}
+
+func (x *CreateSpecialtyReq) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_specialty_specialty_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateSpecialtyReq.ProtoReflect.Descriptor instead.
+func (*CreateSpecialtyReq) Descriptor() ([]byte, []int) {
+	return file_proto_specialty_specialty_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *CreateSpecialtyReq) GetParentId() string {
+	if x != nil {
+		return x.ParentId
+	}
+	return ""
+}
+
+func (x *CreateSpecialtyReq) GetSlug() string {
+	if x != nil {
+		return x.Slug
+	}
+	return ""
+}
+
+func (x *CreateSpecialtyReq) GetNames() map[string]string {
+	if x != nil {
+		return x.Names
+	}
+	return nil
+}
+
+func (x *CreateSpecialtyReq) GetIcon() string {
+	if x != nil {
+		return x.Icon
+	}
+	return ""
+}
+
+func (x *CreateSpecialtyReq) GetAliases() []string {
+	if x != nil {
+		return x.Aliases
+	}
+	return nil
+}
+
+type UpdateSpecialtyReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id       string            `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ParentId string            `protobuf:"bytes,2,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"`
+	Slug     string            `protobuf:"bytes,3,opt,name=slug,proto3" json:"slug,omitempty"`
+	Names    map[string]string `protobuf:"bytes,4,rep,name=names,proto3" json:"names,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Icon     string            `protobuf:"bytes,5,opt,name=icon,proto3" json:"icon,omitempty"`
+	Aliases  []string          `protobuf:"bytes,6,rep,name=aliases,proto3" json:"aliases,omitempty"`
+	// Version read, the update fails when it changed since
+	Version int64 `protobuf:"varint,7,opt,name=version,proto3" json:"version,omitempty"`
+}
+
+func (x *UpdateSpecialtyReq) Reset() {
+	*x = UpdateSpecialtyReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_specialty_specialty_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateSpecialtyReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateSpecialtyReq) ProtoMessage() {}
+
+func (x *UpdateSpecialtyReq) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_specialty_specialty_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateSpecialtyReq.ProtoReflect.Descriptor instead.
+func (*UpdateSpecialtyReq) Descriptor() ([]byte, []int) {
+	return file_proto_specialty_specialty_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *UpdateSpecialtyReq) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateSpecialtyReq) GetParentId() string {
+	if x != nil {
+		return x.ParentId
+	}
+	return ""
+}
+
+func (x *UpdateSpecialtyReq) GetSlug() string {
+	if x != nil {
+		return x.Slug
+	}
+	return ""
+}
+
+func (x *UpdateSpecialtyReq) GetNames() map[string]string {
+	if x != nil {
+		return x.Names
+	}
+	return nil
+}
+
+func (x *UpdateSpecialtyReq) GetIcon() string {
+	if x != nil {
+		return x.Icon
+	}
+	return ""
+}
+
+func (x *UpdateSpecialtyReq) GetAliases() []string {
+	if x != nil {
+		return x.Aliases
+	}
+	return nil
+}
+
+func (x *UpdateSpecialtyReq) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type DeleteSpecialtyReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Version read, the delete fails when it changed since
+	Version int64 `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+}
+
+func (x *DeleteSpecialtyReq) Reset() {
+	*x = DeleteSpecialtyReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_specialty_specialty_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteSpecialtyReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteSpecialtyReq) ProtoMessage() {}
+
+func (x *DeleteSpecialtyReq) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_specialty_specialty_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteSpecialtyReq.ProtoReflect.Descriptor instead.
+func (*DeleteSpecialtyReq) Descriptor() ([]byte, []int) {
+	return file_proto_specialty_specialty_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *DeleteSpecialtyReq) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DeleteSpecialtyReq) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type DeleteSpecialtyRes struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *DeleteSpecialtyRes) Reset() {
+	*x = DeleteSpecialtyRes{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_specialty_specialty_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteSpecialtyRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteSpecialtyRes) ProtoMessage() {}
+
+func (x *DeleteSpecialtyRes) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_specialty_specialty_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteSpecialtyRes.ProtoReflect.Descriptor instead.
+func (*DeleteSpecialtyRes) Descriptor() ([]byte, []int) {
+	return file_proto_specialty_specialty_proto_rawDescGZIP(), []int{8}
+}
+
+type DoctorSpecialty struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SpecialtyId string `protobuf:"bytes,1,opt,name=specialty_id,json=specialtyId,proto3" json:"specialty_id,omitempty"`
+	Primary     bool   `protobuf:"varint,2,opt,name=primary,proto3" json:"primary,omitempty"`
+}
+
+func (x *DoctorSpecialty) Reset() {
+	*x = DoctorSpecialty{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_specialty_specialty_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DoctorSpecialty) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DoctorSpecialty) ProtoMessage() {}
+
+func (x *DoctorSpecialty) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_specialty_specialty_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DoctorSpecialty.ProtoReflect.Descriptor instead.
+func (*DoctorSpecialty) Descriptor() ([]byte, []int) {
+	return file_proto_specialty_specialty_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *DoctorSpecialty) GetSpecialtyId() string {
+	if x != nil {
+		return x.SpecialtyId
+	}
+	return ""
+}
+
+func (x *DoctorSpecialty) GetPrimary() bool {
+	if x != nil {
+		return x.Primary
+	}
+	return false
+}
+
+type SetDoctorSpecialtiesReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DoctorId string `protobuf:"bytes,1,opt,name=doctor_id,json=doctorId,proto3" json:"doctor_id,omitempty"`
+	// Exactly one is primary
+	Specialties []*DoctorSpecialty `protobuf:"bytes,2,rep,name=specialties,proto3" json:"specialties,omitempty"`
+}
+
+func (x *SetDoctorSpecialtiesReq) Reset() {
+	*x = SetDoctorSpecialtiesReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_specialty_specialty_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SetDoctorSpecialtiesReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetDoctorSpecialtiesReq) ProtoMessage() {}
+
+func (x *SetDoctorSpecialtiesReq) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_specialty_specialty_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetDoctorSpecialtiesReq.ProtoReflect.Descriptor instead.
+func (*SetDoctorSpecialtiesReq) Descriptor() ([]byte, []int) {
+	return file_proto_specialty_specialty_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *SetDoctorSpecialtiesReq) GetDoctorId() string {
+	if x != nil {
+		return x.DoctorId
+	}
+	return ""
+}
+
+func (x *SetDoctorSpecialtiesReq) GetSpecialties() []*DoctorSpecialty {
+	if x != nil {
+		return x.Specialties
+	}
+	return nil
+}
+
+type SetDoctorSpecialtiesRes struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *SetDoctorSpecialtiesRes) Reset() {
+	*x = SetDoctorSpecialtiesRes{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_specialty_specialty_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SetDoctorSpecialtiesRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetDoctorSpecialtiesRes) ProtoMessage() {}
+
+func (x *SetDoctorSpecialtiesRes) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_specialty_specialty_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetDoctorSpecialtiesRes.ProtoReflect.Descriptor instead.
+func (*SetDoctorSpecialtiesRes) Descriptor() ([]byte, []int) {
+	return file_proto_specialty_specialty_proto_rawDescGZIP(), []int{11}
+}
+
+type MigrateSpecialistsReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *MigrateSpecialistsReq) Reset() {
+	*x = MigrateSpecialistsReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_specialty_specialty_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MigrateSpecialistsReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MigrateSpecialistsReq) ProtoMessage() {}
+
+func (x *MigrateSpecialistsReq) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_specialty_specialty_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MigrateSpecialistsReq.ProtoReflect.Descriptor instead.
+func (*MigrateSpecialistsReq) Descriptor() ([]byte, []int) {
+	return file_proto_specialty_specialty_proto_rawDescGZIP(), []int{12}
+}
+
+type MigrationReport struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Mapped int32 `protobuf:"varint,1,opt,name=mapped,proto3" json:"mapped,omitempty"`
+	// Values matching no specialty by number of doctors
+	Unmatched map[string]int32 `protobuf:"bytes,2,rep,name=unmatched,proto3" json:"unmatched,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+}
+
+func (x *MigrationReport) Reset() {
+	*x = MigrationReport{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_specialty_specialty_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MigrationReport) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MigrationReport) ProtoMessage() {}
+
+func (x *MigrationReport) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_specialty_specialty_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MigrationReport.ProtoReflect.Descriptor instead.
+func (*MigrationReport) Descriptor() ([]byte, []int) {
+	return file_proto_specialty_specialty_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *MigrationReport) GetMapped() int32 {
+	if x != nil {
+		return x.Mapped
+	}
+	return 0
+}
+
+func (x *MigrationReport) GetUnmatched() map[string]int32 {
+	if x != nil {
+		return x.Unmatched
+	}
+	return nil
+}
+
+var File_proto_specialty_specialty_proto protoreflect.FileDescriptor
+
+var file_proto_specialty_specialty_proto_rawDesc = []byte{
+	0x0a, 0x1f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x73, 0x70, 0x65, 0x63, 0x69, 0x61, 0x6c, 0x74,
+	0x79, 0x2f, 0x73, 0x70, 0x65, 0x63, 0x69, 0x61, 0x6c, 0x74, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x12, 0x09, 0x73, 0x70, 0x65, 0x63, 0x69, 0x61, 0x6c, 0x74, 0x79, 0x22, 0xea, 0x02, 0x0a,
+	0x09, 0x53, 0x70, 0x65, 0x63, 0x69, 0x61, 0x6c, 0x74, 0x79, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x61,
+	0x72, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70,
+	0x61, 0x72, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x6c, 0x75, 0x67, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x73, 0x6c, 0x75, 0x67, 0x12, 0x12, 0x0a, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12,
+	0x35, 0x0a, 0x05, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1f,
+	0x2e, 0x73, 0x70, 0x65, 0x63, 0x69, 0x61, 0x6c, 0x74, 0x79, 0x2e, 0x53, 0x70, 0x65, 0x63, 0x69,
+	0x61, 0x6c, 0x74, 0x79, 0x2e, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52,
+	0x05, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x69, 0x63, 0x6f, 0x6e, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x69, 0x63, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x6c,
+	0x69, 0x61, 0x73, 0x65, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x61, 0x6c, 0x69,
+	0x61, 0x73, 0x65, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x5f,
+	0x61, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65,
+	0x64, 0x41, 0x74, 0x12, 0x30, 0x0a, 0x08, 0x63, 0x68, 0x69, 0x6c, 0x64, 0x72, 0x65, 0x6e, 0x18,
+	0x09, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x73, 0x70, 0x65, 0x63, 0x69, 0x61, 0x6c, 0x74,
+	0x79, 0x2e, 0x53, 0x70, 0x65, 0x63, 0x69, 0x61, 0x6c, 0x74, 0x79, 0x52, 0x08, 0x63, 0x68, 0x69,
+	0x6c, 0x64, 0x72, 0x65, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x18, 0x0a, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x1a,
+	0x38, 0x0a, 0x0a, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a,
+	0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12,
+	0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x42, 0x0a, 0x0c, 0x53, 0x70, 0x65,
+	0x63, 0x69, 0x61, 0x6c, 0x74, 0x79, 0x52, 0x65, 0x73, 0x12, 0x32, 0x0a, 0x09, 0x73, 0x70, 0x65,
+	0x63, 0x69, 0x61, 0x6c, 0x74, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x73,
+	0x70, 0x65, 0x63, 0x69, 0x61, 0x6c, 0x74, 0x79, 0x2e, 0x53, 0x70, 0x65, 0x63, 0x69, 0x61, 0x6c,
+	0x74, 0x79, 0x52, 0x09, 0x73, 0x70, 0x65, 0x63, 0x69, 0x61, 0x6c, 0x74, 0x79, 0x22, 0x2c, 0x0a,
+	0x12, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x70, 0x65, 0x63, 0x69, 0x61, 0x6c, 0x74, 0x69, 0x65, 0x73,
+	0x52, 0x65, 0x71, 0x12, 0x16, 0x0a, 0x06, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x06, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x65, 0x22, 0x4c, 0x0a, 0x12, 0x4c,
+	0x69, 0x73, 0x74, 0x53, 0x70, 0x65, 0x63, 0x69, 0x61, 0x6c, 0x74, 0x69, 0x65, 0x73, 0x52, 0x65,
+	0x73, 0x12, 0x36, 0x0a, 0x0b, 0x73, 0x70, 0x65, 0x63, 0x69, 0x61, 0x6c, 0x74, 0x69, 0x65, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x73, 0x70, 0x65, 0x63, 0x69, 0x61, 0x6c,
+	0x74, 0x79, 0x2e, 0x53, 0x70, 0x65, 0x63, 0x69, 0x61, 0x6c, 0x74, 0x79, 0x52, 0x0b, 0x73, 0x70,
+	0x65, 0x63, 0x69, 0x61, 0x6c, 0x74, 0x69, 0x65, 0x73, 0x22, 0x3d, 0x0a, 0x0f, 0x47, 0x65, 0x74,
+	0x53, 0x70, 0x65, 0x63, 0x69, 0x61, 0x6c, 0x74, 0x79, 0x52, 0x65, 0x71, 0x12, 0x12, 0x0a, 0x04,
+	0x73, 0x6c, 0x75, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x73, 0x6c, 0x75, 0x67,
+	0x12, 0x16, 0x0a, 0x06, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x65, 0x22, 0xed, 0x01, 0x0a, 0x12, 0x43, 0x72, 0x65,
+	0x61, 0x74, 0x65, 0x53, 0x70, 0x65, 0x63, 0x69, 0x61, 0x6c, 0x74, 0x79, 0x52, 0x65, 0x71, 0x12,
+	0x1b, 0x0a, 0x09, 0x70, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x08, 0x70, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x12, 0x0a, 0x04,
+	0x73, 0x6c, 0x75, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x73, 0x6c, 0x75, 0x67,
+	0x12, 0x3e, 0x0a, 0x05, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x28, 0x2e, 0x73, 0x70, 0x65, 0x63, 0x69, 0x61, 0x6c, 0x74, 0x79, 0x2e, 0x43, 0x72, 0x65, 0x61,
+	0x74, 0x65, 0x53, 0x70, 0x65, 0x63, 0x69, 0x61, 0x6c, 0x74, 0x79, 0x52, 0x65, 0x71, 0x2e, 0x4e,
+	0x61, 0x6d, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x05, 0x6e, 0x61, 0x6d, 0x65, 0x73,
+	0x12, 0x12, 0x0a, 0x04, 0x69, 0x63, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x69, 0x63, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x65, 0x73, 0x18,
+	0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x65, 0x73, 0x1a, 0x38,
+	0x0a, 0x0a, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03,
+	0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14,
+	0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x97, 0x02, 0x0a, 0x12, 0x55, 0x70, 0x64,
+	0x61, 0x74, 0x65, 0x53, 0x70, 0x65, 0x63, 0x69, 0x61, 0x6c, 0x74, 0x79, 0x52, 0x65, 0x71, 0x12,
+	0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12,
+	0x1b, 0x0a, 0x09, 0x70, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x08, 0x70, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x12, 0x0a, 0x04,
+	0x73, 0x6c, 0x75, 0x67, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x73, 0x6c, 0x75, 0x67,
+	0x12, 0x3e, 0x0a, 0x05, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x28, 0x2e, 0x73, 0x70, 0x65, 0x63, 0x69, 0x61, 0x6c, 0x74, 0x79, 0x2e, 0x55, 0x70, 0x64, 0x61,
+	0x74, 0x65, 0x53, 0x70, 0x65, 0x63, 0x69, 0x61, 0x6c, 0x74, 0x79, 0x52, 0x65, 0x71, 0x2e, 0x4e,
+	0x61, 0x6d, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x05, 0x6e, 0x61, 0x6d, 0x65, 0x73,
+	0x12, 0x12, 0x0a, 0x04, 0x69, 0x63, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x69, 0x63, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x65, 0x73, 0x18,
+	0x06, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x65, 0x73, 0x12, 0x18,
+	0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x1a, 0x38, 0x0a, 0x0a, 0x4e, 0x61, 0x6d, 0x65,
+	0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02,
+	0x38, 0x01, 0x22, 0x3e, 0x0a, 0x12, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x53, 0x70, 0x65, 0x63,
+	0x69, 0x61, 0x6c, 0x74, 0x79, 0x52, 0x65, 0x71, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x22, 0x14, 0x0a, 0x12, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x53, 0x70, 0x65, 0x63,
+	0x69, 0x61, 0x6c, 0x74, 0x79, 0x52, 0x65, 0x73, 0x22, 0x4e, 0x0a, 0x0f, 0x44, 0x6f, 0x63, 0x74,
+	0x6f, 0x72, 0x53, 0x70, 0x65, 0x63, 0x69, 0x61, 0x6c, 0x74, 0x79, 0x12, 0x21, 0x0a, 0x0c, 0x73,
+	0x70, 0x65, 0x63, 0x69, 0x61, 0x6c, 0x74, 0x79, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0b, 0x73, 0x70, 0x65, 0x63, 0x69, 0x61, 0x6c, 0x74, 0x79, 0x49, 0x64, 0x12, 0x18,
+	0x0a, 0x07, 0x70, 0x72, 0x69, 0x6d, 0x61, 0x72, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x07, 0x70, 0x72, 0x69, 0x6d, 0x61, 0x72, 0x79, 0x22, 0x74, 0x0a, 0x17, 0x53, 0x65, 0x74, 0x44,
+	0x6f, 0x63, 0x74, 0x6f, 0x72, 0x53, 0x70, 0x65, 0x63, 0x69, 0x61, 0x6c, 0x74, 0x69, 0x65, 0x73,
+	0x52, 0x65, 0x71, 0x12, 0x1b, 0x0a, 0x09, 0x64, 0x6f, 0x63, 0x74, 0x6f, 0x72, 0x5f, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x64, 0x6f, 0x63, 0x74, 0x6f, 0x72, 0x49, 0x64,
+	0x12, 0x3c, 0x0a, 0x0b, 0x73, 0x70, 0x65, 0x63, 0x69, 0x61, 0x6c, 0x74, 0x69, 0x65, 0x73, 0x18,
+	0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x73, 0x70, 0x65, 0x63, 0x69, 0x61, 0x6c, 0x74,
+	0x79, 0x2e, 0x44, 0x6f, 0x63, 0x74, 0x6f, 0x72, 0x53, 0x70, 0x65, 0x63, 0x69, 0x61, 0x6c, 0x74,
+	0x79, 0x52, 0x0b, 0x73, 0x70, 0x65, 0x63, 0x69, 0x61, 0x6c, 0x74, 0x69, 0x65, 0x73, 0x22, 0x19,
+	0x0a, 0x17, 0x53, 0x65, 0x74, 0x44, 0x6f, 0x63, 0x74, 0x6f, 0x72, 0x53, 0x70, 0x65, 0x63, 0x69,
+	0x61, 0x6c, 0x74, 0x69, 0x65, 0x73, 0x52, 0x65, 0x73, 0x22, 0x17, 0x0a, 0x15, 0x4d, 0x69, 0x67,
+	0x72, 0x61, 0x74, 0x65, 0x53, 0x70, 0x65, 0x63, 0x69, 0x61, 0x6c, 0x69, 0x73, 0x74, 0x73, 0x52,
+	0x65, 0x71, 0x22, 0xb0, 0x01, 0x0a, 0x0f, 0x4d, 0x69, 0x67, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x6d, 0x61, 0x70, 0x70, 0x65, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x6d, 0x61, 0x70, 0x70, 0x65, 0x64, 0x12, 0x47,
+	0x0a, 0x09, 0x75, 0x6e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x64, 0x18, 0x02, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x29, 0x2e, 0x73, 0x70, 0x65, 0x63, 0x69, 0x61, 0x6c, 0x74, 0x79, 0x2e, 0x4d, 0x69,
+	0x67, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x2e, 0x55, 0x6e,
+	0x6d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x64, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x09, 0x75, 0x6e,
+	0x6d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x64, 0x1a, 0x3c, 0x0a, 0x0e, 0x55, 0x6e, 0x6d, 0x61, 0x74,
+	0x63, 0x68, 0x65, 0x64, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x3a, 0x02, 0x38, 0x01, 0x32, 0xc3, 0x04, 0x0a, 0x10, 0x53, 0x70, 0x65, 0x63, 0x69, 0x61,
+	0x6c, 0x74, 0x79, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x4f, 0x0a, 0x0f, 0x4c, 0x69,
+	0x73, 0x74, 0x53, 0x70, 0x65, 0x63, 0x69, 0x61, 0x6c, 0x74, 0x69, 0x65, 0x73, 0x12, 0x1d, 0x2e,
+	0x73, 0x70, 0x65, 0x63, 0x69, 0x61, 0x6c, 0x74, 0x79, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x70,
+	0x65, 0x63, 0x69, 0x61, 0x6c, 0x74, 0x69, 0x65, 0x73, 0x52, 0x65, 0x71, 0x1a, 0x1d, 0x2e, 0x73,
+	0x70, 0x65, 0x63, 0x69, 0x61, 0x6c, 0x74, 0x79, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x70, 0x65,
+	0x63, 0x69, 0x61, 0x6c, 0x74, 0x69, 0x65, 0x73, 0x52, 0x65, 0x73, 0x12, 0x43, 0x0a, 0x0c, 0x47,
+	0x65, 0x74, 0x53, 0x70, 0x65, 0x63, 0x69, 0x61, 0x6c, 0x74, 0x79, 0x12, 0x1a, 0x2e, 0x73, 0x70,
+	0x65, 0x63, 0x69, 0x61, 0x6c, 0x74, 0x79, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x70, 0x65, 0x63, 0x69,
+	0x61, 0x6c, 0x74, 0x79, 0x52, 0x65, 0x71, 0x1a, 0x17, 0x2e, 0x73, 0x70, 0x65, 0x63, 0x69, 0x61,
+	0x6c, 0x74, 0x79, 0x2e, 0x53, 0x70, 0x65, 0x63, 0x69, 0x61, 0x6c, 0x74, 0x79, 0x52, 0x65, 0x73,
+	0x12, 0x49, 0x0a, 0x0f, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x70, 0x65, 0x63, 0x69, 0x61,
+	0x6c, 0x74, 0x79, 0x12, 0x1d, 0x2e, 0x73, 0x70, 0x65, 0x63, 0x69, 0x61, 0x6c, 0x74, 0x79, 0x2e,
+	0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x70, 0x65, 0x63, 0x69, 0x61, 0x6c, 0x74, 0x79, 0x52,
+	0x65, 0x71, 0x1a, 0x17, 0x2e, 0x73, 0x70, 0x65, 0x63, 0x69, 0x61, 0x6c, 0x74, 0x79, 0x2e, 0x53,
+	0x70, 0x65, 0x63, 0x69, 0x61, 0x6c, 0x74, 0x79, 0x52, 0x65, 0x73, 0x12, 0x49, 0x0a, 0x0f, 0x55,
+	0x70, 0x64, 0x61, 0x74, 0x65, 0x53, 0x70, 0x65, 0x63, 0x69, 0x61, 0x6c, 0x74, 0x79, 0x12, 0x1d,
+	0x2e, 0x73, 0x70, 0x65, 0x63, 0x69, 0x61, 0x6c, 0x74, 0x79, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74,
+	0x65, 0x53, 0x70, 0x65, 0x63, 0x69, 0x61, 0x6c, 0x74, 0x79, 0x52, 0x65, 0x71, 0x1a, 0x17, 0x2e,
+	0x73, 0x70, 0x65, 0x63, 0x69, 0x61, 0x6c, 0x74, 0x79, 0x2e, 0x53, 0x70, 0x65, 0x63, 0x69, 0x61,
+	0x6c, 0x74, 0x79, 0x52, 0x65, 0x73, 0x12, 0x4f, 0x0a, 0x0f, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65,
+	0x53, 0x70, 0x65, 0x63, 0x69, 0x61, 0x6c, 0x74, 0x79, 0x12, 0x1d, 0x2e, 0x73, 0x70, 0x65, 0x63,
+	0x69, 0x61, 0x6c, 0x74, 0x79, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x53, 0x70, 0x65, 0x63,
+	0x69, 0x61, 0x6c, 0x74, 0x79, 0x52, 0x65, 0x71, 0x1a, 0x1d, 0x2e, 0x73, 0x70, 0x65, 0x63, 0x69,
+	0x61, 0x6c, 0x74, 0x79, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x53, 0x70, 0x65, 0x63, 0x69,
+	0x61, 0x6c, 0x74, 0x79, 0x52, 0x65, 0x73, 0x12, 0x5e, 0x0a, 0x14, 0x53, 0x65, 0x74, 0x44, 0x6f,
+	0x63, 0x74, 0x6f, 0x72, 0x53, 0x70, 0x65, 0x63, 0x69, 0x61, 0x6c, 0x74, 0x69, 0x65, 0x73, 0x12,
+	0x22, 0x2e, 0x73, 0x70, 0x65, 0x63, 0x69, 0x61, 0x6c, 0x74, 0x79, 0x2e, 0x53, 0x65, 0x74, 0x44,
+	0x6f, 0x63, 0x74, 0x6f, 0x72, 0x53, 0x70, 0x65, 0x63, 0x69, 0x61, 0x6c, 0x74, 0x69, 0x65, 0x73,
+	0x52, 0x65, 0x71, 0x1a, 0x22, 0x2e, 0x73, 0x70, 0x65, 0x63, 0x69, 0x61, 0x6c, 0x74, 0x79, 0x2e,
+	0x53, 0x65, 0x74, 0x44, 0x6f, 0x63, 0x74, 0x6f, 0x72, 0x53, 0x70, 0x65, 0x63, 0x69, 0x61, 0x6c,
+	0x74, 0x69, 0x65, 0x73, 0x52, 0x65, 0x73, 0x12, 0x52, 0x0a, 0x12, 0x4d, 0x69, 0x67, 0x72, 0x61,
+	0x74, 0x65, 0x53, 0x70, 0x65, 0x63, 0x69, 0x61, 0x6c, 0x69, 0x73, 0x74, 0x73, 0x12, 0x20, 0x2e,
+	0x73, 0x70, 0x65, 0x63, 0x69, 0x61, 0x6c, 0x74, 0x79, 0x2e, 0x4d, 0x69, 0x67, 0x72, 0x61, 0x74,
+	0x65, 0x53, 0x70, 0x65, 0x63, 0x69, 0x61, 0x6c, 0x69, 0x73, 0x74, 0x73, 0x52, 0x65, 0x71, 0x1a,
+	0x1a, 0x2e, 0x73, 0x70, 0x65, 0x63, 0x69, 0x61, 0x6c, 0x74, 0x79, 0x2e, 0x4d, 0x69, 0x67, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x42, 0x0c, 0x5a, 0x0a, 0x6d,
+	0x61, 0x69, 0x6e, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x33,
+}
+
+var (
+	file_proto_specialty_specialty_proto_rawDescOnce sync.Once
+	file_proto_specialty_specialty_proto_rawDescData = file_proto_specialty_specialty_proto_rawDesc
+)
+
+func file_proto_specialty_specialty_proto_rawDescGZIP() []byte {
+	file_proto_specialty_specialty_proto_rawDescOnce.Do(func() {
+		file_proto_specialty_specialty_proto_rawDescData = protoimpl.X.CompressGZIP(file_proto_specialty_specialty_proto_rawDescData)
+	})
+	return file_proto_specialty_specialty_proto_rawDescData
+}
+
+var file_proto_specialty_specialty_proto_msgTypes = make([]protoimpl.MessageInfo, 18)
+var file_proto_specialty_specialty_proto_goTypes = []any{
+	(*Specialty)(nil),               // 0: specialty.Specialty
+	(*SpecialtyRes)(nil),            // 1: specialty.SpecialtyRes
+	(*ListSpecialtiesReq)(nil),      // 2: specialty.ListSpecialtiesReq
+	(*ListSpecialtiesRes)(nil),      // 3: specialty.ListSpecialtiesRes
+	(*GetSpecialtyReq)(nil),         // 4: specialty.GetSpecialtyReq
+	(*CreateSpecialtyReq)(nil),      // 5: specialty.CreateSpecialtyReq
+	(*UpdateSpecialtyReq)(nil),      // 6: specialty.UpdateSpecialtyReq
+	(*DeleteSpecialtyReq)(nil),      // 7: specialty.DeleteSpecialtyReq
+	(*DeleteSpecialtyRes)(nil),      // 8: specialty.DeleteSpecialtyRes
+	(*DoctorSpecialty)(nil),         // 9: specialty.DoctorSpecialty
+	(*SetDoctorSpecialtiesReq)(nil), // 10: specialty.SetDoctorSpecialtiesReq
+	(*SetDoctorSpecialtiesRes)(nil), // 11: specialty.SetDoctorSpecialtiesRes
+	(*MigrateSpecialistsReq)(nil),   // 12: specialty.MigrateSpecialistsReq
+	(*MigrationReport)(nil),         // 13: specialty.MigrationReport
+	nil,                             // 14: specialty.Specialty.NamesEntry
+	nil,                             // 15: specialty.CreateSpecialtyReq.NamesEntry
+	nil,                             // 16: specialty.UpdateSpecialtyReq.NamesEntry
+	nil,                             // 17: specialty.MigrationReport.UnmatchedEntry
+}
+var file_proto_specialty_specialty_proto_depIdxs = []int32{
+	14, // 0: specialty.Specialty.names:type_name -> specialty.Specialty.NamesEntry
+	0,  // 1: specialty.Specialty.children:type_name -> specialty.Specialty
+	0,  // 2: specialty.SpecialtyRes.specialty:type_name -> specialty.Specialty
+	0,  // 3: specialty.ListSpecialtiesRes.specialties:type_name -> specialty.Specialty
+	15, // 4: specialty.CreateSpecialtyReq.names:type_name -> specialty.CreateSpecialtyReq.NamesEntry
+	16, // 5: specialty.UpdateSpecialtyReq.names:type_name -> specialty.UpdateSpecialtyReq.NamesEntry
+	9,  // 6: specialty.SetDoctorSpecialtiesReq.specialties:type_name -> specialty.DoctorSpecialty
+	17, // 7: specialty.MigrationReport.unmatched:type_name -> specialty.MigrationReport.UnmatchedEntry
+	2,  // 8: specialty.SpecialtyService.ListSpecialties:input_type -> specialty.ListSpecialtiesReq
+	4,  // 9: specialty.SpecialtyService.GetSpecialty:input_type -> specialty.GetSpecialtyReq
+	5,  // 10: specialty.SpecialtyService.CreateSpecialty:input_type -> specialty.CreateSpecialtyReq
+	6,  // 11: specialty.SpecialtyService.UpdateSpecialty:input_type -> specialty.UpdateSpecialtyReq
+	7,  // 12: specialty.SpecialtyService.DeleteSpecialty:input_type -> specialty.DeleteSpecialtyReq
+	10, // 13: specialty.SpecialtyService.SetDoctorSpecialties:input_type -> specialty.SetDoctorSpecialtiesReq
+	12, // 14: specialty.SpecialtyService.MigrateSpecialists:input_type -> specialty.MigrateSpecialistsReq
+	3,  // 15: specialty.SpecialtyService.ListSpecialties:output_type -> specialty.ListSpecialtiesRes
+	1,  // 16: specialty.SpecialtyService.GetSpecialty:output_type -> specialty.SpecialtyRes
+	1,  // 17: specialty.SpecialtyService.CreateSpecialty:output_type -> specialty.SpecialtyRes
+	1,  // 18: specialty.SpecialtyService.UpdateSpecialty:output_type -> specialty.SpecialtyRes
+	8,  // 19: specialty.SpecialtyService.DeleteSpecialty:output_type -> specialty.DeleteSpecialtyRes
+	11, // 20: specialty.SpecialtyService.SetDoctorSpecialties:output_type -> specialty.SetDoctorSpecialtiesRes
+	13, // 21: specialty.SpecialtyService.MigrateSpecialists:output_type -> specialty.MigrationReport
+	15, // [15:22] is the sub-list for method output_type
+	8,  // [8:15] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
+}
+
+func init() { file_proto_specialty_specialty_proto_init() }
+func file_proto_specialty_specialty_proto_init() {
+	if File_proto_specialty_specialty_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_proto_specialty_specialty_proto_msgTypes[0].Exporter = func(v any, i int) any {
+			switch v := v.(*Specialty); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_specialty_specialty_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*SpecialtyRes); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_specialty_specialty_proto_msgTypes[2].Exporter = func(v any, i int) any {
+			switch v := v.(*ListSpecialtiesReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_specialty_specialty_proto_msgTypes[3].Exporter = func(v any, i int) any {
+			switch v := v.(*ListSpecialtiesRes); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_specialty_specialty_proto_msgTypes[4].Exporter = func(v any, i int) any {
+			switch v := v.(*GetSpecialtyReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_specialty_specialty_proto_msgTypes[5].Exporter = func(v any, i int) any {
+			switch v := v.(*CreateSpecialtyReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_specialty_specialty_proto_msgTypes[6].Exporter = func(v any, i int) any {
+			switch v := v.(*UpdateSpecialtyReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_specialty_specialty_proto_msgTypes[7].Exporter = func(v any, i int) any {
+			switch v := v.(*DeleteSpecialtyReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_specialty_specialty_proto_msgTypes[8].Exporter = func(v any, i int) any {
+			switch v := v.(*DeleteSpecialtyRes); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_specialty_specialty_proto_msgTypes[9].Exporter = func(v any, i int) any {
+			switch v := v.(*DoctorSpecialty); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_specialty_specialty_proto_msgTypes[10].Exporter = func(v any, i int) any {
+			switch v := v.(*SetDoctorSpecialtiesReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_specialty_specialty_proto_msgTypes[11].Exporter = func(v any, i int) any {
+			switch v := v.(*SetDoctorSpecialtiesRes); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_specialty_specialty_proto_msgTypes[12].Exporter = func(v any, i int) any {
+			switch v := v.(*MigrateSpecialistsReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_specialty_specialty_proto_msgTypes[13].Exporter = func(v any, i int) any {
+			switch v := v.(*MigrationReport); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_proto_specialty_specialty_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   18,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proto_specialty_specialty_proto_goTypes,
+		DependencyIndexes: file_proto_specialty_specialty_proto_depIdxs,
+		MessageInfos:      file_proto_specialty_specialty_proto_msgTypes,
+	}.Build()
+	File_proto_specialty_specialty_proto = out.File
+	file_proto_specialty_specialty_proto_rawDesc = nil
+	file_proto_specialty_specialty_proto_goTypes = nil
+	file_proto_specialty_specialty_proto_depIdxs = nil
+}
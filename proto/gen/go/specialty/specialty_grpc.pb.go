@@ -0,0 +1,352 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.4.0
+// - protoc             v5.26.1
+// source: proto/specialty/specialty.proto
+
+package proto
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.62.0 or later.
+const _ = grpc.SupportPackageIsVersion8
+
+const (
+	SpecialtyService_ListSpecialties_FullMethodName      = "/specialty.SpecialtyService/ListSpecialties"
+	SpecialtyService_GetSpecialty_FullMethodName         = "/specialty.SpecialtyService/GetSpecialty"
+	SpecialtyService_CreateSpecialty_FullMethodName      = "/specialty.SpecialtyService/CreateSpecialty"
+	SpecialtyService_UpdateSpecialty_FullMethodName      = "/specialty.SpecialtyService/UpdateSpecialty"
+	SpecialtyService_DeleteSpecialty_FullMethodName      = "/specialty.SpecialtyService/DeleteSpecialty"
+	SpecialtyService_SetDoctorSpecialties_FullMethodName = "/specialty.SpecialtyService/SetDoctorSpecialties"
+	SpecialtyService_MigrateSpecialists_FullMethodName   = "/specialty.SpecialtyService/MigrateSpecialists"
+)
+
+// SpecialtyServiceClient is the client API for SpecialtyService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// =============================================================================//
+// SpecialtyService manages the specialties catalogue
+type SpecialtyServiceClient interface {
+	ListSpecialties(ctx context.Context, in *ListSpecialtiesReq, opts ...grpc.CallOption) (*ListSpecialtiesRes, error)
+	GetSpecialty(ctx context.Context, in *GetSpecialtyReq, opts ...grpc.CallOption) (*SpecialtyRes, error)
+	CreateSpecialty(ctx context.Context, in *CreateSpecialtyReq, opts ...grpc.CallOption) (*SpecialtyRes, error)
+	UpdateSpecialty(ctx context.Context, in *UpdateSpecialtyReq, opts ...grpc.CallOption) (*SpecialtyRes, error)
+	// DeleteSpecialty only deletes specialties without children nor doctors
+	DeleteSpecialty(ctx context.Context, in *DeleteSpecialtyReq, opts ...grpc.CallOption) (*DeleteSpecialtyRes, error)
+	// SetDoctorSpecialties replaces the specialties of a doctor
+	SetDoctorSpecialties(ctx context.Context, in *SetDoctorSpecialtiesReq, opts ...grpc.CallOption) (*SetDoctorSpecialtiesRes, error)
+	// MigrateSpecialists maps the free-text specialities of doctors to the
+	// catalogue
+	MigrateSpecialists(ctx context.Context, in *MigrateSpecialistsReq, opts ...grpc.CallOption) (*MigrationReport, error)
+}
+
+type specialtyServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSpecialtyServiceClient(cc grpc.ClientConnInterface) SpecialtyServiceClient {
+	return &specialtyServiceClient{cc}
+}
+
+func (c *specialtyServiceClient) ListSpecialties(ctx context.Context, in *ListSpecialtiesReq, opts ...grpc.CallOption) (*ListSpecialtiesRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSpecialtiesRes)
+	err := c.cc.Invoke(ctx, SpecialtyService_ListSpecialties_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *specialtyServiceClient) GetSpecialty(ctx context.Context, in *GetSpecialtyReq, opts ...grpc.CallOption) (*SpecialtyRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SpecialtyRes)
+	err := c.cc.Invoke(ctx, SpecialtyService_GetSpecialty_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *specialtyServiceClient) CreateSpecialty(ctx context.Context, in *CreateSpecialtyReq, opts ...grpc.CallOption) (*SpecialtyRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SpecialtyRes)
+	err := c.cc.Invoke(ctx, SpecialtyService_CreateSpecialty_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *specialtyServiceClient) UpdateSpecialty(ctx context.Context, in *UpdateSpecialtyReq, opts ...grpc.CallOption) (*SpecialtyRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SpecialtyRes)
+	err := c.cc.Invoke(ctx, SpecialtyService_UpdateSpecialty_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *specialtyServiceClient) DeleteSpecialty(ctx context.Context, in *DeleteSpecialtyReq, opts ...grpc.CallOption) (*DeleteSpecialtyRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteSpecialtyRes)
+	err := c.cc.Invoke(ctx, SpecialtyService_DeleteSpecialty_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *specialtyServiceClient) SetDoctorSpecialties(ctx context.Context, in *SetDoctorSpecialtiesReq, opts ...grpc.CallOption) (*SetDoctorSpecialtiesRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetDoctorSpecialtiesRes)
+	err := c.cc.Invoke(ctx, SpecialtyService_SetDoctorSpecialties_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *specialtyServiceClient) MigrateSpecialists(ctx context.Context, in *MigrateSpecialistsReq, opts ...grpc.CallOption) (*MigrationReport, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MigrationReport)
+	err := c.cc.Invoke(ctx, SpecialtyService_MigrateSpecialists_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SpecialtyServiceServer is the server API for SpecialtyService service.
+// All implementations must embed UnimplementedSpecialtyServiceServer
+// for forward compatibility
+//
+// =============================================================================//
+// SpecialtyService manages the specialties catalogue
+type SpecialtyServiceServer interface {
+	ListSpecialties(context.Context, *ListSpecialtiesReq) (*ListSpecialtiesRes, error)
+	GetSpecialty(context.Context, *GetSpecialtyReq) (*SpecialtyRes, error)
+	CreateSpecialty(context.Context, *CreateSpecialtyReq) (*SpecialtyRes, error)
+	UpdateSpecialty(context.Context, *UpdateSpecialtyReq) (*SpecialtyRes, error)
+	// DeleteSpecialty only deletes specialties without children nor doctors
+	DeleteSpecialty(context.Context, *DeleteSpecialtyReq) (*DeleteSpecialtyRes, error)
+	// SetDoctorSpecialties replaces the specialties of a doctor
+	SetDoctorSpecialties(context.Context, *SetDoctorSpecialtiesReq) (*SetDoctorSpecialtiesRes, error)
+	// MigrateSpecialists maps the free-text specialities of doctors to the
+	// catalogue
+	MigrateSpecialists(context.Context, *MigrateSpecialistsReq) (*MigrationReport, error)
+	mustEmbedUnimplementedSpecialtyServiceServer()
+}
+
+// UnimplementedSpecialtyServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedSpecialtyServiceServer struct {
+}
+
+func (UnimplementedSpecialtyServiceServer) ListSpecialties(context.Context, *ListSpecialtiesReq) (*ListSpecialtiesRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSpecialties not implemented")
+}
+func (UnimplementedSpecialtyServiceServer) GetSpecialty(context.Context, *GetSpecialtyReq) (*SpecialtyRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSpecialty not implemented")
+}
+func (UnimplementedSpecialtyServiceServer) CreateSpecialty(context.Context, *CreateSpecialtyReq) (*SpecialtyRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateSpecialty not implemented")
+}
+func (UnimplementedSpecialtyServiceServer) UpdateSpecialty(context.Context, *UpdateSpecialtyReq) (*SpecialtyRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateSpecialty not implemented")
+}
+func (UnimplementedSpecialtyServiceServer) DeleteSpecialty(context.Context, *DeleteSpecialtyReq) (*DeleteSpecialtyRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteSpecialty not implemented")
+}
+func (UnimplementedSpecialtyServiceServer) SetDoctorSpecialties(context.Context, *SetDoctorSpecialtiesReq) (*SetDoctorSpecialtiesRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetDoctorSpecialties not implemented")
+}
+func (UnimplementedSpecialtyServiceServer) MigrateSpecialists(context.Context, *MigrateSpecialistsReq) (*MigrationReport, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MigrateSpecialists not implemented")
+}
+func (UnimplementedSpecialtyServiceServer) mustEmbedUnimplementedSpecialtyServiceServer() {}
+
+// UnsafeSpecialtyServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SpecialtyServiceServer will
+// result in compilation errors.
+type UnsafeSpecialtyServiceServer interface {
+	mustEmbedUnimplementedSpecialtyServiceServer()
+}
+
+func RegisterSpecialtyServiceServer(s grpc.ServiceRegistrar, srv SpecialtyServiceServer) {
+	s.RegisterService(&SpecialtyService_ServiceDesc, srv)
+}
+
+func _SpecialtyService_ListSpecialties_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSpecialtiesReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SpecialtyServiceServer).ListSpecialties(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SpecialtyService_ListSpecialties_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SpecialtyServiceServer).ListSpecialties(ctx, req.(*ListSpecialtiesReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SpecialtyService_GetSpecialty_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSpecialtyReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SpecialtyServiceServer).GetSpecialty(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SpecialtyService_GetSpecialty_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SpecialtyServiceServer).GetSpecialty(ctx, req.(*GetSpecialtyReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SpecialtyService_CreateSpecialty_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateSpecialtyReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SpecialtyServiceServer).CreateSpecialty(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SpecialtyService_CreateSpecialty_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SpecialtyServiceServer).CreateSpecialty(ctx, req.(*CreateSpecialtyReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SpecialtyService_UpdateSpecialty_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateSpecialtyReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SpecialtyServiceServer).UpdateSpecialty(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SpecialtyService_UpdateSpecialty_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SpecialtyServiceServer).UpdateSpecialty(ctx, req.(*UpdateSpecialtyReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SpecialtyService_DeleteSpecialty_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteSpecialtyReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SpecialtyServiceServer).DeleteSpecialty(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SpecialtyService_DeleteSpecialty_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SpecialtyServiceServer).DeleteSpecialty(ctx, req.(*DeleteSpecialtyReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SpecialtyService_SetDoctorSpecialties_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetDoctorSpecialtiesReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SpecialtyServiceServer).SetDoctorSpecialties(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SpecialtyService_SetDoctorSpecialties_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SpecialtyServiceServer).SetDoctorSpecialties(ctx, req.(*SetDoctorSpecialtiesReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SpecialtyService_MigrateSpecialists_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MigrateSpecialistsReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SpecialtyServiceServer).MigrateSpecialists(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SpecialtyService_MigrateSpecialists_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SpecialtyServiceServer).MigrateSpecialists(ctx, req.(*MigrateSpecialistsReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// SpecialtyService_ServiceDesc is the grpc.ServiceDesc for SpecialtyService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var SpecialtyService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "specialty.SpecialtyService",
+	HandlerType: (*SpecialtyServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListSpecialties",
+			Handler:    _SpecialtyService_ListSpecialties_Handler,
+		},
+		{
+			MethodName: "GetSpecialty",
+			Handler:    _SpecialtyService_GetSpecialty_Handler,
+		},
+		{
+			MethodName: "CreateSpecialty",
+			Handler:    _SpecialtyService_CreateSpecialty_Handler,
+		},
+		{
+			MethodName: "UpdateSpecialty",
+			Handler:    _SpecialtyService_UpdateSpecialty_Handler,
+		},
+		{
+			MethodName: "DeleteSpecialty",
+			Handler:    _SpecialtyService_DeleteSpecialty_Handler,
+		},
+		{
+			MethodName: "SetDoctorSpecialties",
+			Handler:    _SpecialtyService_SetDoctorSpecialties_Handler,
+		},
+		{
+			MethodName: "MigrateSpecialists",
+			Handler:    _SpecialtyService_MigrateSpecialists_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/specialty/specialty.proto",
+}